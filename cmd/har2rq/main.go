@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jacoelho/rq/internal/har"
+	"github.com/jacoelho/rq/internal/har/config"
+)
+
+func main() {
+	os.Exit(run(os.Args, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	cfg, err := config.Parse(args)
+	if err != nil {
+		if errors.Is(err, config.ErrHelp) {
+			fmt.Fprintln(stdout, config.Usage())
+			return 0
+		}
+
+		fmt.Fprintf(stderr, "Error: %v\n\n%s\n", err, config.Usage())
+		return 1
+	}
+
+	// Without --out the converted steps own stdout, so the report moves to
+	// stderr to keep the stream pipeable.
+	reportOutput := stdout
+	if cfg.OutputFile == "" {
+		reportOutput = stderr
+	}
+
+	summary, err := har.Run(*cfg, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := summary.Write(reportOutput, cfg.ReportFormat); err != nil {
+		fmt.Fprintf(stderr, "Error: failed to write report: %v\n", err)
+		return 1
+	}
+
+	if summary.HasErrors() {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rqyaml "github.com/jacoelho/rq/internal/rq/yaml"
+)
+
+const session = `{
+  "log": {
+    "entries": [
+      {
+        "request": {"method": "POST", "url": "https://api.example.com/login", "headers": [{"name": "Content-Type", "value": "application/json"}], "postData": {"mimeType": "application/json", "text": "{\"user\":\"a\"}"}},
+        "response": {"status": 200, "content": {"mimeType": "application/json"}}
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/style.css", "headers": []},
+        "response": {"status": 200, "content": {"mimeType": "text/css"}}
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/me", "headers": []},
+        "response": {"status": 200, "content": {"mimeType": "application/json"}}
+      }
+    ]
+  }
+}`
+
+func TestRunWritesStepsInRecordingOrder(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	input := writeHAR(t, tempDir, session)
+	output := filepath.Join(tempDir, "out", "session.yaml")
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := run([]string{"har2rq", "--input", input, "--out", output}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("run() exitCode = %d, stderr = %s", exitCode, stderr.String())
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, err := rqyaml.Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("generated file does not parse: %v\n%s", err, data)
+	}
+	if len(steps) != 2 || steps[0].URL != "https://api.example.com/login" || steps[1].URL != "https://api.example.com/me" {
+		t.Fatalf("steps = %+v, want login then me", steps)
+	}
+	if !strings.Contains(stdout.String(), "HAR import summary") {
+		t.Errorf("report = %q, want the summary on stdout", stdout.String())
+	}
+
+	if exitCode := run([]string{"har2rq", "--input", input, "--out", output}, &stdout, &stderr); exitCode != 1 {
+		t.Errorf("run() exitCode = %d, want 1 when the output exists", exitCode)
+	}
+}
+
+func TestRunStdoutStreamsSteps(t *testing.T) {
+	t.Parallel()
+
+	input := writeHAR(t, t.TempDir(), session)
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := run([]string{"har2rq", "--input", input, "--include-static"}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("run() exitCode = %d, stderr = %s", exitCode, stderr.String())
+	}
+
+	steps, err := rqyaml.Parse(bytes.NewReader(stdout.Bytes()))
+	if err != nil {
+		t.Fatalf("stdout does not parse: %v\n%s", err, stdout.String())
+	}
+	if len(steps) != 3 {
+		t.Errorf("got %d steps, want 3 with --include-static", len(steps))
+	}
+	if !strings.Contains(stderr.String(), "total requests: 3") {
+		t.Errorf("stderr = %q, want the report", stderr.String())
+	}
+}
+
+func writeHAR(t *testing.T, dir string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "session.har")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
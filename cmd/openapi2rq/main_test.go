@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/execute"
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+const document = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    post:
+      tags: [Pets]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string, example: Rex}
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id: {type: integer}
+                  name: {type: string}
+  /pets/{id}:
+    get:
+      tags: [Pets]
+      responses:
+        "200": {description: ok}
+  /health:
+    get:
+      responses:
+        "204": {description: ok}
+`
+
+func TestRunGeneratedFilesPassAgainstConformingServer(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	input := filepath.Join(tempDir, "openapi.yaml")
+	if err := os.WriteFile(input, []byte(document), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outputDir := filepath.Join(tempDir, "tests")
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := run([]string{"openapi2rq", "--input", input, "--out", outputDir}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("run() exitCode = %d, stderr = %s", exitCode, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "converted: 3") {
+		t.Errorf("report = %q, want 3 converted operations", stdout.String())
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/pets":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1,"name":"Rex"}`))
+		case r.URL.Path == "/pets/7":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/health":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	files := []string{filepath.Join(outputDir, "pets.yaml"), filepath.Join(outputDir, "health.yaml")}
+	runner, exitResult := execute.New(&config.Config{
+		TestFiles:      files,
+		Variables:      map[string]any{"base_url": server.URL, "id": "7"},
+		OutputFormat:   output.FormatText,
+		RequestTimeout: config.DefaultTimeout,
+	})
+	if exitResult != nil {
+		t.Fatalf("execute.New() = %s", exitResult.Message)
+	}
+	var runOutput bytes.Buffer
+	runner.SetOutput(&runOutput)
+	runner.SetErrorOutput(&runOutput)
+	if exitCode := runner.Run(context.Background()); exitCode != 0 {
+		t.Fatalf("generated files failed with exit code %d:\n%s", exitCode, runOutput.String())
+	}
+
+	if exitCode := run([]string{"openapi2rq", "--input", input, "--out", outputDir}, &stdout, &stderr); exitCode != 0 {
+		t.Fatalf("second run() exitCode = %d, want 0 with warnings for existing files", exitCode)
+	}
+	if !strings.Contains(stdout.String(), "output_exists: 3") {
+		t.Errorf("report = %q, want existing files reported", stdout.String())
+	}
+}
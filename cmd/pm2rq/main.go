@@ -1,44 +1,110 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/jacoelho/rq/internal/pm/config"
 	"github.com/jacoelho/rq/internal/pm/files"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/pm/verify"
+	rqconfig "github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/execute"
+	"github.com/jacoelho/rq/internal/rq/output"
 )
 
 func main() {
-	os.Exit(run(os.Args))
+	os.Exit(run(os.Args, os.Stdout, os.Stderr))
 }
 
-func run(args []string) int {
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
 	cfg, err := config.Parse(args)
 	if err != nil {
 		if errors.Is(err, config.ErrHelp) {
-			fmt.Fprintln(os.Stdout, config.Usage())
+			fmt.Fprintln(stdout, config.Usage())
 			return 0
 		}
 
-		fmt.Fprintf(os.Stderr, "Error: %v\n\n%s\n", err, config.Usage())
+		fmt.Fprintf(stderr, "Error: %v\n\n%s\n", err, config.Usage())
 		return 1
 	}
 
-	summary, err := files.Run(*cfg)
+	// With --stdout the converted steps own stdout, so the report moves to
+	// stderr to keep the stream pipeable.
+	reportOutput := stdout
+	var summary report.Summary
+	if cfg.Stdout {
+		reportOutput = stderr
+		summary, err = files.Stream(*cfg, stdout)
+	} else {
+		summary, err = files.Run(*cfg)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		return 1
 	}
 
-	if err := summary.Write(os.Stdout, cfg.ReportFormat); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to write report: %v\n", err)
+	if cfg.EnvironmentFile != "" {
+		summary.Variables, err = files.WriteVariables(*cfg)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	if cfg.Verify {
+		summary.Verification, err = verify.Run(context.Background(), *cfg, summary, runRQFile)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: verification failed: %v\n", err)
+			return 1
+		}
+	}
+
+	if err := summary.Write(reportOutput, cfg.ReportFormat); err != nil {
+		fmt.Fprintf(stderr, "Error: failed to write report: %v\n", err)
 		return 1
 	}
 
 	if summary.HasErrors() {
 		return 1
 	}
+	if summary.Verification != nil && summary.Verification.Failed > 0 {
+		return 1
+	}
 
 	return 0
 }
+
+// runRQFile executes one generated file with the rq runner and turns a
+// failing run into an error carrying the runner's diagnostics.
+func runRQFile(ctx context.Context, filename string, variables map[string]any) error {
+	runner, exitResult := execute.New(&rqconfig.Config{
+		TestFiles:      []string{filename},
+		Variables:      variables,
+		OutputFormat:   output.FormatText,
+		RequestTimeout: rqconfig.DefaultTimeout,
+	})
+	if exitResult != nil {
+		return errors.New(exitResult.Message)
+	}
+
+	var stderr bytes.Buffer
+	runner.SetOutput(io.Discard)
+	runner.SetErrorOutput(&stderr)
+
+	if exitCode := runner.Run(ctx); exitCode != 0 {
+		message := strings.TrimSpace(stderr.String())
+		message = strings.TrimPrefix(message, "Error in iteration 1: ")
+		if message == "" {
+			message = fmt.Sprintf("rq exited with code %d", exitCode)
+		}
+		return errors.New(message)
+	}
+
+	return nil
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/jacoelho/rq/internal/pm/report"
+	rqyaml "github.com/jacoelho/rq/internal/rq/yaml"
 )
 
 func TestRunReturnsZeroForSuccessfulMigration(t *testing.T) {
@@ -140,7 +147,7 @@ func runMigration(t *testing.T, content string) (int, string) {
 		"pm2rq",
 		"--input", inputFile,
 		"--out", outputDir,
-	})
+	}, io.Discard, io.Discard)
 	return exitCode, outputDir
 }
 
@@ -169,3 +176,162 @@ func countYAMLFiles(t *testing.T, root string) int {
 
 	return count
 }
+
+func TestRunVerifyReplaysSavedExamples(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "collection.json")
+	outputDir := filepath.Join(tempDir, "out")
+
+	content := `
+{
+  "variable": [{"key": "baseUrl", "value": "https://api.example.com"}],
+  "item": [
+    {
+      "name": "Health",
+      "event": [{"listen":"test","script":{"exec":["tests[\"ok\"] = responseCode.code === 200;"]}}],
+      "request": {"method": "GET", "url": "{{baseUrl}}/health"},
+      "response": [{"name": "OK", "code": 200, "header": [{"key": "Content-Type", "value": "application/json"}], "body": "{\"status\":\"up\"}"}]
+    },
+    {
+      "name": "Create",
+      "event": [{"listen":"test","script":{"exec":["tests[\"created\"] = responseCode.code === 201;"]}}],
+      "request": {"method": "POST", "url": "{{baseUrl}}/users"},
+      "response": [{"name": "OK", "code": 200, "body": "{}"}]
+    },
+    {
+      "name": "Delete",
+      "request": {"method": "DELETE", "url": "{{baseUrl}}/users/1"}
+    }
+  ]
+}
+`
+	if err := os.WriteFile(inputFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	exitCode := run([]string{
+		"pm2rq",
+		"--input", inputFile,
+		"--out", outputDir,
+		"--examples",
+		"--verify",
+		"--report", "json",
+	}, &stdout, io.Discard)
+	if exitCode != 1 {
+		t.Fatalf("run() exitCode = %d, want 1 for failed verification", exitCode)
+	}
+
+	payload := stdout.Bytes()
+	var summary report.Summary
+	if err := json.Unmarshal(payload, &summary); err != nil {
+		t.Fatalf("report is not valid JSON: %v\n%s", err, payload)
+	}
+
+	verification := summary.Verification
+	if verification == nil {
+		t.Fatalf("verification missing from report:\n%s", payload)
+	}
+	if verification.Passed != 1 || verification.Failed != 1 || verification.Skipped != 1 {
+		t.Fatalf("verification = %+v", verification)
+	}
+
+	statuses := make(map[string]report.VerifyStatus)
+	for _, result := range verification.Results {
+		statuses[result.SourcePath] = result.Status
+	}
+	want := map[string]report.VerifyStatus{
+		"Health": report.VerifyPassed,
+		"Create": report.VerifyFailed,
+		"Delete": report.VerifySkipped,
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("statuses = %v, want %v", statuses, want)
+	}
+
+	leftovers, err := filepath.Glob(filepath.Join(outputDir, ".verify-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leftovers) > 0 {
+		t.Fatalf("temporary verification files left behind: %v", leftovers)
+	}
+}
+
+func TestRunStdoutStreamsConvertedSteps(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "collection.json")
+	content := `
+{
+  "item": [
+    {"name": "Health", "request": {"method": "GET", "url": "https://api.example.com/health"}},
+    {
+      "name": "Users",
+      "item": [
+        {"name": "List", "request": {"method": "GET", "url": "https://api.example.com/users"}},
+        {"name": "Create", "request": {"method": "POST", "url": "https://api.example.com/users"}}
+      ]
+    }
+  ]
+}
+`
+	if err := os.WriteFile(inputFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantURLs  []string
+		wantStart string
+	}{
+		{
+			name:      "yaml",
+			wantURLs:  []string{"https://api.example.com/health", "https://api.example.com/users", "https://api.example.com/users"},
+			wantStart: "- method: GET",
+		},
+		{
+			name:      "json filtered to one request",
+			args:      []string{"--format", "json", "--only", "Users/Create"},
+			wantURLs:  []string{"https://api.example.com/users"},
+			wantStart: "[\n  {\n    \"method\": \"POST\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"pm2rq", "--input", inputFile, "--stdout"}, tt.args...)
+			if exitCode := run(args, &stdout, &stderr); exitCode != 0 {
+				t.Fatalf("run() exitCode = %d, stderr:\n%s", exitCode, stderr.String())
+			}
+
+			if !strings.HasPrefix(stdout.String(), tt.wantStart) {
+				t.Fatalf("stdout = %q, want prefix %q", stdout.String(), tt.wantStart)
+			}
+			steps, err := rqyaml.Parse(&stdout)
+			if err != nil {
+				t.Fatalf("stdout is not a valid rq file: %v", err)
+			}
+			urls := make([]string, 0, len(steps))
+			for _, step := range steps {
+				urls = append(urls, step.URL)
+			}
+			if !reflect.DeepEqual(urls, tt.wantURLs) {
+				t.Fatalf("urls = %v, want %v", urls, tt.wantURLs)
+			}
+			if !strings.Contains(stderr.String(), "Collection migration summary") {
+				t.Fatalf("expected the report on stderr, got:\n%s", stderr.String())
+			}
+			if count := countYAMLFiles(t, tempDir); count != 0 {
+				t.Fatalf("expected no generated files with --stdout, got %d", count)
+			}
+		})
+	}
+}
@@ -4,10 +4,18 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/docs"
 	"github.com/jacoelho/rq/internal/rq/execute"
+	"github.com/jacoelho/rq/internal/rq/export"
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/migrate"
+	"github.com/jacoelho/rq/internal/rq/output"
+	"github.com/jacoelho/rq/internal/rq/plan"
+	"github.com/jacoelho/rq/internal/rq/report"
 )
 
 func main() {
@@ -16,6 +24,22 @@ func main() {
 }
 
 func run() int {
+	if len(os.Args) > 1 && os.Args[1] == "plan" {
+		return runPlan(os.Args[1:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		return runMigrate(os.Args[1:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == "docs" {
+		return runDocs(os.Args[1:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		return runReport(os.Args[1:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		return runExport(os.Args[1:])
+	}
+
 	cfg, exitResult := config.Parse(os.Args)
 	if exitResult != nil {
 		exitResult.Print()
@@ -33,3 +57,163 @@ func run() int {
 
 	return r.Run(ctx)
 }
+
+func runPlan(args []string) int {
+	cfg, exitResult := config.ParsePlan(args)
+	if exitResult != nil {
+		exitResult.Print()
+		return exitResult.ExitCode
+	}
+	printer := i18n.NewPrinter(cfg.Lang)
+
+	files, err := plan.Build(cfg.TestFiles, cfg.Variables)
+	if err != nil {
+		printer.Fprintln(os.Stderr, i18n.Error, err)
+		return 1
+	}
+
+	if err := plan.Write(os.Stdout, cfg.Format, files); err != nil {
+		printer.Fprintln(os.Stderr, i18n.WritePlanError, err)
+		return 1
+	}
+
+	return 0
+}
+
+func runExport(args []string) int {
+	cfg, exitResult := config.ParseExport(args)
+	if exitResult != nil {
+		exitResult.Print()
+		return exitResult.ExitCode
+	}
+	printer := i18n.NewPrinter(cfg.Lang)
+
+	files, err := plan.Build(cfg.TestFiles, cfg.Variables)
+	if err != nil {
+		printer.Fprintln(os.Stderr, i18n.Error, err)
+		return 1
+	}
+
+	opts := export.Options{Insecure: cfg.Insecure, CACertFile: cfg.CACertFile}
+	if err := export.Write(os.Stdout, cfg.Format, files, opts); err != nil {
+		printer.Fprintln(os.Stderr, i18n.WriteExportError, err)
+		return 1
+	}
+
+	return 0
+}
+
+func runDocs(args []string) int {
+	cfg, exitResult := config.ParseDocs(args)
+	if exitResult != nil {
+		exitResult.Print()
+		return exitResult.ExitCode
+	}
+	printer := i18n.NewPrinter(cfg.Lang)
+
+	files, err := docs.Build(cfg.TestFiles)
+	if err != nil {
+		printer.Fprintln(os.Stderr, i18n.Error, err)
+		return 1
+	}
+
+	if err := docs.Write(os.Stdout, cfg.Format, files); err != nil {
+		printer.Fprintln(os.Stderr, i18n.WriteDocsError, err)
+		return 1
+	}
+
+	return 0
+}
+
+func runReport(args []string) int {
+	cfg, exitResult := config.ParseReport(args)
+	if exitResult != nil {
+		exitResult.Print()
+		return exitResult.ExitCode
+	}
+	printer := i18n.NewPrinter(cfg.Lang)
+
+	runs, err := report.Load(cfg.Reports)
+	if err != nil {
+		printer.Fprintln(os.Stderr, i18n.Error, err)
+		return 1
+	}
+	if missing := report.MissingShards(runs); len(missing) > 0 {
+		printer.Fprintln(os.Stderr, i18n.MissingShards, strings.Join(missing, ", "))
+	}
+	merged := report.Merge(runs)
+
+	if err := writeMergedReport(cfg.Output, cfg.Format, merged); err != nil {
+		printer.Fprintln(os.Stderr, i18n.WriteMergedError, err)
+		return 1
+	}
+
+	if merged.FailedFiles > 0 {
+		return 1
+	}
+	return 0
+}
+
+// writeMergedReport writes merged to path, or to stdout when path is empty.
+// Closing the file is checked, as a failed close can lose buffered data.
+func writeMergedReport(path string, format report.Format, merged *output.Summary) error {
+	if path == "" {
+		return report.Write(os.Stdout, format, merged)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := report.Write(file, format, merged); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func runMigrate(args []string) int {
+	cfg, exitResult := config.ParseMigrate(args)
+	if exitResult != nil {
+		exitResult.Print()
+		return exitResult.ExitCode
+	}
+	printer := i18n.NewPrinter(cfg.Lang)
+
+	pending := false
+	for _, filename := range cfg.TestFiles {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			printer.Fprintln(os.Stderr, i18n.Error, err)
+			return 1
+		}
+
+		migrated, changes, err := migrate.Migrate(data)
+		if err != nil {
+			printer.Fprintln(os.Stderr, i18n.MigrateError, filename, err)
+			return 1
+		}
+		if len(changes) == 0 {
+			printer.Fprintln(os.Stdout, i18n.MigrateUpToDate, filename)
+			continue
+		}
+
+		pending = true
+		for _, change := range changes {
+			printer.Fprintln(os.Stdout, i18n.MigrateChange, filename, change)
+		}
+
+		if cfg.Write {
+			if err := os.WriteFile(filename, migrated, 0o644); err != nil {
+				printer.Fprintln(os.Stderr, i18n.MigrateWriteError, filename, err)
+				return 1
+			}
+		}
+	}
+
+	if cfg.Check && pending && !cfg.Write {
+		return 1
+	}
+
+	return 0
+}
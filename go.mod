@@ -5,7 +5,16 @@ go 1.24.3
 require (
 	github.com/goccy/go-yaml v1.18.0
 	github.com/google/uuid v1.6.0
+	github.com/quic-go/quic-go v0.57.0
+	github.com/theory/jsonpath v0.9.0
+	golang.org/x/net v0.43.0
 	golang.org/x/time v0.12.0
+	google.golang.org/protobuf v1.36.6
 )
 
-require github.com/theory/jsonpath v0.9.0
+require (
+	github.com/quic-go/qpack v0.6.0 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
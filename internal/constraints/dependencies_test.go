@@ -73,6 +73,8 @@ func TestPurePackagesAvoidSideEffectImports(t *testing.T) {
 		modulePrefix + "rq/expr":        {},
 		modulePrefix + "rq/number":      {},
 		modulePrefix + "rq/assert":      {},
+		modulePrefix + "rq/idn":         {},
+		modulePrefix + "rq/jsonschema":  {},
 		modulePrefix + "pm/normalize":   {},
 		modulePrefix + "pm/lex":         {},
 		modulePrefix + "pm/parse":       {},
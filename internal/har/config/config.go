@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/pm/report"
+)
+
+var (
+	ErrNoArguments         = errors.New("no arguments provided")
+	ErrHelp                = errors.New("help requested")
+	ErrMissingInput        = errors.New("--input is required")
+	ErrInvalidReportFormat = errors.New("--report must be one of: text, json")
+	ErrInvalidPattern      = errors.New("invalid host pattern")
+)
+
+// Config defines CLI options for the HAR import command.
+type Config struct {
+	InputFile    string
+	OutputFile   string
+	Overwrite    bool
+	Hosts        []string
+	Static       bool
+	ReportFormat report.Format
+}
+
+// hostListFlag collects a repeatable host pattern flag.
+type hostListFlag []string
+
+func (f *hostListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *hostListFlag) Set(value string) error {
+	*f = append(*f, strings.ToLower(strings.TrimSpace(value)))
+	return nil
+}
+
+// Parse parses and validates CLI arguments.
+func Parse(args []string) (*Config, error) {
+	if len(args) == 0 {
+		return nil, ErrNoArguments
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+
+	input := fs.String("input", "", "Path to source HAR file")
+	out := fs.String("out", "", "Output rq YAML file; stdout when empty")
+	overwrite := fs.Bool("overwrite", false, "Overwrite an existing output file")
+	static := fs.Bool("include-static", false, "Keep requests for images, stylesheets, scripts, fonts and media")
+	reportFormat := fs.String("report", "text", "Report format: text or json")
+	var hosts hostListFlag
+	fs.Var(&hosts, "host", "Only convert requests whose host matches (repeatable)")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return nil, ErrHelp
+		}
+		return nil, fmt.Errorf("parse arguments: %w", err)
+	}
+
+	if *input == "" {
+		return nil, ErrMissingInput
+	}
+	if _, err := os.Stat(*input); err != nil {
+		return nil, fmt.Errorf("input file not accessible: %w", err)
+	}
+
+	for _, pattern := range hosts {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPattern, pattern)
+		}
+	}
+
+	parsedReportFormat, err := parseReportFormat(*reportFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		InputFile:    *input,
+		OutputFile:   *out,
+		Overwrite:    *overwrite,
+		Hosts:        hosts,
+		Static:       *static,
+		ReportFormat: parsedReportFormat,
+	}, nil
+}
+
+func parseReportFormat(input string) (report.Format, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "", string(report.FormatText):
+		return report.FormatText, nil
+	case string(report.FormatJSON):
+		return report.FormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w, got: %s", ErrInvalidReportFormat, input)
+	}
+}
+
+// Usage returns command usage text.
+func Usage() string {
+	return `har2rq - convert HAR recordings into an rq YAML file
+
+Usage:
+  har2rq --input session.har [--out session.yaml] [--overwrite] [--host PATTERN] [--include-static] [--report text|json]
+
+Options:
+  --input FILE       Path to source HAR file
+  --out FILE         Output rq YAML file; without it steps go to stdout and the report to stderr
+  --overwrite        Overwrite an existing output file
+  --host PATTERN     Only convert requests whose host matches, such as api.example.com or *.example.com (repeatable)
+  --include-static   Keep requests for images, stylesheets, scripts, fonts and media
+  --report FORMAT    Report format: text or json (default: text)
+  -h, --help         Show this help message`
+}
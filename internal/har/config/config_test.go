@@ -0,0 +1,75 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/pm/report"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	input := writeInput(t)
+
+	cfg, err := Parse([]string{"har2rq", "--input", input, "--out", "session.yaml", "--overwrite", "--include-static", "--host", "API.example.com", "--host", "*.internal", "--report", "json"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := &Config{
+		InputFile:    input,
+		OutputFile:   "session.yaml",
+		Overwrite:    true,
+		Hosts:        []string{"api.example.com", "*.internal"},
+		Static:       true,
+		ReportFormat: report.FormatJSON,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("Parse() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	input := writeInput(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want error
+	}{
+		{name: "missing input", args: []string{"har2rq"}, want: ErrMissingInput},
+		{name: "invalid report", args: []string{"har2rq", "--input", input, "--report", "xml"}, want: ErrInvalidReportFormat},
+		{name: "invalid host", args: []string{"har2rq", "--input", input, "--host", "api.["}, want: ErrInvalidPattern},
+		{name: "help", args: []string{"har2rq", "--help"}, want: ErrHelp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(tt.args); !errors.Is(err, tt.want) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse([]string{"har2rq", "--input", "missing.har"}); err == nil {
+		t.Fatal("expected error for missing input file")
+	}
+}
+
+func writeInput(t *testing.T) string {
+	t.Helper()
+
+	input := filepath.Join(t.TempDir(), "session.har")
+	if err := os.WriteFile(input, []byte(`{"log":{"entries":[]}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
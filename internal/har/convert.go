@@ -0,0 +1,200 @@
+package har
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/pm/diagnostics"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// Options selects which entries are converted.
+type Options struct {
+	// Hosts lists glob patterns matched against the request host name, such
+	// as *.example.com. An empty list keeps every host.
+	Hosts []string
+	// Static keeps requests for images, stylesheets, scripts, fonts and
+	// media, which are left out by default.
+	Static bool
+}
+
+// Result is the conversion outcome of one entry.
+type Result struct {
+	SourcePath string
+	Step       model.Step
+	Converted  bool
+	Issues     []report.Issue
+}
+
+// droppedHeaders are recorded headers rq must not replay: the transport sets
+// them itself, and a recorded Accept-Encoding would make the server send a
+// body rq cannot decode.
+var droppedHeaders = map[string]bool{
+	"accept-encoding":   true,
+	"connection":        true,
+	"content-length":    true,
+	"host":              true,
+	"keep-alive":        true,
+	"proxy-connection":  true,
+	"te":                true,
+	"transfer-encoding": true,
+	"upgrade":           true,
+}
+
+var staticResourceTypes = map[string]bool{
+	"font":       true,
+	"image":      true,
+	"media":      true,
+	"script":     true,
+	"stylesheet": true,
+}
+
+// Convert maps the entries selected by opts to steps, in recording order.
+func Convert(archive *Archive, opts Options) []Result {
+	var results []Result
+	for i, entry := range archive.Log.Entries {
+		if !selected(entry, opts) {
+			continue
+		}
+		results = append(results, convertEntry(i, entry))
+	}
+
+	return results
+}
+
+func selected(entry Entry, opts Options) bool {
+	if !opts.Static && isStatic(entry) {
+		return false
+	}
+	if len(opts.Hosts) == 0 {
+		return true
+	}
+
+	parsed, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		// Kept so the report explains why the entry was not converted.
+		return true
+	}
+	for _, pattern := range opts.Hosts {
+		if matched, _ := path.Match(pattern, parsed.Hostname()); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isStatic(entry Entry) bool {
+	if entry.ResourceType != "" {
+		return staticResourceTypes[strings.ToLower(entry.ResourceType)]
+	}
+
+	mimeType := strings.ToLower(entry.Response.Content.MimeType)
+	for _, prefix := range []string{"image/", "font/", "video/", "audio/", "text/css", "text/javascript", "application/javascript"} {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func entryIssue(code report.IssueCode, message string) report.Issue {
+	definition := diagnostics.DefinitionFor(code)
+	return report.Issue{
+		Code:     code,
+		Stage:    definition.DefaultStage,
+		Severity: definition.DefaultSeverity,
+		Message:  message,
+	}
+}
+
+func convertEntry(index int, entry Entry) Result {
+	method := strings.ToUpper(strings.TrimSpace(entry.Request.Method))
+	result := Result{SourcePath: fmt.Sprintf("entries[%d] %s %s", index, method, entry.Request.URL)}
+
+	if method == "" {
+		result.Issues = append(result.Issues, entryIssue(report.CodeInvalidRequestShape, "missing HTTP method"))
+		return result
+	}
+	if !model.IsSupportedMethod(method) {
+		result.Issues = append(result.Issues, entryIssue(report.CodeInvalidRequestShape, fmt.Sprintf("unsupported HTTP method: %s", method)))
+		return result
+	}
+	if _, err := url.ParseRequestURI(entry.Request.URL); err != nil {
+		result.Issues = append(result.Issues, entryIssue(report.CodeInvalidRequestShape, fmt.Sprintf("invalid request URL: %v", err)))
+		return result
+	}
+
+	body, err := convertBody(entry.Request.PostData)
+	if err != nil {
+		result.Issues = append(result.Issues, entryIssue(report.CodeBodyNotSupported, err.Error()))
+		return result
+	}
+
+	step := model.Step{
+		Method:  method,
+		URL:     escapeTemplate(entry.Request.URL),
+		Headers: convertHeaders(entry.Request.Headers),
+		Body:    model.TextBody(body),
+	}
+
+	if entry.Response.Status > 0 {
+		step.Asserts.Status = []model.StatusAssert{{
+			Predicate: model.Predicate{Operation: "equals", Value: entry.Response.Status, HasValue: true},
+		}}
+	} else {
+		result.Issues = append(result.Issues, entryIssue(report.CodeResponseNotRecorded, "no response was recorded, so the step has no status assert"))
+	}
+
+	result.Step = step
+	result.Converted = true
+	return result
+}
+
+func convertHeaders(headers []NameValue) model.KeyValues {
+	var converted model.KeyValues
+	for _, header := range headers {
+		name := strings.TrimSpace(header.Name)
+		// HTTP/2 pseudo-headers such as :authority are not real headers.
+		if name == "" || strings.HasPrefix(name, ":") || droppedHeaders[strings.ToLower(name)] {
+			continue
+		}
+		converted = append(converted, model.KeyValue{Key: name, Value: escapeTemplate(header.Value)})
+	}
+
+	return converted
+}
+
+// convertBody returns the recorded body as text. Form bodies recorded only
+// as params are encoded again; file uploads cannot be replayed because the
+// archive does not hold their content.
+func convertBody(postData *PostData) (string, error) {
+	if postData == nil {
+		return "", nil
+	}
+
+	for _, param := range postData.Params {
+		if param.FileName != "" {
+			return "", fmt.Errorf("file upload %s (%s) is not recorded in the archive", param.Name, param.FileName)
+		}
+	}
+	if postData.Text != "" || len(postData.Params) == 0 {
+		return escapeTemplate(postData.Text), nil
+	}
+
+	values := url.Values{}
+	for _, param := range postData.Params {
+		values.Add(param.Name, param.Value)
+	}
+	return escapeTemplate(values.Encode()), nil
+}
+
+// escapeTemplate keeps recorded text literal when rq renders it as a
+// template, so a body holding {{ is sent as recorded.
+func escapeTemplate(text string) string {
+	return strings.ReplaceAll(text, "{{", `{{"{{"}}`)
+}
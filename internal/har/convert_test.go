@@ -0,0 +1,188 @@
+package har
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/templating"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	status := func(code int) model.Asserts {
+		return model.Asserts{Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: code, HasValue: true}}}}
+	}
+
+	tests := []struct {
+		name      string
+		entry     Entry
+		opts      Options
+		want      *model.Step
+		wantCodes []report.IssueCode
+	}{
+		{
+			name: "request with status",
+			entry: Entry{
+				Request: Request{
+					Method: "post",
+					URL:    "https://api.example.com/users?page=2",
+					Headers: []NameValue{
+						{Name: ":authority", Value: "api.example.com"},
+						{Name: "Content-Type", Value: "application/json"},
+						{Name: "Accept-Encoding", Value: "gzip, br"},
+						{Name: "Content-Length", Value: "13"},
+					},
+					PostData: &PostData{MimeType: "application/json", Text: `{"name":"a"}`},
+				},
+				Response: Response{Status: 201},
+			},
+			want: &model.Step{
+				Method:  "POST",
+				URL:     "https://api.example.com/users?page=2",
+				Headers: model.KeyValues{{Key: "Content-Type", Value: "application/json"}},
+				Body:    model.TextBody(`{"name":"a"}`),
+				Asserts: status(201),
+			},
+		},
+		{
+			name: "form params",
+			entry: Entry{
+				Request:  Request{Method: "POST", URL: "https://api.example.com/form", PostData: &PostData{Params: []Param{{Name: "q", Value: "a b"}}}},
+				Response: Response{Status: 200},
+			},
+			want: &model.Step{Method: "POST", URL: "https://api.example.com/form", Body: model.TextBody("q=a+b"), Asserts: status(200)},
+		},
+		{
+			name: "no response",
+			entry: Entry{
+				Request: Request{Method: "GET", URL: "https://api.example.com/slow"},
+			},
+			want:      &model.Step{Method: "GET", URL: "https://api.example.com/slow"},
+			wantCodes: []report.IssueCode{report.CodeResponseNotRecorded},
+		},
+		{
+			name: "file upload",
+			entry: Entry{
+				Request:  Request{Method: "POST", URL: "https://api.example.com/upload", PostData: &PostData{Params: []Param{{Name: "file", FileName: "a.png"}}}},
+				Response: Response{Status: 201},
+			},
+			wantCodes: []report.IssueCode{report.CodeBodyNotSupported},
+		},
+		{
+			name: "unsupported method",
+			entry: Entry{
+				Request: Request{Method: "BREW", URL: "https://api.example.com/coffee"},
+			},
+			wantCodes: []report.IssueCode{report.CodeInvalidRequestShape},
+		},
+		{
+			name: "static asset skipped",
+			entry: Entry{
+				Request:      Request{Method: "GET", URL: "https://cdn.example.com/app.js"},
+				Response:     Response{Status: 200},
+				ResourceType: "script",
+			},
+		},
+		{
+			name: "static asset kept",
+			entry: Entry{
+				Request:  Request{Method: "GET", URL: "https://cdn.example.com/logo.png"},
+				Response: Response{Status: 200, Content: Content{MimeType: "image/png"}},
+			},
+			opts: Options{Static: true},
+			want: &model.Step{Method: "GET", URL: "https://cdn.example.com/logo.png", Asserts: status(200)},
+		},
+		{
+			name: "host selected",
+			entry: Entry{
+				Request:  Request{Method: "GET", URL: "https://api.example.com/health"},
+				Response: Response{Status: 200},
+			},
+			opts: Options{Hosts: []string{"*.example.com"}},
+			want: &model.Step{Method: "GET", URL: "https://api.example.com/health", Asserts: status(200)},
+		},
+		{
+			name: "host not selected",
+			entry: Entry{
+				Request:  Request{Method: "GET", URL: "https://tracker.example.org/ping"},
+				Response: Response{Status: 204},
+			},
+			opts: Options{Hosts: []string{"*.example.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			results := Convert(&Archive{Log: Log{Entries: []Entry{tt.entry}}}, tt.opts)
+			if tt.want == nil && tt.wantCodes == nil {
+				if len(results) != 0 {
+					t.Fatalf("Convert() = %+v, want the entry left out", results)
+				}
+				return
+			}
+			if len(results) != 1 {
+				t.Fatalf("Convert() returned %d results, want 1", len(results))
+			}
+
+			result := results[0]
+			var codes []report.IssueCode
+			for _, issue := range result.Issues {
+				codes = append(codes, issue.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("issue codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if result.Converted != (tt.want != nil) {
+				t.Fatalf("Converted = %v, want %v", result.Converted, tt.want != nil)
+			}
+			if tt.want != nil && !reflect.DeepEqual(result.Step, *tt.want) {
+				t.Errorf("Step = %+v, want %+v", result.Step, *tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertKeepsTemplateSyntaxLiteral(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"greeting":"{{ .name }}"}`
+	results := Convert(&Archive{Log: Log{Entries: []Entry{{
+		Request:  Request{Method: "POST", URL: "https://api.example.com/{{x}}", PostData: &PostData{Text: body}},
+		Response: Response{Status: 200},
+	}}}}, Options{})
+	if len(results) != 1 || !results[0].Converted {
+		t.Fatalf("Convert() = %+v, want one converted step", results)
+	}
+
+	for got, want := range map[string]string{results[0].Step.Body.Text: body, results[0].Step.URL: "https://api.example.com/{{x}}"} {
+		rendered, err := templating.Apply(got, map[string]any{})
+		if err != nil {
+			t.Fatalf("Apply(%q) error = %v", got, err)
+		}
+		if rendered != want {
+			t.Errorf("Apply(%q) = %q, want %q", got, rendered, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	archive, err := Parse(strings.NewReader(`{"log":{"entries":[{"request":{"method":"GET","url":"https://example.com","headers":[]},"response":{"status":200,"content":{"mimeType":"text/html"}},"_resourceType":"document"}]}}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(archive.Log.Entries) != 1 || archive.Log.Entries[0].ResourceType != "document" {
+		t.Errorf("Parse() = %+v", archive)
+	}
+
+	if _, err := Parse(strings.NewReader(`{"entries":[]}`)); err == nil {
+		t.Error("Parse() error = nil for a document without log")
+	}
+}
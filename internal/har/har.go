@@ -0,0 +1,83 @@
+// Package har reads HTTP Archive (HAR) files recorded by browsers and proxies
+// and converts their entries into rq steps.
+package har
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Archive is the root of a HAR document.
+type Archive struct {
+	Log Log `json:"log"`
+}
+
+// Log holds the recorded entries in the order they were sent.
+type Log struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is one recorded request and its response.
+type Entry struct {
+	Request      Request  `json:"request"`
+	Response     Response `json:"response"`
+	ResourceType string   `json:"_resourceType,omitempty"`
+}
+
+// Request is the recorded request of an entry. URL includes the query string.
+type Request struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Headers  []NameValue `json:"headers"`
+	PostData *PostData   `json:"postData,omitempty"`
+}
+
+// PostData is a recorded request body. Browsers record form bodies either as
+// Text, Params or both.
+type PostData struct {
+	MimeType string  `json:"mimeType"`
+	Text     string  `json:"text"`
+	Params   []Param `json:"params,omitempty"`
+}
+
+// Param is a form field of a recorded body. FileName is set for file uploads,
+// whose content is not part of the archive.
+type Param struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	FileName string `json:"fileName,omitempty"`
+}
+
+// Response is the recorded response of an entry. Status is 0 when the request
+// was blocked or aborted before a response arrived.
+type Response struct {
+	Status  int     `json:"status"`
+	Content Content `json:"content"`
+}
+
+// Content describes the recorded response body.
+type Content struct {
+	MimeType string `json:"mimeType"`
+}
+
+// NameValue is a header of a recorded request.
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Parse decodes a HAR document.
+func Parse(r io.Reader) (*Archive, error) {
+	var archive struct {
+		Log *Log `json:"log"`
+	}
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return nil, err
+	}
+	if archive.Log == nil {
+		return nil, errors.New("document has no log")
+	}
+
+	return &Archive{Log: *archive.Log}, nil
+}
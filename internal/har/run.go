@@ -0,0 +1,83 @@
+package har
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jacoelho/rq/internal/har/config"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/yaml"
+)
+
+// Run converts the HAR file of cfg and writes the steps to cfg.OutputFile,
+// or to stdout when it is empty. Steps keep the recording order, so a
+// session that logs in before calling the API replays the same way.
+func Run(cfg config.Config, stdout io.Writer) (report.Summary, error) {
+	file, err := os.Open(cfg.InputFile)
+	if err != nil {
+		return report.Summary{}, fmt.Errorf("open input file: %w", err)
+	}
+	defer file.Close()
+
+	archive, err := Parse(file)
+	if err != nil {
+		return report.Summary{}, fmt.Errorf("parse HAR: %w", err)
+	}
+
+	summary := report.Summary{Title: "HAR import summary"}
+	var steps []model.Step
+	for _, result := range Convert(archive, Options{Hosts: cfg.Hosts, Static: cfg.Static}) {
+		entry := report.RequestResult{
+			SourcePath: result.SourcePath,
+			Converted:  result.Converted && !report.HasErrors(result.Issues),
+			Issues:     result.Issues,
+		}
+		if entry.Converted {
+			entry.OutputPath = cfg.OutputFile
+			steps = append(steps, result.Step)
+		}
+		summary.Add(entry)
+	}
+	if len(steps) == 0 {
+		return summary, nil
+	}
+
+	payload, err := yaml.EncodeSteps(steps)
+	if err != nil {
+		return report.Summary{}, err
+	}
+	if cfg.OutputFile == "" {
+		if _, err := stdout.Write(payload); err != nil {
+			return report.Summary{}, fmt.Errorf("write steps: %w", err)
+		}
+		return summary, nil
+	}
+
+	if err := writeOutput(cfg.OutputFile, cfg.Overwrite, payload); err != nil {
+		return report.Summary{}, err
+	}
+	return summary, nil
+}
+
+func writeOutput(filename string, overwrite bool, payload []byte) error {
+	if !overwrite {
+		if _, err := os.Stat(filename); err == nil {
+			return fmt.Errorf("output file exists and --overwrite is false: %s", filename)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("stat output file: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+	if err := os.WriteFile(filename, payload, 0644); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
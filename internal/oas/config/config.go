@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/pm/report"
+)
+
+var (
+	ErrNoArguments         = errors.New("no arguments provided")
+	ErrHelp                = errors.New("help requested")
+	ErrMissingInput        = errors.New("--input is required")
+	ErrMissingOutput       = errors.New("--out is required")
+	ErrInvalidReportFormat = errors.New("--report must be one of: text, json")
+	ErrInvalidGroup        = errors.New("--group must be one of: tag, path")
+)
+
+// Group selects how operations are split into files.
+type Group string
+
+const (
+	GroupTag  Group = "tag"
+	GroupPath Group = "path"
+)
+
+// Config defines CLI options for the OpenAPI import command.
+type Config struct {
+	InputFile    string
+	OutputDir    string
+	Overwrite    bool
+	DryRun       bool
+	Group        Group
+	ReportFormat report.Format
+}
+
+// Parse parses and validates CLI arguments.
+func Parse(args []string) (*Config, error) {
+	if len(args) == 0 {
+		return nil, ErrNoArguments
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+
+	input := fs.String("input", "", "Path to source OpenAPI 3 document")
+	out := fs.String("out", "", "Output directory for generated rq YAML files")
+	overwrite := fs.Bool("overwrite", false, "Overwrite existing output files")
+	dryRun := fs.Bool("dry-run", false, "Run generation without writing files")
+	group := fs.String("group", "tag", "Split operations into files by: tag or path")
+	reportFormat := fs.String("report", "text", "Report format: text or json")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return nil, ErrHelp
+		}
+		return nil, fmt.Errorf("parse arguments: %w", err)
+	}
+
+	if *input == "" {
+		return nil, ErrMissingInput
+	}
+	if *out == "" {
+		return nil, ErrMissingOutput
+	}
+	if _, err := os.Stat(*input); err != nil {
+		return nil, fmt.Errorf("input file not accessible: %w", err)
+	}
+
+	parsedGroup, err := parseGroup(*group)
+	if err != nil {
+		return nil, err
+	}
+	parsedReportFormat, err := parseReportFormat(*reportFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		InputFile:    *input,
+		OutputDir:    *out,
+		Overwrite:    *overwrite,
+		DryRun:       *dryRun,
+		Group:        parsedGroup,
+		ReportFormat: parsedReportFormat,
+	}, nil
+}
+
+func parseGroup(input string) (Group, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "", string(GroupTag):
+		return GroupTag, nil
+	case string(GroupPath):
+		return GroupPath, nil
+	default:
+		return "", fmt.Errorf("%w, got: %s", ErrInvalidGroup, input)
+	}
+}
+
+func parseReportFormat(input string) (report.Format, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "", string(report.FormatText):
+		return report.FormatText, nil
+	case string(report.FormatJSON):
+		return report.FormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w, got: %s", ErrInvalidReportFormat, input)
+	}
+}
+
+// Usage returns command usage text.
+func Usage() string {
+	return `openapi2rq - generate rq test skeletons from an OpenAPI 3 document
+
+Usage:
+  openapi2rq --input openapi.yaml --out ./tests [--group tag|path] [--overwrite] [--dry-run] [--report text|json]
+
+Options:
+  --input FILE       Path to source OpenAPI 3 document (YAML or JSON)
+  --out DIR          Output directory for generated rq YAML files
+  --group GROUP      Split operations into files by first tag or first path segment (default: tag)
+  --overwrite        Overwrite existing files
+  --dry-run          Run generation without writing files
+  --report FORMAT    Report format: text or json (default: text)
+  -h, --help         Show this help message`
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/pm/report"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	input := writeInput(t)
+
+	cfg, err := Parse([]string{"openapi2rq", "--input", input, "--out", "tests", "--group", "PATH", "--overwrite", "--dry-run", "--report", "json"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := &Config{
+		InputFile:    input,
+		OutputDir:    "tests",
+		Overwrite:    true,
+		DryRun:       true,
+		Group:        GroupPath,
+		ReportFormat: report.FormatJSON,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("Parse() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	input := writeInput(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want error
+	}{
+		{name: "missing input", args: []string{"openapi2rq", "--out", "tests"}, want: ErrMissingInput},
+		{name: "missing output", args: []string{"openapi2rq", "--input", input}, want: ErrMissingOutput},
+		{name: "invalid group", args: []string{"openapi2rq", "--input", input, "--out", "tests", "--group", "operation"}, want: ErrInvalidGroup},
+		{name: "invalid report", args: []string{"openapi2rq", "--input", input, "--out", "tests", "--report", "xml"}, want: ErrInvalidReportFormat},
+		{name: "help", args: []string{"openapi2rq", "--help"}, want: ErrHelp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(tt.args); !errors.Is(err, tt.want) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func writeInput(t *testing.T) string {
+	t.Helper()
+
+	input := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(input, []byte("openapi: 3.0.3\npaths: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
@@ -0,0 +1,624 @@
+// Package oas generates rq test skeletons from OpenAPI 3 documents: one step
+// per operation with an example request, the documented success status and
+// jsonpath asserts derived from the response schema.
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/oas/config"
+	"github.com/jacoelho/rq/internal/pm/diagnostics"
+	"github.com/jacoelho/rq/internal/pm/naming"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/jsonschema"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/openapi"
+)
+
+// BaseURLVariable is the variable generated URLs start with.
+const BaseURLVariable = "base_url"
+
+// maxDepth bounds $ref chains and nested schemas, so recursive schemas end.
+const maxDepth = 8
+
+// methods lists the operation keys of a path item in the order steps are
+// generated.
+var methods = []string{"get", "post", "put", "patch", "delete", "head", "options", "trace"}
+
+var (
+	pathParameter = regexp.MustCompile(`\{([^{}]+)\}`)
+	identifier    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	nonIdentifier = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Result is the generated step of one operation.
+type Result struct {
+	SourcePath string
+	File       string
+	Step       model.Step
+	Converted  bool
+	Issues     []report.Issue
+}
+
+type generator struct {
+	document map[string]any
+}
+
+// Generate builds one step per operation of spec, ordered by path and
+// method.
+func Generate(spec *openapi.Spec, group config.Group) []Result {
+	g := &generator{document: spec.Document()}
+	paths, _ := g.document["paths"].(map[string]any)
+
+	var results []Result
+	for _, template := range slices.Sorted(maps.Keys(paths)) {
+		item, ok := g.resolve(paths[template])
+		if !ok {
+			continue
+		}
+		for _, method := range methods {
+			node, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			results = append(results, g.operation(template, method, item, node, group))
+		}
+	}
+
+	return results
+}
+
+func issue(code report.IssueCode, message string) report.Issue {
+	definition := diagnostics.DefinitionFor(code)
+	return report.Issue{
+		Code:     code,
+		Stage:    definition.DefaultStage,
+		Severity: definition.DefaultSeverity,
+		Message:  message,
+	}
+}
+
+func (g *generator) operation(template string, method string, item map[string]any, node map[string]any, group config.Group) Result {
+	result := Result{
+		SourcePath: strings.ToUpper(method) + " " + template,
+		File:       fileName(template, node, group),
+	}
+	if !model.IsSupportedMethod(strings.ToUpper(method)) {
+		result.Issues = append(result.Issues, issue(report.CodeInvalidRequestShape, fmt.Sprintf("unsupported HTTP method: %s", strings.ToUpper(method))))
+		return result
+	}
+
+	step := model.Step{
+		Method:      strings.ToUpper(method),
+		URL:         "{{." + BaseURLVariable + "}}" + pathParameter.ReplaceAllStringFunc(template, func(match string) string { return variable(match[1 : len(match)-1]) }),
+		Description: description(node),
+	}
+
+	for _, parameter := range g.parameters(item, node) {
+		name, _ := parameter["name"].(string)
+		if required, _ := parameter["required"].(bool); !required || name == "" {
+			continue
+		}
+		value := g.parameterValue(parameter, name)
+		switch parameter["in"] {
+		case "query":
+			step.Query = append(step.Query, model.KeyValue{Key: name, Value: value})
+		case "header":
+			// The spec describes these with requestBody, responses and security.
+			if !slices.Contains([]string{"accept", "authorization", "content-type"}, strings.ToLower(name)) {
+				step.Headers = append(step.Headers, model.KeyValue{Key: name, Value: value})
+			}
+		}
+	}
+
+	if err := g.requestBody(&step, node); err != nil {
+		result.Issues = append(result.Issues, issue(report.CodeBodyNotSupported, err.Error()))
+		return result
+	}
+	result.Issues = append(result.Issues, g.security(&step, node)...)
+	result.Issues = append(result.Issues, g.responseAsserts(&step, node)...)
+
+	result.Step = step
+	result.Converted = !report.HasErrors(result.Issues)
+	return result
+}
+
+// fileName returns the file of an operation: its first tag, or the first
+// literal segment of its path, such as pets.yaml for /pets/{id}. Untagged
+// operations are grouped by path.
+func fileName(template string, node map[string]any, group config.Group) string {
+	if tags, _ := node["tags"].([]any); group == config.GroupTag && len(tags) > 0 {
+		if tag, ok := tags[0].(string); ok && strings.TrimSpace(tag) != "" {
+			return naming.SanitizeSegment(tag) + ".yaml"
+		}
+	}
+
+	for segment := range strings.SplitSeq(strings.Trim(template, "/"), "/") {
+		if segment != "" && !strings.Contains(segment, "{") {
+			return naming.SanitizeSegment(segment) + ".yaml"
+		}
+	}
+	return "root.yaml"
+}
+
+func description(node map[string]any) string {
+	for _, key := range []string{"summary", "operationId"} {
+		if text, ok := node[key].(string); ok && strings.TrimSpace(text) != "" {
+			return strings.TrimSpace(text)
+		}
+	}
+	return ""
+}
+
+// variable returns the template reading name. Names that are not Go
+// identifiers, such as pet-id, are read with index.
+func variable(name string) string {
+	if identifier.MatchString(name) {
+		return "{{." + name + "}}"
+	}
+	return fmt.Sprintf("{{index . %q}}", name)
+}
+
+// variableName turns a security scheme name such as ApiKeyAuth into a
+// variable name such as apikeyauth.
+func variableName(name string) string {
+	slug := strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" || !identifier.MatchString(slug) {
+		return "api_key"
+	}
+	return slug
+}
+
+// resolve follows $ref to an object of the document.
+func (g *generator) resolve(node any) (map[string]any, bool) {
+	for range maxDepth {
+		current, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		ref, isRef := current["$ref"].(string)
+		if !isRef {
+			return current, true
+		}
+		target, err := jsonschema.Resolve(g.document, ref)
+		if err != nil {
+			return nil, false
+		}
+		node = target
+	}
+	return nil, false
+}
+
+// parameters merges path item and operation parameters. Operation
+// parameters override path item ones with the same name and location.
+func (g *generator) parameters(item map[string]any, node map[string]any) []map[string]any {
+	var merged []map[string]any
+	for _, source := range []any{item["parameters"], node["parameters"]} {
+		list, _ := source.([]any)
+		for _, entry := range list {
+			parameter, ok := g.resolve(entry)
+			if !ok {
+				continue
+			}
+			merged = slices.DeleteFunc(merged, func(existing map[string]any) bool {
+				return existing["name"] == parameter["name"] && existing["in"] == parameter["in"]
+			})
+			merged = append(merged, parameter)
+		}
+	}
+	return merged
+}
+
+// parameterValue returns the example of a parameter, or a template reading
+// a variable of the same name.
+func (g *generator) parameterValue(parameter map[string]any, name string) string {
+	if value, ok := g.example(parameter); ok {
+		return fmt.Sprint(value)
+	}
+	if schema, ok := g.resolve(parameter["schema"]); ok {
+		for _, key := range []string{"example", "default"} {
+			if value, found := schema[key]; found {
+				return fmt.Sprint(value)
+			}
+		}
+		if enum, _ := schema["enum"].([]any); len(enum) > 0 {
+			return fmt.Sprint(enum[0])
+		}
+	}
+	return variable(name)
+}
+
+// example returns the example of a parameter or media type object: its
+// example, or the value of its first named example.
+func (g *generator) example(node map[string]any) (any, bool) {
+	if value, ok := node["example"]; ok {
+		return value, true
+	}
+	examples, _ := node["examples"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(examples)) {
+		example, ok := g.resolve(examples[name])
+		if !ok {
+			continue
+		}
+		if value, ok := example["value"]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// requestBody sets the body of step from the first supported media type. A
+// required body in another media type is an error; an optional one is left
+// out.
+func (g *generator) requestBody(step *model.Step, node map[string]any) error {
+	body, ok := g.resolve(node["requestBody"])
+	if !ok {
+		return nil
+	}
+	content, _ := body["content"].(map[string]any)
+	keys := slices.Sorted(maps.Keys(content))
+
+	for _, key := range keys {
+		mediaType, _, err := mime.ParseMediaType(key)
+		if err != nil {
+			continue
+		}
+		media, _ := g.resolve(content[key])
+		value, found := g.example(media)
+		if !found {
+			value = g.sample(media["schema"], 0)
+		}
+
+		switch {
+		case isJSON(mediaType):
+			switch value.(type) {
+			case map[string]any, []any:
+				step.Body = model.Body{Value: value}
+			default:
+				data, err := json.Marshal(value)
+				if err != nil {
+					return fmt.Errorf("encode example body: %w", err)
+				}
+				step.Body = model.TextBody(string(data))
+			}
+			// Structured bodies are sent as application/json by default.
+			if mediaType != "application/json" || !step.Body.IsStructured() {
+				step.Headers = append(step.Headers, model.KeyValue{Key: "Content-Type", Value: key})
+			}
+			return nil
+		case mediaType == "application/x-www-form-urlencoded":
+			fields, _ := value.(map[string]any)
+			values := url.Values{}
+			for _, name := range slices.Sorted(maps.Keys(fields)) {
+				values.Set(name, fmt.Sprint(fields[name]))
+			}
+			step.Body = model.TextBody(values.Encode())
+			step.Headers = append(step.Headers, model.KeyValue{Key: "Content-Type", Value: key})
+			return nil
+		case strings.HasPrefix(mediaType, "text/"):
+			if value != nil {
+				step.Body = model.TextBody(fmt.Sprint(value))
+			}
+			step.Headers = append(step.Headers, model.KeyValue{Key: "Content-Type", Value: key})
+			return nil
+		}
+	}
+
+	if required, _ := body["required"].(bool); required && len(keys) > 0 {
+		return fmt.Errorf("request body media types are not supported: %s", strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// security maps the first security requirement of the operation, or of the
+// document, to headers, query parameters or oauth2 auth reading variables.
+func (g *generator) security(step *model.Step, node map[string]any) []report.Issue {
+	requirements, found := node["security"].([]any)
+	if !found {
+		requirements, _ = g.document["security"].([]any)
+	}
+	if len(requirements) == 0 {
+		return nil
+	}
+	requirement, _ := requirements[0].(map[string]any)
+
+	components, _ := g.document["components"].(map[string]any)
+	schemes, _ := components["securitySchemes"].(map[string]any)
+
+	var issues []report.Issue
+	for _, name := range slices.Sorted(maps.Keys(requirement)) {
+		scheme, ok := g.resolve(schemes[name])
+		if !ok {
+			issues = append(issues, issue(report.CodeAuthNotMapped, fmt.Sprintf("security scheme %s is not defined", name)))
+			continue
+		}
+
+		kind, _ := scheme["type"].(string)
+		httpScheme, _ := scheme["scheme"].(string)
+		in, _ := scheme["in"].(string)
+		keyName, _ := scheme["name"].(string)
+		switch {
+		case kind == "http" && strings.EqualFold(httpScheme, "bearer"):
+			step.Headers = append(step.Headers, model.KeyValue{Key: "Authorization", Value: "Bearer {{.token}}"})
+		case kind == "http" && strings.EqualFold(httpScheme, "basic"):
+			step.Headers = append(step.Headers, model.KeyValue{Key: "Authorization", Value: `Basic {{b64enc (print .username ":" .password)}}`})
+		case kind == "apiKey" && in == "header" && keyName != "":
+			step.Headers = append(step.Headers, model.KeyValue{Key: keyName, Value: "{{." + variableName(name) + "}}"})
+		case kind == "apiKey" && in == "query" && keyName != "":
+			step.Query = append(step.Query, model.KeyValue{Key: keyName, Value: "{{." + variableName(name) + "}}"})
+		case kind == "apiKey" && in == "cookie" && keyName != "":
+			step.Headers = append(step.Headers, model.KeyValue{Key: "Cookie", Value: keyName + "={{." + variableName(name) + "}}"})
+		case kind == "oauth2" && g.clientCredentials(scheme) != "":
+			scopes := make([]string, 0)
+			list, _ := requirement[name].([]any)
+			for _, scope := range list {
+				scopes = append(scopes, fmt.Sprint(scope))
+			}
+			step.Auth = &model.Auth{OAuth2: &model.OAuth2{
+				TokenURL:     g.clientCredentials(scheme),
+				ClientID:     "{{.client_id}}",
+				ClientSecret: "{{.client_secret}}",
+				Scopes:       scopes,
+			}}
+		default:
+			issues = append(issues, issue(report.CodeAuthNotMapped, fmt.Sprintf("security scheme %s (%s) was not mapped; add the credentials manually", name, kind)))
+		}
+	}
+
+	return issues
+}
+
+// clientCredentials returns the token URL of the client credentials flow
+// of an oauth2 scheme.
+func (g *generator) clientCredentials(scheme map[string]any) string {
+	flows, _ := scheme["flows"].(map[string]any)
+	flow, _ := flows["clientCredentials"].(map[string]any)
+	tokenURL, _ := flow["tokenUrl"].(string)
+	return tokenURL
+}
+
+// responseAsserts asserts the first documented success status and, for a
+// JSON response, the type of the root and of the required, non-nullable properties.
+func (g *generator) responseAsserts(step *model.Step, node map[string]any) []report.Issue {
+	responses, _ := g.resolve(node["responses"])
+
+	var code string
+	for _, key := range slices.Sorted(maps.Keys(responses)) {
+		if strings.HasPrefix(key, "2") {
+			code = key
+			break
+		}
+	}
+	if code == "" {
+		return []report.Issue{issue(report.CodeSuccessStatusMissing, "no 2xx response is documented, so the step has no status assert")}
+	}
+
+	// A range such as 2XX allows any success status.
+	predicate := model.Predicate{Operation: "between", Value: []any{200, 299}, HasValue: true}
+	if status, err := strconv.Atoi(code); err == nil {
+		predicate = model.Predicate{Operation: "equals", Value: status, HasValue: true}
+	}
+	step.Asserts.Status = []model.StatusAssert{{Predicate: predicate}}
+
+	response, _ := g.resolve(responses[code])
+	content, _ := response["content"].(map[string]any)
+	for _, key := range slices.Sorted(maps.Keys(content)) {
+		mediaType, _, err := mime.ParseMediaType(key)
+		if err != nil || !isJSON(mediaType) {
+			continue
+		}
+		media, _ := g.resolve(content[key])
+		step.Asserts.JSONPath = g.schemaAsserts(media["schema"])
+		break
+	}
+
+	return nil
+}
+
+func (g *generator) schemaAsserts(node any) []model.JSONPathAssert {
+	schema, ok := g.resolve(node)
+	if !ok {
+		return nil
+	}
+
+	switch g.schemaType(schema) {
+	case "array":
+		return []model.JSONPathAssert{{Path: "$", Predicate: model.Predicate{Operation: "type_is", Value: "array", HasValue: true}}}
+	case "object":
+	default:
+		return nil
+	}
+
+	properties, required := g.objectShape(schema, 0)
+	var asserts []model.JSONPathAssert
+	for _, name := range required {
+		path := "$." + name
+		if !identifier.MatchString(name) {
+			path = fmt.Sprintf("$[%q]", name)
+		}
+
+		predicate := model.Predicate{Operation: "exists"}
+		if property, ok := g.resolve(properties[name]); ok {
+			// exists fails on null, so nullable properties are not asserted.
+			if nullable(property) {
+				continue
+			}
+			if kind := assertType(g.schemaType(property)); kind != "" {
+				predicate = model.Predicate{Operation: "type_is", Value: kind, HasValue: true}
+			}
+		}
+		asserts = append(asserts, model.JSONPathAssert{Path: path, Predicate: predicate})
+	}
+
+	return asserts
+}
+
+// assertType maps a schema type to a type_is value.
+func assertType(schemaType string) string {
+	switch schemaType {
+	case "integer", "number":
+		return "number"
+	case "string", "boolean", "object", "array":
+		return schemaType
+	default:
+		return ""
+	}
+}
+
+func nullable(schema map[string]any) bool {
+	if value, _ := schema["nullable"].(bool); value {
+		return true
+	}
+	types, _ := schema["type"].([]any)
+	return slices.Contains(types, any("null"))
+}
+
+// schemaType returns the declared type of schema, or the type implied by
+// properties or items.
+func (g *generator) schemaType(schema map[string]any) string {
+	switch kind := schema["type"].(type) {
+	case string:
+		return kind
+	case []any:
+		for _, entry := range kind {
+			if name, ok := entry.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, entry := range all {
+			if member, ok := g.resolve(entry); ok {
+				if kind := g.schemaType(member); kind != "" {
+					return kind
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// objectShape collects the properties and sorted required names of an
+// object schema, including those of allOf members.
+func (g *generator) objectShape(schema map[string]any, depth int) (map[string]any, []string) {
+	properties := make(map[string]any)
+	var required []string
+	if depth > maxDepth {
+		return properties, required
+	}
+
+	if own, ok := schema["properties"].(map[string]any); ok {
+		maps.Copy(properties, own)
+	}
+	if names, ok := schema["required"].([]any); ok {
+		for _, name := range names {
+			if text, ok := name.(string); ok && !slices.Contains(required, text) {
+				required = append(required, text)
+			}
+		}
+	}
+	all, _ := schema["allOf"].([]any)
+	for _, entry := range all {
+		member, ok := g.resolve(entry)
+		if !ok {
+			continue
+		}
+		memberProperties, memberRequired := g.objectShape(member, depth+1)
+		maps.Copy(properties, memberProperties)
+		for _, name := range memberRequired {
+			if !slices.Contains(required, name) {
+				required = append(required, name)
+			}
+		}
+	}
+
+	slices.Sort(required)
+	return properties, required
+}
+
+// sample builds an example value from a schema: its example, default or
+// first enum value, or a placeholder of its type. Objects hold their
+// required properties, or every property when none is required.
+func (g *generator) sample(node any, depth int) any {
+	schema, ok := g.resolve(node)
+	if !ok || depth > maxDepth {
+		return nil
+	}
+
+	for _, key := range []string{"example", "default", "const"} {
+		if value, found := schema[key]; found {
+			return value
+		}
+	}
+	if enum, _ := schema["enum"].([]any); len(enum) > 0 {
+		return enum[0]
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options, _ := schema[key].([]any); len(options) > 0 {
+			return g.sample(options[0], depth+1)
+		}
+	}
+
+	switch g.schemaType(schema) {
+	case "object":
+		properties, required := g.objectShape(schema, depth)
+		names := required
+		if len(names) == 0 {
+			names = slices.Sorted(maps.Keys(properties))
+		}
+		object := make(map[string]any, len(names))
+		for _, name := range names {
+			object[name] = g.sample(properties[name], depth+1)
+		}
+		return object
+	case "array":
+		return []any{g.sample(schema["items"], depth+1)}
+	case "integer", "number":
+		if minimum, found := schema["minimum"]; found {
+			return minimum
+		}
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		format, _ := schema["format"].(string)
+		return sampleString(format)
+	default:
+		return nil
+	}
+}
+
+func sampleString(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	default:
+		return "string"
+	}
+}
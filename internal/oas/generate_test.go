@@ -0,0 +1,250 @@
+package oas
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/oas/config"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/openapi"
+)
+
+const document = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+security: [{bearer: []}]
+components:
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+    key: {type: apiKey, in: query, name: api_key}
+    client:
+      type: oauth2
+      flows:
+        clientCredentials: {tokenUrl: https://auth.example.com/token, scopes: {}}
+    openid: {type: openIdConnect, openIdConnectUrl: https://auth.example.com}
+  schemas:
+    Pet:
+      type: object
+      required: [id, name, nickname, owner-id]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+        nickname: {type: string, nullable: true}
+        owner-id: {}
+        born: {type: string, format: date}
+    NewPet:
+      allOf:
+        - {$ref: '#/components/schemas/Pet'}
+        - {type: object, required: [kind], properties: {kind: {type: string, enum: [cat, dog]}}}
+paths:
+  /pets:
+    get:
+      tags: [Pets]
+      summary: List pets
+      parameters:
+        - {name: limit, in: query, required: true, schema: {type: integer, default: 10}}
+        - {name: page, in: query, schema: {type: integer}}
+        - {name: X-Request-ID, in: header, required: true, schema: {type: string}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    post:
+      tags: [Pets]
+      security: [{key: []}]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/NewPet'}
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+  /pets/{petId}:
+    parameters:
+      - {name: petId, in: path, required: true, schema: {type: integer}}
+    get:
+      tags: [Pets]
+      operationId: getPet
+      security: [{client: ["pets:read"]}]
+      responses:
+        2XX: {description: ok}
+    delete:
+      security: [{openid: []}]
+      responses:
+        default: {description: error}
+  /pets/{petId}/photo:
+    put:
+      security: []
+      requestBody:
+        required: true
+        content:
+          image/png: {schema: {type: string, format: binary}}
+      responses:
+        "204": {description: ok}
+  /login:
+    post:
+      security: []
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            example: {user: alice, password: secret}
+      responses:
+        "204": {description: ok}
+`
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	spec, err := openapi.Parse([]byte(document))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	equals := func(status int) []model.StatusAssert {
+		return []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: status, HasValue: true}}}
+	}
+	typeIs := func(path string, kind string) model.JSONPathAssert {
+		return model.JSONPathAssert{Path: path, Predicate: model.Predicate{Operation: "type_is", Value: kind, HasValue: true}}
+	}
+	petAsserts := []model.JSONPathAssert{
+		typeIs("$.id", "number"),
+		typeIs("$.name", "string"),
+		{Path: `$["owner-id"]`, Predicate: model.Predicate{Operation: "exists"}},
+	}
+
+	tests := []struct {
+		source    string
+		file      string
+		want      *model.Step
+		wantCodes []report.IssueCode
+	}{
+		{
+			source: "POST /login",
+			file:   "login.yaml",
+			want: &model.Step{
+				Method:  "POST",
+				URL:     "{{.base_url}}/login",
+				Headers: model.KeyValues{{Key: "Content-Type", Value: "application/x-www-form-urlencoded"}},
+				Body:    model.TextBody("password=secret&user=alice"),
+				Asserts: model.Asserts{Status: equals(204)},
+			},
+		},
+		{
+			source: "GET /pets",
+			file:   "pets.yaml",
+			want: &model.Step{
+				Method:      "GET",
+				URL:         "{{.base_url}}/pets",
+				Description: "List pets",
+				Headers: model.KeyValues{
+					{Key: "X-Request-ID", Value: `{{index . "X-Request-ID"}}`},
+					{Key: "Authorization", Value: "Bearer {{.token}}"},
+				},
+				Query: model.KeyValues{{Key: "limit", Value: "10"}},
+				Asserts: model.Asserts{
+					Status:   equals(200),
+					JSONPath: []model.JSONPathAssert{typeIs("$", "array")},
+				},
+			},
+		},
+		{
+			source: "POST /pets",
+			file:   "pets.yaml",
+			want: &model.Step{
+				Method: "POST",
+				URL:    "{{.base_url}}/pets",
+				Query:  model.KeyValues{{Key: "api_key", Value: "{{.key}}"}},
+				Body: model.Body{Value: map[string]any{
+					"id": 0, "kind": "cat", "name": "string", "nickname": "string", "owner-id": nil,
+				}},
+				Asserts: model.Asserts{Status: equals(201), JSONPath: petAsserts},
+			},
+		},
+		{
+			source: "GET /pets/{petId}",
+			file:   "pets.yaml",
+			want: &model.Step{
+				Method:      "GET",
+				URL:         "{{.base_url}}/pets/{{.petId}}",
+				Description: "getPet",
+				Auth: &model.Auth{OAuth2: &model.OAuth2{
+					TokenURL:     "https://auth.example.com/token",
+					ClientID:     "{{.client_id}}",
+					ClientSecret: "{{.client_secret}}",
+					Scopes:       []string{"pets:read"},
+				}},
+				Asserts: model.Asserts{Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "between", Value: []any{200, 299}, HasValue: true}}}},
+			},
+		},
+		{
+			source: "DELETE /pets/{petId}",
+			file:   "pets.yaml",
+			want: &model.Step{
+				Method: "DELETE",
+				URL:    "{{.base_url}}/pets/{{.petId}}",
+			},
+			wantCodes: []report.IssueCode{report.CodeAuthNotMapped, report.CodeSuccessStatusMissing},
+		},
+		{
+			source:    "PUT /pets/{petId}/photo",
+			file:      "pets.yaml",
+			wantCodes: []report.IssueCode{report.CodeBodyNotSupported},
+		},
+	}
+
+	results := Generate(spec, config.GroupPath)
+	if len(results) != len(tests) {
+		t.Fatalf("Generate() returned %d results, want %d", len(results), len(tests))
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			t.Parallel()
+
+			result := results[i]
+			if result.SourcePath != tt.source || result.File != tt.file {
+				t.Fatalf("result = %s in %s, want %s in %s", result.SourcePath, result.File, tt.source, tt.file)
+			}
+
+			var codes []report.IssueCode
+			for _, issue := range result.Issues {
+				codes = append(codes, issue.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("issue codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if result.Converted != (tt.want != nil) {
+				t.Fatalf("Converted = %v, want %v", result.Converted, tt.want != nil)
+			}
+			if tt.want != nil && !reflect.DeepEqual(result.Step, *tt.want) {
+				t.Errorf("Step =\n%#v\nwant\n%#v", result.Step, *tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateGroupsByTag(t *testing.T) {
+	t.Parallel()
+
+	spec, err := openapi.Parse([]byte(document))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var files []string
+	for _, result := range Generate(spec, config.GroupTag) {
+		files = append(files, result.File)
+	}
+
+	want := []string{"login.yaml", "pets.yaml", "pets.yaml", "pets.yaml", "pets.yaml", "pets.yaml"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+}
@@ -0,0 +1,99 @@
+package oas
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jacoelho/rq/internal/oas/config"
+	"github.com/jacoelho/rq/internal/pm/diagnostics"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/openapi"
+	"github.com/jacoelho/rq/internal/rq/yaml"
+)
+
+// Run generates the steps of cfg.InputFile and writes one file per group to
+// cfg.OutputDir. A file that exists without --overwrite is left alone and
+// its operations are reported as skipped.
+func Run(cfg config.Config) (report.Summary, error) {
+	spec, err := openapi.Load(cfg.InputFile)
+	if err != nil {
+		return report.Summary{}, fmt.Errorf("load OpenAPI document: %w", err)
+	}
+
+	results := Generate(spec, cfg.Group)
+
+	var files []string
+	steps := make(map[string][]model.Step)
+	for _, result := range results {
+		if !result.Converted {
+			continue
+		}
+		if _, seen := steps[result.File]; !seen {
+			files = append(files, result.File)
+		}
+		steps[result.File] = append(steps[result.File], result.Step)
+	}
+
+	exists := make(map[string]bool)
+	if !cfg.DryRun {
+		if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+			return report.Summary{}, fmt.Errorf("create output directory: %w", err)
+		}
+		for _, file := range files {
+			written, err := writeFile(filepath.Join(cfg.OutputDir, file), cfg.Overwrite, steps[file])
+			if err != nil {
+				return report.Summary{}, fmt.Errorf("write output file: %w", err)
+			}
+			exists[file] = !written
+		}
+	}
+
+	summary := report.Summary{Title: "OpenAPI import summary"}
+	for _, result := range results {
+		entry := report.RequestResult{
+			SourcePath: result.SourcePath,
+			OutputPath: result.File,
+			Converted:  result.Converted,
+			Issues:     result.Issues,
+		}
+		if entry.Converted && exists[result.File] {
+			path := filepath.Join(cfg.OutputDir, result.File)
+			entry.Converted = false
+			entry.Issues = append(entry.Issues, report.Issue{
+				Code:     report.CodeOutputExists,
+				Stage:    diagnostics.StageFiles,
+				Severity: diagnostics.SeverityWarning,
+				Path:     path,
+				Message:  fmt.Sprintf("output file exists and --overwrite is false: %s", path),
+			})
+		}
+		summary.Add(entry)
+	}
+
+	return summary, nil
+}
+
+// writeFile writes steps to filename and reports false when the file exists
+// and overwrite is off.
+func writeFile(filename string, overwrite bool, steps []model.Step) (bool, error) {
+	if !overwrite {
+		if _, err := os.Stat(filename); err == nil {
+			return false, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("stat output file: %w", err)
+		}
+	}
+
+	payload, err := yaml.EncodeSteps(steps)
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(filename, payload, 0644); err != nil {
+		return false, fmt.Errorf("write file: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,108 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SourceLine returns the line of the source file holding the given 1-based
+// script line, or 0 when it is not known.
+func (s Script) SourceLine(line int) int {
+	if line < 1 || line > len(s.ExecLines) {
+		return 0
+	}
+	return s.ExecLines[line-1]
+}
+
+// locateScripts records the source line of every script line in collection,
+// so diagnostics can point into the collection file.
+func locateScripts(data []byte, collection *Collection) error {
+	newlines := make([]int, 0, bytes.Count(data, []byte("\n")))
+	for i, c := range data {
+		if c == '\n' {
+			newlines = append(newlines, i)
+		}
+	}
+
+	// Scripts are keyed by their event path, e.g. "item/0/event/1".
+	lines := make(map[string][]int)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	err := walkJSON(decoder, nil, func(path []string, offset int64) {
+		n := len(path)
+		if n < 5 || path[n-5] != "event" || path[n-3] != "script" || path[n-2] != "exec" {
+			return
+		}
+		key := strings.Join(path[:n-3], "/")
+		// A JSON string cannot span lines, so its end is on its line.
+		lines[key] = append(lines[key], sort.SearchInts(newlines, int(offset))+1)
+	})
+	if err != nil {
+		return err
+	}
+
+	assignEventLines(collection.Event, "", lines)
+	assignItemLines(collection.Item, "", lines)
+	return nil
+}
+
+func assignItemLines(items []Item, prefix string, lines map[string][]int) {
+	for i := range items {
+		path := prefix + "item/" + strconv.Itoa(i) + "/"
+		assignEventLines(items[i].Event, path, lines)
+		assignItemLines(items[i].Item, path, lines)
+	}
+}
+
+func assignEventLines(events []Event, prefix string, lines map[string][]int) {
+	for i := range events {
+		key := prefix + "event/" + strconv.Itoa(i)
+		if found, ok := lines[key]; ok && len(found) == len(events[i].Script.Exec) {
+			events[i].Script.ExecLines = found
+		}
+	}
+}
+
+// walkJSON reads one value and calls visit with the path and end offset of
+// every scalar in it.
+func walkJSON(decoder *json.Decoder, path []string, visit func(path []string, offset int64)) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		visit(path, decoder.InputOffset())
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return fmt.Errorf("unexpected object key %v", key)
+			}
+			if err := walkJSON(decoder, append(path, name), visit); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; decoder.More(); i++ {
+			if err := walkJSON(decoder, append(path, strconv.Itoa(i)), visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = decoder.Token()
+	return err
+}
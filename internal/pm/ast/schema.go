@@ -4,16 +4,45 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // ErrDecode indicates collection JSON decoding failures.
 var ErrDecode = fmt.Errorf("collection decode error")
 
-// Collection is the top-level collection export format.
+// Collection is the top-level collection export format. Auth applies to
+// every request that does not define its own.
 type Collection struct {
-	Info  Info    `json:"info"`
-	Event []Event `json:"event"`
-	Item  []Item  `json:"item"`
+	Info     Info            `json:"info"`
+	Event    []Event         `json:"event"`
+	Item     []Item          `json:"item"`
+	Variable []Variable      `json:"variable"`
+	Auth     json.RawMessage `json:"auth"`
+}
+
+// Variable is a collection-level variable definition. Type is "secret" for
+// values the source tool masks.
+type Variable struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled"`
+}
+
+// Environment is an exported environment: a named set of variables that
+// take precedence over collection variables.
+type Environment struct {
+	Name   string             `json:"name"`
+	Values []EnvironmentValue `json:"values"`
+}
+
+// EnvironmentValue is one environment variable. Enabled is nil when the
+// export leaves it out, which means enabled.
+type EnvironmentValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Enabled *bool  `json:"enabled"`
 }
 
 // Info carries collection metadata.
@@ -22,12 +51,24 @@ type Info struct {
 	Schema string `json:"schema"`
 }
 
-// Item is either a folder (with nested item) or a request item.
+// Item is either a folder (with nested item) or a request item. Auth is set
+// on folders and applies to the requests below them.
 type Item struct {
-	Name    string   `json:"name"`
-	Item    []Item   `json:"item"`
-	Request *Request `json:"request"`
-	Event   []Event  `json:"event"`
+	Name     string          `json:"name"`
+	Item     []Item          `json:"item"`
+	Request  *Request        `json:"request"`
+	Event    []Event         `json:"event"`
+	Response []Response      `json:"response"`
+	Auth     json.RawMessage `json:"auth"`
+}
+
+// Response is a saved example response attached to a request item.
+type Response struct {
+	Name   string   `json:"name"`
+	Status string   `json:"status"`
+	Code   int      `json:"code"`
+	Header []Header `json:"header"`
+	Body   string   `json:"body"`
 }
 
 // Request defines a source HTTP request.
@@ -40,6 +81,24 @@ type Request struct {
 	Auth      json.RawMessage `json:"auth"`
 }
 
+// HasAuth reports whether auth holds an auth definition. A missing, null or
+// inherit auth defers to the enclosing folder or collection.
+func HasAuth(auth json.RawMessage) bool {
+	raw := strings.TrimSpace(string(auth))
+	if raw == "" || raw == "null" || raw == "{}" {
+		return false
+	}
+
+	var decoded struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(auth, &decoded); err == nil && strings.EqualFold(strings.TrimSpace(decoded.Type), "inherit") {
+		return false
+	}
+
+	return true
+}
+
 // EffectiveURL merges all known URL representations.
 func (r Request) EffectiveURL() URLObject {
 	resolved := URLObject{
@@ -143,12 +202,46 @@ type Body struct {
 	File       *BodyFile `json:"file"`
 }
 
-// BodyKV is a key/value entry for form-like body payloads.
+// BodyKV is a key/value entry for form-like body payloads. File entries of
+// form-data bodies name their files in Src.
 type BodyKV struct {
-	Key      string `json:"key"`
-	Value    string `json:"value"`
-	Type     string `json:"type"`
-	Disabled bool   `json:"disabled"`
+	Key         string      `json:"key"`
+	Value       string      `json:"value"`
+	Type        string      `json:"type"`
+	Src         FileSources `json:"src"`
+	ContentType string      `json:"contentType"`
+	Disabled    bool        `json:"disabled"`
+}
+
+// FileSources supports both string and array src input forms.
+type FileSources []string
+
+func (s *FileSources) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || string(data) == "null" {
+		*s = nil
+		return nil
+	}
+
+	if data[0] == '"' {
+		var src string
+		if err := json.Unmarshal(data, &src); err != nil {
+			return fmt.Errorf("decode file src string: %w", err)
+		}
+		if src == "" {
+			*s = nil
+			return nil
+		}
+		*s = FileSources{src}
+		return nil
+	}
+
+	var sources []string
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return fmt.Errorf("decode file src list: %w", err)
+	}
+	*s = sources
+
+	return nil
 }
 
 // BodyFile defines file-mode body input metadata.
@@ -162,17 +255,36 @@ type Event struct {
 	Script Script `json:"script"`
 }
 
-// Script holds executable source lines.
+// Script holds executable source lines. ExecLines holds the line of the
+// collection file each Exec entry is on, when known.
 type Script struct {
-	Exec []string `json:"exec"`
+	Exec      []string `json:"exec"`
+	ExecLines []int    `json:"-"`
+}
+
+// ParseEnvironment reads environment JSON into the schema model.
+func ParseEnvironment(r io.Reader) (Environment, error) {
+	var environment Environment
+	if err := json.NewDecoder(r).Decode(&environment); err != nil {
+		return Environment{}, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+
+	return environment, nil
 }
 
 // Parse reads collection JSON into the schema model.
+// Script lines are located in the source so diagnostics can refer to them.
 func Parse(r io.Reader) (Collection, error) {
-	decoder := json.NewDecoder(r)
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Collection{}, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
 
 	var collection Collection
-	if err := decoder.Decode(&collection); err != nil {
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return Collection{}, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if err := locateScripts(data, &collection); err != nil {
 		return Collection{}, fmt.Errorf("%w: %v", ErrDecode, err)
 	}
 
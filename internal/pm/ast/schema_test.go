@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -103,6 +105,55 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseLocatesScriptLines(t *testing.T) {
+	t.Parallel()
+
+	input := `{
+  "event": [
+    {"listen": "test", "script": {"exec": ["pm.test('a');"]}}
+  ],
+  "item": [
+    {
+      "name": "Folder",
+      "item": [
+        {
+          "name": "Request",
+          "request": {"method": "GET", "url": "https://example.com"},
+          "event": [
+            {"listen": "prerequest", "script": {"exec": []}},
+            {
+              "listen": "test",
+              "script": {
+                "exec": [
+                  "const json = pm.response.json();",
+                  "pm.expect(json.ok).to.eql(true);"
+                ]
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+	collection, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := collection.Event[0].Script.ExecLines; !slices.Equal(got, []int{3}) {
+		t.Fatalf("collection script lines = %v, want [3]", got)
+	}
+	script := collection.Item[0].Item[0].Event[1].Script
+	if got := script.ExecLines; !slices.Equal(got, []int{18, 19}) {
+		t.Fatalf("request script lines = %v, want [18 19]", got)
+	}
+	if script.SourceLine(2) != 19 || script.SourceLine(3) != 0 {
+		t.Fatalf("SourceLine() = %d, %d", script.SourceLine(2), script.SourceLine(3))
+	}
+}
+
 func TestRequestEffectiveURLPortFallbackAndPrecedence(t *testing.T) {
 	t.Parallel()
 
@@ -147,3 +198,32 @@ func TestRequestEffectiveURLPortFallbackAndPrecedence(t *testing.T) {
 		}
 	})
 }
+
+func TestFileSourcesUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  FileSources
+	}{
+		{name: "string", input: `"a.png"`, want: FileSources{"a.png"}},
+		{name: "array", input: `["a.png", "b.png"]`, want: FileSources{"a.png", "b.png"}},
+		{name: "empty_string", input: `""`, want: nil},
+		{name: "null", input: `null`, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got FileSources
+			if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Unmarshal() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
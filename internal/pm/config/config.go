@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/jacoelho/rq/internal/pm/report"
@@ -16,16 +18,66 @@ var (
 	ErrHelp                = errors.New("help requested")
 	ErrMissingInput        = errors.New("--input is required")
 	ErrMissingOutput       = errors.New("--out is required")
-	ErrInvalidReportFormat = errors.New("--report must be one of: text, json")
+	ErrInvalidReportFormat = errors.New("--report must be one of: text, json, sarif")
+	ErrInvalidPattern      = errors.New("invalid path pattern")
+	ErrVerifyDryRun        = errors.New("--verify cannot be combined with --dry-run")
+	ErrInvalidFormat       = errors.New("--format must be one of: yaml, json")
+	ErrStdoutVerify        = errors.New("--stdout cannot be combined with --verify")
+	ErrStdoutExamples      = errors.New("--stdout cannot be combined with --examples")
+	ErrStdoutEnvironment   = errors.New("--stdout cannot be combined with --environment")
+	ErrSARIFRoot           = errors.New("--sarif-root requires --report sarif")
+	ErrInputOutsideRoot    = errors.New("--input must be inside the SARIF root")
+)
+
+// StepFormat selects how converted steps are written by --stdout.
+type StepFormat string
+
+const (
+	StepFormatYAML StepFormat = "yaml"
+	StepFormatJSON StepFormat = "json"
 )
 
 // Config defines CLI options for the collection migration command.
 type Config struct {
-	InputFile    string
-	OutputDir    string
-	Overwrite    bool
-	DryRun       bool
-	ReportFormat report.Format
+	InputFile string
+	OutputDir string
+	// EnvironmentFile is an exported environment whose variables are
+	// written, with the collection variables, to rq variable files.
+	EnvironmentFile string
+	Overwrite       bool
+	DryRun          bool
+	Examples        bool
+	Verify          bool
+	Stdout          bool
+	Format          StepFormat
+	Only            []string
+	Exclude         []string
+	ReportFormat    report.Format
+	// SourceURI is the input file relative to --sarif-root, or to the
+	// working directory, as SARIF results refer to it.
+	SourceURI string
+}
+
+// patternListFlag collects a repeatable path pattern flag.
+type patternListFlag []string
+
+func (f *patternListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *patternListFlag) Set(value string) error {
+	*f = append(*f, strings.Trim(strings.TrimSpace(value), "/"))
+	return nil
+}
+
+func validatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidPattern, pattern)
+		}
+	}
+
+	return nil
 }
 
 // Parse parses and validates CLI arguments.
@@ -42,7 +94,17 @@ func Parse(args []string) (*Config, error) {
 	out := fs.String("out", "", "Output directory for generated rq YAML files")
 	overwrite := fs.Bool("overwrite", false, "Overwrite existing output files")
 	dryRun := fs.Bool("dry-run", false, "Run conversion without writing files")
-	reportFormat := fs.String("report", "text", "Report format: text or json")
+	examples := fs.Bool("examples", false, "Emit saved example responses as golden files and asserts")
+	verify := fs.Bool("verify", false, "Run converted files against a server replaying saved examples")
+	stdout := fs.Bool("stdout", false, "Write converted steps to stdout instead of files")
+	format := fs.String("format", "yaml", "Step format for --stdout: yaml or json")
+	reportFormat := fs.String("report", "text", "Report format: text, json or sarif")
+	environment := fs.String("environment", "", "Environment JSON file to convert into rq variable files")
+	sarifRoot := fs.String("sarif-root", "", "Directory SARIF artifact paths are relative to (default: working directory)")
+	var only, exclude patternListFlag
+	fs.Var(&only, "only", "Only convert requests whose folder/request path matches (repeatable)")
+	fs.Var(&only, "include", "Alias for --only")
+	fs.Var(&exclude, "exclude", "Skip requests whose folder/request path matches (repeatable)")
 
 	if err := fs.Parse(args[1:]); err != nil {
 		if err == flag.ErrHelp {
@@ -54,7 +116,7 @@ func Parse(args []string) (*Config, error) {
 	if *input == "" {
 		return nil, ErrMissingInput
 	}
-	if *out == "" {
+	if *out == "" && !*stdout {
 		return nil, ErrMissingOutput
 	}
 
@@ -67,38 +129,130 @@ func Parse(args []string) (*Config, error) {
 		return nil, err
 	}
 
+	var sourceURI string
+	if parsedReportFormat == report.FormatSARIF {
+		sourceURI, err = relativeURI(*input, *sarifRoot)
+		if err != nil {
+			return nil, err
+		}
+	} else if *sarifRoot != "" {
+		return nil, ErrSARIFRoot
+	}
+
+	if err := validatePatterns(append(only, exclude...)); err != nil {
+		return nil, err
+	}
+
+	if *verify && *dryRun {
+		return nil, ErrVerifyDryRun
+	}
+
+	parsedFormat, err := parseStepFormat(*format)
+	if err != nil {
+		return nil, err
+	}
+
+	if *stdout && *verify {
+		return nil, ErrStdoutVerify
+	}
+	if *stdout && *examples {
+		return nil, ErrStdoutExamples
+	}
+	if *environment != "" {
+		if *stdout {
+			return nil, ErrStdoutEnvironment
+		}
+		if _, err := os.Stat(*environment); err != nil {
+			return nil, fmt.Errorf("environment file not accessible: %w", err)
+		}
+	}
+
 	return &Config{
-		InputFile:    *input,
-		OutputDir:    *out,
-		Overwrite:    *overwrite,
-		DryRun:       *dryRun,
-		ReportFormat: parsedReportFormat,
+		InputFile:       *input,
+		OutputDir:       *out,
+		EnvironmentFile: *environment,
+		Overwrite:       *overwrite,
+		DryRun:          *dryRun,
+		Examples:        *examples,
+		Verify:          *verify,
+		Stdout:          *stdout,
+		Format:          parsedFormat,
+		Only:            only,
+		Exclude:         exclude,
+		ReportFormat:    parsedReportFormat,
+		SourceURI:       sourceURI,
 	}, nil
 }
 
+// relativeURI returns filename relative to root, or to the working directory
+// when root is empty, with forward slashes.
+func relativeURI(filename string, root string) (string, error) {
+	if root == "" {
+		root = "."
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("resolve SARIF root: %w", err)
+	}
+	absFile, err := filepath.Abs(filename)
+	if err != nil {
+		return "", fmt.Errorf("resolve input file: %w", err)
+	}
+
+	relative, err := filepath.Rel(absRoot, absFile)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s is not below %s", ErrInputOutsideRoot, filename, absRoot)
+	}
+
+	return filepath.ToSlash(relative), nil
+}
+
 func parseReportFormat(input string) (report.Format, error) {
 	switch strings.ToLower(strings.TrimSpace(input)) {
 	case "", string(report.FormatText):
 		return report.FormatText, nil
 	case string(report.FormatJSON):
 		return report.FormatJSON, nil
+	case string(report.FormatSARIF):
+		return report.FormatSARIF, nil
 	default:
 		return "", fmt.Errorf("%w, got: %s", ErrInvalidReportFormat, input)
 	}
 }
 
+func parseStepFormat(input string) (StepFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "", string(StepFormatYAML):
+		return StepFormatYAML, nil
+	case string(StepFormatJSON):
+		return StepFormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w, got: %s", ErrInvalidFormat, input)
+	}
+}
+
 // Usage returns command usage text.
 func Usage() string {
 	return `pm2rq - migrate collection JSON into rq YAML files
 
 Usage:
-  pm2rq --input collection.json --out ./migrated [--overwrite] [--dry-run] [--report text|json]
+  pm2rq --input collection.json --out ./migrated [--overwrite] [--dry-run] [--examples] [--verify] [--environment FILE] [--include PATTERN] [--exclude PATTERN] [--report text|json|sarif] [--sarif-root DIR]
+  pm2rq --input collection.json --stdout [--format yaml|json] [--include PATTERN] [--exclude PATTERN]
 
 Options:
-  --input FILE      Path to source collection JSON file
-  --out DIR         Output directory for generated rq YAML files
-  --overwrite       Overwrite existing files
-  --dry-run         Run conversion without writing files
-  --report FORMAT   Report format: text or json (default: text)
-  -h, --help        Show this help message`
+  --input FILE       Path to source collection JSON file
+  --out DIR          Output directory for generated rq YAML files
+  --overwrite        Overwrite existing files
+  --dry-run          Run conversion without writing files
+  --examples         Emit saved example responses as golden files and asserts
+  --verify           Run converted files against a server replaying saved examples
+  --environment FILE Write the environment and collection variables to variables.yaml and secrets.yaml
+  --stdout           Write converted steps to stdout as one file; the report goes to stderr
+  --format FORMAT    Step format for --stdout: yaml or json (default: yaml)
+  --include PATTERN  Only convert requests whose folder/request path or request name matches (repeatable)
+  --only PATTERN     Alias for --include
+  --exclude PATTERN  Skip requests whose folder/request path or request name matches (repeatable)
+  --report FORMAT    Report format: text, json or sarif (default: text)
+  --sarif-root DIR   Directory SARIF artifact paths are relative to (default: working directory)
+  -h, --help         Show this help message`
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/jacoelho/rq/internal/pm/report"
@@ -18,7 +19,12 @@ func TestParse(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	cfg, err := Parse([]string{"pm2rq", "--input", input, "--out", filepath.Join(tempDir, "out"), "--report", "json", "--overwrite", "--dry-run"})
+	environment := filepath.Join(tempDir, "dev.postman_environment.json")
+	if err := os.WriteFile(environment, []byte(`{"values":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Parse([]string{"pm2rq", "--input", input, "--out", filepath.Join(tempDir, "out"), "--report", "json", "--overwrite", "--dry-run", "--examples", "--environment", environment, "--only", "Users/*", "--include", "/Orders/", "--exclude", "Users/Admin"})
 	if err != nil {
 		t.Fatalf("Parse() error = %v", err)
 	}
@@ -26,6 +32,9 @@ func TestParse(t *testing.T) {
 	if cfg.InputFile != input {
 		t.Fatalf("InputFile = %q", cfg.InputFile)
 	}
+	if cfg.EnvironmentFile != environment {
+		t.Fatalf("EnvironmentFile = %q", cfg.EnvironmentFile)
+	}
 	if cfg.ReportFormat != report.FormatJSON {
 		t.Fatalf("ReportFormat = %q", cfg.ReportFormat)
 	}
@@ -35,6 +44,33 @@ func TestParse(t *testing.T) {
 	if !cfg.DryRun {
 		t.Fatal("expected DryRun=true")
 	}
+	if !cfg.Examples {
+		t.Fatal("expected Examples=true")
+	}
+	if !reflect.DeepEqual(cfg.Only, []string{"Users/*", "Orders"}) {
+		t.Fatalf("Only = %#v", cfg.Only)
+	}
+	if !reflect.DeepEqual(cfg.Exclude, []string{"Users/Admin"}) {
+		t.Fatalf("Exclude = %#v", cfg.Exclude)
+	}
+}
+
+func TestParseStdout(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	input := filepath.Join(tempDir, "collection.json")
+	if err := os.WriteFile(input, []byte(`{"item":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Parse([]string{"pm2rq", "--input", input, "--stdout", "--format", "JSON"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !cfg.Stdout || cfg.Format != StepFormatJSON || cfg.OutputDir != "" {
+		t.Fatalf("cfg = %+v", cfg)
+	}
 }
 
 func TestParseErrors(t *testing.T) {
@@ -56,11 +92,62 @@ func TestParseErrors(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	cfg, err := Parse([]string{"pm2rq", "--input", input, "--out", "out", "--report", "SARIF", "--sarif-root", tempDir})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cfg.ReportFormat != report.FormatSARIF {
+		t.Fatalf("ReportFormat = %q", cfg.ReportFormat)
+	}
+	if cfg.SourceURI != "collection.json" {
+		t.Fatalf("SourceURI = %q", cfg.SourceURI)
+	}
+
+	_, err = Parse([]string{"pm2rq", "--input", input, "--out", "out", "--report", "sarif", "--sarif-root", filepath.Join(tempDir, "sub")})
+	if !errors.Is(err, ErrInputOutsideRoot) {
+		t.Fatalf("expected ErrInputOutsideRoot, got %v", err)
+	}
+
+	_, err = Parse([]string{"pm2rq", "--input", input, "--out", "out", "--sarif-root", tempDir})
+	if !errors.Is(err, ErrSARIFRoot) {
+		t.Fatalf("expected ErrSARIFRoot, got %v", err)
+	}
+
 	_, err = Parse([]string{"pm2rq", "--input", input, "--out", "out", "--report", "xml"})
 	if !errors.Is(err, ErrInvalidReportFormat) {
 		t.Fatalf("expected ErrInvalidReportFormat, got %v", err)
 	}
 
+	_, err = Parse([]string{"pm2rq", "--input", input, "--out", "out", "--only", "Users/["})
+	if !errors.Is(err, ErrInvalidPattern) {
+		t.Fatalf("expected ErrInvalidPattern, got %v", err)
+	}
+
+	_, err = Parse([]string{"pm2rq", "--input", input, "--stdout", "--format", "toml"})
+	if !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("expected ErrInvalidFormat, got %v", err)
+	}
+
+	_, err = Parse([]string{"pm2rq", "--input", input, "--stdout", "--verify"})
+	if !errors.Is(err, ErrStdoutVerify) {
+		t.Fatalf("expected ErrStdoutVerify, got %v", err)
+	}
+
+	_, err = Parse([]string{"pm2rq", "--input", input, "--stdout", "--examples"})
+	if !errors.Is(err, ErrStdoutExamples) {
+		t.Fatalf("expected ErrStdoutExamples, got %v", err)
+	}
+
+	_, err = Parse([]string{"pm2rq", "--input", input, "--stdout", "--environment", input})
+	if !errors.Is(err, ErrStdoutEnvironment) {
+		t.Fatalf("expected ErrStdoutEnvironment, got %v", err)
+	}
+
+	_, err = Parse([]string{"pm2rq", "--input", input, "--out", "out", "--environment", "missing.json"})
+	if err == nil {
+		t.Fatal("expected error for missing environment file")
+	}
+
 	_, err = Parse([]string{"pm2rq", "--help"})
 	if !errors.Is(err, ErrHelp) {
 		t.Fatalf("expected ErrHelp, got %v", err)
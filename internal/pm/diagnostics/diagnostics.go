@@ -14,6 +14,9 @@ const (
 	CodeQueryDuplicate                  Code = "query_duplicate_key"
 	CodeTemplatePlaceholderUnsupported  Code = "template_placeholder_unsupported"
 	CodeOutputExists                    Code = "output_exists"
+	CodeResponseNotRecorded             Code = "response_not_recorded"
+	CodeSuccessStatusMissing            Code = "success_status_missing"
+	CodePreRequestLineUnmapped          Code = "prerequest_line_unmapped"
 )
 
 // Stage identifies the migration pipeline stage where a diagnostic was raised.
@@ -93,6 +96,21 @@ var definitions = map[Code]Definition{
 		DefaultStage:    StageFiles,
 		DefaultSeverity: SeverityWarning,
 	},
+	CodeResponseNotRecorded: {
+		Code:            CodeResponseNotRecorded,
+		DefaultStage:    StageRequestMap,
+		DefaultSeverity: SeverityWarning,
+	},
+	CodeSuccessStatusMissing: {
+		Code:            CodeSuccessStatusMissing,
+		DefaultStage:    StageRequestMap,
+		DefaultSeverity: SeverityWarning,
+	},
+	CodePreRequestLineUnmapped: {
+		Code:            CodePreRequestLineUnmapped,
+		DefaultStage:    StageLower,
+		DefaultSeverity: SeverityWarning,
+	},
 }
 
 // DefinitionFor resolves canonical metadata for a diagnostic code.
@@ -108,10 +126,12 @@ func DefinitionFor(code Code) Definition {
 	}
 }
 
-// Span identifies a source range.
+// Span identifies a source range. Line and Column count within the script;
+// SourceLine is the line of the collection file the script line is on.
 type Span struct {
-	Line   int `json:"line,omitempty"`
-	Column int `json:"column,omitempty"`
+	Line       int `json:"line,omitempty"`
+	Column     int `json:"column,omitempty"`
+	SourceLine int `json:"source_line,omitempty"`
 }
 
 // Issue is a single migration diagnostic.
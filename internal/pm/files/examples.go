@@ -0,0 +1,45 @@
+package files
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/pm/normalize"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// golden is a saved example response body to be written next to a step file.
+type golden struct {
+	Filename string
+	Body     []byte
+}
+
+// applyExample turns the request's saved example into a status assert and a
+// golden body file.
+func applyExample(step *model.Step, node normalize.RequestNode, stepPath string) *golden {
+	example, ok := node.Example()
+	if !ok {
+		return nil
+	}
+
+	if len(step.Asserts.Status) == 0 {
+		step.Asserts.Status = append(step.Asserts.Status, model.StatusAssert{
+			Predicate: model.Predicate{Operation: "equals", Value: example.Code, HasValue: true},
+		})
+	}
+
+	if strings.TrimSpace(example.Body) == "" {
+		return nil
+	}
+
+	extension := ".golden.txt"
+	if json.Valid([]byte(example.Body)) {
+		extension = ".golden.json"
+	}
+	filename := strings.TrimSuffix(stepPath, filepath.Ext(stepPath)) + extension
+
+	step.Asserts.Golden = append(step.Asserts.Golden, model.GoldenAssert{File: filepath.Base(filename)})
+
+	return &golden{Filename: filename, Body: []byte(example.Body)}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jacoelho/rq/internal/pm/config"
@@ -66,3 +67,63 @@ func TestPipelineGeneratedYAMLExecutesInRQ(t *testing.T) {
 
 	assertGeneratedFileRunsInRQ(t, generatedFile)
 }
+
+func TestPipelineExamplesExecuteAsGoldenAsserts(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"rq","id":7}`))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	inputFile := filepath.Join(tempDir, "collection.json")
+	outputDir := filepath.Join(tempDir, "out")
+
+	content := `{
+  "item": [
+    {
+      "name": "Create user",
+      "request": {"method": "POST", "url": "` + server.URL + `/users"},
+      "response": [
+        {"name": "Conflict", "code": 409, "body": "{\"error\":\"exists\"}"},
+        {"name": "Created", "code": 201, "body": "{\n  \"id\": 7,\n  \"name\": \"rq\"\n}"}
+      ]
+    }
+  ]
+}`
+	if err := os.WriteFile(inputFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := Run(config.Config{
+		InputFile:    inputFile,
+		OutputDir:    outputDir,
+		Examples:     true,
+		ReportFormat: report.FormatJSON,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if summary.Converted != 1 || summary.HasErrors() {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	generatedFile := filepath.Join(outputDir, "create-user-post.yaml")
+	payload, err := os.ReadFile(generatedFile)
+	if err != nil {
+		t.Fatalf("expected generated file: %v", err)
+	}
+	for _, want := range []string{"value: 201", "file: create-user-post.golden.json"} {
+		if !strings.Contains(string(payload), want) {
+			t.Fatalf("generated file missing %q:\n%s", want, payload)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "create-user-post.golden.json")); err != nil {
+		t.Fatalf("expected golden file: %v", err)
+	}
+
+	assertGeneratedFileRunsInRQ(t, generatedFile)
+}
@@ -3,6 +3,7 @@ package files
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -23,6 +24,78 @@ var errOutputExists = errors.New("output file already exists")
 
 // Run executes the collection-to-rq migration.
 func Run(cfg config.Config) (report.Summary, error) {
+	if !cfg.DryRun {
+		if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+			return report.Summary{}, fmt.Errorf("create output directory: %w", err)
+		}
+	}
+
+	outputPath := func(relativePath string) string {
+		return filepath.Join(cfg.OutputDir, relativePath)
+	}
+
+	return convert(cfg, outputPath, func(entry *report.RequestResult, filename string, step model.Step, example *golden) error {
+		if cfg.DryRun {
+			return nil
+		}
+
+		err := writeOutputs(filename, cfg.Overwrite, step, example)
+		var exists *outputExistsError
+		if !errors.As(err, &exists) {
+			return err
+		}
+
+		entry.Converted = false
+		entry.Issues = append(entry.Issues, report.Issue{
+			Code:     report.CodeOutputExists,
+			Stage:    diagnostics.StageFiles,
+			Severity: diagnostics.SeverityWarning,
+			Path:     exists.Path,
+			Message:  fmt.Sprintf("output file exists and --overwrite is false: %s", exists.Path),
+		})
+		return nil
+	})
+}
+
+// Stream converts the collection like Run but writes every converted step to
+// w as a single rq file instead of one file per request. Body file paths are
+// rebased against the current directory.
+func Stream(cfg config.Config, w io.Writer) (report.Summary, error) {
+	var steps []model.Step
+	summary, err := convert(cfg, filepath.Base, func(_ *report.RequestResult, _ string, step model.Step, _ *golden) error {
+		steps = append(steps, step)
+		return nil
+	})
+	if err != nil {
+		return report.Summary{}, err
+	}
+	if len(steps) == 0 {
+		return summary, nil
+	}
+
+	encode := yaml.EncodeSteps
+	if cfg.Format == config.StepFormatJSON {
+		encode = yaml.EncodeStepsJSON
+	}
+	payload, err := encode(steps)
+	if err != nil {
+		return report.Summary{}, err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return report.Summary{}, fmt.Errorf("write steps: %w", err)
+	}
+
+	return summary, nil
+}
+
+// emitFunc receives each converted request that has no errors. It may mark
+// the entry as not converted and attach issues.
+type emitFunc func(entry *report.RequestResult, filename string, step model.Step, example *golden) error
+
+// convert maps every selected request and hands the result to emit.
+// outputPath turns the planned relative file name into the path body files
+// are rebased against.
+func convert(cfg config.Config, outputPath func(relativePath string) string, emit emitFunc) (report.Summary, error) {
 	file, err := os.Open(cfg.InputFile)
 	if err != nil {
 		return report.Summary{}, fmt.Errorf("open input file: %w", err)
@@ -34,15 +107,9 @@ func Run(cfg config.Config) (report.Summary, error) {
 		return report.Summary{}, fmt.Errorf("parse collection: %w", err)
 	}
 
-	nodes := normalize.Requests(collection)
+	nodes := normalize.Filter(normalize.Requests(collection), cfg.Only, cfg.Exclude)
 	planner := naming.NewPlanner()
-	var summary report.Summary
-
-	if !cfg.DryRun {
-		if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
-			return report.Summary{}, fmt.Errorf("create output directory: %w", err)
-		}
-	}
+	summary := report.Summary{Source: cfg.SourceURI}
 
 	for _, node := range nodes {
 		converted := requestmap.Request(node)
@@ -53,10 +120,18 @@ func Run(cfg config.Config) (report.Summary, error) {
 			methodForName = node.Request.Method
 		}
 		relativePath := planner.Next(node.FolderPath, node.Name, methodForName)
-		absolutePath := filepath.Join(cfg.OutputDir, relativePath)
+		absolutePath := outputPath(relativePath)
 
+		var example *golden
 		if converted.Converted {
 			converted.Step.BodyFile = pathing.RebaseBodyFilePath(converted.Step.BodyFile, cfg.InputFile, absolutePath)
+			for i := range converted.Step.Multipart {
+				part := &converted.Step.Multipart[i]
+				part.File = pathing.RebaseBodyFilePath(part.File, cfg.InputFile, absolutePath)
+			}
+			if cfg.Examples {
+				example = applyExample(&converted.Step, node, absolutePath)
+			}
 		}
 
 		entry := report.RequestResult{
@@ -66,20 +141,9 @@ func Run(cfg config.Config) (report.Summary, error) {
 			Issues:     append([]report.Issue(nil), issues...),
 		}
 
-		if entry.Converted && !cfg.DryRun {
-			if err := writeStepFile(absolutePath, cfg.Overwrite, converted.Step); err != nil {
-				if errors.Is(err, errOutputExists) {
-					entry.Converted = false
-					entry.Issues = append(entry.Issues, report.Issue{
-						Code:     report.CodeOutputExists,
-						Stage:    diagnostics.StageFiles,
-						Severity: diagnostics.SeverityWarning,
-						Path:     absolutePath,
-						Message:  fmt.Sprintf("output file exists and --overwrite is false: %s", absolutePath),
-					})
-				} else {
-					return report.Summary{}, fmt.Errorf("write output file: %w", err)
-				}
+		if entry.Converted {
+			if err := emit(&entry, absolutePath, converted.Step, example); err != nil {
+				return report.Summary{}, fmt.Errorf("write output file: %w", err)
 			}
 		}
 
@@ -105,12 +169,33 @@ func qualifyIssues(sourcePath string, issues []report.Issue) []report.Issue {
 	return qualified
 }
 
-func writeStepFile(filename string, overwrite bool, step model.Step) error {
+// outputExistsError reports which generated file already exists.
+type outputExistsError struct {
+	Path string
+}
+
+func (e *outputExistsError) Error() string {
+	return fmt.Sprintf("%v: %s", errOutputExists, e.Path)
+}
+
+func (e *outputExistsError) Unwrap() error {
+	return errOutputExists
+}
+
+// writeOutputs writes the step file and its optional golden file. Existing
+// files are checked up front so a request never leaves a partial pair behind.
+func writeOutputs(filename string, overwrite bool, step model.Step, example *golden) error {
 	if !overwrite {
-		if _, err := os.Stat(filename); err == nil {
-			return errOutputExists
-		} else if !errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("stat output file: %w", err)
+		filenames := []string{filename}
+		if example != nil {
+			filenames = append(filenames, example.Filename)
+		}
+		for _, name := range filenames {
+			if _, err := os.Stat(name); err == nil {
+				return &outputExistsError{Path: name}
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("stat output file: %w", err)
+			}
 		}
 	}
 
@@ -123,6 +208,12 @@ func writeStepFile(filename string, overwrite bool, step model.Step) error {
 		return err
 	}
 
+	if example != nil {
+		if err := os.WriteFile(example.Filename, example.Body, 0644); err != nil {
+			return fmt.Errorf("write golden file: %w", err)
+		}
+	}
+
 	if err := os.WriteFile(filename, payload, 0644); err != nil {
 		return fmt.Errorf("write file: %w", err)
 	}
@@ -0,0 +1,169 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/jacoelho/rq/internal/pm/ast"
+	"github.com/jacoelho/rq/internal/pm/config"
+	"github.com/jacoelho/rq/internal/pm/diagnostics"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/pm/template"
+)
+
+// Variable files written to the output directory.
+const (
+	VariablesFilename = "variables.yaml"
+	SecretsFilename   = "secrets.yaml"
+)
+
+// variableSet holds variable values by name, remembering which are secret.
+type variableSet struct {
+	values  map[string]string
+	secrets map[string]bool
+}
+
+func (s *variableSet) set(name string, value string, secret bool) {
+	s.values[name] = value
+	s.secrets[name] = secret
+}
+
+// WriteVariables converts the collection variables and the environment of
+// cfg into rq variable files. Environment values override collection values
+// with the same name. Secret values go to a separate file, meant for
+// --secret-file, so they can be kept out of version control.
+func WriteVariables(cfg config.Config) (*report.VariableFiles, error) {
+	collection, err := parseFile(cfg.InputFile, ast.Parse)
+	if err != nil {
+		return nil, fmt.Errorf("parse collection: %w", err)
+	}
+	environment, err := parseFile(cfg.EnvironmentFile, ast.ParseEnvironment)
+	if err != nil {
+		return nil, fmt.Errorf("parse environment: %w", err)
+	}
+
+	set := variableSet{values: make(map[string]string), secrets: make(map[string]bool)}
+	for _, variable := range collection.Variable {
+		if variable.Disabled || strings.TrimSpace(variable.Key) == "" {
+			continue
+		}
+		set.set(variable.Key, variable.Value, isSecretType(variable.Type))
+	}
+	for _, value := range environment.Values {
+		if (value.Enabled != nil && !*value.Enabled) || strings.TrimSpace(value.Key) == "" {
+			continue
+		}
+		set.set(value.Key, value.Value, isSecretType(value.Type))
+	}
+
+	result := &report.VariableFiles{}
+	variables := yaml.MapSlice{}
+	secrets := yaml.MapSlice{}
+	for _, name := range slices.Sorted(maps.Keys(set.values)) {
+		value := set.values[name]
+		if !template.IsVariableName(name) {
+			result.Issues = append(result.Issues, variableIssue(report.CodeTemplatePlaceholderUnsupported, fmt.Sprintf("variable %s cannot be referenced from rq templates and was left out", name)))
+			continue
+		}
+		if strings.Contains(value, "{{") {
+			result.Issues = append(result.Issues, variableIssue(report.CodeTemplatePlaceholderUnsupported, fmt.Sprintf("variable %s references other variables, which rq does not expand in variable files", name)))
+		}
+
+		item := yaml.MapItem{Key: name, Value: value}
+		if set.secrets[name] {
+			secrets = append(secrets, item)
+			continue
+		}
+		variables = append(variables, item)
+	}
+	result.Variables = len(variables)
+	result.Secrets = len(secrets)
+
+	if cfg.DryRun {
+		return result, nil
+	}
+
+	written, err := writeVariableFile(cfg, VariablesFilename, variables, 0644, result)
+	if err != nil {
+		return nil, err
+	}
+	if written {
+		result.VariablesFile = VariablesFilename
+	}
+
+	written, err = writeVariableFile(cfg, SecretsFilename, secrets, 0600, result)
+	if err != nil {
+		return nil, err
+	}
+	if written {
+		result.SecretsFile = SecretsFilename
+	}
+
+	return result, nil
+}
+
+// writeVariableFile writes values to name in the output directory. An empty
+// set writes nothing; an existing file is kept unless --overwrite is given.
+func writeVariableFile(cfg config.Config, name string, values yaml.MapSlice, perm os.FileMode, result *report.VariableFiles) (bool, error) {
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	filename := filepath.Join(cfg.OutputDir, name)
+	if !cfg.Overwrite {
+		if _, err := os.Stat(filename); err == nil {
+			issue := variableIssue(report.CodeOutputExists, fmt.Sprintf("output file exists and --overwrite is false: %s", filename))
+			issue.Stage = diagnostics.StageFiles
+			issue.Path = filename
+			result.Issues = append(result.Issues, issue)
+			return false, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("stat output file: %w", err)
+		}
+	}
+
+	payload, err := yaml.Marshal(values)
+	if err != nil {
+		return false, fmt.Errorf("encode %s: %w", name, err)
+	}
+	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
+		return false, fmt.Errorf("create output directory: %w", err)
+	}
+	if err := os.WriteFile(filename, payload, perm); err != nil {
+		return false, fmt.Errorf("write %s: %w", name, err)
+	}
+
+	return true, nil
+}
+
+func parseFile[T any](filename string, parse func(r io.Reader) (T, error)) (T, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer file.Close()
+
+	return parse(file)
+}
+
+func isSecretType(kind string) bool {
+	return strings.EqualFold(strings.TrimSpace(kind), "secret")
+}
+
+func variableIssue(code report.IssueCode, message string) report.Issue {
+	definition := diagnostics.DefinitionFor(code)
+	return report.Issue{
+		Code:     code,
+		Stage:    definition.DefaultStage,
+		Severity: definition.DefaultSeverity,
+		Message:  message,
+	}
+}
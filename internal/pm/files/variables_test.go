@@ -0,0 +1,141 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/jacoelho/rq/internal/pm/config"
+	"github.com/jacoelho/rq/internal/pm/report"
+)
+
+func TestWriteVariables(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	input := filepath.Join(tempDir, "collection.json")
+	environment := filepath.Join(tempDir, "dev.postman_environment.json")
+	outputDir := filepath.Join(tempDir, "out")
+
+	writeFile(t, input, `{
+  "variable": [
+    {"key": "baseUrl", "value": "https://api.example.com"},
+    {"key": "page_size", "value": "20"},
+    {"key": "unused", "value": "x", "disabled": true}
+  ],
+  "item": []
+}`)
+	writeFile(t, environment, `{
+  "name": "Dev",
+  "values": [
+    {"key": "baseUrl", "value": "https://dev.example.com", "enabled": true},
+    {"key": "token", "value": "s3cr3t", "type": "secret"},
+    {"key": "api.key", "value": "abc", "type": "secret"},
+    {"key": "base-url", "value": "https://dev.example.com"},
+    {"key": "login", "value": "{{user}}@example.com"},
+    {"key": "off", "value": "1", "enabled": false}
+  ]
+}`)
+
+	result, err := WriteVariables(config.Config{InputFile: input, EnvironmentFile: environment, OutputDir: outputDir})
+	if err != nil {
+		t.Fatalf("WriteVariables() error = %v", err)
+	}
+
+	if result.Variables != 3 || result.Secrets != 2 {
+		t.Fatalf("result = %+v, expected 3 variables and 2 secrets", result)
+	}
+	if result.VariablesFile != VariablesFilename || result.SecretsFile != SecretsFilename {
+		t.Fatalf("files = %q, %q", result.VariablesFile, result.SecretsFile)
+	}
+	if len(result.Issues) != 2 || report.HasErrors(result.Issues) {
+		t.Fatalf("issues = %+v, expected two warnings", result.Issues)
+	}
+
+	expectedVariables := map[string]string{
+		"baseUrl":   "https://dev.example.com",
+		"login":     "{{user}}@example.com",
+		"page_size": "20",
+	}
+	if got := readVariableFile(t, filepath.Join(outputDir, VariablesFilename)); !reflect.DeepEqual(got, expectedVariables) {
+		t.Fatalf("variables = %v, expected %v", got, expectedVariables)
+	}
+
+	expectedSecrets := map[string]string{"api.key": "abc", "token": "s3cr3t"}
+	secretsFile := filepath.Join(outputDir, SecretsFilename)
+	if got := readVariableFile(t, secretsFile); !reflect.DeepEqual(got, expectedSecrets) {
+		t.Fatalf("secrets = %v, expected %v", got, expectedSecrets)
+	}
+	info, err := os.Stat(secretsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Fatalf("secrets file mode = %o, expected 600", perm)
+	}
+}
+
+func TestWriteVariablesKeepsExistingFiles(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	input := filepath.Join(tempDir, "collection.json")
+	environment := filepath.Join(tempDir, "env.json")
+	writeFile(t, input, `{"variable": [{"key": "baseUrl", "value": "https://api.example.com"}], "item": []}`)
+	writeFile(t, environment, `{"values": []}`)
+	existing := filepath.Join(tempDir, VariablesFilename)
+	writeFile(t, existing, "baseUrl: keep\n")
+
+	cfg := config.Config{InputFile: input, EnvironmentFile: environment, OutputDir: tempDir}
+	result, err := WriteVariables(cfg)
+	if err != nil {
+		t.Fatalf("WriteVariables() error = %v", err)
+	}
+	if result.VariablesFile != "" || len(result.Issues) != 1 || result.Issues[0].Code != report.CodeOutputExists {
+		t.Fatalf("result = %+v, expected an output_exists issue", result)
+	}
+	if got := readVariableFile(t, existing); got["baseUrl"] != "keep" {
+		t.Fatalf("existing file was overwritten: %v", got)
+	}
+
+	cfg.Overwrite = true
+	if _, err := WriteVariables(cfg); err != nil {
+		t.Fatalf("WriteVariables() error = %v", err)
+	}
+	if got := readVariableFile(t, existing); got["baseUrl"] != "https://api.example.com" {
+		t.Fatalf("file was not overwritten: %v", got)
+	}
+
+	cfg.DryRun = true
+	result, err = WriteVariables(cfg)
+	if err != nil {
+		t.Fatalf("WriteVariables() error = %v", err)
+	}
+	if result.Variables != 1 || result.VariablesFile != "" {
+		t.Fatalf("dry run result = %+v", result)
+	}
+}
+
+func writeFile(t *testing.T, filename string, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readVariableFile(t *testing.T, filename string) map[string]string {
+	t.Helper()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values map[string]string
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		t.Fatalf("parse %s: %v", filename, err)
+	}
+	return values
+}
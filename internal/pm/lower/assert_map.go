@@ -45,6 +45,43 @@ func addJSONPathAssert(asserts *model.Asserts, seen map[string]struct{}, path st
 	asserts.JSONPath = append(asserts.JSONPath, assert)
 }
 
+func addHeaderAssert(asserts *model.Asserts, seen map[string]struct{}, name string, op string, value any, hasValue bool) {
+	key := assertKey("header:"+strings.ToLower(name), op, value, hasValue)
+	if _, exists := seen[key]; exists {
+		return
+	}
+	seen[key] = struct{}{}
+
+	assert := model.HeaderAssert{
+		Name: name,
+		Predicate: model.Predicate{
+			Operation: op,
+			HasValue:  hasValue,
+		},
+	}
+	if hasValue {
+		assert.Predicate.Value = value
+	}
+
+	asserts.Headers = append(asserts.Headers, assert)
+}
+
+func addDurationAssert(asserts *model.Asserts, seen map[string]struct{}, op string, millis int64) {
+	key := assertKey("duration", op, millis, true)
+	if _, exists := seen[key]; exists {
+		return
+	}
+	seen[key] = struct{}{}
+
+	asserts.Duration = append(asserts.Duration, model.DurationAssert{
+		Predicate: model.Predicate{
+			Operation: op,
+			Value:     millis,
+			HasValue:  true,
+		},
+	})
+}
+
 func assertKey(path string, op string, value any, hasValue bool) string {
 	if !hasValue {
 		return fmt.Sprintf("%s|%s", path, op)
@@ -97,6 +134,31 @@ func mapArrayTypeAssertion(asserts *model.Asserts, seen map[string]struct{}, lin
 	return true, true
 }
 
+func mapResponseTimeComparison(asserts *model.Asserts, seen map[string]struct{}, line string) bool {
+	expression := extractTestExpression(line)
+	if expression == "" {
+		return false
+	}
+
+	matches := responseTimeComparisonPattern.FindStringSubmatch(expression)
+	if len(matches) != 3 {
+		return false
+	}
+	millis, ok := parseMilliseconds(matches[2])
+	if !ok {
+		return false
+	}
+
+	ops := map[string]string{
+		"<":  "less_than",
+		"<=": "less_than_or_equal",
+		">":  "greater_than",
+		">=": "greater_than_or_equal",
+	}
+	addDurationAssert(asserts, seen, ops[matches[1]], millis)
+	return true
+}
+
 func extractTestExpression(line string) string {
 	matches := testExpressionPattern.FindStringSubmatch(strings.TrimSpace(line))
 	if len(matches) != 2 {
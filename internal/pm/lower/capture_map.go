@@ -26,7 +26,10 @@ func issueResult(issueCode report.IssueCode) mapResult {
 	}
 }
 
-func mapEnvironmentCapture(captures *model.Captures, line string) mapResult {
+// mapEnvironmentCapture maps environment, global and collection variable
+// assignments to captures. rq has a single variable scope, so the last
+// assignment of a name wins whichever scope it targets.
+func mapEnvironmentCapture(captures *model.Captures, line string, roots map[string]struct{}) mapResult {
 	matches := setEnvironmentPattern.FindStringSubmatch(line)
 	if len(matches) != 3 {
 		return mapResult{}
@@ -51,7 +54,7 @@ func mapEnvironmentCapture(captures *model.Captures, line string) mapResult {
 		return mappedResult(false)
 	}
 
-	path, ok := jsonExprToPath(valueExpr)
+	path, ok := jsonValuePath(valueExpr, roots)
 	if !ok {
 		return issueResult(report.CodeScriptJSONPathTranslationFailed)
 	}
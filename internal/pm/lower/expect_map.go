@@ -0,0 +1,381 @@
+package lower
+
+import (
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// expectation is a parsed pm.expect(subject).to.<assertion>(args) statement.
+type expectation struct {
+	subject   string
+	assertion string
+	args      []string
+	negated   bool
+}
+
+// expectChainWords are chai language chains that do not change the meaning
+// of the assertion that follows them.
+var expectChainWords = map[string]struct{}{
+	"and": {}, "at": {}, "be": {}, "been": {}, "deep": {}, "does": {}, "has": {},
+	"have": {}, "is": {}, "of": {}, "same": {}, "still": {}, "that": {}, "to": {},
+	"which": {}, "with": {},
+}
+
+// mapExpectAssertion maps pm.expect statements on the JSON body or on a
+// response header, such as those found in pm.test callbacks. roots holds the
+// variable names bound to the parsed body.
+func mapExpectAssertion(asserts *model.Asserts, seen map[string]struct{}, line string, roots map[string]struct{}) (bool, bool) {
+	parsed, ok := parseExpectation(line)
+	if !ok {
+		return false, false
+	}
+
+	if isResponseTimeExpression(parsed.subject) {
+		return mapResponseTimeExpectation(asserts, seen, parsed), false
+	}
+	if strings.TrimSpace(parsed.subject) == "pm.response" {
+		return mapResponseHeaderExpectation(asserts, seen, parsed), false
+	}
+	if name := parseHeaderExpression(parsed.subject); name != "" {
+		return mapHeaderValueExpectation(asserts, seen, name, parsed), false
+	}
+
+	path, ok := jsonValuePath(parsed.subject, roots)
+	if !ok {
+		return false, false
+	}
+
+	switch parsed.assertion {
+	case "eql", "equal", "equals", "eq":
+		if len(parsed.args) != 1 {
+			return false, false
+		}
+		value, ok := parseLiteral(parsed.args[0])
+		if !ok {
+			return false, false
+		}
+		addJSONPathAssert(asserts, seen, path, equalityOp(parsed.negated), value, true)
+		return true, true
+	case "include", "includes", "contain", "contains":
+		if len(parsed.args) != 1 {
+			return false, false
+		}
+		// rq compares strings only; chai membership checks on arrays and
+		// objects have no single predicate equivalent.
+		value, ok := parseQuoted(parsed.args[0])
+		if !ok {
+			return false, false
+		}
+		op := "contains"
+		if parsed.negated {
+			op = "not_contains"
+		}
+		addJSONPathAssert(asserts, seen, path, op, value, true)
+		return true, true
+	case "property":
+		if len(parsed.args) == 0 || len(parsed.args) > 2 {
+			return false, false
+		}
+		key, ok := parseQuoted(parsed.args[0])
+		if !ok || key == "" {
+			return false, false
+		}
+		path = appendJSONPathSegment(path, key)
+		if len(parsed.args) == 1 {
+			if parsed.negated {
+				return false, false
+			}
+			addJSONPathAssert(asserts, seen, path, "exists", nil, false)
+			return true, true
+		}
+		value, ok := parseLiteral(parsed.args[1])
+		if !ok {
+			return false, false
+		}
+		addJSONPathAssert(asserts, seen, path, equalityOp(parsed.negated), value, true)
+		return true, true
+	}
+
+	return false, false
+}
+
+// mapResponseHeaderExpectation maps pm.response.to.have.header(name) and
+// its (name, value) form.
+func mapResponseHeaderExpectation(asserts *model.Asserts, seen map[string]struct{}, parsed expectation) bool {
+	if parsed.assertion != "header" || len(parsed.args) == 0 || len(parsed.args) > 2 {
+		return false
+	}
+	name, ok := parseQuoted(parsed.args[0])
+	if !ok || strings.TrimSpace(name) == "" {
+		return false
+	}
+
+	if len(parsed.args) == 1 {
+		if parsed.negated {
+			return false
+		}
+		addHeaderAssert(asserts, seen, name, "exists", nil, false)
+		return true
+	}
+
+	value, ok := parseQuoted(parsed.args[1])
+	if !ok {
+		return false
+	}
+	addHeaderAssert(asserts, seen, name, equalityOp(parsed.negated), value, true)
+	return true
+}
+
+// mapHeaderValueExpectation maps expectations on a header value read with
+// pm.response.headers.get(name).
+func mapHeaderValueExpectation(asserts *model.Asserts, seen map[string]struct{}, name string, parsed expectation) bool {
+	switch parsed.assertion {
+	case "exist":
+		if parsed.negated || len(parsed.args) != 0 {
+			return false
+		}
+		addHeaderAssert(asserts, seen, name, "exists", nil, false)
+		return true
+	case "eql", "equal", "equals", "eq":
+		if len(parsed.args) != 1 {
+			return false
+		}
+		value, ok := parseQuoted(parsed.args[0])
+		if !ok {
+			return false
+		}
+		addHeaderAssert(asserts, seen, name, equalityOp(parsed.negated), value, true)
+		return true
+	case "include", "includes", "contain", "contains":
+		if len(parsed.args) != 1 {
+			return false
+		}
+		value, ok := parseQuoted(parsed.args[0])
+		if !ok {
+			return false
+		}
+		op := "contains"
+		if parsed.negated {
+			op = "not_contains"
+		}
+		addHeaderAssert(asserts, seen, name, op, value, true)
+		return true
+	}
+
+	return false
+}
+
+// responseTimeOps maps chai comparisons to predicate operators, plain and
+// negated.
+var responseTimeOps = map[string][2]string{
+	"below":       {"less_than", "greater_than_or_equal"},
+	"lt":          {"less_than", "greater_than_or_equal"},
+	"lessThan":    {"less_than", "greater_than_or_equal"},
+	"most":        {"less_than_or_equal", "greater_than"},
+	"lte":         {"less_than_or_equal", "greater_than"},
+	"above":       {"greater_than", "less_than_or_equal"},
+	"gt":          {"greater_than", "less_than_or_equal"},
+	"greaterThan": {"greater_than", "less_than_or_equal"},
+	"least":       {"greater_than_or_equal", "less_than"},
+	"gte":         {"greater_than_or_equal", "less_than"},
+}
+
+// mapResponseTimeExpectation maps comparisons of pm.response.responseTime,
+// in milliseconds, to duration asserts.
+func mapResponseTimeExpectation(asserts *model.Asserts, seen map[string]struct{}, parsed expectation) bool {
+	ops, ok := responseTimeOps[parsed.assertion]
+	if !ok || len(parsed.args) != 1 {
+		return false
+	}
+	millis, ok := parseMilliseconds(parsed.args[0])
+	if !ok {
+		return false
+	}
+
+	op := ops[0]
+	if parsed.negated {
+		op = ops[1]
+	}
+	addDurationAssert(asserts, seen, op, millis)
+	return true
+}
+
+func equalityOp(negated bool) string {
+	if negated {
+		return "not_equals"
+	}
+	return "equals"
+}
+
+// jsonValuePath converts an expression to a JSONPath when it reads from the
+// parsed body, either through pm.response.json() or through a variable in
+// roots.
+func jsonValuePath(subject string, roots map[string]struct{}) (string, bool) {
+	subject = strings.TrimSpace(subject)
+	if rest, ok := strings.CutPrefix(subject, "pm.response.json()"); ok {
+		return jsonExprToPath("json" + rest)
+	}
+
+	name, consumed := consumeIdentifier(subject)
+	if consumed == 0 {
+		return "", false
+	}
+	if _, ok := roots[name]; !ok {
+		return "", false
+	}
+
+	return jsonExprToPath("json" + subject[consumed:])
+}
+
+// parseExpectation splits a pm.expect statement into its subject, its
+// assertion and the assertion arguments.
+func parseExpectation(line string) (expectation, bool) {
+	const prefix = "pm.expect("
+
+	line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), ";"))
+	// pm.response.to.have.header(...) reads as pm.expect(pm.response).to...
+	if rest, ok := strings.CutPrefix(line, "pm.response.to."); ok {
+		line = "pm.expect(pm.response).to." + rest
+	}
+	if !strings.HasPrefix(line, prefix) {
+		return expectation{}, false
+	}
+
+	closing := closingParen(line, len(prefix)-1)
+	if closing < 0 {
+		return expectation{}, false
+	}
+
+	parsed := expectation{subject: strings.TrimSpace(line[len(prefix):closing])}
+	if parsed.subject == "" {
+		return expectation{}, false
+	}
+
+	rest := line[closing+1:]
+	for rest != "" {
+		if parsed.assertion != "" || rest[0] != '.' {
+			return expectation{}, false
+		}
+
+		word, consumed := consumeIdentifier(rest[1:])
+		if consumed == 0 {
+			return expectation{}, false
+		}
+		rest = rest[1+consumed:]
+
+		if strings.HasPrefix(rest, "(") {
+			end := closingParen(rest, 0)
+			if end < 0 {
+				return expectation{}, false
+			}
+			parsed.assertion = word
+			parsed.args = splitTopLevel(rest[1:end], ',')
+			rest = rest[end+1:]
+			continue
+		}
+
+		if word == "not" {
+			parsed.negated = !parsed.negated
+			continue
+		}
+		if _, ok := expectChainWords[word]; ok {
+			continue
+		}
+		parsed.assertion = word
+	}
+
+	if parsed.assertion == "" {
+		return expectation{}, false
+	}
+
+	return parsed, true
+}
+
+// inlineTestBody returns the statements of a pm.test callback written on a
+// single line, such as pm.test("ok", () => { pm.expect(json.ok).to.eql(true); });
+func inlineTestBody(line string) []string {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "pm.test(") {
+		return nil
+	}
+
+	open := strings.IndexByte(line, '{')
+	end := strings.LastIndexByte(line, '}')
+	if open < 0 || end <= open {
+		return nil
+	}
+
+	var statements []string
+	for _, statement := range splitTopLevel(line[open+1:end], ';') {
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements
+}
+
+// closingParen returns the index of the parenthesis closing the one at open,
+// skipping quoted text, or -1.
+func closingParen(input string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// splitTopLevel splits input on separators outside quotes and brackets, such
+// as the commas of an argument list or the semicolons of a block.
+func splitTopLevel(input string, separator byte) []string {
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+
+	var parts []string
+	depth := 0
+	start := 0
+	var quote byte
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == separator && depth == 0:
+			parts = append(parts, strings.TrimSpace(input[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(parts, strings.TrimSpace(input[start:]))
+}
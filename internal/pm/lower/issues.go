@@ -8,7 +8,7 @@ import (
 	"github.com/jacoelho/rq/internal/pm/report"
 )
 
-func buildUnmappedIssues(counts map[report.IssueCode]int, firstLine map[report.IssueCode]int, total int) []report.Issue {
+func buildUnmappedIssues(counts map[report.IssueCode]int, firstLine map[report.IssueCode]diagnostics.Span, total int) []report.Issue {
 	if total == 0 {
 		return nil
 	}
@@ -20,6 +20,7 @@ func buildUnmappedIssues(counts map[report.IssueCode]int, firstLine map[report.I
 	sort.Strings(codes)
 
 	issues := make([]report.Issue, 0, len(codes)+1)
+	var first *diagnostics.Span
 	for _, code := range codes {
 		issueCode := report.IssueCode(code)
 		issue := report.Issue{
@@ -28,8 +29,11 @@ func buildUnmappedIssues(counts map[report.IssueCode]int, firstLine map[report.I
 			Severity: diagnostics.SeverityError,
 			Message:  fmt.Sprintf("%d script lines were not mapped (%s)", counts[issueCode], issueCode),
 		}
-		if line := firstLine[issueCode]; line > 0 {
-			issue.Span = &diagnostics.Span{Line: line}
+		if span, ok := firstLine[issueCode]; ok {
+			issue.Span = &span
+			if first == nil || span.SourceLine < first.SourceLine || span.SourceLine == first.SourceLine && span.Line < first.Line {
+				first = &span
+			}
 		}
 		issues = append(issues, issue)
 	}
@@ -39,6 +43,7 @@ func buildUnmappedIssues(counts map[report.IssueCode]int, firstLine map[report.I
 		Stage:    diagnostics.StageLower,
 		Severity: diagnostics.SeverityError,
 		Message:  fmt.Sprintf("%d test script lines were not mapped", total),
+		Span:     first,
 	})
 
 	return issues
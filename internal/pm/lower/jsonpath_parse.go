@@ -332,6 +332,20 @@ func isStatusExpression(input string) bool {
 	return trimmed == "responseCode.code" || trimmed == "pm.response.code"
 }
 
+func isResponseTimeExpression(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	return trimmed == "responseTime" || trimmed == "pm.response.responseTime"
+}
+
+// parseMilliseconds parses a whole, non-negative number of milliseconds.
+func parseMilliseconds(input string) (int64, bool) {
+	value, err := strconv.ParseInt(strings.TrimSpace(input), 10, 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+	return value, true
+}
+
 func parseHeaderExpression(input string) string {
 	trimmed := strings.TrimSpace(input)
 	if matches := headerCapturePattern.FindStringSubmatch(trimmed); len(matches) == 2 {
@@ -348,6 +362,16 @@ func isJSONParseLine(line string) bool {
 	return strings.HasPrefix(line, "var json = JSON.parse(responseBody)") || strings.HasPrefix(line, "let json = JSON.parse(responseBody)") || strings.HasPrefix(line, "const json = JSON.parse(responseBody)")
 }
 
+// parseJSONDeclaration returns the variable a statement binds the parsed body
+// to, as in var data = pm.response.json().
+func parseJSONDeclaration(line string) (string, bool) {
+	matches := jsonDeclarationPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if len(matches) != 2 {
+		return "", false
+	}
+	return matches[1], true
+}
+
 func isJSONValidityLine(line string) bool {
 	lower := strings.ToLower(strings.TrimSpace(line))
 	return strings.Contains(lower, "response is valid json") && strings.Contains(lower, "= true")
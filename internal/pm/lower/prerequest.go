@@ -0,0 +1,143 @@
+package lower
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/pm/ast"
+	"github.com/jacoelho/rq/internal/pm/diagnostics"
+	"github.com/jacoelho/rq/internal/pm/lex"
+	"github.com/jacoelho/rq/internal/pm/parse"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+var (
+	dateNowPattern     = regexp.MustCompile(`^(?:Date\.now\(\s*\)|new Date\(\s*\)\.getTime\(\s*\)|\+\s*new Date\(\s*\))$`)
+	unixSecondsPattern = regexp.MustCompile(`^(?:Math\.(?:floor|round|trunc)\(\s*(?:Date\.now\(\s*\)|new Date\(\s*\)\.getTime\(\s*\))\s*/\s*1000\s*\))$`)
+	isoDatePattern     = regexp.MustCompile(`^new Date\(\s*\)\.toISOString\(\s*\)$`)
+)
+
+// PreRequestResult contains the variables set by pre-request scripts and
+// diagnostics for the lines that could not be mapped.
+type PreRequestResult struct {
+	// Variables holds one value per variable name, in first assignment
+	// order. Values are source template text: literals as written and time
+	// expressions as the matching dynamic variable, such as {{$timestamp}}.
+	Variables     model.KeyValues
+	Issues        []report.Issue
+	MappedLines   int
+	IgnoredLines  int
+	UnmappedLines int
+}
+
+// TranslatePreRequest maps pre-request scripts that set variables from
+// literals or the current time. Like captures, the last assignment of a name
+// wins whichever scope it targets. Assignments inside conditionals are not
+// mapped, since the condition cannot be evaluated ahead of the run.
+func TranslatePreRequest(events []ast.Event) PreRequestResult {
+	result := PreRequestResult{}
+	var firstUnmapped diagnostics.Span
+	conditionDepth := 0
+
+	for _, event := range events {
+		if strings.ToLower(strings.TrimSpace(event.Listen)) != "prerequest" {
+			continue
+		}
+
+		program := parse.Script(lex.Script(event.Script.Exec))
+		for _, statement := range program.Statements {
+			switch statement.Kind {
+			case parse.StatementEmpty:
+				result.IgnoredLines++
+				continue
+			case parse.StatementControlIf:
+				conditionDepth++
+			case parse.StatementControlClose:
+				if conditionDepth > 0 {
+					conditionDepth--
+				}
+				result.IgnoredLines++
+				continue
+			case parse.StatementCode:
+				name, value, ok := parsePreRequestAssignment(statement.Text)
+				if ok && conditionDepth == 0 {
+					setVariable(&result.Variables, name, value)
+					result.MappedLines++
+					continue
+				}
+			}
+
+			result.UnmappedLines++
+			if firstUnmapped.Line == 0 {
+				firstUnmapped = diagnostics.Span{Line: statement.Line, SourceLine: event.Script.SourceLine(statement.Line)}
+			}
+		}
+	}
+
+	if result.UnmappedLines > 0 {
+		issue := report.Issue{
+			Code:     report.CodePreRequestLineUnmapped,
+			Stage:    diagnostics.StageLower,
+			Severity: diagnostics.SeverityWarning,
+			Message:  fmt.Sprintf("%d pre-request script lines were not mapped", result.UnmappedLines),
+		}
+		if firstUnmapped.Line > 0 {
+			issue.Span = &firstUnmapped
+		}
+		result.Issues = append(result.Issues, issue)
+	}
+
+	return result
+}
+
+func parsePreRequestAssignment(line string) (string, string, bool) {
+	matches := setEnvironmentPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if len(matches) != 3 {
+		return "", "", false
+	}
+
+	name := strings.TrimSpace(matches[1])
+	if name == "" {
+		return "", "", false
+	}
+
+	value, ok := preRequestValue(strings.TrimSpace(matches[2]))
+	if !ok {
+		return "", "", false
+	}
+
+	return name, value, true
+}
+
+// preRequestValue returns the source template text of a literal or a
+// supported time expression. Date.now() is in milliseconds, so it becomes the
+// Unix seconds followed by 000.
+func preRequestValue(expr string) (string, bool) {
+	switch {
+	case dateNowPattern.MatchString(expr):
+		return "{{$timestamp}}000", true
+	case unixSecondsPattern.MatchString(expr):
+		return "{{$timestamp}}", true
+	case isoDatePattern.MatchString(expr):
+		return "{{$isoTimestamp}}", true
+	}
+
+	value, ok := parseLiteral(expr)
+	if !ok || value == nil {
+		return "", false
+	}
+
+	return fmt.Sprint(value), true
+}
+
+func setVariable(variables *model.KeyValues, name, value string) {
+	for i := range *variables {
+		if (*variables)[i].Key == name {
+			(*variables)[i].Value = value
+			return
+		}
+	}
+	*variables = append(*variables, model.KeyValue{Key: name, Value: value})
+}
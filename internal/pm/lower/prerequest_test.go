@@ -0,0 +1,95 @@
+package lower
+
+import (
+	"testing"
+
+	"github.com/jacoelho/rq/internal/pm/ast"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestTranslatePreRequestMapsAssignments(t *testing.T) {
+	t.Parallel()
+
+	events := []ast.Event{
+		{
+			Listen: "prerequest",
+			Script: ast.Script{Exec: []string{
+				`pm.environment.set("name", "rex");`,
+				`pm.variables.set('count', 3);`,
+				`pm.globals.set("ts", Date.now());`,
+				`pm.collectionVariables.set("seconds", Math.floor(Date.now() / 1000));`,
+				`postman.setEnvironmentVariable("at", new Date().toISOString());`,
+				``,
+				`pm.environment.set("name", "fido");`,
+			}},
+		},
+		{
+			Listen: "test",
+			Script: ast.Script{Exec: []string{`pm.environment.set("ignored", "x");`}},
+		},
+	}
+
+	result := TranslatePreRequest(events)
+
+	expected := model.KeyValues{
+		{Key: "name", Value: "fido"},
+		{Key: "count", Value: "3"},
+		{Key: "ts", Value: "{{$timestamp}}000"},
+		{Key: "seconds", Value: "{{$timestamp}}"},
+		{Key: "at", Value: "{{$isoTimestamp}}"},
+	}
+	if len(result.Variables) != len(expected) {
+		t.Fatalf("Variables = %+v, expected %+v", result.Variables, expected)
+	}
+	for i := range expected {
+		if result.Variables[i] != expected[i] {
+			t.Fatalf("Variables[%d] = %+v, expected %+v", i, result.Variables[i], expected[i])
+		}
+	}
+	if result.MappedLines != 6 || result.IgnoredLines != 1 || result.UnmappedLines != 0 {
+		t.Fatalf("mapped/ignored/unmapped = %d/%d/%d", result.MappedLines, result.IgnoredLines, result.UnmappedLines)
+	}
+	if len(result.Issues) != 0 {
+		t.Fatalf("Issues = %+v, expected none", result.Issues)
+	}
+}
+
+func TestTranslatePreRequestReportsUnmappedLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		exec []string
+	}{
+		{name: "computed value", exec: []string{`pm.environment.set("sig", CryptoJS.HmacSHA256("a", "b").toString());`}},
+		{name: "null literal", exec: []string{`pm.environment.set("x", null);`}},
+		{name: "local variable", exec: []string{`const id = 1;`}},
+		{name: "conditional assignment", exec: []string{`if (pm.environment.get("x") === undefined) {`, `pm.environment.set("x", "a");`, `}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := TranslatePreRequest([]ast.Event{{
+				Listen: "prerequest",
+				Script: ast.Script{Exec: tt.exec},
+			}})
+
+			if len(result.Variables) != 0 {
+				t.Fatalf("unexpected variables: %+v", result.Variables)
+			}
+			issue := findIssue(result.Issues, report.CodePreRequestLineUnmapped)
+			if issue == nil {
+				t.Fatalf("missing %s issue: %+v", report.CodePreRequestLineUnmapped, result.Issues)
+			}
+			if report.HasErrors(result.Issues) {
+				t.Fatalf("unmapped pre-request lines must be warnings: %+v", result.Issues)
+			}
+			if issue.Span == nil || issue.Span.Line != 1 {
+				t.Fatalf("issue span = %+v, expected line 1", issue.Span)
+			}
+		})
+	}
+}
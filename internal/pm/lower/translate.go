@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/jacoelho/rq/internal/pm/ast"
+	"github.com/jacoelho/rq/internal/pm/diagnostics"
 	"github.com/jacoelho/rq/internal/pm/lex"
 	"github.com/jacoelho/rq/internal/pm/parse"
 	"github.com/jacoelho/rq/internal/pm/report"
@@ -28,9 +29,13 @@ var (
 
 	jsonComparisonPattern = regexp.MustCompile(`^(json(?:\.[A-Za-z_][A-Za-z0-9_]*|\[[^\]]+\])*)\s*(===|!==)\s*(.+?)\s*;?$`)
 
+	jsonDeclarationPattern = regexp.MustCompile(`^(?:var|let|const)\s+([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(?:pm\.response\.json\(\s*\)|JSON\.parse\(\s*responseBody\s*\))\s*;?$`)
+
 	arrayIsArrayPattern = regexp.MustCompile(`^Array\.isArray\(\s*(json(?:\.[A-Za-z_][A-Za-z0-9_]*|\[[^\]]+\])*)\s*\)$`)
 
-	setEnvironmentPattern = regexp.MustCompile(`^(?:postman\.setEnvironmentVariable|pm\.environment\.set)\(\s*['"]([^'"]+)['"]\s*,\s*(.+?)\s*\)\s*;?$`)
+	responseTimeComparisonPattern = regexp.MustCompile(`^(?:pm\.response\.)?responseTime\s*(<=|>=|<|>)\s*(\d+)$`)
+
+	setEnvironmentPattern = regexp.MustCompile(`^(?:postman\.set(?:Environment|Global)Variable|pm\.(?:environment|globals|collectionVariables|variables)\.set)\(\s*['"]([^'"]+)['"]\s*,\s*(.+?)\s*\)\s*;?$`)
 
 	headerCapturePattern = regexp.MustCompile(`^responseHeaders\[['"]([^'"]+)['"]\]$`)
 	pmHeaderCaptureRegex = regexp.MustCompile(`^pm\.response\.headers\.get\(\s*['"]([^'"]+)['"]\s*\)$`)
@@ -69,13 +74,14 @@ func Translate(events []ast.Event) Result {
 	result := Result{}
 
 	unmappedCounts := make(map[report.IssueCode]int)
-	unmappedFirstLine := make(map[report.IssueCode]int)
+	unmappedFirstLine := make(map[report.IssueCode]diagnostics.Span)
+	var script ast.Script
 	recordUnmapped := func(code report.IssueCode, line int) {
 		unmappedCounts[code]++
 		result.UnmappedLines++
 		if line > 0 {
 			if _, exists := unmappedFirstLine[code]; !exists {
-				unmappedFirstLine[code] = line
+				unmappedFirstLine[code] = diagnostics.Span{Line: line, SourceLine: script.SourceLine(line)}
 			}
 		}
 	}
@@ -86,12 +92,87 @@ func Translate(events []ast.Event) Result {
 	jsonParseIntent := false
 	jsonSemanticsEnforced := false
 	conditionStack := make([]conditionFrame, 0)
+	jsonRoots := map[string]struct{}{"json": {}}
+
+	translateLine := func(line string, lineNumber int) {
+		if code, ok := extractStatusAssertionCode(line); ok {
+			addStatusAssert(&result.Asserts, statusSeen, code)
+			result.MappedLines++
+			return
+		}
+
+		if mapResponseTimeComparison(&result.Asserts, assertSeen, line) {
+			result.MappedLines++
+			return
+		}
+
+		if name, ok := parseJSONDeclaration(line); ok {
+			jsonRoots[name] = struct{}{}
+			jsonParseIntent = true
+			result.MappedLines++
+			return
+		}
+
+		if isJSONParseLine(line) || isJSONValidityLine(line) {
+			jsonParseIntent = true
+			result.MappedLines++
+			return
+		}
+
+		if mapped, needsJSON := mapHasAssertion(&result.Asserts, assertSeen, line); mapped {
+			if needsJSON {
+				jsonSemanticsEnforced = true
+			}
+			result.MappedLines++
+			return
+		}
+
+		if mapped, needsJSON := mapJSONComparison(&result.Asserts, assertSeen, line); mapped {
+			if needsJSON {
+				jsonSemanticsEnforced = true
+			}
+			result.MappedLines++
+			return
+		}
+
+		if mapped, needsJSON := mapArrayTypeAssertion(&result.Asserts, assertSeen, line); mapped {
+			if needsJSON {
+				jsonSemanticsEnforced = true
+			}
+			result.MappedLines++
+			return
+		}
+
+		if mapped, needsJSON := mapExpectAssertion(&result.Asserts, assertSeen, line, jsonRoots); mapped {
+			if needsJSON {
+				jsonSemanticsEnforced = true
+			}
+			result.MappedLines++
+			return
+		}
+
+		captureResult := mapEnvironmentCapture(&captured, line, jsonRoots)
+		if captureResult.mapped {
+			if captureResult.requiresJSON {
+				jsonSemanticsEnforced = true
+			}
+			result.MappedLines++
+			return
+		}
+		if captureResult.issueCode != "" {
+			recordUnmapped(captureResult.issueCode, lineNumber)
+			return
+		}
+
+		recordUnmapped(report.CodeScriptLineUnmapped, lineNumber)
+	}
 
 	for _, event := range events {
 		if strings.ToLower(strings.TrimSpace(event.Listen)) != "test" {
 			continue
 		}
 
+		script = event.Script
 		program := parse.Script(lex.Script(event.Script.Exec))
 		for _, statement := range program.Statements {
 			switch statement.Kind {
@@ -132,66 +213,26 @@ func Translate(events []ast.Event) Result {
 				result.MappedLines++
 				continue
 			case parse.StatementStructural:
-				result.IgnoredLines++
-				continue
-			}
-
-			line := strings.TrimSpace(statement.Text)
-			if hasUnsupportedCondition(conditionStack) {
-				recordUnmapped(report.CodeScriptExpressionNotSupported, statement.Line)
-				continue
-			}
-
-			if code, ok := extractStatusAssertionCode(line); ok {
-				addStatusAssert(&result.Asserts, statusSeen, code)
-				result.MappedLines++
-				continue
-			}
-
-			if isJSONParseLine(line) || isJSONValidityLine(line) {
-				jsonParseIntent = true
-				result.MappedLines++
-				continue
-			}
-
-			if mapped, needsJSON := mapHasAssertion(&result.Asserts, assertSeen, line); mapped {
-				if needsJSON {
-					jsonSemanticsEnforced = true
+				inline := inlineTestBody(statement.Text)
+				if len(inline) == 0 {
+					result.IgnoredLines++
+					continue
 				}
-				result.MappedLines++
-				continue
-			}
-
-			if mapped, needsJSON := mapJSONComparison(&result.Asserts, assertSeen, line); mapped {
-				if needsJSON {
-					jsonSemanticsEnforced = true
+				if hasUnsupportedCondition(conditionStack) {
+					recordUnmapped(report.CodeScriptExpressionNotSupported, statement.Line)
+					continue
 				}
-				result.MappedLines++
-				continue
-			}
-
-			if mapped, needsJSON := mapArrayTypeAssertion(&result.Asserts, assertSeen, line); mapped {
-				if needsJSON {
-					jsonSemanticsEnforced = true
+				for _, line := range inline {
+					translateLine(line, statement.Line)
 				}
-				result.MappedLines++
 				continue
 			}
 
-			captureResult := mapEnvironmentCapture(&captured, line)
-			if captureResult.mapped {
-				if captureResult.requiresJSON {
-					jsonSemanticsEnforced = true
-				}
-				result.MappedLines++
-				continue
-			}
-			if captureResult.issueCode != "" {
-				recordUnmapped(captureResult.issueCode, statement.Line)
+			if hasUnsupportedCondition(conditionStack) {
+				recordUnmapped(report.CodeScriptExpressionNotSupported, statement.Line)
 				continue
 			}
-
-			recordUnmapped(report.CodeScriptLineUnmapped, statement.Line)
+			translateLine(strings.TrimSpace(statement.Text), statement.Line)
 		}
 	}
 
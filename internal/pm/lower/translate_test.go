@@ -90,9 +90,12 @@ func TestTranslateReportsLineSpanForUnsupportedLines(t *testing.T) {
 
 	events := []ast.Event{{
 		Listen: "test",
-		Script: ast.Script{Exec: []string{
-			`tests["unsupported"] = pm.response.json().ok === true;`,
-		}},
+		Script: ast.Script{
+			Exec: []string{
+				`tests["unsupported"] = pm.response.json().ok === true;`,
+			},
+			ExecLines: []int{42},
+		},
 	}}
 
 	result := Translate(events)
@@ -106,8 +109,8 @@ func TestTranslateReportsLineSpanForUnsupportedLines(t *testing.T) {
 	if issue.Severity != diagnostics.SeverityError {
 		t.Fatalf("issue severity = %q, want %q", issue.Severity, diagnostics.SeverityError)
 	}
-	if issue.Span == nil || issue.Span.Line != 1 {
-		t.Fatalf("issue span = %+v, want line 1", issue.Span)
+	if issue.Span == nil || issue.Span.Line != 1 || issue.Span.SourceLine != 42 {
+		t.Fatalf("issue span = %+v, want line 1 on source line 42", issue.Span)
 	}
 }
 
@@ -257,6 +260,182 @@ func TestTranslateStatusDirectAssertionsStillMap(t *testing.T) {
 	}
 }
 
+func TestTranslateMapsPMTestCallbackBodies(t *testing.T) {
+	t.Parallel()
+
+	events := []ast.Event{{
+		Listen: "test",
+		Script: ast.Script{Exec: []string{
+			`pm.test("Status code is 200", function () {`,
+			`    pm.response.to.have.status(200);`,
+			`});`,
+			`pm.test("Body is correct", function () {`,
+			`    var jsonData = pm.response.json();`,
+			`    pm.expect(jsonData.name).to.eql("rex");`,
+			`    pm.expect(jsonData.data["id"]).to.equal(42);`,
+			`    pm.expect(jsonData.kind).to.not.equal('cat');`,
+			`    pm.expect(jsonData).to.have.property('owner');`,
+			`    pm.expect(jsonData.owner).to.have.property("active", true);`,
+			`    pm.expect(pm.response.json().message).to.include("created");`,
+			`});`,
+			`pm.test("inline", () => { pm.expect(pm.response.json().ok).to.be.equal(true); });`,
+		}},
+	}}
+
+	result := Translate(events)
+
+	if result.UnmappedLines != 0 {
+		t.Fatalf("UnmappedLines = %d, expected 0: %+v", result.UnmappedLines, result.Issues)
+	}
+	if len(result.Asserts.Status) != 1 {
+		t.Fatalf("status asserts = %d", len(result.Asserts.Status))
+	}
+
+	tests := []struct {
+		path  string
+		op    string
+		value any
+	}{
+		{path: "$.name", op: "equals", value: "rex"},
+		{path: "$.data.id", op: "equals", value: int64(42)},
+		{path: "$.kind", op: "not_equals", value: "cat"},
+		{path: "$.owner.active", op: "equals", value: true},
+		{path: "$.message", op: "contains", value: "created"},
+		{path: "$.ok", op: "equals", value: true},
+	}
+	for _, tt := range tests {
+		if !hasJSONPathAssertWithValue(result.Asserts.JSONPath, tt.path, tt.op, tt.value) {
+			t.Errorf("missing %s %s %v in %+v", tt.path, tt.op, tt.value, result.Asserts.JSONPath)
+		}
+	}
+	if !hasJSONPathAssert(result.Asserts.JSONPath, "$.owner", "exists") {
+		t.Errorf("missing $.owner exists in %+v", result.Asserts.JSONPath)
+	}
+}
+
+func TestTranslateMapsHeaderExpectations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		line  string
+		op    string
+		value any
+	}{
+		{name: "has header", line: `pm.response.to.have.header("X-Request-Id");`, op: "exists"},
+		{name: "has header value", line: `pm.response.to.have.header("X-Request-Id", "abc");`, op: "equals", value: "abc"},
+		{name: "expect response has header", line: `pm.expect(pm.response).to.have.header('X-Request-Id');`, op: "exists"},
+		{name: "header includes", line: `pm.expect(pm.response.headers.get('X-Request-Id')).to.include('json');`, op: "contains", value: "json"},
+		{name: "header not includes", line: `pm.expect(pm.response.headers.get("X-Request-Id")).to.not.contain("xml");`, op: "not_contains", value: "xml"},
+		{name: "header equals", line: `pm.expect(pm.response.headers.get("X-Request-Id")).to.eql("abc");`, op: "equals", value: "abc"},
+		{name: "header exists", line: `pm.expect(pm.response.headers.get("X-Request-Id")).to.exist;`, op: "exists"},
+		{name: "legacy header", line: `pm.expect(responseHeaders["X-Request-Id"]).to.equal("abc");`, op: "equals", value: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := Translate([]ast.Event{{
+				Listen: "test",
+				Script: ast.Script{Exec: []string{tt.line}},
+			}})
+
+			if result.UnmappedLines != 0 || len(result.Issues) != 0 {
+				t.Fatalf("UnmappedLines = %d, issues = %+v", result.UnmappedLines, result.Issues)
+			}
+			if len(result.Asserts.Headers) != 1 {
+				t.Fatalf("header asserts = %+v, expected one", result.Asserts.Headers)
+			}
+			assert := result.Asserts.Headers[0]
+			if assert.Name != "X-Request-Id" || assert.Predicate.Operation != tt.op || assert.Predicate.Value != tt.value {
+				t.Fatalf("header assert = %+v, expected %s %v", assert, tt.op, tt.value)
+			}
+			if assert.Predicate.HasValue != (tt.value != nil) {
+				t.Fatalf("HasValue = %v", assert.Predicate.HasValue)
+			}
+		})
+	}
+}
+
+func TestTranslateMapsResponseTimeAssertions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		line  string
+		op    string
+		value int64
+	}{
+		{name: "below", line: `pm.expect(pm.response.responseTime).to.be.below(500);`, op: "less_than", value: 500},
+		{name: "lessThan", line: `pm.expect(pm.response.responseTime).to.be.lessThan(200);`, op: "less_than", value: 200},
+		{name: "at most", line: `pm.expect(pm.response.responseTime).to.be.at.most(300);`, op: "less_than_or_equal", value: 300},
+		{name: "above", line: `pm.expect(pm.response.responseTime).to.be.above(10);`, op: "greater_than", value: 10},
+		{name: "not below", line: `pm.expect(pm.response.responseTime).to.not.be.below(5);`, op: "greater_than_or_equal", value: 5},
+		{name: "legacy", line: `tests["Response time is less than 200ms"] = responseTime < 200;`, op: "less_than", value: 200},
+		{name: "legacy pm", line: `tests["fast"] = pm.response.responseTime <= 250;`, op: "less_than_or_equal", value: 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := Translate([]ast.Event{{
+				Listen: "test",
+				Script: ast.Script{Exec: []string{tt.line}},
+			}})
+
+			if result.UnmappedLines != 0 || len(result.Issues) != 0 {
+				t.Fatalf("UnmappedLines = %d, issues = %+v", result.UnmappedLines, result.Issues)
+			}
+			if len(result.Asserts.Duration) != 1 {
+				t.Fatalf("duration asserts = %+v, expected one", result.Asserts.Duration)
+			}
+			predicate := result.Asserts.Duration[0].Predicate
+			if predicate.Operation != tt.op || predicate.Value != tt.value || !predicate.HasValue {
+				t.Fatalf("duration assert = %+v, expected %s %d", predicate, tt.op, tt.value)
+			}
+		})
+	}
+}
+
+func TestTranslateUnsupportedExpectationsAreUnmapped(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "unknown variable", line: `pm.expect(other.id).to.eql(1);`},
+		{name: "non literal value", line: `pm.expect(json.id).to.eql(pm.environment.get("id"));`},
+		{name: "array membership", line: `pm.expect(json.ids).to.include(3);`},
+		{name: "negated property", line: `pm.expect(json).to.not.have.property('error');`},
+		{name: "unsupported assertion", line: `pm.expect(json.items).to.have.lengthOf(2);`},
+		{name: "chained after assertion", line: `pm.expect(json).to.have.property('a').that.equals(1);`},
+		{name: "negated header", line: `pm.response.to.not.have.header("X-Debug");`},
+		{name: "response time equality", line: `pm.expect(pm.response.responseTime).to.eql(100);`},
+		{name: "header regex", line: `pm.expect(pm.response.headers.get("Date")).to.match(/GMT/);`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := Translate([]ast.Event{{
+				Listen: "test",
+				Script: ast.Script{Exec: []string{tt.line}},
+			}})
+
+			if result.UnmappedLines != 1 {
+				t.Fatalf("UnmappedLines = %d, expected 1", result.UnmappedLines)
+			}
+			if len(result.Asserts.JSONPath) != 0 {
+				t.Fatalf("unexpected asserts: %+v", result.Asserts.JSONPath)
+			}
+		})
+	}
+}
+
 func TestTranslateLooseJSONEqualityIsNotMapped(t *testing.T) {
 	t.Parallel()
 
@@ -368,6 +547,68 @@ func TestTranslateConditionalHasExpressionDottedPathMapsNestedJSONPath(t *testin
 	}
 }
 
+func TestTranslateMapsVariableScopeSetters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		line string
+		path string
+	}{
+		{name: "environment", line: `pm.environment.set("x", json.data.id);`, path: "$.data.id"},
+		{name: "globals", line: `pm.globals.set('x', json.token);`, path: "$.token"},
+		{name: "collection variables", line: `pm.collectionVariables.set("x", json.items[0].id);`, path: "$.items[0].id"},
+		{name: "legacy globals", line: `postman.setGlobalVariable("x", json.id);`, path: "$.id"},
+		{name: "response json", line: `pm.environment.set("x", pm.response.json().data.id);`, path: "$.data.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := Translate([]ast.Event{{
+				Listen: "test",
+				Script: ast.Script{Exec: []string{tt.line}},
+			}})
+
+			if result.UnmappedLines != 0 {
+				t.Fatalf("UnmappedLines = %d, expected 0", result.UnmappedLines)
+			}
+			if result.Captures == nil || len(result.Captures.JSONPath) != 1 {
+				t.Fatalf("expected one capture, got %+v", result.Captures)
+			}
+			if capture := result.Captures.JSONPath[0]; capture.Name != "x" || capture.Path != tt.path {
+				t.Fatalf("capture = %+v, expected x at %s", capture, tt.path)
+			}
+		})
+	}
+}
+
+func TestTranslateVariableSettersKeepLastAssignmentAcrossScopes(t *testing.T) {
+	t.Parallel()
+
+	events := []ast.Event{{
+		Listen: "test",
+		Script: ast.Script{Exec: []string{
+			`const body = pm.response.json();`,
+			`pm.environment.set("x", body.first);`,
+			`pm.collectionVariables.set("x", pm.response.headers.get("X-Id"));`,
+			`pm.globals.set("x", body.data.id);`,
+		}},
+	}}
+
+	result := Translate(events)
+	if result.UnmappedLines != 0 {
+		t.Fatalf("UnmappedLines = %d, expected 0: %+v", result.UnmappedLines, result.Issues)
+	}
+	if result.Captures == nil || len(result.Captures.Headers) != 0 || len(result.Captures.JSONPath) != 1 {
+		t.Fatalf("expected only the last capture, got %+v", result.Captures)
+	}
+	if result.Captures.JSONPath[0].Path != "$.data.id" {
+		t.Fatalf("unexpected capture path: %+v", result.Captures.JSONPath[0])
+	}
+}
+
 func TestTranslateEnvironmentCaptureKeepsLastAssignmentAcrossTypes(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,51 @@
+package normalize
+
+import (
+	"path"
+	"strings"
+)
+
+// Filter keeps request nodes that match at least one of the only patterns (all
+// nodes when none are given) and none of the exclude patterns.
+//
+// Patterns use path.Match syntax against the "/"-joined folder and request
+// names, e.g. "Users/*". A pattern that matches a folder also selects every
+// request below it. A pattern without a "/" also matches the request name
+// alone, so "*Delete*" selects matching requests in any folder.
+func Filter(nodes []RequestNode, only []string, exclude []string) []RequestNode {
+	if len(only) == 0 && len(exclude) == 0 {
+		return nodes
+	}
+
+	var out []RequestNode
+	for _, node := range nodes {
+		if len(only) > 0 && !matchesAny(only, node) {
+			continue
+		}
+		if matchesAny(exclude, node) {
+			continue
+		}
+		out = append(out, node)
+	}
+
+	return out
+}
+
+func matchesAny(patterns []string, node RequestNode) bool {
+	segments := node.FullPath()
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			if matched, err := path.Match(pattern, node.Name); err == nil && matched {
+				return true
+			}
+		}
+		for end := 1; end <= len(segments); end++ {
+			matched, err := path.Match(pattern, strings.Join(segments[:end], "/"))
+			if err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package normalize
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	nodes := []RequestNode{
+		{Name: "List", FolderPath: []string{"Users"}},
+		{Name: "Delete", FolderPath: []string{"Users", "Admin"}},
+		{Name: "List", FolderPath: []string{"Orders"}},
+		{Name: "Health"},
+	}
+
+	tests := []struct {
+		name    string
+		only    []string
+		exclude []string
+		want    []string
+	}{
+		{name: "no filters", want: []string{"Users/List", "Users/Admin/Delete", "Orders/List", "Health"}},
+		{name: "folder glob includes nested folders", only: []string{"Users/*"}, want: []string{"Users/List", "Users/Admin/Delete"}},
+		{name: "folder name selects subtree", only: []string{"Orders"}, want: []string{"Orders/List"}},
+		{name: "multiple only patterns", only: []string{"Orders", "Health"}, want: []string{"Orders/List", "Health"}},
+		{name: "exclude subtree", exclude: []string{"Users/Admin"}, want: []string{"Users/List", "Orders/List", "Health"}},
+		{name: "only and exclude", only: []string{"Users"}, exclude: []string{"*/*/Delete"}, want: []string{"Users/List"}},
+		{name: "request name glob", only: []string{"*/List"}, want: []string{"Users/List", "Orders/List"}},
+		{name: "request name in any folder", only: []string{"Del*"}, want: []string{"Users/Admin/Delete"}},
+		{name: "exclude request name", exclude: []string{"List"}, want: []string{"Users/Admin/Delete", "Health"}},
+		{name: "no match", only: []string{"Billing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, node := range Filter(nodes, tt.only, tt.exclude) {
+				got = append(got, strings.Join(node.FullPath(), "/"))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
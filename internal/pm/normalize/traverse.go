@@ -1,6 +1,10 @@
 package normalize
 
-import "github.com/jacoelho/rq/internal/pm/ast"
+import (
+	"encoding/json"
+
+	"github.com/jacoelho/rq/internal/pm/ast"
+)
 
 // RequestNode contains request data plus folder context from the source tree.
 type RequestNode struct {
@@ -8,6 +12,19 @@ type RequestNode struct {
 	FolderPath []string
 	Request    ast.Request
 	Events     []ast.Event
+	Examples   []ast.Response
+	// InheritedAuth is the auth of the closest enclosing folder or of the
+	// collection that defines one.
+	InheritedAuth json.RawMessage
+}
+
+// Auth returns the auth that applies to the request: its own, or the
+// inherited one when it defers to its parents.
+func (n RequestNode) Auth() json.RawMessage {
+	if ast.HasAuth(n.Request.Auth) {
+		return n.Request.Auth
+	}
+	return n.InheritedAuth
 }
 
 // FullPath returns folder/request path segments.
@@ -18,34 +35,58 @@ func (n RequestNode) FullPath() []string {
 	return path
 }
 
+// Example returns the first saved example response with a 2xx status. Error
+// examples document failure modes rather than the response a plain run receives.
+func (n RequestNode) Example() (ast.Response, bool) {
+	for _, example := range n.Examples {
+		if example.Code >= 200 && example.Code < 300 {
+			return example, true
+		}
+	}
+
+	return ast.Response{}, false
+}
+
 // Requests flattens a nested collection into request nodes.
 func Requests(collection ast.Collection) []RequestNode {
 	var out []RequestNode
-	walkItems(collection.Item, nil, collection.Event, &out)
+	walkItems(collection.Item, nil, collection.Event, inheritAuth(nil, collection.Auth), &out)
 	return out
 }
 
-func walkItems(items []ast.Item, folderPath []string, inheritedEvents []ast.Event, out *[]RequestNode) {
+func walkItems(items []ast.Item, folderPath []string, inheritedEvents []ast.Event, inheritedAuth json.RawMessage, out *[]RequestNode) {
 	for _, item := range items {
 		events := appendEvents(inheritedEvents, item.Event)
+		auth := inheritAuth(inheritedAuth, item.Auth)
 
 		if item.Request != nil {
 			node := RequestNode{
-				Name:       item.Name,
-				FolderPath: append([]string(nil), folderPath...),
-				Request:    *item.Request,
-				Events:     events,
+				Name:          item.Name,
+				FolderPath:    append([]string(nil), folderPath...),
+				Request:       *item.Request,
+				Events:        events,
+				Examples:      append([]ast.Response(nil), item.Response...),
+				InheritedAuth: auth,
 			}
 			*out = append(*out, node)
 		}
 
 		if len(item.Item) > 0 {
 			nextPath := append(append([]string(nil), folderPath...), item.Name)
-			walkItems(item.Item, nextPath, events, out)
+			walkItems(item.Item, nextPath, events, auth, out)
 		}
 	}
 }
 
+// inheritAuth returns the auth a folder passes to its children: its own when
+// it defines one, otherwise the one it inherited.
+func inheritAuth(parent json.RawMessage, current json.RawMessage) json.RawMessage {
+	if ast.HasAuth(current) {
+		return current
+	}
+	return parent
+}
+
 func appendEvents(parent []ast.Event, current []ast.Event) []ast.Event {
 	if len(parent) == 0 && len(current) == 0 {
 		return nil
@@ -1,6 +1,7 @@
 package normalize
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -187,3 +188,52 @@ func TestRequestsCombinesAncestorAndRequestEventsInOrder(t *testing.T) {
 		t.Fatalf("Req 2 events = %#v", req2.Events)
 	}
 }
+
+func TestRequestsInheritsClosestAuth(t *testing.T) {
+	t.Parallel()
+
+	collectionAuth := json.RawMessage(`{"type":"bearer","bearer":[{"key":"token","value":"{{token}}"}]}`)
+	folderAuth := json.RawMessage(`{"type":"basic","basic":[{"key":"username","value":"{{user}}"}]}`)
+	requestAuth := json.RawMessage(`{"type":"noauth"}`)
+
+	collection := ast.Collection{
+		Auth: collectionAuth,
+		Item: []ast.Item{
+			{Name: "Top", Request: &ast.Request{Method: "GET"}},
+			{
+				Name: "Admin",
+				Auth: folderAuth,
+				Item: []ast.Item{
+					{Name: "Inherits folder", Request: &ast.Request{Method: "GET"}},
+					{Name: "Own auth", Request: &ast.Request{Method: "GET", Auth: requestAuth}},
+					{Name: "Explicit inherit", Request: &ast.Request{Method: "GET", Auth: json.RawMessage(`{"type":"inherit"}`)}},
+				},
+			},
+			{
+				Name: "Public",
+				Auth: json.RawMessage(`null`),
+				Item: []ast.Item{
+					{Name: "Inherits collection", Request: &ast.Request{Method: "GET"}},
+				},
+			},
+		},
+	}
+
+	expected := map[string]json.RawMessage{
+		"Top":                 collectionAuth,
+		"Inherits folder":     folderAuth,
+		"Own auth":            requestAuth,
+		"Explicit inherit":    folderAuth,
+		"Inherits collection": collectionAuth,
+	}
+
+	nodes := Requests(collection)
+	if len(nodes) != len(expected) {
+		t.Fatalf("expected %d nodes, got %d", len(expected), len(nodes))
+	}
+	for _, node := range nodes {
+		if got := string(node.Auth()); got != string(expected[node.Name]) {
+			t.Errorf("%s auth = %s, expected %s", node.Name, got, expected[node.Name])
+		}
+	}
+}
@@ -13,8 +13,9 @@ import (
 type Format string
 
 const (
-	FormatText Format = "text"
-	FormatJSON Format = "json"
+	FormatText  Format = "text"
+	FormatJSON  Format = "json"
+	FormatSARIF Format = "sarif"
 )
 
 // IssueCode classifies conversion limitations and skips.
@@ -31,6 +32,9 @@ const (
 	CodeQueryDuplicate                  = diagnostics.CodeQueryDuplicate
 	CodeTemplatePlaceholderUnsupported  = diagnostics.CodeTemplatePlaceholderUnsupported
 	CodeOutputExists                    = diagnostics.CodeOutputExists
+	CodeResponseNotRecorded             = diagnostics.CodeResponseNotRecorded
+	CodeSuccessStatusMissing            = diagnostics.CodeSuccessStatusMissing
+	CodePreRequestLineUnmapped          = diagnostics.CodePreRequestLineUnmapped
 )
 
 // Issue captures a specific conversion warning/error.
@@ -61,12 +65,70 @@ type RequestResult struct {
 
 // Summary aggregates outcomes across the full collection conversion.
 type Summary struct {
+	// Title heads the text report. Empty means "Collection migration summary".
+	Title string `json:"-"`
+	// Source is the URI of the converted file relative to the repository
+	// root, used as the artifact of SARIF results.
+	Source string `json:"-"`
+
 	Total     int               `json:"total"`
 	Converted int               `json:"converted"`
 	Partial   int               `json:"partial"`
 	Skipped   int               `json:"skipped"`
 	ByCode    map[IssueCode]int `json:"by_code,omitempty"`
 	Requests  []RequestResult   `json:"requests,omitempty"`
+
+	Verification *Verification  `json:"verification,omitempty"`
+	Variables    *VariableFiles `json:"variables,omitempty"`
+}
+
+// VariableFiles describes the rq variable files written from collection and
+// environment variables. Paths are relative to the output directory.
+type VariableFiles struct {
+	VariablesFile string  `json:"variables_file,omitempty"`
+	SecretsFile   string  `json:"secrets_file,omitempty"`
+	Variables     int     `json:"variables"`
+	Secrets       int     `json:"secrets"`
+	Issues        []Issue `json:"issues,omitempty"`
+}
+
+// VerifyStatus is the outcome of replaying one converted request.
+type VerifyStatus string
+
+const (
+	VerifyPassed  VerifyStatus = "passed"
+	VerifyFailed  VerifyStatus = "failed"
+	VerifySkipped VerifyStatus = "skipped"
+)
+
+// VerifyResult is the round-trip verification outcome for one generated file.
+type VerifyResult struct {
+	SourcePath string       `json:"source_path"`
+	OutputPath string       `json:"output_path"`
+	Status     VerifyStatus `json:"status"`
+	Message    string       `json:"message,omitempty"`
+}
+
+// Verification aggregates round-trip verification outcomes.
+type Verification struct {
+	Passed  int            `json:"passed"`
+	Failed  int            `json:"failed"`
+	Skipped int            `json:"skipped"`
+	Results []VerifyResult `json:"results,omitempty"`
+}
+
+// Add records one verification result.
+func (v *Verification) Add(result VerifyResult) {
+	switch result.Status {
+	case VerifyPassed:
+		v.Passed++
+	case VerifyFailed:
+		v.Failed++
+	default:
+		v.Skipped++
+	}
+
+	v.Results = append(v.Results, result)
 }
 
 // HasErrors reports whether the summary contains any error-severity issue.
@@ -103,18 +165,20 @@ func (s *Summary) Add(result RequestResult) {
 	}
 }
 
+// hintsByCode holds the extension opportunity suggested by each issue code.
+var hintsByCode = map[IssueCode]string{
+	CodeTestNotMapped:                   "Add richer script assertion extraction to map more test checks into rq asserts.",
+	CodeScriptLineUnmapped:              "Add more script pattern handlers for remaining assertion/capture forms.",
+	CodeScriptExpressionNotSupported:    "Add conditional/control-flow aware script translation.",
+	CodeScriptJSONPathTranslationFailed: "Expand JavaScript expression to JSONPath translation support.",
+	CodeAuthNotMapped:                   "Add direct auth strategy conversion (basic, bearer, oauth2) to rq-native fields/headers.",
+	CodeBodyNotSupported:                "Add mapping for the remaining body modes, such as graphql.",
+	CodeTemplatePlaceholderUnsupported:  "Map unsupported placeholder syntaxes to rq templates/functions or adjust generated templates manually.",
+	CodePreRequestLineUnmapped:          "Replace pre-request logic with rq template functions or captures from an earlier step.",
+}
+
 // Hints returns prioritized extension opportunities inferred from issues.
 func (s Summary) Hints() []string {
-	hintsByCode := map[IssueCode]string{
-		CodeTestNotMapped:                   "Add richer script assertion extraction to map more test checks into rq asserts.",
-		CodeScriptLineUnmapped:              "Add more script pattern handlers for remaining assertion/capture forms.",
-		CodeScriptExpressionNotSupported:    "Add conditional/control-flow aware script translation.",
-		CodeScriptJSONPathTranslationFailed: "Expand JavaScript expression to JSONPath translation support.",
-		CodeAuthNotMapped:                   "Add direct auth strategy conversion (basic, bearer, oauth2) to rq-native fields/headers.",
-		CodeBodyNotSupported:                "Add multipart/file body mapping support.",
-		CodeTemplatePlaceholderUnsupported:  "Map unsupported placeholder syntaxes to rq templates/functions or adjust generated templates manually.",
-	}
-
 	type pair struct {
 		code  IssueCode
 		count int
@@ -158,6 +222,8 @@ func (s Summary) Write(w io.Writer, format Format) error {
 		encoder := json.NewEncoder(w)
 		encoder.SetIndent("", "  ")
 		return encoder.Encode(s)
+	case FormatSARIF:
+		return s.writeSARIF(w)
 	case FormatText, "":
 		writef := func(format string, args ...any) error {
 			if _, err := fmt.Fprintf(w, format, args...); err != nil {
@@ -166,7 +232,11 @@ func (s Summary) Write(w io.Writer, format Format) error {
 			return nil
 		}
 
-		if err := writef("Collection migration summary\n"); err != nil {
+		title := s.Title
+		if title == "" {
+			title = "Collection migration summary"
+		}
+		if err := writef("%s\n", title); err != nil {
 			return err
 		}
 		if err := writef("  total requests: %d\n", s.Total); err != nil {
@@ -210,6 +280,39 @@ func (s Summary) Write(w io.Writer, format Format) error {
 			}
 		}
 
+		if v := s.Variables; v != nil {
+			if err := writef("\nVariables:\n  variables: %d\n  secrets: %d\n", v.Variables, v.Secrets); err != nil {
+				return err
+			}
+			for _, file := range []string{v.VariablesFile, v.SecretsFile} {
+				if file == "" {
+					continue
+				}
+				if err := writef("  - wrote %s\n", file); err != nil {
+					return err
+				}
+			}
+			for _, issue := range v.Issues {
+				if err := writef("  - %s %s: %s\n", issue.Severity, issue.Code, issue.Message); err != nil {
+					return err
+				}
+			}
+		}
+
+		if v := s.Verification; v != nil {
+			if err := writef("\nRound-trip verification:\n  passed: %d\n  failed: %d\n  skipped: %d\n", v.Passed, v.Failed, v.Skipped); err != nil {
+				return err
+			}
+			for _, result := range v.Results {
+				if result.Status == VerifyPassed {
+					continue
+				}
+				if err := writef("  - %s %s: %s\n", result.Status, result.OutputPath, result.Message); err != nil {
+					return err
+				}
+			}
+		}
+
 		return nil
 	default:
 		return fmt.Errorf("unsupported report format: %s", format)
@@ -2,6 +2,7 @@ package report
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
@@ -81,6 +82,119 @@ func TestWriteText(t *testing.T) {
 	}
 }
 
+func TestWriteSARIF(t *testing.T) {
+	t.Parallel()
+
+	var summary Summary
+	summary.Source = "collections/api.json"
+	summary.Add(RequestResult{SourcePath: "Users/Create", OutputPath: "Users/create.yaml", Converted: true, Issues: []Issue{
+		{Code: CodeScriptLineUnmapped, Stage: diagnostics.StageLower, Severity: diagnostics.SeverityError, Message: "1 test script lines were not mapped", Span: &diagnostics.Span{Line: 4, SourceLine: 37}},
+		{Code: CodeAuthNotMapped, Severity: diagnostics.SeverityInfo, Message: "oauth2 auth was not mapped"},
+	}})
+	summary.Add(RequestResult{SourcePath: "Health", Converted: true})
+
+	var buf bytes.Buffer
+	if err := summary.Write(&buf, FormatSARIF); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID   string `json:"id"`
+						Help *struct {
+							Text string `json:"text"`
+						} `json:"help"`
+						DefaultConfiguration struct {
+							Level string `json:"level"`
+						} `json:"defaultConfiguration"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Message   struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+				Properties map[string]any `json:"properties"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, buf.String())
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %s", buf.String())
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "pm2rq" || len(run.Tool.Driver.Rules) != 2 {
+		t.Fatalf("unexpected driver: %+v", run.Tool.Driver)
+	}
+	rule := run.Tool.Driver.Rules[1]
+	if rule.ID != string(CodeScriptLineUnmapped) || rule.DefaultConfiguration.Level != "error" || rule.Help == nil {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+
+	if len(run.Results) != 2 {
+		t.Fatalf("results = %d, expected 2", len(run.Results))
+	}
+	result := run.Results[0]
+	if result.RuleID != string(CodeScriptLineUnmapped) || result.RuleIndex != 1 || result.Level != "error" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Message.Text != "Users/Create: 1 test script lines were not mapped (script line 4)" {
+		t.Fatalf("message = %q", result.Message.Text)
+	}
+	if len(result.Locations) != 1 {
+		t.Fatalf("locations = %+v", result.Locations)
+	}
+	location := result.Locations[0]
+	if location.PhysicalLocation.ArtifactLocation.URI != "collections/api.json" || location.PhysicalLocation.Region.StartLine != 37 {
+		t.Fatalf("physical location = %+v", location.PhysicalLocation)
+	}
+	if len(location.LogicalLocations) != 1 || location.LogicalLocations[0].FullyQualifiedName != "Users/Create" {
+		t.Fatalf("logical locations = %+v", location.LogicalLocations)
+	}
+	if result.Properties["scriptLine"] != float64(4) || result.Properties["outputPath"] != "Users/create.yaml" {
+		t.Fatalf("properties = %v", result.Properties)
+	}
+	if run.Results[1].Level != "note" || run.Results[1].RuleIndex != 0 {
+		t.Fatalf("unexpected result: %+v", run.Results[1])
+	}
+}
+
+func TestWriteSARIFWithoutIssues(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := (Summary{Total: 1, Converted: 1}).Write(&buf, FormatSARIF); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"results": []`) {
+		t.Fatalf("expected empty results array: %s", buf.String())
+	}
+}
+
 func TestWriteTextPropagatesWriterError(t *testing.T) {
 	t.Parallel()
 
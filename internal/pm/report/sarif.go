@@ -0,0 +1,195 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/jacoelho/rq/internal/pm/diagnostics"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	Help                 *sarifMessage      `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations,omitempty"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogical         `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           sarifRegion   `json:"region"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogical struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// writeSARIF writes the request issues as a SARIF 2.1.0 log with one rule
+// per issue code. Results point at the line of the source collection holding
+// the script line of their span, or at its first line without one.
+func (s Summary) writeSARIF(w io.Writer) error {
+	codes := make([]IssueCode, 0, len(s.ByCode))
+	for code := range s.ByCode {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	ruleIndex := make(map[IssueCode]int, len(codes))
+	rules := make([]sarifRule, 0, len(codes))
+	for i, code := range codes {
+		ruleIndex[code] = i
+		rule := sarifRule{
+			ID:                   string(code),
+			ShortDescription:     sarifMessage{Text: string(code)},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(diagnostics.DefinitionFor(code).DefaultSeverity)},
+		}
+		if hint, ok := hintsByCode[code]; ok {
+			rule.Help = &sarifMessage{Text: hint}
+		}
+		rules = append(rules, rule)
+	}
+
+	results := []sarifResult{}
+	for _, request := range s.Requests {
+		for _, issue := range request.Issues {
+			results = append(results, s.sarifResult(request, issue, ruleIndex[issue.Code]))
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "pm2rq", Rules: rules}},
+			Results: results,
+		}},
+	})
+}
+
+func (s Summary) sarifResult(request RequestResult, issue Issue, ruleIndex int) sarifResult {
+	path := issue.Path
+	if path == "" {
+		path = request.SourcePath
+	}
+
+	message := issue.Message
+	if path != "" {
+		message = path + ": " + message
+	}
+
+	result := sarifResult{
+		RuleID:    string(issue.Code),
+		RuleIndex: ruleIndex,
+		Level:     sarifLevel(issue.Severity),
+	}
+
+	properties := map[string]any{}
+	if issue.Stage != "" {
+		properties["stage"] = issue.Stage
+	}
+	if request.OutputPath != "" {
+		properties["outputPath"] = request.OutputPath
+	}
+	if span := issue.Span; span != nil && span.Line > 0 {
+		message += fmt.Sprintf(" (script line %d)", span.Line)
+		properties["scriptLine"] = span.Line
+		if span.Column > 0 {
+			properties["scriptColumn"] = span.Column
+		}
+	}
+	result.Message = sarifMessage{Text: message}
+	if len(properties) > 0 {
+		result.Properties = properties
+	}
+
+	var location sarifLocation
+	if s.Source != "" {
+		line := 1
+		if issue.Span != nil && issue.Span.SourceLine > 0 {
+			line = issue.Span.SourceLine
+		}
+		location.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifact{URI: s.Source},
+			Region:           sarifRegion{StartLine: line},
+		}
+	}
+	if path != "" {
+		location.LogicalLocations = []sarifLogical{{FullyQualifiedName: path}}
+	}
+	if location.PhysicalLocation != nil || location.LogicalLocations != nil {
+		result.Locations = []sarifLocation{location}
+	}
+
+	return result
+}
+
+// sarifLevel maps a severity to a SARIF level. Missing severity is a warning,
+// as in HasErrors.
+func sarifLevel(severity diagnostics.Severity) string {
+	switch severity {
+	case diagnostics.SeverityError:
+		return "error"
+	case diagnostics.SeverityInfo:
+		return "note"
+	default:
+		return "warning"
+	}
+}
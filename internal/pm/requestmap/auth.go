@@ -0,0 +1,154 @@
+package requestmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/pm/ast"
+	"github.com/jacoelho/rq/internal/pm/normalize"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+var singlePlaceholder = regexp.MustCompile(`^\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}$`)
+
+// convertedAuth is the rq form of a request auth: a header or a query
+// parameter carrying the credentials.
+type convertedAuth struct {
+	headers model.KeyValues
+	query   model.KeyValues
+}
+
+// convertAuth maps the bearer, basic and API key auth that applies to node,
+// its own or inherited from a folder or the collection. Credentials held in
+// variables stay templated, so secrets are passed at run time.
+func convertAuth(node normalize.RequestNode) (convertedAuth, []report.Issue) {
+	raw := node.Auth()
+	if !ast.HasAuth(raw) {
+		return convertedAuth{}, nil
+	}
+
+	var auth struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &auth); err != nil {
+		return convertedAuth{}, []report.Issue{
+			requestIssue(report.CodeAuthNotMapped, fmt.Sprintf("auth configuration could not be decoded: %v", err)),
+		}
+	}
+
+	authType := strings.ToLower(strings.TrimSpace(auth.Type))
+	if authType == "noauth" {
+		return convertedAuth{}, nil
+	}
+
+	params, ok := authParams(raw, authType)
+	if !ok {
+		return convertedAuth{}, []report.Issue{
+			requestIssue(report.CodeAuthNotMapped, fmt.Sprintf("%s auth was not mapped; define equivalent headers/variables manually", authType)),
+		}
+	}
+
+	switch authType {
+	case "bearer":
+		token, issues := normalizeWithIssues(params["token"], "auth.bearer.token")
+		return convertedAuth{headers: model.KeyValues{{Key: "Authorization", Value: "Bearer " + token}}}, issues
+	case "basic":
+		username, userOK := templateOperand(params["username"])
+		password, passwordOK := templateOperand(params["password"])
+		if !userOK || !passwordOK {
+			return convertedAuth{}, []report.Issue{
+				requestIssue(report.CodeAuthNotMapped, "basic auth credentials mixing text and variables were not mapped; define an Authorization header manually"),
+			}
+		}
+		value := fmt.Sprintf(`Basic {{b64enc (print %s ":" %s)}}`, username, password)
+		return convertedAuth{headers: model.KeyValues{{Key: "Authorization", Value: value}}}, nil
+	case "apikey":
+		return convertAPIKeyAuth(params)
+	default:
+		return convertedAuth{}, []report.Issue{
+			requestIssue(report.CodeAuthNotMapped, fmt.Sprintf("%s auth was not mapped; define equivalent headers/variables manually", authType)),
+		}
+	}
+}
+
+func convertAPIKeyAuth(params map[string]string) (convertedAuth, []report.Issue) {
+	name := strings.TrimSpace(params["key"])
+	if name == "" {
+		return convertedAuth{}, []report.Issue{
+			requestIssue(report.CodeAuthNotMapped, "apikey auth has no key name; define equivalent headers/variables manually"),
+		}
+	}
+
+	value, issues := normalizeWithIssues(params["value"], "auth.apikey.value")
+	entry := model.KeyValues{{Key: name, Value: value}}
+	switch strings.ToLower(strings.TrimSpace(params["in"])) {
+	case "", "header":
+		return convertedAuth{headers: entry}, issues
+	case "query":
+		return convertedAuth{query: entry}, issues
+	default:
+		return convertedAuth{}, append(issues, requestIssue(report.CodeAuthNotMapped, fmt.Sprintf("apikey auth in %s was not mapped", params["in"])))
+	}
+}
+
+// authParams reads the parameters of an auth definition. Collections in the
+// v2.1 format list them as key/value entries; v2.0 uses a plain object.
+func authParams(raw json.RawMessage, authType string) (map[string]string, bool) {
+	var definition map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &definition); err != nil {
+		return nil, false
+	}
+	data, ok := definition[authType]
+	if !ok {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	var entries []struct {
+		Key   string `json:"key"`
+		Value any    `json:"value"`
+	}
+	if err := json.Unmarshal(data, &entries); err == nil {
+		for _, entry := range entries {
+			params[entry.Key] = authValue(entry.Value)
+		}
+		return params, true
+	}
+
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, false
+	}
+	for key, value := range object {
+		params[key] = authValue(value)
+	}
+
+	return params, true
+}
+
+func authValue(value any) string {
+	if value == nil {
+		return ""
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return fmt.Sprint(value)
+}
+
+// templateOperand returns value as an operand of a template function call:
+// a variable reference for a single placeholder and a quoted string for
+// plain text. Text mixed with placeholders has no operand form.
+func templateOperand(value string) (string, bool) {
+	if matches := singlePlaceholder.FindStringSubmatch(strings.TrimSpace(value)); len(matches) == 2 {
+		return "." + matches[1], true
+	}
+	if strings.Contains(value, "{{") {
+		return "", false
+	}
+	return strconv.Quote(value), true
+}
@@ -1,10 +1,10 @@
 package requestmap
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/textproto"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/jacoelho/rq/internal/pm/ast"
@@ -56,18 +56,33 @@ func Request(node normalize.RequestNode) Result {
 	headers, headerIssues := convertHeaders(node)
 	result.Issues = append(result.Issues, headerIssues...)
 
-	body, bodyFile, bodyHeaders, bodyIssues := convertBody(node)
+	body, bodyIssues := convertBody(node)
 	result.Issues = append(result.Issues, bodyIssues...)
-	if len(bodyHeaders) > 0 {
-		for _, header := range bodyHeaders {
+	for _, header := range body.headers {
+		if !hasHeader(headers, header.Key) {
+			headers = append(headers, header)
+		}
+	}
+	if len(body.multipart) > 0 {
+		// rq sets Content-Type with the boundary of the body it builds.
+		headers = slices.DeleteFunc(headers, func(header model.KeyValue) bool {
+			return strings.EqualFold(header.Key, "Content-Type")
+		})
+	}
+
+	if !hasHeader(headers, "Authorization") {
+		auth, authIssues := convertAuth(node)
+		result.Issues = append(result.Issues, authIssues...)
+		for _, header := range auth.headers {
 			if !hasHeader(headers, header.Key) {
 				headers = append(headers, header)
 			}
 		}
-	}
-
-	if hasAuth(node) && !hasHeader(headers, "Authorization") {
-		result.Issues = append(result.Issues, requestIssue(report.CodeAuthNotMapped, "auth configuration was not mapped; define equivalent headers/variables manually"))
+		for _, param := range auth.query {
+			if _, exists := query.Get(param.Key); !exists {
+				query = append(query, param)
+			}
+		}
 	}
 
 	scriptResult := lower.Translate(node.Events)
@@ -78,9 +93,12 @@ func Request(node normalize.RequestNode) Result {
 		URL:      urlValue,
 		Headers:  nil,
 		Query:    nil,
-		Body:     body,
-		BodyFile: bodyFile,
-		Asserts:  scriptResult.Asserts,
+		Body:     model.TextBody(body.text),
+		BodyFile: body.file,
+		// Postman sends file bodies as they are on disk.
+		BodyFileRaw: body.file != "",
+		Multipart:   body.multipart,
+		Asserts:     scriptResult.Asserts,
 	}
 	step.Captures = scriptResult.Captures
 
@@ -91,6 +109,11 @@ func Request(node normalize.RequestNode) Result {
 		step.Query = query
 	}
 
+	preRequest := lower.TranslatePreRequest(node.Events)
+	result.Issues = append(result.Issues, preRequest.Issues...)
+	preRequestIssues := applyPreRequestVariables(&step, preRequest.Variables)
+	result.Issues = append(result.Issues, preRequestIssues...)
+
 	result.Step = step
 	result.Converted = !report.HasErrors(result.Issues)
 	return result
@@ -237,36 +260,48 @@ func convertNormalizedKeyValues[T any](
 	)
 }
 
-func convertBody(node normalize.RequestNode) (string, string, model.KeyValues, []report.Issue) {
+// convertedBody is the rq form of a request body: inline text, a body_file
+// or multipart parts, with the headers the body implies.
+type convertedBody struct {
+	text      string
+	file      string
+	multipart []model.Part
+	headers   model.KeyValues
+}
+
+func convertBody(node normalize.RequestNode) (convertedBody, []report.Issue) {
 	if node.Request.Body == nil {
-		return "", "", nil, nil
+		return convertedBody{}, nil
 	}
 
 	mode := strings.ToLower(strings.TrimSpace(node.Request.Body.Mode))
 	switch mode {
 	case "", "none":
-		return "", "", nil, nil
+		return convertedBody{}, nil
 	case "raw":
 		normalized, issues := normalizeWithIssues(node.Request.Body.Raw, "body")
-		return normalized, "", nil, issues
+		return convertedBody{text: normalized}, issues
 	case "file":
 		if node.Request.Body.File == nil {
-			return "", "", nil, nil
+			return convertedBody{}, nil
 		}
 		sourcePath, issues := normalizeWithIssues(strings.TrimSpace(node.Request.Body.File.Src), "body_file")
-		return "", sourcePath, nil, issues
+		return convertedBody{file: sourcePath}, issues
 	case "urlencoded":
 		body, headers, issues := convertFormLikeBody(node.Request.Body.URLEncoded)
-		return body, "", headers, issues
+		return convertedBody{text: body, headers: headers}, issues
 	case "formdata":
-		if issues := validateFormDataEntries(node.Request.Body.FormData); len(issues) > 0 {
-			return "", "", nil, issues
+		// Text-only form data is sent url-encoded; file entries need a
+		// multipart body.
+		if hasFormDataFiles(node.Request.Body.FormData) {
+			parts, issues := convertMultipartBody(node.Request.Body.FormData)
+			return convertedBody{multipart: parts}, issues
 		}
 
 		body, headers, issues := convertFormLikeBody(node.Request.Body.FormData)
-		return body, "", headers, issues
+		return convertedBody{text: body, headers: headers}, issues
 	default:
-		return "", "", nil, []report.Issue{
+		return convertedBody{}, []report.Issue{
 			requestIssue(report.CodeBodyNotSupported, fmt.Sprintf("body mode is not supported: %s", mode)),
 		}
 	}
@@ -283,19 +318,51 @@ func convertFormLikeBody(values []ast.BodyKV) (string, model.KeyValues, []report
 	}, issues
 }
 
-func validateFormDataEntries(values []ast.BodyKV) []report.Issue {
+func isFormDataFile(entry ast.BodyKV) bool {
+	return strings.EqualFold(strings.TrimSpace(entry.Type), "file")
+}
+
+func hasFormDataFiles(values []ast.BodyKV) bool {
+	return slices.ContainsFunc(values, func(entry ast.BodyKV) bool {
+		return !entry.Disabled && isFormDataFile(entry)
+	})
+}
+
+// convertMultipartBody maps form-data entries to multipart parts. A file
+// entry with several sources becomes one part per file under the same name.
+func convertMultipartBody(values []ast.BodyKV) ([]model.Part, []report.Issue) {
+	var (
+		parts  []model.Part
+		issues []report.Issue
+	)
 	for _, entry := range values {
-		if entry.Disabled {
+		key := strings.TrimSpace(entry.Key)
+		if entry.Disabled || key == "" {
 			continue
 		}
-		if strings.EqualFold(strings.TrimSpace(entry.Type), "file") {
-			return []report.Issue{
-				requestIssue(report.CodeBodyNotSupported, "form-data file entries are not supported"),
-			}
+
+		name, nameIssues := normalizeWithIssues(key, fmt.Sprintf("body key[%s]", key))
+		issues = append(issues, nameIssues...)
+
+		if !isFormDataFile(entry) {
+			value, valueIssues := normalizeWithIssues(entry.Value, fmt.Sprintf("body value[%s]", key))
+			issues = append(issues, valueIssues...)
+			parts = append(parts, model.Part{Name: name, Value: value, ContentType: entry.ContentType})
+			continue
+		}
+
+		if len(entry.Src) == 0 {
+			issues = append(issues, requestIssue(report.CodeBodyNotSupported, fmt.Sprintf("form-data file entry %s has no source file", key)))
+			continue
+		}
+		for _, src := range entry.Src {
+			file, fileIssues := normalizeWithIssues(strings.TrimSpace(src), fmt.Sprintf("body file[%s]", key))
+			issues = append(issues, fileIssues...)
+			parts = append(parts, model.Part{Name: name, File: file, ContentType: entry.ContentType})
 		}
 	}
 
-	return nil
+	return parts, issues
 }
 
 func encodeKeyValues(values []ast.BodyKV) (string, []report.Issue) {
@@ -441,22 +508,37 @@ func templateDiagnosticsToIssues(field string, tmplDiagnostics []template.Diagno
 	return issues
 }
 
-func hasAuth(node normalize.RequestNode) bool {
-	raw := strings.TrimSpace(string(node.Request.Auth))
-	if raw == "" || raw == "null" || raw == "{}" {
-		return false
+// applyPreRequestVariables replaces references to variables set by the
+// pre-request script with their values, since rq files have no variables of
+// their own.
+func applyPreRequestVariables(step *model.Step, variables model.KeyValues) []report.Issue {
+	if len(variables) == 0 {
+		return nil
 	}
 
-	var auth struct {
-		Type string `json:"type"`
+	var issues []report.Issue
+	pairs := make([]string, 0, len(variables)*2)
+	for _, variable := range variables {
+		value, valueIssues := normalizeWithIssues(variable.Value, fmt.Sprintf("prerequest[%s]", variable.Key))
+		issues = append(issues, valueIssues...)
+		pairs = append(pairs, "{{."+variable.Key+"}}", value)
 	}
-	if err := json.Unmarshal(node.Request.Auth, &auth); err == nil {
-		if strings.EqualFold(strings.TrimSpace(auth.Type), "noauth") {
-			return false
-		}
+	replacer := strings.NewReplacer(pairs...)
+
+	step.URL = replacer.Replace(step.URL)
+	for i := range step.Headers {
+		step.Headers[i].Value = replacer.Replace(step.Headers[i].Value)
+	}
+	for i := range step.Query {
+		step.Query[i].Value = replacer.Replace(step.Query[i].Value)
+	}
+	step.Body = model.TextBody(replacer.Replace(step.Body.Text))
+	step.BodyFile = replacer.Replace(step.BodyFile)
+	for i := range step.Multipart {
+		step.Multipart[i].Value = replacer.Replace(step.Multipart[i].Value)
 	}
 
-	return true
+	return issues
 }
 
 func hasHeader(headers model.KeyValues, expected string) bool {
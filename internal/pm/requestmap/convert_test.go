@@ -2,12 +2,14 @@ package requestmap
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/jacoelho/rq/internal/pm/ast"
 	"github.com/jacoelho/rq/internal/pm/normalize"
 	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/model"
 )
 
 func TestRequestBasicMapping(t *testing.T) {
@@ -51,8 +53,8 @@ func TestRequestBasicMapping(t *testing.T) {
 	if got, _ := result.Step.Headers.Get("Authorization"); got != "Bearer {{.token}}" {
 		t.Fatalf("authorization header = %q", got)
 	}
-	if result.Step.Body != `{"id":"{{.user_id}}"}` {
-		t.Fatalf("body = %q", result.Step.Body)
+	if result.Step.Body.Text != `{"id":"{{.user_id}}"}` {
+		t.Fatalf("body = %q", result.Step.Body.Text)
 	}
 	if len(result.Step.Asserts.Status) != 1 {
 		t.Fatalf("status asserts len = %d", len(result.Step.Asserts.Status))
@@ -214,7 +216,7 @@ func TestRequestBuildsURLWithDefaultSchemeFromHostPath(t *testing.T) {
 	}
 }
 
-func TestRequestUnsupportedFormDataFile(t *testing.T) {
+func TestRequestFormDataFileWithoutSource(t *testing.T) {
 	t.Parallel()
 
 	node := normalize.RequestNode{
@@ -234,16 +236,98 @@ func TestRequestUnsupportedFormDataFile(t *testing.T) {
 
 	result := Request(node)
 	if result.Converted {
-		t.Fatal("expected request conversion to fail on unsupported form-data file mode")
+		t.Fatal("expected request conversion to fail on form-data file without source")
 	}
-	if result.Step.Body != "" {
-		t.Fatalf("expected empty body, got %q", result.Step.Body)
+	if result.Step.Body.Text != "" {
+		t.Fatalf("expected empty body, got %q", result.Step.Body.Text)
 	}
 	if !hasIssue(result.Issues, report.CodeBodyNotSupported) {
 		t.Fatalf("expected body unsupported issue, got %+v", result.Issues)
 	}
 }
 
+func TestRequestFormDataFilesMapToMultipart(t *testing.T) {
+	t.Parallel()
+
+	node := normalize.RequestNode{
+		Name: "Upload",
+		Request: ast.Request{
+			Method: "POST",
+			URL:    ast.URLValue{Raw: "https://api.example.com/upload"},
+			Header: []ast.Header{{Key: "Content-Type", Value: "multipart/form-data"}},
+			Body: &ast.Body{
+				Mode: "formdata",
+				FormData: []ast.BodyKV{
+					{Key: "title", Value: "{{title}}"},
+					{Key: "meta", Value: `{"a":1}`, ContentType: "application/json"},
+					{Key: "files", Type: "file", Src: ast.FileSources{"a.png", "b.png"}},
+					{Key: "skipped", Type: "file", Src: ast.FileSources{"c.png"}, Disabled: true},
+				},
+			},
+		},
+	}
+
+	result := Request(node)
+	if !result.Converted {
+		t.Fatalf("expected request to be converted, issues: %+v", result.Issues)
+	}
+
+	want := []model.Part{
+		{Name: "title", Value: "{{.title}}"},
+		{Name: "meta", Value: `{"a":1}`, ContentType: "application/json"},
+		{Name: "files", File: "a.png"},
+		{Name: "files", File: "b.png"},
+	}
+	if !reflect.DeepEqual(result.Step.Multipart, want) {
+		t.Fatalf("multipart = %+v, want %+v", result.Step.Multipart, want)
+	}
+	if result.Step.Body.Text != "" {
+		t.Fatalf("expected empty body, got %q", result.Step.Body.Text)
+	}
+	if len(result.Step.Headers) != 0 {
+		t.Fatalf("expected Content-Type header to be dropped, got %+v", result.Step.Headers)
+	}
+}
+
+func TestRequestAppliesPreRequestVariables(t *testing.T) {
+	t.Parallel()
+
+	node := normalize.RequestNode{
+		Name: "Create order",
+		Request: ast.Request{
+			Method: "POST",
+			URL:    ast.URLValue{Raw: "https://api.example.com/orders/{{order_id}}"},
+			Header: []ast.Header{{Key: "X-Request-Time", Value: "{{sent_at}}"}},
+			Body:   &ast.Body{Mode: "raw", Raw: `{"ref":"order-{{ref}}","user":"{{user}}"}`},
+		},
+		Events: []ast.Event{{
+			Listen: "prerequest",
+			Script: ast.Script{Exec: []string{
+				`pm.environment.set("order_id", "42");`,
+				`pm.variables.set("sent_at", new Date().toISOString());`,
+				`pm.globals.set("ref", Date.now());`,
+			}},
+		}},
+	}
+
+	result := Request(node)
+	if !result.Converted {
+		t.Fatalf("expected request to be converted: %+v", result.Issues)
+	}
+	if len(result.Issues) != 0 {
+		t.Fatalf("expected no issues, got %+v", result.Issues)
+	}
+	if result.Step.URL != "https://api.example.com/orders/42" {
+		t.Fatalf("url = %q", result.Step.URL)
+	}
+	if got, _ := result.Step.Headers.Get("X-Request-Time"); got != "{{iso8601}}" {
+		t.Fatalf("X-Request-Time = %q", got)
+	}
+	if expected := `{"ref":"order-{{timestamp}}000","user":"{{.user}}"}`; result.Step.Body.Text != expected {
+		t.Fatalf("body = %q, expected %q", result.Step.Body.Text, expected)
+	}
+}
+
 func TestRequestFileBodyMapping(t *testing.T) {
 	t.Parallel()
 
@@ -263,12 +347,15 @@ func TestRequestFileBodyMapping(t *testing.T) {
 	if !result.Converted {
 		t.Fatal("expected request to be converted")
 	}
-	if result.Step.Body != "" {
-		t.Fatalf("expected empty inline body, got %q", result.Step.Body)
+	if result.Step.Body.Text != "" {
+		t.Fatalf("expected empty inline body, got %q", result.Step.Body.Text)
 	}
 	if result.Step.BodyFile != "{{.upload_path}}" {
 		t.Fatalf("body_file = %q", result.Step.BodyFile)
 	}
+	if !result.Step.BodyFileRaw {
+		t.Fatal("expected body_file_raw for a Postman file body")
+	}
 	if len(result.Issues) != 0 {
 		t.Fatalf("expected no issues, got %+v", result.Issues)
 	}
@@ -293,12 +380,15 @@ func TestRequestFileBodyEmptySource(t *testing.T) {
 	if !result.Converted {
 		t.Fatal("expected request to be converted")
 	}
-	if result.Step.Body != "" {
-		t.Fatalf("expected empty body, got %q", result.Step.Body)
+	if result.Step.Body.Text != "" {
+		t.Fatalf("expected empty body, got %q", result.Step.Body.Text)
 	}
 	if result.Step.BodyFile != "" {
 		t.Fatalf("expected empty body_file, got %q", result.Step.BodyFile)
 	}
+	if result.Step.BodyFileRaw {
+		t.Fatal("expected no body_file_raw without body_file")
+	}
 	if len(result.Issues) != 0 {
 		t.Fatalf("expected no issues, got %+v", result.Issues)
 	}
@@ -345,6 +435,149 @@ func TestRequestAuthIssue(t *testing.T) {
 	}
 }
 
+func TestRequestMapsAuth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		auth          string
+		inherited     string
+		header        string
+		headerValue   string
+		queryKey      string
+		queryValue    string
+		expectNoIssue bool
+	}{
+		{
+			name:        "bearer",
+			auth:        `{"type":"bearer","bearer":[{"key":"token","value":"{{token}}","type":"string"}]}`,
+			header:      "Authorization",
+			headerValue: "Bearer {{.token}}",
+		},
+		{
+			name:        "bearer object form",
+			auth:        `{"type":"bearer","bearer":{"token":"{{token}}"}}`,
+			header:      "Authorization",
+			headerValue: "Bearer {{.token}}",
+		},
+		{
+			name:        "basic",
+			auth:        `{"type":"basic","basic":[{"key":"username","value":"{{user}}"},{"key":"password","value":"{{password}}"}]}`,
+			header:      "Authorization",
+			headerValue: `Basic {{b64enc (print .user ":" .password)}}`,
+		},
+		{
+			name:        "basic literal username",
+			auth:        `{"type":"basic","basic":[{"key":"username","value":"admin"},{"key":"password","value":"{{password}}"}]}`,
+			header:      "Authorization",
+			headerValue: `Basic {{b64enc (print "admin" ":" .password)}}`,
+		},
+		{
+			name:        "apikey header",
+			auth:        `{"type":"apikey","apikey":[{"key":"key","value":"X-API-Key"},{"key":"value","value":"{{api_key}}"},{"key":"in","value":"header"}]}`,
+			header:      "X-API-Key",
+			headerValue: "{{.api_key}}",
+		},
+		{
+			name:       "apikey query",
+			auth:       `{"type":"apikey","apikey":[{"key":"key","value":"api_key"},{"key":"value","value":"{{api_key}}"},{"key":"in","value":"query"}]}`,
+			queryKey:   "api_key",
+			queryValue: "{{.api_key}}",
+		},
+		{
+			name:        "inherited from folder",
+			inherited:   `{"type":"bearer","bearer":[{"key":"token","value":"{{token}}"}]}`,
+			header:      "Authorization",
+			headerValue: "Bearer {{.token}}",
+		},
+		{
+			name:      "request noauth overrides inherited",
+			auth:      `{"type":"noauth"}`,
+			inherited: `{"type":"bearer","bearer":[{"key":"token","value":"{{token}}"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			node := normalize.RequestNode{
+				Name: "Auth",
+				Request: ast.Request{
+					Method: "GET",
+					URL:    ast.URLValue{Raw: "https://api.example.com"},
+				},
+			}
+			if tt.auth != "" {
+				node.Request.Auth = json.RawMessage(tt.auth)
+			}
+			if tt.inherited != "" {
+				node.InheritedAuth = json.RawMessage(tt.inherited)
+			}
+
+			result := Request(node)
+			if !result.Converted {
+				t.Fatalf("expected request to be converted: %+v", result.Issues)
+			}
+			if len(result.Issues) != 0 {
+				t.Fatalf("expected no issues, got %+v", result.Issues)
+			}
+			if tt.header != "" {
+				if got, _ := result.Step.Headers.Get(tt.header); got != tt.headerValue {
+					t.Fatalf("%s = %q, expected %q", tt.header, got, tt.headerValue)
+				}
+			} else if len(result.Step.Headers) != 0 {
+				t.Fatalf("unexpected headers: %+v", result.Step.Headers)
+			}
+			if tt.queryKey != "" {
+				if got, _ := result.Step.Query.Get(tt.queryKey); got != tt.queryValue {
+					t.Fatalf("query %s = %q, expected %q", tt.queryKey, got, tt.queryValue)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestAuthKeepsExplicitAuthorizationHeader(t *testing.T) {
+	t.Parallel()
+
+	node := normalize.RequestNode{
+		Name: "Auth",
+		Request: ast.Request{
+			Method: "GET",
+			URL:    ast.URLValue{Raw: "https://api.example.com"},
+			Header: []ast.Header{{Key: "Authorization", Value: "Token {{token}}"}},
+		},
+		InheritedAuth: json.RawMessage(`{"type":"bearer","bearer":[{"key":"token","value":"{{other}}"}]}`),
+	}
+
+	result := Request(node)
+	if len(result.Step.Headers) != 1 {
+		t.Fatalf("headers = %+v, expected only the explicit header", result.Step.Headers)
+	}
+	if got, _ := result.Step.Headers.Get("Authorization"); got != "Token {{.token}}" {
+		t.Fatalf("authorization header = %q", got)
+	}
+}
+
+func TestRequestAuthUnsupportedTypeIsReported(t *testing.T) {
+	t.Parallel()
+
+	node := normalize.RequestNode{
+		Name: "Auth",
+		Request: ast.Request{
+			Method: "GET",
+			URL:    ast.URLValue{Raw: "https://api.example.com"},
+		},
+		InheritedAuth: json.RawMessage(`{"type":"digest","digest":[{"key":"username","value":"u"}]}`),
+	}
+
+	result := Request(node)
+	if !hasIssue(result.Issues, report.CodeAuthNotMapped) {
+		t.Fatalf("expected auth issue, got %+v", result.Issues)
+	}
+}
+
 func TestRequestNoAuthDoesNotCreateAuthIssue(t *testing.T) {
 	t.Parallel()
 
@@ -501,11 +734,11 @@ func TestRequestURLencodedBodyPreservesTemplates(t *testing.T) {
 	if !result.Converted {
 		t.Fatal("expected request to be converted")
 	}
-	if result.Step.Body != "user_id={{.user_id}}&name=John+Doe" {
-		t.Fatalf("body = %q", result.Step.Body)
+	if result.Step.Body.Text != "user_id={{.user_id}}&name=John+Doe" {
+		t.Fatalf("body = %q", result.Step.Body.Text)
 	}
-	if strings.Contains(result.Step.Body, "%7B%7B") {
-		t.Fatalf("body should preserve templates, got %q", result.Step.Body)
+	if strings.Contains(result.Step.Body.Text, "%7B%7B") {
+		t.Fatalf("body should preserve templates, got %q", result.Step.Body.Text)
 	}
 }
 
@@ -530,11 +763,11 @@ func TestRequestFormBodyPreservesTemplates(t *testing.T) {
 	if !result.Converted {
 		t.Fatal("expected request to be converted")
 	}
-	if result.Step.Body != "note=hello+{{.name}}%2Fx" {
-		t.Fatalf("body = %q", result.Step.Body)
+	if result.Step.Body.Text != "note=hello+{{.name}}%2Fx" {
+		t.Fatalf("body = %q", result.Step.Body.Text)
 	}
-	if strings.Contains(result.Step.Body, "%7B%7B") {
-		t.Fatalf("body should preserve templates, got %q", result.Step.Body)
+	if strings.Contains(result.Step.Body.Text, "%7B%7B") {
+		t.Fatalf("body should preserve templates, got %q", result.Step.Body.Text)
 	}
 }
 
@@ -569,8 +802,8 @@ func TestRequestFormLikeBodiesShareEncodingAndContentType(t *testing.T) {
 	if !urlEncoded.Converted || !formData.Converted {
 		t.Fatalf("expected both request bodies to convert, got urlencoded=%v formdata=%v", urlEncoded.Converted, formData.Converted)
 	}
-	if urlEncoded.Step.Body != formData.Step.Body {
-		t.Fatalf("body mismatch: urlencoded=%q formdata=%q", urlEncoded.Step.Body, formData.Step.Body)
+	if urlEncoded.Step.Body.Text != formData.Step.Body.Text {
+		t.Fatalf("body mismatch: urlencoded=%q formdata=%q", urlEncoded.Step.Body.Text, formData.Step.Body.Text)
 	}
 
 	urlContentType, ok := urlEncoded.Step.Headers.Get("Content-Type")
@@ -616,8 +849,8 @@ func TestRequestFormLikeBodiesSkipContentTypeWhenBodyEmpty(t *testing.T) {
 
 	urlEncoded := Request(newNode("urlencoded"))
 	formData := Request(newNode("formdata"))
-	if urlEncoded.Step.Body != "" || formData.Step.Body != "" {
-		t.Fatalf("expected empty bodies, got urlencoded=%q formdata=%q", urlEncoded.Step.Body, formData.Step.Body)
+	if urlEncoded.Step.Body.Text != "" || formData.Step.Body.Text != "" {
+		t.Fatalf("expected empty bodies, got urlencoded=%q formdata=%q", urlEncoded.Step.Body.Text, formData.Step.Body.Text)
 	}
 	if _, ok := urlEncoded.Step.Headers.Get("Content-Type"); ok {
 		t.Fatalf("did not expect Content-Type for empty urlencoded body, got %+v", urlEncoded.Step.Headers)
@@ -19,6 +19,12 @@ type Diagnostic struct {
 	End         int
 }
 
+// IsVariableName reports whether a placeholder naming name is rewritten to an
+// rq template variable, so a variable file entry with that name can be used.
+func IsVariableName(name string) bool {
+	return simpleVariable.MatchString(name)
+}
+
 // Normalize rewrites source placeholders into rq-compatible template paths.
 func Normalize(input string) string {
 	normalized, _ := NormalizeDetailed(input)
@@ -96,8 +102,12 @@ func normalizeDynamicVariable(inner string) (string, bool) {
 	switch strings.ToLower(inner) {
 	case "$timestamp":
 		return "timestamp", true
-	case "$guid":
+	case "$guid", "$randomuuid":
 		return "uuidv4", true
+	case "$isotimestamp":
+		return "iso8601", true
+	case "$randomint":
+		return "randomInt 0 1000", true
 	default:
 		return "", false
 	}
@@ -69,6 +69,21 @@ func TestNormalizeDetailed(t *testing.T) {
 			wantDiag:  false,
 			wantInner: "",
 		},
+		{
+			name:  "maps_random_uuid_dynamic_variable",
+			input: "trace={{$randomUUID}}",
+			want:  "trace={{uuidv4}}",
+		},
+		{
+			name:  "maps_iso_timestamp_dynamic_variable",
+			input: "at={{$isoTimestamp}}",
+			want:  "at={{iso8601}}",
+		},
+		{
+			name:  "maps_random_int_dynamic_variable",
+			input: "n={{$randomInt}}",
+			want:  "n={{randomInt 0 1000}}",
+		},
 		{
 			name:      "unsupported_hyphenated_placeholder",
 			input:     "url={{base-url}}",
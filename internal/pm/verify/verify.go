@@ -0,0 +1,224 @@
+// Package verify replays saved example responses against converted rq files
+// to check that the generated asserts hold for the recorded responses.
+package verify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/jacoelho/rq/internal/pm/ast"
+	"github.com/jacoelho/rq/internal/pm/config"
+	"github.com/jacoelho/rq/internal/pm/normalize"
+	"github.com/jacoelho/rq/internal/pm/report"
+	"github.com/jacoelho/rq/internal/rq/yaml"
+)
+
+// RunFunc executes one rq file with the given variables and returns an error
+// when any step fails. It is injected so this package stays independent from
+// the rq runtime.
+type RunFunc func(ctx context.Context, filename string, variables map[string]any) error
+
+// Run replays the first successful example of every converted request from a
+// local server and runs the generated file against it. Requests without a
+// saved example are reported as skipped.
+func Run(ctx context.Context, cfg config.Config, summary report.Summary, run RunFunc) (*report.Verification, error) {
+	file, err := os.Open(cfg.InputFile)
+	if err != nil {
+		return nil, fmt.Errorf("open input file: %w", err)
+	}
+	defer file.Close()
+
+	collection, err := ast.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("parse collection: %w", err)
+	}
+
+	nodes := make(map[string]normalize.RequestNode)
+	for _, node := range normalize.Requests(collection) {
+		nodes[strings.Join(node.FullPath(), "/")] = node
+	}
+
+	server, err := newReplayServer()
+	if err != nil {
+		return nil, err
+	}
+	defer server.Close()
+
+	variables := collectionVariables(collection.Variable)
+	verification := &report.Verification{}
+
+	for _, request := range summary.Requests {
+		if !request.Converted {
+			continue
+		}
+
+		result := report.VerifyResult{
+			SourcePath: request.SourcePath,
+			OutputPath: request.OutputPath,
+		}
+
+		example, ok := nodes[request.SourcePath].Example()
+		if !ok {
+			result.Status = report.VerifySkipped
+			result.Message = "no saved 2xx example response"
+			verification.Add(result)
+			continue
+		}
+
+		server.replay(example)
+		if err := runAgainst(ctx, filepath.Join(cfg.OutputDir, request.OutputPath), server.URL(), variables, run); err != nil {
+			result.Status = report.VerifyFailed
+			result.Message = err.Error()
+		} else {
+			result.Status = report.VerifyPassed
+		}
+		verification.Add(result)
+	}
+
+	return verification, nil
+}
+
+// runAgainst rewrites the generated steps to target the replay server and runs
+// them from a temporary file next to the original, so relative body_file and
+// golden paths still resolve.
+func runAgainst(ctx context.Context, filename string, origin string, variables map[string]any, run RunFunc) error {
+	payload, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("read generated file: %w", err)
+	}
+
+	steps, err := yaml.Parse(strings.NewReader(string(payload)))
+	if err != nil {
+		return fmt.Errorf("parse generated file: %w", err)
+	}
+	for index := range steps {
+		steps[index].URL = replaceOrigin(steps[index].URL, origin)
+	}
+
+	rewritten, err := yaml.EncodeSteps(steps)
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(filename), ".verify-*.yaml")
+	if err != nil {
+		return fmt.Errorf("create verification file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(rewritten); err != nil {
+		temp.Close()
+		return fmt.Errorf("write verification file: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("write verification file: %w", err)
+	}
+
+	return run(ctx, temp.Name(), variables)
+}
+
+// replaceOrigin swaps the scheme and host of a step URL, which is usually a
+// template such as {{.baseUrl}}, for the replay server origin.
+func replaceOrigin(rawURL string, origin string) string {
+	rest := rawURL
+	switch {
+	case strings.HasPrefix(rest, "{{"):
+		if end := strings.Index(rest, "}}"); end >= 0 {
+			rest = rest[end+len("}}"):]
+		}
+	case strings.Contains(rest, "://"):
+		rest = rest[strings.Index(rest, "://")+len("://"):]
+		if slash := strings.IndexAny(rest, "/?"); slash >= 0 {
+			rest = rest[slash:]
+		} else {
+			rest = ""
+		}
+	}
+
+	if rest != "" && !strings.HasPrefix(rest, "/") && !strings.HasPrefix(rest, "?") {
+		rest = "/" + rest
+	}
+
+	return origin + rest
+}
+
+func collectionVariables(definitions []ast.Variable) map[string]any {
+	variables := make(map[string]any, len(definitions))
+	for _, definition := range definitions {
+		if definition.Disabled || strings.TrimSpace(definition.Key) == "" {
+			continue
+		}
+		variables[definition.Key] = definition.Value
+	}
+
+	return variables
+}
+
+// replayServer answers every request with the current example response.
+type replayServer struct {
+	listener net.Listener
+	server   *http.Server
+	current  atomic.Pointer[ast.Response]
+}
+
+func newReplayServer() (*replayServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("start replay server: %w", err)
+	}
+
+	s := &replayServer{listener: listener}
+	s.server = &http.Server{Handler: http.HandlerFunc(s.serveHTTP)}
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+
+	return s, nil
+}
+
+func (s *replayServer) URL() string {
+	return "http://" + s.listener.Addr().String()
+}
+
+func (s *replayServer) replay(example ast.Response) {
+	s.current.Store(&example)
+}
+
+func (s *replayServer) Close() error {
+	return s.server.Close()
+}
+
+func (s *replayServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	example := s.current.Load()
+	if example == nil {
+		http.Error(w, "no example loaded", http.StatusNotFound)
+		return
+	}
+
+	for _, header := range example.Header {
+		if header.Disabled || skipReplayHeader(header.Key) {
+			continue
+		}
+		w.Header().Add(header.Key, header.Value)
+	}
+
+	w.WriteHeader(example.Code)
+	_, _ = w.Write([]byte(example.Body))
+}
+
+// skipReplayHeader drops framing headers that described the original wire
+// encoding; example bodies are stored decoded.
+func skipReplayHeader(key string) bool {
+	switch http.CanonicalHeaderKey(strings.TrimSpace(key)) {
+	case "Content-Length", "Content-Encoding", "Transfer-Encoding", "Connection":
+		return true
+	default:
+		return false
+	}
+}
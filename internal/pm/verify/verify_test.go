@@ -0,0 +1,32 @@
+package verify
+
+import "testing"
+
+func TestReplaceOrigin(t *testing.T) {
+	t.Parallel()
+
+	const origin = "http://127.0.0.1:8080"
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "template base", url: "{{.baseUrl}}/users/{{.id}}", want: origin + "/users/{{.id}}"},
+		{name: "template without slash", url: "{{.baseUrl}}users", want: origin + "/users"},
+		{name: "absolute url", url: "https://api.example.com/v1/users?limit=1", want: origin + "/v1/users?limit=1"},
+		{name: "absolute url without path", url: "https://api.example.com", want: origin},
+		{name: "query only", url: "https://api.example.com?x=1", want: origin + "?x=1"},
+		{name: "bare template", url: "{{.url}}", want: origin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := replaceOrigin(tt.url, origin); got != tt.want {
+				t.Fatalf("replaceOrigin(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Package baseline stores per-step durations from a known-good run and
+// reports steps whose latency regressed against them.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Version is the baseline file format version.
+const Version = 1
+
+// NoiseFloor is the smallest slowdown reported as a regression, so steps
+// taking a few milliseconds do not fail on scheduling jitter.
+const NoiseFloor = 5 * time.Millisecond
+
+// Baseline maps step keys, "file#index", to their recorded duration.
+type Baseline struct {
+	Version int              `json:"version"`
+	Steps   map[string]Entry `json:"steps"`
+}
+
+// Entry is the recorded duration of one step. Name is informational and
+// helps when reading the file.
+type Entry struct {
+	Name       string  `json:"name"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// Duration returns the recorded duration.
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.DurationMS * float64(time.Millisecond))
+}
+
+// Sample collects the durations observed for one step across iterations.
+type Sample struct {
+	Name      string
+	Durations []time.Duration
+}
+
+// Median returns the median duration, which keeps a single slow iteration
+// from skewing the result.
+func (s Sample) Median() time.Duration {
+	if len(s.Durations) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(s.Durations)
+	slices.Sort(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// FromSamples builds a baseline from the median of each sample.
+func FromSamples(samples map[string]Sample) *Baseline {
+	b := &Baseline{Version: Version, Steps: make(map[string]Entry, len(samples))}
+	for key, sample := range samples {
+		if len(sample.Durations) == 0 {
+			continue
+		}
+		b.Steps[key] = Entry{
+			Name:       sample.Name,
+			DurationMS: float64(sample.Median().Microseconds()) / 1000,
+		}
+	}
+
+	return b
+}
+
+// Load reads a baseline file.
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parse baseline %s: %w", path, err)
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("baseline %s has version %d, expected %d", path, b.Version, Version)
+	}
+
+	return &b, nil
+}
+
+// Save writes the baseline as indented JSON.
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Regression is a step slower than its baseline by more than the threshold.
+type Regression struct {
+	Key      string
+	Name     string
+	Baseline time.Duration
+	Current  time.Duration
+}
+
+// Increase returns the slowdown as a percentage of the baseline.
+func (r Regression) Increase() float64 {
+	if r.Baseline <= 0 {
+		return 0
+	}
+
+	return (float64(r.Current) - float64(r.Baseline)) / float64(r.Baseline) * 100
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s (%s): %d ms vs baseline %d ms (+%.1f%%)",
+		r.Key, r.Name, r.Current.Milliseconds(), r.Baseline.Milliseconds(), r.Increase())
+}
+
+// Compare returns the steps whose median duration exceeds the baseline by
+// more than thresholdPercent, ordered by key. Steps missing from either side
+// are ignored.
+func (b *Baseline) Compare(samples map[string]Sample, thresholdPercent float64) []Regression {
+	var regressions []Regression
+	for key, sample := range samples {
+		entry, ok := b.Steps[key]
+		if !ok || len(sample.Durations) == 0 {
+			continue
+		}
+
+		recorded := entry.Duration()
+		current := sample.Median()
+		limit := time.Duration(float64(recorded) * (1 + thresholdPercent/100))
+		if current <= limit || current-recorded < NoiseFloor {
+			continue
+		}
+
+		regressions = append(regressions, Regression{
+			Key:      key,
+			Name:     sample.Name,
+			Baseline: recorded,
+			Current:  current,
+		})
+	}
+
+	slices.SortFunc(regressions, func(a, b Regression) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return regressions
+}
@@ -0,0 +1,87 @@
+package baseline
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSampleMedian(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		want      time.Duration
+	}{
+		{name: "empty", want: 0},
+		{name: "odd", durations: []time.Duration{30, 10, 20}, want: 20},
+		{name: "even", durations: []time.Duration{40, 10, 20, 30}, want: 25},
+		{name: "outlier", durations: []time.Duration{10, 900, 12}, want: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := (Sample{Durations: tt.durations}).Median(); got != tt.want {
+				t.Fatalf("Median() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	want := FromSamples(map[string]Sample{
+		"a.yaml#0": {Name: "step 0 GET /", Durations: []time.Duration{100 * time.Millisecond, 120 * time.Millisecond, 110 * time.Millisecond}},
+		"a.yaml#1": {Name: "step 1 GET /none"},
+	})
+
+	if err := want.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	expected := &Baseline{Version: Version, Steps: map[string]Entry{
+		"a.yaml#0": {Name: "step 0 GET /", DurationMS: 110},
+	}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Load() = %+v, want %+v", got, expected)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	recorded := &Baseline{Version: Version, Steps: map[string]Entry{
+		"a#0": {DurationMS: 100},
+		"a#1": {DurationMS: 100},
+		"a#2": {DurationMS: 2},
+		"a#3": {DurationMS: 100},
+	}}
+	samples := map[string]Sample{
+		"a#0": {Name: "fast", Durations: []time.Duration{110 * time.Millisecond}},
+		"a#1": {Name: "slow", Durations: []time.Duration{150 * time.Millisecond}},
+		"a#2": {Name: "tiny", Durations: []time.Duration{5 * time.Millisecond}},
+		"b#0": {Name: "new", Durations: []time.Duration{time.Second}},
+	}
+
+	got := recorded.Compare(samples, 20)
+	want := []Regression{{Key: "a#1", Name: "slow", Baseline: 100 * time.Millisecond, Current: 150 * time.Millisecond}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Compare() = %+v, want %+v", got, want)
+	}
+	if increase := got[0].Increase(); increase != 50 {
+		t.Fatalf("Increase() = %v, want 50", increase)
+	}
+	if s := got[0].String(); s != "a#1 (slow): 150 ms vs baseline 100 ms (+50.0%)" {
+		t.Fatalf("String() = %q", s)
+	}
+}
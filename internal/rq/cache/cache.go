@@ -0,0 +1,133 @@
+// Package cache stores responses of cacheable steps on disk, so repeated
+// runs reuse them instead of sending the request again.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Version is the cache entry format version. Entries of other versions are
+// treated as misses.
+const Version = 1
+
+// Entry is a stored response.
+type Entry struct {
+	Version int         `json:"version"`
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+	Stored  time.Time   `json:"stored"`
+}
+
+// Store keeps one file per entry in a directory.
+type Store struct {
+	dir string
+}
+
+// New returns a store in dir. The directory is created on the first save.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Key identifies a request by method, URL and headers. Header names are
+// canonicalised and sorted, so the order they were set in does not matter.
+func Key(method string, url string, header http.Header) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n", strings.ToUpper(method), url)
+
+	canonical := make(http.Header, len(header))
+	for name, values := range header {
+		key := http.CanonicalHeaderKey(name)
+		canonical[key] = append(canonical[key], values...)
+	}
+	names := slices.Sorted(maps.Keys(canonical))
+	for _, name := range names {
+		fmt.Fprintf(hash, "%s: %s\n", name, strings.Join(canonical[name], ", "))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+// Load returns the entry stored under key. A missing entry, or one of
+// another format version, is reported as not found without an error.
+func (s *Store) Load(key string) (*Entry, bool, error) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false, fmt.Errorf("parse cache entry %s: %w", s.path(key), err)
+	}
+	if entry.Version != Version {
+		return nil, false, nil
+	}
+
+	return &entry, true, nil
+}
+
+// Save stores entry under key. The file is written under a temporary name
+// and renamed, so files running in parallel never read a partial entry.
+func (s *Store) Save(key string, entry *Entry) error {
+	entry.Version = Version
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path(key))
+}
+
+// Response rebuilds the stored response for req.
+func (e *Entry) Response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          http.NoBody,
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
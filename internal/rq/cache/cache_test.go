@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	base := Key("GET", "https://example.com/a", http.Header{"Accept": {"application/json"}, "X-Trace": {"1"}})
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		header   http.Header
+		wantSame bool
+	}{
+		{name: "header_order_and_case", method: "get", url: "https://example.com/a", header: http.Header{"x-trace": {"1"}, "accept": {"application/json"}}, wantSame: true},
+		{name: "different_url", method: "GET", url: "https://example.com/b", header: http.Header{"Accept": {"application/json"}, "X-Trace": {"1"}}},
+		{name: "different_method", method: "HEAD", url: "https://example.com/a", header: http.Header{"Accept": {"application/json"}, "X-Trace": {"1"}}},
+		{name: "different_header_value", method: "GET", url: "https://example.com/a", header: http.Header{"Accept": {"application/json"}, "X-Trace": {"2"}}},
+		{name: "missing_header", method: "GET", url: "https://example.com/a", header: http.Header{"Accept": {"application/json"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Key(tt.method, tt.url, tt.header)
+			if (got == base) != tt.wantSame {
+				t.Errorf("Key() = %s, base %s, want same %v", got, base, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	store := New(filepath.Join(t.TempDir(), "cache"))
+	key := Key("GET", "https://example.com/a", nil)
+
+	if _, found, err := store.Load(key); err != nil || found {
+		t.Fatalf("Load() on empty store = found %v, error %v", found, err)
+	}
+
+	entry := &Entry{
+		Method: "GET",
+		URL:    "https://example.com/a",
+		Status: http.StatusOK,
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   []byte(`{"ok":true}`),
+	}
+	if err := store.Save(key, entry); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, found, err := store.Load(key)
+	if err != nil || !found {
+		t.Fatalf("Load() = found %v, error %v", found, err)
+	}
+	if got.Status != http.StatusOK || string(got.Body) != `{"ok":true}` || got.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Load() = %+v", got)
+	}
+
+	resp := got.Response(nil)
+	if resp.StatusCode != http.StatusOK || resp.Status != "200 OK" || resp.ContentLength != int64(len(got.Body)) {
+		t.Errorf("Response() = %+v", resp)
+	}
+}
+
+func TestStoreLoadOtherVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := New(dir)
+	key := Key("GET", "https://example.com/a", nil)
+	if err := os.WriteFile(filepath.Join(dir, key+".json"), []byte(`{"version":0,"status":200}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found, err := store.Load(key); err != nil || found {
+		t.Errorf("Load() = found %v, error %v, want a miss", found, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, key+".json"), []byte(`not json`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.Load(key); err == nil {
+		t.Error("Load() of a corrupt entry error = nil")
+	}
+}
@@ -1,6 +1,7 @@
 package capture
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"time"
@@ -42,6 +43,29 @@ func ExtractAllCertificateFields(resp *http.Response) (*CertificateInfo, error)
 	}, nil
 }
 
+// ExtractTLSField returns the negotiated protocol version (e.g. "TLS 1.3"),
+// cipher suite name or ALPN protocol of the response connection.
+func ExtractTLSField(resp *http.Response, field string) (string, error) {
+	if resp == nil {
+		return "", fmt.Errorf("%w: response is nil", ErrInvalidInput)
+	}
+
+	if resp.TLS == nil {
+		return "", ErrNotFound
+	}
+
+	switch field {
+	case model.TLSFieldVersion:
+		return tls.VersionName(resp.TLS.Version), nil
+	case model.TLSFieldCipher:
+		return tls.CipherSuiteName(resp.TLS.CipherSuite), nil
+	case model.TLSFieldALPN:
+		return resp.TLS.NegotiatedProtocol, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported tls field: %s", ErrInvalidInput, field)
+	}
+}
+
 func ExtractCertificateField(resp *http.Response, field string) (any, error) {
 	certInfo, err := ExtractAllCertificateFields(resp)
 	if err != nil {
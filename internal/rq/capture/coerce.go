@@ -0,0 +1,119 @@
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/number"
+)
+
+// Coerce converts a captured value to the requested capture type. An empty
+// kind returns the value unchanged; missing values stay nil so exists-style
+// checks keep working. Blank strings count as missing for the int, float,
+// bool and json kinds, while the string kind keeps them as empty text.
+func Coerce(value any, kind string) (any, error) {
+	if kind == "" || value == nil {
+		return value, nil
+	}
+	if kind == model.CaptureTypeString {
+		return coerceString(value)
+	}
+
+	text, isString := value.(string)
+	if isString {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, nil
+		}
+	}
+
+	switch kind {
+	case model.CaptureTypeInt:
+		if isString {
+			parsed, err := strconv.Atoi(text)
+			if err != nil {
+				return nil, coerceError(value, kind)
+			}
+			return parsed, nil
+		}
+		if jsonNumber, ok := value.(json.Number); ok {
+			if parsed, err := jsonNumber.Int64(); err == nil {
+				return int(parsed), nil
+			}
+		}
+		// float64(math.MaxInt64) rounds up to 2^63, which no longer fits.
+		if parsed, ok := number.ToFloat64(value); ok && parsed == math.Trunc(parsed) &&
+			parsed >= math.MinInt64 && parsed < math.MaxInt64 {
+			return int(parsed), nil
+		}
+	case model.CaptureTypeFloat:
+		if isString {
+			parsed, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, coerceError(value, kind)
+			}
+			return parsed, nil
+		}
+		if parsed, ok := number.ToFloat64(value); ok {
+			return parsed, nil
+		}
+	case model.CaptureTypeBool:
+		if isString {
+			parsed, err := strconv.ParseBool(text)
+			if err != nil {
+				return nil, coerceError(value, kind)
+			}
+			return parsed, nil
+		}
+		if parsed, ok := value.(bool); ok {
+			return parsed, nil
+		}
+	case model.CaptureTypeJSON:
+		if !isString {
+			return value, nil
+		}
+		var parsed any
+		if err := json.Unmarshal([]byte(text), &parsed); err != nil {
+			return nil, coerceError(value, kind)
+		}
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported capture type %q", ErrInvalidInput, kind)
+	}
+
+	return nil, coerceError(value, kind)
+}
+
+// coerceString renders value as text: numbers in plain decimal notation,
+// so 1000000 never becomes 1e+06, and objects and arrays as JSON.
+func coerceString(value any) (any, error) {
+	switch current := value.(type) {
+	case string:
+		return current, nil
+	case json.Number:
+		return current.String(), nil
+	case bool:
+		return strconv.FormatBool(current), nil
+	case float32:
+		return strconv.FormatFloat(float64(current), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(current, 'f', -1, 64), nil
+	}
+	if _, ok := number.ToFloat64(value); ok {
+		return fmt.Sprint(value), nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, coerceError(value, model.CaptureTypeString)
+	}
+	return string(encoded), nil
+}
+
+func coerceError(value any, kind string) error {
+	return fmt.Errorf("%w: cannot convert %v (%T) to %s", ErrExtraction, value, value, kind)
+}
@@ -0,0 +1,70 @@
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCoerce(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   any
+		kind    string
+		want    any
+		wantErr error
+	}{
+		{name: "no type keeps value", value: "42", kind: "", want: "42"},
+		{name: "string to int", value: " 42 ", kind: "int", want: 42},
+		{name: "integral float to int", value: float64(7), kind: "int", want: 7},
+		{name: "fractional float to int", value: 7.5, kind: "int", wantErr: ErrExtraction},
+		{name: "huge float to int", value: 1e300, kind: "int", wantErr: ErrExtraction},
+		{name: "float above int64 to int", value: 9.3e18, kind: "int", wantErr: ErrExtraction},
+		{name: "float at 2^63 to int", value: float64(math.MaxInt64), kind: "int", wantErr: ErrExtraction},
+		{name: "smallest int64 float to int", value: float64(math.MinInt64), kind: "int", want: math.MinInt64},
+		{name: "invalid int", value: "abc", kind: "int", wantErr: ErrExtraction},
+		{name: "string to float", value: "1.25", kind: "float", want: 1.25},
+		{name: "int to float", value: 3, kind: "float", want: float64(3)},
+		{name: "string to bool", value: "true", kind: "bool", want: true},
+		{name: "invalid bool", value: "yes", kind: "bool", wantErr: ErrExtraction},
+		{name: "string to json", value: `{"ids":[1,2]}`, kind: "json", want: map[string]any{"ids": []any{float64(1), float64(2)}}},
+		{name: "json keeps typed value", value: []any{"a"}, kind: "json", want: []any{"a"}},
+		{name: "invalid json", value: "{", kind: "json", wantErr: ErrExtraction},
+		{name: "json number to int", value: json.Number("9007199254740993"), kind: "int", want: 9007199254740993},
+		{name: "json number to string", value: json.Number("1.50"), kind: "string", want: "1.50"},
+		{name: "large float to string", value: float64(1000000), kind: "string", want: "1000000"},
+		{name: "int to string", value: int64(42), kind: "string", want: "42"},
+		{name: "bool to string", value: true, kind: "string", want: "true"},
+		{name: "string keeps text", value: " 007 ", kind: "string", want: " 007 "},
+		{name: "object to string", value: map[string]any{"id": 1}, kind: "string", want: `{"id":1}`},
+		{name: "empty string stays missing", value: "", kind: "int", want: nil},
+		{name: "empty string to string", value: "", kind: "string", want: ""},
+		{name: "blank string to string", value: "  ", kind: "string", want: "  "},
+		{name: "nil stays missing", value: nil, kind: "bool", want: nil},
+		{name: "unknown type", value: "1", kind: "decimal", wantErr: ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Coerce(tt.value, tt.kind)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Coerce() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Coerce() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Coerce() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
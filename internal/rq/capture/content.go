@@ -1,27 +1,131 @@
 package capture
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"strings"
 
+	"github.com/jacoelho/rq/internal/rq/css"
+	"github.com/jacoelho/rq/internal/rq/markup"
+	"github.com/jacoelho/rq/internal/rq/xpath"
 	"github.com/theory/jsonpath"
 )
 
 // ParseJSONBody decodes a JSON response payload once so multiple selectors can reuse it.
 func ParseJSONBody(body []byte) (any, error) {
+	return parseJSONBody(body, false)
+}
+
+// ParseJSONBodyNumbers is ParseJSONBody keeping numbers as json.Number, so
+// integers beyond 2^53 are not rounded.
+func ParseJSONBodyNumbers(body []byte) (any, error) {
+	return parseJSONBody(body, true)
+}
+
+func parseJSONBody(body []byte, useNumber bool) (any, error) {
 	if len(body) == 0 {
 		return nil, fmt.Errorf("%w: body is empty", ErrInvalidInput)
 	}
 
 	var data any
-	if err := json.Unmarshal(body, &data); err != nil {
+	if err := unmarshalJSON(body, &data, useNumber); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse JSON data: %v", ErrExtraction, err)
+	}
+
+	return data, nil
+}
+
+// unmarshalJSON is json.Unmarshal, optionally decoding numbers as
+// json.Number.
+func unmarshalJSON(data []byte, v *any, useNumber bool) error {
+	if !useNumber {
+		return json.Unmarshal(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// utf8BOM is the byte order mark some legacy services prepend to UTF-8 bodies.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ParseJSONBodyLenient is ParseJSONBody for misbehaving services: it strips a
+// UTF-8 byte order mark and decodes only the first complete top-level value,
+// ignoring anything that follows it.
+func ParseJSONBodyLenient(body []byte) (any, error) {
+	return parseJSONBodyLenient(body, false)
+}
+
+// ParseJSONBodyLenientNumbers is ParseJSONBodyLenient keeping numbers as
+// json.Number.
+func ParseJSONBodyLenientNumbers(body []byte) (any, error) {
+	return parseJSONBodyLenient(body, true)
+}
+
+func parseJSONBodyLenient(body []byte, useNumber bool) (any, error) {
+	body = bytes.TrimPrefix(body, utf8BOM)
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, fmt.Errorf("%w: body is empty", ErrInvalidInput)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if useNumber {
+		decoder.UseNumber()
+	}
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
 		return nil, fmt.Errorf("%w: failed to parse JSON data: %v", ErrExtraction, err)
 	}
 
 	return data, nil
 }
 
+// ParseJSONLines decodes a newline-delimited JSON (NDJSON, JSON Lines) payload
+// into a list with one element per document, so $[0] selects the first line.
+// Blank lines are skipped and errors name the offending line.
+func ParseJSONLines(body []byte) (any, error) {
+	return parseJSONLines(body, false)
+}
+
+// ParseJSONLinesNumbers is ParseJSONLines keeping numbers as json.Number.
+func ParseJSONLinesNumbers(body []byte) (any, error) {
+	return parseJSONLines(body, true)
+}
+
+func parseJSONLines(body []byte, useNumber bool) (any, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, fmt.Errorf("%w: body is empty", ErrInvalidInput)
+	}
+
+	documents := []any{}
+	for number, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var document any
+		if err := unmarshalJSON(line, &document, useNumber); err != nil {
+			return nil, fmt.Errorf("%w: failed to parse JSON line %d: %v", ErrExtraction, number+1, err)
+		}
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
 // ExtractJSONPathFromData selects the first value matching pathExpr from decoded JSON data.
 func ExtractJSONPathFromData(data any, pathExpr string) (any, error) {
 	if pathExpr == "" {
@@ -33,7 +137,10 @@ func ExtractJSONPathFromData(data any, pathExpr string) (any, error) {
 		return nil, fmt.Errorf("%w: invalid JSONPath %s: %v", ErrExtraction, pathExpr, err)
 	}
 
-	results := path.Select(data)
+	results, err := selectJSONPath(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: JSONPath %s: %v", ErrExtraction, pathExpr, err)
+	}
 	if len(results) > 0 {
 		return results[0], nil
 	}
@@ -41,6 +148,20 @@ func ExtractJSONPathFromData(data any, pathExpr string) (any, error) {
 	return nil, ErrNotFound
 }
 
+// selectJSONPath evaluates path, turning a panic inside the JSONPath engine
+// into an error. The engine panics when match() or search() is given a member
+// that some filtered values lack; guarding the argument, as in
+// [?@.name && match(@.name, 'a.*')], avoids it.
+func selectJSONPath(path *jsonpath.Path, data any) (results []any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("evaluation failed (%v); guard optional members passed to match() or search(), e.g. @.name && match(@.name, ...)", r)
+		}
+	}()
+
+	return path.Select(data), nil
+}
+
 // ExtractJSONPathFromDataString converts non-string values using fmt.Sprintf.
 func ExtractJSONPathFromDataString(data any, pathExpr string) (string, error) {
 	result, err := ExtractJSONPathFromData(data, pathExpr)
@@ -55,6 +176,54 @@ func ExtractJSONPathFromDataString(data any, pathExpr string) (string, error) {
 	return fmt.Sprintf("%v", result), nil
 }
 
+// ExtractXPath evaluates an XPath expression against a parsed XML or HTML
+// document (see the xpath package for the supported subset).
+func ExtractXPath(doc *markup.Node, pathExpr string) (any, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("%w: document is nil", ErrInvalidInput)
+	}
+
+	expr, err := xpath.Compile(pathExpr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExtraction, err)
+	}
+
+	value, found := expr.Evaluate(doc)
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	return value, nil
+}
+
+// ExtractCSS returns the text, with whitespace runs collapsed, of the first
+// element of doc matching selector, or the value of its attribute attr when
+// attr is set.
+func ExtractCSS(doc *markup.Node, selector, attr string) (any, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("%w: document is nil", ErrInvalidInput)
+	}
+
+	compiled, err := css.Compile(selector)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExtraction, err)
+	}
+
+	element := compiled.MatchFirst(doc)
+	if element == nil {
+		return nil, ErrNotFound
+	}
+	if attr == "" {
+		return strings.Join(strings.Fields(element.Text()), " "), nil
+	}
+
+	value, ok := element.Attr(attr)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
 // ExtractJSONPath supports standard JSONPath syntax (e.g., "$.user.name", "$..items[0]").
 func ExtractJSONPath(body []byte, pathExpr string) (any, error) {
 	data, err := ParseJSONBody(body)
@@ -8,6 +8,8 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -78,6 +80,120 @@ func TestParseJSONBody(t *testing.T) {
 	}
 }
 
+func TestParseJSONBodyLenient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		body      []byte
+		want      any
+		wantError bool
+	}{
+		{
+			name: "plain JSON",
+			body: []byte(`{"id":1}`),
+			want: map[string]any{"id": float64(1)},
+		},
+		{
+			name: "UTF-8 BOM",
+			body: []byte("\xEF\xBB\xBF{\"id\":1}"),
+			want: map[string]any{"id": float64(1)},
+		},
+		{
+			name: "trailing garbage",
+			body: []byte(`{"id":1}<!-- served by legacy -->`),
+			want: map[string]any{"id": float64(1)},
+		},
+		{
+			name: "second top-level value is ignored",
+			body: []byte("[1]\n[2]"),
+			want: []any{float64(1)},
+		},
+		{
+			name:      "only BOM",
+			body:      []byte("\xEF\xBB\xBF"),
+			wantError: true,
+		},
+		{
+			name:      "truncated document",
+			body:      []byte(`{"id":`),
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseJSONBodyLenient(tt.body)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseJSONBodyLenient() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseJSONBodyLenient() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		body      []byte
+		want      any
+		wantError string
+	}{
+		{
+			name: "one document per line",
+			body: []byte("{\"id\":1}\n{\"id\":2}\n"),
+			want: []any{map[string]any{"id": float64(1)}, map[string]any{"id": float64(2)}},
+		},
+		{
+			name: "blank lines and CRLF",
+			body: []byte("[1]\r\n\r\n\"x\"\r\n"),
+			want: []any{[]any{float64(1)}, "x"},
+		},
+		{
+			name:      "invalid line is reported by number",
+			body:      []byte("{\"id\":1}\n\n{\"id\":"),
+			wantError: "line 3",
+		},
+		{
+			name:      "empty body",
+			body:      []byte("\n\n"),
+			wantError: "body is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseJSONLines(tt.body)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("ParseJSONLines() error = %v, want it to contain %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseJSONLines() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseJSONLines() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExtractJSONPath(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -241,6 +357,169 @@ func TestExtractJSONPathFromData(t *testing.T) {
 	}
 }
 
+func TestExtractJSONPathFilters(t *testing.T) {
+	t.Parallel()
+
+	data, err := ParseJSONBody([]byte(`{
+		"expensive": 10,
+		"store": {"book": [
+			{"title": "Sayings", "price": 8.95, "available": false},
+			{"title": "Sword", "price": 12.99, "available": true},
+			{"title": "Moby", "price": 8.99, "available": true}
+		]}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseJSONBody() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		want       any
+		isNotFound bool
+	}{
+		{
+			name: "root reference in comparison",
+			path: "$.store.book[?(@.price < $.expensive)].title",
+			want: "Sayings",
+		},
+		{
+			name: "root reference without parentheses",
+			path: "$.store.book[?@.price > $.expensive].title",
+			want: "Sword",
+		},
+		{
+			name:       "root reference to missing member matches nothing",
+			path:       "$.store.book[?@.price < $.cheap].title",
+			isNotFound: true,
+		},
+		{
+			name: "logical and",
+			path: "$.store.book[?(@.price < 10 && @.available == true)].title",
+			want: "Moby",
+		},
+		{
+			name: "logical or",
+			path: "$.store.book[?@.price > 12 || @.title == 'Moby'].title",
+			want: "Sword",
+		},
+		{
+			name: "logical not",
+			path: "$.store.book[?!(@.available == true)].title",
+			want: "Sayings",
+		},
+		{
+			name: "grouped operators",
+			path: "$.store.book[?(@.price < 9 || @.price > 12) && @.available == true].title",
+			want: "Sword",
+		},
+		{
+			name:       "logical and matching nothing",
+			path:       "$.store.book[?@.price > 12 && @.available == false].title",
+			isNotFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExtractJSONPathFromData(data, tt.path)
+			if tt.isNotFound {
+				if !IsNotFound(err) {
+					t.Fatalf("expected ErrNotFound, got %v (%v)", err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractJSONPathFromData() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("ExtractJSONPathFromData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractJSONPathFunctions(t *testing.T) {
+	t.Parallel()
+
+	data, err := ParseJSONBody([]byte(`{"users": [
+		{"name": "alice", "tags": ["admin", "ops", "dev"]},
+		{"name": "bob", "tags": ["dev"]},
+		{"id": 3, "tags": []}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseJSONBody() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		want       any
+		isNotFound bool
+		wantErr    bool
+	}{
+		{
+			name: "length of array",
+			path: "$.users[?length(@.tags) > 2].name",
+			want: "alice",
+		},
+		{
+			name: "length of string",
+			path: "$.users[?length(@.name) == 3].name",
+			want: "bob",
+		},
+		{
+			name: "count of nodes",
+			path: "$.users[?count(@.tags[*]) == 1].name",
+			want: "bob",
+		},
+		{
+			name:       "length of missing member matches nothing",
+			path:       "$.users[?length(@.email) > 0].name",
+			isNotFound: true,
+		},
+		{
+			name: "match whole string",
+			path: "$.users[?@.name && match(@.name, 'b.b')].name",
+			want: "bob",
+		},
+		{
+			name: "search substring",
+			path: "$.users[?@.name && search(@.name, 'lic')].name",
+			want: "alice",
+		},
+		{
+			name:    "match on missing member is an error",
+			path:    "$.users[?match(@.name, 'b.b')].name",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExtractJSONPathFromData(data, tt.path)
+			switch {
+			case tt.isNotFound:
+				if !IsNotFound(err) {
+					t.Fatalf("expected ErrNotFound, got %v (%v)", err, got)
+				}
+			case tt.wantErr:
+				if !errors.Is(err, ErrExtraction) {
+					t.Fatalf("expected ErrExtraction, got %v (%v)", err, got)
+				}
+			case err != nil:
+				t.Fatalf("ExtractJSONPathFromData() error = %v", err)
+			case got != tt.want:
+				t.Fatalf("ExtractJSONPathFromData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExtractJSONPathString(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 func ExtractStatusCode(resp *http.Response) (int, error) {
@@ -13,6 +15,15 @@ func ExtractStatusCode(resp *http.Response) (int, error) {
 	return resp.StatusCode, nil
 }
 
+// ExtractURL returns the URL of the request that produced resp, after
+// redirects and normalization.
+func ExtractURL(resp *http.Response) (string, error) {
+	if resp == nil || resp.Request == nil || resp.Request.URL == nil {
+		return "", fmt.Errorf("%w: response has no request URL", ErrInvalidInput)
+	}
+	return resp.Request.URL.String(), nil
+}
+
 // ExtractHeader matching is case-insensitive per HTTP specifications.
 func ExtractHeader(resp *http.Response, headerName string) (string, error) {
 	if resp == nil {
@@ -31,6 +42,97 @@ func ExtractHeader(resp *http.Response, headerName string) (string, error) {
 	return headerValue, nil
 }
 
+// ExtractCookie returns an attribute of the first cookie named name that the
+// response sets: the value (the default), domain, path, expires as an RFC 3339
+// UTC time, max_age in seconds, secure, http_only or same_site. Attributes
+// the cookie does not set are not found.
+func ExtractCookie(resp *http.Response, name, attribute string) (any, error) {
+	if resp == nil {
+		return nil, fmt.Errorf("%w: response is nil", ErrInvalidInput)
+	}
+
+	var cookie *http.Cookie
+	for _, candidate := range resp.Cookies() {
+		if candidate.Name == name {
+			cookie = candidate
+			break
+		}
+	}
+	if cookie == nil {
+		return nil, ErrNotFound
+	}
+
+	switch attribute {
+	case "", "value":
+		return cookie.Value, nil
+	case "domain":
+		return notEmpty(cookie.Domain)
+	case "path":
+		return notEmpty(cookie.Path)
+	case "expires":
+		if cookie.Expires.IsZero() {
+			return nil, ErrNotFound
+		}
+		return cookie.Expires.UTC().Format(time.RFC3339), nil
+	case "max_age":
+		switch {
+		case cookie.MaxAge == 0:
+			return nil, ErrNotFound
+		case cookie.MaxAge < 0:
+			return 0, nil
+		}
+		return cookie.MaxAge, nil
+	case "secure":
+		return cookie.Secure, nil
+	case "http_only":
+		return cookie.HttpOnly, nil
+	case "same_site":
+		switch cookie.SameSite {
+		case http.SameSiteLaxMode:
+			return "Lax", nil
+		case http.SameSiteStrictMode:
+			return "Strict", nil
+		case http.SameSiteNoneMode:
+			return "None", nil
+		}
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("%w: unsupported cookie attribute %q", ErrInvalidInput, attribute)
+	}
+}
+
+func notEmpty(value string) (any, error) {
+	if value == "" {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+// ExtractAllowedMethods parses the Allow header into an uppercase method set.
+// Repeated Allow headers are merged.
+func ExtractAllowedMethods(resp *http.Response) (map[string]struct{}, error) {
+	if resp == nil {
+		return nil, fmt.Errorf("%w: response is nil", ErrInvalidInput)
+	}
+
+	values := resp.Header.Values("Allow")
+	if len(values) == 0 {
+		return nil, ErrNotFound
+	}
+
+	methods := make(map[string]struct{})
+	for _, value := range values {
+		for method := range strings.SplitSeq(value, ",") {
+			method = strings.ToUpper(strings.TrimSpace(method))
+			if method != "" {
+				methods[method] = struct{}{}
+			}
+		}
+	}
+
+	return methods, nil
+}
+
 // ExtractAllHeaders handles multi-value headers and returns a defensive copy.
 func ExtractAllHeaders(resp *http.Response) (map[string][]string, error) {
 	if resp == nil {
@@ -1,7 +1,10 @@
 package capture
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -55,6 +58,27 @@ func TestExtractStatusCode(t *testing.T) {
 	}
 }
 
+func TestExtractURL(t *testing.T) {
+	t.Parallel()
+
+	requestURL, err := url.Parse("https://xn--mnchen-3ya.de/stra%C3%9Fe")
+	if err != nil {
+		t.Fatalf("parse URL: %v", err)
+	}
+
+	got, err := ExtractURL(&http.Response{Request: &http.Request{URL: requestURL}})
+	if err != nil {
+		t.Fatalf("ExtractURL() error = %v", err)
+	}
+	if got != "https://xn--mnchen-3ya.de/stra%C3%9Fe" {
+		t.Fatalf("ExtractURL() = %q", got)
+	}
+
+	if _, err := ExtractURL(&http.Response{}); err == nil {
+		t.Fatal("expected error for response without request")
+	}
+}
+
 func TestExtractHeader(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -139,6 +163,93 @@ func TestExtractHeader(t *testing.T) {
 	}
 }
 
+func TestExtractAllowedMethods(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		want    map[string]struct{}
+		wantErr error
+	}{
+		{
+			name:   "comma separated with spaces and mixed case",
+			header: http.Header{"Allow": {"get, Post ,OPTIONS"}},
+			want:   map[string]struct{}{"GET": {}, "POST": {}, "OPTIONS": {}},
+		},
+		{
+			name:   "repeated headers are merged",
+			header: http.Header{"Allow": {"GET", "PUT,"}},
+			want:   map[string]struct{}{"GET": {}, "PUT": {}},
+		},
+		{
+			name:    "missing header",
+			header:  http.Header{},
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExtractAllowedMethods(&http.Response{Header: tt.header})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExtractAllowedMethods() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ExtractAllowedMethods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractCookie(t *testing.T) {
+	t.Parallel()
+
+	resp := &http.Response{Header: http.Header{"Set-Cookie": {
+		"sid=abc; Path=/; Domain=example.com; Expires=Wed, 21 Oct 2026 07:28:00 GMT; Secure; HttpOnly; SameSite=Strict",
+		"theme=dark; Max-Age=0",
+		"sid=ignored",
+	}}}
+
+	tests := []struct {
+		name      string
+		cookie    string
+		attribute string
+		want      any
+		wantErr   error
+	}{
+		{name: "value by default", cookie: "sid", want: "abc"},
+		{name: "value", cookie: "sid", attribute: "value", want: "abc"},
+		{name: "domain", cookie: "sid", attribute: "domain", want: "example.com"},
+		{name: "path", cookie: "sid", attribute: "path", want: "/"},
+		{name: "expires", cookie: "sid", attribute: "expires", want: "2026-10-21T07:28:00Z"},
+		{name: "secure", cookie: "sid", attribute: "secure", want: true},
+		{name: "http_only", cookie: "sid", attribute: "http_only", want: true},
+		{name: "same_site", cookie: "sid", attribute: "same_site", want: "Strict"},
+		{name: "max_age zero", cookie: "theme", attribute: "max_age", want: 0},
+		{name: "unset attribute", cookie: "theme", attribute: "expires", wantErr: ErrNotFound},
+		{name: "unset flag", cookie: "theme", attribute: "secure", want: false},
+		{name: "missing cookie", cookie: "lang", wantErr: ErrNotFound},
+		{name: "unsupported attribute", cookie: "sid", attribute: "size", wantErr: ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExtractCookie(resp, tt.cookie, tt.attribute)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExtractCookie() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ExtractCookie() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExtractAllHeaders(t *testing.T) {
 	tests := []struct {
 		name      string
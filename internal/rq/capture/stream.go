@@ -0,0 +1,197 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/theory/jsonpath"
+	"github.com/theory/jsonpath/spec"
+)
+
+// errStreamDone unwinds the streaming walk once enough matches were found.
+var errStreamDone = errors.New("stream limit reached")
+
+// StreamLimit returns up to n values matching pathExpr in the first JSON
+// document read from r; n <= 0 returns every match. Paths made only of
+// member names, non-negative indexes and wildcards, such as $.items[0].id,
+// are evaluated while decoding: values outside the path are skipped without
+// being decoded and reading stops after the n-th match. Other paths decode
+// the whole document first.
+func StreamLimit(ctx context.Context, r io.Reader, pathExpr string, n int) ([]any, error) {
+	if pathExpr == "" {
+		return nil, fmt.Errorf("%w: JSONPath expression is empty", ErrInvalidInput)
+	}
+
+	path, err := jsonpath.Parse(pathExpr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid JSONPath %s: %v", ErrExtraction, pathExpr, err)
+	}
+
+	decoder := json.NewDecoder(r)
+	steps, ok := streamSteps(path.Query())
+	if !ok {
+		return selectDecoded(decoder, path, pathExpr, n)
+	}
+
+	s := &streamer{ctx: ctx, decoder: decoder, limit: n}
+	if err := s.walk(steps); err != nil && !errors.Is(err, errStreamDone) {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: body is empty", ErrInvalidInput)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("%w: failed to parse JSON data: %v", ErrExtraction, err)
+	}
+
+	return s.matches, nil
+}
+
+func selectDecoded(decoder *json.Decoder, path *jsonpath.Path, pathExpr string, n int) ([]any, error) {
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: body is empty", ErrInvalidInput)
+		}
+		return nil, fmt.Errorf("%w: failed to parse JSON data: %v", ErrExtraction, err)
+	}
+
+	results, err := selectJSONPath(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: JSONPath %s: %v", ErrExtraction, pathExpr, err)
+	}
+	if n > 0 && len(results) > n {
+		results = results[:n]
+	}
+
+	return results, nil
+}
+
+// streamStep is one child segment of a streamable path: a member name, an
+// array index or, when wildcard is set, every member or element.
+type streamStep struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// streamSteps converts query into steps, reporting false when it uses
+// descendant segments, unions, slices, filters or negative indexes.
+func streamSteps(query *spec.PathQuery) ([]streamStep, bool) {
+	segments := query.Segments()
+	steps := make([]streamStep, 0, len(segments))
+	for _, segment := range segments {
+		selectors := segment.Selectors()
+		if segment.IsDescendant() || len(selectors) != 1 {
+			return nil, false
+		}
+
+		switch selector := selectors[0].(type) {
+		case spec.Name:
+			steps = append(steps, streamStep{name: string(selector)})
+		case spec.Index:
+			if selector < 0 {
+				return nil, false
+			}
+			steps = append(steps, streamStep{index: int(selector), isIndex: true})
+		case spec.WildcardSelector:
+			steps = append(steps, streamStep{wildcard: true})
+		default:
+			return nil, false
+		}
+	}
+
+	return steps, true
+}
+
+type streamer struct {
+	ctx     context.Context
+	decoder *json.Decoder
+	limit   int
+	matches []any
+}
+
+// walk matches the next value of the decoder against steps.
+func (s *streamer) walk(steps []streamStep) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(steps) == 0 {
+		var value any
+		if err := s.decoder.Decode(&value); err != nil {
+			return err
+		}
+		s.matches = append(s.matches, value)
+		if s.limit > 0 && len(s.matches) >= s.limit {
+			return errStreamDone
+		}
+		return nil
+	}
+
+	token, err := s.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	step := steps[0]
+	switch token {
+	case json.Delim('{'):
+		for s.decoder.More() {
+			key, err := s.decoder.Token()
+			if err != nil {
+				return err
+			}
+			if step.wildcard || (!step.isIndex && key == step.name) {
+				err = s.walk(steps[1:])
+			} else {
+				err = s.skip()
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; s.decoder.More(); i++ {
+			if step.wildcard || (step.isIndex && i == step.index) {
+				err = s.walk(steps[1:])
+			} else {
+				err = s.skip()
+			}
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		// A scalar has no children to select.
+		return nil
+	}
+
+	_, err = s.decoder.Token()
+	return err
+}
+
+// skip consumes the next value without decoding it.
+func (s *streamer) skip() error {
+	depth := 0
+	for {
+		token, err := s.decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStreamLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		body    string
+		path    string
+		limit   int
+		want    []any
+		wantErr error
+	}{
+		{name: "member", body: testJSON, path: "$.user.name", limit: 1, want: []any{"John Doe"}},
+		{name: "index", body: testJSON, path: "$.items[1]", limit: 1, want: []any{"banana"}},
+		{name: "wildcard_limited", body: testJSON, path: "$.items[*]", limit: 2, want: []any{"apple", "banana"}},
+		{name: "wildcard_unlimited", body: testJSON, path: "$.items.*", want: []any{"apple", "banana", "orange"}},
+		{name: "object_value", body: `{"a":{"b":[1,{"c":true}]}}`, path: "$.a.b[1]", limit: 1, want: []any{map[string]any{"c": true}}},
+		{name: "no_match", body: testJSON, path: "$.user.missing", limit: 1, want: nil},
+		{name: "scalar_root", body: `42`, path: "$.a", want: nil},
+		{name: "descendant_falls_back", body: testJSON, path: "$..email", limit: 1, want: []any{"john@example.com"}},
+		{name: "filter_falls_back", body: `[{"id":1},{"id":2},{"id":3}]`, path: "$[?@.id > 1].id", limit: 1, want: []any{float64(2)}},
+		{name: "empty_body", body: "", path: "$.a", wantErr: ErrInvalidInput},
+		{name: "invalid_json", body: `{"a":`, path: "$.a", wantErr: ErrExtraction},
+		{name: "invalid_path", body: testJSON, path: "$[", wantErr: ErrExtraction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := StreamLimit(context.Background(), strings.NewReader(tt.body), tt.path, tt.limit)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("StreamLimit() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StreamLimit() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("StreamLimit() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// failingReader fails the read after its prefix, standing for the rest of a
+// huge document.
+type failingReader struct {
+	prefix io.Reader
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.prefix.Read(p)
+	if errors.Is(err, io.EOF) {
+		return n, errors.New("read past the match")
+	}
+	return n, err
+}
+
+func TestStreamLimitStopsReading(t *testing.T) {
+	t.Parallel()
+
+	r := &failingReader{prefix: strings.NewReader(`{"items":[{"id":"first"},{"id":"second"},`)}
+	got, err := StreamLimit(context.Background(), r, "$.items[0].id", 1)
+	if err != nil {
+		t.Fatalf("StreamLimit() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []any{"first"}) {
+		t.Fatalf("StreamLimit() = %v, want [first]", got)
+	}
+}
+
+func TestStreamLimitCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := StreamLimit(ctx, strings.NewReader(testJSON), "$.user.name", 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("StreamLimit() error = %v, want context.Canceled", err)
+	}
+}
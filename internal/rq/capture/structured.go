@@ -0,0 +1,176 @@
+package capture
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ExtractHeaderLinks parses RFC 8288 Link headers into a map from each link
+// relation to its target URL. Relative targets are resolved against the
+// request URL. A link with several relations, as in rel="prev first", is
+// stored under each of them; the first link of a relation wins. A missing
+// header yields an empty map, so templates can test for a relation.
+func ExtractHeaderLinks(resp *http.Response, headerName string) (map[string]any, error) {
+	if resp == nil {
+		return nil, fmt.Errorf("%w: response is nil", ErrInvalidInput)
+	}
+
+	var base *url.URL
+	if resp.Request != nil {
+		base = resp.Request.URL
+	}
+
+	links := make(map[string]any)
+	for _, value := range resp.Header.Values(headerName) {
+		parsed, err := parseLinks(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s header: %v", ErrExtraction, headerName, err)
+		}
+
+		for _, link := range parsed {
+			target := link.target
+			if base != nil {
+				if ref, err := url.Parse(target); err == nil {
+					target = base.ResolveReference(ref).String()
+				}
+			}
+			for rel := range strings.FieldsSeq(strings.ToLower(link.params["rel"])) {
+				if _, ok := links[rel]; !ok {
+					links[rel] = target
+				}
+			}
+		}
+	}
+
+	return links, nil
+}
+
+// ExtractHeaderList splits comma-separated header values into their items,
+// merging repeated headers. Commas inside double quotes do not split. A
+// missing header yields an empty list.
+func ExtractHeaderList(resp *http.Response, headerName string) ([]any, error) {
+	if resp == nil {
+		return nil, fmt.Errorf("%w: response is nil", ErrInvalidInput)
+	}
+
+	items := []any{}
+	for _, value := range resp.Header.Values(headerName) {
+		for _, item := range splitOutsideQuotes(value) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+type link struct {
+	target string
+	params map[string]string
+}
+
+// parseLinks parses one Link header value: comma-separated
+// <target>; name=value; name="quoted value" entries.
+func parseLinks(value string) ([]link, error) {
+	var links []link
+	rest := value
+	for {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			return links, nil
+		}
+		if rest[0] != '<' {
+			return nil, fmt.Errorf("expected <target> at %q", rest)
+		}
+		end := strings.IndexByte(rest, '>')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated <target> in %q", rest)
+		}
+
+		current := link{target: strings.TrimSpace(rest[1:end]), params: map[string]string{}}
+		rest = strings.TrimLeft(rest[end+1:], " \t")
+		for strings.HasPrefix(rest, ";") {
+			var name, paramValue string
+			var err error
+			name, paramValue, rest, err = parseLinkParam(rest[1:])
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := current.params[name]; !ok && name != "" {
+				current.params[name] = paramValue
+			}
+			rest = strings.TrimLeft(rest, " \t")
+		}
+		if rest != "" && rest[0] != ',' {
+			return nil, fmt.Errorf("unexpected %q after link parameters", rest)
+		}
+		links = append(links, current)
+	}
+}
+
+// parseLinkParam parses name[=value] up to the next ; or , and returns the
+// remaining input. Parameter names are case-insensitive.
+func parseLinkParam(input string) (string, string, string, error) {
+	input = strings.TrimLeft(input, " \t")
+	end := strings.IndexAny(input, "=;,")
+	if end < 0 {
+		return strings.ToLower(strings.TrimSpace(input)), "", "", nil
+	}
+
+	name := strings.ToLower(strings.TrimSpace(input[:end]))
+	if input[end] != '=' {
+		return name, "", input[end:], nil
+	}
+
+	input = strings.TrimLeft(input[end+1:], " \t")
+	if !strings.HasPrefix(input, `"`) {
+		end = strings.IndexAny(input, ";,")
+		if end < 0 {
+			return name, strings.TrimSpace(input), "", nil
+		}
+		return name, strings.TrimSpace(input[:end]), input[end:], nil
+	}
+
+	var value strings.Builder
+	for i := 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			if i+1 < len(input) {
+				i++
+				value.WriteByte(input[i])
+			}
+		case '"':
+			return name, value.String(), input[i+1:], nil
+		default:
+			value.WriteByte(input[i])
+		}
+	}
+	return "", "", "", fmt.Errorf("unterminated quoted value for %s", name)
+}
+
+func splitOutsideQuotes(value string) []string {
+	var (
+		items    []string
+		start    int
+		inQuotes bool
+	)
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if inQuotes {
+				i++
+			}
+		case '"':
+			inQuotes = !inQuotes
+		case ',':
+			if !inQuotes {
+				items = append(items, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(items, value[start:])
+}
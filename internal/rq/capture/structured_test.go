@@ -0,0 +1,109 @@
+package capture
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestExtractHeaderLinks(t *testing.T) {
+	t.Parallel()
+
+	requestURL, _ := url.Parse("https://api.example.com/items?page=2")
+
+	tests := []struct {
+		name    string
+		values  []string
+		want    map[string]any
+		wantErr error
+	}{
+		{
+			name: "github style pagination",
+			values: []string{
+				`<https://api.example.com/items?page=3>; rel="next", <https://api.example.com/items?page=9>; rel="last"`,
+			},
+			want: map[string]any{
+				"next": "https://api.example.com/items?page=3",
+				"last": "https://api.example.com/items?page=9",
+			},
+		},
+		{
+			name:   "relative targets and several relations",
+			values: []string{`</items?page=1>; rel="prev first"; title="a, b"`, `</items?page=3>;rel=next`},
+			want: map[string]any{
+				"prev":  "https://api.example.com/items?page=1",
+				"first": "https://api.example.com/items?page=1",
+				"next":  "https://api.example.com/items?page=3",
+			},
+		},
+		{
+			name:   "first link of a relation wins",
+			values: []string{`</a>; rel=next, </b>; REL="Next"`},
+			want:   map[string]any{"next": "https://api.example.com/a"},
+		},
+		{
+			name:   "missing header",
+			values: nil,
+			want:   map[string]any{},
+		},
+		{
+			name:    "target without brackets",
+			values:  []string{`https://api.example.com/items; rel=next`},
+			wantErr: ErrExtraction,
+		},
+		{
+			name:    "unterminated quote",
+			values:  []string{`</a>; rel="next`},
+			wantErr: ErrExtraction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{
+				Header:  http.Header{"Link": tt.values},
+				Request: &http.Request{URL: requestURL},
+			}
+			got, err := ExtractHeaderLinks(resp, "Link")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExtractHeaderLinks() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ExtractHeaderLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractHeaderList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		values []string
+		want   []any
+	}{
+		{name: "comma separated", values: []string{"gzip, br ,deflate"}, want: []any{"gzip", "br", "deflate"}},
+		{name: "repeated headers", values: []string{"a", "b,", "c"}, want: []any{"a", "b", "c"}},
+		{name: "quoted commas", values: []string{`W/"x,y", "z"`}, want: []any{`W/"x,y"`, `"z"`}},
+		{name: "missing header", values: nil, want: []any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExtractHeaderList(&http.Response{Header: http.Header{"Vary": tt.values}}, "Vary")
+			if err != nil {
+				t.Fatalf("ExtractHeaderList() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ExtractHeaderList() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
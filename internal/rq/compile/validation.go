@@ -1,163 +1,1388 @@
 package compile
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/jacoelho/rq/internal/rq/assert"
+	"github.com/jacoelho/rq/internal/rq/css"
 	"github.com/jacoelho/rq/internal/rq/expr"
 	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/xpath"
 )
 
 var ErrInvalidSpec = errors.New("invalid spec")
 
 func ValidateSteps(steps []model.Step) error {
+	return ValidateStepsWithPositions(steps, nil)
+}
+
+// ValidateStepsWithPositions validates steps like ValidateSteps and appends the
+// YAML line of the offending field, e.g. "asserts.jsonpath[2]: ... (line 37)".
+// positions is indexed like steps and may be shorter or nil.
+func ValidateStepsWithPositions(steps []model.Step, positions []model.Positions) error {
+	names := make(map[string]struct{})
 	for index, step := range steps {
-		if err := ValidateStep(step); err != nil {
-			return fmt.Errorf("%w: step %d: %w", ErrInvalidSpec, index+1, err)
+		err := ValidateStep(step)
+		if err == nil {
+			err = validateStepNames(step, names)
+		}
+		if err == nil {
+			continue
+		}
+
+		if index < len(positions) {
+			if line := positions[index].Line(fieldPath(err)); line > 0 {
+				return fmt.Errorf("%w: step %d: %w (line %d)", ErrInvalidSpec, index+1, err, line)
+			}
+		}
+
+		return fmt.Errorf("%w: step %d: %w", ErrInvalidSpec, index+1, err)
+	}
+
+	return nil
+}
+
+func ValidateStep(step model.Step) error {
+	if step.DocsOnly() {
+		return validateDocsStep(step)
+	}
+	if step.ChecksOnly() {
+		return validateChecksStep(step)
+	}
+
+	if strings.TrimSpace(step.Method) == "" {
+		return &FieldError{Path: "method", Err: errors.New("step method cannot be empty")}
+	}
+
+	if !model.IsSupportedMethod(step.Method) && step.Connect == nil && step.DNS == nil {
+		if !step.Options.AllowCustomMethod {
+			return &FieldError{Path: "method", Err: fmt.Errorf("unsupported HTTP method: %s (set options.allow_custom_method for extension methods)", step.Method)}
+		}
+		if !model.IsMethodToken(step.Method) {
+			return &FieldError{Path: "method", Err: fmt.Errorf("invalid HTTP method: %q", step.Method)}
+		}
+	}
+
+	if strings.TrimSpace(step.URL) == "" {
+		return &FieldError{Path: "url", Err: errors.New("step URL cannot be empty")}
+	}
+
+	if strings.TrimSpace(step.When) != "" {
+		if err := expr.ValidateBoolean(step.When); err != nil {
+			return &FieldError{Path: "when", Err: fmt.Errorf("step when is invalid: %w", err)}
+		}
+	}
+
+	if hasInlineBody(step.Body) && strings.TrimSpace(step.BodyFile) != "" {
+		return &FieldError{Path: "body_file", Err: errors.New("step cannot define both body and body_file")}
+	}
+	if step.BodyFileRaw && strings.TrimSpace(step.BodyFile) == "" {
+		return &FieldError{Path: "body_file_raw", Err: errors.New("body_file_raw requires body_file")}
+	}
+
+	if err := validateMultipart(step); err != nil {
+		return err
+	}
+
+	if step.Options.Retries < 0 {
+		return &FieldError{Path: "options.retries", Err: fmt.Errorf("retries must be >= 0, got: %d", step.Options.Retries)}
+	}
+
+	if err := validateRetryBackoff(step.Options); err != nil {
+		return err
+	}
+
+	if step.Options.JSONLines && step.Options.LenientJSON {
+		return &FieldError{Path: "options.json_lines", Err: errors.New("json_lines cannot be combined with lenient_json")}
+	}
+
+	if err := validateTLSOptions(step.Options.TLS); err != nil {
+		return &FieldError{Path: "options.tls", Err: err}
+	}
+
+	if err := validateHTTPVersion(step); err != nil {
+		return &FieldError{Path: "options.http_version", Err: err}
+	}
+
+	if err := validateExpectContinue(step); err != nil {
+		return err
+	}
+
+	if err := validateOutput(step); err != nil {
+		return err
+	}
+
+	if err := validateCacheable(step); err != nil {
+		return err
+	}
+
+	if err := validatePollJob(step.PollJob); err != nil {
+		return &FieldError{Path: "poll_job", Err: err}
+	}
+
+	if err := validateWebSocket(step); err != nil {
+		return &FieldError{Path: "websocket", Err: err}
+	}
+
+	if err := validateConditional(step); err != nil {
+		return &FieldError{Path: "conditional", Err: err}
+	}
+
+	if err := validateGRPC(step); err != nil {
+		return &FieldError{Path: "grpc", Err: err}
+	}
+
+	if err := validateGraphQL(step); err != nil {
+		return &FieldError{Path: "graphql", Err: err}
+	}
+
+	if err := validateConnect(step); err != nil {
+		return &FieldError{Path: "connect", Err: err}
+	}
+
+	if err := validateDNS(step); err != nil {
+		return &FieldError{Path: "dns", Err: err}
+	}
+
+	if err := validateAuth(step); err != nil {
+		return &FieldError{Path: "auth", Err: err}
+	}
+
+	if err := validateAsserts(step.Asserts); err != nil {
+		return err
+	}
+
+	if err := validateCaptures(step.Captures); err != nil {
+		return err
+	}
+
+	if err := validateChecks(step.Checks); err != nil {
+		return err
+	}
+
+	if err := validateStableAsserts(step.Asserts.Stable, step.Captures); err != nil {
+		return err
+	}
+
+	if err := validateRangeAssert(step); err != nil {
+		return &FieldError{Path: "asserts.range", Err: err}
+	}
+
+	if err := validateExports(step.Exports, step.Captures); err != nil {
+		return err
+	}
+
+	if err := validateAcceptMatrix(step); err != nil {
+		return err
+	}
+
+	if err := validateCleanups(step.RegisterCleanup); err != nil {
+		return err
+	}
+
+	if err := validateForeach(step); err != nil {
+		return &FieldError{Path: "foreach", Err: err}
+	}
+
+	return nil
+}
+
+// FieldError is a validation error for one field of a step. Path uses the
+// YAML key names, e.g. "asserts.jsonpath[2]", so it can be mapped back to a
+// source line.
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+func indexedFieldError(field string, index int, err error) error {
+	return &FieldError{Path: fmt.Sprintf("%s[%d]", field, index), Err: err}
+}
+
+// fieldPath returns the path of the FieldError in err's chain, or "" for the
+// step itself.
+func fieldPath(err error) string {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return fieldErr.Path
+	}
+
+	return ""
+}
+
+func validateTLSOptions(options *model.TLSOptions) error {
+	if options == nil {
+		return nil
+	}
+
+	var minVersion, maxVersion uint16
+	if options.MinVersion != "" {
+		version, err := model.ParseTLSVersion(options.MinVersion)
+		if err != nil {
+			return fmt.Errorf("tls min_version is invalid: %w", err)
+		}
+		minVersion = version
+	}
+	if options.MaxVersion != "" {
+		version, err := model.ParseTLSVersion(options.MaxVersion)
+		if err != nil {
+			return fmt.Errorf("tls max_version is invalid: %w", err)
+		}
+		maxVersion = version
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return fmt.Errorf("tls min_version %s is greater than max_version %s", options.MinVersion, options.MaxVersion)
+	}
+
+	for _, cipher := range options.Ciphers {
+		if _, err := model.ParseCipherSuite(cipher); err != nil {
+			return fmt.Errorf("tls ciphers is invalid: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateHTTPVersion checks options.http_version. WebSocket, gRPC, connect
+// and dns steps pick their own protocol, so the option only applies to plain
+// requests.
+func validateHTTPVersion(step model.Step) error {
+	version := step.Options.HTTPVersion
+	if version == "" {
+		return nil
+	}
+
+	if !model.IsSupportedHTTPVersion(version) {
+		return fmt.Errorf("unsupported http_version: %s (expected 1.1, 2 or 3)", version)
+	}
+	if step.WebSocket != nil || step.GRPC != nil || step.Connect != nil || step.DNS != nil {
+		return errors.New("http_version cannot be combined with websocket, grpc, connect or dns")
+	}
+	if version == model.HTTPVersion3 && strings.HasPrefix(step.URL, "http://") {
+		return fmt.Errorf("http_version 3 runs over QUIC and needs an https:// URL, got: %s", step.URL)
+	}
+
+	return nil
+}
+
+// validateExpectContinue checks that expect_continue has a body to hold back
+// and that continue asserts have a 100-continue handshake to observe.
+func validateExpectContinue(step model.Step) error {
+	if !step.Options.ExpectContinue {
+		if len(step.Asserts.Continue) > 0 {
+			return &FieldError{Path: "asserts.continue", Err: errors.New("continue asserts require options.expect_continue")}
+		}
+		return nil
+	}
+
+	if !hasInlineBody(step.Body) && strings.TrimSpace(step.BodyFile) == "" && step.GraphQL == nil && len(step.Multipart) == 0 {
+		return &FieldError{Path: "options.expect_continue", Err: errors.New("expect_continue requires a request body")}
+	}
+	if step.WebSocket != nil || step.GRPC != nil || step.Connect != nil || step.DNS != nil {
+		return &FieldError{Path: "options.expect_continue", Err: errors.New("expect_continue cannot be combined with websocket, grpc, connect or dns")}
+	}
+
+	return nil
+}
+
+// validateOutput checks that a body streamed to options.output is only read
+// by asserts and captures that work on the file.
+func validateOutput(step model.Step) error {
+	if strings.TrimSpace(step.Options.Output) == "" {
+		if step.Captures != nil && len(step.Captures.Output) > 0 {
+			return &FieldError{Path: "captures.output", Err: errors.New("output captures require options.output")}
+		}
+		return nil
+	}
+
+	if step.PollJob != nil || step.WebSocket != nil || step.GRPC != nil || step.Connect != nil || step.DNS != nil {
+		return &FieldError{Path: "options.output", Err: errors.New("output cannot be combined with poll_job, websocket, grpc, connect or dns")}
+	}
+
+	if step.Options.JSONLines || step.Options.LenientJSON {
+		return &FieldError{Path: "options.output", Err: errors.New("output cannot be combined with json_lines or lenient_json")}
+	}
+
+	asserts := step.Asserts
+	if len(asserts.CSS) > 0 || len(asserts.Golden) > 0 || len(asserts.JSONSchema) > 0 || asserts.GraphQL != nil || asserts.Range != nil {
+		return &FieldError{Path: "options.output", Err: errors.New("output cannot be combined with css, golden, jsonschema, graphql or range asserts; use body asserts")}
+	}
+	if captures := step.Captures; captures != nil {
+		if len(captures.XPath) > 0 || len(captures.CSS) > 0 || len(captures.Regex) > 0 || len(captures.Body) > 0 {
+			return &FieldError{Path: "options.output", Err: errors.New("output cannot be combined with xpath, css, regex or body captures; use output captures")}
+		}
+	}
+
+	return nil
+}
+
+// validateCacheable checks that a cacheable step is a plain GET whose
+// asserts and captures only read the response, not the connection that
+// served it, as a cached response has none.
+func validateCacheable(step model.Step) error {
+	if !step.Options.Cacheable {
+		return nil
+	}
+
+	if !strings.EqualFold(step.Method, model.MethodGet) {
+		return &FieldError{Path: "options.cacheable", Err: fmt.Errorf("cacheable requires a GET step, got: %s", step.Method)}
+	}
+	if step.PollJob != nil || step.WebSocket != nil || step.GRPC != nil || step.Connect != nil || step.DNS != nil || strings.TrimSpace(step.Options.Output) != "" {
+		return &FieldError{Path: "options.cacheable", Err: errors.New("cacheable cannot be combined with poll_job, websocket, grpc, connect, dns or output")}
+	}
+
+	asserts := step.Asserts
+	captures := step.Captures
+	if captures == nil {
+		captures = &model.Captures{}
+	}
+	if len(asserts.Certificate) > 0 || len(asserts.TLS) > 0 || len(asserts.TTFB) > 0 || len(asserts.Duration) > 0 ||
+		len(captures.Certificate) > 0 || len(captures.TLS) > 0 || len(captures.TTFB) > 0 || len(captures.Duration) > 0 {
+		return &FieldError{Path: "options.cacheable", Err: errors.New("cacheable cannot be combined with certificate, tls, ttfb or duration asserts and captures")}
+	}
+
+	return nil
+}
+
+// validateMultipart checks the multipart parts. The body and its Content-Type,
+// which carries the boundary, are built by the runner, so neither may be set
+// explicitly.
+func validateMultipart(step model.Step) error {
+	if len(step.Multipart) == 0 {
+		return nil
+	}
+
+	if hasInlineBody(step.Body) || strings.TrimSpace(step.BodyFile) != "" {
+		return &FieldError{Path: "multipart", Err: errors.New("step cannot define both multipart and body or body_file")}
+	}
+	if step.GraphQL != nil || step.WebSocket != nil || step.GRPC != nil || step.Connect != nil || step.DNS != nil {
+		return &FieldError{Path: "multipart", Err: errors.New("multipart cannot be combined with graphql, websocket, grpc, connect or dns")}
+	}
+	if _, ok := step.Headers.GetFold("Content-Type"); ok {
+		return &FieldError{Path: "multipart", Err: errors.New("multipart sets Content-Type with its boundary; remove the Content-Type header")}
+	}
+
+	for i, part := range step.Multipart {
+		if err := requireField(part.Name, "multipart part", "name"); err != nil {
+			return indexedFieldError("multipart", i, err)
+		}
+		file := strings.TrimSpace(part.File) != ""
+		if file && part.Value != "" {
+			return indexedFieldError("multipart", i, errors.New("multipart part cannot define both value and file"))
+		}
+		if !file && part.Filename != "" {
+			return indexedFieldError("multipart", i, errors.New("multipart part filename requires file"))
+		}
+	}
+
+	return nil
+}
+
+// validateRetryBackoff checks the options that space out retries. They have no
+// effect without retries, so setting them alone is reported as a mistake.
+func validateRetryBackoff(options model.Options) error {
+	if options.RetryBackoff == "" && options.RetryDelay == 0 && options.RetryMaxWait == 0 && options.RetryJitter == 0 {
+		return nil
+	}
+
+	if options.Retries == 0 {
+		return &FieldError{Path: "options.retries", Err: errors.New("retry_backoff, retry_delay, retry_max_wait and retry_jitter require retries")}
+	}
+	if options.RetryBackoff != "" && !model.IsSupportedRetryBackoff(options.RetryBackoff) {
+		return &FieldError{Path: "options.retry_backoff", Err: fmt.Errorf("unsupported retry_backoff: %s (expected constant or exponential)", options.RetryBackoff)}
+	}
+	if options.RetryDelay < 0 {
+		return &FieldError{Path: "options.retry_delay", Err: fmt.Errorf("retry_delay must be >= 0, got: %s", options.RetryDelay)}
+	}
+	if options.RetryBackoff == model.RetryBackoffExponential && options.RetryDelay == 0 {
+		return &FieldError{Path: "options.retry_delay", Err: errors.New("exponential retry_backoff requires a retry_delay")}
+	}
+	if options.RetryMaxWait < 0 {
+		return &FieldError{Path: "options.retry_max_wait", Err: fmt.Errorf("retry_max_wait must be >= 0, got: %s", options.RetryMaxWait)}
+	}
+	if options.RetryJitter < 0 || options.RetryJitter > 1 {
+		return &FieldError{Path: "options.retry_jitter", Err: fmt.Errorf("retry_jitter must be between 0 and 1, got: %v", options.RetryJitter)}
+	}
+
+	return nil
+}
+
+func validateCleanups(cleanups []model.Cleanup) error {
+	for i, cleanup := range cleanups {
+		if err := requireField(cleanup.Method, "register_cleanup", "method"); err != nil {
+			return indexedFieldError("register_cleanup", i, err)
+		}
+		if !model.IsSupportedMethod(cleanup.Method) {
+			return indexedFieldError("register_cleanup", i, fmt.Errorf("unsupported HTTP method: %s", cleanup.Method))
+		}
+		if err := requireField(cleanup.URL, "register_cleanup", "url"); err != nil {
+			return indexedFieldError("register_cleanup", i, err)
+		}
+	}
+
+	return nil
+}
+
+func validatePollJob(poll *model.PollJob) error {
+	if poll == nil {
+		return nil
+	}
+
+	if err := requireField(poll.StatusPath, "poll_job", "status_path"); err != nil {
+		return err
+	}
+	if len(poll.Success) == 0 {
+		return errors.New("poll_job missing required 'success' values")
+	}
+	if poll.Interval < 0 {
+		return fmt.Errorf("poll_job interval must be >= 0, got: %s", poll.Interval)
+	}
+	if poll.Timeout < 0 {
+		return fmt.Errorf("poll_job timeout must be >= 0, got: %s", poll.Timeout)
+	}
+
+	return nil
+}
+
+func validateWebSocket(step model.Step) error {
+	ws := step.WebSocket
+	if ws == nil {
+		return nil
+	}
+
+	if !strings.EqualFold(step.Method, "GET") {
+		return fmt.Errorf("websocket steps must use GET, got: %s", step.Method)
+	}
+	if hasInlineBody(step.Body) || strings.TrimSpace(step.BodyFile) != "" {
+		return errors.New("websocket steps send messages with send, not body or body_file")
+	}
+	if step.PollJob != nil {
+		return errors.New("websocket cannot be combined with poll_job")
+	}
+	if ws.Receive < 0 {
+		return fmt.Errorf("websocket receive must be >= 0, got: %d", ws.Receive)
+	}
+	if ws.Timeout < 0 {
+		return fmt.Errorf("websocket timeout must be >= 0, got: %s", ws.Timeout)
+	}
+
+	return nil
+}
+
+func validateGraphQL(step model.Step) error {
+	graphql := step.GraphQL
+	if graphql == nil {
+		return nil
+	}
+
+	if !strings.EqualFold(step.Method, "POST") {
+		return fmt.Errorf("graphql steps must use POST, got: %s", step.Method)
+	}
+	if hasInlineBody(step.Body) || strings.TrimSpace(step.BodyFile) != "" {
+		return errors.New("graphql steps build their body from query and variables, not body or body_file")
+	}
+	if step.WebSocket != nil || step.GRPC != nil {
+		return errors.New("graphql cannot be combined with websocket or grpc")
+	}
+
+	return requireField(graphql.Query, "graphql", "query")
+}
+
+// validateStepNames checks that step names are unique and that conditional_on
+// refers to an earlier step. names holds the names of the steps before step.
+func validateStepNames(step model.Step, names map[string]struct{}) error {
+	if step.ConditionalOn != "" {
+		if _, ok := names[step.ConditionalOn]; !ok {
+			return &FieldError{Path: "conditional_on", Err: fmt.Errorf("conditional_on must name an earlier step, got: %s", step.ConditionalOn)}
+		}
+	}
+
+	if step.Name == "" {
+		return nil
+	}
+	if _, ok := names[step.Name]; ok {
+		return &FieldError{Path: "name", Err: fmt.Errorf("duplicate step name: %s", step.Name)}
+	}
+	names[step.Name] = struct{}{}
+
+	return nil
+}
+
+func validateConditional(step model.Step) error {
+	if step.ConditionalOn != "" {
+		if step.Conditional != nil {
+			return errors.New("conditional_on cannot be combined with conditional")
+		}
+		etag, date := model.ConditionalHeaders(step.Method)
+		for _, header := range []string{etag, date} {
+			if _, ok := step.Headers.GetFold(header); ok {
+				return fmt.Errorf("conditional_on cannot be combined with an explicit %s header", header)
+			}
+		}
+	}
+
+	conditional := step.Conditional
+	if conditional == nil {
+		return nil
+	}
+
+	ifNoneMatch := strings.TrimSpace(conditional.IfNoneMatch) != ""
+	ifMatch := strings.TrimSpace(conditional.IfMatch) != ""
+	if ifNoneMatch == ifMatch {
+		return errors.New("conditional requires exactly one of if_none_match or if_match")
+	}
+
+	header, _ := conditional.Header()
+	if _, ok := step.Headers.GetFold(header); ok {
+		return fmt.Errorf("conditional cannot be combined with an explicit %s header", header)
+	}
+
+	return nil
+}
+
+func validateGRPC(step model.Step) error {
+	call := step.GRPC
+	if call == nil {
+		return nil
+	}
+
+	if !strings.EqualFold(step.Method, model.MethodPost) {
+		return fmt.Errorf("grpc steps must use POST, got: %s", step.Method)
+	}
+	if err := requireField(call.Service, "grpc", "service"); err != nil {
+		return err
+	}
+	if err := requireField(call.Method, "grpc", "method"); err != nil {
+		return err
+	}
+	if err := requireField(call.Protoset, "grpc", "protoset"); err != nil {
+		return err
+	}
+	if _, err := model.ParseGRPCCode(call.Status); err != nil {
+		return err
+	}
+	if step.PollJob != nil || step.WebSocket != nil {
+		return errors.New("grpc cannot be combined with poll_job or websocket")
+	}
+	if header, _ := step.Options.Matrix(); header != "" {
+		return errors.New("grpc cannot be combined with options.accept_matrix or accept_language_matrix")
+	}
+
+	return nil
+}
+
+func validateConnect(step model.Step) error {
+	connect := step.Connect
+	if connect == nil {
+		return nil
+	}
+
+	if !strings.EqualFold(step.Method, model.MethodConnect) {
+		return fmt.Errorf("connect steps must use CONNECT, got: %s", step.Method)
+	}
+	secure := strings.HasPrefix(step.URL, "tls://")
+	if !secure && !strings.HasPrefix(step.URL, "tcp://") {
+		return fmt.Errorf("connect steps need a tcp:// or tls:// URL, got: %s", step.URL)
+	}
+	if hasInlineBody(step.Body) || strings.TrimSpace(step.BodyFile) != "" {
+		return errors.New("connect steps send no request, so body and body_file are not allowed")
+	}
+	if step.PollJob != nil || step.WebSocket != nil || step.GRPC != nil || step.DNS != nil {
+		return errors.New("connect cannot be combined with poll_job, websocket, grpc or dns")
+	}
+	if header, _ := step.Options.Matrix(); header != "" {
+		return errors.New("connect cannot be combined with options.accept_matrix or accept_language_matrix")
+	}
+	if !secure && (len(connect.ALPN) > 0 || connect.ServerName != "" || step.Options.TLS != nil) {
+		return errors.New("alpn, server_name and options.tls require a tls:// URL")
+	}
+	if connect.Timeout < 0 {
+		return fmt.Errorf("connect timeout must be >= 0, got: %s", connect.Timeout)
+	}
+
+	return nil
+}
+
+func validateDNS(step model.Step) error {
+	dns := step.DNS
+	if dns == nil {
+		return nil
+	}
+
+	if !strings.EqualFold(step.Method, model.MethodLookup) {
+		return fmt.Errorf("dns steps must use LOOKUP, got: %s", step.Method)
+	}
+	if !strings.HasPrefix(step.URL, "dns://") {
+		return fmt.Errorf("dns steps need a dns:// URL, got: %s", step.URL)
+	}
+	if dns.Type != "" && !model.IsSupportedDNSType(strings.ToUpper(dns.Type)) {
+		return fmt.Errorf("unsupported dns record type: %s (expected A, AAAA, CNAME, TXT, MX or NS)", dns.Type)
+	}
+	if hasInlineBody(step.Body) || strings.TrimSpace(step.BodyFile) != "" {
+		return errors.New("dns steps send no request, so body and body_file are not allowed")
+	}
+	if step.PollJob != nil || step.WebSocket != nil || step.GRPC != nil {
+		return errors.New("dns cannot be combined with poll_job, websocket or grpc")
+	}
+	if header, _ := step.Options.Matrix(); header != "" {
+		return errors.New("dns cannot be combined with options.accept_matrix or accept_language_matrix")
+	}
+	if dns.Timeout < 0 {
+		return fmt.Errorf("dns timeout must be >= 0, got: %s", dns.Timeout)
+	}
+
+	return nil
+}
+
+func validateAuth(step model.Step) error {
+	auth := step.Auth
+	if auth == nil {
+		return nil
+	}
+
+	if auth.OAuth2 == nil {
+		return errors.New("auth requires oauth2")
+	}
+	if err := requireField(auth.OAuth2.TokenURL, "oauth2", "token_url"); err != nil {
+		return err
+	}
+	if err := requireField(auth.OAuth2.ClientID, "oauth2", "client_id"); err != nil {
+		return err
+	}
+	if err := requireField(auth.OAuth2.ClientSecret, "oauth2", "client_secret"); err != nil {
+		return err
+	}
+	if _, ok := step.Headers.GetFold("Authorization"); ok {
+		return errors.New("auth cannot be combined with an explicit Authorization header")
+	}
+	if step.Connect != nil || step.DNS != nil {
+		return errors.New("auth cannot be combined with connect or dns")
+	}
+
+	return nil
+}
+
+func validateAsserts(asserts model.Asserts) error {
+	for i, assert := range asserts.Status {
+		if err := validatePredicate(assert.Predicate, "status assert"); err != nil {
+			return indexedFieldError("asserts.status", i, err)
+		}
+	}
+
+	for i, assert := range asserts.Headers {
+		if err := requireField(assert.Name, "header assert", "name"); err != nil {
+			return indexedFieldError("asserts.headers", i, err)
+		}
+		if err := validatePredicate(assert.Predicate, "header assert"); err != nil {
+			return indexedFieldError("asserts.headers", i, err)
+		}
+	}
+
+	for i, assert := range asserts.Cookies {
+		if err := requireField(assert.Name, "cookie assert", "name"); err != nil {
+			return indexedFieldError("asserts.cookies", i, err)
+		}
+		if !model.IsSupportedCookieAttribute(assert.Attribute) {
+			return indexedFieldError("asserts.cookies", i, fmt.Errorf("unsupported cookie attribute: %s", assert.Attribute))
+		}
+		if err := validatePredicate(assert.Predicate, "cookie assert"); err != nil {
+			return indexedFieldError("asserts.cookies", i, err)
+		}
+	}
+
+	for i, assert := range asserts.Certificate {
+		if err := requireField(assert.Name, "certificate assert", "name"); err != nil {
+			return indexedFieldError("asserts.certificate", i, err)
+		}
+		if !isSupportedCertificateField(assert.Name) {
+			return indexedFieldError("asserts.certificate", i, fmt.Errorf("unsupported certificate field: %s", assert.Name))
+		}
+
+		if err := validatePredicate(assert.Predicate, "certificate assert"); err != nil {
+			return indexedFieldError("asserts.certificate", i, err)
+		}
+	}
+
+	for i, assert := range asserts.JSONPath {
+		if err := requireField(assert.Path, "jsonpath assert", "path"); err != nil {
+			return indexedFieldError("asserts.jsonpath", i, err)
+		}
+
+		if err := validatePredicate(assert.Predicate, "jsonpath assert"); err != nil {
+			return indexedFieldError("asserts.jsonpath", i, err)
+		}
+	}
+
+	if asserts.GraphQL != nil {
+		if err := validateGraphQLAsserts("asserts.graphql.data", asserts.GraphQL.Data); err != nil {
+			return err
+		}
+		if err := validateGraphQLAsserts("asserts.graphql.errors", asserts.GraphQL.Errors); err != nil {
+			return err
+		}
+	}
+
+	for i, assert := range asserts.CSS {
+		if err := validateCSSSelector(assert.Selector, "css assert"); err != nil {
+			return indexedFieldError("asserts.css", i, err)
+		}
+
+		if err := validatePredicate(assert.Predicate, "css assert"); err != nil {
+			return indexedFieldError("asserts.css", i, err)
+		}
+	}
+
+	for i, assert := range asserts.TLS {
+		if err := requireField(assert.Name, "tls assert", "name"); err != nil {
+			return indexedFieldError("asserts.tls", i, err)
+		}
+		if !model.IsSupportedTLSField(assert.Name) {
+			return indexedFieldError("asserts.tls", i, fmt.Errorf("unsupported tls field: %s", assert.Name))
+		}
+		if err := validatePredicate(assert.Predicate, "tls assert"); err != nil {
+			return indexedFieldError("asserts.tls", i, err)
+		}
+	}
+
+	for i, assert := range asserts.TTFB {
+		if err := validatePredicate(assert.Predicate, "ttfb assert"); err != nil {
+			return indexedFieldError("asserts.ttfb", i, err)
+		}
+	}
+
+	for i, assert := range asserts.Duration {
+		if err := validateDurationAssert(assert.Predicate); err != nil {
+			return indexedFieldError("asserts.duration", i, err)
+		}
+	}
+
+	for i, assert := range asserts.Golden {
+		if err := requireField(assert.File, "golden assert", "file"); err != nil {
+			return indexedFieldError("asserts.golden", i, err)
+		}
+	}
+
+	for i, assert := range asserts.JSONSchema {
+		if err := requireField(assert.File, "jsonschema assert", "file"); err != nil {
+			return indexedFieldError("asserts.jsonschema", i, err)
+		}
+	}
+
+	for i, assert := range asserts.Body {
+		if err := validateBodyAssert(assert.Predicate); err != nil {
+			return indexedFieldError("asserts.body", i, err)
+		}
+	}
+
+	for i, assert := range asserts.ContentLanguage {
+		if err := validateHeaderSetAssert(assert.Predicate, "content_language"); err != nil {
+			return indexedFieldError("asserts.content_language", i, err)
+		}
+	}
+
+	for i, assert := range asserts.Vary {
+		if err := validateHeaderSetAssert(assert.Predicate, "vary"); err != nil {
+			return indexedFieldError("asserts.vary", i, err)
+		}
+	}
+
+	for i, expression := range asserts.Expr {
+		if err := expr.ValidateBoolean(expression); err != nil {
+			return indexedFieldError("asserts.expr", i, fmt.Errorf("expr assert is invalid: %w", err))
+		}
+	}
+
+	for i, assert := range asserts.Attempts {
+		if err := validatePredicate(assert.Predicate, "attempts assert"); err != nil {
+			return indexedFieldError("asserts.attempts", i, err)
+		}
+	}
+
+	for i, assert := range asserts.Redirects {
+		if err := validatePredicate(assert.Predicate, "redirects assert"); err != nil {
+			return indexedFieldError("asserts.redirects", i, err)
+		}
+	}
+
+	for i, assert := range asserts.URL {
+		if err := validatePredicate(assert.Predicate, "url assert"); err != nil {
+			return indexedFieldError("asserts.url", i, err)
 		}
 	}
 
+	for i, assert := range asserts.Continue {
+		if err := validatePredicate(assert.Predicate, "continue assert"); err != nil {
+			return indexedFieldError("asserts.continue", i, err)
+		}
+	}
+
+	if err := validateAllowAssert(asserts.Allow); err != nil {
+		return &FieldError{Path: "asserts.allow", Err: err}
+	}
+
 	return nil
 }
 
-func ValidateStep(step model.Step) error {
-	if strings.TrimSpace(step.Method) == "" {
-		return errors.New("step method cannot be empty")
+func validateRangeAssert(step model.Step) error {
+	if step.Asserts.Range == nil || !*step.Asserts.Range {
+		return nil
 	}
+	if _, ok := step.Headers.GetFold("Range"); !ok {
+		return errors.New("range assert requires a Range request header")
+	}
+	if step.WebSocket != nil || step.GRPC != nil || step.Connect != nil || step.DNS != nil {
+		return errors.New("range assert cannot be combined with websocket, grpc, connect or dns")
+	}
+
+	return nil
+}
 
-	if !model.IsSupportedMethod(step.Method) {
-		return fmt.Errorf("unsupported HTTP method: %s", step.Method)
+func validateAllowAssert(assert *model.AllowAssert) error {
+	if assert == nil {
+		return nil
+	}
+	if len(assert.Contains) == 0 && len(assert.Excludes) == 0 {
+		return errors.New("allow assert requires 'contains' or 'excludes'")
 	}
 
-	if strings.TrimSpace(step.URL) == "" {
-		return errors.New("step URL cannot be empty")
+	for _, method := range slices.Concat(assert.Contains, assert.Excludes) {
+		if !model.IsMethodToken(method) {
+			return fmt.Errorf("allow assert has invalid method: %q", method)
+		}
 	}
 
-	if strings.TrimSpace(step.When) != "" {
-		if err := expr.ValidateBoolean(step.When); err != nil {
-			return fmt.Errorf("step when is invalid: %w", err)
+	return nil
+}
+
+func validateCaptures(captures *model.Captures) error {
+	if captures == nil {
+		return nil
+	}
+
+	for i, capture := range captures.Status {
+		if err := requireField(capture.Name, "status capture", "name"); err != nil {
+			return indexedFieldError("captures.status", i, err)
 		}
 	}
 
-	if strings.TrimSpace(step.Body) != "" && strings.TrimSpace(step.BodyFile) != "" {
-		return errors.New("step cannot define both body and body_file")
+	for i, capture := range captures.Headers {
+		if err := requireField(capture.Name, "header capture", "name"); err != nil {
+			return indexedFieldError("captures.headers", i, err)
+		}
+		if err := requireField(capture.HeaderName, "header capture", "header_name"); err != nil {
+			return indexedFieldError("captures.headers", i, err)
+		}
+		if err := validateHeaderParse(capture); err != nil {
+			return indexedFieldError("captures.headers", i, err)
+		}
+		if err := validateCaptureType(capture.Name, capture.Type); err != nil {
+			return indexedFieldError("captures.headers", i, err)
+		}
 	}
 
-	if step.Options.Retries < 0 {
-		return fmt.Errorf("retries must be >= 0, got: %d", step.Options.Retries)
+	for i, capture := range captures.Cookies {
+		if err := requireField(capture.Name, "cookie capture", "name"); err != nil {
+			return indexedFieldError("captures.cookies", i, err)
+		}
+		if err := requireField(capture.CookieName, "cookie capture", "cookie_name"); err != nil {
+			return indexedFieldError("captures.cookies", i, err)
+		}
+		if !model.IsSupportedCookieAttribute(capture.Attribute) {
+			return indexedFieldError("captures.cookies", i, fmt.Errorf("unsupported cookie attribute: %s", capture.Attribute))
+		}
+		if err := validateCaptureType(capture.Name, capture.Type); err != nil {
+			return indexedFieldError("captures.cookies", i, err)
+		}
 	}
 
-	if err := validateAsserts(step.Asserts); err != nil {
-		return err
+	for i, capture := range captures.Certificate {
+		if err := requireField(capture.Name, "certificate capture", "name"); err != nil {
+			return indexedFieldError("captures.certificate", i, err)
+		}
+		if err := requireField(capture.CertificateField, "certificate capture", "certificate_field"); err != nil {
+			return indexedFieldError("captures.certificate", i, err)
+		}
+		if !isSupportedCertificateField(capture.CertificateField) {
+			return indexedFieldError("captures.certificate", i, fmt.Errorf("unsupported certificate field: %s", capture.CertificateField))
+		}
 	}
 
-	if err := validateCaptures(step.Captures); err != nil {
-		return err
+	for i, capture := range captures.JSONPath {
+		if err := requireField(capture.Name, "jsonpath capture", "name"); err != nil {
+			return indexedFieldError("captures.jsonpath", i, err)
+		}
+		if err := requireField(capture.Path, "jsonpath capture", "path"); err != nil {
+			return indexedFieldError("captures.jsonpath", i, err)
+		}
+		if err := validateCaptureType(capture.Name, capture.Type); err != nil {
+			return indexedFieldError("captures.jsonpath", i, err)
+		}
 	}
 
-	return nil
-}
+	for i, capture := range captures.XPath {
+		if err := requireField(capture.Name, "xpath capture", "name"); err != nil {
+			return indexedFieldError("captures.xpath", i, err)
+		}
+		if err := requireField(capture.Path, "xpath capture", "path"); err != nil {
+			return indexedFieldError("captures.xpath", i, err)
+		}
+		if _, err := xpath.Compile(capture.Path); err != nil {
+			return indexedFieldError("captures.xpath", i, err)
+		}
+		if err := validateCaptureType(capture.Name, capture.Type); err != nil {
+			return indexedFieldError("captures.xpath", i, err)
+		}
+	}
 
-func validateAsserts(asserts model.Asserts) error {
-	for _, assert := range asserts.Status {
-		if err := validatePredicate(assert.Predicate, "status assert"); err != nil {
-			return err
+	for i, capture := range captures.CSS {
+		if err := requireField(capture.Name, "css capture", "name"); err != nil {
+			return indexedFieldError("captures.css", i, err)
+		}
+		if err := validateCSSSelector(capture.Selector, "css capture"); err != nil {
+			return indexedFieldError("captures.css", i, err)
+		}
+		if err := validateCaptureType(capture.Name, capture.Type); err != nil {
+			return indexedFieldError("captures.css", i, err)
 		}
 	}
 
-	for _, assert := range asserts.Headers {
-		if err := requireField(assert.Name, "header assert", "name"); err != nil {
-			return err
+	for i, capture := range captures.Regex {
+		if err := requireField(capture.Name, "regex capture", "name"); err != nil {
+			return indexedFieldError("captures.regex", i, err)
 		}
-		if err := validatePredicate(assert.Predicate, "header assert"); err != nil {
-			return err
+		if err := requireField(capture.Pattern, "regex capture", "pattern"); err != nil {
+			return indexedFieldError("captures.regex", i, err)
+		}
+		if capture.Group < 0 {
+			return indexedFieldError("captures.regex", i, fmt.Errorf("regex capture %q has negative group: %d", capture.Name, capture.Group))
+		}
+		if err := validateCaptureType(capture.Name, capture.Type); err != nil {
+			return indexedFieldError("captures.regex", i, err)
 		}
 	}
 
-	for _, assert := range asserts.Certificate {
-		if err := requireField(assert.Name, "certificate assert", "name"); err != nil {
-			return err
+	for i, capture := range captures.Body {
+		if err := requireField(capture.Name, "body capture", "name"); err != nil {
+			return indexedFieldError("captures.body", i, err)
 		}
-		if !isSupportedCertificateField(assert.Name) {
-			return fmt.Errorf("unsupported certificate field: %s", assert.Name)
+		if err := validateCaptureType(capture.Name, capture.Type); err != nil {
+			return indexedFieldError("captures.body", i, err)
+		}
+	}
+
+	for i, capture := range captures.URL {
+		if err := requireField(capture.Name, "url capture", "name"); err != nil {
+			return indexedFieldError("captures.url", i, err)
 		}
+	}
 
-		if err := validatePredicate(assert.Predicate, "certificate assert"); err != nil {
-			return err
+	for i, capture := range captures.TTFB {
+		if err := requireField(capture.Name, "ttfb capture", "name"); err != nil {
+			return indexedFieldError("captures.ttfb", i, err)
 		}
 	}
 
-	for _, assert := range asserts.JSONPath {
-		if err := requireField(assert.Path, "jsonpath assert", "path"); err != nil {
-			return err
+	for i, capture := range captures.Duration {
+		if err := requireField(capture.Name, "duration capture", "name"); err != nil {
+			return indexedFieldError("captures.duration", i, err)
+		}
+	}
+
+	for i, capture := range captures.Output {
+		if err := requireField(capture.Name, "output capture", "name"); err != nil {
+			return indexedFieldError("captures.output", i, err)
 		}
+		switch capture.Property {
+		case model.OutputPropertySize, model.OutputPropertySHA256, model.OutputPropertyPath:
+		default:
+			return indexedFieldError("captures.output", i, fmt.Errorf("output capture %s has unsupported property %q (supported: size, sha256, path)", capture.Name, capture.Property))
+		}
+	}
 
-		if err := validatePredicate(assert.Predicate, "jsonpath assert"); err != nil {
-			return err
+	for i, capture := range captures.TLS {
+		if err := requireField(capture.Name, "tls capture", "name"); err != nil {
+			return indexedFieldError("captures.tls", i, err)
+		}
+		if err := requireField(capture.TLSField, "tls capture", "tls_field"); err != nil {
+			return indexedFieldError("captures.tls", i, err)
+		}
+		if !model.IsSupportedTLSField(capture.TLSField) {
+			return indexedFieldError("captures.tls", i, fmt.Errorf("unsupported tls field: %s", capture.TLSField))
+		}
+	}
+
+	return validateCaptureSinks(captures)
+}
+
+// validateCaptureSinks checks that sinks name captures of the same step, as
+// they are written right after the step captures them.
+func validateCaptureSinks(captures *model.Captures) error {
+	if len(captures.Sink) == 0 {
+		return nil
+	}
+
+	names := captureNames(captures)
+	for i, sink := range captures.Sink {
+		if err := requireField(sink.Name, "capture sink", "name"); err != nil {
+			return indexedFieldError("captures.sink", i, err)
+		}
+		if err := requireField(sink.File, "capture sink", "file"); err != nil {
+			return indexedFieldError("captures.sink", i, err)
+		}
+		if !names[sink.Name] {
+			return indexedFieldError("captures.sink", i, fmt.Errorf("sink references capture %q not defined in this step", sink.Name))
 		}
 	}
 
 	return nil
 }
 
-func validateCaptures(captures *model.Captures) error {
-	if captures == nil {
+func validateHeaderParse(capture model.HeaderCapture) error {
+	switch capture.Parse {
+	case "":
 		return nil
+	case model.HeaderParseLink, model.HeaderParseList:
+	default:
+		return fmt.Errorf("header capture %s has unsupported parse %q (supported: link, list)", capture.Name, capture.Parse)
+	}
+	if capture.Type != "" {
+		return fmt.Errorf("header capture %s cannot combine parse with type", capture.Name)
 	}
+	return nil
+}
 
-	for _, capture := range captures.Status {
-		if err := requireField(capture.Name, "status capture", "name"); err != nil {
-			return err
+func validateGraphQLAsserts(field string, asserts []model.JSONPathAssert) error {
+	for i, assert := range asserts {
+		if err := requireField(assert.Path, "graphql assert", "path"); err != nil {
+			return indexedFieldError(field, i, err)
+		}
+		if !strings.HasPrefix(assert.Path, "$") {
+			return indexedFieldError(field, i, fmt.Errorf("graphql assert path must start with $, got: %s", assert.Path))
+		}
+		if err := validatePredicate(assert.Predicate, "graphql assert"); err != nil {
+			return indexedFieldError(field, i, err)
 		}
 	}
 
-	for _, capture := range captures.Headers {
-		if err := requireField(capture.Name, "header capture", "name"); err != nil {
-			return err
+	return nil
+}
+
+func validateCSSSelector(selector, kind string) error {
+	if err := requireField(selector, kind, "selector"); err != nil {
+		return err
+	}
+	_, err := css.Compile(selector)
+	return err
+}
+
+func validateCaptureType(name string, kind string) error {
+	if kind == "" || model.IsSupportedCaptureType(kind) {
+		return nil
+	}
+
+	return fmt.Errorf("capture %q has unsupported type: %s", name, kind)
+}
+
+func validateStableAsserts(asserts []model.StableAssert, captures *model.Captures) error {
+	if len(asserts) == 0 {
+		return nil
+	}
+
+	names := captureNames(captures)
+	for i, assert := range asserts {
+		if err := requireField(assert.Capture, "stable assert", "capture"); err != nil {
+			return indexedFieldError("asserts.stable", i, err)
 		}
-		if err := requireField(capture.HeaderName, "header capture", "header_name"); err != nil {
-			return err
+		if !names[assert.Capture] {
+			return indexedFieldError("asserts.stable", i, fmt.Errorf("stable assert references capture %q not defined in this step", assert.Capture))
 		}
 	}
 
-	for _, capture := range captures.Certificate {
-		if err := requireField(capture.Name, "certificate capture", "name"); err != nil {
-			return err
+	return nil
+}
+
+// validateExports checks that exports only name captures of the same step,
+// as --export-captures writes them once the file has run.
+func validateExports(exports []string, captures *model.Captures) error {
+	if len(exports) == 0 {
+		return nil
+	}
+
+	names := captureNames(captures)
+	for i, name := range exports {
+		if strings.TrimSpace(name) == "" {
+			return indexedFieldError("exports", i, errors.New("export name cannot be empty"))
 		}
-		if err := requireField(capture.CertificateField, "certificate capture", "certificate_field"); err != nil {
-			return err
+		if !names[name] {
+			return indexedFieldError("exports", i, fmt.Errorf("export references capture %q not defined in this step", name))
 		}
-		if !isSupportedCertificateField(capture.CertificateField) {
-			return fmt.Errorf("unsupported certificate field: %s", capture.CertificateField)
+	}
+
+	return nil
+}
+
+// validateForeach checks that foreach has items to iterate. Stable asserts
+// compare a capture across runs of the same step, which a loop makes
+// ambiguous.
+func validateForeach(step model.Step) error {
+	foreach := step.Foreach
+	if foreach == nil {
+		return nil
+	}
+
+	if foreach.From == "" && len(foreach.Items) == 0 {
+		return errors.New("foreach requires a non-empty list or a variable name")
+	}
+	if foreach.From != "" && strings.TrimSpace(foreach.From) != foreach.From {
+		return fmt.Errorf("foreach variable name %q cannot contain surrounding spaces", foreach.From)
+	}
+	if len(step.Asserts.Stable) > 0 {
+		return errors.New("foreach cannot be combined with stable asserts")
+	}
+
+	return nil
+}
+
+// validateAcceptMatrix checks options.accept_matrix or
+// accept_language_matrix and the variant_asserts scoped to its values.
+func validateAcceptMatrix(step model.Step) error {
+	if len(step.Options.AcceptMatrix) > 0 && len(step.Options.LanguageMatrix) > 0 {
+		return &FieldError{Path: "options.accept_language_matrix", Err: errors.New("accept_language_matrix cannot be combined with accept_matrix")}
+	}
+
+	header, matrix := step.Options.Matrix()
+	field := "options.accept_matrix"
+	if header == "Accept-Language" {
+		field = "options.accept_language_matrix"
+	}
+	if header != "" {
+		if _, ok := step.Headers.GetFold(header); ok {
+			return &FieldError{Path: field, Err: fmt.Errorf("%s cannot be combined with an %s header", strings.TrimPrefix(field, "options."), header)}
 		}
 	}
 
-	for _, capture := range captures.JSONPath {
-		if err := requireField(capture.Name, "jsonpath capture", "name"); err != nil {
-			return err
+	seen := make(map[string]bool, len(matrix))
+	for i, value := range matrix {
+		if strings.TrimSpace(value) == "" {
+			return indexedFieldError(field, i, fmt.Errorf("%s value cannot be empty", strings.ToLower(header)))
 		}
-		if err := requireField(capture.Path, "jsonpath capture", "path"); err != nil {
-			return err
+		if seen[value] {
+			return indexedFieldError(field, i, fmt.Errorf("duplicate %s value %q", strings.ToLower(header), value))
 		}
+		seen[value] = true
 	}
 
-	for _, capture := range captures.Regex {
-		if err := requireField(capture.Name, "regex capture", "name"); err != nil {
-			return err
+	for _, accept := range slices.Sorted(maps.Keys(step.VariantAsserts)) {
+		if !seen[accept] {
+			return &FieldError{Path: "variant_asserts", Err: fmt.Errorf("variant %q is not listed in options.accept_matrix or accept_language_matrix", accept)}
 		}
-		if err := requireField(capture.Pattern, "regex capture", "pattern"); err != nil {
-			return err
+		asserts := step.VariantAsserts[accept]
+		if err := validateAsserts(asserts); err != nil {
+			return &FieldError{Path: "variant_asserts", Err: fmt.Errorf("%s: %w", accept, err)}
 		}
-		if capture.Group < 0 {
-			return fmt.Errorf("regex capture %q has negative group: %d", capture.Name, capture.Group)
+		if err := validateStableAsserts(asserts.Stable, step.Captures); err != nil {
+			return &FieldError{Path: "variant_asserts", Err: fmt.Errorf("%s: %w", accept, err)}
 		}
 	}
 
+	return nil
+}
+
+func captureNames(captures *model.Captures) map[string]bool {
+	names := make(map[string]bool)
+	if captures == nil {
+		return names
+	}
+
+	for _, capture := range captures.Status {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.Headers {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.Cookies {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.Certificate {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.JSONPath {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.XPath {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.CSS {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.Regex {
+		names[capture.Name] = true
+	}
 	for _, capture := range captures.Body {
-		if err := requireField(capture.Name, "body capture", "name"); err != nil {
-			return err
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.URL {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.Output {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.TLS {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.TTFB {
+		names[capture.Name] = true
+	}
+	for _, capture := range captures.Duration {
+		names[capture.Name] = true
+	}
+
+	return names
+}
+
+// validateDocsStep checks a step that only documents its file.
+func validateDocsStep(step model.Step) error {
+	rest := step
+	rest.Description = ""
+	rest.Docs = ""
+	if !reflect.DeepEqual(rest, model.Step{}) {
+		return &FieldError{Path: "description", Err: errors.New("a step without method, url and checks only documents the file and may only set description and docs")}
+	}
+
+	return nil
+}
+
+// validateChecksStep checks a step that only evaluates checks. Besides
+// checks it may only set when, dump_vars and documentation, as it sends no
+// request.
+func validateChecksStep(step model.Step) error {
+	rest := step
+	rest.When = ""
+	rest.DumpVars = false
+	rest.Checks = nil
+	rest.Description = ""
+	rest.Docs = ""
+	if !reflect.DeepEqual(rest, model.Step{}) {
+		return &FieldError{Path: "checks", Err: errors.New("a step without method and url only runs checks and may only set when, dump_vars, description and docs")}
+	}
+
+	if strings.TrimSpace(step.When) != "" {
+		if err := expr.ValidateBoolean(step.When); err != nil {
+			return &FieldError{Path: "when", Err: fmt.Errorf("step when is invalid: %w", err)}
+		}
+	}
+
+	return validateChecks(step.Checks)
+}
+
+func validateChecks(checks []model.Check) error {
+	for i, check := range checks {
+		if err := requireField(check.Name, "check", "name"); err != nil {
+			return indexedFieldError("checks", i, err)
+		}
+
+		// A templated value is only known at run time, so only the
+		// operator can be checked here.
+		if value, ok := check.Predicate.Value.(string); ok && strings.Contains(value, "{{") {
+			if _, err := assert.BuildExpr(check.Predicate); err != nil {
+				return indexedFieldError("checks", i, fmt.Errorf("check is invalid: %w", err))
+			}
+			continue
+		}
+		if err := validatePredicate(check.Predicate, "check"); err != nil {
+			return indexedFieldError("checks", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateDurationAssert checks the predicate with its value converted to
+// milliseconds, as it is evaluated.
+func validateDurationAssert(p model.Predicate) error {
+	if p.HasValue {
+		millis, err := model.DurationMillis(p.Value)
+		if err != nil {
+			return fmt.Errorf("duration assert is invalid: %w", err)
+		}
+		p.Value = millis
+	}
+
+	return validatePredicate(p, "duration assert")
+}
+
+// validateBodyAssert checks the value of a body assert against its
+// operation, as body asserts do not take general predicates.
+func validateBodyAssert(p model.Predicate) error {
+	if !p.HasValue {
+		return fmt.Errorf("body assert %s requires a value", p.Operation)
+	}
+
+	switch p.Operation {
+	case model.BodyOpSizeEquals, model.BodyOpSizeLessThan:
+		if size, ok := p.Value.(int64); !ok || size < 0 {
+			return fmt.Errorf("body assert %s value must be a non-negative integer, got: %v", p.Operation, p.Value)
+		}
+	case model.BodyOpSHA256Equals:
+		digest, ok := p.Value.(string)
+		if decoded, err := hex.DecodeString(digest); !ok || err != nil || len(decoded) != sha256.Size {
+			return fmt.Errorf("body assert %s value must be a hex SHA-256 digest, got: %v", p.Operation, p.Value)
+		}
+	case model.BodyOpStartsWithBytes:
+		value, ok := p.Value.(string)
+		if !ok {
+			return fmt.Errorf("body assert %s value must be a hex string, got: %v", p.Operation, p.Value)
+		}
+		if _, err := model.ParseHexBytes(value); err != nil {
+			return fmt.Errorf("body assert %s is invalid: %w", p.Operation, err)
+		}
+	default:
+		return fmt.Errorf("unsupported body assert op: %s (expected %s, %s, %s or %s)", p.Operation,
+			model.BodyOpSizeEquals, model.BodyOpSizeLessThan, model.BodyOpSHA256Equals, model.BodyOpStartsWithBytes)
+	}
+
+	return nil
+}
+
+// validateHeaderSetAssert checks a content_language or vary assert: one of
+// the set operations with a member or a list of members as its value.
+func validateHeaderSetAssert(p model.Predicate, kind string) error {
+	switch p.Operation {
+	case model.SetOpContains, model.SetOpNotContains, model.SetOpEquals, model.SetOpSubsetOf:
+	default:
+		return fmt.Errorf("unsupported %s assert op: %s (expected %s, %s, %s or %s)", kind, p.Operation,
+			model.SetOpContains, model.SetOpNotContains, model.SetOpEquals, model.SetOpSubsetOf)
+	}
+	if !p.HasValue {
+		return fmt.Errorf("%s assert %s requires a value", kind, p.Operation)
+	}
+
+	values, isList := p.Value.([]any)
+	if !isList {
+		values = []any{p.Value}
+	}
+	if len(values) == 0 && (p.Operation == model.SetOpContains || p.Operation == model.SetOpNotContains) {
+		return fmt.Errorf("%s assert %s requires at least one value", kind, p.Operation)
+	}
+	for _, value := range values {
+		member, ok := value.(string)
+		if !ok || strings.TrimSpace(member) == "" || strings.Contains(member, ",") {
+			return fmt.Errorf("%s assert %s values must be non-empty strings without commas, got: %v", kind, p.Operation, value)
 		}
 	}
 
@@ -180,6 +1405,10 @@ func requireField(value string, location string, fieldName string) error {
 	return nil
 }
 
+func hasInlineBody(body model.Body) bool {
+	return body.IsStructured() || strings.TrimSpace(body.Text) != ""
+}
+
 func isSupportedCertificateField(field string) bool {
 	switch field {
 	case model.CertificateFieldSubject:
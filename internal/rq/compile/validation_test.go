@@ -1,6 +1,8 @@
 package compile
 
 import (
+	"bytes"
+	"errors"
 	"strings"
 	"testing"
 
@@ -91,6 +93,65 @@ func TestValidateStep(t *testing.T) {
 			step: mustParseStep(t, `
 - method: TRACE
   url: https://api.example.com/health
+`),
+			wantError: true,
+		},
+		{
+			name: "custom_method_with_option",
+			step: mustParseStep(t, `
+- method: PURGE
+  url: https://cdn.example.com/assets/app.js
+  options:
+    allow_custom_method: true
+`),
+		},
+		{
+			name: "custom_method_without_option_is_invalid",
+			step: mustParseStep(t, `
+- method: PROPFIND
+  url: https://dav.example.com/files/
+`),
+			wantError: true,
+		},
+		{
+			name: "custom_method_with_invalid_token_is_invalid",
+			step: mustParseStep(t, `
+- method: "BAD METHOD"
+  url: https://dav.example.com/files/
+  options:
+    allow_custom_method: true
+`),
+			wantError: true,
+		},
+		{
+			name: "allow_assert",
+			step: mustParseStep(t, `
+- method: OPTIONS
+  url: https://api.example.com/users
+  asserts:
+    allow:
+      contains: [GET, POST]
+      excludes: [DELETE]
+`),
+		},
+		{
+			name: "empty_allow_assert_is_invalid",
+			step: mustParseStep(t, `
+- method: OPTIONS
+  url: https://api.example.com/users
+  asserts:
+    allow: {}
+`),
+			wantError: true,
+		},
+		{
+			name: "allow_assert_with_invalid_method_is_invalid",
+			step: mustParseStep(t, `
+- method: OPTIONS
+  url: https://api.example.com/users
+  asserts:
+    allow:
+      contains: ["GET POST"]
 `),
 			wantError: true,
 		},
@@ -98,35 +159,1654 @@ func TestValidateStep(t *testing.T) {
 			name: "body_and_body_file_together_is_invalid",
 			step: mustParseStep(t, `
 - method: POST
-  url: https://api.example.com/upload
-  body: "inline"
-  body_file: ./payload.bin
+  url: https://api.example.com/upload
+  body: "inline"
+  body_file: ./payload.bin
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_when_condition",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/health
+  when: status_code == 200 && is_ready
+`),
+		},
+		{
+			name: "invalid_when_condition",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/health
+  when: status_code ==
+`),
+			wantError: true,
+		},
+		{
+			name: "non_boolean_when_condition",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/health
+  when: 1
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_poll_job",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/jobs/1
+  poll_job:
+    status_path: $.status
+    success: [done]
+    failure: [failed]
+    interval: 2s
+    timeout: 1m
+`),
+		},
+		{
+			name: "poll_job_missing_status_path",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/jobs/1
+  poll_job:
+    success: [done]
+`),
+			wantError: true,
+		},
+		{
+			name: "poll_job_missing_success",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/jobs/1
+  poll_job:
+    status_path: $.status
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_stable_assert",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/orders
+  asserts:
+    stable:
+      - capture: order_id
+  captures:
+    jsonpath:
+      - name: order_id
+        path: $.id
+`),
+		},
+		{
+			name: "stable_assert_unknown_capture",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/orders
+  asserts:
+    stable:
+      - capture: order_id
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_exports",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/orders
+  captures:
+    jsonpath:
+      - name: order_id
+        path: $.id
+  exports: [order_id]
+`),
+		},
+		{
+			name: "export_unknown_capture",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/orders
+  exports: [order_id]
+`),
+			wantError: true,
+		},
+		{
+			name: "json_lines_with_lenient_json",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/events
+  options:
+    json_lines: true
+    lenient_json: true
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_accept_matrix",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  options:
+    accept_matrix: [application/json, application/xml]
+  variant_asserts:
+    application/xml:
+      headers:
+        - name: Content-Type
+          op: starts_with
+          value: application/xml
+`),
+		},
+		{
+			name: "accept_matrix_with_accept_header",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  headers:
+    accept: application/json
+  options:
+    accept_matrix: [application/json]
+`),
+			wantError: true,
+		},
+		{
+			name: "accept_matrix_duplicate",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  options:
+    accept_matrix: [application/json, application/json]
+`),
+			wantError: true,
+		},
+		{
+			name: "variant_asserts_unknown_variant",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  options:
+    accept_matrix: [application/json]
+  variant_asserts:
+    text/csv:
+      status:
+        - op: equals
+          value: 200
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_websocket",
+			step: mustParseStep(t, `
+- method: GET
+  url: wss://api.example.com/stream
+  websocket:
+    send: ['{"op":"subscribe"}']
+    receive: 2
+    timeout: 5s
+`),
+		},
+		{
+			name: "websocket_with_post",
+			step: mustParseStep(t, `
+- method: POST
+  url: wss://api.example.com/stream
+  websocket:
+    send: [hello]
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_conditional",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders/1
+  conditional:
+    if_none_match: order_etag
+`),
+		},
+		{
+			name: "conditional_with_both_headers",
+			step: mustParseStep(t, `
+- method: PUT
+  url: https://api.example.com/orders/1
+  conditional:
+    if_none_match: order_etag
+    if_match: order_etag
+`),
+			wantError: true,
+		},
+		{
+			name: "conditional_with_explicit_header",
+			step: mustParseStep(t, `
+- method: PUT
+  url: https://api.example.com/orders/1
+  headers:
+    if-match: '"v1"'
+  conditional:
+    if_match: order_etag
+`),
+			wantError: true,
+		},
+		{
+			name: "conditional_on_with_conditional",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders/1
+  conditional_on: load_order
+  conditional:
+    if_none_match: order_etag
+`),
+			wantError: true,
+		},
+		{
+			name: "conditional_on_with_explicit_header",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders/1
+  conditional_on: load_order
+  headers:
+    If-Modified-Since: Mon, 01 Jan 2024 00:00:00 GMT
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_grpc",
+			step: mustParseStep(t, `
+- method: POST
+  url: http://localhost:50051
+  grpc:
+    service: orders.v1.Orders
+    method: GetOrder
+    protoset: orders.protoset
+    status: NOT_FOUND
+  body:
+    id: "42"
+`),
+		},
+		{
+			name: "grpc_with_get",
+			step: mustParseStep(t, `
+- method: GET
+  url: http://localhost:50051
+  grpc:
+    service: orders.v1.Orders
+    method: GetOrder
+    protoset: orders.protoset
+`),
+			wantError: true,
+		},
+		{
+			name: "grpc_without_protoset",
+			step: mustParseStep(t, `
+- method: POST
+  url: http://localhost:50051
+  grpc:
+    service: orders.v1.Orders
+    method: GetOrder
+`),
+			wantError: true,
+		},
+		{
+			name: "grpc_with_unknown_status",
+			step: mustParseStep(t, `
+- method: POST
+  url: http://localhost:50051
+  grpc:
+    service: orders.v1.Orders
+    method: GetOrder
+    protoset: orders.protoset
+    status: MISSING
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_connect",
+			step: mustParseStep(t, `
+- method: CONNECT
+  url: tls://db.example.com:5432
+  connect:
+    alpn: [postgresql]
+    timeout: 2s
+  asserts:
+    tls:
+      - name: alpn
+        op: equals
+        value: postgresql
+`),
+		},
+		{
+			name: "connect_with_http_url",
+			step: mustParseStep(t, `
+- method: CONNECT
+  url: https://db.example.com:5432
+  connect: {}
+`),
+			wantError: true,
+		},
+		{
+			name: "connect_with_get",
+			step: mustParseStep(t, `
+- method: GET
+  url: tcp://db.example.com:5432
+  connect: {}
+`),
+			wantError: true,
+		},
+		{
+			name: "connect_tcp_with_alpn",
+			step: mustParseStep(t, `
+- method: CONNECT
+  url: tcp://db.example.com:5432
+  connect:
+    alpn: [h2]
+`),
+			wantError: true,
+		},
+		{
+			name: "connect_method_without_connect",
+			step: mustParseStep(t, `
+- method: CONNECT
+  url: tcp://db.example.com:5432
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_dns",
+			step: mustParseStep(t, `
+- method: LOOKUP
+  url: dns://api.example.com
+  dns:
+    type: aaaa
+    server: 1.1.1.1:53
+  asserts:
+    jsonpath:
+      - path: $
+        op: length
+        value: 2
+`),
+		},
+		{
+			name: "dns_with_unknown_type",
+			step: mustParseStep(t, `
+- method: LOOKUP
+  url: dns://api.example.com
+  dns:
+    type: SRV
+`),
+			wantError: true,
+		},
+		{
+			name: "dns_with_http_url",
+			step: mustParseStep(t, `
+- method: LOOKUP
+  url: https://api.example.com
+  dns: {}
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_oauth2",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  auth:
+    oauth2:
+      token_url: https://auth.example.com/token
+      client_id: rq
+      client_secret: "{{.client_secret}}"
+      scopes: [orders:read]
+`),
+		},
+		{
+			name: "oauth2_without_secret",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  auth:
+    oauth2:
+      token_url: https://auth.example.com/token
+      client_id: rq
+`),
+			wantError: true,
+		},
+		{
+			name: "oauth2_with_authorization_header",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  headers:
+    Authorization: Bearer abc
+  auth:
+    oauth2:
+      token_url: https://auth.example.com/token
+      client_id: rq
+      client_secret: s3cret
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_http_version",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    http_version: 1.1
+`),
+		},
+		{
+			name: "valid_http_version_3",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    http_version: 3
+`),
+		},
+		{
+			name: "unsupported_http_version",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    http_version: 1.0
+`),
+			wantError: true,
+		},
+		{
+			name: "http_version_3_needs_https",
+			step: mustParseStep(t, `
+- method: GET
+  url: http://api.example.com
+  options:
+    http_version: 3
+`),
+			wantError: true,
+		},
+		{
+			name: "http_version_with_websocket",
+			step: mustParseStep(t, `
+- method: GET
+  url: wss://api.example.com
+  options:
+    http_version: 2
+  websocket:
+    send: [ping]
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_checks",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders/1
+  captures:
+    jsonpath:
+      - name: order_total
+        path: $.total
+      - name: item_prices
+        path: $.item_prices
+  checks:
+    - name: order_total
+      op: equals
+      value: "{{ sum .item_prices }}"
+    - name: order_total
+      op: greater_than
+      value: 0
+`),
+		},
+		{
+			name: "valid_checks_only_step",
+			step: mustParseStep(t, `
+- when: order_total != null
+  checks:
+    - name: order_total
+      op: exists
+`),
+		},
+		{
+			name: "checks_only_step_with_headers",
+			step: mustParseStep(t, `
+- headers:
+    Accept: application/json
+  checks:
+    - name: order_total
+      op: exists
+`),
+			wantError: true,
+		},
+		{
+			name: "check_missing_name",
+			step: mustParseStep(t, `
+- checks:
+    - op: equals
+      value: 1
+`),
+			wantError: true,
+		},
+		{
+			name: "check_templated_value_with_unknown_op",
+			step: mustParseStep(t, `
+- checks:
+    - name: total
+      op: roughly
+      value: "{{ .expected }}"
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_retry_backoff",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    retries: 5
+    retry_backoff: exponential
+    retry_delay: 200ms
+    retry_max_wait: 2s
+    retry_jitter: 0.2
+`),
+		},
+		{
+			name: "retry_backoff_without_retries",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    retry_delay: 1s
+`),
+			wantError: true,
+		},
+		{
+			name: "retry_backoff_unsupported",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    retries: 2
+    retry_backoff: linear
+    retry_delay: 1s
+`),
+			wantError: true,
+		},
+		{
+			name: "retry_backoff_exponential_without_delay",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    retries: 2
+    retry_backoff: exponential
+`),
+			wantError: true,
+		},
+		{
+			name: "retry_jitter_out_of_range",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    retries: 2
+    retry_delay: 1s
+    retry_jitter: 1.5
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_docs_only_step",
+			step: mustParseStep(t, `
+- description: Orders API
+  docs: |
+    Creates and reads orders.
+`),
+		},
+		{
+			name: "valid_step_description",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  description: Health check
+  docs: Returns 200 when the service is up.
+`),
+		},
+		{
+			name: "docs_only_step_with_request_fields",
+			step: mustParseStep(t, `
+- description: Orders API
+  headers:
+    Accept: application/json
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_xpath_capture",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders.xml
+  captures:
+    xpath:
+      - name: order_id
+        path: //order[1]/@id
+      - name: orders
+        path: count(//order)
+        type: int
+`),
+		},
+		{
+			name: "xpath_capture_missing_path",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders.xml
+  captures:
+    xpath:
+      - name: order_id
+`),
+			wantError: true,
+		},
+		{
+			name: "xpath_capture_invalid_path",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders.xml
+  captures:
+    xpath:
+      - name: order_id
+        path: //order[
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_css_asserts_and_captures",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://shop.example.com/cart
+  asserts:
+    css:
+      - selector: "#cart .price"
+        op: equals
+        value: "12.50"
+      - selector: a.checkout
+        attr: href
+        op: exists
+  captures:
+    css:
+      - name: csrf
+        selector: input[name=csrf]
+        attr: value
+`),
+		},
+		{
+			name: "css_assert_missing_selector",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://shop.example.com/cart
+  asserts:
+    css:
+      - op: exists
+`),
+			wantError: true,
+		},
+		{
+			name: "css_assert_invalid_selector",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://shop.example.com/cart
+  asserts:
+    css:
+      - selector: "li:hover"
+        op: exists
+`),
+			wantError: true,
+		},
+		{
+			name: "css_capture_missing_name",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://shop.example.com/cart
+  captures:
+    css:
+      - selector: .price
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_register_cleanup",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/users
+  captures:
+    jsonpath:
+      - name: user_id
+        path: $.id
+  register_cleanup:
+    - method: DELETE
+      url: https://api.example.com/users/{{.user_id}}
+`),
+		},
+		{
+			name: "register_cleanup_missing_url",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/users
+  register_cleanup:
+    - method: DELETE
+`),
+			wantError: true,
+		},
+		{
+			name: "register_cleanup_unsupported_method",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/users
+  register_cleanup:
+    - method: PURGE
+      url: https://api.example.com/users/1
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_cookie_asserts_and_captures",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/login
+  options:
+    cookies: false
+  asserts:
+    cookies:
+      - name: sid
+        op: exists
+      - name: sid
+        attribute: http_only
+        op: equals
+        value: true
+  captures:
+    cookies:
+      - name: session_id
+        cookie_name: sid
+        redact: true
+`),
+		},
+		{
+			name: "cookie_assert_unsupported_attribute",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/login
+  asserts:
+    cookies:
+      - name: sid
+        attribute: size
+        op: exists
+`),
+			wantError: true,
+		},
+		{
+			name: "cookie_capture_missing_cookie_name",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/login
+  captures:
+    cookies:
+      - name: session_id
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_structured_header_captures",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/items
+  captures:
+    headers:
+      - name: links
+        header_name: Link
+        parse: link
+      - name: vary
+        header_name: Vary
+        parse: list
+`),
+		},
+		{
+			name: "header_capture_unsupported_parse",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/items
+  captures:
+    headers:
+      - name: links
+        header_name: Link
+        parse: sfv
+`),
+			wantError: true,
+		},
+		{
+			name: "header_capture_parse_with_type",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/items
+  captures:
+    headers:
+      - name: vary
+        header_name: Vary
+        parse: list
+        type: json
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_graphql",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/graphql
+  graphql:
+    query: |
+      query GetUser($id: ID!) { user(id: $id) { name } }
+    operation_name: GetUser
+    variables:
+      id: "{{.user_id}}"
+  asserts:
+    graphql:
+      no_errors: true
+      data:
+        - path: $.user.name
+          op: equals
+          value: Alice
+`),
+		},
+		{
+			name: "graphql_requires_post",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/graphql
+  graphql:
+    query: "{ viewer { login } }"
+`),
+			wantError: true,
+		},
+		{
+			name: "graphql_with_body",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/graphql
+  body: "{}"
+  graphql:
+    query: "{ viewer { login } }"
+`),
+			wantError: true,
+		},
+		{
+			name: "graphql_missing_query",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/graphql
+  graphql:
+    operation_name: Viewer
+`),
+			wantError: true,
+		},
+		{
+			name: "graphql_assert_relative_path",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/graphql
+  graphql:
+    query: "{ viewer { login } }"
+  asserts:
+    graphql:
+      data:
+        - path: viewer.login
+          op: exists
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_expect_continue_and_range",
+			step: mustParseStep(t, `
+- method: PUT
+  url: https://api.example.com/objects/1
+  body: payload
+  options:
+    expect_continue: true
+  asserts:
+    continue:
+      - op: equals
+        value: true
+`),
+			wantError: false,
+		},
+		{
+			name: "expect_continue_without_body",
+			step: mustParseStep(t, `
+- method: PUT
+  url: https://api.example.com/objects/1
+  options:
+    expect_continue: true
+`),
+			wantError: true,
+		},
+		{
+			name: "continue_assert_without_expect_continue",
+			step: mustParseStep(t, `
+- method: PUT
+  url: https://api.example.com/objects/1
+  body: payload
+  asserts:
+    continue:
+      - op: equals
+        value: true
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_range_assert",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/objects/1
+  headers:
+    Range: bytes=100-
+  asserts:
+    range: true
+`),
+			wantError: false,
+		},
+		{
+			name: "range_assert_without_range_header",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/objects/1
+  asserts:
+    range: true
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_multipart",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/uploads
+  multipart:
+    - name: title
+      value: report
+    - name: doc
+      file: report.pdf
+      filename: weekly.pdf
+      content_type: application/pdf
+  options:
+    expect_continue: true
+`),
+			wantError: false,
+		},
+		{
+			name: "multipart_with_body",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/uploads
+  body: payload
+  multipart:
+    - name: title
+      value: report
+`),
+			wantError: true,
+		},
+		{
+			name: "multipart_with_content_type_header",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/uploads
+  headers:
+    Content-Type: multipart/form-data
+  multipart:
+    - name: title
+      value: report
+`),
+			wantError: true,
+		},
+		{
+			name: "multipart_part_without_name",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/uploads
+  multipart:
+    - value: report
+`),
+			wantError: true,
+		},
+		{
+			name: "multipart_part_with_value_and_file",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/uploads
+  multipart:
+    - name: doc
+      value: report
+      file: report.pdf
+`),
+			wantError: true,
+		},
+		{
+			name: "multipart_filename_without_file",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/uploads
+  multipart:
+    - name: doc
+      value: report
+      filename: report.pdf
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_body_file_raw",
+			step: mustParseStep(t, `
+- method: PUT
+  url: https://api.example.com/objects/1
+  body_file: backup.tar
+  body_file_raw: true
+`),
+			wantError: false,
+		},
+		{
+			name: "body_file_raw_without_body_file",
+			step: mustParseStep(t, `
+- method: PUT
+  url: https://api.example.com/objects/1
+  body: payload
+  body_file_raw: true
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_capture_sink",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/tokens
+  captures:
+    jsonpath:
+      - name: token
+        path: $.access_token
+        redact: true
+    sink:
+      - name: token
+        file: out/token.txt
+        reveal: true
+`),
+			wantError: false,
+		},
+		{
+			name: "capture_sink_unknown_capture",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/tokens
+  captures:
+    jsonpath:
+      - name: token
+        path: $.access_token
+    sink:
+      - name: id
+        file: out/id.txt
+`),
+			wantError: true,
+		},
+		{
+			name: "capture_sink_without_file",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/tokens
+  captures:
+    jsonpath:
+      - name: token
+        path: $.access_token
+    sink:
+      - name: token
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_body_asserts",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.zip
+  asserts:
+    body:
+      - op: size_equals
+        value: 2048
+      - op: size_less_than
+        value: 4096
+      - op: sha256_equals
+        value: e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+      - op: starts_with_bytes
+        value: "50 4b 03 04"
+`),
+			wantError: false,
+		},
+		{
+			name: "body_assert_unsupported_op",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.zip
+  asserts:
+    body:
+      - op: contains
+        value: PK
+`),
+			wantError: true,
+		},
+		{
+			name: "body_assert_negative_size",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.zip
+  asserts:
+    body:
+      - op: size_equals
+        value: -1
+`),
+			wantError: true,
+		},
+		{
+			name: "body_assert_short_sha256",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.zip
+  asserts:
+    body:
+      - op: sha256_equals
+        value: e3b0c442
+`),
+			wantError: true,
+		},
+		{
+			name: "body_assert_invalid_hex_bytes",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.zip
+  asserts:
+    body:
+      - op: starts_with_bytes
+        value: "PK"
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_expr_assert",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  asserts:
+    expr:
+      - "json.items.all(i, i.price > 0)"
+`),
+		},
+		{
+			name: "expr_assert_not_boolean",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  asserts:
+    expr:
+      - "size(json.items)"
+`),
+			wantError: true,
+		},
+		{
+			name: "expr_assert_syntax_error",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders
+  asserts:
+    expr:
+      - "json.items.all(i,"
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_output",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.tar
+  options:
+    output: downloads/export.tar
+  asserts:
+    body:
+      - op: size_less_than
+        value: 4294967296
+  captures:
+    output:
+      - name: export_sha
+        property: sha256
+`),
+		},
+		{
+			name: "output_with_golden_assert",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.json
+  options:
+    output: export.json
+  asserts:
+    golden:
+      - file: export.golden.json
+`),
+			wantError: true,
+		},
+		{
+			name: "output_with_jsonpath",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.json
+  options:
+    output: export.json
+  asserts:
+    jsonpath:
+      - path: $.items[0].id
+        op: exists
+  captures:
+    jsonpath:
+      - name: first_id
+        path: $.items[0].id
+`),
+		},
+		{
+			name: "output_with_websocket",
+			step: mustParseStep(t, `
+- method: GET
+  url: wss://api.example.com/feed
+  options:
+    output: feed.txt
+  websocket:
+    receive: 1
+`),
+			wantError: true,
+		},
+		{
+			name: "output_capture_without_output",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.tar
+  captures:
+    output:
+      - name: export_size
+        property: size
+`),
+			wantError: true,
+		},
+		{
+			name: "output_capture_unsupported_property",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/exports/1.tar
+  options:
+    output: export.tar
+  captures:
+    output:
+      - name: export_md5
+        property: md5
+`),
+			wantError: true,
+		},
+		{
+			name: "cacheable_get",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/countries
+  options:
+    cacheable: true
+  asserts:
+    status:
+      - op: equals
+        value: 200
+`),
+		},
+		{
+			name: "cacheable_post",
+			step: mustParseStep(t, `
+- method: POST
+  url: https://api.example.com/countries
+  options:
+    cacheable: true
 `),
 			wantError: true,
 		},
 		{
-			name: "valid_when_condition",
+			name: "cacheable_with_output",
 			step: mustParseStep(t, `
 - method: GET
-  url: https://api.example.com/health
-  when: status_code == 200 && is_ready
+  url: https://api.example.com/countries
+  options:
+    cacheable: true
+    output: countries.json
 `),
+			wantError: true,
 		},
 		{
-			name: "invalid_when_condition",
+			name: "cacheable_with_duration_assert",
 			step: mustParseStep(t, `
 - method: GET
-  url: https://api.example.com/health
-  when: status_code ==
+  url: https://api.example.com/countries
+  options:
+    cacheable: true
+  asserts:
+    duration:
+      - op: less_than
+        value: 500ms
 `),
 			wantError: true,
 		},
 		{
-			name: "non_boolean_when_condition",
+			name: "valid_language_matrix_and_set_asserts",
 			step: mustParseStep(t, `
 - method: GET
-  url: https://api.example.com/health
-  when: 1
+  url: https://api.example.com/articles/1
+  options:
+    accept_language_matrix: [en, fr-FR]
+  asserts:
+    vary:
+      - op: contains
+        value: Accept-Language
+  variant_asserts:
+    fr-FR:
+      content_language:
+        - op: equals
+          value: [fr-FR]
+`),
+		},
+		{
+			name: "language_matrix_with_accept_matrix",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/articles/1
+  options:
+    accept_matrix: [application/json]
+    accept_language_matrix: [en]
+`),
+			wantError: true,
+		},
+		{
+			name: "language_matrix_with_accept_language_header",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/articles/1
+  headers:
+    Accept-Language: en
+  options:
+    accept_language_matrix: [fr]
+`),
+			wantError: true,
+		},
+		{
+			name: "set_assert_unsupported_op",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/articles/1
+  asserts:
+    content_language:
+      - op: regex
+        value: ^fr
+`),
+			wantError: true,
+		},
+		{
+			name: "set_assert_value_with_comma",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/articles/1
+  asserts:
+    vary:
+      - op: contains
+        value: "Accept, Origin"
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_foreach_list",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/regions/{{.item}}
+  foreach: [eu, us]
+`),
+		},
+		{
+			name: "valid_foreach_variable",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/tenants/{{.item.id}}
+  foreach: tenants
+`),
+		},
+		{
+			name: "foreach_empty_list",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/regions/{{.item}}
+  foreach: []
+`),
+			wantError: true,
+		},
+		{
+			name: "foreach_with_stable_assert",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/regions/{{.item}}
+  foreach: [eu, us]
+  captures:
+    jsonpath:
+      - name: version
+        path: $.version
+  asserts:
+    stable:
+      - capture: version
+`),
+			wantError: true,
+		},
+		{
+			name: "jsonschema_without_file",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/users/1
+  asserts:
+    jsonschema:
+      - file: ""
+`),
+			wantError: true,
+		},
+		{
+			name: "jsonschema_with_output",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/users/1
+  options:
+    output: user.json
+  asserts:
+    jsonschema:
+      - file: user.schema.json
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_string_capture_type",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com/orders/1
+  captures:
+    jsonpath:
+      - name: order_id
+        path: $.id
+        type: string
+`),
+		},
+		{
+			name: "valid_tls_options_and_asserts",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    tls:
+      min_version: "1.2"
+      max_version: "1.3"
+      ciphers: [TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256]
+  asserts:
+    tls:
+      - name: version
+        op: equals
+        value: TLS 1.3
+  captures:
+    tls:
+      - name: negotiated_cipher
+        tls_field: cipher
+`),
+		},
+		{
+			name: "tls_min_greater_than_max",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    tls:
+      min_version: "1.3"
+      max_version: "1.2"
+`),
+			wantError: true,
+		},
+		{
+			name: "tls_unknown_cipher",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  options:
+    tls:
+      ciphers: [TLS_MADE_UP]
+`),
+			wantError: true,
+		},
+		{
+			name: "tls_assert_unknown_field",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  asserts:
+    tls:
+      - name: curve
+        op: equals
+        value: X25519
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_ttfb_assert_and_capture",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  asserts:
+    ttfb:
+      - op: less_than
+        value: 500
+  captures:
+    ttfb:
+      - name: ttfb_ms
+`),
+		},
+		{
+			name: "ttfb_capture_missing_name",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  captures:
+    ttfb:
+      - redact: false
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_duration_assert_and_capture",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  asserts:
+    duration:
+      - op: less_than
+        value: 500ms
+      - op: greater_than
+        value: 1
+  captures:
+    duration:
+      - name: duration_ms
+`),
+		},
+		{
+			name: "duration_assert_invalid_value",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  asserts:
+    duration:
+      - op: less_than
+        value: fast
+`),
+			wantError: true,
+		},
+		{
+			name: "valid_attempts_and_redirects_asserts",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  asserts:
+    attempts:
+      - op: equals
+        value: 1
+    redirects:
+      - op: less_than_or_equal
+        value: 2
+`),
+		},
+		{
+			name: "attempts_assert_missing_value",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  asserts:
+    attempts:
+      - op: equals
+`),
+			wantError: true,
+		},
+		{
+			name: "golden_assert_missing_file",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  asserts:
+    golden:
+      - file: ""
+`),
+			wantError: true,
+		},
+		{
+			name: "typed_captures",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  captures:
+    headers:
+      - name: total
+        header_name: X-Total-Count
+        type: int
+    regex:
+      - name: enabled
+        pattern: 'enabled=(\w+)'
+        group: 1
+        type: bool
+    body:
+      - name: payload
+        type: json
+`),
+		},
+		{
+			name: "unsupported_capture_type",
+			step: mustParseStep(t, `
+- method: GET
+  url: https://api.example.com
+  captures:
+    headers:
+      - name: total
+        header_name: X-Total-Count
+        type: decimal
 `),
 			wantError: true,
 		},
@@ -190,3 +1870,192 @@ func TestValidateStep_AcceptsExtractorCertificateConstants(t *testing.T) {
 		t.Fatalf("ValidateStep() error = %v, want nil", err)
 	}
 }
+
+func TestValidateStepsWithPositions(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`- method: GET
+  url: https://api.example.com/health
+- method: GET
+  url: https://api.example.com/users
+  asserts:
+    jsonpath:
+      - path: $.id
+        op: exists
+      - path: $.name
+        op: equal
+        value: rq
+`)
+	steps, err := model.Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	positions, err := model.ParsePositions(data)
+	if err != nil {
+		t.Fatalf("ParsePositions() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		positions []model.Positions
+		want      string
+	}{
+		{
+			name:      "with positions",
+			positions: positions,
+			want:      `invalid spec: step 2: asserts.jsonpath[1]: jsonpath assert is invalid: unsupported predicate operation: "equal" (line 9)`,
+		},
+		{
+			name: "without positions",
+			want: `invalid spec: step 2: asserts.jsonpath[1]: jsonpath assert is invalid: unsupported predicate operation: "equal"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateStepsWithPositions(steps, tt.positions)
+			if err == nil {
+				t.Fatal("expected validation error")
+			}
+			if !errors.Is(err, ErrInvalidSpec) {
+				t.Fatalf("error %v does not wrap ErrInvalidSpec", err)
+			}
+			if err.Error() != tt.want {
+				t.Fatalf("error = %q, want %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStepsConditionalOn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{
+			name: "earlier step",
+			yaml: `- method: GET
+  url: https://api.example.com/orders/1
+  name: load_order
+- method: PUT
+  url: https://api.example.com/orders/1
+  conditional_on: load_order
+`,
+		},
+		{
+			name: "unknown step",
+			yaml: `- method: GET
+  url: https://api.example.com/orders/1
+  conditional_on: load_order
+`,
+			want: "invalid spec: step 1: conditional_on: conditional_on must name an earlier step, got: load_order (line 3)",
+		},
+		{
+			name: "later step",
+			yaml: `- method: GET
+  url: https://api.example.com/orders/1
+  conditional_on: load_order
+- method: GET
+  url: https://api.example.com/orders/1
+  name: load_order
+`,
+			want: "invalid spec: step 1: conditional_on: conditional_on must name an earlier step, got: load_order (line 3)",
+		},
+		{
+			name: "self reference",
+			yaml: `- method: GET
+  url: https://api.example.com/orders/1
+  name: load_order
+  conditional_on: load_order
+`,
+			want: "invalid spec: step 1: conditional_on: conditional_on must name an earlier step, got: load_order (line 4)",
+		},
+		{
+			name: "duplicate name",
+			yaml: `- method: GET
+  url: https://api.example.com/orders/1
+  name: load_order
+- method: GET
+  url: https://api.example.com/orders/2
+  name: load_order
+`,
+			want: "invalid spec: step 2: name: duplicate step name: load_order (line 6)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data := []byte(tt.yaml)
+			steps, err := model.Parse(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			positions, err := model.ParsePositions(data)
+			if err != nil {
+				t.Fatalf("ParsePositions() error = %v", err)
+			}
+
+			err = ValidateStepsWithPositions(steps, positions)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("ValidateStepsWithPositions() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStepFieldPaths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		step model.Step
+		path string
+	}{
+		{
+			name: "method",
+			step: model.Step{Method: "TRACE", URL: "https://api.example.com"},
+			path: "method",
+		},
+		{
+			name: "retries",
+			step: model.Step{Method: "GET", URL: "https://api.example.com", Options: model.Options{Retries: -1}},
+			path: "options.retries",
+		},
+		{
+			name: "capture",
+			step: model.Step{
+				Method:   "GET",
+				URL:      "https://api.example.com",
+				Captures: &model.Captures{Status: []model.StatusCapture{{Name: "ok"}, {}}},
+			},
+			path: "captures.status[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var fieldErr *FieldError
+			if err := ValidateStep(tt.step); !errors.As(err, &fieldErr) {
+				t.Fatalf("ValidateStep() error = %v, want *FieldError", err)
+			}
+			if fieldErr.Path != tt.path {
+				t.Fatalf("path = %q, want %q", fieldErr.Path, tt.path)
+			}
+		})
+	}
+}
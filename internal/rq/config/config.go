@@ -16,12 +16,29 @@ import (
 	"github.com/jacoelho/rq/internal/rq/clock"
 	"github.com/jacoelho/rq/internal/rq/exit"
 	"github.com/jacoelho/rq/internal/rq/httpclient"
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/model"
 	"github.com/jacoelho/rq/internal/rq/output"
 )
 
 const (
 	// DefaultTimeout is the default timeout for HTTP requests.
 	DefaultTimeout = 30 * time.Second
+
+	// DefaultInterval is the default delay between runs in daemon mode.
+	DefaultInterval = 30 * time.Second
+	// DefaultListenAddr is the default address for the daemon HTTP endpoints.
+	DefaultListenAddr = ":9090"
+	// ReportJUnit is the --report kind for JUnit XML.
+	ReportJUnit = "junit"
+	// DefaultBaselineThreshold is the slowdown, in percent, tolerated against
+	// a --baseline before a step counts as regressed.
+	DefaultBaselineThreshold = 20.0
+	// DefaultArtifactsDir is where failure artifacts such as screenshots are
+	// written.
+	DefaultArtifactsDir = "rq-artifacts"
+	// DefaultDebugBodyLimit is how many bytes of each body --debug shows.
+	DefaultDebugBodyLimit = 8192
 )
 
 var (
@@ -32,18 +49,83 @@ var (
 	ErrInvalidVariableFormat = errors.New("variable must be in format name=value")
 	ErrEmptyVariableName     = errors.New("variable name cannot be empty")
 	ErrInvalidOutputFormat   = errors.New("output format must be one of: text, json")
+	ErrDaemonWithRepeat      = errors.New("--daemon cannot be combined with --repeat")
+	ErrInteractiveMode       = errors.New("--interactive cannot be combined with --daemon, --repeat or --parallel")
+	ErrTraceWithDaemon       = errors.New("--trace cannot be combined with --daemon")
+	ErrInvalidReportFormat   = errors.New("report must be in format kind=path")
+	ErrEmptyReportKind       = errors.New("report kind cannot be empty")
+	ErrUnsupportedReport     = errors.New("report kind must be one of: junit")
+	ErrReportMode            = errors.New("--report cannot be combined with --daemon or --interactive")
+	ErrInvalidParallel       = errors.New("--parallel must be >= 0")
+	ErrExportMode            = errors.New("--export-captures cannot be combined with --daemon or --interactive")
+	ErrBaselineMode          = errors.New("--baseline cannot be combined with --daemon or --interactive")
+	ErrBaselineRequired      = errors.New("--update-baseline, --baseline-threshold and --baseline-warn require --baseline")
+	ErrInvalidThreshold      = errors.New("--baseline-threshold must be >= 0")
+	ErrOpenAPIRequired       = errors.New("--openapi-warn requires --openapi")
+	ErrCacheMode             = errors.New("--cache cannot be combined with --daemon")
+	ErrScreenshotRequired    = errors.New("--artifacts-dir requires --screenshot-cmd")
+	ErrInvalidInterval       = errors.New("interval must be greater than zero")
+	ErrDaemonRequired        = errors.New("--interval and --listen require --daemon")
+	ErrInsecureWithCACert    = errors.New("--insecure cannot be combined with --cacert")
+	ErrInvalidMetaFormat     = errors.New("meta must be in format key=value")
+	ErrEmptyMetaKey          = errors.New("meta key cannot be empty")
+	ErrInvalidMetaKey        = errors.New("meta key must start with a letter or underscore and contain only letters, digits and underscores")
+	ErrInvalidMaxResponse    = errors.New("--max-response-bytes must be >= 0")
+	ErrInvalidDebugBody      = errors.New("--debug-body-limit must be >= 0")
+	ErrDebugRequired         = errors.New("--debug-body-limit requires --debug")
+	ErrInvalidBreaker        = errors.New("--circuit-breaker must be >= 0")
+	ErrInvalidMaxConns       = errors.New("--max-conns-per-host must be >= 0")
+	ErrInvalidRunTimeout     = errors.New("--run-timeout must be >= 0")
+	ErrRunTimeoutMode        = errors.New("--run-timeout cannot be combined with --daemon or --interactive")
 )
 
 type Config struct {
 	TestFiles []string
 	Debug     bool
+	DebugBody int // Bytes of each body shown in debug output (0 = unlimited)
 	Repeat    int // Additional iterations after first run (negative = infinite)
+	Parallel  int // Test files executed at once (0 or 1 = sequential)
+
+	Interactive bool // Execute steps one at a time from a prompt
+
+	Daemon     bool          // Run the suite on a schedule and serve /healthz and /metrics
+	Interval   time.Duration // Delay between daemon runs
+	ListenAddr string        // Address for the daemon HTTP endpoints
 
 	Insecure       bool
 	CACertFile     string
+	TLSMinVersion  uint16 // Zero keeps the Go default
+	TLSMaxVersion  uint16 // Zero keeps the Go default
 	RequestTimeout time.Duration
-	RateLimit      float64 // Requests per second (0 = unlimited)
+	RunTimeout     time.Duration // Deadline for the whole run, including repeats and retries (0 = none)
+	RateLimit      float64       // Requests per second (0 = unlimited)
 	OutputFormat   output.OutputFormat
+	Lang           string // Language of messages and the text summary (empty = English)
+
+	MaxResponseBytes int64 // Largest accepted response body after decompression (0 = unlimited)
+	CircuitBreaker   int   // Consecutive connection failures before a host's steps are skipped (0 = off)
+	MaxConnsPerHost  int   // Connections open to one host at a time (0 = default of 50)
+
+	TracePath string            // Chrome trace-event file written when the run ends
+	Reports   map[string]string // Report kind to output path, written when the run ends
+	Meta      map[string]string // Run metadata attached to output, reports and metrics
+
+	ExportCapturesPath string // KEY=value or JSON file of captures written when the run ends
+
+	BaselinePath      string  // Per-step duration baseline file
+	UpdateBaseline    bool    // Record the baseline instead of checking it
+	BaselineThreshold float64 // Tolerated slowdown in percent before a step regresses
+	BaselineWarn      bool    // Report regressions without failing the run
+
+	OpenAPIPath string // OpenAPI 3 document every response is validated against
+	OpenAPIWarn bool   // Report OpenAPI mismatches without failing the step
+
+	CacheDir string // Directory reused responses of cacheable steps are stored in
+
+	Shard Shard // Part of the test files this job runs; the zero value runs all
+
+	ScreenshotCommand string // Renderer run with the HTML body of a failed step on stdin
+	ArtifactsDir      string // Directory for failure artifacts
 
 	Secrets    map[string]any
 	SecretFile string
@@ -54,6 +136,8 @@ type Config struct {
 func (c *Config) TLSConfig() (*tls.Config, error) {
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: c.Insecure,
+		MinVersion:         c.TLSMinVersion,
+		MaxVersion:         c.TLSMaxVersion,
 	}
 
 	if c.CACertFile != "" {
@@ -149,8 +233,9 @@ func (f *keyValueFlag) Values() map[string]any {
 }
 
 func Parse(args []string) (*Config, *exit.Result) {
+	printer := i18n.NewPrinter(i18n.DefaultLang)
 	if len(args) == 0 {
-		return nil, exit.Errorf("Error: %v\n\n%s", ErrNoArguments, Usage())
+		return nil, usageError(printer, ErrNoArguments.Error())
 	}
 
 	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
@@ -162,77 +247,257 @@ func Parse(args []string) (*Config, *exit.Result) {
 
 	var (
 		debug        = fs.Bool("debug", false, "Enable debug output showing request and response details")
+		debugBody    = fs.Int("debug-body-limit", DefaultDebugBodyLimit, "Bytes of each request and response body shown by --debug (0 for unlimited)")
 		repeat       = fs.Int("repeat", 0, "Number of additional times to repeat test execution after the first run (negative for infinite loop)")
+		parallel     = fs.Int("parallel", 0, "Number of test files to execute concurrently (0 or 1 runs them sequentially)")
+		interactive  = fs.Bool("interactive", false, "Execute steps one at a time from an interactive prompt")
+		daemon       = fs.Bool("daemon", false, "Run the suite on a schedule and serve /healthz and /metrics")
+		interval     = fs.Duration("interval", DefaultInterval, "Delay between runs in daemon mode")
+		listenAddr   = fs.String("listen", DefaultListenAddr, "Address for the daemon /healthz and /metrics endpoints")
 		insecure     = fs.Bool("insecure", false, "Skip TLS certificate verification")
 		caCertFile   = fs.String("cacert", "", "Path to CA certificate file for TLS verification")
+		tlsMin       = fs.String("tls-min", "", "Minimum TLS version: 1.0, 1.1, 1.2 or 1.3")
+		tlsMax       = fs.String("tls-max", "", "Maximum TLS version: 1.0, 1.1, 1.2 or 1.3")
 		secrets      = newKeyValueFlag(ErrInvalidSecretFormat, ErrEmptySecretName)
-		secretFile   = fs.String("secret-file", "", "Path to key=value file containing secrets")
+		reports      = newKeyValueFlag(ErrInvalidReportFormat, ErrEmptyReportKind)
+		meta         = newKeyValueFlag(ErrInvalidMetaFormat, ErrEmptyMetaKey)
+		secretFile   = fs.String("secret-file", "", "Path to key=value, YAML or JSON file containing secrets")
 		variables    = newKeyValueFlag(ErrInvalidVariableFormat, ErrEmptyVariableName)
-		variableFile = fs.String("variable-file", "", "Path to key=value file containing template variables")
+		variableFile = fs.String("variable-file", "", "Path to key=value, YAML or JSON file containing template variables")
 		timeout      = fs.Duration("timeout", DefaultTimeout, "HTTP request timeout")
+		runTimeout   = fs.Duration("run-timeout", 0, "Stop the whole run after DURATION, including repeats and retries (0 for no limit)")
 		rateLimit    = fs.Float64("rate-limit", 0, "Rate limit in requests per second (0 for unlimited)")
+		maxResponse  = fs.Int64("max-response-bytes", 0, "Fail a step when its response body exceeds N bytes after decompression (0 for unlimited)")
+		breaker      = fs.Int("circuit-breaker", 0, "Skip remaining steps for a host after N consecutive connection failures (0 to disable)")
+		maxConns     = fs.Int("max-conns-per-host", 0, "Maximum connections open to one host at a time (default: 50)")
 		output       = fs.String("output", "text", "Output format: text or json")
+		lang         = fs.String("lang", i18n.DefaultLang, "Language of messages and the text summary")
+		tracePath    = fs.String("trace", "", "Write a Chrome trace-event timeline of the run to FILE")
+		exportPath   = fs.String("export-captures", "", "Write captures to FILE when the run ends, as JSON for .json files and KEY=value lines otherwise")
+		baselinePath = fs.String("baseline", "", "Compare step durations against a baseline FILE")
+		updateBase   = fs.Bool("update-baseline", false, "Write the step durations of this run to the --baseline file")
+		threshold    = fs.Float64("baseline-threshold", DefaultBaselineThreshold, "Slowdown in percent tolerated against the baseline")
+		baselineWarn = fs.Bool("baseline-warn", false, "Report baseline regressions without failing the run")
+		openAPIPath  = fs.String("openapi", "", "Validate every response against the matching operation of an OpenAPI 3 FILE")
+		openAPIWarn  = fs.Bool("openapi-warn", false, "Report OpenAPI mismatches as warnings instead of failing the step")
+		cacheDir     = fs.String("cache", "", "Reuse responses of steps with options.cacheable, stored in DIR")
+		shard        = fs.String("shard", "", "Run only shard K/N of the test files, assigned by hashing their paths")
+		screenshot   = fs.String("screenshot-cmd", "", "Render the HTML response of a failed step with CMD, which reads the HTML on stdin and writes the artifact path given as its last argument")
+		artifactsDir = fs.String("artifacts-dir", DefaultArtifactsDir, "Directory for failure artifacts such as screenshots")
 		secretSalt   = fs.String("secret-salt", clock.Now().Format("2006-01-02"), "Salt to use for secret redaction hashes (default: current date)")
 	)
 
 	fs.Var(secrets, "secret", "Secret in format name=value (can be used multiple times)")
 	fs.Var(variables, "variable", "Variable in format name=value (can be used multiple times)")
+	fs.Var(reports, "report", "Report in format kind=path, e.g. junit=report.xml (can be used multiple times)")
+	fs.Var(meta, "meta", "Run metadata in format key=value, attached to JSON output, reports and metrics (can be used multiple times)")
 
 	if err := fs.Parse(args[1:]); err != nil {
 		if err == flag.ErrHelp {
 			return nil, exit.Success(Usage())
 		}
-		return nil, exit.Errorf("Error: failed to parse arguments: %v\n\n%s", err, Usage())
+		return nil, usageError(printer, printer.Sprintf(i18n.ParseArgsError, err))
 	}
+	printer = i18n.NewPrinter(*lang)
 
 	// Get remaining positional arguments as test files
 	files := fs.Args()
 	if len(files) == 0 {
-		return nil, exit.Errorf("Error: %v\n\n%s", ErrNoTestFiles, Usage())
+		return nil, usageError(printer, ErrNoTestFiles.Error())
+	}
+
+	if err := validateFlags(fs); err != nil {
+		return nil, usageError(printer, err.Error())
 	}
 
 	finalVariables, err := mergeVariables(*variableFile, variables.Values())
 	if err != nil {
-		return nil, exit.Errorf("Error: failed to load variable file: %v\n\n%s", err, Usage())
+		return nil, usageError(printer, printer.Sprintf(i18n.VariableFileErr, err))
 	}
 
 	finalSecrets, err := mergeSecrets(*secretFile, secrets.Values())
 	if err != nil {
-		return nil, exit.Errorf("Error: failed to load secret file: %v\n\n%s", err, Usage())
+		return nil, usageError(printer, printer.Sprintf(i18n.SecretFileErr, err))
 	}
 
 	outputFormat, err := parseOutputFormat(*output)
 	if err != nil {
-		return nil, exit.Errorf("Error: %v\n\n%s", err, Usage())
+		return nil, usageError(printer, err.Error())
+	}
+
+	tlsMinVersion, tlsMaxVersion, err := parseTLSVersions(*tlsMin, *tlsMax)
+	if err != nil {
+		return nil, usageError(printer, err.Error())
+	}
+
+	finalReports, err := parseReports(reports.Values())
+	if err != nil {
+		return nil, usageError(printer, err.Error())
+	}
+	finalMeta, err := parseMeta(meta.Values())
+	if err != nil {
+		return nil, usageError(printer, err.Error())
+	}
+	if *threshold < 0 {
+		return nil, usageError(printer, fmt.Sprintf("%v, got: %g", ErrInvalidThreshold, *threshold))
+	}
+	if *daemon && *interval <= 0 {
+		return nil, usageError(printer, fmt.Sprintf("%v, got: %s", ErrInvalidInterval, *interval))
+	}
+	if *runTimeout < 0 {
+		return nil, usageError(printer, fmt.Sprintf("%v, got: %s", ErrInvalidRunTimeout, *runTimeout))
+	}
+	if *parallel < 0 {
+		return nil, usageError(printer, fmt.Sprintf("%v, got: %d", ErrInvalidParallel, *parallel))
+	}
+	if *debugBody < 0 {
+		return nil, usageError(printer, fmt.Sprintf("%v, got: %d", ErrInvalidDebugBody, *debugBody))
+	}
+	if *maxResponse < 0 {
+		return nil, usageError(printer, fmt.Sprintf("%v, got: %d", ErrInvalidMaxResponse, *maxResponse))
+	}
+	if *breaker < 0 {
+		return nil, usageError(printer, fmt.Sprintf("%v, got: %d", ErrInvalidBreaker, *breaker))
+	}
+	if *maxConns < 0 {
+		return nil, usageError(printer, fmt.Sprintf("%v, got: %d", ErrInvalidMaxConns, *maxConns))
+	}
+	var selectedShard Shard
+	if *shard != "" {
+		selectedShard, err = ParseShard(*shard)
+		if err != nil {
+			return nil, usageError(printer, err.Error())
+		}
 	}
 
 	config := &Config{
 		TestFiles:      files,
 		Debug:          *debug,
 		Repeat:         *repeat,
+		Parallel:       *parallel,
+		Daemon:         *daemon,
 		Insecure:       *insecure,
 		CACertFile:     *caCertFile,
+		TLSMinVersion:  tlsMinVersion,
+		TLSMaxVersion:  tlsMaxVersion,
 		RequestTimeout: *timeout,
+		RunTimeout:     *runTimeout,
 		RateLimit:      *rateLimit,
 		OutputFormat:   outputFormat,
 		Secrets:        finalSecrets,
 		SecretFile:     *secretFile,
 		Variables:      finalVariables,
 		SecretSalt:     *secretSalt,
+
+		Interactive:      *interactive,
+		MaxResponseBytes: *maxResponse,
+		CircuitBreaker:   *breaker,
+		MaxConnsPerHost:  *maxConns,
+		TracePath:        *tracePath,
+		Reports:          finalReports,
+		Meta:             finalMeta,
+
+		ExportCapturesPath: *exportPath,
+		CacheDir:           *cacheDir,
+		Shard:              selectedShard,
+	}
+
+	if *baselinePath != "" {
+		config.BaselinePath = *baselinePath
+		config.UpdateBaseline = *updateBase
+		config.BaselineThreshold = *threshold
+		config.BaselineWarn = *baselineWarn
+	}
+
+	if *openAPIPath != "" {
+		config.OpenAPIPath = *openAPIPath
+		config.OpenAPIWarn = *openAPIWarn
+	}
+
+	if *debug {
+		config.DebugBody = *debugBody
+	}
+
+	if *screenshot != "" {
+		config.ScreenshotCommand = *screenshot
+		config.ArtifactsDir = *artifactsDir
+	}
+
+	if lang := printer.Lang(); lang != i18n.DefaultLang {
+		config.Lang = lang
+	}
+
+	if *daemon {
+		config.Interval = *interval
+		config.ListenAddr = *listenAddr
 	}
 
 	if err := config.Validate(); err != nil {
-		return nil, exit.Errorf("Error: %v\n\n%s", err, Usage())
+		return nil, usageError(printer, err.Error())
 	}
+	// Every file was checked above, so a typo fails on all shards alike.
+	config.TestFiles = config.Shard.Select(config.TestFiles)
 
 	return config, nil
 }
 
+// parseReports validates --report kinds. It returns nil when no report was
+// requested.
+func parseReports(values map[string]any) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	reports := make(map[string]string, len(values))
+	for kind, path := range values {
+		if kind != ReportJUnit {
+			return nil, fmt.Errorf("%w, got: %s", ErrUnsupportedReport, kind)
+		}
+		if path == "" {
+			return nil, fmt.Errorf("%w, got: %s=", ErrInvalidReportFormat, kind)
+		}
+		reports[kind] = path.(string)
+	}
+
+	return reports, nil
+}
+
+// parseMeta validates --meta keys, which become metric labels and so follow
+// the Prometheus label name rules. It returns nil when no meta was given.
+func parseMeta(values map[string]any) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	meta := make(map[string]string, len(values))
+	for key, value := range values {
+		if !isMetaKey(key) {
+			return nil, fmt.Errorf("%w, got: %s", ErrInvalidMetaKey, key)
+		}
+		meta[key] = value.(string)
+	}
+
+	return meta, nil
+}
+
+func isMetaKey(key string) bool {
+	for i, char := range key {
+		switch {
+		case char == '_', char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z':
+		case i > 0 && char >= '0' && char <= '9':
+		default:
+			return false
+		}
+	}
+
+	return key != ""
+}
+
 func mergeVariables(variableFile string, cliVariables map[string]any) (map[string]any, error) {
 	var merged map[string]any
 
 	if variableFile != "" {
-		fileVariables, err := loadKeyValueFile(variableFile)
+		fileVariables, err := loadVariableFile(variableFile)
 		if err != nil {
 			return nil, err
 		}
@@ -254,7 +519,7 @@ func mergeSecrets(secretFile string, cliSecrets map[string]any) (map[string]any,
 	merged := make(map[string]any)
 
 	if secretFile != "" {
-		fileSecrets, err := loadKeyValueFile(secretFile)
+		fileSecrets, err := loadValueFile(secretFile)
 		if err != nil {
 			return nil, err
 		}
@@ -276,11 +541,35 @@ func parseOutputFormat(input string) (output.OutputFormat, error) {
 	}
 }
 
-// loadVariableFile loads variables from key=value format with comment support.
+func parseTLSVersions(minInput, maxInput string) (uint16, uint16, error) {
+	var minVersion, maxVersion uint16
+	if minInput != "" {
+		version, err := model.ParseTLSVersion(minInput)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid --tls-min: %w", err)
+		}
+		minVersion = version
+	}
+	if maxInput != "" {
+		version, err := model.ParseTLSVersion(maxInput)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid --tls-max: %w", err)
+		}
+		maxVersion = version
+	}
+	if minVersion != 0 && maxVersion != 0 && minVersion > maxVersion {
+		return 0, 0, fmt.Errorf("--tls-min %s is greater than --tls-max %s", minInput, maxInput)
+	}
+
+	return minVersion, maxVersion, nil
+}
+
+// loadVariableFile loads variables from a key=value, YAML or JSON file.
 func loadVariableFile(filename string) (map[string]any, error) {
-	return loadKeyValueFile(filename)
+	return loadValueFile(filename)
 }
 
+// loadKeyValueFile loads key=value lines, skipping blank lines and # comments.
 func loadKeyValueFile(filename string) (map[string]any, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
@@ -309,42 +598,47 @@ func loadKeyValueFile(filename string) (map[string]any, error) {
 			return nil, fmt.Errorf("empty key at line %d: %s", lineNum+1, line)
 		}
 
-		variables[key] = value
+		variables[key] = unquoteShellValue(value)
 	}
 
 	return variables, nil
 }
 
+// unquoteShellValue removes the single quotes --export-captures puts around
+// values with shell characters, so an exported file can be read back with
+// --var-file. Other values are returned unchanged.
+func unquoteShellValue(value string) string {
+	if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return value
+	}
+
+	return strings.ReplaceAll(value[1:len(value)-1], `'\''`, "'")
+}
+
+// Usage returns the help text of rq in English.
 func Usage() string {
-	return `rq - HTTP testing tool
-
-Usage: rq [options] <file1> [file2] ...
-
-Options:
-  --debug                 Enable debug output showing request and response details
-  --repeat N              Number of additional times to repeat after first run (negative for infinite)
-  --insecure              Skip TLS certificate verification
-  --cacert FILE           Path to CA certificate file for TLS verification
-  --timeout DURATION      HTTP request timeout (default: 30s)
-  --rate-limit N          Rate limit in requests per second (0 for unlimited)
-  --output FORMAT         Output format: text or json (default: text)
-  --secret NAME=VALUE     Secret in format name=value (can be used multiple times)
-  --secret-file FILE      Path to key=value file containing secrets
-  --secret-salt SALT      Salt to use for secret redaction hashes (default: current date)
-  --variable NAME=VALUE   Variable in format name=value (can be used multiple times)
-  --variable-file FILE    Path to key=value file containing template variables
-  -h, --help              Show this help message
-  -v, --version           Show version information
-
-Examples:
-  rq test.yaml                           # Run test file once
-  rq test.yaml --debug                   # Run with debug output
-  rq test.yaml --rate-limit 5            # Rate limit to 5 requests per second
-  rq test.yaml --repeat 1                # Run test file twice (1 + 1 additional)
-  rq test.yaml --repeat -1               # Run test file infinitely
-  rq file1.yaml file2.yaml              # Run multiple test files in sequence
-  rq test.yaml --secret API_KEY=secret   # Pass secret to test
-  rq test.yaml --variable HOST=localhost # Pass variable to test`
+	return i18n.NewPrinter(i18n.DefaultLang).Text(i18n.Usage)
+}
+
+// messageLang returns the language lang resolves to, or "" for English, so
+// a subcommand printer follows --lang like the run does.
+func messageLang(lang string) string {
+	if resolved := i18n.NewPrinter(lang).Lang(); resolved != i18n.DefaultLang {
+		return resolved
+	}
+	return ""
+}
+
+// usageError reports err followed by the help text, both in the language of
+// printer.
+func usageError(printer *i18n.Printer, err string) *exit.Result {
+	return commandUsageError(printer, i18n.Usage, err)
+}
+
+// commandUsageError is usageError for a subcommand, followed by the help
+// text of usage.
+func commandUsageError(printer *i18n.Printer, usage i18n.Key, err string) *exit.Result {
+	return exit.Error(printer.Sprintf(i18n.UsageError, err, printer.Text(usage)))
 }
 
 func (c *Config) HTTPClient() (*http.Client, error) {
@@ -353,5 +647,5 @@ func (c *Config) HTTPClient() (*http.Client, error) {
 		return nil, fmt.Errorf("failed to create TLS configuration: %w", err)
 	}
 
-	return httpclient.New(tlsConfig, c.RequestTimeout), nil
+	return httpclient.New(tlsConfig, c.RequestTimeout, c.MaxConnsPerHost), nil
 }
@@ -319,6 +319,452 @@ func TestParse(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "with_tls_versions",
+			args: []string{"rq", "--tls-min", "1.2", "--tls-max", "TLS1.3", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				TLSMinVersion:  tls.VersionTLS12,
+				TLSMaxVersion:  tls.VersionTLS13,
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name: "with_max_response_bytes",
+			args: []string{"rq", "--max-response-bytes", "1048576", testFile1},
+			want: &Config{
+				TestFiles:        []string{testFile1},
+				RequestTimeout:   DefaultTimeout,
+				Secrets:          map[string]any{},
+				SecretSalt:       "2025-07-05",
+				MaxResponseBytes: 1 << 20,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "negative_max_response_bytes",
+			args:    []string{"rq", "--max-response-bytes", "-1", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_circuit_breaker",
+			args: []string{"rq", "--circuit-breaker", "3", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+				CircuitBreaker: 3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "with_max_conns_per_host",
+			args: []string{"rq", "--max-conns-per-host", "4", testFile1},
+			want: &Config{
+				TestFiles:       []string{testFile1},
+				RequestTimeout:  DefaultTimeout,
+				Secrets:         map[string]any{},
+				SecretSalt:      "2025-07-05",
+				MaxConnsPerHost: 4,
+			},
+			wantErr: false,
+		},
+		{
+			name: "with_lang_without_catalog",
+			args: []string{"rq", "--lang", "pt_BR.UTF-8", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name: "with_run_timeout",
+			args: []string{"rq", "--run-timeout", "15m", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				RequestTimeout: DefaultTimeout,
+				RunTimeout:     15 * time.Minute,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "negative_run_timeout",
+			args:    []string{"rq", "--run-timeout", "-1s", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "run_timeout_with_daemon",
+			args:    []string{"rq", "--run-timeout", "1m", "--daemon", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "negative_max_conns_per_host",
+			args:    []string{"rq", "--max-conns-per-host", "-1", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "negative_circuit_breaker",
+			args:    []string{"rq", "--circuit-breaker", "-1", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_interactive",
+			args: []string{"rq", "--interactive", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				Interactive:    true,
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "interactive_with_repeat",
+			args:    []string{"rq", "--interactive", "--repeat", "2", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_trace",
+			args: []string{"rq", "--trace", "trace.json", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+				TracePath:      "trace.json",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "trace_with_daemon",
+			args:    []string{"rq", "--trace", "trace.json", "--daemon", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_junit_report",
+			args: []string{"rq", "--report", "junit=report.xml", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+				Reports:        map[string]string{ReportJUnit: "report.xml"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "unsupported_report_kind",
+			args:    []string{"rq", "--report", "html=report.html", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "report_without_path",
+			args:    []string{"rq", "--report", "junit=", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "report_with_daemon",
+			args:    []string{"rq", "--report", "junit=report.xml", "--daemon", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_meta",
+			args: []string{"rq", "--meta", "build=1234", "--meta", "git_sha=abc123", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				RequestTimeout: DefaultTimeout,
+				Meta:           map[string]string{"build": "1234", "git_sha": "abc123"},
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "meta_with_invalid_key",
+			args:    []string{"rq", "--meta", "git-sha=abc123", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "meta_without_value_separator",
+			args:    []string{"rq", "--meta", "build", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_parallel",
+			args: []string{"rq", "--parallel", "4", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				Parallel:       4,
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "negative_parallel",
+			args:    []string{"rq", "--parallel", "-1", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_baseline_defaults",
+			args: []string{"rq", "--baseline", "baseline.json", testFile1},
+			want: &Config{
+				TestFiles:         []string{testFile1},
+				RequestTimeout:    DefaultTimeout,
+				Secrets:           map[string]any{},
+				SecretSalt:        "2025-07-05",
+				BaselinePath:      "baseline.json",
+				BaselineThreshold: DefaultBaselineThreshold,
+			},
+			wantErr: false,
+		},
+		{
+			name: "with_baseline_options",
+			args: []string{"rq", "--baseline", "baseline.json", "--update-baseline", "--baseline-threshold", "50", "--baseline-warn", testFile1},
+			want: &Config{
+				TestFiles:         []string{testFile1},
+				RequestTimeout:    DefaultTimeout,
+				Secrets:           map[string]any{},
+				SecretSalt:        "2025-07-05",
+				BaselinePath:      "baseline.json",
+				UpdateBaseline:    true,
+				BaselineThreshold: 50,
+				BaselineWarn:      true,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "update_baseline_without_baseline",
+			args:    []string{"rq", "--update-baseline", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_openapi",
+			args: []string{"rq", "--openapi", "spec.yaml", "--openapi-warn", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+				OpenAPIPath:    "spec.yaml",
+				OpenAPIWarn:    true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "with_cache",
+			args: []string{"rq", "--cache", ".rq-cache", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+				CacheDir:       ".rq-cache",
+			},
+			wantErr: false,
+		},
+		{
+			name: "with_shard",
+			args: []string{"rq", "--shard", "1/1", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+				Shard:          Shard{Index: 1, Count: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid_shard",
+			args:    []string{"rq", "--shard", "3/2", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "cache_with_daemon",
+			args:    []string{"rq", "--cache", ".rq-cache", "--daemon", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_debug",
+			args: []string{"rq", "--debug", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				Debug:          true,
+				DebugBody:      DefaultDebugBodyLimit,
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name: "with_debug_body_limit",
+			args: []string{"rq", "--debug", "--debug-body-limit", "256", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				Debug:          true,
+				DebugBody:      256,
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "debug_body_limit_without_debug",
+			args:    []string{"rq", "--debug-body-limit", "256", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "negative_debug_body_limit",
+			args:    []string{"rq", "--debug", "--debug-body-limit", "-1", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "openapi_warn_without_openapi",
+			args:    []string{"rq", "--openapi-warn", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "negative_baseline_threshold",
+			args:    []string{"rq", "--baseline", "baseline.json", "--baseline-threshold", "-5", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "insecure_with_cacert",
+			args:    []string{"rq", "--insecure", "--cacert", caCertFile, testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "interval_without_daemon",
+			args:    []string{"rq", "--interval", "1m", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "export_captures",
+			args: []string{"rq", "--export-captures", "out.env", testFile1},
+			want: &Config{
+				TestFiles:          []string{testFile1},
+				RequestTimeout:     DefaultTimeout,
+				OutputFormat:       output.FormatText,
+				Secrets:            map[string]any{},
+				SecretSalt:         "2025-07-05",
+				ExportCapturesPath: "out.env",
+			},
+			wantErr: false,
+		},
+		{
+			name: "screenshot_cmd",
+			args: []string{"rq", "--screenshot-cmd", "render-html", testFile1},
+			want: &Config{
+				TestFiles:         []string{testFile1},
+				RequestTimeout:    DefaultTimeout,
+				OutputFormat:      output.FormatText,
+				Secrets:           map[string]any{},
+				SecretSalt:        "2025-07-05",
+				ScreenshotCommand: "render-html",
+				ArtifactsDir:      DefaultArtifactsDir,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "artifacts_dir_without_screenshot_cmd",
+			args:    []string{"rq", "--artifacts-dir", "out", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "export_captures_with_interactive",
+			args:    []string{"rq", "--export-captures", "out.env", "--interactive", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "invalid_tls_min",
+			args:    []string{"rq", "--tls-min", "1.4", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "tls_min_greater_than_max",
+			args:    []string{"rq", "--tls-min", "1.3", "--tls-max", "1.2", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "with_daemon",
+			args: []string{"rq", "--daemon", "--interval", "1m", "--listen", "127.0.0.1:9100", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				Daemon:         true,
+				Interval:       time.Minute,
+				ListenAddr:     "127.0.0.1:9100",
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name: "with_daemon_defaults",
+			args: []string{"rq", "--daemon", testFile1},
+			want: &Config{
+				TestFiles:      []string{testFile1},
+				Daemon:         true,
+				Interval:       DefaultInterval,
+				ListenAddr:     DefaultListenAddr,
+				RequestTimeout: DefaultTimeout,
+				Secrets:        map[string]any{},
+				SecretSalt:     "2025-07-05",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "daemon_with_repeat",
+			args:    []string{"rq", "--daemon", "--repeat", "1", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "daemon_with_zero_interval",
+			args:    []string{"rq", "--daemon", "--interval", "0s", testFile1},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name:    "no_arguments",
 			args:    []string{},
@@ -825,6 +1271,19 @@ invalid certificate content
 				}
 			},
 		},
+		{
+			name: "tls_version_bounds",
+			config: &Config{
+				TLSMinVersion: tls.VersionTLS12,
+				TLSMaxVersion: tls.VersionTLS13,
+			},
+			wantErr: false,
+			checkFn: func(t *testing.T, tlsConfig *tls.Config) {
+				if tlsConfig.MinVersion != tls.VersionTLS12 || tlsConfig.MaxVersion != tls.VersionTLS13 {
+					t.Errorf("Expected TLS 1.2-1.3 bounds, got %x-%x", tlsConfig.MinVersion, tlsConfig.MaxVersion)
+				}
+			},
+		},
 		{
 			name: "with_nonexistent_ca_cert",
 			config: &Config{
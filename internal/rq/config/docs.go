@@ -0,0 +1,63 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"github.com/jacoelho/rq/internal/rq/docs"
+	"github.com/jacoelho/rq/internal/rq/exit"
+	"github.com/jacoelho/rq/internal/rq/i18n"
+)
+
+// DocsConfig holds the options accepted by `rq docs`.
+type DocsConfig struct {
+	TestFiles []string
+	Format    docs.Format
+	Lang      string // Language of messages (empty = English)
+}
+
+// ParseDocs parses `rq docs` arguments. args[0] is the subcommand name.
+func ParseDocs(args []string) (*DocsConfig, *exit.Result) {
+	if len(args) == 0 {
+		return nil, commandUsageError(i18n.NewPrinter(i18n.DefaultLang), i18n.DocsUsage, ErrNoArguments.Error())
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.Usage = func() {}
+	fs.SetOutput(io.Discard)
+
+	output := fs.String("output", "markdown", "Output format: markdown or html")
+	lang := fs.String("lang", i18n.DefaultLang, "Language of messages")
+
+	err := fs.Parse(args[1:])
+	printer := i18n.NewPrinter(*lang)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return nil, exit.Success(printer.Text(i18n.DocsUsage))
+		}
+		return nil, commandUsageError(printer, i18n.DocsUsage, printer.Sprintf(i18n.ParseArgsError, err))
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		return nil, commandUsageError(printer, i18n.DocsUsage, ErrNoTestFiles.Error())
+	}
+
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			return nil, commandUsageError(printer, i18n.DocsUsage, printer.Sprintf(i18n.FileNotFound, file, err))
+		}
+	}
+
+	format, err := docs.ParseFormat(*output)
+	if err != nil {
+		return nil, commandUsageError(printer, i18n.DocsUsage, err.Error())
+	}
+
+	return &DocsConfig{
+		TestFiles: files,
+		Format:    format,
+		Lang:      messageLang(*lang),
+	}, nil
+}
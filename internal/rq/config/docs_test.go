@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/docs"
+)
+
+func TestParseDocs(t *testing.T) {
+	t.Parallel()
+
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	if err := os.WriteFile(testFile, []byte("- method: GET\n  url: https://example.com\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		want         *DocsConfig
+		wantExitCode int
+		wantErr      bool
+	}{
+		{
+			name: "defaults to markdown",
+			args: []string{"docs", testFile},
+			want: &DocsConfig{TestFiles: []string{testFile}, Format: docs.FormatMarkdown},
+		},
+		{
+			name: "html output",
+			args: []string{"docs", "--output", "html", testFile},
+			want: &DocsConfig{TestFiles: []string{testFile}, Format: docs.FormatHTML},
+		},
+		{name: "help", args: []string{"docs", "--help"}, wantExitCode: 0, wantErr: true},
+		{name: "no files", args: []string{"docs"}, wantExitCode: 1, wantErr: true},
+		{name: "missing file", args: []string{"docs", "missing.yaml"}, wantExitCode: 1, wantErr: true},
+		{name: "invalid output", args: []string{"docs", "--output", "pdf", testFile}, wantExitCode: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, result := ParseDocs(tt.args)
+			if tt.wantErr {
+				if result == nil {
+					t.Fatalf("ParseDocs() expected exit result, got config %+v", got)
+				}
+				if result.ExitCode != tt.wantExitCode {
+					t.Fatalf("ParseDocs() exit code = %d, want %d", result.ExitCode, tt.wantExitCode)
+				}
+				return
+			}
+
+			if result != nil {
+				t.Fatalf("ParseDocs() unexpected exit result: %s", result.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseDocs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
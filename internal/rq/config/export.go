@@ -0,0 +1,86 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"github.com/jacoelho/rq/internal/rq/exit"
+	"github.com/jacoelho/rq/internal/rq/export"
+	"github.com/jacoelho/rq/internal/rq/i18n"
+)
+
+// ExportConfig holds the options accepted by `rq export`.
+type ExportConfig struct {
+	TestFiles  []string
+	Variables  map[string]any
+	Format     export.Format
+	Insecure   bool
+	CACertFile string
+	Lang       string // Language of messages (empty = English)
+}
+
+// ParseExport parses `rq export` arguments. args[0] is the subcommand name.
+func ParseExport(args []string) (*ExportConfig, *exit.Result) {
+	if len(args) == 0 {
+		return nil, commandUsageError(i18n.NewPrinter(i18n.DefaultLang), i18n.ExportUsage, ErrNoArguments.Error())
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.Usage = func() {}
+	fs.SetOutput(io.Discard)
+
+	var (
+		variables    = newKeyValueFlag(ErrInvalidVariableFormat, ErrEmptyVariableName)
+		variableFile = fs.String("variable-file", "", "Path to key=value, YAML or JSON file containing template variables")
+		format       = fs.String("format", "curl", "Export format: curl")
+		insecure     = fs.Bool("insecure", false, "Add --insecure to every command")
+		caCertFile   = fs.String("cacert", "", "Add --cacert FILE to every command")
+		lang         = fs.String("lang", i18n.DefaultLang, "Language of messages")
+	)
+
+	fs.Var(variables, "variable", "Variable in format name=value (can be used multiple times)")
+
+	err := fs.Parse(args[1:])
+	printer := i18n.NewPrinter(*lang)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return nil, exit.Success(printer.Text(i18n.ExportUsage))
+		}
+		return nil, commandUsageError(printer, i18n.ExportUsage, printer.Sprintf(i18n.ParseArgsError, err))
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		return nil, commandUsageError(printer, i18n.ExportUsage, ErrNoTestFiles.Error())
+	}
+
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			return nil, commandUsageError(printer, i18n.ExportUsage, printer.Sprintf(i18n.FileNotFound, file, err))
+		}
+	}
+
+	if *insecure && *caCertFile != "" {
+		return nil, commandUsageError(printer, i18n.ExportUsage, ErrInsecureWithCACert.Error())
+	}
+
+	finalVariables, err := mergeVariables(*variableFile, variables.Values())
+	if err != nil {
+		return nil, commandUsageError(printer, i18n.ExportUsage, printer.Sprintf(i18n.VariableFileErr, err))
+	}
+
+	exportFormat, err := export.ParseFormat(*format)
+	if err != nil {
+		return nil, commandUsageError(printer, i18n.ExportUsage, err.Error())
+	}
+
+	return &ExportConfig{
+		TestFiles:  files,
+		Variables:  finalVariables,
+		Format:     exportFormat,
+		Insecure:   *insecure,
+		CACertFile: *caCertFile,
+		Lang:       messageLang(*lang),
+	}, nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/export"
+)
+
+func TestParseExport(t *testing.T) {
+	t.Parallel()
+
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	if err := os.WriteFile(testFile, []byte("- method: GET\n  url: https://example.com\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		want         *ExportConfig
+		wantExitCode int
+		wantErr      bool
+	}{
+		{
+			name: "defaults to curl",
+			args: []string{"export", testFile},
+			want: &ExportConfig{TestFiles: []string{testFile}, Format: export.FormatCurl},
+		},
+		{
+			name: "variables and tls options",
+			args: []string{"export", "--format", "curl", "--variable", "host=localhost", "--cacert", "ca.pem", testFile},
+			want: &ExportConfig{
+				TestFiles:  []string{testFile},
+				Variables:  map[string]any{"host": "localhost"},
+				Format:     export.FormatCurl,
+				CACertFile: "ca.pem",
+			},
+		},
+		{
+			name: "insecure",
+			args: []string{"export", "--insecure", testFile},
+			want: &ExportConfig{TestFiles: []string{testFile}, Format: export.FormatCurl, Insecure: true},
+		},
+		{name: "help", args: []string{"export", "--help"}, wantExitCode: 0, wantErr: true},
+		{name: "no files", args: []string{"export"}, wantExitCode: 1, wantErr: true},
+		{name: "missing file", args: []string{"export", "missing.yaml"}, wantExitCode: 1, wantErr: true},
+		{name: "invalid format", args: []string{"export", "--format", "httpie", testFile}, wantExitCode: 1, wantErr: true},
+		{name: "insecure with cacert", args: []string{"export", "--insecure", "--cacert", "ca.pem", testFile}, wantExitCode: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, result := ParseExport(tt.args)
+			if tt.wantErr {
+				if result == nil {
+					t.Fatalf("ParseExport() expected exit result, got config %+v", got)
+				}
+				if result.ExitCode != tt.wantExitCode {
+					t.Fatalf("ParseExport() exit code = %d, want %d", result.ExitCode, tt.wantExitCode)
+				}
+				return
+			}
+
+			if result != nil {
+				t.Fatalf("ParseExport() unexpected exit result: %s", result.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseExport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
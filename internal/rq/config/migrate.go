@@ -0,0 +1,62 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"github.com/jacoelho/rq/internal/rq/exit"
+	"github.com/jacoelho/rq/internal/rq/i18n"
+)
+
+// MigrateConfig holds the options accepted by `rq migrate`.
+type MigrateConfig struct {
+	TestFiles []string
+	Write     bool
+	Check     bool
+	Lang      string // Language of messages (empty = English)
+}
+
+// ParseMigrate parses `rq migrate` arguments. args[0] is the subcommand name.
+func ParseMigrate(args []string) (*MigrateConfig, *exit.Result) {
+	if len(args) == 0 {
+		return nil, commandUsageError(i18n.NewPrinter(i18n.DefaultLang), i18n.MigrateUsage, ErrNoArguments.Error())
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.Usage = func() {}
+	fs.SetOutput(io.Discard)
+
+	var (
+		write = fs.Bool("write", false, "Rewrite files in place")
+		check = fs.Bool("check", false, "Exit with status 1 when any file needs migration")
+		lang  = fs.String("lang", i18n.DefaultLang, "Language of messages")
+	)
+
+	err := fs.Parse(args[1:])
+	printer := i18n.NewPrinter(*lang)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return nil, exit.Success(printer.Text(i18n.MigrateUsage))
+		}
+		return nil, commandUsageError(printer, i18n.MigrateUsage, printer.Sprintf(i18n.ParseArgsError, err))
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		return nil, commandUsageError(printer, i18n.MigrateUsage, ErrNoTestFiles.Error())
+	}
+
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			return nil, commandUsageError(printer, i18n.MigrateUsage, printer.Sprintf(i18n.FileNotFound, file, err))
+		}
+	}
+
+	return &MigrateConfig{
+		TestFiles: files,
+		Write:     *write,
+		Check:     *check,
+		Lang:      messageLang(*lang),
+	}, nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseMigrate(t *testing.T) {
+	t.Parallel()
+
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	if err := os.WriteFile(testFile, []byte("- method: GET\n  url: https://example.com\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		want         *MigrateConfig
+		wantExitCode int
+		wantErr      bool
+	}{
+		{
+			name: "defaults to listing changes",
+			args: []string{"migrate", testFile},
+			want: &MigrateConfig{TestFiles: []string{testFile}},
+		},
+		{
+			name: "write and check",
+			args: []string{"migrate", "--write", "--check", testFile},
+			want: &MigrateConfig{TestFiles: []string{testFile}, Write: true, Check: true},
+		},
+		{name: "help", args: []string{"migrate", "--help"}, wantExitCode: 0, wantErr: true},
+		{name: "no files", args: []string{"migrate"}, wantExitCode: 1, wantErr: true},
+		{name: "missing file", args: []string{"migrate", "missing.yaml"}, wantExitCode: 1, wantErr: true},
+		{name: "run-only flag rejected", args: []string{"migrate", "--repeat", "1", testFile}, wantExitCode: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, result := ParseMigrate(tt.args)
+			if tt.wantErr {
+				if result == nil {
+					t.Fatalf("ParseMigrate() expected exit result, got config %+v", got)
+				}
+				if result.ExitCode != tt.wantExitCode {
+					t.Fatalf("ParseMigrate() exit code = %d, want %d", result.ExitCode, tt.wantExitCode)
+				}
+				return
+			}
+
+			if result != nil {
+				t.Fatalf("ParseMigrate() unexpected exit result: %s", result.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseMigrate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+
+	"github.com/jacoelho/rq/internal/rq/exit"
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/plan"
+)
+
+// PlanConfig holds the options accepted by `rq plan`.
+type PlanConfig struct {
+	TestFiles []string
+	Variables map[string]any
+	Format    plan.Format
+	Lang      string // Language of messages (empty = English)
+}
+
+// ParsePlan parses `rq plan` arguments. args[0] is the subcommand name.
+func ParsePlan(args []string) (*PlanConfig, *exit.Result) {
+	if len(args) == 0 {
+		return nil, commandUsageError(i18n.NewPrinter(i18n.DefaultLang), i18n.PlanUsage, ErrNoArguments.Error())
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.Usage = func() {}
+	fs.SetOutput(io.Discard)
+
+	var (
+		variables    = newKeyValueFlag(ErrInvalidVariableFormat, ErrEmptyVariableName)
+		variableFile = fs.String("variable-file", "", "Path to key=value, YAML or JSON file containing template variables")
+		output       = fs.String("output", "yaml", "Output format: yaml or json")
+		lang         = fs.String("lang", i18n.DefaultLang, "Language of messages")
+	)
+
+	fs.Var(variables, "variable", "Variable in format name=value (can be used multiple times)")
+
+	err := fs.Parse(args[1:])
+	printer := i18n.NewPrinter(*lang)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return nil, exit.Success(printer.Text(i18n.PlanUsage))
+		}
+		return nil, commandUsageError(printer, i18n.PlanUsage, printer.Sprintf(i18n.ParseArgsError, err))
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		return nil, commandUsageError(printer, i18n.PlanUsage, ErrNoTestFiles.Error())
+	}
+
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			return nil, commandUsageError(printer, i18n.PlanUsage, printer.Sprintf(i18n.FileNotFound, file, err))
+		}
+	}
+
+	finalVariables, err := mergeVariables(*variableFile, variables.Values())
+	if err != nil {
+		return nil, commandUsageError(printer, i18n.PlanUsage, printer.Sprintf(i18n.VariableFileErr, err))
+	}
+
+	format, err := plan.ParseFormat(*output)
+	if err != nil {
+		return nil, commandUsageError(printer, i18n.PlanUsage, err.Error())
+	}
+
+	return &PlanConfig{
+		TestFiles: files,
+		Variables: finalVariables,
+		Format:    format,
+		Lang:      messageLang(*lang),
+	}, nil
+}
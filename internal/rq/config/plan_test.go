@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/plan"
+)
+
+func TestParsePlan(t *testing.T) {
+	t.Parallel()
+
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	if err := os.WriteFile(testFile, []byte("- method: GET\n  url: https://example.com\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		want         *PlanConfig
+		wantExitCode int
+		wantErr      bool
+	}{
+		{
+			name: "defaults to yaml",
+			args: []string{"plan", testFile},
+			want: &PlanConfig{TestFiles: []string{testFile}, Format: plan.FormatYAML},
+		},
+		{
+			name: "variables and json output",
+			args: []string{"plan", "--output", "json", "--variable", "host=localhost", testFile},
+			want: &PlanConfig{
+				TestFiles: []string{testFile},
+				Variables: map[string]any{"host": "localhost"},
+				Format:    plan.FormatJSON,
+			},
+		},
+		{name: "help", args: []string{"plan", "--help"}, wantExitCode: 0, wantErr: true},
+		{name: "no files", args: []string{"plan"}, wantExitCode: 1, wantErr: true},
+		{name: "missing file", args: []string{"plan", "missing.yaml"}, wantExitCode: 1, wantErr: true},
+		{name: "invalid output", args: []string{"plan", "--output", "text", testFile}, wantExitCode: 1, wantErr: true},
+		{name: "run-only flag rejected", args: []string{"plan", "--repeat", "1", testFile}, wantExitCode: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, result := ParsePlan(tt.args)
+			if tt.wantErr {
+				if result == nil {
+					t.Fatalf("ParsePlan() expected exit result, got config %+v", got)
+				}
+				if result.ExitCode != tt.wantExitCode {
+					t.Fatalf("ParsePlan() exit code = %d, want %d", result.ExitCode, tt.wantExitCode)
+				}
+				return
+			}
+
+			if result != nil {
+				t.Fatalf("ParsePlan() unexpected exit result: %s", result.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParsePlan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePlanLocalized(t *testing.T) {
+	t.Parallel()
+
+	i18n.Register("x-config", i18n.Catalog{
+		i18n.UsageError:   "Erro: %s\n\n%s",
+		i18n.FileNotFound: "arquivo de teste %s não encontrado: %v",
+		i18n.PlanUsage:    "Uso: rq plan [opções] <arquivo.yaml>...",
+	})
+
+	_, result := ParsePlan([]string{"plan", "--lang", "x-config", "missing.yaml"})
+	if result == nil || result.ExitCode != 1 {
+		t.Fatalf("ParsePlan() result = %+v, want exit code 1", result)
+	}
+	for _, want := range []string{"Erro: arquivo de teste missing.yaml não encontrado", "Uso: rq plan [opções]"} {
+		if !strings.Contains(result.Message, want) {
+			t.Errorf("message missing %q:\n%s", want, result.Message)
+		}
+	}
+
+	_, result = ParsePlan([]string{"plan", "--lang", "x-config", "--help"})
+	if result == nil || result.Message != "Uso: rq plan [opções] <arquivo.yaml>..." {
+		t.Fatalf("ParsePlan(--help) result = %+v, want localized usage", result)
+	}
+}
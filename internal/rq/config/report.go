@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/jacoelho/rq/internal/rq/exit"
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/report"
+)
+
+// ErrNoReports is returned when `rq report merge` is given no reports.
+var ErrNoReports = errors.New("no reports specified")
+
+// ReportConfig holds the options accepted by `rq report merge`.
+type ReportConfig struct {
+	Reports []string
+	Output  string // File the merged report is written to; empty for stdout
+	Format  report.Format
+	Lang    string // Language of messages (empty = English)
+}
+
+// ParseReport parses `rq report` arguments. args[0] is the subcommand name
+// and args[1] the report command, of which only merge exists. Options may
+// follow the report files.
+func ParseReport(args []string) (*ReportConfig, *exit.Result) {
+	printer := i18n.NewPrinter(i18n.DefaultLang)
+	if len(args) < 2 {
+		return nil, commandUsageError(printer, i18n.ReportUsage, ErrNoArguments.Error())
+	}
+	switch args[1] {
+	case "merge":
+	case "-h", "--help", "-help":
+		return nil, exit.Success(printer.Text(i18n.ReportUsage))
+	default:
+		return nil, commandUsageError(printer, i18n.ReportUsage, printer.Sprintf(i18n.UnknownReport, args[1]))
+	}
+
+	fs := flag.NewFlagSet(args[0]+" "+args[1], flag.ContinueOnError)
+	fs.Usage = func() {}
+	fs.SetOutput(io.Discard)
+
+	var outputPath string
+	fs.StringVar(&outputPath, "o", "", "Write the merged report to FILE instead of stdout")
+	fs.StringVar(&outputPath, "output", "", "Write the merged report to FILE instead of stdout")
+	format := fs.String("format", string(report.FormatJSON), "Format of the merged report: json, junit or text")
+	lang := fs.String("lang", i18n.DefaultLang, "Language of messages")
+
+	reports, err := parseInterspersed(fs, args[2:])
+	printer = i18n.NewPrinter(*lang)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return nil, exit.Success(printer.Text(i18n.ReportUsage))
+		}
+		return nil, commandUsageError(printer, i18n.ReportUsage, printer.Sprintf(i18n.ParseArgsError, err))
+	}
+	if len(reports) == 0 {
+		return nil, commandUsageError(printer, i18n.ReportUsage, ErrNoReports.Error())
+	}
+
+	for _, file := range reports {
+		if _, err := os.Stat(file); err != nil {
+			return nil, commandUsageError(printer, i18n.ReportUsage, printer.Sprintf(i18n.ReportNotFound, file, err))
+		}
+	}
+
+	reportFormat, err := report.ParseFormat(*format)
+	if err != nil {
+		return nil, commandUsageError(printer, i18n.ReportUsage, err.Error())
+	}
+
+	return &ReportConfig{
+		Reports: reports,
+		Output:  outputPath,
+		Format:  reportFormat,
+		Lang:    messageLang(*lang),
+	}, nil
+}
+
+// parseInterspersed parses args with fs, allowing options after positional
+// arguments, and returns the positional arguments in order.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
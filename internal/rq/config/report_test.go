@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/report"
+)
+
+func TestParseReport(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "shard-1.json")
+	second := filepath.Join(dir, "shard-2.json")
+	for _, file := range []string{first, second} {
+		if err := os.WriteFile(file, []byte(`{"file_results":[]}`), 0o644); err != nil {
+			t.Fatalf("write file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		want         *ReportConfig
+		wantExitCode int
+		wantErr      bool
+	}{
+		{
+			name: "defaults to json on stdout",
+			args: []string{"report", "merge", first, second},
+			want: &ReportConfig{Reports: []string{first, second}, Format: report.FormatJSON},
+		},
+		{
+			name: "options after reports",
+			args: []string{"report", "merge", first, second, "-o", "merged.xml", "--format", "junit"},
+			want: &ReportConfig{Reports: []string{first, second}, Output: "merged.xml", Format: report.FormatJUnit},
+		},
+		{
+			name: "options between reports",
+			args: []string{"report", "merge", first, "--output", "merged.txt", second, "--format", "text"},
+			want: &ReportConfig{Reports: []string{first, second}, Output: "merged.txt", Format: report.FormatText},
+		},
+		{name: "help", args: []string{"report", "--help"}, wantExitCode: 0, wantErr: true},
+		{name: "merge help", args: []string{"report", "merge", "--help"}, wantExitCode: 0, wantErr: true},
+		{name: "no command", args: []string{"report"}, wantExitCode: 1, wantErr: true},
+		{name: "unknown command", args: []string{"report", "split", first}, wantExitCode: 1, wantErr: true},
+		{name: "no reports", args: []string{"report", "merge", "-o", "merged.json"}, wantExitCode: 1, wantErr: true},
+		{name: "missing report", args: []string{"report", "merge", "missing.json"}, wantExitCode: 1, wantErr: true},
+		{name: "invalid format", args: []string{"report", "merge", first, "--format", "html"}, wantExitCode: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, result := ParseReport(tt.args)
+			if tt.wantErr {
+				if result == nil {
+					t.Fatalf("ParseReport() expected exit result, got config %+v", got)
+				}
+				if result.ExitCode != tt.wantExitCode {
+					t.Fatalf("ParseReport() exit code = %d, want %d", result.ExitCode, tt.wantExitCode)
+				}
+				return
+			}
+
+			if result != nil {
+				t.Fatalf("ParseReport() unexpected exit result: %s", result.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseReport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidShard is returned for a --shard value that is not K/N with
+// 1 <= K <= N.
+var ErrInvalidShard = errors.New("--shard must be K/N with 1 <= K <= N")
+
+// Shard is the part of the test files one job runs with --shard K/N. Index
+// is 1-based. Files are assigned by hashing their path, so parallel CI jobs
+// agree on the split without a coordinator and a file keeps its shard when
+// others are added or removed. The zero Shard selects every file.
+type Shard struct {
+	Index int
+	Count int
+}
+
+// ParseShard parses a K/N shard value.
+func ParseShard(value string) (Shard, error) {
+	index, count, found := strings.Cut(value, "/")
+	if !found {
+		return Shard{}, fmt.Errorf("%w, got: %s", ErrInvalidShard, value)
+	}
+
+	k, err := strconv.Atoi(strings.TrimSpace(index))
+	if err != nil {
+		return Shard{}, fmt.Errorf("%w, got: %s", ErrInvalidShard, value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n < 1 || k < 1 || k > n {
+		return Shard{}, fmt.Errorf("%w, got: %s", ErrInvalidShard, value)
+	}
+
+	return Shard{Index: k, Count: n}, nil
+}
+
+func (s Shard) String() string {
+	if s.Count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// Contains reports whether file belongs to the shard. Paths are cleaned and
+// use forward slashes before hashing, so ./suite/a.yaml and suite/a.yaml
+// land in the same shard on every platform.
+func (s Shard) Contains(file string) bool {
+	if s.Count <= 1 {
+		return true
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(filepath.ToSlash(filepath.Clean(file))))
+	return int(hash.Sum64()%uint64(s.Count)) == s.Index-1
+}
+
+// Select returns the files of the shard, in their original order.
+func (s Shard) Select(files []string) []string {
+	if s.Count <= 1 {
+		return files
+	}
+
+	selected := make([]string, 0, len(files)/s.Count+1)
+	for _, file := range files {
+		if s.Contains(file) {
+			selected = append(selected, file)
+		}
+	}
+	return selected
+}
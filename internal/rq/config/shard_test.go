@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestParseShard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    Shard
+		wantErr bool
+	}{
+		{value: "1/1", want: Shard{Index: 1, Count: 1}},
+		{value: "2/5", want: Shard{Index: 2, Count: 5}},
+		{value: "5/5", want: Shard{Index: 5, Count: 5}},
+		{value: "0/5", wantErr: true},
+		{value: "6/5", wantErr: true},
+		{value: "1/0", wantErr: true},
+		{value: "2", wantErr: true},
+		{value: "a/b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseShard(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidShard) {
+					t.Fatalf("ParseShard() error = %v, want %v", err, ErrInvalidShard)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseShard() error = %v", err)
+			}
+			if got != tt.want || got.String() != tt.value {
+				t.Errorf("ParseShard() = %+v (%s), want %+v", got, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShardSelect(t *testing.T) {
+	t.Parallel()
+
+	files := make([]string, 0, 50)
+	for i := range 50 {
+		files = append(files, fmt.Sprintf("suite/test-%02d.yaml", i))
+	}
+
+	const count = 4
+	var combined []string
+	for index := 1; index <= count; index++ {
+		selected := Shard{Index: index, Count: count}.Select(files)
+		if !slices.IsSortedFunc(selected, func(a, b string) int { return slices.Index(files, a) - slices.Index(files, b) }) {
+			t.Errorf("shard %d/%d changed the file order: %v", index, count, selected)
+		}
+		combined = append(combined, selected...)
+	}
+
+	slices.Sort(combined)
+	if !slices.Equal(combined, files) {
+		t.Errorf("shards do not cover every file exactly once: %v", combined)
+	}
+
+	if got := (Shard{}).Select(files); !slices.Equal(got, files) {
+		t.Errorf("zero Shard selected %d of %d files", len(got), len(files))
+	}
+	if a, b := (Shard{Index: 3, Count: count}).Contains("./suite/test-07.yaml"), (Shard{Index: 3, Count: count}).Contains("suite/test-07.yaml"); a != b {
+		t.Errorf("Contains() depends on the path spelling: %v != %v", a, b)
+	}
+}
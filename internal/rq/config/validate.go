@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// flagRule is a flag combination Parse rejects. A conflict fails when flag and
+// other are both in use; a dependency (requires) fails when flag is in use
+// without other. The hint tells the user how to fix the command line.
+type flagRule struct {
+	flag     string
+	other    string
+	requires bool
+	err      error
+	hint     string
+}
+
+// flagRules lists the conflicting and dependent flags. Keep it ordered by the
+// flag the rule is about, so related rules stay together.
+var flagRules = []flagRule{
+	{flag: "debug-body-limit", other: "debug", requires: true, err: ErrDebugRequired,
+		hint: "add --debug to show request and response bodies"},
+
+	{flag: "daemon", other: "repeat", err: ErrDaemonWithRepeat,
+		hint: "daemon mode already runs the suite repeatedly; pace it with --interval"},
+
+	{flag: "interactive", other: "daemon", err: ErrInteractiveMode,
+		hint: "drop --daemon to step through the suite from a prompt"},
+	{flag: "interactive", other: "repeat", err: ErrInteractiveMode,
+		hint: "re-run steps from the prompt with run N instead of --repeat"},
+	{flag: "interactive", other: "parallel", err: ErrInteractiveMode,
+		hint: "interactive sessions execute one step at a time; drop --parallel"},
+
+	{flag: "run-timeout", other: "daemon", err: ErrRunTimeoutMode,
+		hint: "a daemon runs until stopped; bound each run with --timeout instead"},
+	{flag: "run-timeout", other: "interactive", err: ErrRunTimeoutMode,
+		hint: "interactive sessions last until you quit"},
+
+	{flag: "interval", other: "daemon", requires: true, err: ErrDaemonRequired,
+		hint: "add --daemon to run on a schedule, or use --repeat for a fixed number of runs"},
+	{flag: "listen", other: "daemon", requires: true, err: ErrDaemonRequired,
+		hint: "add --daemon to serve /healthz and /metrics"},
+
+	{flag: "insecure", other: "cacert", err: ErrInsecureWithCACert,
+		hint: "--insecure skips certificate verification, so --cacert would be ignored; keep only one"},
+
+	{flag: "trace", other: "daemon", err: ErrTraceWithDaemon,
+		hint: "a daemon never finishes, so the trace would never be written"},
+
+	{flag: "report", other: "daemon", err: ErrReportMode,
+		hint: "a daemon never finishes, so the report would never be written; scrape /metrics instead"},
+	{flag: "report", other: "interactive", err: ErrReportMode,
+		hint: "reports are written at the end of a non-interactive run"},
+
+	{flag: "export-captures", other: "daemon", err: ErrExportMode,
+		hint: "a daemon never finishes, so the captures would never be written"},
+	{flag: "export-captures", other: "interactive", err: ErrExportMode,
+		hint: "use vars at the prompt to see captures"},
+
+	{flag: "artifacts-dir", other: "screenshot-cmd", requires: true, err: ErrScreenshotRequired,
+		hint: "add --screenshot-cmd to produce artifacts for failed HTML steps"},
+
+	{flag: "baseline", other: "daemon", err: ErrBaselineMode,
+		hint: "baselines are compared at the end of a non-daemon run"},
+	{flag: "baseline", other: "interactive", err: ErrBaselineMode,
+		hint: "baselines are compared at the end of a non-interactive run"},
+	{flag: "update-baseline", other: "baseline", requires: true, err: ErrBaselineRequired,
+		hint: "add --baseline FILE to choose where the durations are written"},
+	{flag: "baseline-threshold", other: "baseline", requires: true, err: ErrBaselineRequired,
+		hint: "add --baseline FILE to compare against"},
+	{flag: "baseline-warn", other: "baseline", requires: true, err: ErrBaselineRequired,
+		hint: "add --baseline FILE to compare against"},
+	{flag: "openapi-warn", other: "openapi", requires: true, err: ErrOpenAPIRequired,
+		hint: "add --openapi FILE to validate responses against"},
+	{flag: "cache", other: "daemon", err: ErrCacheMode,
+		hint: "monitoring checks the live endpoints; drop --cache"},
+}
+
+// validateFlags checks the flags set on fs against flagRules and reports
+// every violation, so a command line can be fixed in one go. Each error wraps
+// the rule's sentinel for errors.Is.
+func validateFlags(fs *flag.FlagSet) error {
+	used := flagsInUse(fs)
+
+	var errs []error
+	for _, rule := range flagRules {
+		violated := used[rule.other]
+		if rule.requires {
+			violated = !violated
+		}
+		if used[rule.flag] && violated {
+			errs = append(errs, &flagRuleError{rule: rule})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// flagRuleError names the exact flags of a violated rule while matching the
+// rule's sentinel with errors.Is.
+type flagRuleError struct {
+	rule flagRule
+}
+
+func (e *flagRuleError) Error() string {
+	if e.rule.requires {
+		return fmt.Sprintf("--%s requires --%s: %s", e.rule.flag, e.rule.other, e.rule.hint)
+	}
+	return fmt.Sprintf("--%s cannot be combined with --%s: %s", e.rule.flag, e.rule.other, e.rule.hint)
+}
+
+func (e *flagRuleError) Unwrap() error {
+	return e.rule.err
+}
+
+// flagsInUse returns the flags given on the command line with a value other
+// than their default, so --repeat 0 or --daemon=false do not trip a rule.
+func flagsInUse(fs *flag.FlagSet) map[string]bool {
+	used := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		if f.Value.String() != f.DefValue {
+			used[f.Name] = true
+		}
+	})
+
+	return used
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestValidateFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr []error
+		wantMsg string
+	}{
+		{
+			name: "no_flags",
+		},
+		{
+			name: "default_values_are_not_in_use",
+			args: []string{"--daemon", "--repeat", "0"},
+		},
+		{
+			name: "false_boolean_is_not_in_use",
+			args: []string{"--insecure=false", "--cacert", "ca.pem"},
+		},
+		{
+			name: "dependency_satisfied",
+			args: []string{"--daemon", "--interval", "1m"},
+		},
+		{
+			name:    "conflict",
+			args:    []string{"--insecure", "--cacert", "ca.pem"},
+			wantErr: []error{ErrInsecureWithCACert},
+			wantMsg: "--insecure cannot be combined with --cacert: --insecure skips certificate verification",
+		},
+		{
+			name:    "missing_dependency",
+			args:    []string{"--listen", ":9100"},
+			wantErr: []error{ErrDaemonRequired},
+			wantMsg: "--listen requires --daemon: add --daemon",
+		},
+		{
+			name:    "every_violation_reported",
+			args:    []string{"--interactive", "--repeat", "2", "--baseline-warn"},
+			wantErr: []error{ErrInteractiveMode, ErrBaselineRequired},
+			wantMsg: "--baseline-warn requires --baseline",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := flag.NewFlagSet("rq", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Bool("daemon", false, "")
+			fs.Bool("interactive", false, "")
+			fs.Bool("insecure", false, "")
+			fs.Bool("baseline-warn", false, "")
+			fs.Int("repeat", 0, "")
+			fs.String("cacert", "", "")
+			fs.String("listen", DefaultListenAddr, "")
+			fs.String("baseline", "", "")
+			fs.Duration("interval", DefaultInterval, "")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			err := validateFlags(fs)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("validateFlags() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("validateFlags() error = nil, want error")
+			}
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Errorf("validateFlags() error = %v, want %v", err, want)
+				}
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("validateFlags() error = %q, want it to contain %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// loadValueFile loads variables or secrets. Files ending in .yaml, .yml or
+// .json hold a structured document; anything else uses key=value lines.
+func loadValueFile(filename string) (map[string]any, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml", ".json":
+		return loadStructuredFile(filename)
+	default:
+		return loadKeyValueFile(filename)
+	}
+}
+
+// loadStructuredFile reads a YAML or JSON mapping and flattens nested values
+// to dotted keys, so {"db": {"hosts": ["a"]}} yields "db.hosts.0" = "a".
+// Values become strings, as they are in key=value files.
+func loadStructuredFile(filename string) (map[string]any, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	var document map[string]any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: expected a mapping of names to values: %w", filename, err)
+	}
+
+	values := make(map[string]any)
+	for _, key := range sortedKeys(document) {
+		if key == "" {
+			return nil, fmt.Errorf("empty key in %s", filename)
+		}
+		flattenValue(values, key, document[key])
+	}
+
+	return values, nil
+}
+
+func flattenValue(values map[string]any, prefix string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(v) {
+			flattenValue(values, prefix+"."+key, v[key])
+		}
+	case []any:
+		for i, item := range v {
+			flattenValue(values, prefix+"."+strconv.Itoa(i), item)
+		}
+	case nil:
+		values[prefix] = ""
+	default:
+		values[prefix] = fmt.Sprint(v)
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadValueFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		want     map[string]any
+		wantErr  bool
+	}{
+		{
+			name:     "yaml_nested",
+			filename: "vars.yaml",
+			content: `api_url: https://api.example.com
+db:
+  host: localhost
+  port: 5432
+  replicas: [r1, r2]
+debug: true
+empty:
+`,
+			want: map[string]any{
+				"api_url":       "https://api.example.com",
+				"db.host":       "localhost",
+				"db.port":       "5432",
+				"db.replicas.0": "r1",
+				"db.replicas.1": "r2",
+				"debug":         "true",
+				"empty":         "",
+			},
+		},
+		{
+			name:     "yml_extension",
+			filename: "vars.YML",
+			content:  "version: v2\n",
+			want:     map[string]any{"version": "v2"},
+		},
+		{
+			name:     "json_nested",
+			filename: "secrets.json",
+			content:  `{"token": "abc", "oauth": {"client_id": "id", "scopes": ["read"]}, "ttl": 1.5}`,
+			want: map[string]any{
+				"token":           "abc",
+				"oauth.client_id": "id",
+				"oauth.scopes.0":  "read",
+				"ttl":             "1.5",
+			},
+		},
+		{
+			name:     "env_fallback",
+			filename: "vars.env",
+			content:  "name=value\n",
+			want:     map[string]any{"name": "value"},
+		},
+		{
+			name:     "env_exported_quotes",
+			filename: "out.env",
+			content:  "note='it'\\''s done'\nurl='https://api.example.com/a?b=c'\nempty=''\nbare='x\n",
+			want: map[string]any{
+				"note":  "it's done",
+				"url":   "https://api.example.com/a?b=c",
+				"empty": "",
+				"bare":  "'x",
+			},
+		},
+		{
+			name:     "top_level_list",
+			filename: "vars.yaml",
+			content:  "- a\n- b\n",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid_json",
+			filename: "vars.json",
+			content:  `{"token": `,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tt.filename)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			got, err := loadValueFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadValueFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadValueFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
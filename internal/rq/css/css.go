@@ -0,0 +1,229 @@
+// Package css matches CSS selectors against markup documents.
+//
+// Supported are type, universal, #id, .class and attribute selectors
+// ([attr], =, ~=, |=, ^=, $= and *=), the descendant, child (>), adjacent
+// sibling (+) and general sibling (~) combinators, selector lists separated
+// by commas, and the pseudo-classes :first-child, :last-child, :only-child,
+// :nth-child() with an integer, odd, even or an+b, and :not() of a compound
+// selector.
+package css
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/markup"
+)
+
+// ErrSyntax is returned for selectors outside the supported subset.
+var ErrSyntax = errors.New("invalid css selector")
+
+// Selector is a compiled selector list.
+type Selector struct {
+	source    string
+	selectors []complexSelector
+}
+
+// Compile parses a selector list.
+func Compile(selector string) (*Selector, error) {
+	p := &parser{source: selector}
+	compiled := &Selector{source: selector}
+	for {
+		p.skipSpace()
+		complex, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		compiled.selectors = append(compiled.selectors, complex)
+
+		p.skipSpace()
+		if p.done() {
+			return compiled, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("unexpected %q", p.peek())
+		}
+	}
+}
+
+// String returns the source of the selector.
+func (s *Selector) String() string {
+	return s.source
+}
+
+// MatchAll returns the elements of doc that match, in document order.
+func (s *Selector) MatchAll(doc *markup.Node) []*markup.Node {
+	var matched []*markup.Node
+	var walk func(node *markup.Node)
+	walk = func(node *markup.Node) {
+		for _, child := range node.Children {
+			if child.Type != markup.ElementNode {
+				continue
+			}
+			if s.Matches(child) {
+				matched = append(matched, child)
+			}
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	return matched
+}
+
+// MatchFirst returns the first element of doc that matches, or nil.
+func (s *Selector) MatchFirst(doc *markup.Node) *markup.Node {
+	if matched := s.MatchAll(doc); len(matched) > 0 {
+		return matched[0]
+	}
+	return nil
+}
+
+// Matches reports whether element matches any selector of the list.
+func (s *Selector) Matches(element *markup.Node) bool {
+	for _, complex := range s.selectors {
+		if complex.matches(element, len(complex.compounds)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// complexSelector is a chain of compound selectors; combinators[i] joins
+// compounds[i] and compounds[i+1].
+type complexSelector struct {
+	compounds   []compound
+	combinators []byte
+}
+
+func (c complexSelector) matches(element *markup.Node, index int) bool {
+	if !c.compounds[index].matches(element) {
+		return false
+	}
+	if index == 0 {
+		return true
+	}
+
+	switch c.combinators[index-1] {
+	case '>':
+		parent := element.Parent
+		return parent != nil && parent.Type == markup.ElementNode && c.matches(parent, index-1)
+	case '+':
+		previous := previousElements(element)
+		return len(previous) > 0 && c.matches(previous[len(previous)-1], index-1)
+	case '~':
+		for _, sibling := range previousElements(element) {
+			if c.matches(sibling, index-1) {
+				return true
+			}
+		}
+		return false
+	default:
+		for ancestor := element.Parent; ancestor != nil && ancestor.Type == markup.ElementNode; ancestor = ancestor.Parent {
+			if c.matches(ancestor, index-1) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// compound is a sequence of simple selectors that all apply to one element.
+type compound struct {
+	tag     string // Empty or * matches any element
+	filters []filter
+}
+
+type filter func(element *markup.Node) bool
+
+func (c compound) matches(element *markup.Node) bool {
+	if c.tag != "" && c.tag != "*" && !strings.EqualFold(c.tag, element.Name) {
+		return false
+	}
+	for _, f := range c.filters {
+		if !f(element) {
+			return false
+		}
+	}
+	return true
+}
+
+func previousElements(element *markup.Node) []*markup.Node {
+	if element.Parent == nil {
+		return nil
+	}
+
+	var previous []*markup.Node
+	for _, sibling := range element.Parent.Elements() {
+		if sibling == element {
+			break
+		}
+		previous = append(previous, sibling)
+	}
+	return previous
+}
+
+// position returns the 1-based index of element among its element siblings
+// and the number of siblings.
+func position(element *markup.Node) (int, int) {
+	if element.Parent == nil {
+		return 1, 1
+	}
+
+	siblings := element.Parent.Elements()
+	for i, sibling := range siblings {
+		if sibling == element {
+			return i + 1, len(siblings)
+		}
+	}
+	return 0, len(siblings)
+}
+
+func attributeFilter(name, op, value string) (filter, error) {
+	var test func(actual string) bool
+	switch op {
+	case "":
+		test = func(string) bool { return true }
+	case "=":
+		test = func(actual string) bool { return actual == value }
+	case "~=":
+		test = func(actual string) bool { return value != "" && containsWord(actual, value) }
+	case "|=":
+		test = func(actual string) bool { return actual == value || strings.HasPrefix(actual, value+"-") }
+	case "^=":
+		test = func(actual string) bool { return value != "" && strings.HasPrefix(actual, value) }
+	case "$=":
+		test = func(actual string) bool { return value != "" && strings.HasSuffix(actual, value) }
+	case "*=":
+		test = func(actual string) bool { return value != "" && strings.Contains(actual, value) }
+	default:
+		return nil, fmt.Errorf("unsupported attribute operator %s", op)
+	}
+
+	return func(element *markup.Node) bool {
+		actual, ok := element.Attr(name)
+		return ok && test(actual)
+	}, nil
+}
+
+func containsWord(list, word string) bool {
+	for _, field := range strings.Fields(list) {
+		if field == word {
+			return true
+		}
+	}
+	return false
+}
+
+// nthFilter matches elements whose position is a*n+b for some n >= 0.
+func nthFilter(a, b int) filter {
+	return func(element *markup.Node) bool {
+		index, _ := position(element)
+		if a == 0 {
+			return index == b
+		}
+		n := index - b
+		return n%a == 0 && n/a >= 0
+	}
+}
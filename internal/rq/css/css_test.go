@@ -0,0 +1,115 @@
+package css
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/markup"
+)
+
+const page = `<!DOCTYPE html>
+<html lang="en-GB">
+<body>
+  <div id="cart" class="panel wide">
+    <ul>
+      <li class="item" data-sku="A-1">Pen</li>
+      <li class="item sale" data-sku="A-2">Ink</li>
+      <li class="item" data-sku="B-1">Paper</li>
+      <li class="item sale" data-sku="B-2">Clip</li>
+    </ul>
+    <p class="total">Total</p>
+    <span class="price">12.50</span>
+  </div>
+  <p>Footer</p>
+</body>
+</html>`
+
+func TestMatchAll(t *testing.T) {
+	t.Parallel()
+
+	doc, err := markup.ParseHTML([]byte(page))
+	if err != nil {
+		t.Fatalf("ParseHTML() error = %v", err)
+	}
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{selector: "li", want: []string{"Pen", "Ink", "Paper", "Clip"}},
+		{selector: "LI.sale", want: []string{"Ink", "Clip"}},
+		{selector: "#cart .price", want: []string{"12.50"}},
+		{selector: "div > p", want: []string{"Total"}},
+		{selector: "body > p", want: []string{"Footer"}},
+		{selector: "ul + p", want: []string{"Total"}},
+		{selector: "ul ~ span", want: []string{"12.50"}},
+		{selector: "li:first-child", want: []string{"Pen"}},
+		{selector: "li:last-child", want: []string{"Clip"}},
+		{selector: "li:nth-child(2)", want: []string{"Ink"}},
+		{selector: "li:nth-child(odd)", want: []string{"Pen", "Paper"}},
+		{selector: "li:nth-child(2n+3)", want: []string{"Paper"}},
+		{selector: "li:nth-child(-n+2)", want: []string{"Pen", "Ink"}},
+		{selector: "li:not(.sale)", want: []string{"Pen", "Paper"}},
+		{selector: "[data-sku='B-1']", want: []string{"Paper"}},
+		{selector: `li[data-sku^="B"]`, want: []string{"Paper", "Clip"}},
+		{selector: "li[data-sku$=-2]", want: []string{"Ink", "Clip"}},
+		{selector: "li[data-sku*='-']", want: []string{"Pen", "Ink", "Paper", "Clip"}},
+		{selector: "div[class~=wide] .total", want: []string{"Total"}},
+		{selector: "html[lang|=en] span", want: []string{"12.50"}},
+		{selector: ".price, .total", want: []string{"Total", "12.50"}},
+		{selector: "span:only-child", want: nil},
+		{selector: "article", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			t.Parallel()
+
+			selector, err := Compile(tt.selector)
+			if err != nil {
+				t.Fatalf("Compile(%q) error = %v", tt.selector, err)
+			}
+
+			var got []string
+			for _, element := range selector.MatchAll(doc) {
+				got = append(got, element.Text())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MatchAll(%q) = %q, want %q", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"",
+		"li,",
+		"div >",
+		"#",
+		".",
+		"li[",
+		"li[data-sku",
+		"li[data-sku='A-1'",
+		"li[data-sku!=x]",
+		"li:hover",
+		"li:nth-child(0)",
+		"li:nth-child(xn)",
+		"li:not(.sale",
+		"li)",
+		"li[data-sku='A-1]",
+	}
+
+	for _, selector := range tests {
+		t.Run(selector, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Compile(selector); !errors.Is(err, ErrSyntax) {
+				t.Errorf("Compile(%q) error = %v, want ErrSyntax", selector, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package css
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/markup"
+)
+
+type parser struct {
+	source string
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.source)
+}
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.source[p.pos]
+}
+
+func (p *parser) consume(c byte) bool {
+	if p.peek() == c && !p.done() {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace and reports whether there was any.
+func (p *parser) skipSpace() bool {
+	start := p.pos
+	for !p.done() && strings.IndexByte(" \t\n\r\f", p.peek()) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w %q: %s", ErrSyntax, p.source, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) unexpected() error {
+	if p.done() {
+		return p.errorf("unexpected end of selector")
+	}
+	return p.errorf("unexpected %q", p.peek())
+}
+
+func (p *parser) parseComplex() (complexSelector, error) {
+	first, err := p.parseCompound()
+	if err != nil {
+		return complexSelector{}, err
+	}
+	result := complexSelector{compounds: []compound{first}}
+
+	for {
+		spaced := p.skipSpace()
+		if p.done() || p.peek() == ',' || p.peek() == ')' {
+			return result, nil
+		}
+
+		combinator := byte(' ')
+		switch c := p.peek(); c {
+		case '>', '+', '~':
+			combinator = c
+			p.pos++
+			p.skipSpace()
+		default:
+			if !spaced {
+				return complexSelector{}, p.unexpected()
+			}
+		}
+
+		next, err := p.parseCompound()
+		if err != nil {
+			return complexSelector{}, err
+		}
+		result.combinators = append(result.combinators, combinator)
+		result.compounds = append(result.compounds, next)
+	}
+}
+
+func (p *parser) parseCompound() (compound, error) {
+	var result compound
+	if p.consume('*') {
+		result.tag = "*"
+	} else if isIdentChar(p.peek()) {
+		result.tag = p.parseIdent()
+	}
+
+	for {
+		var (
+			f   filter
+			err error
+		)
+		switch p.peek() {
+		case '#':
+			p.pos++
+			f, err = p.parseNamedFilter("id", "=")
+		case '.':
+			p.pos++
+			f, err = p.parseNamedFilter("class", "~=")
+		case '[':
+			p.pos++
+			f, err = p.parseAttribute()
+		case ':':
+			p.pos++
+			f, err = p.parsePseudo()
+		default:
+			if result.tag == "" && len(result.filters) == 0 {
+				return compound{}, p.unexpected()
+			}
+			return result, nil
+		}
+		if err != nil {
+			return compound{}, err
+		}
+		result.filters = append(result.filters, f)
+	}
+}
+
+func (p *parser) parseIdent() string {
+	start := p.pos
+	for !p.done() && isIdentChar(p.peek()) {
+		p.pos++
+	}
+	return p.source[start:p.pos]
+}
+
+func isIdentChar(c byte) bool {
+	return c == '-' || c == '_' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// parseNamedFilter parses the name after # or . into an attribute filter.
+func (p *parser) parseNamedFilter(attribute, op string) (filter, error) {
+	name := p.parseIdent()
+	if name == "" {
+		return nil, p.unexpected()
+	}
+	return attributeFilter(attribute, op, name)
+}
+
+func (p *parser) parseAttribute() (filter, error) {
+	p.skipSpace()
+	name := p.parseIdent()
+	if name == "" {
+		return nil, p.unexpected()
+	}
+	p.skipSpace()
+	if p.consume(']') {
+		return attributeFilter(name, "", "")
+	}
+
+	var op string
+	switch {
+	case p.peek() == '=':
+		op = "="
+	case strings.IndexByte("~|^$*", p.peek()) >= 0 && p.pos+1 < len(p.source) && p.source[p.pos+1] == '=':
+		op = p.source[p.pos : p.pos+2]
+	default:
+		return nil, p.unexpected()
+	}
+	p.pos += len(op)
+	p.skipSpace()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.consume(']') {
+		return nil, p.unexpected()
+	}
+
+	return attributeFilter(name, op, value)
+}
+
+func (p *parser) parseValue() (string, error) {
+	quote := p.peek()
+	if quote != '"' && quote != '\'' {
+		if value := p.parseIdent(); value != "" {
+			return value, nil
+		}
+		return "", p.unexpected()
+	}
+
+	end := strings.IndexByte(p.source[p.pos+1:], quote)
+	if end < 0 {
+		return "", p.errorf("unterminated string")
+	}
+	value := p.source[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return value, nil
+}
+
+func (p *parser) parsePseudo() (filter, error) {
+	name := strings.ToLower(p.parseIdent())
+	switch name {
+	case "first-child":
+		return func(element *markup.Node) bool {
+			index, _ := position(element)
+			return index == 1
+		}, nil
+	case "last-child":
+		return func(element *markup.Node) bool {
+			index, size := position(element)
+			return index == size
+		}, nil
+	case "only-child":
+		return func(element *markup.Node) bool {
+			_, size := position(element)
+			return size == 1
+		}, nil
+	case "nth-child":
+		argument, err := p.parseArgument()
+		if err != nil {
+			return nil, err
+		}
+		a, b, err := parseNth(argument)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		return nthFilter(a, b), nil
+	case "not":
+		if !p.consume('(') {
+			return nil, p.unexpected()
+		}
+		p.skipSpace()
+		inner, err := p.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(')') {
+			return nil, p.unexpected()
+		}
+		return func(element *markup.Node) bool {
+			return !inner.matches(element)
+		}, nil
+	case "":
+		return nil, p.unexpected()
+	default:
+		return nil, p.errorf("unsupported pseudo-class :%s", name)
+	}
+}
+
+// parseArgument returns the text between parentheses.
+func (p *parser) parseArgument() (string, error) {
+	if !p.consume('(') {
+		return "", p.unexpected()
+	}
+	end := strings.IndexByte(p.source[p.pos:], ')')
+	if end < 0 {
+		return "", p.errorf("missing )")
+	}
+	argument := p.source[p.pos : p.pos+end]
+	p.pos += end + 1
+	return argument, nil
+}
+
+// parseNth parses the an+b argument of :nth-child.
+func parseNth(argument string) (int, int, error) {
+	argument = strings.ToLower(strings.Join(strings.Fields(argument), ""))
+	switch argument {
+	case "odd":
+		return 2, 1, nil
+	case "even":
+		return 2, 0, nil
+	}
+
+	before, after, hasN := strings.Cut(argument, "n")
+	if !hasN {
+		b, err := strconv.Atoi(argument)
+		if err != nil || b < 1 {
+			return 0, 0, fmt.Errorf(":nth-child expects a positive integer, odd, even or an+b, got %q", argument)
+		}
+		return 0, b, nil
+	}
+
+	a := 1
+	switch before {
+	case "", "+":
+	case "-":
+		a = -1
+	default:
+		parsed, err := strconv.Atoi(before)
+		if err != nil {
+			return 0, 0, fmt.Errorf(":nth-child has invalid step %q", before)
+		}
+		a = parsed
+	}
+
+	b := 0
+	if after != "" {
+		parsed, err := strconv.Atoi(after)
+		if err != nil {
+			return 0, 0, fmt.Errorf(":nth-child has invalid offset %q", after)
+		}
+		b = parsed
+	}
+
+	return a, b, nil
+}
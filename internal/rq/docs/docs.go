@@ -0,0 +1,355 @@
+// Package docs renders rq test files as a Markdown or HTML walkthrough of the
+// API they exercise: each request, the response it expects and the variables
+// it needs.
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/jacoelho/rq/internal/rq/compile"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/templating"
+	"github.com/jacoelho/rq/internal/rq/yaml"
+)
+
+// Format selects how documentation is rendered.
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ErrInvalidFormat is returned for unknown documentation formats.
+var ErrInvalidFormat = fmt.Errorf("docs output format must be one of: markdown, html")
+
+// ParseFormat parses a documentation format name.
+func ParseFormat(input string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "markdown", "md", "":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	default:
+		return FormatMarkdown, fmt.Errorf("%w, got: %s", ErrInvalidFormat, input)
+	}
+}
+
+// File is the documentation of one test file. Description and Docs come from
+// the steps that only document the file.
+type File struct {
+	Filename    string
+	Description string
+	Docs        string
+	Steps       []Step
+}
+
+// Step is the documentation of one step, ready to render.
+type Step struct {
+	Number    int
+	Title     string // First line of the description, or the method and URL
+	Details   string // Rest of the description
+	Docs      string
+	Request   string   // HTTP-style request line, headers and body
+	When      string   // Condition that guards the step
+	Variables []string // Template variables the request and checks refer to
+	Asserts   string   // YAML of the asserts
+	Captures  string   // YAML of the captures
+	Checks    []string
+}
+
+// Build parses and validates each file and collects its documentation.
+func Build(files []string) ([]File, error) {
+	out := make([]File, 0, len(files))
+	for _, filename := range files {
+		steps, err := parseFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := buildFile(filename, steps)
+		if err != nil {
+			return nil, fmt.Errorf("failed to document file %s: %w", filename, err)
+		}
+		out = append(out, file)
+	}
+
+	return out, nil
+}
+
+func parseFile(filename string) ([]model.Step, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+
+	steps, positions, err := yaml.ParseWithPositions(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", filename, err)
+	}
+	if err := compile.ValidateStepsWithPositions(steps, positions); err != nil {
+		return nil, fmt.Errorf("failed to validate file %s: %w", filename, err)
+	}
+
+	return steps, nil
+}
+
+func buildFile(filename string, steps []model.Step) (File, error) {
+	file := File{Filename: filename}
+	var descriptions, docs []string
+	for _, step := range steps {
+		if !step.DocsOnly() {
+			continue
+		}
+		descriptions = appendText(descriptions, step.Description)
+		docs = appendText(docs, step.Docs)
+	}
+	file.Description = strings.Join(descriptions, "\n\n")
+	file.Docs = strings.Join(docs, "\n\n")
+
+	for _, step := range steps {
+		if step.DocsOnly() {
+			continue
+		}
+
+		documented, err := buildStep(step)
+		if err != nil {
+			return File{}, fmt.Errorf("step %d: %w", len(file.Steps)+1, err)
+		}
+		documented.Number = len(file.Steps) + 1
+		file.Steps = append(file.Steps, documented)
+	}
+
+	return file, nil
+}
+
+func appendText(texts []string, text string) []string {
+	if text = strings.TrimSpace(text); text != "" {
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+func buildStep(step model.Step) (Step, error) {
+	title, details := stepTitle(step)
+	documented := Step{
+		Title:     title,
+		Details:   details,
+		Docs:      strings.TrimSpace(step.Docs),
+		When:      step.When,
+		Variables: stepVariables(step),
+	}
+
+	if !step.ChecksOnly() {
+		request, err := requestText(step)
+		if err != nil {
+			return Step{}, err
+		}
+		documented.Request = request
+	}
+
+	asserts, err := yaml.EncodeAsserts(step.Asserts)
+	if err != nil {
+		return Step{}, err
+	}
+	if text := strings.TrimSpace(string(asserts)); text != "{}" {
+		documented.Asserts = text
+	}
+
+	if step.Captures != nil {
+		captures, err := goyaml.Marshal(step.Captures)
+		if err != nil {
+			return Step{}, fmt.Errorf("encode captures: %w", err)
+		}
+		if text := strings.TrimSpace(string(captures)); text != "{}" {
+			documented.Captures = text
+		}
+	}
+
+	for _, check := range step.Checks {
+		line := fmt.Sprintf("%s %s", check.Name, check.Predicate.Operation)
+		if check.Predicate.HasValue {
+			line += fmt.Sprintf(" %v", check.Predicate.Value)
+		}
+		documented.Checks = append(documented.Checks, line)
+	}
+
+	return documented, nil
+}
+
+// stepTitle splits the description of the step into its first line and the
+// rest. Steps without a description are titled by their method and URL.
+func stepTitle(step model.Step) (string, string) {
+	if description := strings.TrimSpace(step.Description); description != "" {
+		title, details, _ := strings.Cut(description, "\n")
+		return strings.TrimSpace(title), strings.TrimSpace(details)
+	}
+	if step.ChecksOnly() {
+		return "Checks", ""
+	}
+	return step.Method + " " + step.URL, ""
+}
+
+// requestText renders the request as it would appear on the wire: the request
+// line with its query, the headers and the body.
+func requestText(step model.Step) (string, error) {
+	var b strings.Builder
+	b.WriteString(step.Method + " " + step.URL)
+	for i, entry := range step.Query {
+		separator := "&"
+		if i == 0 && !strings.Contains(step.URL, "?") {
+			separator = "?"
+		}
+		b.WriteString(separator + url.QueryEscape(entry.Key) + "=" + entry.Value)
+	}
+	b.WriteString("\n")
+
+	for _, entry := range step.Headers {
+		b.WriteString(entry.Key + ": " + entry.Value + "\n")
+	}
+
+	switch {
+	case step.Body.IsStructured():
+		body, err := json.MarshalIndent(step.Body.Value, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("encode body: %w", err)
+		}
+		b.WriteString("\n" + string(body) + "\n")
+	case step.Body.Text != "":
+		b.WriteString("\n" + strings.TrimRight(step.Body.Text, "\n") + "\n")
+	case step.BodyFile != "":
+		b.WriteString("\n< " + step.BodyFile + "\n")
+	case len(step.Multipart) > 0:
+		b.WriteString("\n")
+		for _, part := range step.Multipart {
+			if part.File != "" {
+				b.WriteString(part.Name + "=< " + part.File + "\n")
+				continue
+			}
+			b.WriteString(part.Name + "=" + part.Value + "\n")
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// stepVariables lists, sorted, the variables referenced by {{.name}} actions
+// in the request and checks of step, and the variables the checks assert on.
+func stepVariables(step model.Step) []string {
+	texts := []string{step.URL, step.BodyFile, step.Body.Text}
+	for _, part := range step.Multipart {
+		texts = append(texts, part.Value, part.File, part.Filename)
+	}
+	for _, entry := range step.Headers {
+		texts = append(texts, entry.Value)
+	}
+	for _, entry := range step.Query {
+		texts = append(texts, entry.Value)
+	}
+	texts = appendValueTexts(texts, step.Body.Value)
+	for _, check := range step.Checks {
+		texts = append(texts, fmt.Sprint(check.Predicate.Value))
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, check := range step.Checks {
+		if !seen[check.Name] {
+			seen[check.Name] = true
+			names = append(names, check.Name)
+		}
+	}
+	for _, text := range texts {
+		for _, name := range templateVariables(text) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func appendValueTexts(texts []string, value any) []string {
+	switch v := value.(type) {
+	case string:
+		return append(texts, v)
+	case []any:
+		for _, item := range v {
+			texts = appendValueTexts(texts, item)
+		}
+	case map[string]any:
+		for _, item := range v {
+			texts = appendValueTexts(texts, item)
+		}
+	}
+	return texts
+}
+
+// templateVariables returns the top-level field names referenced by text.
+// Text that is not a valid template yields none.
+func templateVariables(text string) []string {
+	if !strings.Contains(text, "{{") {
+		return nil
+	}
+
+	tmpl, err := template.New("docs").Funcs(templating.FuncMap()).Parse(text)
+	if err != nil || tmpl.Tree == nil {
+		return nil
+	}
+
+	var names []string
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			names = append(names, n.Ident[0])
+		case *parse.ChainNode:
+			walk(n.Node)
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			// Fields inside range and with refer to the new dot.
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		}
+	}
+	walk(tmpl.Tree.Root)
+
+	return names
+}
@@ -0,0 +1,180 @@
+package docs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const ordersFile = `- description: Orders API
+  docs: |
+    Creates an order and reads it back.
+
+    Needs a <token>.
+- method: POST
+  url: "{{.base_url}}/orders"
+  description: |
+    Create an order
+    The response carries the new order id.
+  headers:
+    Authorization: Bearer {{.token}}
+  query:
+    dry_run: "false"
+  body:
+    sku: "{{.sku}}"
+  asserts:
+    status:
+      - op: equals
+        value: 201
+  captures:
+    jsonpath:
+      - name: order_id
+        path: $.id
+- method: GET
+  url: "{{.base_url}}/orders/{{.order_id}}"
+  when: order_id != ""
+- checks:
+    - name: total
+      op: less_than
+      value: "{{ .limit }}"
+`
+
+func TestTemplateVariables(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "plain text", text: "https://example.com", want: nil},
+		{name: "fields", text: "{{.host}}/users/{{ .user.id }}", want: []string{"host", "user"}},
+		{name: "function arguments", text: `{{ sum .prices 1 }} {{ .name | printf "%s" }}`, want: []string{"prices", "name"}},
+		{name: "conditionals", text: "{{ if .debug }}{{ .verbose }}{{ else }}{{ .quiet }}{{ end }}", want: []string{"debug", "verbose", "quiet"}},
+		{name: "range changes dot", text: "{{ range .items }}{{ .id }}{{ end }}", want: []string{"items"}},
+		{name: "invalid template", text: "{{ .host", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := templateVariables(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("templateVariables(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "orders.yaml")
+	if err := os.WriteFile(filename, []byte(ordersFile), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	files, err := Build([]string{filename})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("files = %d, want 1", len(files))
+	}
+
+	file := files[0]
+	if file.Description != "Orders API" || !strings.HasPrefix(file.Docs, "Creates an order") {
+		t.Fatalf("file description = %q, docs = %q", file.Description, file.Docs)
+	}
+	if len(file.Steps) != 3 {
+		t.Fatalf("steps = %d, want 3 without the docs-only step", len(file.Steps))
+	}
+
+	create := file.Steps[0]
+	if create.Number != 1 || create.Title != "Create an order" || create.Details != "The response carries the new order id." {
+		t.Fatalf("create step = %+v", create)
+	}
+	wantRequest := "POST {{.base_url}}/orders?dry_run=false\nAuthorization: Bearer {{.token}}\n\n{\n  \"sku\": \"{{.sku}}\"\n}"
+	if create.Request != wantRequest {
+		t.Fatalf("request = %q, want %q", create.Request, wantRequest)
+	}
+	if !reflect.DeepEqual(create.Variables, []string{"base_url", "sku", "token"}) {
+		t.Fatalf("variables = %v", create.Variables)
+	}
+	if !strings.Contains(create.Asserts, "value: 201") || !strings.Contains(create.Captures, "name: order_id") {
+		t.Fatalf("asserts = %q, captures = %q", create.Asserts, create.Captures)
+	}
+
+	read := file.Steps[1]
+	if read.Title != "GET {{.base_url}}/orders/{{.order_id}}" || read.When != `order_id != ""` || read.Asserts != "" {
+		t.Fatalf("read step = %+v", read)
+	}
+
+	checks := file.Steps[2]
+	if checks.Title != "Checks" || checks.Request != "" || !reflect.DeepEqual(checks.Checks, []string{"total less_than {{ .limit }}"}) {
+		t.Fatalf("checks step = %+v", checks)
+	}
+	if !reflect.DeepEqual(checks.Variables, []string{"limit", "total"}) {
+		t.Fatalf("checks variables = %v", checks.Variables)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "orders.yaml")
+	if err := os.WriteFile(filename, []byte(ordersFile), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	files, err := Build([]string{filename})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		format Format
+		want   []string
+	}{
+		{
+			format: FormatMarkdown,
+			want: []string{
+				"# " + filename + "\n\nOrders API\n\nCreates an order and reads it back.\n\nNeeds a <token>.\n",
+				"\n## 1. Create an order\n\nThe response carries the new order id.\n\n```http\nPOST {{.base_url}}/orders?dry_run=false\n",
+				"\nVariables used: `base_url`, `sku`, `token`\n",
+				"\nExpected response:\n\n```yaml\nstatus:\n",
+				"\n## 2. GET {{.base_url}}/orders/{{.order_id}}\n",
+				"\nRuns when: `order_id != \"\"`\n",
+				"\nChecks:\n\n- `total less_than {{ .limit }}`\n",
+			},
+		},
+		{
+			format: FormatHTML,
+			want: []string{
+				"<h1>" + filename + "</h1>",
+				"<p>Needs a &lt;token&gt;.</p>",
+				"<h2>1. Create an order</h2>\n<p>The response carries the new order id.</p>",
+				"<p>Variables used: <code>base_url</code>, <code>sku</code>, <code>token</code></p>",
+				"<li><code>total less_than {{ .limit }}</code></li>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			if err := Write(&out, tt.format, files); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Fatalf("output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
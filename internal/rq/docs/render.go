@@ -0,0 +1,146 @@
+package docs
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Write renders files in format. Markdown keeps docs text as written; HTML
+// escapes it and splits it into paragraphs at blank lines.
+func Write(w io.Writer, format Format, files []File) error {
+	switch format {
+	case FormatHTML:
+		return writeHTML(w, files)
+	default:
+		return writeMarkdown(w, files)
+	}
+}
+
+func writeMarkdown(w io.Writer, files []File) error {
+	var b strings.Builder
+	for i, file := range files {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "# %s\n", file.Filename)
+		writeMarkdownText(&b, file.Description)
+		writeMarkdownText(&b, file.Docs)
+
+		for _, step := range file.Steps {
+			fmt.Fprintf(&b, "\n## %d. %s\n", step.Number, step.Title)
+			writeMarkdownText(&b, step.Details)
+			writeMarkdownText(&b, step.Docs)
+
+			if step.Request != "" {
+				fmt.Fprintf(&b, "\n```http\n%s\n```\n", step.Request)
+			}
+			if step.When != "" {
+				fmt.Fprintf(&b, "\nRuns when: `%s`\n", step.When)
+			}
+			if len(step.Variables) > 0 {
+				fmt.Fprintf(&b, "\nVariables used: `%s`\n", strings.Join(step.Variables, "`, `"))
+			}
+			if step.Asserts != "" {
+				fmt.Fprintf(&b, "\nExpected response:\n\n```yaml\n%s\n```\n", step.Asserts)
+			}
+			if step.Captures != "" {
+				fmt.Fprintf(&b, "\nCaptures:\n\n```yaml\n%s\n```\n", step.Captures)
+			}
+			if len(step.Checks) > 0 {
+				b.WriteString("\nChecks:\n\n")
+				for _, check := range step.Checks {
+					fmt.Fprintf(&b, "- `%s`\n", check)
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownText(b *strings.Builder, text string) {
+	if text != "" {
+		fmt.Fprintf(b, "\n%s\n", text)
+	}
+}
+
+var htmlTemplate = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"paragraphs": paragraphs,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>API documentation</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+pre { background: #f5f5f5; padding: 0.75rem; overflow-x: auto; }
+code { font-family: ui-monospace, monospace; }
+</style>
+</head>
+<body>
+{{- range .}}
+<section>
+<h1>{{.Filename}}</h1>
+{{- range paragraphs .Description}}
+<p>{{.}}</p>
+{{- end}}
+{{- range paragraphs .Docs}}
+<p>{{.}}</p>
+{{- end}}
+{{- range .Steps}}
+<h2>{{.Number}}. {{.Title}}</h2>
+{{- range paragraphs .Details}}
+<p>{{.}}</p>
+{{- end}}
+{{- range paragraphs .Docs}}
+<p>{{.}}</p>
+{{- end}}
+{{- if .Request}}
+<pre><code>{{.Request}}</code></pre>
+{{- end}}
+{{- if .When}}
+<p>Runs when: <code>{{.When}}</code></p>
+{{- end}}
+{{- if .Variables}}
+<p>Variables used:{{range $i, $name := .Variables}}{{if $i}},{{end}} <code>{{$name}}</code>{{end}}</p>
+{{- end}}
+{{- if .Asserts}}
+<p>Expected response:</p>
+<pre><code>{{.Asserts}}</code></pre>
+{{- end}}
+{{- if .Captures}}
+<p>Captures:</p>
+<pre><code>{{.Captures}}</code></pre>
+{{- end}}
+{{- if .Checks}}
+<p>Checks:</p>
+<ul>
+{{- range .Checks}}
+<li><code>{{.}}</code></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, files []File) error {
+	return htmlTemplate.Execute(w, files)
+}
+
+// paragraphs splits text at blank lines.
+func paragraphs(text string) []string {
+	var out []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			out = append(out, paragraph)
+		}
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
+)
+
+// screenshotTimeout bounds one run of the --screenshot-cmd renderer, so a
+// hung browser cannot stall the suite.
+const screenshotTimeout = 30 * time.Second
+
+// htmlResponse is an HTML body a step received, with the URL it came from so
+// the renderer can resolve relative links.
+type htmlResponse struct {
+	url  string
+	body []byte
+}
+
+func isHTMLResponse(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// failureArtifacts produces the artifacts of a failed step: a screenshot of
+// its last HTML response when --screenshot-cmd is set. A renderer failure is
+// reported and leaves the step without artifacts; it never changes the
+// step result.
+func (r *Runner) failureArtifacts(ctx context.Context, filename string, index int, log *attemptLog) []string {
+	if r.config == nil || r.config.ScreenshotCommand == "" || log == nil || log.html == nil {
+		return nil
+	}
+
+	path := filepath.Join(r.config.ArtifactsDir, artifactName(filename, index, ".png"))
+	if err := renderScreenshot(ctx, r.config.ScreenshotCommand, path, log.html); err != nil {
+		r.printf(i18n.ScreenshotError, fmt.Sprintf("%s step %d", filename, index), err)
+		return nil
+	}
+
+	return []string{path}
+}
+
+// renderScreenshot runs command with the HTML on stdin and path as its last
+// argument. The command also sees RQ_ARTIFACT, the same path, and RQ_URL,
+// the URL the HTML was served from. It is split on whitespace and run
+// without a shell; wrap anything more elaborate in a script.
+func renderScreenshot(ctx context.Context, command, path string, html *htmlResponse) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return errors.New("screenshot command is empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, screenshotTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], append(args[1:], path)...)
+	cmd.Stdin = bytes.NewReader(html.body)
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "RQ_ARTIFACT="+path, "RQ_URL="+html.url)
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("renderer did not write %s", path)
+	}
+
+	return nil
+}
+
+// artifactName names the artifact of a step after its file, replacing path
+// separators and other unsafe characters, so files with the same base name in
+// different directories do not collide.
+func artifactName(filename string, index int, ext string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.TrimPrefix(filepath.ToSlash(filepath.Clean(filename)), "./"))
+
+	return fmt.Sprintf("%s-step%d%s", safe, index, ext)
+}
@@ -0,0 +1,103 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteCompiledFileScreenshotsFailedHTMLSteps(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("renderer fixture is a shell script")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("<h1>Oops</h1>"))
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"oops"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	renderer := filepath.Join(dir, "render.sh")
+	script := "#!/bin/sh\ncat > \"$1\"\necho \"$RQ_URL\" > \"$RQ_ARTIFACT.url\"\n"
+	if err := os.WriteFile(renderer, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	failing := func(path string) model.Step {
+		return model.Step{
+			Method:  "GET",
+			URL:     server.URL + path,
+			Asserts: model.Asserts{Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200, HasValue: true}}}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		path    string
+		want    []string
+		wantErr string
+	}{
+		{name: "html_failure", command: renderer, path: "/page", want: []string{filepath.Join(dir, "html_failure", "suite_pages.yaml-step0.png")}},
+		{name: "json_failure", command: renderer, path: "/json"},
+		{name: "renderer_failure", command: "false", path: "/page", wantErr: "Screenshot of suite/pages.yaml step 0 failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var errOutput bytes.Buffer
+			runner := newDefault()
+			runner.SetErrorOutput(&errOutput)
+			runner.config = &config.Config{ScreenshotCommand: tt.command, ArtifactsDir: filepath.Join(dir, tt.name)}
+
+			outcome, err := runner.executeCompiledFile(context.Background(), CompiledFile{
+				Filename: "suite/pages.yaml",
+				Steps:    []model.Step{failing(tt.path)},
+			})
+			if err == nil {
+				t.Fatal("executeCompiledFile() error = nil, want the failing status assert")
+			}
+
+			got := outcome.steps[0].Artifacts
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("artifacts = %q, want %q", got, tt.want)
+			}
+			if tt.wantErr != "" && !strings.Contains(errOutput.String(), tt.wantErr) {
+				t.Errorf("error output = %q, want containing %q", errOutput.String(), tt.wantErr)
+			}
+			if len(tt.want) == 0 {
+				return
+			}
+
+			html, err := os.ReadFile(tt.want[0])
+			if err != nil || string(html) != "<h1>Oops</h1>" {
+				t.Errorf("artifact = %q, %v, want the HTML body", html, err)
+			}
+			url, err := os.ReadFile(tt.want[0] + ".url")
+			if err != nil || strings.TrimSpace(string(url)) != server.URL+tt.path {
+				t.Errorf("RQ_URL = %q, %v, want %s", url, err, server.URL+tt.path)
+			}
+		})
+	}
+}
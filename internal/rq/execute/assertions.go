@@ -1,8 +1,12 @@
 package execute
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/jacoelho/rq/internal/rq/assert"
 	"github.com/jacoelho/rq/internal/rq/capture"
@@ -23,12 +27,51 @@ func (r *Runner) executeAssertions(asserts model.Asserts, resp *http.Response, s
 	if err := runner.runHeaders(asserts.Headers); err != nil {
 		return err
 	}
+	if err := runner.runHeaderSet("Content-Language", asserts.ContentLanguage); err != nil {
+		return err
+	}
+	if err := runner.runHeaderSet("Vary", asserts.Vary); err != nil {
+		return err
+	}
+	if err := runner.runCookies(asserts.Cookies); err != nil {
+		return err
+	}
 	if err := runner.runCertificates(asserts.Certificate); err != nil {
 		return err
 	}
 	if err := runner.runJSONPath(asserts.JSONPath); err != nil {
 		return err
 	}
+	if err := runner.runGraphQL(asserts.GraphQL); err != nil {
+		return err
+	}
+	if err := runner.runCSS(asserts.CSS); err != nil {
+		return err
+	}
+	if err := runner.runURL(asserts.URL); err != nil {
+		return err
+	}
+	if err := runner.runTLS(asserts.TLS); err != nil {
+		return err
+	}
+	if err := runner.runTTFB(asserts.TTFB); err != nil {
+		return err
+	}
+	if err := runner.runDuration(asserts.Duration); err != nil {
+		return err
+	}
+	if err := runner.runAttempts(asserts.Attempts); err != nil {
+		return err
+	}
+	if err := runner.runRedirects(asserts.Redirects); err != nil {
+		return err
+	}
+	if err := runner.runContinue(asserts.Continue); err != nil {
+		return err
+	}
+	if err := runner.runAllow(asserts.Allow); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -93,7 +136,12 @@ func (r assertionRunner) runHeaders(asserts []model.HeaderAssert) error {
 			}
 		}
 
-		ok, err := r.evaluate(actual, current.Predicate)
+		value, err := headerOperand(actual, current.Predicate)
+		if err != nil {
+			return fmt.Errorf("header %s assertion failed: %w", current.Name, err)
+		}
+
+		ok, err := r.evaluate(value, current.Predicate)
 		if err != nil {
 			return fmt.Errorf("header assertion error: %w", err)
 		}
@@ -105,6 +153,55 @@ func (r assertionRunner) runHeaders(asserts []model.HeaderAssert) error {
 	return nil
 }
 
+// headerOperand returns the header value as a number for numeric
+// operations, such as greater_than on Content-Length or Retry-After.
+func headerOperand(actual string, input model.Predicate) (any, error) {
+	op, err := predicate.ParseOperator(input.Operation)
+	if err != nil || !op.IsNumeric() {
+		return actual, nil
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected a numeric value for %s, got %q", input.Operation, actual)
+	}
+
+	return value, nil
+}
+
+func (r assertionRunner) runCookies(asserts []model.CookieAssert) error {
+	for _, current := range asserts {
+		actual, err := capture.ExtractCookie(r.resp, current.Name, current.Attribute)
+		if err != nil {
+			if !capture.IsNotFound(err) {
+				return fmt.Errorf("cookie assertion failed for %s: %w", current.Name, err)
+			}
+			if current.Predicate.Operation != string(predicate.OpExists) {
+				return fmt.Errorf("cookie assertion failed for %s: %s not set by the response", current.Name, cookieSubject(current.Attribute))
+			}
+			actual = nil
+		}
+
+		ok, err := r.evaluate(actual, current.Predicate)
+		if err != nil {
+			return fmt.Errorf("cookie assertion failed for %s: %w", current.Name, err)
+		}
+		if !ok {
+			return fmt.Errorf("cookie assertion failed for %s: expected %s %s %v, got %v", current.Name, cookieSubject(current.Attribute), current.Predicate.Operation, current.Predicate.Value, actual)
+		}
+	}
+
+	return nil
+}
+
+// cookieSubject names what a cookie assert compares in its messages.
+func cookieSubject(attribute string) string {
+	if attribute == "" {
+		return "cookie"
+	}
+	return attribute
+}
+
 func (r assertionRunner) runCertificates(asserts []model.CertificateAssert) error {
 	for _, current := range asserts {
 		actual, err := capture.ExtractCertificateField(r.resp, current.Name)
@@ -133,7 +230,7 @@ func (r assertionRunner) runJSONPath(asserts []model.JSONPathAssert) error {
 	}
 
 	for _, current := range asserts {
-		actual, err := capture.ExtractJSONPathFromData(r.selectors.data, current.Path)
+		actual, err := r.selectors.jsonPath(current.Path)
 		if err != nil {
 			actual, err = resolveJSONPathAssertionValue(current, err)
 			if err != nil {
@@ -152,3 +249,305 @@ func (r assertionRunner) runJSONPath(asserts []model.JSONPathAssert) error {
 
 	return nil
 }
+
+// runGraphQL checks the errors member for no_errors and runs the data and
+// errors asserts as JSONPath asserts rooted at the response.
+func (r assertionRunner) runGraphQL(asserts *model.GraphQLAsserts) error {
+	if asserts == nil {
+		return nil
+	}
+	if r.selectors.err != nil {
+		return fmt.Errorf("graphql assertion failed: %w", r.selectors.err)
+	}
+
+	if asserts.NoErrors {
+		errs, err := capture.ExtractJSONPathFromData(r.selectors.data, "$.errors")
+		if err != nil && !capture.IsNotFound(err) {
+			return fmt.Errorf("graphql assertion failed: %w", err)
+		}
+		if list, ok := errs.([]any); ok && len(list) > 0 {
+			return fmt.Errorf("graphql assertion failed: expected no errors, got %d: %s", len(list), graphQLErrorMessage(list[0]))
+		}
+	}
+
+	return r.runJSONPath(asserts.JSONPath())
+}
+
+// graphQLErrorMessage returns the message of a GraphQL error, or the error
+// itself when it has none.
+func graphQLErrorMessage(graphqlErr any) string {
+	if fields, ok := graphqlErr.(map[string]any); ok {
+		if message, ok := fields["message"].(string); ok {
+			return message
+		}
+	}
+	return fmt.Sprint(graphqlErr)
+}
+
+func (r assertionRunner) runCSS(asserts []model.CSSAssert) error {
+	if len(asserts) == 0 {
+		return nil
+	}
+	if r.selectors.documentErr != nil {
+		return fmt.Errorf("css assertion failed for %s: %w", asserts[0].Selector, r.selectors.documentErr)
+	}
+
+	for _, current := range asserts {
+		actual, err := capture.ExtractCSS(r.selectors.document, current.Selector, current.Attr)
+		if err != nil {
+			if !capture.IsNotFound(err) {
+				return fmt.Errorf("css assertion failed for %s: %w", current.Selector, err)
+			}
+			if current.Predicate.Operation != string(predicate.OpExists) {
+				return fmt.Errorf("css assertion failed for %s: selector matched no element", current.Selector)
+			}
+			actual = nil
+		}
+
+		ok, err := r.evaluate(actual, current.Predicate)
+		if err != nil {
+			return fmt.Errorf("css assertion failed for %s: %w", current.Selector, err)
+		}
+		if !ok {
+			return fmt.Errorf("css assertion failed for %s: expected %s %v, but condition was not met", current.Selector, current.Predicate.Operation, current.Predicate.Value)
+		}
+	}
+
+	return nil
+}
+
+func (r assertionRunner) runURL(asserts []model.URLAssert) error {
+	for _, current := range asserts {
+		actual, err := capture.ExtractURL(r.resp)
+		if err != nil {
+			return fmt.Errorf("url extraction failed: %w", err)
+		}
+
+		ok, err := r.evaluate(actual, current.Predicate)
+		if err != nil {
+			return fmt.Errorf("url assertion error: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("url assertion failed: expected %s %v, got %v", current.Predicate.Operation, current.Predicate.Value, actual)
+		}
+	}
+
+	return nil
+}
+
+func (r assertionRunner) runTLS(asserts []model.TLSAssert) error {
+	for _, current := range asserts {
+		actual, err := capture.ExtractTLSField(r.resp, current.Name)
+		if err != nil {
+			return fmt.Errorf("tls assertion failed for field %s: %w", current.Name, err)
+		}
+
+		ok, err := r.evaluate(actual, current.Predicate)
+		if err != nil {
+			return fmt.Errorf("tls assertion error: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("tls %s assertion failed: expected %s %v, got %v", current.Name, current.Predicate.Operation, current.Predicate.Value, actual)
+		}
+	}
+
+	return nil
+}
+
+func (r assertionRunner) runTTFB(asserts []model.TTFBAssert) error {
+	for _, current := range asserts {
+		ttfb, ok := responseTTFB(r.resp)
+		if !ok {
+			return fmt.Errorf("ttfb assertion failed: time to first byte was not measured")
+		}
+		actual := ttfb.Milliseconds()
+
+		ok, err := r.evaluate(actual, current.Predicate)
+		if err != nil {
+			return fmt.Errorf("ttfb assertion error: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("ttfb assertion failed: expected %s %v ms, got %d ms", current.Predicate.Operation, current.Predicate.Value, actual)
+		}
+	}
+
+	return nil
+}
+
+func (r assertionRunner) runDuration(asserts []model.DurationAssert) error {
+	for _, current := range asserts {
+		duration, ok := responseDuration(r.resp)
+		if !ok {
+			return fmt.Errorf("duration assertion failed: response time was not measured")
+		}
+		actual := duration.Milliseconds()
+
+		input := current.Predicate
+		if input.HasValue {
+			millis, err := model.DurationMillis(input.Value)
+			if err != nil {
+				return fmt.Errorf("duration assertion error: %w", err)
+			}
+			input.Value = millis
+		}
+
+		ok, err := r.evaluate(actual, input)
+		if err != nil {
+			return fmt.Errorf("duration assertion error: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("duration assertion failed: expected %s %v, got %d ms", current.Predicate.Operation, current.Predicate.Value, actual)
+		}
+	}
+
+	return nil
+}
+
+func (r assertionRunner) runAttempts(asserts []model.AttemptsAssert) error {
+	for _, current := range asserts {
+		actual := responseAttempt(r.resp)
+
+		ok, err := r.evaluate(actual, current.Predicate)
+		if err != nil {
+			return fmt.Errorf("attempts assertion error: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("attempts assertion failed: expected %s %v, got %d", current.Predicate.Operation, current.Predicate.Value, actual)
+		}
+	}
+
+	return nil
+}
+
+func (r assertionRunner) runRedirects(asserts []model.RedirectsAssert) error {
+	for _, current := range asserts {
+		actual := responseRedirects(r.resp)
+
+		ok, err := r.evaluate(actual, current.Predicate)
+		if err != nil {
+			return fmt.Errorf("redirects assertion error: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("redirects assertion failed: expected %s %v, got %d", current.Predicate.Operation, current.Predicate.Value, actual)
+		}
+	}
+
+	return nil
+}
+
+func (r assertionRunner) runContinue(asserts []model.ContinueAssert) error {
+	for _, current := range asserts {
+		actual := responseContinued(r.resp)
+
+		ok, err := r.evaluate(actual, current.Predicate)
+		if err != nil {
+			return fmt.Errorf("continue assertion error: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("continue assertion failed: expected %s %v, got %t", current.Predicate.Operation, current.Predicate.Value, actual)
+		}
+	}
+
+	return nil
+}
+
+func (r assertionRunner) runAllow(current *model.AllowAssert) error {
+	if current == nil {
+		return nil
+	}
+
+	allowed, err := capture.ExtractAllowedMethods(r.resp)
+	if err != nil && !capture.IsNotFound(err) {
+		return fmt.Errorf("allow extraction failed: %w", err)
+	}
+	if err != nil && len(current.Contains) > 0 {
+		return errors.New("allow assertion failed: response has no Allow header")
+	}
+
+	for _, method := range current.Contains {
+		if _, ok := allowed[strings.ToUpper(method)]; !ok {
+			return fmt.Errorf("allow assertion failed: expected %s in Allow %q", method, r.resp.Header.Get("Allow"))
+		}
+	}
+	for _, method := range current.Excludes {
+		if _, ok := allowed[strings.ToUpper(method)]; ok {
+			return fmt.Errorf("allow assertion failed: did not expect %s in Allow %q", method, r.resp.Header.Get("Allow"))
+		}
+	}
+
+	return nil
+}
+
+// checkBodilessResponse rejects body-based asserts up front when the response
+// cannot carry a body, instead of surfacing a confusing parse or mismatch error.
+func checkBodilessResponse(asserts model.Asserts, resp *http.Response, body []byte) error {
+	if len(body) > 0 || (len(asserts.JSONPath) == 0 && asserts.GraphQL == nil && len(asserts.CSS) == 0 && len(asserts.Golden) == 0 && len(asserts.JSONSchema) == 0) {
+		return nil
+	}
+
+	var reason string
+	switch {
+	case resp.Request != nil && resp.Request.Method == http.MethodHead:
+		reason = "HEAD responses have no body"
+	case resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified:
+		reason = fmt.Sprintf("status %d responses have no body", resp.StatusCode)
+	default:
+		return nil
+	}
+
+	kind := "golden"
+	switch {
+	case len(asserts.JSONPath) > 0:
+		kind = "jsonpath"
+	case asserts.GraphQL != nil:
+		kind = "graphql"
+	case len(asserts.CSS) > 0:
+		kind = "css"
+	case len(asserts.JSONSchema) > 0:
+		kind = "jsonschema"
+	}
+
+	return fmt.Errorf("%s assertion cannot run: %s", kind, reason)
+}
+
+// checkStableCaptures compares captured values against the first iteration
+// that produced them, so repeated runs can verify idempotent responses.
+func (r *Runner) checkStableCaptures(stepKey string, asserts []model.StableAssert, captures map[string]CaptureValue) error {
+	if len(asserts) == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stableCaptures == nil {
+		r.stableCaptures = make(map[string]any)
+	}
+
+	for _, current := range asserts {
+		actual, ok := captures[current.Capture]
+		if !ok {
+			return fmt.Errorf("stable assertion failed for %s: capture not set", current.Capture)
+		}
+
+		key := stepKey + "/" + current.Capture
+		expected, seen := r.stableCaptures[key]
+		if !seen {
+			r.stableCaptures[key] = actual.Value
+			continue
+		}
+
+		if !reflect.DeepEqual(expected, actual.Value) {
+			if actual.Redact {
+				return fmt.Errorf("stable assertion failed for %s: value changed between iterations", current.Capture)
+			}
+			return fmt.Errorf("stable assertion failed for %s: expected %v (first iteration), got %v", current.Capture, expected, actual.Value)
+		}
+	}
+
+	return nil
+}
+
+func stableCaptureKey(filename string, stepIndex int) string {
+	return fmt.Sprintf("%s#%d", filename, stepIndex)
+}
@@ -69,6 +69,49 @@ func TestExecuteHeaderAssertionsMissingHeaderUsesEmptyValue(t *testing.T) {
 	}
 }
 
+func TestExecuteHeaderAssertionsNumericOperations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		op      string
+		want    any
+		wantErr string
+	}{
+		{name: "less than", value: "512", op: "less_than", want: 1024},
+		{name: "between", value: " 120 ", op: "between", want: []any{60, 3600}},
+		{name: "greater or equal alias", value: "3", op: "greater_or_equal", want: 3},
+		{name: "outside range", value: "7200", op: "between", want: []any{60, 3600}, wantErr: "header X-Value assertion failed: expected between [60 3600], got 7200"},
+		{name: "not a number", value: "soon", op: "greater_than", want: 0, wantErr: `header X-Value assertion failed: expected a numeric value for greater_than, got "soon"`},
+		{name: "string operations keep the text", value: "512", op: "equals", want: "512"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"X-Value": {tt.value}}}
+			err := newDefault().executeAssertions(
+				model.Asserts{
+					Headers: []model.HeaderAssert{{Name: "X-Value", Predicate: model.Predicate{Operation: tt.op, Value: tt.want, HasValue: true}}},
+				},
+				resp,
+				selectorContext{},
+			)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("executeAssertions() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("executeAssertions() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestExecuteJSONPathAssertionsMissingPathHandling(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,93 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+type attemptKey struct{}
+
+// withAttempt records the 1-based attempt number of a step on its context,
+// so assertions on the response can tell whether retries were needed.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+type attemptLogKey struct{}
+
+// attemptLog collects the attempts of one step for its result. status holds
+// the response status of the attempt in progress, and html the last HTML
+// response, kept for failure screenshots.
+type attemptLog struct {
+	attempts []output.Attempt
+	status   int
+	html     *htmlResponse
+}
+
+// withAttemptLog returns a context whose steps record their attempts in the
+// returned log.
+func withAttemptLog(ctx context.Context) (context.Context, *attemptLog) {
+	log := &attemptLog{}
+	return context.WithValue(ctx, attemptLogKey{}, log), log
+}
+
+// stepAttemptLog returns the log on ctx, or nil, which discards records.
+func stepAttemptLog(ctx context.Context) *attemptLog {
+	log, _ := ctx.Value(attemptLogKey{}).(*attemptLog)
+	return log
+}
+
+// setResponse notes the status of the attempt in progress and keeps the body
+// when the response is HTML.
+func (l *attemptLog) setResponse(resp *http.Response, body []byte) {
+	if l == nil {
+		return
+	}
+
+	l.status = resp.StatusCode
+	if isHTMLResponse(resp) && len(body) > 0 && resp.Request != nil {
+		l.html = &htmlResponse{url: resp.Request.URL.String(), body: body}
+	}
+}
+
+// record closes the attempt in progress.
+func (l *attemptLog) record(number int, duration time.Duration, err error) {
+	if l == nil {
+		return
+	}
+
+	l.attempts = append(l.attempts, output.Attempt{Number: number, Status: l.status, Duration: duration, Error: err})
+	l.status = 0
+}
+
+// responseAttempt returns the attempt that produced resp, defaulting to 1.
+func responseAttempt(resp *http.Response) int {
+	if resp == nil || resp.Request == nil {
+		return 1
+	}
+
+	attempt, ok := resp.Request.Context().Value(attemptKey{}).(int)
+	if !ok {
+		return 1
+	}
+
+	return attempt
+}
+
+// responseRedirects counts the redirect hops that led to resp by walking the
+// chain of responses that triggered each request.
+func responseRedirects(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+
+	hops := 0
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		hops++
+	}
+
+	return hops
+}
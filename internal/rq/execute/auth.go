@@ -0,0 +1,157 @@
+package execute
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/templating"
+)
+
+// tokenExpirySkew renews tokens slightly before they expire, so a token is
+// not rejected while a request is in flight.
+const tokenExpirySkew = 10 * time.Second
+
+// oauthToken is a cached access token. A zero expires never expires.
+type oauthToken struct {
+	value   string
+	expires time.Time
+}
+
+func (t oauthToken) valid(now time.Time) bool {
+	return t.expires.IsZero() || now.Add(tokenExpirySkew).Before(t.expires)
+}
+
+// authorize sets the Authorization header of req from the step's auth block
+// and returns the credential sent, so debug output can redact it.
+func (r *Runner) authorize(ctx context.Context, step model.Step, req *http.Request, captures map[string]CaptureValue) (string, error) {
+	if step.Auth == nil || step.Auth.OAuth2 == nil {
+		return "", nil
+	}
+
+	token, err := r.oauth2Token(ctx, *step.Auth.OAuth2, captures)
+	if err != nil {
+		return "", fmt.Errorf("oauth2: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return token, nil
+}
+
+// oauth2Token returns a cached token for the client, requesting a new one
+// with the client credentials grant when there is none or it has expired.
+// authMu is held during the request, so files running in parallel share one
+// token instead of each fetching their own.
+func (r *Runner) oauth2Token(ctx context.Context, config model.OAuth2, captures map[string]CaptureValue) (string, error) {
+	rendered, err := renderOAuth2(config, captures)
+	if err != nil {
+		return "", err
+	}
+	key := strings.Join([]string{rendered.TokenURL, rendered.ClientID, rendered.ClientSecret, strings.Join(rendered.Scopes, " ")}, "\x00")
+
+	r.authMu.Lock()
+	defer r.authMu.Unlock()
+
+	if token, ok := r.oauthTokens[key]; ok && token.valid(time.Now()) {
+		return token.value, nil
+	}
+
+	token, err := r.requestOAuth2Token(ctx, rendered)
+	if err != nil {
+		return "", err
+	}
+
+	if r.oauthTokens == nil {
+		r.oauthTokens = make(map[string]oauthToken)
+	}
+	r.oauthTokens[key] = token
+
+	return token.value, nil
+}
+
+func renderOAuth2(config model.OAuth2, captures map[string]CaptureValue) (model.OAuth2, error) {
+	tmplVars := captureMapForTemplate(captures)
+
+	fields := []*string{&config.TokenURL, &config.ClientID, &config.ClientSecret}
+	scopes := make([]string, len(config.Scopes))
+	copy(scopes, config.Scopes)
+	config.Scopes = scopes
+	for i := range config.Scopes {
+		fields = append(fields, &config.Scopes[i])
+	}
+
+	for _, field := range fields {
+		rendered, err := templating.Apply(*field, tmplVars)
+		if err != nil {
+			return model.OAuth2{}, fmt.Errorf("failed to process template: %w", err)
+		}
+		*field = rendered
+	}
+
+	return config, nil
+}
+
+// requestOAuth2Token performs the client credentials grant of RFC 6749,
+// section 4.4, authenticating the client with HTTP Basic.
+func (r *Runner) requestOAuth2Token(ctx context.Context, config model.OAuth2) (oauthToken, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(config.Scopes) > 0 {
+		form.Set("scope", strings.Join(config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauthToken{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+
+	if err := r.rateLimiter.Wait(ctx, req.URL.Host); err != nil {
+		return oauthToken{}, fmt.Errorf("rate limiting interrupted: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return oauthToken{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readResponseBody(resp, r.maxResponseBytes())
+	if err != nil {
+		return oauthToken{}, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var payload struct {
+		AccessToken      string  `json:"access_token"`
+		ExpiresIn        float64 `json:"expires_in"`
+		Error            string  `json:"error"`
+		ErrorDescription string  `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return oauthToken{}, fmt.Errorf("token endpoint answered %s with an invalid body: %w", resp.Status, err)
+	}
+	if resp.StatusCode != http.StatusOK || payload.AccessToken == "" {
+		reason := payload.Error
+		if payload.ErrorDescription != "" {
+			reason += ": " + payload.ErrorDescription
+		}
+		if reason == "" {
+			reason = "no access_token in response"
+		}
+		return oauthToken{}, fmt.Errorf("token endpoint answered %s: %s", resp.Status, reason)
+	}
+
+	token := oauthToken{value: payload.AccessToken}
+	if payload.ExpiresIn > 0 {
+		token.expires = start.Add(time.Duration(payload.ExpiresIn * float64(time.Second)))
+	}
+
+	return token, nil
+}
@@ -0,0 +1,91 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepOAuth2(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		expiresIn  int
+		secret     string
+		wantIssued int64
+		wantErr    string
+	}{
+		{name: "token is cached across steps", expiresIn: 3600, secret: "s3cret", wantIssued: 1},
+		{name: "expiring token is renewed", expiresIn: 5, secret: "s3cret", wantIssued: 2},
+		{name: "rejected client fails the step", secret: "wrong", wantErr: "oauth2: token endpoint answered 401 Unauthorized: invalid_client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var issued atomic.Int64
+			tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id, secret, _ := r.BasicAuth()
+				if r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "orders:read orders:write" {
+					http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if id != "rq" || secret != "s3cret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					fmt.Fprint(w, `{"error":"invalid_client"}`)
+					return
+				}
+				fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, issued.Add(1), tt.expiresIn)
+			}))
+			t.Cleanup(tokenServer.Close)
+
+			var authorizations []string
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorizations = append(authorizations, r.Header.Get("Authorization"))
+			}))
+			t.Cleanup(apiServer.Close)
+
+			step := model.Step{
+				Method: "GET",
+				URL:    apiServer.URL,
+				Auth: &model.Auth{OAuth2: &model.OAuth2{
+					TokenURL:     tokenServer.URL,
+					ClientID:     "rq",
+					ClientSecret: "{{.client_secret}}",
+					Scopes:       []string{"orders:read", "orders:write"},
+				}},
+			}
+			runner := newDefault()
+			captures := map[string]CaptureValue{"client_secret": {Value: tt.secret, Redact: true}}
+
+			for range 2 {
+				_, err := runner.executeStep(context.Background(), step, captures, "")
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("executeStep() error = %v", err)
+				}
+			}
+
+			if got := issued.Load(); got != tt.wantIssued {
+				t.Fatalf("issued tokens = %d, want %d", got, tt.wantIssued)
+			}
+			if want := fmt.Sprintf("Bearer token-%d", tt.wantIssued); authorizations[1] != want {
+				t.Fatalf("Authorization = %q, want %q", authorizations[1], want)
+			}
+		})
+	}
+}
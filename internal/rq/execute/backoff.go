@@ -0,0 +1,51 @@
+package execute
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/random"
+)
+
+// retryWait is the pause after the given failed attempt. Constant backoff
+// waits retry_delay every time and exponential backoff doubles it after each
+// attempt. retry_max_wait caps the wait and retry_jitter then shortens it by a
+// random share, so clients retrying together spread out.
+func retryWait(options model.Options, attempt int) time.Duration {
+	wait := options.RetryDelay
+	if options.RetryBackoff == model.RetryBackoffExponential {
+		scaled := float64(wait) * math.Pow(2, float64(attempt-1))
+		wait = time.Duration(math.MaxInt64)
+		if scaled < math.MaxInt64 {
+			wait = time.Duration(scaled)
+		}
+	}
+	if options.RetryMaxWait > 0 {
+		wait = min(wait, options.RetryMaxWait)
+	}
+
+	if spread := int(float64(wait) * options.RetryJitter); spread > 0 {
+		wait -= time.Duration(random.IntN(spread))
+	}
+
+	return wait
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,99 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestRetryWait(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options model.Options
+		attempt int
+		want    time.Duration
+	}{
+		{name: "immediate by default", options: model.Options{Retries: 3}, attempt: 2, want: 0},
+		{name: "delay without backoff is constant", options: model.Options{RetryDelay: time.Second}, attempt: 3, want: time.Second},
+		{name: "constant", options: model.Options{RetryBackoff: "constant", RetryDelay: 200 * time.Millisecond}, attempt: 4, want: 200 * time.Millisecond},
+		{name: "exponential first", options: model.Options{RetryBackoff: "exponential", RetryDelay: 100 * time.Millisecond}, attempt: 1, want: 100 * time.Millisecond},
+		{name: "exponential doubles", options: model.Options{RetryBackoff: "exponential", RetryDelay: 100 * time.Millisecond}, attempt: 4, want: 800 * time.Millisecond},
+		{name: "exponential capped", options: model.Options{RetryBackoff: "exponential", RetryDelay: 100 * time.Millisecond, RetryMaxWait: 300 * time.Millisecond}, attempt: 4, want: 300 * time.Millisecond},
+		{name: "exponential overflow capped", options: model.Options{RetryBackoff: "exponential", RetryDelay: time.Second, RetryMaxWait: time.Minute}, attempt: 200, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := retryWait(tt.options, tt.attempt); got != tt.want {
+				t.Fatalf("retryWait() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryWaitJitter(t *testing.T) {
+	t.Parallel()
+
+	options := model.Options{RetryBackoff: "constant", RetryDelay: time.Second, RetryJitter: 0.5}
+	for range 100 {
+		got := retryWait(options, 1)
+		if got < 500*time.Millisecond || got > time.Second {
+			t.Fatalf("retryWait() = %s, want between 500ms and 1s", got)
+		}
+	}
+}
+
+func TestExecuteStepWithRetriesBackoff(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	step := model.Step{
+		Method: "GET",
+		URL:    server.URL,
+		Options: model.Options{
+			Retries:      3,
+			RetryBackoff: "exponential",
+			RetryDelay:   40 * time.Millisecond,
+		},
+		Asserts: model.Asserts{
+			Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200, HasValue: true}}},
+		},
+	}
+
+	start := time.Now()
+	if _, err := newDefault().executeStepWithRetries(context.Background(), step, map[string]CaptureValue{}, ""); err != nil {
+		t.Fatalf("executeStepWithRetries() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 120*time.Millisecond {
+		t.Fatalf("elapsed = %s, want at least 40ms + 80ms of backoff", elapsed)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("requests = %d, want 3", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	requests.Store(0)
+	step.Options.RetryDelay = time.Minute
+	if _, err := newDefault().executeStepWithRetries(ctx, step, map[string]CaptureValue{}, ""); err != context.DeadlineExceeded {
+		t.Fatalf("executeStepWithRetries() error = %v, want %v while waiting to retry", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,79 @@
+package execute
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/jacoelho/rq/internal/rq/baseline"
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+// applyBaseline records or checks step durations against --baseline. It
+// reports false when the run must fail, either because the baseline could not
+// be used or because a step regressed outside warn-only mode.
+func (r *Runner) applyBaseline() bool {
+	if r.config == nil || r.config.BaselinePath == "" {
+		return true
+	}
+
+	samples := baselineSamples(r.reported)
+
+	if r.config.UpdateBaseline {
+		if err := baseline.FromSamples(samples).Save(r.config.BaselinePath); err != nil {
+			r.printf(i18n.BaselineWriteError, err)
+			return false
+		}
+		r.printf(i18n.BaselineWritten, r.config.BaselinePath, len(samples))
+		return true
+	}
+
+	recorded, err := baseline.Load(r.config.BaselinePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			r.printf(i18n.BaselineNotFound, r.config.BaselinePath)
+		} else {
+			r.printf(i18n.BaselineReadError, err)
+		}
+		return false
+	}
+
+	regressions := recorded.Compare(samples, r.config.BaselineThreshold)
+	if len(regressions) == 0 {
+		return true
+	}
+
+	header := i18n.BaselineRegressions
+	if r.config.BaselineWarn {
+		header = i18n.BaselineWarnings
+	}
+	r.printf(header, r.config.BaselineThreshold, r.config.BaselinePath)
+	for _, regression := range regressions {
+		r.logf("  %s\n", regression)
+	}
+
+	return r.config.BaselineWarn
+}
+
+// baselineSamples gathers the durations of steps that passed, keyed like
+// stable captures so a step keeps its identity across runs.
+func baselineSamples(summaries []*output.Summary) map[string]baseline.Sample {
+	samples := make(map[string]baseline.Sample)
+	for _, summary := range summaries {
+		for _, file := range summary.FileResults {
+			for _, step := range file.Steps {
+				if step.Error != nil || step.Skipped != "" {
+					continue
+				}
+
+				key := stableCaptureKey(file.Filename, step.Index)
+				sample := samples[key]
+				sample.Name = step.Name
+				sample.Durations = append(sample.Durations, step.Duration)
+				samples[key] = sample
+			}
+		}
+	}
+
+	return samples
+}
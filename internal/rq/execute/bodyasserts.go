@@ -0,0 +1,93 @@
+package execute
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// checkBodyAsserts verifies the raw response body against body asserts. The
+// body is compared as bytes, so downloads and binary responses are checked
+// without decoding them.
+func checkBodyAsserts(asserts []model.BodyAssert, body []byte) error {
+	return checkBodyReader(asserts, io.NewSectionReader(bytes.NewReader(body), 0, int64(len(body))))
+}
+
+// checkBodyFileAsserts verifies a body written by options.output. The file
+// is read in chunks, so large downloads are checked without loading them.
+func checkBodyFileAsserts(asserts []model.BodyAssert, path string) error {
+	if len(asserts) == 0 {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("body assertion error: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("body assertion error: %w", err)
+	}
+
+	return checkBodyReader(asserts, io.NewSectionReader(file, 0, info.Size()))
+}
+
+func checkBodyReader(asserts []model.BodyAssert, body *io.SectionReader) error {
+	for _, current := range asserts {
+		if err := checkBodyAssert(current.Predicate, body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkBodyAssert(p model.Predicate, body *io.SectionReader) error {
+	size := body.Size()
+
+	switch p.Operation {
+	case model.BodyOpSizeEquals:
+		want, _ := p.Value.(int64)
+		if size != want {
+			return fmt.Errorf("body assertion failed: expected size %d bytes, got %d", want, size)
+		}
+	case model.BodyOpSizeLessThan:
+		limit, _ := p.Value.(int64)
+		if size >= limit {
+			return fmt.Errorf("body assertion failed: expected size less than %d bytes, got %d", limit, size)
+		}
+	case model.BodyOpSHA256Equals:
+		hash := sha256.New()
+		if _, err := io.Copy(hash, io.NewSectionReader(body, 0, size)); err != nil {
+			return fmt.Errorf("body assertion error: %w", err)
+		}
+		got := hex.EncodeToString(hash.Sum(nil))
+		if want := strings.ToLower(fmt.Sprint(p.Value)); got != want {
+			return fmt.Errorf("body assertion failed: expected sha256 %s, got %s", want, got)
+		}
+	case model.BodyOpStartsWithBytes:
+		prefix, err := model.ParseHexBytes(fmt.Sprint(p.Value))
+		if err != nil {
+			return fmt.Errorf("body assertion error: %w", err)
+		}
+		head := make([]byte, min(size, int64(len(prefix))))
+		if _, err := body.ReadAt(head, 0); err != nil && err != io.EOF {
+			return fmt.Errorf("body assertion error: %w", err)
+		}
+		if !bytes.Equal(head, prefix) {
+			return fmt.Errorf("body assertion failed: expected body to start with % x, got % x", prefix, head)
+		}
+	default:
+		return fmt.Errorf("body assertion error: unsupported operation %s", p.Operation)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package execute
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestCheckBodyAsserts(t *testing.T) {
+	t.Parallel()
+
+	pdf := []byte("%PDF-1.7\n")
+
+	tests := []struct {
+		name    string
+		op      string
+		value   any
+		wantErr string
+	}{
+		{name: "size_equals", op: model.BodyOpSizeEquals, value: int64(9)},
+		{name: "size_equals_mismatch", op: model.BodyOpSizeEquals, value: int64(10), wantErr: "expected size 10 bytes, got 9"},
+		{name: "size_less_than", op: model.BodyOpSizeLessThan, value: int64(10)},
+		{name: "size_less_than_equal", op: model.BodyOpSizeLessThan, value: int64(9), wantErr: "expected size less than 9 bytes, got 9"},
+		{name: "sha256_equals", op: model.BodyOpSHA256Equals, value: "0716F9264C9FE19F5D7455276107F3DDCC1D3497F63D60689A73558AE8A1BF5E"},
+		{name: "sha256_mismatch", op: model.BodyOpSHA256Equals, value: strings.Repeat("0", 64), wantErr: "got 0716f9264c9fe19f"},
+		{name: "starts_with_bytes", op: model.BodyOpStartsWithBytes, value: "25 50 44 46"},
+		{name: "starts_with_bytes_mismatch", op: model.BodyOpStartsWithBytes, value: "89504e47", wantErr: "expected body to start with 89 50 4e 47, got 25 50 44 46"},
+		{name: "starts_with_bytes_longer_than_body", op: model.BodyOpStartsWithBytes, value: strings.Repeat("25", 20), wantErr: "expected body to start with"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			asserts := []model.BodyAssert{{Predicate: model.Predicate{Operation: tt.op, Value: tt.value, HasValue: true}}}
+			err := checkBodyAsserts(asserts, pdf)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkBodyAsserts() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkBodyAsserts() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package execute
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+// circuitBreaker counts consecutive connection failures per host. Once a host
+// reaches the threshold, further requests to it are skipped until the breaker
+// is reset at the start of the next run. A nil breaker never opens.
+type circuitBreaker struct {
+	threshold int
+
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+func newCircuitBreaker(threshold int) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+
+	return &circuitBreaker{
+		threshold: threshold,
+		failures:  make(map[string]int),
+	}
+}
+
+// allow returns a skip error when the breaker for host is open.
+func (b *circuitBreaker) allow(host string) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if failures := b.failures[host]; failures >= b.threshold {
+		return &output.SkippedError{
+			Reason: fmt.Sprintf("circuit breaker open for %s after %d consecutive connection failures", host, failures),
+		}
+	}
+
+	return nil
+}
+
+// record counts a failed connection to host, or clears the count when a
+// response was received.
+func (b *circuitBreaker) record(host string, failed bool) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if failed {
+		b.failures[host]++
+		return
+	}
+	delete(b.failures, host)
+}
+
+// reset closes every breaker so each run probes the hosts again.
+func (b *circuitBreaker) reset() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	clear(b.failures)
+}
@@ -0,0 +1,104 @@
+package execute
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	breaker := newCircuitBreaker(2)
+
+	breaker.record("a", true)
+	if err := breaker.allow("a"); err != nil {
+		t.Fatalf("allow() after one failure = %v, want nil", err)
+	}
+
+	breaker.record("a", true)
+	err := breaker.allow("a")
+	if !output.IsSkipped(err) {
+		t.Fatalf("allow() after two failures = %v, want skipped error", err)
+	}
+	if want := "circuit breaker open for a after 2 consecutive connection failures"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("error = %q, want it to contain %q", err, want)
+	}
+	if err := breaker.allow("b"); err != nil {
+		t.Fatalf("allow() for other host = %v, want nil", err)
+	}
+
+	breaker.reset()
+	if err := breaker.allow("a"); err != nil {
+		t.Fatalf("allow() after reset = %v, want nil", err)
+	}
+
+	breaker.record("a", true)
+	breaker.record("a", false)
+	breaker.record("a", true)
+	if err := breaker.allow("a"); err != nil {
+		t.Fatalf("allow() after success = %v, want nil", err)
+	}
+
+	var disabled *circuitBreaker
+	disabled.record("a", true)
+	if err := disabled.allow("a"); err != nil {
+		t.Fatalf("disabled allow() = %v, want nil", err)
+	}
+}
+
+func TestExecuteCompiledFilesCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	downURL := "http://" + listener.Addr().String()
+	listener.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	statusOK := []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200}}}
+	down := model.Step{Method: "GET", URL: downURL, Asserts: model.Asserts{Status: statusOK}}
+	up := model.Step{Method: "GET", URL: server.URL, Asserts: model.Asserts{Status: statusOK}}
+	retried := down
+	retried.Options.Retries = 3
+
+	runner := newDefault()
+	runner.breaker = newCircuitBreaker(2)
+
+	files := []CompiledFile{
+		{Filename: "first.yaml", Steps: []model.Step{retried}},
+		{Filename: "second.yaml", Steps: []model.Step{up, down}},
+		{Filename: "third.yaml", Steps: []model.Step{up}},
+	}
+
+	for range 2 {
+		summary, err := runner.executeCompiledFiles(context.Background(), files)
+		if err == nil {
+			t.Fatal("executeCompiledFiles() error = nil, want failure")
+		}
+
+		first := summary.FileResults[0]
+		if output.IsSkipped(first.Error) || !strings.Contains(first.Error.Error(), "request failed") {
+			t.Fatalf("first file error = %v, want the connection error that opened the breaker", first.Error)
+		}
+
+		second := summary.FileResults[1]
+		if !output.IsSkipped(second.Error) || second.RequestCount != 1 {
+			t.Fatalf("second file = %+v, want skipped after one request", second)
+		}
+
+		if third := summary.FileResults[2]; third.Error != nil {
+			t.Fatalf("third file error = %v, want other hosts unaffected", third.Error)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/cache"
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// cachedRequest returns the response stored for req by --cache, or sends
+// req on a miss. The key of a miss is returned so the response is only
+// saved once the step passed; a hit returns an empty key. Unreadable
+// entries are logged and treated as misses.
+func (r *Runner) cachedRequest(ctx context.Context, options model.Options, req *http.Request) (*http.Response, []byte, string, error) {
+	key := cache.Key(req.Method, req.URL.String(), req.Header)
+	entry, found, err := r.cache.Load(key)
+	if err != nil {
+		r.printf(i18n.CacheError, err)
+	}
+	if found {
+		return entry.Response(req), entry.Body, "", nil
+	}
+
+	resp, body, err := r.executeRequest(ctx, options, req)
+	return resp, body, key, err
+}
+
+// saveCached stores the response of a cache miss under key. Failing to
+// write the cache is logged and does not fail the step.
+func (r *Runner) saveCached(key string, resp *http.Response, body []byte) {
+	if key == "" {
+		return
+	}
+
+	entry := &cache.Entry{
+		Method: resp.Request.Method,
+		URL:    resp.Request.URL.String(),
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   body,
+		Stored: time.Now(),
+	}
+	if err := r.cache.Save(key, entry); err != nil {
+		r.printf(i18n.CacheError, err)
+	}
+}
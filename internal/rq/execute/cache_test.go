@@ -0,0 +1,79 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/cache"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepCacheable(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = w.Write([]byte(`{"id":7}`))
+	}))
+	t.Cleanup(server.Close)
+
+	statusOK := []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200, HasValue: true}}}
+	captureID := &model.Captures{JSONPath: []model.JSONPathCapture{{Name: "id", Path: "$.id"}}}
+
+	tests := []struct {
+		name         string
+		path         string
+		cacheable    bool
+		wantRequests int32
+		wantErr      bool
+	}{
+		{name: "hit_after_first_run", path: "/item", cacheable: true, wantRequests: 1},
+		{name: "not_cacheable", path: "/item", wantRequests: 2},
+		{name: "failing_step_not_stored", path: "/missing", cacheable: true, wantRequests: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			runner := newDefault()
+			runner.cache = cache.New(t.TempDir())
+			runner.SetErrorOutput(&logs)
+
+			step := model.Step{
+				Method:   "GET",
+				URL:      server.URL + tt.path,
+				Options:  model.Options{Cacheable: tt.cacheable},
+				Asserts:  model.Asserts{Status: statusOK},
+				Captures: captureID,
+			}
+
+			requests.Store(0)
+			for range 2 {
+				captures := map[string]CaptureValue{}
+				_, err := runner.executeStep(context.Background(), step, captures, "")
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("executeStep() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !tt.wantErr && captures["id"].Value != float64(7) {
+					t.Errorf("captures[id] = %v, want 7", captures["id"].Value)
+				}
+			}
+
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if logs.Len() > 0 {
+				t.Errorf("unexpected logs: %s", logs.String())
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/jacoelho/rq/internal/rq/capture"
+	"github.com/jacoelho/rq/internal/rq/markup"
 	"github.com/jacoelho/rq/internal/rq/model"
 )
 
@@ -26,7 +27,8 @@ func initializeCaptures(vars map[string]any) map[string]CaptureValue {
 // executeCaptures extracts values from the response using different capture types.
 func (r *Runner) executeCaptures(captures *model.Captures, resp *http.Response, body []byte, captureMap map[string]CaptureValue) error {
 	hasJSONPathCaptures := captures != nil && len(captures.JSONPath) > 0
-	selectors := selectorContextFromBody(body, hasJSONPathCaptures)
+	hasCSSCaptures := captures != nil && len(captures.CSS) > 0
+	selectors := selectorContextFromBody(body, hasJSONPathCaptures, model.Options{}).withHTML(body, hasCSSCaptures)
 	return r.executeCapturesWithSelectors(captures, resp, body, selectors, captureMap)
 }
 
@@ -56,6 +58,10 @@ func (r *Runner) executeCapturesWithSelectors(
 		return err
 	}
 
+	if err := runner.runCookies(captures.Cookies); err != nil {
+		return err
+	}
+
 	if err := runner.runCertificates(captures.Certificate); err != nil {
 		return err
 	}
@@ -64,6 +70,14 @@ func (r *Runner) executeCapturesWithSelectors(
 		return err
 	}
 
+	if err := runner.runXPath(captures.XPath); err != nil {
+		return err
+	}
+
+	if err := runner.runCSS(captures.CSS); err != nil {
+		return err
+	}
+
 	if err := runner.runRegex(captures.Regex); err != nil {
 		return err
 	}
@@ -72,6 +86,22 @@ func (r *Runner) executeCapturesWithSelectors(
 		return err
 	}
 
+	if err := runner.runURL(captures.URL); err != nil {
+		return err
+	}
+
+	if err := runner.runTLS(captures.TLS); err != nil {
+		return err
+	}
+
+	if err := runner.runTTFB(captures.TTFB); err != nil {
+		return err
+	}
+
+	if err := runner.runDuration(captures.Duration); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -125,6 +155,13 @@ func (r captureRunner) runStatus(captures []model.StatusCapture) error {
 
 func (r captureRunner) runHeaders(captures []model.HeaderCapture) error {
 	for _, current := range captures {
+		if current.Parse != "" {
+			if err := r.runStructuredHeader(current); err != nil {
+				return err
+			}
+			continue
+		}
+
 		value, err := capture.ExtractHeader(r.resp, current.HeaderName)
 		if err != nil {
 			if capture.IsNotFound(err) {
@@ -134,6 +171,54 @@ func (r captureRunner) runHeaders(captures []model.HeaderCapture) error {
 			}
 		}
 
+		typed, err := capture.Coerce(value, current.Type)
+		if err != nil {
+			return fmt.Errorf("header capture failed for %s: %w", current.Name, err)
+		}
+
+		r.set(current.Name, typed, current.Redact)
+	}
+
+	return nil
+}
+
+func (r captureRunner) runStructuredHeader(current model.HeaderCapture) error {
+	var (
+		value any
+		err   error
+	)
+	switch current.Parse {
+	case model.HeaderParseLink:
+		value, err = capture.ExtractHeaderLinks(r.resp, current.HeaderName)
+	case model.HeaderParseList:
+		value, err = capture.ExtractHeaderList(r.resp, current.HeaderName)
+	default:
+		err = fmt.Errorf("unsupported parse %q", current.Parse)
+	}
+	if err != nil {
+		return fmt.Errorf("header capture failed for %s: %w", current.Name, err)
+	}
+
+	r.set(current.Name, value, current.Redact)
+	return nil
+}
+
+func (r captureRunner) runCookies(captures []model.CookieCapture) error {
+	for _, current := range captures {
+		value, err := capture.ExtractCookie(r.resp, current.CookieName, current.Attribute)
+		if err != nil {
+			if capture.IsNotFound(err) {
+				value = nil
+			} else {
+				return fmt.Errorf("cookie capture failed for %s: %w", current.Name, err)
+			}
+		}
+
+		value, err = capture.Coerce(value, current.Type)
+		if err != nil {
+			return fmt.Errorf("cookie capture failed for %s: %w", current.Name, err)
+		}
+
 		r.set(current.Name, value, current.Redact)
 	}
 
@@ -162,7 +247,7 @@ func (r captureRunner) runJSONPath(captures []model.JSONPathCapture) error {
 	}
 
 	for _, current := range captures {
-		value, err := capture.ExtractJSONPathFromData(r.selectors.data, current.Path)
+		value, err := r.selectors.jsonPath(current.Path)
 		if err != nil {
 			if capture.IsNotFound(err) {
 				value = nil
@@ -171,6 +256,71 @@ func (r captureRunner) runJSONPath(captures []model.JSONPathCapture) error {
 			}
 		}
 
+		value, err = capture.Coerce(value, current.Type)
+		if err != nil {
+			return fmt.Errorf("JSONPath capture failed for %s: %w", current.Name, err)
+		}
+
+		r.set(current.Name, value, current.Redact)
+	}
+
+	return nil
+}
+
+func (r captureRunner) runXPath(captures []model.XPathCapture) error {
+	if len(captures) == 0 {
+		return nil
+	}
+
+	doc, err := markup.Parse(r.body)
+	if err != nil {
+		return fmt.Errorf("xpath capture failed for %s: %w", captures[0].Name, err)
+	}
+
+	for _, current := range captures {
+		value, err := capture.ExtractXPath(doc, current.Path)
+		if err != nil {
+			if capture.IsNotFound(err) {
+				value = nil
+			} else {
+				return fmt.Errorf("xpath capture failed for %s: %w", current.Name, err)
+			}
+		}
+
+		value, err = capture.Coerce(value, current.Type)
+		if err != nil {
+			return fmt.Errorf("xpath capture failed for %s: %w", current.Name, err)
+		}
+
+		r.set(current.Name, value, current.Redact)
+	}
+
+	return nil
+}
+
+func (r captureRunner) runCSS(captures []model.CSSCapture) error {
+	if len(captures) == 0 {
+		return nil
+	}
+	if r.selectors.documentErr != nil {
+		return fmt.Errorf("css capture failed for %s: %w", captures[0].Name, r.selectors.documentErr)
+	}
+
+	for _, current := range captures {
+		value, err := capture.ExtractCSS(r.selectors.document, current.Selector, current.Attr)
+		if err != nil {
+			if capture.IsNotFound(err) {
+				value = nil
+			} else {
+				return fmt.Errorf("css capture failed for %s: %w", current.Name, err)
+			}
+		}
+
+		value, err = capture.Coerce(value, current.Type)
+		if err != nil {
+			return fmt.Errorf("css capture failed for %s: %w", current.Name, err)
+		}
+
 		r.set(current.Name, value, current.Redact)
 	}
 
@@ -184,7 +334,12 @@ func (r captureRunner) runRegex(captures []model.RegexCapture) error {
 			return err
 		}
 
-		r.set(current.Name, value, current.Redact)
+		typed, err := capture.Coerce(value, current.Type)
+		if err != nil {
+			return fmt.Errorf("regex capture failed for %s: %w", current.Name, err)
+		}
+
+		r.set(current.Name, typed, current.Redact)
 	}
 
 	return nil
@@ -197,8 +352,65 @@ func (r captureRunner) runBody(captures []model.BodyCapture) error {
 			return fmt.Errorf("body capture failed for %s: %w", current.Name, err)
 		}
 
+		typed, err := capture.Coerce(value, current.Type)
+		if err != nil {
+			return fmt.Errorf("body capture failed for %s: %w", current.Name, err)
+		}
+
+		r.set(current.Name, typed, current.Redact)
+	}
+
+	return nil
+}
+
+func (r captureRunner) runURL(captures []model.URLCapture) error {
+	for _, current := range captures {
+		value, err := capture.ExtractURL(r.resp)
+		if err != nil {
+			return fmt.Errorf("url capture failed for %s: %w", current.Name, err)
+		}
+
 		r.set(current.Name, value, current.Redact)
 	}
 
 	return nil
 }
+
+func (r captureRunner) runTLS(captures []model.TLSCapture) error {
+	for _, current := range captures {
+		value, err := capture.ExtractTLSField(r.resp, current.TLSField)
+		if err != nil {
+			return fmt.Errorf("tls capture failed for %s: %w", current.Name, err)
+		}
+
+		r.set(current.Name, value, current.Redact)
+	}
+
+	return nil
+}
+
+func (r captureRunner) runDuration(captures []model.DurationCapture) error {
+	for _, current := range captures {
+		duration, ok := responseDuration(r.resp)
+		if !ok {
+			return fmt.Errorf("duration capture failed for %s: response time was not measured", current.Name)
+		}
+
+		r.set(current.Name, duration.Milliseconds(), current.Redact)
+	}
+
+	return nil
+}
+
+func (r captureRunner) runTTFB(captures []model.TTFBCapture) error {
+	for _, current := range captures {
+		ttfb, ok := responseTTFB(r.resp)
+		if !ok {
+			return fmt.Errorf("ttfb capture failed for %s: time to first byte was not measured", current.Name)
+		}
+
+		r.set(current.Name, ttfb.Milliseconds(), current.Redact)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package execute
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/predicate"
+	"github.com/jacoelho/rq/internal/rq/templating"
+)
+
+// runChecks evaluates checks against the current variables. A check on an
+// undefined variable fails unless it asserts exists.
+func (r *Runner) runChecks(checks []model.Check, captures map[string]CaptureValue) error {
+	if len(checks) == 0 {
+		return nil
+	}
+
+	tmplVars := captureMapForTemplate(captures)
+	evaluator := r.assertionEvaluator()
+	for _, check := range checks {
+		actual, defined := tmplVars[check.Name]
+		if !defined && check.Predicate.Operation != string(predicate.OpExists) {
+			return fmt.Errorf("check %s: variable is not defined", check.Name)
+		}
+
+		expected, err := renderCheckValue(check.Predicate.Value, tmplVars)
+		if err != nil {
+			return fmt.Errorf("check %s: failed to process value template: %w", check.Name, err)
+		}
+
+		input := check.Predicate
+		input.Value = expected
+		ok, err := evaluator.Evaluate(actual, input)
+		if err != nil {
+			return fmt.Errorf("check %s: %w", check.Name, err)
+		}
+		if !ok {
+			return fmt.Errorf("check %s failed: expected %s %v, got %v", check.Name, input.Operation, expected, actual)
+		}
+	}
+
+	return nil
+}
+
+// renderCheckValue renders a templated string value and decodes the result
+// as JSON when it is a number, boolean or other JSON value, so
+// "{{ sum .prices }}" compares as a number. Other values are returned as is.
+func renderCheckValue(value any, tmplVars map[string]any) (any, error) {
+	text, ok := value.(string)
+	if !ok || !strings.Contains(text, "{{") {
+		return value, nil
+	}
+
+	rendered, err := templating.Apply(text, tmplVars)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if err := json.Unmarshal([]byte(rendered), &decoded); err != nil {
+		return rendered, nil
+	}
+
+	return decoded, nil
+}
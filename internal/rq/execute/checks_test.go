@@ -0,0 +1,125 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestRunChecks(t *testing.T) {
+	t.Parallel()
+
+	captures := map[string]CaptureValue{
+		"order_total": {Value: 64.97},
+		"item_prices": {Value: []any{19.99, 39.98, 5.0}},
+		"status":      {Value: "shipped"},
+	}
+
+	check := func(name, op string, value any) model.Check {
+		return model.Check{Name: name, Predicate: model.Predicate{Operation: op, Value: value, HasValue: value != nil}}
+	}
+
+	tests := []struct {
+		name    string
+		checks  []model.Check
+		wantErr string
+	}{
+		{
+			name:   "templated sum matches",
+			checks: []model.Check{check("order_total", "equals", "{{ sum .item_prices }}")},
+		},
+		{
+			name: "literal values",
+			checks: []model.Check{
+				check("order_total", "greater_than", 50),
+				check("status", "equals", "shipped"),
+				check("status", "regex", "^ship"),
+			},
+		},
+		{
+			name:   "templated string",
+			checks: []model.Check{check("status", "equals", "{{ .status }}")},
+		},
+		{
+			name:    "templated sum differs",
+			checks:  []model.Check{check("order_total", "equals", "{{ sub (sum .item_prices) 1 }}")},
+			wantErr: "check order_total failed: expected equals 63.97, got 64.97",
+		},
+		{
+			name:    "undefined variable",
+			checks:  []model.Check{check("missing", "equals", 1)},
+			wantErr: "check missing: variable is not defined",
+		},
+		{
+			name:    "undefined variable exists",
+			checks:  []model.Check{check("missing", "exists", nil)},
+			wantErr: "check missing failed",
+		},
+		{
+			name:    "invalid template",
+			checks:  []model.Check{check("order_total", "equals", "{{ .nope }}")},
+			wantErr: "failed to process value template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := newDefault().runChecks(tt.checks, captures)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("runChecks() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runChecks() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestExecuteStepChecks(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total": 30.5, "prices": [10.25, 20.25]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	runner := newDefault()
+	captures := map[string]CaptureValue{}
+
+	order := model.Step{
+		Method: "GET",
+		URL:    server.URL,
+		Captures: &model.Captures{JSONPath: []model.JSONPathCapture{
+			{Name: "total", Path: "$.total"},
+			{Name: "prices", Path: "$.prices"},
+		}},
+		Checks: []model.Check{{Name: "total", Predicate: model.Predicate{Operation: "equals", Value: "{{ sum .prices }}", HasValue: true}}},
+	}
+	if _, err := runner.executeStep(context.Background(), order, captures, ""); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+
+	checksOnly := model.Step{
+		Checks: []model.Check{{Name: "total", Predicate: model.Predicate{Operation: "less_than", Value: 30, HasValue: true}}},
+	}
+	ran, err := runner.executeStep(context.Background(), checksOnly, captures, "")
+	if !ran || err == nil || !strings.Contains(err.Error(), "check total failed") {
+		t.Fatalf("executeStep() = %v, %v, want a failed check", ran, err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+}
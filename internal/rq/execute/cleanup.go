@@ -0,0 +1,70 @@
+package execute
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// pendingCleanup is a register_cleanup request waiting for the run to end,
+// with the captures and cookie jar of its file when the registering step
+// succeeded.
+type pendingCleanup struct {
+	step     model.Step
+	captures map[string]CaptureValue
+	jar      http.CookieJar
+	baseDir  string
+}
+
+// registerCleanups queues the register_cleanup requests of step. They reuse
+// the step's auth and TLS options, so they reach the same API as the request
+// that created the resource.
+func (r *Runner) registerCleanups(ctx context.Context, step model.Step, captures map[string]CaptureValue, baseDir string) {
+	if len(step.RegisterCleanup) == 0 {
+		return
+	}
+
+	snapshot := maps.Clone(captures)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, cleanup := range step.RegisterCleanup {
+		r.cleanups = append(r.cleanups, pendingCleanup{
+			step: model.Step{
+				Method:  cleanup.Method,
+				URL:     cleanup.URL,
+				Headers: cleanup.Headers,
+				Query:   cleanup.Query,
+				Options: model.Options{TLS: step.Options.TLS},
+				Auth:    step.Auth,
+				Asserts: model.Asserts{
+					Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "less_than", Value: 400, HasValue: true}}},
+				},
+			},
+			captures: snapshot,
+			jar:      stepCookieJar(ctx),
+			baseDir:  baseDir,
+		})
+	}
+}
+
+// runCleanups sends the queued cleanups, last registered first, and reports
+// the ones that fail. Cancellation of ctx is ignored so an interrupted run
+// still removes what it created.
+func (r *Runner) runCleanups(ctx context.Context) {
+	r.mu.Lock()
+	pending := r.cleanups
+	r.cleanups = nil
+	r.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, cleanup := range slices.Backward(pending) {
+		if _, err := r.executeStep(withCookieJar(ctx, cleanup.jar), cleanup.step, cleanup.captures, cleanup.baseDir); err != nil {
+			r.printf(i18n.CleanupError, cleanup.step.Method, cleanup.step.URL, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteCompiledFilesRunsCleanupsInReverseOrder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests []string
+		created  int
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch {
+		case r.Method == http.MethodPost:
+			created++
+			fmt.Fprintf(w, `{"id":"%d"}`, created)
+		case r.URL.Path == "/interrupt":
+			cancel()
+		case r.URL.Path == "/items/1":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	create := model.Step{
+		Method: "POST",
+		URL:    server.URL + "/items",
+		Captures: &model.Captures{
+			JSONPath: []model.JSONPathCapture{{Name: "id", Path: "$.id"}},
+		},
+		RegisterCleanup: []model.Cleanup{
+			{Method: "DELETE", URL: server.URL + "/items/{{.id}}"},
+		},
+	}
+	files := []CompiledFile{{
+		Filename: "cleanup.yaml",
+		Steps: []model.Step{
+			create,
+			create,
+			{Method: "GET", URL: server.URL + "/interrupt"},
+			create,
+		},
+	}}
+
+	var errOutput bytes.Buffer
+	runner := newDefault()
+	runner.SetErrorOutput(&errOutput)
+
+	if _, err := runner.executeCompiledFiles(ctx, files); err == nil {
+		t.Fatal("executeCompiledFiles() error = nil, want interruption")
+	}
+
+	want := []string{
+		"POST /items",
+		"POST /items",
+		"GET /interrupt",
+		"DELETE /items/2",
+		"DELETE /items/1",
+	}
+	if !slices.Equal(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+	if got := errOutput.String(); !strings.Contains(got, "Cleanup DELETE") || !strings.Contains(got, "status assertion failed") {
+		t.Errorf("error output = %q, want the failed cleanup of item 1", got)
+	}
+	if len(runner.cleanups) != 0 {
+		t.Errorf("cleanups left queued = %d, want 0", len(runner.cleanups))
+	}
+}
@@ -0,0 +1,86 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+type validatorsKey struct{}
+
+// validators holds the ETag and Last-Modified response headers of the named
+// steps of a file, for later conditional_on steps.
+type validators struct {
+	mu     sync.Mutex
+	byStep map[string]validator
+}
+
+type validator struct {
+	etag         string
+	lastModified string
+}
+
+// withValidators returns a context recording the validators of named steps.
+// Every file runs with its own store, like its cookie jar.
+func withValidators(ctx context.Context) context.Context {
+	return context.WithValue(ctx, validatorsKey{}, &validators{byStep: make(map[string]validator)})
+}
+
+func stepValidators(ctx context.Context) *validators {
+	store, _ := ctx.Value(validatorsKey{}).(*validators)
+	return store
+}
+
+// recordValidators keeps the validators of the response to a named step. A
+// later response to the same step, such as the next foreach element,
+// replaces them.
+func recordValidators(ctx context.Context, step model.Step, resp *http.Response) {
+	store := stepValidators(ctx)
+	if store == nil || step.Name == "" || resp == nil {
+		return
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.byStep[step.Name] = validator{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+// applyConditionalOn sets the conditional headers from the validators of the
+// step named by conditional_on.
+func applyConditionalOn(ctx context.Context, step model.Step, req *http.Request) error {
+	if step.ConditionalOn == "" {
+		return nil
+	}
+
+	var (
+		found validator
+		ok    bool
+	)
+	if store := stepValidators(ctx); store != nil {
+		store.mu.Lock()
+		found, ok = store.byStep[step.ConditionalOn]
+		store.mu.Unlock()
+	}
+	if !ok {
+		return fmt.Errorf("conditional_on: step %q has not received a response", step.ConditionalOn)
+	}
+	if found.etag == "" && found.lastModified == "" {
+		return fmt.Errorf("conditional_on: the response to step %q has no ETag or Last-Modified header", step.ConditionalOn)
+	}
+
+	etagKey, dateKey := model.ConditionalHeaders(req.Method)
+	if found.etag != "" {
+		req.Header.Set(etagKey, found.etag)
+	}
+	if found.lastModified != "" {
+		req.Header.Set(dateKey, found.lastModified)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package execute
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// DefaultConnectTimeout bounds dialing and the TLS handshake of a connect
+// step when it sets no connect.timeout.
+const DefaultConnectTimeout = 10 * time.Second
+
+// executeConnect dials the host and port of req and, for tls:// URLs,
+// completes a TLS handshake with the client's TLS settings. The returned
+// response carries no status or body; its TLS field holds the connection
+// state for tls and certificate asserts.
+func (r *Runner) executeConnect(ctx context.Context, step model.Step, req *http.Request) (*http.Response, []byte, error) {
+	connect := step.Connect
+	timeout := connect.Timeout
+	if timeout == 0 {
+		timeout = DefaultConnectTimeout
+	}
+
+	address := req.URL.Host
+	if req.URL.Port() == "" {
+		return nil, nil, fmt.Errorf("connect URL %s has no port", req.URL.Redacted())
+	}
+
+	if err := r.rateLimiter.Wait(ctx, address); err != nil {
+		return nil, nil, fmt.Errorf("rate limiting interrupted: %w", err)
+	}
+
+	var tlsConfig *tls.Config
+	if req.URL.Scheme == "tls" {
+		var err error
+		tlsConfig, err = r.connectTLSConfig(step, req.URL.Hostname())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	r.breaker.record(address, err != nil && ctx.Err() == nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect failed: %w", err)
+	}
+	defer conn.Close()
+
+	resp := &http.Response{Header: make(http.Header), Request: req}
+	if tlsConfig == nil {
+		return resp, nil, nil
+	}
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, nil, fmt.Errorf("tls handshake failed: %w", err)
+	}
+	state := tlsConn.ConnectionState()
+	resp.TLS = &state
+
+	return resp, nil, nil
+}
+
+// connectTLSConfig derives the handshake settings from the step's client, so
+// --insecure, --cacert and options.tls apply as they do to HTTP steps.
+func (r *Runner) connectTLSConfig(step model.Step, host string) (*tls.Config, error) {
+	client, err := r.getClient(step.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *tls.Config
+	switch transport := client.Transport.(type) {
+	case nil:
+		cfg = &tls.Config{}
+	case *http.Transport:
+		cfg = &tls.Config{}
+		if transport.TLSClientConfig != nil {
+			cfg = transport.TLSClientConfig.Clone()
+		}
+	default:
+		return nil, fmt.Errorf("connect steps require an *http.Transport, got %T", transport)
+	}
+
+	cfg.NextProtos = step.Connect.ALPN
+	cfg.ServerName = host
+	if step.Connect.ServerName != "" {
+		cfg.ServerName = step.Connect.ServerName
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,99 @@
+package execute
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepConnect(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	t.Cleanup(tlsServer.Close)
+
+	plainServer := httptest.NewServer(handler)
+	t.Cleanup(plainServer.Close)
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	closedAddress := closed.Addr().String()
+	closed.Close()
+
+	tlsAddress := tlsServer.Listener.Addr().String()
+	plainAddress := plainServer.Listener.Addr().String()
+
+	tests := []struct {
+		name    string
+		step    model.Step
+		wantErr string
+	}{
+		{
+			name: "tls handshake negotiates alpn",
+			step: model.Step{
+				Method:  "CONNECT",
+				URL:     "tls://" + tlsAddress,
+				Connect: &model.Connect{ALPN: []string{"h2"}},
+				Asserts: model.Asserts{
+					TLS: []model.TLSAssert{{Name: "alpn", Predicate: model.Predicate{Operation: "equals", Value: "h2", HasValue: true}}},
+				},
+				Captures: &model.Captures{
+					Certificate: []model.CertificateCapture{{Name: "issuer", CertificateField: "issuer"}},
+				},
+			},
+		},
+		{
+			name: "tcp port is open",
+			step: model.Step{Method: "CONNECT", URL: "tcp://" + plainAddress, Connect: &model.Connect{}},
+		},
+		{
+			name:    "closed port fails",
+			step:    model.Step{Method: "CONNECT", URL: "tcp://" + closedAddress, Connect: &model.Connect{}},
+			wantErr: "connect failed",
+		},
+		{
+			name:    "plain server fails the handshake",
+			step:    model.Step{Method: "CONNECT", URL: "tls://" + plainAddress, Connect: &model.Connect{}},
+			wantErr: "tls handshake failed",
+		},
+		{
+			name:    "missing port",
+			step:    model.Step{Method: "CONNECT", URL: "tcp://127.0.0.1", Connect: &model.Connect{}},
+			wantErr: "has no port",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner := newDefault()
+			runner.client = tlsServer.Client()
+			captures := map[string]CaptureValue{}
+
+			_, err := runner.executeStep(context.Background(), tt.step, captures, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeStep() error = %v", err)
+			}
+			if tt.step.Captures != nil && !strings.Contains(captures["issuer"].Value.(string), "Acme") {
+				t.Fatalf("issuer = %v", captures["issuer"].Value)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+type cookieJarKey struct{}
+
+// newCookieJar returns an empty jar. It never fails without options.
+func newCookieJar() http.CookieJar {
+	jar, _ := cookiejar.New(nil)
+	return jar
+}
+
+// withCookieJar returns a context whose requests store and send cookies in
+// jar. Every file runs with its own jar, so cookies never leak between files.
+func withCookieJar(ctx context.Context, jar http.CookieJar) context.Context {
+	return context.WithValue(ctx, cookieJarKey{}, jar)
+}
+
+// stepCookieJar returns the jar on ctx, or nil.
+func stepCookieJar(ctx context.Context) http.CookieJar {
+	jar, _ := ctx.Value(cookieJarKey{}).(http.CookieJar)
+	return jar
+}
+
+// clientWithCookies returns client storing and sending cookies in jar,
+// unless the step sets options.cookies to false.
+func clientWithCookies(client *http.Client, jar http.CookieJar, options model.Options) *http.Client {
+	if jar == nil || (options.Cookies != nil && !*options.Cookies) {
+		return client
+	}
+
+	clientCopy := *client
+	clientCopy.Jar = jar
+	return &clientCopy
+}
@@ -0,0 +1,107 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteCompiledFilesKeepsCookiesPerFile(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "sid", Value: "abc", Path: "/", HttpOnly: true, MaxAge: 3600, SameSite: http.SameSiteLaxMode})
+		case "/me":
+			if cookie, err := r.Cookie("sid"); err != nil || cookie.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	status := func(code int) model.Asserts {
+		return model.Asserts{Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: code, HasValue: true}}}}
+	}
+	disabled := false
+
+	files := []CompiledFile{
+		{
+			Filename: "session.yaml",
+			Steps: []model.Step{
+				{
+					Method: "POST",
+					URL:    server.URL + "/login",
+					Asserts: model.Asserts{Cookies: []model.CookieAssert{
+						{Name: "sid", Predicate: model.Predicate{Operation: "equals", Value: "abc", HasValue: true}},
+						{Name: "sid", Attribute: "http_only", Predicate: model.Predicate{Operation: "equals", Value: true, HasValue: true}},
+						{Name: "sid", Attribute: "same_site", Predicate: model.Predicate{Operation: "equals", Value: "Lax", HasValue: true}},
+						{Name: "sid", Attribute: "max_age", Predicate: model.Predicate{Operation: "equals", Value: 3600, HasValue: true}},
+					}},
+					Captures: &model.Captures{Cookies: []model.CookieCapture{
+						{Name: "session", CookieName: "sid"},
+					}},
+				},
+				{Method: "GET", URL: server.URL + "/me", Asserts: status(http.StatusOK)},
+				{Method: "GET", URL: server.URL + "/me", Options: model.Options{Cookies: &disabled}, Asserts: status(http.StatusUnauthorized)},
+			},
+		},
+		{
+			Filename: "anonymous.yaml",
+			Steps: []model.Step{
+				{Method: "GET", URL: server.URL + "/me", Asserts: status(http.StatusUnauthorized)},
+			},
+		},
+	}
+
+	summary, err := newDefault().executeCompiledFiles(context.Background(), files)
+	if err != nil {
+		t.Fatalf("executeCompiledFiles() error = %v", err)
+	}
+	if summary.SucceededFiles != 2 {
+		t.Fatalf("summary.SucceededFiles = %d, want 2", summary.SucceededFiles)
+	}
+}
+
+func TestCookieAssertFailures(t *testing.T) {
+	t.Parallel()
+
+	resp := &http.Response{Header: http.Header{"Set-Cookie": {"sid=abc; Path=/"}}}
+
+	tests := []struct {
+		name   string
+		assert model.CookieAssert
+		want   string
+	}{
+		{
+			name:   "missing_cookie",
+			assert: model.CookieAssert{Name: "token", Predicate: model.Predicate{Operation: "equals", Value: "x", HasValue: true}},
+			want:   "cookie assertion failed for token: cookie not set by the response",
+		},
+		{
+			name:   "missing_attribute",
+			assert: model.CookieAssert{Name: "sid", Attribute: "expires", Predicate: model.Predicate{Operation: "exists"}},
+			want:   "cookie assertion failed for sid: expected expires exists <nil>, got <nil>",
+		},
+		{
+			name:   "flag_not_set",
+			assert: model.CookieAssert{Name: "sid", Attribute: "secure", Predicate: model.Predicate{Operation: "equals", Value: true, HasValue: true}},
+			want:   "cookie assertion failed for sid: expected secure equals true, got false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := newDefault().executeAssertions(model.Asserts{Cookies: []model.CookieAssert{tt.assert}}, resp, selectorContext{})
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("executeAssertions() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package execute
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/monitor"
+)
+
+const daemonShutdownTimeout = 5 * time.Second
+
+func (r *Runner) runDaemon(ctx context.Context) int {
+	listener, err := net.Listen("tcp", r.config.ListenAddr)
+	if err != nil {
+		r.printf(i18n.DaemonListenError, err)
+		return 1
+	}
+
+	return r.serveDaemon(ctx, listener)
+}
+
+// serveDaemon runs the suite every configured interval until ctx is cancelled,
+// serving the latest results on /healthz and /metrics from listener.
+func (r *Runner) serveDaemon(ctx context.Context, listener net.Listener) int {
+	state := monitor.New(r.config.Meta)
+	server := &http.Server{
+		Handler:           state.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), daemonShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	r.printf(i18n.DaemonListening, listener.Addr(), r.config.Interval)
+
+	for {
+		result, err := r.runOnce(ctx)
+		if ctx.Err() != nil {
+			return 0
+		}
+
+		state.Record(result, err, time.Now())
+		if err != nil {
+			r.printf(i18n.DaemonRunFailed, err)
+		}
+		if result != nil {
+			if err := result.Format(r.config.OutputFormat, r.payloadWriter()); err != nil {
+				r.printf(i18n.FormatResultsError, err)
+			}
+		}
+
+		timer := time.NewTimer(r.config.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0
+		case err := <-serveErr:
+			timer.Stop()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				r.printf(i18n.DaemonStopped, err)
+			}
+			return 1
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/config"
+)
+
+func TestRunnerDaemonServesResults(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	yamlContent := fmt.Sprintf(`- method: GET
+  url: %s
+  asserts:
+    status:
+      - op: equals
+        value: 200`, target.URL)
+	if err := os.WriteFile(testFile, []byte(yamlContent), 0o644); err != nil {
+		t.Fatalf("write test file: %v", err)
+	}
+
+	runner, exitResult := New(&config.Config{
+		TestFiles: []string{testFile},
+		Daemon:    true,
+		Interval:  10 * time.Millisecond,
+	})
+	if exitResult != nil {
+		t.Fatalf("New() error: %s", exitResult.Message)
+	}
+	runner.SetOutput(io.Discard)
+	runner.SetErrorOutput(io.Discard)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	baseURL := "http://" + listener.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int, 1)
+	go func() {
+		done <- runner.serveDaemon(ctx, listener)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for calls.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("daemon did not run the suite repeatedly, calls = %d", calls.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get(baseURL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("/healthz status = %d, want 200", resp.StatusCode)
+	}
+
+	resp, err = client.Get(baseURL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	metrics, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(metrics), "rq_last_run_success 1") {
+		t.Fatalf("unexpected metrics:\n%s", metrics)
+	}
+
+	cancel()
+	select {
+	case code := <-done:
+		if code != 0 {
+			t.Fatalf("serveDaemon() = %d, want 0", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon did not stop after cancellation")
+	}
+}
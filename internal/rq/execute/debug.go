@@ -1,7 +1,10 @@
 package execute
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
+	"unicode/utf8"
 
 	"github.com/jacoelho/rq/internal/rq/output"
 	"github.com/jacoelho/rq/internal/rq/sanitizer"
@@ -23,28 +26,115 @@ func redactValues(captures map[string]CaptureValue, staticSecrets map[string]any
 	return values
 }
 
+func (r *Runner) secretSalt() string {
+	if r.config == nil {
+		return ""
+	}
+	return r.config.SecretSalt
+}
+
+// redactError masks secrets and redacted captures in the message of err, so
+// reports, logs and traces never show them.
+func (r *Runner) redactError(err error, captures map[string]CaptureValue) error {
+	return sanitizer.RedactError(err, redactValues(captures, r.staticSecrets()), r.secretSalt())
+}
+
+// redactAttempts masks secrets in the errors of attempts in place.
+func (r *Runner) redactAttempts(attempts []output.Attempt, captures map[string]CaptureValue) []output.Attempt {
+	for i := range attempts {
+		attempts[i].Error = r.redactError(attempts[i].Error, captures)
+	}
+	return attempts
+}
+
+// writeDebug writes one debug dump without interleaving it with output from
+// files running in parallel.
+func (r *Runner) writeDebug(description string, data []byte, timing *output.DebugTiming) error {
+	r.logMu.Lock()
+	defer r.logMu.Unlock()
+
+	return output.FormatDebug(r.config.OutputFormat, r.errorWriter(), description, data, timing)
+}
+
 // debugRequest outputs detailed request information when debug mode is enabled.
 func (r *Runner) debugRequest(req *http.Request, redactValues []any) {
-	reqDump, err := sanitizer.DumpRequestRedacted(req, redactValues, r.config.SecretSalt)
+	reqDump, err := sanitizer.DumpRequestRedacted(req, redactValues, r.secretSalt())
 	if err != nil {
 		r.logf("Error dumping request: %v\n", err)
 		return
 	}
 
-	if err := output.FormatDebug(r.config.OutputFormat, r.errorWriter(), "REQUEST", reqDump); err != nil {
+	if err := r.writeDebug("REQUEST", r.truncateDebugBody(reqDump), nil); err != nil {
 		r.logf("Error formatting debug request: %v\n", err)
 	}
 }
 
-// debugResponse outputs detailed response information when debug mode is enabled.
+// debugResponse outputs detailed response information and the request
+// timing when debug mode is enabled.
 func (r *Runner) debugResponse(resp *http.Response, body []byte, redactValues []any) {
-	respDump, err := sanitizer.DumpResponseRedacted(resp, body, redactValues, r.config.SecretSalt)
+	respDump, err := sanitizer.DumpResponseRedacted(resp, body, redactValues, r.secretSalt())
 	if err != nil {
 		r.logf("Error dumping response: %v\n", err)
 		return
 	}
 
-	if err := output.FormatDebug(r.config.OutputFormat, r.errorWriter(), "RESPONSE", respDump); err != nil {
+	if err := r.writeDebug("RESPONSE", r.truncateDebugBody(respDump), debugTiming(resp)); err != nil {
 		r.logf("Error formatting debug response: %v\n", err)
 	}
 }
+
+func debugTiming(resp *http.Response) *output.DebugTiming {
+	var timing output.DebugTiming
+	if total, ok := responseDuration(resp); ok {
+		timing.Duration = total
+	}
+	if ttfb, ok := responseTTFB(resp); ok {
+		timing.TTFB = ttfb
+	}
+
+	return &timing
+}
+
+// truncateDebugBody cuts the body of an HTTP dump to --debug-body-limit
+// bytes, keeping whole UTF-8 characters, and notes how much was left out.
+// Dumps are redacted before they are cut, so a secret is never shown in
+// part.
+func (r *Runner) truncateDebugBody(dump []byte) []byte {
+	limit := r.config.DebugBody
+	headerEnd := bytes.Index(dump, []byte("\r\n\r\n"))
+	if limit <= 0 || headerEnd < 0 {
+		return dump
+	}
+
+	bodyStart := headerEnd + 4
+	if len(dump)-bodyStart <= limit {
+		return dump
+	}
+
+	cut := bodyStart + limit
+	for cut > bodyStart && !utf8.RuneStart(dump[cut]) {
+		cut--
+	}
+	omitted := len(dump) - cut
+	truncated := append(dump[:cut:cut], fmt.Sprintf("\n... [%d more bytes, raise --debug-body-limit to see them]", omitted)...)
+
+	return truncated
+}
+
+// snapshotVariables copies the variables visible to a step for dump_vars,
+// masking secrets and redacted captures the same way debug output does.
+func (r *Runner) snapshotVariables(step int, captures map[string]CaptureValue) output.VariableSnapshot {
+	secrets := r.staticSecrets()
+	salt := r.secretSalt()
+
+	values := make(map[string]any, len(captures))
+	for name, capture := range captures {
+		if _, secret := secrets[name]; secret || capture.Redact {
+			values[name] = sanitizer.RedactedToken(fmt.Sprint(capture.Value), salt)
+			continue
+		}
+		values[name] = capture.Value
+	}
+
+	return output.VariableSnapshot{Step: step, Values: values}
+}
@@ -0,0 +1,108 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestTruncateDebugBody(t *testing.T) {
+	t.Parallel()
+
+	const head = "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n"
+
+	tests := []struct {
+		name  string
+		limit int
+		dump  string
+		want  string
+	}{
+		{name: "unlimited", limit: 0, dump: head + "abcdef", want: head + "abcdef"},
+		{name: "within limit", limit: 6, dump: head + "abcdef", want: head + "abcdef"},
+		{name: "over limit", limit: 4, dump: head + "abcdef", want: head + "abcd\n... [2 more bytes, raise --debug-body-limit to see them]"},
+		{name: "keeps whole characters", limit: 2, dump: head + "aé", want: head + "a\n... [2 more bytes, raise --debug-body-limit to see them]"},
+		{name: "headers only", limit: 4, dump: "GET / HTTP/1.1\r\nHost: example.com", want: "GET / HTTP/1.1\r\nHost: example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner := newDefault()
+			runner.config = &config.Config{DebugBody: tt.limit}
+			if got := string(runner.truncateDebugBody([]byte(tt.dump))); got != tt.want {
+				t.Errorf("truncateDebugBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugDumpsFailedResponse(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"missing field name"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	runner := newDefault()
+	runner.config = &config.Config{Debug: true}
+	var stderr bytes.Buffer
+	runner.SetErrorOutput(&stderr)
+
+	step := model.Step{
+		Method: "GET",
+		URL:    server.URL,
+		Asserts: model.Asserts{
+			Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200, HasValue: true}}},
+		},
+	}
+	if _, err := runner.executeStep(context.Background(), step, map[string]CaptureValue{}, ""); err == nil {
+		t.Fatal("executeStep() error = nil, want a status assertion failure")
+	}
+
+	output := stderr.String()
+	if !strings.Contains(output, "RESPONSE (") || !strings.Contains(output, `{"error":"missing field name"}`) {
+		t.Errorf("debug output is missing the failed response with its timing:\n%s", output)
+	}
+}
+
+func TestStepErrorsRedactSecrets(t *testing.T) {
+	t.Parallel()
+
+	const secret = "s3cr3t-token-value"
+
+	// The server is closed, so the transport error quotes the URL.
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	runner := newDefault()
+	runner.config = &config.Config{Secrets: map[string]any{"token": secret}}
+	runner.variables = runner.config.AllVariables()
+
+	step := model.Step{Method: "GET", URL: server.URL + "/?token={{ .token }}"}
+	outcome, err := runner.executeCompiledFile(context.Background(), CompiledFile{Filename: "secret.yaml", Steps: []model.Step{step}})
+	if err == nil {
+		t.Fatal("executeCompiledFile() error = nil, want the connection failure")
+	}
+
+	stepErr := outcome.steps[0].Error
+	if stepErr == nil {
+		t.Fatal("step error = nil, want the connection failure")
+	}
+	for _, message := range []string{err.Error(), stepErr.Error()} {
+		if strings.Contains(message, secret) {
+			t.Errorf("error %q contains the secret", message)
+		}
+		if !strings.Contains(message, "[S256:") {
+			t.Errorf("error %q has no redaction placeholder", message)
+		}
+	}
+}
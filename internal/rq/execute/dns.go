@@ -0,0 +1,125 @@
+package execute
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// DefaultDNSTimeout bounds a dns step lookup when it sets no dns.timeout.
+const DefaultDNSTimeout = 5 * time.Second
+
+// executeDNS resolves the host of req and returns the records, sorted, as a
+// JSON list. The lookup duration is reported as the time to first byte, so
+// ttfb asserts check resolution latency.
+func (r *Runner) executeDNS(ctx context.Context, step model.Step, req *http.Request) (*http.Response, []byte, error) {
+	dns := step.DNS
+	timeout := dns.Timeout
+	if timeout == 0 {
+		timeout = DefaultDNSTimeout
+	}
+	recordType := strings.ToUpper(dns.Type)
+	if recordType == "" {
+		recordType = model.DNSTypeA
+	}
+
+	host := req.URL.Hostname()
+	if host == "" {
+		return nil, nil, fmt.Errorf("dns URL %s has no host", req.URL.Redacted())
+	}
+
+	if err := r.rateLimiter.Wait(ctx, host); err != nil {
+		return nil, nil, fmt.Errorf("rate limiting interrupted: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	records, err := lookupRecords(ctx, newResolver(dns.Server), recordType, host)
+	elapsed := time.Since(start)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dns lookup failed: %w", err)
+	}
+	slices.Sort(records)
+
+	body, err := json.Marshal(records)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := &http.Response{Header: make(http.Header), Request: withMeasuredTiming(req, start, elapsed)}
+	return resp, body, nil
+}
+
+// newResolver returns the system resolver, or one that sends every query to
+// server when set.
+func newResolver(server string) *net.Resolver {
+	if server == "" {
+		return net.DefaultResolver
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+func lookupRecords(ctx context.Context, resolver *net.Resolver, recordType, host string) ([]string, error) {
+	switch recordType {
+	case model.DNSTypeA, model.DNSTypeAAAA:
+		network := "ip4"
+		if recordType == model.DNSTypeAAAA {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			records = append(records, ip.String())
+		}
+		return records, nil
+	case model.DNSTypeCNAME:
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		return []string{cname}, nil
+	case model.DNSTypeTXT:
+		return resolver.LookupTXT(ctx, host)
+	case model.DNSTypeMX:
+		mxs, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]string, 0, len(mxs))
+		for _, mx := range mxs {
+			records = append(records, mx.Host)
+		}
+		return records, nil
+	case model.DNSTypeNS:
+		nss, err := resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]string, 0, len(nss))
+		for _, ns := range nss {
+			records = append(records, ns.Host)
+		}
+		return records, nil
+	default:
+		return nil, fmt.Errorf("unsupported dns record type: %s", recordType)
+	}
+}
@@ -0,0 +1,161 @@
+package execute
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// newDNSServer answers A queries for api.test. with two addresses and TXT
+// queries with one record; other names get NXDOMAIN.
+func newDNSServer(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply := dnsReply(buf[:n]); reply != nil {
+				conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// dnsReply builds the response to query, copying its question and pointing
+// every answer at it.
+func dnsReply(query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+	end := 12
+	var labels []string
+	for end < len(query) && query[end] != 0 {
+		length := int(query[end])
+		labels = append(labels, string(query[end+1:end+1+length]))
+		end += length + 1
+	}
+	end += 5
+	if end > len(query) {
+		return nil
+	}
+	name := strings.ToLower(strings.Join(labels, "."))
+	qtype := binary.BigEndian.Uint16(query[end-4:])
+
+	var answers [][]byte
+	rcode := uint16(0)
+	switch {
+	case name != "api.test":
+		rcode = 3
+	case qtype == 1:
+		answers = [][]byte{{10, 0, 0, 2}, {10, 0, 0, 1}}
+	case qtype == 16:
+		txt := "v=spf1 -all"
+		answers = [][]byte{append([]byte{byte(len(txt))}, txt...)}
+	}
+
+	reply := binary.BigEndian.AppendUint16(nil, binary.BigEndian.Uint16(query))
+	reply = binary.BigEndian.AppendUint16(reply, 0x8180|rcode)
+	reply = binary.BigEndian.AppendUint16(reply, 1)
+	reply = binary.BigEndian.AppendUint16(reply, uint16(len(answers)))
+	reply = append(reply, 0, 0, 0, 0)
+	reply = append(reply, query[12:end]...)
+	for _, data := range answers {
+		reply = append(reply, 0xC0, 12)
+		reply = binary.BigEndian.AppendUint16(reply, qtype)
+		reply = binary.BigEndian.AppendUint16(reply, 1)
+		reply = binary.BigEndian.AppendUint32(reply, 60)
+		reply = binary.BigEndian.AppendUint16(reply, uint16(len(data)))
+		reply = append(reply, data...)
+	}
+
+	return reply
+}
+
+func TestExecuteStepDNS(t *testing.T) {
+	t.Parallel()
+
+	server := newDNSServer(t)
+
+	tests := []struct {
+		name    string
+		step    model.Step
+		want    string
+		wantErr string
+	}{
+		{
+			name: "a records are sorted and asserted",
+			step: model.Step{
+				Method: "LOOKUP",
+				URL:    "dns://api.test",
+				DNS:    &model.DNS{Server: server},
+				Asserts: model.Asserts{
+					JSONPath: []model.JSONPathAssert{{
+						Path:      "$[1]",
+						Predicate: model.Predicate{Operation: "equals", Value: "10.0.0.2", HasValue: true},
+					}},
+					TTFB: []model.TTFBAssert{{Predicate: model.Predicate{Operation: "less_than", Value: 5000, HasValue: true}}},
+				},
+				Captures: &model.Captures{JSONPath: []model.JSONPathCapture{{Name: "record", Path: "$[0]"}}},
+			},
+			want: "10.0.0.1",
+		},
+		{
+			name: "txt records",
+			step: model.Step{
+				Method:   "LOOKUP",
+				URL:      "dns://api.test",
+				DNS:      &model.DNS{Type: "txt", Server: server},
+				Captures: &model.Captures{JSONPath: []model.JSONPathCapture{{Name: "record", Path: "$[0]"}}},
+			},
+			want: "v=spf1 -all",
+		},
+		{
+			name:    "unknown name fails",
+			step:    model.Step{Method: "LOOKUP", URL: "dns://missing.test", DNS: &model.DNS{Server: server}},
+			wantErr: "dns lookup failed",
+		},
+		{
+			name:    "unresponsive server times out",
+			step:    model.Step{Method: "LOOKUP", URL: "dns://api.test", DNS: &model.DNS{Server: "127.0.0.1:1", Timeout: 100 * time.Millisecond}},
+			wantErr: "dns lookup failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			captures := map[string]CaptureValue{}
+			_, err := newDefault().executeStep(context.Background(), tt.step, captures, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeStep() error = %v", err)
+			}
+			if captures["record"].Value != tt.want {
+				t.Fatalf("record = %v, want %s", captures["record"].Value, tt.want)
+			}
+		})
+	}
+}
@@ -1,22 +1,42 @@
 package execute
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/jacoelho/rq/internal/pathing"
 	"github.com/jacoelho/rq/internal/rq/expr"
+	"github.com/jacoelho/rq/internal/rq/idn"
 	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/output"
 	"github.com/jacoelho/rq/internal/rq/templating"
+	"github.com/jacoelho/rq/internal/rq/trace"
 )
 
-// executeStep executes a single HTTP request step with retry logic.
+// executeStep executes a single HTTP request step with retry logic. It
+// reports whether a request was made; a step that only runs checks reports
+// true once its checks ran.
 func (r *Runner) executeStep(ctx context.Context, step model.Step, captures map[string]CaptureValue, stepBaseDir string) (bool, error) {
+	if step.DocsOnly() {
+		return true, nil
+	}
+
+	if step.Foreach != nil {
+		return r.executeForeach(ctx, step, captures, stepBaseDir)
+	}
+
 	shouldExecute, err := evaluateStepCondition(step, captures)
 	if err != nil {
 		return false, err
@@ -28,6 +48,20 @@ func (r *Runner) executeStep(ctx context.Context, step model.Step, captures map[
 		return false, nil
 	}
 
+	if step.ChecksOnly() {
+		return true, r.runChecks(step.Checks, captures)
+	}
+
+	if header, _ := step.Options.Matrix(); header != "" {
+		return r.executeMatrix(ctx, step, captures, stepBaseDir)
+	}
+
+	return r.executeStepWithRetries(ctx, step, captures, stepBaseDir)
+}
+
+// executeStepWithRetries sends the step, retrying failed attempts up to
+// options.retries times.
+func (r *Runner) executeStepWithRetries(ctx context.Context, step model.Step, captures map[string]CaptureValue, stepBaseDir string) (bool, error) {
 	maxAttempts := max(step.Options.Retries+1, 1)
 
 	var lastErr error
@@ -44,12 +78,19 @@ func (r *Runner) executeStep(ctx context.Context, step model.Step, captures map[
 			r.logf("Retry attempt %d of %d\n", attempt-1, step.Options.Retries)
 		}
 
-		attemptRequestMade, err := r.executeStepAttempt(ctx, step, captures, stepBaseDir)
+		attemptStart := time.Now()
+		attemptRequestMade, err := r.executeStepAttempt(withAttempt(ctx, attempt), step, captures, stepBaseDir)
+		r.traceSpan(ctx, trace.CategoryAttempt, fmt.Sprintf("attempt %d", attempt), attemptStart, err)
+		stepAttemptLog(ctx).record(attempt, time.Since(attemptStart), err)
 		if attemptRequestMade {
 			requestMade = true
 		}
 
 		if err != nil && !attemptRequestMade {
+			// A breaker opened by this step's own retries keeps the connection error.
+			if lastErr != nil && output.IsSkipped(err) {
+				return requestMade, lastErr
+			}
 			return requestMade, err
 		}
 
@@ -58,6 +99,14 @@ func (r *Runner) executeStep(ctx context.Context, step model.Step, captures map[
 		}
 
 		lastErr = err
+
+		wait := retryWait(step.Options, attempt)
+		if r.config != nil && r.config.Debug && wait > 0 {
+			r.logf("Waiting %s before retry\n", wait)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return requestMade, err
+		}
 	}
 
 	return requestMade, lastErr
@@ -69,41 +118,173 @@ func (r *Runner) executeStepAttempt(ctx context.Context, step model.Step, captur
 	if err != nil {
 		return false, err
 	}
+	if err := r.breaker.allow(req.URL.Host); err != nil {
+		return false, err
+	}
+	credential, err := r.authorize(ctx, step, req, captures)
+	if err != nil {
+		return false, err
+	}
 
 	staticSecrets := r.staticSecrets()
 	valuesToRedact := redactValues(captures, staticSecrets)
+	if credential != "" {
+		valuesToRedact = append(valuesToRedact, credential)
+	}
 	if r.config != nil && r.config.Debug {
 		r.debugRequest(req, valuesToRedact)
 	}
 
-	resp, respBody, err := r.executeRequest(ctx, step.Options, req)
+	output, err := resolveOutputPath(step, captures, stepBaseDir)
 	if err != nil {
-		return true, err
+		return false, err
 	}
 
-	if err := r.processStepResponse(step, resp, respBody, captures); err != nil {
+	var (
+		resp     *http.Response
+		respBody []byte
+		cacheKey string
+	)
+	switch {
+	case step.PollJob != nil:
+		resp, respBody, err = r.pollJob(ctx, step, req, captures)
+	case step.WebSocket != nil:
+		resp, respBody, err = r.executeWebSocket(ctx, step, req, captures)
+	case step.GRPC != nil:
+		resp, respBody, err = r.executeGRPC(ctx, step, req, stepBaseDir)
+	case step.Connect != nil:
+		resp, respBody, err = r.executeConnect(ctx, step, req)
+	case step.DNS != nil:
+		resp, respBody, err = r.executeDNS(ctx, step, req)
+	case output != "":
+		resp, err = r.downloadResponse(ctx, step.Options, req, output)
+	case step.Options.Cacheable && r.cache != nil:
+		resp, respBody, cacheKey, err = r.cachedRequest(ctx, step.Options, req)
+	default:
+		resp, respBody, err = r.executeRequest(ctx, step.Options, req)
+	}
+	if err != nil {
 		return true, err
 	}
+	stepAttemptLog(ctx).setResponse(resp, respBody)
+	recordValidators(ctx, step, resp)
 
+	err = r.processStepResponse(step, resp, respBody, output, captures, stepBaseDir)
 	if r.config != nil && r.config.Debug {
+		// Captures may have added redacted values; a failed step is dumped
+		// too, as that is when the response matters most.
 		valuesToRedact = redactValues(captures, staticSecrets)
+		if credential != "" {
+			valuesToRedact = append(valuesToRedact, credential)
+		}
 		r.debugResponse(resp, respBody, valuesToRedact)
 	}
+	if err != nil {
+		return true, err
+	}
+	r.saveCached(cacheKey, resp, respBody)
 
 	return true, nil
 }
 
-// getClient returns an HTTP client configured for the specific options' redirect settings.
-func (r *Runner) getClient(options model.Options) *http.Client {
+// getClient returns an HTTP client configured for the specific options' TLS,
+// HTTP version and redirect settings.
+func (r *Runner) getClient(options model.Options) (*http.Client, error) {
+	client := r.client
+	if options.TLS != nil {
+		var err error
+		client, err = r.tlsClient(*options.TLS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.HTTPVersion != "" {
+		var err error
+		client, err = r.versionClient(client, options.HTTPVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if options.FollowRedirect == nil || *options.FollowRedirect {
-		return r.client
+		return client, nil
 	}
 
-	clientCopy := *r.client
+	clientCopy := *client
 	clientCopy.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	}
-	return &clientCopy
+	return &clientCopy, nil
+}
+
+// tlsClient returns a client whose transport applies the step TLS options.
+// Clients are cached per option set so connections are still reused.
+func (r *Runner) tlsClient(options model.TLSOptions) (*http.Client, error) {
+	key := options.MinVersion + "|" + options.MaxVersion + "|" + strings.Join(options.Ciphers, ",")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if client, ok := r.tlsClients[key]; ok {
+		return client, nil
+	}
+
+	var transport *http.Transport
+	switch base := r.client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = base.Clone()
+	default:
+		return nil, fmt.Errorf("tls options require an *http.Transport, got %T", base)
+	}
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if err := applyTLSOptions(transport.TLSClientConfig, options); err != nil {
+		return nil, err
+	}
+
+	client := *r.client
+	client.Transport = transport
+	if r.tlsClients == nil {
+		r.tlsClients = make(map[string]*http.Client)
+	}
+	r.tlsClients[key] = &client
+
+	return &client, nil
+}
+
+func applyTLSOptions(cfg *tls.Config, options model.TLSOptions) error {
+	if options.MinVersion != "" {
+		version, err := model.ParseTLSVersion(options.MinVersion)
+		if err != nil {
+			return err
+		}
+		cfg.MinVersion = version
+	}
+	if options.MaxVersion != "" {
+		version, err := model.ParseTLSVersion(options.MaxVersion)
+		if err != nil {
+			return err
+		}
+		cfg.MaxVersion = version
+	}
+	if len(options.Ciphers) > 0 {
+		suites := make([]uint16, 0, len(options.Ciphers))
+		for _, name := range options.Ciphers {
+			id, err := model.ParseCipherSuite(name)
+			if err != nil {
+				return err
+			}
+			suites = append(suites, id)
+		}
+		cfg.CipherSuites = suites
+	}
+
+	return nil
 }
 
 // captureMapForTemplate converts capture map to map[string]any for template expansion
@@ -138,6 +319,11 @@ func prepareRequest(ctx context.Context, step model.Step, captures map[string]Ca
 		return nil, fmt.Errorf("failed to process URL template: %w", err)
 	}
 
+	requestURL, err = normalizeRequestURL(requestURL)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(step.Query) > 0 {
 		requestURL, err = processQueryParameters(requestURL, step.Query, tmplVars)
 		if err != nil {
@@ -150,6 +336,13 @@ func prepareRequest(ctx context.Context, step model.Step, captures map[string]Ca
 		return nil, err
 	}
 
+	if step.Options.BodyCanonicalJSON {
+		body, err = canonicalizeJSON(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	req, err := newHTTPRequest(ctx, step.Method, requestURL, body)
 	if err != nil {
 		return nil, err
@@ -159,6 +352,34 @@ func prepareRequest(ctx context.Context, step model.Step, captures map[string]Ca
 		return nil, err
 	}
 
+	if len(step.Multipart) > 0 {
+		body, err := renderMultipart(step.Multipart, tmplVars, stepBaseDir)
+		if err != nil {
+			return nil, err
+		}
+		body.attach(req)
+	}
+
+	if step.Conditional != nil {
+		header, name := step.Conditional.Header()
+		etag, ok := captures[name]
+		if !ok || fmt.Sprint(etag.Value) == "" {
+			return nil, fmt.Errorf("conditional %s: capture %q is not set; capture the ETag header in an earlier step", header, name)
+		}
+		req.Header.Set(header, fmt.Sprint(etag.Value))
+	}
+	if err := applyConditionalOn(ctx, step, req); err != nil {
+		return nil, err
+	}
+
+	if (step.Body.IsStructured() || step.GraphQL != nil) && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if step.Options.ExpectContinue {
+		req.Header.Set("Expect", "100-continue")
+	}
+
 	return req, nil
 }
 
@@ -181,7 +402,14 @@ func resolveRequestBody(step model.Step, templateVars map[string]any) (string, e
 }
 
 func resolveRequestBodyWithBaseDir(step model.Step, templateVars map[string]any, baseDir string) (string, error) {
-	body, err := templating.Apply(step.Body, templateVars)
+	if step.GraphQL != nil {
+		return renderGraphQLBody(*step.GraphQL, templateVars)
+	}
+	if step.Body.IsStructured() {
+		return renderStructuredBody(step.Body.Value, templateVars)
+	}
+
+	body, err := templating.Apply(step.Body.Text, templateVars)
 	if err != nil {
 		return "", fmt.Errorf("failed to process body template: %w", err)
 	}
@@ -205,7 +433,113 @@ func resolveRequestBodyWithBaseDir(step model.Step, templateVars map[string]any,
 		return "", fmt.Errorf("failed to read body_file %s: %w", filePath, err)
 	}
 
-	return string(content), nil
+	// Content without template actions is sent as read, so binary payloads
+	// that predate templating keep working without body_file_raw.
+	if step.BodyFileRaw || !bytes.Contains(content, []byte("{{")) {
+		return string(content), nil
+	}
+
+	rendered, err := templating.ApplyWithName(filepath.Base(filePath), string(content), templateVars)
+	if err != nil {
+		return "", fmt.Errorf("failed to process body_file template %s: %w", filePath, err)
+	}
+
+	return rendered, nil
+}
+
+// renderStructuredBody applies templates to leaf strings and serializes the result as JSON.
+func renderStructuredBody(value any, templateVars map[string]any) (string, error) {
+	rendered, err := applyTemplatesToValue(value, templateVars)
+	if err != nil {
+		return "", fmt.Errorf("failed to process body template: %w", err)
+	}
+
+	payload, err := json.Marshal(rendered)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode body as JSON: %w", err)
+	}
+
+	return string(payload), nil
+}
+
+// renderGraphQLBody encodes a GraphQL request, applying templates to the
+// leaf strings of its variables.
+func renderGraphQLBody(graphql model.GraphQL, templateVars map[string]any) (string, error) {
+	payload := map[string]any{"query": graphql.Query}
+	if graphql.OperationName != "" {
+		payload["operationName"] = graphql.OperationName
+	}
+	if len(graphql.Variables) > 0 {
+		variables, err := applyTemplatesToValue(graphql.Variables, templateVars)
+		if err != nil {
+			return "", fmt.Errorf("failed to process graphql variables template: %w", err)
+		}
+		payload["variables"] = variables
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode graphql request: %w", err)
+	}
+
+	return string(body), nil
+}
+
+// canonicalizeJSON re-encodes a JSON document with sorted object keys, no
+// insignificant whitespace and numbers preserved verbatim.
+func canonicalizeJSON(body string) (string, error) {
+	if strings.TrimSpace(body) == "" {
+		return body, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("failed to canonicalize body: invalid JSON: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", errors.New("failed to canonicalize body: unexpected data after JSON value")
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to canonicalize body: %w", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func applyTemplatesToValue(value any, templateVars map[string]any) (any, error) {
+	switch current := value.(type) {
+	case string:
+		return templating.Apply(current, templateVars)
+	case map[string]any:
+		out := make(map[string]any, len(current))
+		for key, item := range current {
+			rendered, err := applyTemplatesToValue(item, templateVars)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = rendered
+		}
+		return out, nil
+	case []any:
+		out := make([]any, 0, len(current))
+		for _, item := range current {
+			rendered, err := applyTemplatesToValue(item, templateVars)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, rendered)
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
 }
 
 func applyTemplatedHeaders(req *http.Request, headers model.KeyValues, templateVars map[string]any) error {
@@ -227,17 +561,16 @@ func applyTemplatedHeaders(req *http.Request, headers model.KeyValues, templateV
 }
 
 func (r *Runner) executeRequest(ctx context.Context, options model.Options, req *http.Request) (*http.Response, []byte, error) {
-	if err := r.rateLimiter.Wait(ctx); err != nil {
-		return nil, nil, fmt.Errorf("rate limiting interrupted: %w", err)
-	}
-
-	resp, err := r.getClient(options).Do(req)
+	resp, err := r.sendRequest(ctx, options, req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("request failed: %w", err)
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	bodyStart := time.Now()
+	respBody, err := readResponseBody(resp, r.maxResponseBytes())
+	r.traceRequestPhases(resp, bodyStart)
+	completeTiming(resp)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
 	}
@@ -245,23 +578,118 @@ func (r *Runner) executeRequest(ctx context.Context, options model.Options, req
 	return resp, respBody, nil
 }
 
-func (r *Runner) processStepResponse(step model.Step, resp *http.Response, respBody []byte, captures map[string]CaptureValue) error {
-	hasJSONPathSelectors := len(step.Asserts.JSONPath) > 0
+// downloadResponse sends req and streams the response body to path instead
+// of holding it in memory.
+func (r *Runner) downloadResponse(ctx context.Context, options model.Options, req *http.Request, path string) (*http.Response, error) {
+	resp, err := r.sendRequest(ctx, options, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyStart := time.Now()
+	err = writeResponseBody(resp, path, r.maxResponseBytes())
+	r.traceRequestPhases(resp, bodyStart)
+	completeTiming(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write response body to %s: %w", path, err)
+	}
+
+	return resp, nil
+}
+
+func (r *Runner) sendRequest(ctx context.Context, options model.Options, req *http.Request) (*http.Response, error) {
+	if err := r.rateLimiter.Wait(ctx, req.URL.Host); err != nil {
+		return nil, fmt.Errorf("rate limiting interrupted: %w", err)
+	}
+
+	client, err := r.getClient(options)
+	if err != nil {
+		return nil, err
+	}
+	client = clientWithCookies(client, stepCookieJar(ctx), options)
+
+	resp, err := client.Do(withRequestTiming(req))
+	r.breaker.record(req.URL.Host, err != nil && ctx.Err() == nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	return resp, nil
+}
+
+// processStepResponse runs the asserts and captures of step. output is the
+// file the body was streamed to, in which case respBody is empty.
+func (r *Runner) processStepResponse(step model.Step, resp *http.Response, respBody []byte, output string, captures map[string]CaptureValue, stepBaseDir string) error {
+	hasJSONPathSelectors := len(step.Asserts.JSONPath) > 0 || len(step.Asserts.Expr) > 0 || step.Asserts.GraphQL != nil
 	if step.Captures != nil && len(step.Captures.JSONPath) > 0 {
 		hasJSONPathSelectors = true
 	}
 
-	selectors := selectorContextFromBody(respBody, hasJSONPathSelectors)
+	if err := checkBodilessResponse(step.Asserts, resp, respBody); err != nil {
+		return fmt.Errorf("assertion failed: %w", err)
+	}
+
+	hasCSSSelectors := len(step.Asserts.CSS) > 0 || (step.Captures != nil && len(step.Captures.CSS) > 0)
+	selectors := selectorContextFromBody(respBody, hasJSONPathSelectors, step.Options).withHTML(respBody, hasCSSSelectors)
+	if output != "" {
+		selectors = selectorContextFromFile(output)
+	}
 
 	if err := r.executeAssertions(step.Asserts, resp, selectors); err != nil {
 		return fmt.Errorf("assertion failed: %w", err)
 	}
 
+	if err := checkGoldenFiles(step.Asserts.Golden, respBody, stepBaseDir); err != nil {
+		return fmt.Errorf("assertion failed: %w", err)
+	}
+
+	if err := checkJSONSchemas(step.Asserts.JSONSchema, respBody, stepBaseDir); err != nil {
+		return fmt.Errorf("assertion failed: %w", err)
+	}
+
+	if output != "" {
+		if err := checkBodyFileAsserts(step.Asserts.Body, output); err != nil {
+			return fmt.Errorf("assertion failed: %w", err)
+		}
+	} else if err := checkBodyAsserts(step.Asserts.Body, respBody); err != nil {
+		return fmt.Errorf("assertion failed: %w", err)
+	}
+
+	if err := r.checkOpenAPI(step, resp, respBody, output); err != nil {
+		return fmt.Errorf("assertion failed: %w", err)
+	}
+
+	if err := checkExprAsserts(step.Asserts.Expr, resp, respBody, selectors, step.Options, captures); err != nil {
+		return fmt.Errorf("assertion failed: %w", err)
+	}
+
+	if step.Asserts.Range != nil && *step.Asserts.Range {
+		if err := checkRange(resp, respBody); err != nil {
+			return fmt.Errorf("assertion failed: %w", err)
+		}
+	}
+
 	if err := r.executeCapturesWithSelectors(step.Captures, resp, respBody, selectors, captures); err != nil {
 		return fmt.Errorf("capture failed: %w", err)
 	}
 
-	return nil
+	if err := captureOutput(step.Captures, output, captures); err != nil {
+		return fmt.Errorf("capture failed: %w", err)
+	}
+
+	if err := r.writeCaptureSinks(step.Captures, captures, stepBaseDir); err != nil {
+		return fmt.Errorf("capture failed: %w", err)
+	}
+
+	return r.runChecks(step.Checks, captures)
+}
+
+func (r *Runner) maxResponseBytes() int64 {
+	if r.config == nil {
+		return 0
+	}
+	return r.config.MaxResponseBytes
 }
 
 func (r *Runner) staticSecrets() map[string]any {
@@ -271,6 +699,48 @@ func (r *Runner) staticSecrets() map[string]any {
 	return r.config.Secrets
 }
 
+// normalizeRequestURL punycodes internationalized hosts and percent-encodes
+// non-ASCII path and query characters, so the URL is sent in wire form.
+func normalizeRequestURL(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	if host := parsedURL.Hostname(); host != "" {
+		asciiHost, err := idn.ToASCII(host)
+		if err != nil {
+			return "", fmt.Errorf("failed to normalize URL host: %w", err)
+		}
+		if asciiHost != host {
+			if port := parsedURL.Port(); port != "" {
+				parsedURL.Host = net.JoinHostPort(asciiHost, port)
+			} else {
+				parsedURL.Host = asciiHost
+			}
+		}
+	}
+
+	parsedURL.RawQuery = escapeNonASCII(parsedURL.RawQuery)
+
+	return parsedURL.String(), nil
+}
+
+// escapeNonASCII percent-encodes bytes outside printable ASCII, leaving
+// existing escapes and query delimiters untouched.
+func escapeNonASCII(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
 // processQueryParameters processes query parameters from a step and appends them to the given URL.
 func processQueryParameters(requestURL string, queryParams model.KeyValues, captures map[string]any) (string, error) {
 	if len(queryParams) == 0 {
@@ -2,14 +2,18 @@ package execute
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/jacoelho/rq/internal/rq/model"
 )
@@ -20,7 +24,7 @@ func TestResolveRequestBody(t *testing.T) {
 	t.Run("inline body", func(t *testing.T) {
 		t.Parallel()
 
-		body, err := resolveRequestBody(model.Step{Body: `{"id":"{{.id}}"}`}, map[string]any{"id": "123"})
+		body, err := resolveRequestBody(model.Step{Body: model.TextBody(`{"id":"{{.id}}"}`)}, map[string]any{"id": "123"})
 		if err != nil {
 			t.Fatalf("resolveRequestBody() error = %v", err)
 		}
@@ -48,10 +52,50 @@ func TestResolveRequestBody(t *testing.T) {
 		}
 	})
 
+	t.Run("body_file content templates", func(t *testing.T) {
+		t.Parallel()
+
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "payload.json")
+		if err := os.WriteFile(filePath, []byte(`{"id":"{{.id}}"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		templated, err := resolveRequestBody(model.Step{BodyFile: filePath}, map[string]any{"id": "42"})
+		if err != nil {
+			t.Fatalf("resolveRequestBody() error = %v", err)
+		}
+		if templated != `{"id":"42"}` {
+			t.Fatalf("templated body = %q", templated)
+		}
+
+		raw, err := resolveRequestBody(model.Step{BodyFile: filePath, BodyFileRaw: true}, map[string]any{"id": "42"})
+		if err != nil {
+			t.Fatalf("resolveRequestBody() error = %v", err)
+		}
+		if raw != `{"id":"{{.id}}"}` {
+			t.Fatalf("raw body = %q", raw)
+		}
+	})
+
+	t.Run("invalid body_file template", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "payload.txt")
+		if err := os.WriteFile(filePath, []byte("{{.id"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := resolveRequestBody(model.Step{BodyFile: filePath}, nil)
+		if err == nil || !strings.Contains(err.Error(), "failed to process body_file template") {
+			t.Fatalf("resolveRequestBody() error = %v, want template error", err)
+		}
+	})
+
 	t.Run("empty body_file path uses inline body", func(t *testing.T) {
 		t.Parallel()
 
-		step := model.Step{Body: "fallback", BodyFile: "   "}
+		step := model.Step{Body: model.TextBody("fallback"), BodyFile: "   "}
 		body, err := resolveRequestBody(step, nil)
 		if err != nil {
 			t.Fatalf("resolveRequestBody() error = %v", err)
@@ -152,6 +196,460 @@ func TestResolveRequestBodyWithBaseDirKeepsAbsoluteLikePath(t *testing.T) {
 	}
 }
 
+func TestPrepareRequestStructuredBody(t *testing.T) {
+	t.Parallel()
+
+	t.Run("serializes JSON and sets content type", func(t *testing.T) {
+		t.Parallel()
+
+		step := model.Step{
+			Method: "POST",
+			URL:    "https://api.example.com/users",
+			Body: model.Body{Value: map[string]any{
+				"name":  "{{.name}}",
+				"age":   int64(30),
+				"roles": []any{"admin", "{{.role}}"},
+			}},
+		}
+
+		req, err := prepareRequest(context.Background(), step, map[string]CaptureValue{
+			"name": {Value: `Jane "JJ" Doe`},
+			"role": {Value: "viewer"},
+		}, "")
+		if err != nil {
+			t.Fatalf("prepareRequest() error = %v", err)
+		}
+		defer req.Body.Close()
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatalf("ReadAll(req.Body) error = %v", err)
+		}
+		want := `{"age":30,"name":"Jane \"JJ\" Doe","roles":["admin","viewer"]}`
+		if string(body) != want {
+			t.Fatalf("request body = %s, want %s", body, want)
+		}
+		if got := req.Header.Get("Content-Type"); got != "application/json" {
+			t.Fatalf("Content-Type = %q, want application/json", got)
+		}
+	})
+
+	t.Run("keeps explicit content type", func(t *testing.T) {
+		t.Parallel()
+
+		step := model.Step{
+			Method:  "POST",
+			URL:     "https://api.example.com/users",
+			Headers: model.KeyValues{{Key: "Content-Type", Value: "application/vnd.api+json"}},
+			Body:    model.Body{Value: []any{}},
+		}
+
+		req, err := prepareRequest(context.Background(), step, nil, "")
+		if err != nil {
+			t.Fatalf("prepareRequest() error = %v", err)
+		}
+		if got := req.Header.Get("Content-Type"); got != "application/vnd.api+json" {
+			t.Fatalf("Content-Type = %q", got)
+		}
+	})
+}
+
+func TestCanonicalizeJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "sorts keys and strips whitespace",
+			input: "{\n  \"b\": [1, 2],\n  \"a\": {\"d\": true, \"c\": null}\n}",
+			want:  `{"a":{"c":null,"d":true},"b":[1,2]}`,
+		},
+		{
+			name:  "preserves number representation and html characters",
+			input: `{"amount": 10.50, "big": 12345678901234567890, "html": "<a&b>"}`,
+			want:  `{"amount":10.50,"big":12345678901234567890,"html":"<a&b>"}`,
+		},
+		{
+			name:  "empty body is unchanged",
+			input: "",
+			want:  "",
+		},
+		{
+			name:    "invalid JSON",
+			input:   `{"a":`,
+			wantErr: true,
+		},
+		{
+			name:    "trailing data",
+			input:   `{"a":1} {"b":2}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := canonicalizeJSON(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("canonicalizeJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("canonicalizeJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareRequestCanonicalizesBody(t *testing.T) {
+	t.Parallel()
+
+	step := model.Step{
+		Method:  "POST",
+		URL:     "https://api.example.com/orders",
+		Body:    model.TextBody(`{ "id": "{{.id}}", "amount": 1.0 }`),
+		Options: model.Options{BodyCanonicalJSON: true},
+	}
+
+	req, err := prepareRequest(context.Background(), step, map[string]CaptureValue{
+		"id": {Value: "42"},
+	}, "")
+	if err != nil {
+		t.Fatalf("prepareRequest() error = %v", err)
+	}
+	defer req.Body.Close()
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("ReadAll(req.Body) error = %v", err)
+	}
+	if want := `{"amount":1.0,"id":"42"}`; string(body) != want {
+		t.Fatalf("request body = %s, want %s", body, want)
+	}
+}
+
+func TestExecuteStepCustomMethod(t *testing.T) {
+	t.Parallel()
+
+	var gotMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	runner := newDefault()
+	step := model.Step{
+		Method:  "PURGE",
+		URL:     server.URL + "/assets/app.js",
+		Options: model.Options{AllowCustomMethod: true},
+		Asserts: model.Asserts{
+			Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200}}},
+		},
+	}
+
+	if _, err := runner.executeStep(context.Background(), step, map[string]CaptureValue{}, ""); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+	if gotMethod != "PURGE" {
+		t.Fatalf("server saw method %q, want PURGE", gotMethod)
+	}
+}
+
+func TestExecuteStepAllowAndBodilessResponses(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodOptions:
+			w.Header().Set("Allow", "GET, HEAD, POST, OPTIONS")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":1}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	jsonPathAsserts := []model.JSONPathAssert{{Path: "$.id", Predicate: model.Predicate{Operation: "equals", Value: 1}}}
+
+	tests := []struct {
+		name    string
+		step    model.Step
+		wantErr string
+	}{
+		{
+			name: "allow contains advertised methods",
+			step: model.Step{
+				Method:  http.MethodOptions,
+				URL:     server.URL,
+				Asserts: model.Asserts{Allow: &model.AllowAssert{Contains: []string{"get", "POST"}, Excludes: []string{"DELETE"}}},
+			},
+		},
+		{
+			name: "allow missing method",
+			step: model.Step{
+				Method:  http.MethodOptions,
+				URL:     server.URL,
+				Asserts: model.Asserts{Allow: &model.AllowAssert{Contains: []string{"DELETE"}}},
+			},
+			wantErr: `allow assertion failed: expected DELETE in Allow "GET, HEAD, POST, OPTIONS"`,
+		},
+		{
+			name: "allow excluded method present",
+			step: model.Step{
+				Method:  http.MethodOptions,
+				URL:     server.URL,
+				Asserts: model.Asserts{Allow: &model.AllowAssert{Excludes: []string{"POST"}}},
+			},
+			wantErr: "allow assertion failed: did not expect POST",
+		},
+		{
+			name: "allow header absent",
+			step: model.Step{
+				Method:  http.MethodGet,
+				URL:     server.URL,
+				Asserts: model.Asserts{Allow: &model.AllowAssert{Contains: []string{"GET"}}},
+			},
+			wantErr: "allow assertion failed: response has no Allow header",
+		},
+		{
+			name: "jsonpath on HEAD response",
+			step: model.Step{
+				Method:  http.MethodHead,
+				URL:     server.URL,
+				Asserts: model.Asserts{JSONPath: jsonPathAsserts},
+			},
+			wantErr: "jsonpath assertion cannot run: HEAD responses have no body",
+		},
+		{
+			name: "golden on no content response",
+			step: model.Step{
+				Method:  http.MethodOptions,
+				URL:     server.URL,
+				Asserts: model.Asserts{Golden: []model.GoldenAssert{{File: "expected.json"}}},
+			},
+			wantErr: "golden assertion cannot run: status 204 responses have no body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newDefault().executeStep(context.Background(), tt.step, map[string]CaptureValue{}, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("executeStep() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("executeStep() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteStepLenientJSON(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte("\xEF\xBB\xBF{\"id\":\"abc\"}\n<!-- legacy footer -->"))
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name    string
+		lenient bool
+		wantErr bool
+	}{
+		{name: "strict parsing rejects BOM and trailing bytes", wantErr: true},
+		{name: "lenient parsing reads first document", lenient: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			step := model.Step{
+				Method:  "GET",
+				URL:     server.URL,
+				Options: model.Options{LenientJSON: tt.lenient},
+				Captures: &model.Captures{
+					JSONPath: []model.JSONPathCapture{{Name: "id", Path: "$.id"}},
+				},
+			}
+			captures := map[string]CaptureValue{}
+
+			_, err := newDefault().executeStep(context.Background(), step, captures, "")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected JSON parse error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeStep() error = %v", err)
+			}
+			if captures["id"].Value != "abc" {
+				t.Fatalf("id = %v, want abc", captures["id"].Value)
+			}
+		})
+	}
+}
+
+func TestExecuteStepJSONLines(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Write([]byte("{\"id\":\"a\",\"ok\":true}\n{\"id\":\"b\",\"ok\":false}\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	step := model.Step{
+		Method:  "GET",
+		URL:     server.URL,
+		Options: model.Options{JSONLines: true},
+		Asserts: model.Asserts{
+			JSONPath: []model.JSONPathAssert{{
+				Path:      "$",
+				Predicate: model.Predicate{Operation: "length", Value: 2, HasValue: true},
+			}},
+		},
+		Captures: &model.Captures{
+			JSONPath: []model.JSONPathCapture{{Name: "failed", Path: "$[?@.ok == false].id"}},
+		},
+	}
+	captures := map[string]CaptureValue{}
+
+	if _, err := newDefault().executeStep(context.Background(), step, captures, ""); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+	if captures["failed"].Value != "b" {
+		t.Fatalf("failed = %v, want b", captures["failed"].Value)
+	}
+}
+
+func TestExecuteStepConditional(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const etag = `"v2"`
+		switch {
+		case r.Header.Get("If-None-Match") == etag:
+			w.WriteHeader(http.StatusNotModified)
+		case r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag:
+			w.WriteHeader(http.StatusPreconditionFailed)
+		default:
+			w.Header().Set("ETag", etag)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	status := func(code int) model.Asserts {
+		return model.Asserts{Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: code, HasValue: true}}}}
+	}
+
+	runner := newDefault()
+	captures := map[string]CaptureValue{"stale_etag": {Value: `"v1"`}}
+	steps := []model.Step{
+		{
+			Method:   "GET",
+			URL:      server.URL,
+			Asserts:  status(http.StatusOK),
+			Captures: &model.Captures{Headers: []model.HeaderCapture{{Name: "etag", HeaderName: "ETag"}}},
+		},
+		{
+			Method:      "GET",
+			URL:         server.URL,
+			Conditional: &model.Conditional{IfNoneMatch: "etag"},
+			Asserts:     status(http.StatusNotModified),
+		},
+		{
+			Method:      "PUT",
+			URL:         server.URL,
+			Conditional: &model.Conditional{IfMatch: "stale_etag"},
+			Asserts:     status(http.StatusPreconditionFailed),
+		},
+	}
+	for i, step := range steps {
+		if _, err := runner.executeStep(context.Background(), step, captures, ""); err != nil {
+			t.Fatalf("step %d: executeStep() error = %v", i, err)
+		}
+	}
+
+	missing := model.Step{Method: "GET", URL: server.URL, Conditional: &model.Conditional{IfMatch: "unknown"}}
+	_, err := runner.executeStep(context.Background(), missing, captures, "")
+	if err == nil || !strings.Contains(err.Error(), `capture "unknown" is not set`) {
+		t.Fatalf("executeStep() error = %v, want missing capture error", err)
+	}
+}
+
+func TestExecuteStepConditionalOn(t *testing.T) {
+	t.Parallel()
+
+	const (
+		etag         = `"v2"`
+		lastModified = "Mon, 01 Jan 2024 00:00:00 GMT"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified:
+			w.WriteHeader(http.StatusNotModified)
+		case r.Header.Get("If-Match") == etag && r.Header.Get("If-Unmodified-Since") == lastModified:
+			w.WriteHeader(http.StatusNoContent)
+		case r.Header.Get("If-Match") != "":
+			w.WriteHeader(http.StatusPreconditionFailed)
+		case r.URL.Path == "/plain":
+		default:
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Last-Modified", lastModified)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	status := func(code int) model.Asserts {
+		return model.Asserts{Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: code, HasValue: true}}}}
+	}
+
+	runner := newDefault()
+	ctx := withValidators(context.Background())
+	captures := map[string]CaptureValue{}
+	steps := []model.Step{
+		{Method: "GET", URL: server.URL, Name: "load", Asserts: status(http.StatusOK)},
+		{Method: "GET", URL: server.URL, ConditionalOn: "load", Asserts: status(http.StatusNotModified)},
+		{Method: "PUT", URL: server.URL, ConditionalOn: "load", Asserts: status(http.StatusNoContent)},
+		{Method: "GET", URL: server.URL + "/plain", Name: "plain"},
+	}
+	for i, step := range steps {
+		if _, err := runner.executeStep(ctx, step, captures, ""); err != nil {
+			t.Fatalf("step %d: executeStep() error = %v", i, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		on   string
+		want string
+	}{
+		{name: "no validators", on: "plain", want: `the response to step "plain" has no ETag or Last-Modified header`},
+		{name: "not run", on: "missing", want: `step "missing" has not received a response`},
+	}
+	for _, tt := range tests {
+		step := model.Step{Method: "GET", URL: server.URL, ConditionalOn: tt.on}
+		if _, err := runner.executeStep(ctx, step, captures, ""); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("%s: executeStep() error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
 func TestExecuteStepWhenCondition(t *testing.T) {
 	t.Parallel()
 
@@ -245,3 +743,637 @@ func TestProcessQueryParametersPreservesInsertionOrder(t *testing.T) {
 		t.Fatalf("RawQuery = %q, want %q", parsedURL.RawQuery, wantRawQuery)
 	}
 }
+
+func TestNormalizeRequestURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "ascii unchanged", input: "https://example.com/a?b=c", want: "https://example.com/a?b=c"},
+		{name: "idn host", input: "https://münchen.de/", want: "https://xn--mnchen-3ya.de/"},
+		{name: "idn host with port", input: "https://münchen.de:8443/", want: "https://xn--mnchen-3ya.de:8443/"},
+		{name: "unicode path", input: "https://example.com/straße/日本", want: "https://example.com/stra%C3%9Fe/%E6%97%A5%E6%9C%AC"},
+		{name: "unicode query", input: "https://example.com/?q=ü x&a=1", want: "https://example.com/?q=%C3%BC%20x&a=1"},
+		{name: "existing escapes preserved", input: "https://example.com/a%20b?q=%C3%BC", want: "https://example.com/a%20b?q=%C3%BC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := normalizeRequestURL(tt.input)
+			if err != nil {
+				t.Fatalf("normalizeRequestURL(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Fatalf("normalizeRequestURL(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteStepURLAssertAndCapture(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	runner := newDefault()
+	step := model.Step{
+		Method: "GET",
+		URL:    server.URL + "/{{.city}}",
+		Asserts: model.Asserts{
+			URL: []model.URLAssert{{Predicate: model.Predicate{Operation: "ends_with", Value: "/m%C3%BCnchen", HasValue: true}}},
+		},
+		Captures: &model.Captures{
+			URL: []model.URLCapture{{Name: "requested_url"}},
+		},
+	}
+	captures := map[string]CaptureValue{"city": {Value: "münchen"}}
+
+	if _, err := runner.executeStep(context.Background(), step, captures, ""); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+	if gotPath != "/m%C3%BCnchen" {
+		t.Fatalf("server path = %q, want /m%%C3%%BCnchen", gotPath)
+	}
+	if want := server.URL + "/m%C3%BCnchen"; captures["requested_url"].Value != want {
+		t.Fatalf("requested_url = %v, want %s", captures["requested_url"].Value, want)
+	}
+}
+
+func TestExecuteStepTLSOptions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	newRunner := func() *Runner {
+		runner := newDefault()
+		runner.client = server.Client()
+		return runner
+	}
+
+	t.Run("max version and cipher are negotiated", func(t *testing.T) {
+		t.Parallel()
+
+		step := model.Step{
+			Method: "GET",
+			URL:    server.URL,
+			Options: model.Options{TLS: &model.TLSOptions{
+				MaxVersion: "1.2",
+				Ciphers:    []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+			}},
+			Asserts: model.Asserts{
+				TLS: []model.TLSAssert{{Name: "version", Predicate: model.Predicate{Operation: "equals", Value: "TLS 1.2", HasValue: true}}},
+			},
+			Captures: &model.Captures{
+				TLS: []model.TLSCapture{{Name: "cipher", TLSField: "cipher"}},
+			},
+		}
+		captures := map[string]CaptureValue{}
+
+		if _, err := newRunner().executeStep(context.Background(), step, captures, ""); err != nil {
+			t.Fatalf("executeStep() error = %v", err)
+		}
+		if got := captures["cipher"].Value; got != "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256" {
+			t.Fatalf("cipher = %v", got)
+		}
+	})
+
+	t.Run("server rejecting offered versions fails", func(t *testing.T) {
+		t.Parallel()
+
+		step := model.Step{
+			Method:  "GET",
+			URL:     server.URL,
+			Options: model.Options{TLS: &model.TLSOptions{MaxVersion: "1.1"}},
+		}
+
+		if _, err := newRunner().executeStep(context.Background(), step, map[string]CaptureValue{}, ""); err == nil {
+			t.Fatal("expected handshake failure")
+		}
+	})
+}
+
+func TestExecuteStepTTFB(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(150 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	}))
+	t.Cleanup(server.Close)
+
+	step := model.Step{
+		Method: "GET",
+		URL:    server.URL,
+		Asserts: model.Asserts{
+			TTFB: []model.TTFBAssert{
+				{Predicate: model.Predicate{Operation: "greater_than_or_equal", Value: 30, HasValue: true}},
+				{Predicate: model.Predicate{Operation: "less_than", Value: 150, HasValue: true}},
+			},
+		},
+		Captures: &model.Captures{
+			TTFB: []model.TTFBCapture{{Name: "ttfb_ms"}},
+		},
+	}
+	captures := map[string]CaptureValue{}
+
+	start := time.Now()
+	if _, err := newDefault().executeStep(context.Background(), step, captures, ""); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+	if total := time.Since(start); total < 180*time.Millisecond {
+		t.Fatalf("total duration = %s, expected body transfer delay to be included", total)
+	}
+
+	ttfb, ok := captures["ttfb_ms"].Value.(int64)
+	if !ok || ttfb < 30 || ttfb >= 150 {
+		t.Fatalf("ttfb_ms = %v, want between 30 and 150", captures["ttfb_ms"].Value)
+	}
+}
+
+func TestExecuteStepDuration(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	}))
+	t.Cleanup(server.Close)
+
+	durationStep := func(op string, value any) model.Step {
+		return model.Step{
+			Method: "GET",
+			URL:    server.URL,
+			Asserts: model.Asserts{
+				Duration: []model.DurationAssert{{Predicate: model.Predicate{Operation: op, Value: value, HasValue: true}}},
+			},
+			Captures: &model.Captures{
+				Duration: []model.DurationCapture{{Name: "duration_ms"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		step    model.Step
+		wantErr string
+	}{
+		{name: "body transfer is included", step: durationStep("greater_than_or_equal", "100ms")},
+		{name: "milliseconds", step: durationStep("less_than", 5000)},
+		{name: "regression", step: durationStep("less_than", "50ms"), wantErr: "duration assertion failed: expected less_than 50ms, got"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			captures := map[string]CaptureValue{}
+			_, err := newDefault().executeStep(context.Background(), tt.step, captures, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeStep() error = %v", err)
+			}
+			if duration, ok := captures["duration_ms"].Value.(int64); !ok || duration < 100 {
+				t.Fatalf("duration_ms = %v, want at least 100", captures["duration_ms"].Value)
+			}
+		})
+	}
+}
+
+func TestExecuteStepAttemptsAndRedirects(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T, failures int32) *httptest.Server {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/start":
+				http.Redirect(w, r, "/middle", http.StatusFound)
+			case "/middle":
+				http.Redirect(w, r, "/end", http.StatusFound)
+			default:
+				if calls.Add(1) <= failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	countPredicate := func(value int) model.Predicate {
+		return model.Predicate{Operation: "equals", Value: value, HasValue: true}
+	}
+
+	tests := []struct {
+		name      string
+		failures  int32
+		path      string
+		asserts   model.Asserts
+		wantError string
+	}{
+		{
+			name: "first attempt",
+			path: "/end",
+			asserts: model.Asserts{
+				Attempts:  []model.AttemptsAssert{{Predicate: countPredicate(1)}},
+				Redirects: []model.RedirectsAssert{{Predicate: countPredicate(0)}},
+			},
+		},
+		{
+			name:     "retries are counted",
+			failures: 2,
+			path:     "/end",
+			asserts: model.Asserts{
+				Status:   []model.StatusAssert{{Predicate: countPredicate(200)}},
+				Attempts: []model.AttemptsAssert{{Predicate: countPredicate(3)}},
+			},
+		},
+		{
+			name:     "hidden retries fail the assert",
+			failures: 1,
+			path:     "/end",
+			asserts: model.Asserts{
+				Status:   []model.StatusAssert{{Predicate: countPredicate(200)}},
+				Attempts: []model.AttemptsAssert{{Predicate: countPredicate(1)}},
+			},
+			wantError: "attempts assertion failed: expected equals 1, got 4",
+		},
+		{
+			name: "redirect hops",
+			path: "/start",
+			asserts: model.Asserts{
+				Redirects: []model.RedirectsAssert{{Predicate: countPredicate(2)}},
+			},
+		},
+		{
+			name: "redirect budget exceeded",
+			path: "/start",
+			asserts: model.Asserts{
+				Redirects: []model.RedirectsAssert{{Predicate: model.Predicate{Operation: "less_than", Value: 2, HasValue: true}}},
+			},
+			wantError: "redirects assertion failed: expected less_than 2, got 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newServer(t, tt.failures)
+			step := model.Step{
+				Method:  "GET",
+				URL:     server.URL + tt.path,
+				Asserts: tt.asserts,
+				Options: model.Options{Retries: 3},
+			}
+
+			_, err := newDefault().executeStep(context.Background(), step, map[string]CaptureValue{}, "")
+			if tt.wantError == "" {
+				if err != nil {
+					t.Fatalf("executeStep() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Fatalf("executeStep() error = %v, want %q", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExecuteStepTypedCaptures(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "42")
+		w.Header().Set("X-Empty", "")
+		w.Write([]byte(`{"ratio":"0.5","enabled":"true"}`))
+	}))
+	defer server.Close()
+
+	step := model.Step{
+		Method: "GET",
+		URL:    server.URL,
+		Captures: &model.Captures{
+			Headers: []model.HeaderCapture{
+				{Name: "total", HeaderName: "X-Total-Count", Type: model.CaptureTypeInt},
+				{Name: "empty", HeaderName: "X-Empty", Type: model.CaptureTypeString},
+			},
+			JSONPath: []model.JSONPathCapture{{Name: "ratio", Path: "$.ratio", Type: model.CaptureTypeFloat}},
+			Regex:    []model.RegexCapture{{Name: "enabled", Pattern: `"enabled":"(\w+)"`, Group: 1, Type: model.CaptureTypeBool}},
+			Body:     []model.BodyCapture{{Name: "payload", Type: model.CaptureTypeJSON}},
+		},
+	}
+	captures := map[string]CaptureValue{}
+
+	if _, err := newDefault().executeStep(context.Background(), step, captures, ""); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+
+	want := map[string]any{
+		"total":   42,
+		"empty":   "",
+		"ratio":   0.5,
+		"enabled": true,
+		"payload": map[string]any{"ratio": "0.5", "enabled": "true"},
+	}
+	for name, value := range want {
+		if !reflect.DeepEqual(captures[name].Value, value) {
+			t.Errorf("%s = %#v, want %#v", name, captures[name].Value, value)
+		}
+	}
+}
+
+func TestExecuteStepXPathCaptures(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<?xml version="1.0"?><orders><order id="o-1"><total>12.5</total></order><order id="o-2"><total>3</total></order></orders>`))
+	}))
+	defer server.Close()
+
+	step := model.Step{
+		Method: "GET",
+		URL:    server.URL,
+		Captures: &model.Captures{
+			XPath: []model.XPathCapture{
+				{Name: "first_id", Path: "//order[1]/@id"},
+				{Name: "second_total", Path: "//order[@id='o-2']/total", Type: model.CaptureTypeInt},
+				{Name: "orders", Path: "count(//order)"},
+				{Name: "missing", Path: "//customer"},
+			},
+		},
+	}
+	captures := map[string]CaptureValue{}
+
+	if _, err := newDefault().executeStep(context.Background(), step, captures, ""); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+
+	want := map[string]any{
+		"first_id":     "o-1",
+		"second_total": 3,
+		"orders":       2,
+		"missing":      nil,
+	}
+	for name, value := range want {
+		if !reflect.DeepEqual(captures[name].Value, value) {
+			t.Errorf("%s = %#v, want %#v", name, captures[name].Value, value)
+		}
+	}
+}
+
+func TestExecuteStepCSSAssertsAndCaptures(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body>
+<div id="cart"><span class="price">
+  12.50
+</span></div>
+<form><input name="csrf" value="tok-1"></form>
+</body></html>`))
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name    string
+		asserts []model.CSSAssert
+		wantErr string
+	}{
+		{
+			name: "passing",
+			asserts: []model.CSSAssert{
+				{Selector: "#cart .price", Predicate: model.Predicate{Operation: "equals", Value: "12.50"}},
+				{Selector: "input[name=csrf]", Attr: "value", Predicate: model.Predicate{Operation: "starts_with", Value: "tok-"}},
+				{Selector: "#cart", Predicate: model.Predicate{Operation: "exists"}},
+			},
+		},
+		{
+			name: "missing_element",
+			asserts: []model.CSSAssert{
+				{Selector: ".missing", Predicate: model.Predicate{Operation: "exists"}},
+			},
+			wantErr: "css assertion failed for .missing: expected exists",
+		},
+		{
+			name: "failing_predicate",
+			asserts: []model.CSSAssert{
+				{Selector: ".price", Predicate: model.Predicate{Operation: "contains", Value: "99"}},
+			},
+			wantErr: "css assertion failed for .price",
+		},
+		{
+			name: "no_match",
+			asserts: []model.CSSAssert{
+				{Selector: ".missing", Predicate: model.Predicate{Operation: "equals", Value: "x"}},
+			},
+			wantErr: "selector matched no element",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			step := model.Step{
+				Method:  "GET",
+				URL:     server.URL,
+				Asserts: model.Asserts{CSS: tt.asserts},
+				Captures: &model.Captures{
+					CSS: []model.CSSCapture{
+						{Name: "csrf", Selector: "input[name=csrf]", Attr: "value"},
+						{Name: "price", Selector: ".price", Type: model.CaptureTypeFloat},
+					},
+				},
+			}
+			captures := map[string]CaptureValue{}
+
+			_, err := newDefault().executeStep(context.Background(), step, captures, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeStep() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeStep() error = %v", err)
+			}
+			if got := captures["csrf"].Value; got != "tok-1" {
+				t.Errorf("csrf = %#v, want tok-1", got)
+			}
+			if got := captures["price"].Value; got != 12.5 {
+				t.Errorf("price = %#v, want 12.5", got)
+			}
+		})
+	}
+}
+
+func TestExecuteStepStructuredHeaderCaptures(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "3" {
+			w.Header().Set("Link", `</items?page=3>; rel="next", </items?page=9>; rel="last"`)
+		}
+		w.Header().Add("Vary", "Accept, Accept-Encoding")
+		w.Header().Add("Vary", "Origin")
+	}))
+	t.Cleanup(server.Close)
+
+	captures := map[string]CaptureValue{}
+	step := model.Step{
+		Method: "GET",
+		URL:    server.URL + "/items",
+		Captures: &model.Captures{
+			Headers: []model.HeaderCapture{
+				{Name: "links", HeaderName: "Link", Parse: model.HeaderParseLink},
+				{Name: "vary", HeaderName: "Vary", Parse: model.HeaderParseList},
+			},
+		},
+	}
+	if _, err := newDefault().executeStep(context.Background(), step, captures, ""); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+
+	wantLinks := map[string]any{
+		"next": server.URL + "/items?page=3",
+		"last": server.URL + "/items?page=9",
+	}
+	if got := captures["links"].Value; !reflect.DeepEqual(got, wantLinks) {
+		t.Errorf("links = %#v, want %#v", got, wantLinks)
+	}
+	if got, want := captures["vary"].Value, []any{"Accept", "Accept-Encoding", "Origin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("vary = %#v, want %#v", got, want)
+	}
+
+	next := model.Step{
+		Method: "GET",
+		URL:    "{{.links.next}}",
+		Captures: &model.Captures{
+			Headers: []model.HeaderCapture{{Name: "links", HeaderName: "Link", Parse: model.HeaderParseLink}},
+		},
+	}
+	if _, err := newDefault().executeStep(context.Background(), next, captures, ""); err != nil {
+		t.Fatalf("executeStep(next) error = %v", err)
+	}
+	if got := captures["links"].Value; !reflect.DeepEqual(got, map[string]any{}) {
+		t.Errorf("links on the last page = %#v, want an empty map", got)
+	}
+}
+
+func TestExecuteStepExpectContinueAndRange(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/upload":
+			io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusCreated)
+		case "/reject":
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		case "/object":
+			http.ServeContent(w, r, "object", time.Time{}, strings.NewReader("0123456789"))
+		case "/whole":
+			w.Write([]byte("0123456789"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	rangeAssert := true
+	continued := func(value bool) []model.ContinueAssert {
+		return []model.ContinueAssert{{Predicate: model.Predicate{Operation: "equals", Value: value, HasValue: true}}}
+	}
+	tests := []struct {
+		name    string
+		step    model.Step
+		wantErr string
+	}{
+		{
+			name: "server_sends_continue",
+			step: model.Step{
+				Method:  "PUT",
+				URL:     server.URL + "/upload",
+				Body:    model.TextBody("payload"),
+				Options: model.Options{ExpectContinue: true},
+				Asserts: model.Asserts{Continue: continued(true)},
+			},
+		},
+		{
+			name: "server_rejects_before_body",
+			step: model.Step{
+				Method:  "PUT",
+				URL:     server.URL + "/reject",
+				Body:    model.TextBody("payload"),
+				Options: model.Options{ExpectContinue: true},
+				Asserts: model.Asserts{Continue: continued(true)},
+			},
+			wantErr: "continue assertion failed: expected equals true, got false",
+		},
+		{
+			name: "range_honored",
+			step: model.Step{
+				Method:  "GET",
+				URL:     server.URL + "/object",
+				Headers: model.KeyValues{{Key: "Range", Value: "bytes=4-"}},
+				Asserts: model.Asserts{Range: &rangeAssert},
+			},
+		},
+		{
+			name: "range_ignored",
+			step: model.Step{
+				Method:  "GET",
+				URL:     server.URL + "/whole",
+				Headers: model.KeyValues{{Key: "Range", Value: "bytes=4-"}},
+				Asserts: model.Asserts{Range: &rangeAssert},
+			},
+			wantErr: "range assertion failed: expected status 206, got 200",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newDefault().executeStep(context.Background(), tt.step, map[string]CaptureValue{}, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("executeStep() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("executeStep() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package execute
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// exportedCaptures selects the captures of a file for --export-captures. When
+// steps list exports, only those captures are written; otherwise every
+// capture the file made is, except redacted ones and configured variables.
+func exportedCaptures(steps []model.Step, captures map[string]CaptureValue, variables map[string]any) map[string]any {
+	exported := make(map[string]any)
+
+	var whitelist []string
+	for _, step := range steps {
+		whitelist = append(whitelist, step.Exports...)
+	}
+
+	if len(whitelist) > 0 {
+		for _, name := range whitelist {
+			if capture, ok := captures[name]; ok {
+				exported[name] = capture.Value
+			}
+		}
+		return exported
+	}
+
+	for name, capture := range captures {
+		if _, configured := variables[name]; configured || capture.Redact {
+			continue
+		}
+		exported[name] = capture.Value
+	}
+
+	return exported
+}
+
+// writeExports saves the captures of the last iteration to the
+// --export-captures file, as a JSON object when the file ends in .json and as
+// KEY=value lines otherwise. Files are merged in order, so a later file wins
+// when two capture the same name.
+func (r *Runner) writeExports() error {
+	if r.config == nil || r.config.ExportCapturesPath == "" || len(r.reported) == 0 {
+		return nil
+	}
+
+	exports := make(map[string]any)
+	for _, file := range r.reported[len(r.reported)-1].FileResults {
+		maps.Copy(exports, file.Exports)
+	}
+
+	if strings.EqualFold(filepath.Ext(r.config.ExportCapturesPath), ".json") {
+		data, err := json.MarshalIndent(exports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("export captures: %w", err)
+		}
+		return os.WriteFile(r.config.ExportCapturesPath, append(data, '\n'), 0o600)
+	}
+
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(exports)) {
+		value, err := exportValue(exports[name])
+		if err != nil {
+			return fmt.Errorf("export %s: %w", name, err)
+		}
+		fmt.Fprintf(&b, "%s=%s\n", name, value)
+	}
+
+	return os.WriteFile(r.config.ExportCapturesPath, []byte(b.String()), 0o600)
+}
+
+// exportValue renders value for a KEY=value line that a POSIX shell can
+// source. Maps and lists are written as JSON; values with characters the shell
+// would interpret are single-quoted.
+func exportValue(value any) (string, error) {
+	text, err := captureText(value)
+	if err != nil {
+		return "", err
+	}
+
+	if text != "" && strings.Trim(text, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-.,:/@%+=") == "" {
+		return text, nil
+	}
+
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'", nil
+}
+
+// captureText renders a captured value as text: strings as they are, maps
+// and lists as JSON, and an unset capture as an empty string.
+func captureText(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
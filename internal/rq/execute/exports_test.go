@@ -0,0 +1,36 @@
+package execute
+
+import "testing"
+
+func TestExportValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "plain", value: "order-1", want: "order-1"},
+		{name: "url", value: "https://api.example.com/a?b=c", want: "'https://api.example.com/a?b=c'"},
+		{name: "number", value: 42, want: "42"},
+		{name: "empty", value: "", want: "''"},
+		{name: "spaces", value: "two words", want: "'two words'"},
+		{name: "quote", value: "it's", want: `'it'\''s'`},
+		{name: "list", value: []any{"a", 1.0}, want: `'["a",1]'`},
+		{name: "map", value: map[string]any{"k": true}, want: `'{"k":true}'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := exportValue(tt.value)
+			if err != nil {
+				t.Fatalf("exportValue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("exportValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
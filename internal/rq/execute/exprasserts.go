@@ -0,0 +1,53 @@
+package execute
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/expr"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// checkExprAsserts evaluates expr asserts against the response. Expressions
+// see status, headers (lowercased names, values joined with ", "), json
+// (the parsed body, or null when it is not JSON), body and vars (captures).
+// JSON numbers keep their literal so large integer ids compare exactly.
+func checkExprAsserts(expressions []string, resp *http.Response, body []byte, selectors selectorContext, options model.Options, captures map[string]CaptureValue) error {
+	if len(expressions) == 0 {
+		return nil
+	}
+
+	variables := exprVariables(resp, body, selectors, options, captures)
+	for _, expression := range expressions {
+		ok, err := expr.Eval(expression, variables)
+		if err != nil {
+			return fmt.Errorf("expr assertion error: %s: %w", expression, err)
+		}
+		if !ok {
+			return fmt.Errorf("expr assertion failed: %s evaluated to false", expression)
+		}
+	}
+
+	return nil
+}
+
+func exprVariables(resp *http.Response, body []byte, selectors selectorContext, options model.Options, captures map[string]CaptureValue) map[string]any {
+	headers := make(map[string]any, len(resp.Header))
+	for name, values := range resp.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+
+	var data any
+	if selectors.err == nil && selectors.file == "" {
+		data, _ = parseJSONNumbers(body, options)
+	}
+
+	return map[string]any{
+		"status":  resp.StatusCode,
+		"headers": headers,
+		"json":    data,
+		"body":    string(body),
+		"vars":    captureMapForTemplate(captures),
+	}
+}
@@ -0,0 +1,61 @@
+package execute
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestCheckExprAsserts(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"id":9007199254740993,"items":[{"price":10},{"price":2.5}],"owner":"alice"}`)
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}, "X-Tag": {"a", "b"}},
+	}
+	captures := map[string]CaptureValue{"owner": {Value: "alice"}}
+
+	tests := []struct {
+		name    string
+		expr    string
+		body    []byte
+		options model.Options
+		wantErr string
+	}{
+		{name: "json_macro", expr: "json.items.all(i, i.price > 0)"},
+		{name: "status_and_headers", expr: "status == 200 && headers['x-tag'] == 'a, b'"},
+		{name: "captures", expr: "json.owner == vars.owner"},
+		{name: "integer_beyond_float_precision", expr: "json.id == 9007199254740993 && json.id != 9007199254740992"},
+		{name: "json_lines", expr: "json[1].id == 9007199254740993", body: []byte("{\"id\":1}\n{\"id\":9007199254740993}\n"), options: model.Options{JSONLines: true}},
+		{name: "raw_body", expr: "body.contains('alice')"},
+		{name: "non_json_body", expr: "json == null && body == 'plain'", body: []byte("plain")},
+		{name: "false", expr: "size(json.items) > 2", wantErr: "expr assertion failed: size(json.items) > 2 evaluated to false"},
+		{name: "evaluation_error", expr: "json.missing > 0", wantErr: "expr assertion error: json.missing > 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			respBody := body
+			if tt.body != nil {
+				respBody = tt.body
+			}
+			selectors := selectorContextFromBody(respBody, true, tt.options)
+
+			err := checkExprAsserts([]string{tt.expr}, resp, respBody, selectors, tt.options, captures)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkExprAsserts() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkExprAsserts() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// executeForeach runs the step once per foreach element with item and
+// item_index set, stopping at the first element that fails. The when
+// condition is evaluated for each element, and cleanups are registered per
+// element so they see the element they were created for. Any item and
+// item_index values set before the loop are restored afterwards.
+func (r *Runner) executeForeach(ctx context.Context, step model.Step, captures map[string]CaptureValue, stepBaseDir string) (bool, error) {
+	items, redact, err := foreachItems(step.Foreach, captures)
+	if err != nil {
+		return false, err
+	}
+
+	previousItem, hadItem := captures[model.ForeachItem]
+	previousIndex, hadIndex := captures[model.ForeachIndex]
+	defer func() {
+		restoreCapture(captures, model.ForeachItem, previousItem, hadItem)
+		restoreCapture(captures, model.ForeachIndex, previousIndex, hadIndex)
+	}()
+
+	single := step
+	single.Foreach = nil
+
+	requestMade := false
+	for i, item := range items {
+		captures[model.ForeachItem] = CaptureValue{Value: item, Redact: redact}
+		captures[model.ForeachIndex] = CaptureValue{Value: i}
+
+		made, err := r.executeStep(ctx, single, captures, stepBaseDir)
+		requestMade = requestMade || made
+		if err != nil {
+			return requestMade, fmt.Errorf("foreach item %d: %w", i, err)
+		}
+		if made {
+			r.registerCleanups(ctx, single, captures, stepBaseDir)
+		}
+	}
+
+	return requestMade, nil
+}
+
+// foreachItems returns the elements to iterate and whether they come from a
+// redacted capture.
+func foreachItems(foreach *model.Foreach, captures map[string]CaptureValue) ([]any, bool, error) {
+	if foreach.From == "" {
+		return foreach.Items, false, nil
+	}
+
+	source, ok := captures[foreach.From]
+	if !ok {
+		return nil, false, fmt.Errorf("foreach: %s is not defined", foreach.From)
+	}
+	if items, ok := source.Value.([]any); ok {
+		return items, source.Redact, nil
+	}
+
+	value := reflect.ValueOf(source.Value)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, false, fmt.Errorf("foreach: %s is %T, not a list", foreach.From, source.Value)
+	}
+	items := make([]any, value.Len())
+	for i := range items {
+		items[i] = value.Index(i).Interface()
+	}
+
+	return items, source.Redact, nil
+}
+
+func restoreCapture(captures map[string]CaptureValue, name string, value CaptureValue, found bool) {
+	if found {
+		captures[name] = value
+		return
+	}
+	delete(captures, name)
+}
@@ -0,0 +1,119 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepForeach(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		foreach   model.Foreach
+		captures  map[string]CaptureValue
+		when      string
+		wantPaths []string
+		wantErr   string
+	}{
+		{
+			name:      "list literal",
+			foreach:   model.Foreach{Items: []any{"eu", "us"}},
+			wantPaths: []string{"/regions/eu/0", "/regions/us/1"},
+		},
+		{
+			name:      "captured array",
+			foreach:   model.Foreach{From: "regions"},
+			captures:  map[string]CaptureValue{"regions": {Value: []any{"ap", "eu"}}},
+			wantPaths: []string{"/regions/ap/0", "/regions/eu/1"},
+		},
+		{
+			name:      "typed slice",
+			foreach:   model.Foreach{From: "regions"},
+			captures:  map[string]CaptureValue{"regions": {Value: []string{"us"}}},
+			wantPaths: []string{"/regions/us/0"},
+		},
+		{
+			name:      "when evaluated per item",
+			foreach:   model.Foreach{Items: []any{"eu", "us", "ap"}},
+			when:      `item != "us"`,
+			wantPaths: []string{"/regions/eu/0", "/regions/ap/2"},
+		},
+		{
+			name:      "failing item stops the loop",
+			foreach:   model.Foreach{Items: []any{"eu", "fail", "us"}},
+			wantPaths: []string{"/regions/eu/0", "/regions/fail/1"},
+			wantErr:   "foreach item 1:",
+		},
+		{
+			name:    "undefined variable",
+			foreach: model.Foreach{From: "regions"},
+			wantErr: "foreach: regions is not defined",
+		},
+		{
+			name:     "not a list",
+			foreach:  model.Foreach{From: "regions"},
+			captures: map[string]CaptureValue{"regions": {Value: "eu"}},
+			wantErr:  "foreach: regions is string, not a list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu    sync.Mutex
+				paths []string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				paths = append(paths, r.URL.Path)
+				mu.Unlock()
+				if strings.Contains(r.URL.Path, "fail") {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			captures := map[string]CaptureValue{"item": {Value: "outer"}}
+			for name, value := range tt.captures {
+				captures[name] = value
+			}
+			foreach := tt.foreach
+			step := model.Step{
+				Method:  "GET",
+				URL:     server.URL + "/regions/{{.item}}/{{.item_index}}",
+				When:    tt.when,
+				Foreach: &foreach,
+				Asserts: model.Asserts{
+					Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200, HasValue: true}}},
+				},
+			}
+
+			_, err := newDefault().executeStep(context.Background(), step, captures, "")
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("executeStep() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if !slices.Equal(paths, tt.wantPaths) {
+				t.Errorf("requested paths = %v, want %v", paths, tt.wantPaths)
+			}
+			if got := captures["item"].Value; got != "outer" {
+				t.Errorf("item after loop = %v, want outer restored", got)
+			}
+			if _, found := captures["item_index"]; found {
+				t.Error("item_index is still set after the loop")
+			}
+		})
+	}
+}
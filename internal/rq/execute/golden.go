@@ -0,0 +1,40 @@
+package execute
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/jacoelho/rq/internal/pathing"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// checkGoldenFiles compares the response body with each golden file. When both
+// sides are JSON they are compared as values, so key order and whitespace do
+// not matter; otherwise the bytes must match, ignoring trailing newlines.
+func checkGoldenFiles(asserts []model.GoldenAssert, body []byte, baseDir string) error {
+	for _, current := range asserts {
+		filename := pathing.ResolveBodyFilePath(current.File, baseDir)
+		expected, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("golden assertion error: %w", err)
+		}
+
+		if !goldenEqual(expected, body) {
+			return fmt.Errorf("golden assertion failed: response body does not match %s", current.File)
+		}
+	}
+
+	return nil
+}
+
+func goldenEqual(expected, actual []byte) bool {
+	var expectedValue, actualValue any
+	if json.Unmarshal(expected, &expectedValue) == nil && json.Unmarshal(actual, &actualValue) == nil {
+		return reflect.DeepEqual(expectedValue, actualValue)
+	}
+
+	return bytes.Equal(bytes.TrimRight(expected, "\r\n"), bytes.TrimRight(actual, "\r\n"))
+}
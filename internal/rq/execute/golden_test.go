@@ -0,0 +1,55 @@
+package execute
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestCheckGoldenFiles(t *testing.T) {
+	t.Parallel()
+
+	baseDir := t.TempDir()
+	files := map[string]string{
+		"user.golden.json": "{\n  \"name\": \"rq\",\n  \"tags\": [\"a\", \"b\"]\n}\n",
+		"plain.golden.txt": "pong\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(baseDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		file      string
+		body      string
+		wantError string
+	}{
+		{name: "json ignores key order and whitespace", file: "user.golden.json", body: `{"tags":["a","b"],"name":"rq"}`},
+		{name: "json value mismatch", file: "user.golden.json", body: `{"tags":["b","a"],"name":"rq"}`, wantError: "does not match user.golden.json"},
+		{name: "text ignores trailing newline", file: "plain.golden.txt", body: "pong"},
+		{name: "text mismatch", file: "plain.golden.txt", body: "ping", wantError: "golden assertion failed"},
+		{name: "missing file", file: "missing.json", body: "{}", wantError: "golden assertion error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkGoldenFiles([]model.GoldenAssert{{File: tt.file}}, []byte(tt.body), baseDir)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Fatalf("checkGoldenFiles() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Fatalf("checkGoldenFiles() error = %v, want %q", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package execute
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepGraphQL(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query         string         `json:"query"`
+			OperationName string         `json:"operationName"`
+			Variables     map[string]any `json:"variables"`
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if request.Variables["id"] != "u-1" {
+			w.Write([]byte(`{"data":{"user":null},"errors":[{"message":"user not found","extensions":{"code":"NOT_FOUND"}}]}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"user":{"name":"Alice","operation":"` + request.OperationName + `"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	equals := func(path string, value any) model.JSONPathAssert {
+		return model.JSONPathAssert{Path: path, Predicate: model.Predicate{Operation: "equals", Value: value, HasValue: true}}
+	}
+
+	tests := []struct {
+		name    string
+		userID  string
+		asserts model.GraphQLAsserts
+		wantErr string
+	}{
+		{
+			name:   "data",
+			userID: "{{.user_id}}",
+			asserts: model.GraphQLAsserts{
+				NoErrors: true,
+				Data:     []model.JSONPathAssert{equals("$.user.name", "Alice"), equals("$.user.operation", "GetUser")},
+			},
+		},
+		{
+			name:   "expected_errors",
+			userID: "u-2",
+			asserts: model.GraphQLAsserts{
+				Errors: []model.JSONPathAssert{equals("$[0].extensions.code", "NOT_FOUND")},
+			},
+		},
+		{
+			name:    "unexpected_errors",
+			userID:  "u-2",
+			asserts: model.GraphQLAsserts{NoErrors: true},
+			wantErr: "graphql assertion failed: expected no errors, got 1: user not found",
+		},
+		{
+			name:    "data_mismatch",
+			userID:  "u-1",
+			asserts: model.GraphQLAsserts{Data: []model.JSONPathAssert{equals("$.user.name", "Bob")}},
+			wantErr: "JSONPath assertion failed for $.data.user.name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			step := model.Step{
+				Method: "POST",
+				URL:    server.URL,
+				GraphQL: &model.GraphQL{
+					Query:         "query GetUser($id: ID!) { user(id: $id) { name } }",
+					OperationName: "GetUser",
+					Variables:     map[string]any{"id": tt.userID},
+				},
+				Asserts: model.Asserts{GraphQL: &tt.asserts},
+			}
+			captures := map[string]CaptureValue{"user_id": {Value: "u-1"}}
+
+			_, err := newDefault().executeStep(context.Background(), step, captures, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("executeStep() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("executeStep() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderGraphQLBody(t *testing.T) {
+	t.Parallel()
+
+	body, err := renderGraphQLBody(model.GraphQL{
+		Query:     "{ viewer { login } }",
+		Variables: map[string]any{"filter": map[string]any{"owner": "{{.owner}}"}, "first": 10},
+	}, map[string]any{"owner": "octocat"})
+	if err != nil {
+		t.Fatalf("renderGraphQLBody() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	want := map[string]any{
+		"query":     "{ viewer { login } }",
+		"variables": map[string]any{"filter": map[string]any{"owner": "octocat"}, "first": float64(10)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %v, want %v", got, want)
+	}
+}
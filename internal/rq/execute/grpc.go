@@ -0,0 +1,196 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacoelho/rq/internal/pathing"
+	"github.com/jacoelho/rq/internal/rq/grpc"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// executeGRPC performs the unary call described by step.GRPC, sending the
+// JSON body of req as the request message. The response message, as JSON, is
+// returned as the body; trailers such as grpc-status are merged into the
+// response headers so header asserts and captures can read them.
+func (r *Runner) executeGRPC(ctx context.Context, step model.Step, req *http.Request, stepBaseDir string) (*http.Response, []byte, error) {
+	call := step.GRPC
+	expected, err := model.ParseGRPCCode(call.Status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	method, err := r.grpcMethod(*call, stepBaseDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var message []byte
+	if req.Body != nil {
+		message, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read grpc request message: %w", err)
+		}
+	}
+	frame, err := method.EncodeRequest(message)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	callURL := *req.URL
+	callURL.Path = strings.TrimSuffix(callURL.Path, "/") + method.Path()
+	callURL.RawPath = ""
+
+	callReq, err := http.NewRequestWithContext(ctx, http.MethodPost, callURL.String(), bytes.NewReader(frame))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create grpc request: %w", err)
+	}
+	callReq.Header = req.Header.Clone()
+	callReq.Header.Del("Content-Length")
+	callReq.Header.Set("Content-Type", grpc.ContentType)
+	callReq.Header.Set("TE", "trailers")
+
+	if err := r.rateLimiter.Wait(ctx, callReq.URL.Host); err != nil {
+		return nil, nil, fmt.Errorf("rate limiting interrupted: %w", err)
+	}
+
+	client, err := r.grpcClient(step.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := client.Do(withRequestTiming(callReq))
+	r.breaker.record(callReq.URL.Host, err != nil && ctx.Err() == nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("grpc request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyStart := time.Now()
+	respBody, err := readResponseBody(resp, r.maxResponseBytes())
+	r.traceRequestPhases(resp, bodyStart)
+	completeTiming(resp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("grpc request failed: server answered %s", resp.Status)
+	}
+
+	// Trailers are only complete once the body has been read. Servers that
+	// fail early send a trailers-only response with the status in the headers.
+	for name, values := range resp.Trailer {
+		resp.Header[name] = append(resp.Header[name], values...)
+	}
+
+	code, err := grpcStatus(resp.Header)
+	if err != nil {
+		return nil, nil, err
+	}
+	if code != expected {
+		return nil, nil, fmt.Errorf("grpc status %s (%d): %s, expected %s",
+			model.GRPCCodeName(code), code, resp.Header.Get("Grpc-Message"), model.GRPCCodeName(expected))
+	}
+	if code != 0 {
+		return resp, nil, nil
+	}
+
+	decoded, err := method.DecodeResponse(respBody)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, decoded, nil
+}
+
+func grpcStatus(header http.Header) (int, error) {
+	value := header.Get("Grpc-Status")
+	if value == "" {
+		return 0, errors.New("grpc response is missing grpc-status")
+	}
+
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid grpc-status %q", value)
+	}
+
+	return code, nil
+}
+
+// grpcMethod resolves the step's method, caching it per descriptor set so
+// the file is read once per run.
+func (r *Runner) grpcMethod(call model.GRPC, stepBaseDir string) (*grpc.Method, error) {
+	path := pathing.ResolveBodyFilePath(call.Protoset, stepBaseDir)
+	key := path + "|" + call.Service + "|" + call.Method
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if method, ok := r.grpcMethods[key]; ok {
+		return method, nil
+	}
+
+	files, err := grpc.LoadDescriptorSet(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load protoset: %w", err)
+	}
+	method, err := grpc.FindMethod(files, call.Service, call.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.grpcMethods == nil {
+		r.grpcMethods = make(map[string]*grpc.Method)
+	}
+	r.grpcMethods[key] = method
+
+	return method, nil
+}
+
+// grpcClient returns the step's client with a transport that only speaks
+// HTTP/2, using prior knowledge (h2c) for http:// URLs. Transports are cached
+// per base transport so connections are still reused.
+func (r *Runner) grpcClient(options model.Options) (*http.Client, error) {
+	client, err := r.getClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transport, ok := r.grpcTransports[client.Transport]
+	if !ok {
+		var base *http.Transport
+		switch t := client.Transport.(type) {
+		case nil:
+			base = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			base = t.Clone()
+		default:
+			return nil, fmt.Errorf("grpc steps require an *http.Transport, got %T", t)
+		}
+
+		base.Protocols = new(http.Protocols)
+		base.Protocols.SetHTTP2(true)
+		base.Protocols.SetUnencryptedHTTP2(true)
+
+		if r.grpcTransports == nil {
+			r.grpcTransports = make(map[http.RoundTripper]*http.Transport)
+		}
+		r.grpcTransports[client.Transport] = base
+		transport = base
+	}
+
+	grpcClient := *client
+	grpcClient.Transport = transport
+	return &grpcClient, nil
+}
@@ -0,0 +1,155 @@
+package execute
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/grpc"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// newGRPCServer serves orders.v1.Orders/UpdateOrder over h2c. It answers an
+// order for any id except "missing", which gets a trailers-only NOT_FOUND.
+func newGRPCServer(t *testing.T, method *grpc.Method) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 || r.URL.Path != method.Path() || r.Header.Get("Content-Type") != grpc.ContentType {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		message, err := method.DecodeResponse(body)
+		if err != nil {
+			t.Errorf("DecodeResponse() error = %v", err)
+			return
+		}
+		var order map[string]any
+		_ = json.Unmarshal(message, &order)
+
+		w.Header().Set("Content-Type", grpc.ContentType)
+		if order["id"] == "missing" {
+			w.Header().Set("Grpc-Status", "5")
+			w.Header().Set("Grpc-Message", "order not found")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		w.Header().Set("Trailer", "Grpc-Status, X-Region")
+		order["quantity"] = 3
+		reply, _ := json.Marshal(order)
+		frame, err := method.EncodeRequest(reply)
+		if err != nil {
+			t.Errorf("EncodeRequest() error = %v", err)
+			return
+		}
+		_, _ = w.Write(frame)
+		w.Header().Set("Grpc-Status", "0")
+		w.Header().Set("X-Region", "eu")
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestExecuteStepGRPC(t *testing.T) {
+	t.Parallel()
+
+	protoset := filepath.Join("..", "grpc", "testdata", "orders.protoset")
+	files, err := grpc.LoadDescriptorSet(protoset)
+	if err != nil {
+		t.Fatalf("LoadDescriptorSet() error = %v", err)
+	}
+	method, err := grpc.FindMethod(files, "orders.v1.Orders", "UpdateOrder")
+	if err != nil {
+		t.Fatalf("FindMethod() error = %v", err)
+	}
+	server := newGRPCServer(t, method)
+
+	call := func(id, status string) model.Step {
+		return model.Step{
+			Method: "POST",
+			URL:    server.URL,
+			Body:   model.Body{Text: `{"id": "` + id + `"}`},
+			GRPC: &model.GRPC{
+				Service:  "orders.v1.Orders",
+				Method:   "UpdateOrder",
+				Protoset: filepath.Base(protoset),
+				Status:   status,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		step    model.Step
+		wantErr string
+	}{
+		{
+			name: "response message and trailers are asserted",
+			step: func() model.Step {
+				step := call("{{.id}}", "")
+				step.Asserts = model.Asserts{
+					Headers: []model.HeaderAssert{{
+						Name:      "x-region",
+						Predicate: model.Predicate{Operation: "equals", Value: "eu", HasValue: true},
+					}},
+					JSONPath: []model.JSONPathAssert{{
+						Path:      "$.quantity",
+						Predicate: model.Predicate{Operation: "equals", Value: 3, HasValue: true},
+					}},
+				}
+				step.Captures = &model.Captures{
+					JSONPath: []model.JSONPathCapture{{Name: "order_id", Path: "$.id"}},
+				}
+				return step
+			}(),
+		},
+		{
+			name: "expected error status",
+			step: call("missing", "NOT_FOUND"),
+		},
+		{
+			name:    "unexpected error status",
+			step:    call("missing", ""),
+			wantErr: "grpc status NOT_FOUND (5): order not found, expected OK",
+		},
+		{
+			name:    "invalid request message",
+			step:    model.Step{Method: "POST", URL: server.URL, Body: model.Body{Text: `{"sku": 1}`}, GRPC: call("", "").GRPC},
+			wantErr: "invalid orders.v1.Order message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			captures := map[string]CaptureValue{"id": {Value: "42"}}
+			_, err := newDefault().executeStep(context.Background(), tt.step, captures, filepath.Dir(protoset))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeStep() error = %v", err)
+			}
+			if tt.step.Captures != nil && captures["order_id"].Value != "42" {
+				t.Fatalf("order_id = %v, want 42", captures["order_id"].Value)
+			}
+		})
+	}
+}
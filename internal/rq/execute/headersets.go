@@ -0,0 +1,91 @@
+package execute
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// runHeaderSet checks the members of the list header name, such as
+// Content-Language or Vary. Members are compared case-insensitively, and a
+// missing header is the empty set.
+func (r assertionRunner) runHeaderSet(name string, asserts []model.HeaderSetAssert) error {
+	if len(asserts) == 0 {
+		return nil
+	}
+
+	members := model.HeaderSetMembers(r.resp.Header.Values(name))
+	for _, current := range asserts {
+		if err := checkHeaderSet(current.Predicate, members); err != nil {
+			return fmt.Errorf("%s assertion failed: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func checkHeaderSet(p model.Predicate, members []string) error {
+	want := headerSetValues(p.Value)
+
+	switch p.Operation {
+	case model.SetOpContains:
+		if missing := setDifference(want, members); len(missing) > 0 {
+			return fmt.Errorf("expected %s to contain %s", formatSet(members), strings.Join(missing, ", "))
+		}
+	case model.SetOpNotContains:
+		for _, value := range want {
+			if setHas(members, value) {
+				return fmt.Errorf("expected %s not to contain %s", formatSet(members), value)
+			}
+		}
+	case model.SetOpEquals:
+		if len(setDifference(want, members)) > 0 || len(setDifference(members, want)) > 0 {
+			return fmt.Errorf("expected %s, got %s", formatSet(want), formatSet(members))
+		}
+	case model.SetOpSubsetOf:
+		if extra := setDifference(members, want); len(extra) > 0 {
+			return fmt.Errorf("expected %s to be a subset of %s, got extra %s", formatSet(members), formatSet(want), strings.Join(extra, ", "))
+		}
+	default:
+		return fmt.Errorf("unsupported operation: %s", p.Operation)
+	}
+
+	return nil
+}
+
+// headerSetValues returns the expected members: a single value or a list.
+func headerSetValues(value any) []string {
+	list, ok := value.([]any)
+	if !ok {
+		return []string{fmt.Sprint(value)}
+	}
+
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		values = append(values, fmt.Sprint(item))
+	}
+	return values
+}
+
+// setDifference returns the members of a missing from b.
+func setDifference(a []string, b []string) []string {
+	var missing []string
+	for _, value := range a {
+		if !setHas(b, value) {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+func setHas(members []string, value string) bool {
+	return slices.ContainsFunc(members, func(member string) bool {
+		return strings.EqualFold(member, value)
+	})
+}
+
+func formatSet(members []string) string {
+	return "{" + strings.Join(members, ", ") + "}"
+}
@@ -0,0 +1,48 @@
+package execute
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestCheckHeaderSet(t *testing.T) {
+	t.Parallel()
+
+	members := model.HeaderSetMembers([]string{"Accept-Encoding, Accept-Language", "Origin"})
+
+	tests := []struct {
+		name    string
+		op      string
+		value   any
+		wantErr string
+	}{
+		{name: "contains_single", op: model.SetOpContains, value: "accept-language"},
+		{name: "contains_list", op: model.SetOpContains, value: []any{"Origin", "Accept-Encoding"}},
+		{name: "contains_missing", op: model.SetOpContains, value: []any{"Origin", "Cookie"}, wantErr: "expected {Accept-Encoding, Accept-Language, Origin} to contain Cookie"},
+		{name: "not_contains", op: model.SetOpNotContains, value: "Cookie"},
+		{name: "not_contains_present", op: model.SetOpNotContains, value: "ORIGIN", wantErr: "not to contain ORIGIN"},
+		{name: "equals_any_order", op: model.SetOpEquals, value: []any{"origin", "Accept-Language", "Accept-Encoding"}},
+		{name: "equals_extra_member", op: model.SetOpEquals, value: []any{"Origin"}, wantErr: "expected {Origin}, got {Accept-Encoding, Accept-Language, Origin}"},
+		{name: "subset_of", op: model.SetOpSubsetOf, value: []any{"Accept-Encoding", "Accept-Language", "Origin", "Cookie"}},
+		{name: "subset_of_extra", op: model.SetOpSubsetOf, value: []any{"Accept-Encoding", "Accept-Language"}, wantErr: "got extra Origin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkHeaderSet(model.Predicate{Operation: tt.op, Value: tt.value, HasValue: true}, members)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkHeaderSet() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkHeaderSet() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
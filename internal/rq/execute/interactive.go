@@ -0,0 +1,212 @@
+package execute
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
+)
+
+// interactiveStep is a step of the session with the result of its last run,
+// kept as the message key of the status so list prints it in the session
+// language.
+type interactiveStep struct {
+	file   CompiledFile
+	index  int
+	status i18n.Key
+}
+
+// interactiveSession holds the steps of every test file in order and the
+// variables they share. Unlike a normal run, captures carry across files so a
+// session can be driven from a login file into the files that depend on it.
+type interactiveSession struct {
+	steps    []interactiveStep
+	captures map[string]CaptureValue
+	next     int
+}
+
+func (r *Runner) runInteractive(ctx context.Context) int {
+	if r.compiled == nil {
+		compiled, err := compileFiles(r.config.TestFiles)
+		if err != nil {
+			r.printf(i18n.Error, err)
+			return 1
+		}
+		r.compiled = compiled
+	}
+
+	session := newInteractiveSession(r.compiled, r.variables)
+	exitCode := r.serveInteractive(withValidators(withCookieJar(ctx, newCookieJar())), session, r.inputReader())
+	r.runCleanups(ctx)
+	return exitCode
+}
+
+func newInteractiveSession(files []CompiledFile, variables map[string]any) *interactiveSession {
+	session := &interactiveSession{captures: initializeCaptures(variables)}
+	for _, file := range files {
+		for i := range file.Steps {
+			session.steps = append(session.steps, interactiveStep{file: file, index: i, status: i18n.InteractivePending})
+		}
+	}
+
+	return session
+}
+
+// serveInteractive reads commands from in until quit, end of input or
+// cancellation. The exit code is 1 when the last run of any step failed.
+func (r *Runner) serveInteractive(ctx context.Context, session *interactiveSession, in io.Reader) int {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	w := r.payloadWriter()
+	p := r.printer
+	p.Fprintln(w, i18n.InteractiveLoaded, len(session.steps))
+
+	for {
+		p.Fprintf(w, i18n.InteractivePrompt)
+
+		var line string
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(w)
+			return session.exitCode()
+		case next, ok := <-lines:
+			if !ok {
+				fmt.Fprintln(w)
+				return session.exitCode()
+			}
+			line = next
+		}
+
+		command, argument, _ := strings.Cut(strings.TrimSpace(line), " ")
+		argument = strings.TrimSpace(argument)
+
+		switch command {
+		case "", "next", "n":
+			if session.next >= len(session.steps) {
+				p.Fprintln(w, i18n.InteractiveNoMoreSteps)
+				continue
+			}
+			r.runInteractiveStep(ctx, session, session.next)
+		case "run", "r":
+			index, err := strconv.Atoi(argument)
+			if err != nil || index < 0 || index >= len(session.steps) {
+				p.Fprintln(w, i18n.InteractiveInvalidStep, argument, len(session.steps)-1)
+				continue
+			}
+			r.runInteractiveStep(ctx, session, index)
+		case "continue", "c":
+			for session.next < len(session.steps) && ctx.Err() == nil {
+				if !r.runInteractiveStep(ctx, session, session.next) {
+					break
+				}
+			}
+		case "list", "l":
+			session.list(w, p)
+		case "vars", "v":
+			r.printInteractiveVariables(w, session.captures)
+		case "set":
+			name, value, ok := strings.Cut(argument, "=")
+			name = strings.TrimSpace(name)
+			if !ok || name == "" {
+				p.Fprintln(w, i18n.InteractiveSetUsage)
+				continue
+			}
+			session.captures[name] = CaptureValue{Value: value}
+		case "help", "h":
+			fmt.Fprint(w, p.Text(i18n.InteractiveHelp))
+		case "quit", "q", "exit":
+			return session.exitCode()
+		default:
+			p.Fprintln(w, i18n.InteractiveUnknownCommand, command)
+		}
+	}
+}
+
+// runInteractiveStep executes step index and moves the cursor past it. It
+// reports whether the step passed.
+func (r *Runner) runInteractiveStep(ctx context.Context, session *interactiveSession, index int) bool {
+	w := r.payloadWriter()
+	current := &session.steps[index]
+	step := current.file.Steps[current.index]
+	session.next = index + 1
+
+	start := time.Now()
+	requestMade, err := r.executeStep(ctx, step, session.captures, current.file.BaseDir)
+	if err == nil && requestMade {
+		err = r.checkStableCaptures(stableCaptureKey(current.file.Filename, current.index), step.Asserts.Stable, session.captures)
+	}
+	err = r.redactError(err, session.captures)
+	if err == nil && requestMade && step.Foreach == nil {
+		r.registerCleanups(ctx, step, session.captures, current.file.BaseDir)
+	}
+	elapsed := time.Since(start).Milliseconds()
+
+	switch {
+	case err != nil:
+		current.status = i18n.InteractiveFailed
+		r.printer.Fprintln(w, i18n.InteractiveStepFailed, index, stepTitle(step), err, elapsed)
+		return false
+	case !requestMade:
+		current.status = i18n.InteractiveSkipped
+		r.printer.Fprintln(w, i18n.InteractiveStepSkipped, index, stepTitle(step))
+	default:
+		current.status = i18n.InteractivePassed
+		r.printer.Fprintln(w, i18n.InteractiveStepSuccess, index, stepTitle(step), elapsed)
+	}
+
+	return true
+}
+
+func (s *interactiveSession) list(w io.Writer, p *i18n.Printer) {
+	for i, step := range s.steps {
+		marker := " "
+		if i == s.next {
+			marker = ">"
+		}
+		definition := step.file.Steps[step.index]
+		fmt.Fprintf(w, "%s %3d  %-7s  %s:%d  %s\n", marker, i, p.Text(step.status), step.file.Filename, step.index, stepTitle(definition))
+	}
+}
+
+func (s *interactiveSession) exitCode() int {
+	for _, step := range s.steps {
+		if step.status == i18n.InteractiveFailed {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func (r *Runner) printInteractiveVariables(w io.Writer, captures map[string]CaptureValue) {
+	snapshot := r.snapshotVariables(0, captures)
+	if len(snapshot.Values) == 0 {
+		r.printer.Fprintln(w, i18n.InteractiveNoVariables)
+		return
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(snapshot.Values)) {
+		fmt.Fprintf(w, "  %s = %v\n", name, snapshot.Values[name])
+	}
+}
@@ -0,0 +1,173 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestServeInteractive(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/flaky" && failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token":"abc"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	statusOK := []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200}}}
+	file := CompiledFile{
+		Filename: "flow.yaml",
+		Steps: []model.Step{
+			{
+				Method:   "GET",
+				URL:      server.URL + "/login",
+				Captures: &model.Captures{JSONPath: []model.JSONPathCapture{{Name: "token", Path: "$.token"}}},
+			},
+			{Method: "GET", URL: server.URL + "/flaky", Asserts: model.Asserts{Status: statusOK}},
+			{Method: "GET", URL: server.URL + "/done"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		commands []string
+		wantCode int
+		want     []string
+	}{
+		{
+			name:     "step and inspect variables",
+			commands: []string{"n", "vars", "list", "quit"},
+			wantCode: 0,
+			want: []string{
+				"3 step(s) loaded",
+				"Step 0 GET " + server.URL + "/login: Success",
+				"  token = abc",
+				">   1  pending  flow.yaml:1",
+			},
+		},
+		{
+			name:     "continue stops at failed step",
+			commands: []string{"c", "list"},
+			wantCode: 1,
+			want: []string{
+				"Step 1 GET " + server.URL + "/flaky: Failed: ",
+				"    1  failed   flow.yaml:1",
+				">   2  pending  flow.yaml:2",
+			},
+		},
+		{
+			name:     "invalid commands",
+			commands: []string{"run 9", "bogus", "set novalue", "set region=eu", "v"},
+			wantCode: 0,
+			want: []string{
+				`Invalid step "9": expected a number between 0 and 2`,
+				`Unknown command "bogus"`,
+				"Usage: set NAME=VALUE",
+				"  region = eu",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			runner := newDefault()
+			runner.SetOutput(&out)
+
+			session := newInteractiveSession([]CompiledFile{file}, map[string]any{})
+			input := strings.NewReader(strings.Join(tt.commands, "\n") + "\n")
+
+			if code := runner.serveInteractive(context.Background(), session, input); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d\n%s", code, tt.wantCode, out.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestServeInteractiveRerun(t *testing.T) {
+	t.Parallel()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	statusOK := []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200}}}
+	file := CompiledFile{
+		Filename: "flow.yaml",
+		Steps:    []model.Step{{Method: "GET", URL: server.URL, Asserts: model.Asserts{Status: statusOK}}},
+	}
+
+	var out bytes.Buffer
+	runner := newDefault()
+	runner.SetOutput(&out)
+	session := newInteractiveSession([]CompiledFile{file}, nil)
+
+	if code := runner.serveInteractive(context.Background(), session, strings.NewReader("n\n")); code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+
+	failing.Store(false)
+	if code := runner.serveInteractive(context.Background(), session, strings.NewReader("run 0\n")); code != 0 {
+		t.Fatalf("exit code after rerun = %d, want 0\n%s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "Step 0 GET "+server.URL+": Success") {
+		t.Fatalf("output missing successful rerun:\n%s", out.String())
+	}
+}
+
+func TestServeInteractiveLocalized(t *testing.T) {
+	t.Parallel()
+
+	i18n.Register("x-interactive", i18n.Catalog{
+		i18n.InteractiveLoaded:         "%d passo(s) carregado(s)",
+		i18n.InteractiveUnknownCommand: "Comando desconhecido %q",
+		i18n.InteractivePending:        "pendente",
+	})
+
+	var out bytes.Buffer
+	runner := newDefault()
+	runner.printer = i18n.NewPrinter("x-interactive")
+	runner.SetOutput(&out)
+	file := CompiledFile{Filename: "flow.yaml", Steps: []model.Step{{Method: "GET", URL: "http://example.invalid"}}}
+	session := newInteractiveSession([]CompiledFile{file}, nil)
+
+	if code := runner.serveInteractive(context.Background(), session, strings.NewReader("bogus\nlist\nset\n")); code != 0 {
+		t.Fatalf("exit code = %d, want 0\n%s", code, out.String())
+	}
+	for _, want := range []string{
+		"1 passo(s) carregado(s)\n",
+		`Comando desconhecido "bogus"`,
+		">   0  pendente  flow.yaml:0",
+		"Usage: set NAME=VALUE\n", // untranslated messages fall back to English
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
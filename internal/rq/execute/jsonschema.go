@@ -0,0 +1,73 @@
+package execute
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	goyaml "github.com/goccy/go-yaml"
+
+	"github.com/jacoelho/rq/internal/pathing"
+	"github.com/jacoelho/rq/internal/rq/jsonschema"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// maxSchemaViolations bounds the violations listed in a failure message.
+const maxSchemaViolations = 10
+
+// checkJSONSchemas validates the JSON body against each schema file and
+// lists the paths of the values that do not match.
+func checkJSONSchemas(asserts []model.JSONSchemaAssert, body []byte, baseDir string) error {
+	if len(asserts) == 0 {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("jsonschema assertion failed: response body is not valid JSON: %w", err)
+	}
+
+	for _, current := range asserts {
+		document, err := loadSchema(pathing.ResolveBodyFilePath(current.File, baseDir))
+		if err != nil {
+			return fmt.Errorf("jsonschema assertion error: %w", err)
+		}
+
+		violations := jsonschema.New(document).Validate(value)
+		if len(violations) == 0 {
+			continue
+		}
+
+		problems := make([]string, 0, min(len(violations), maxSchemaViolations))
+		for _, violation := range violations[:min(len(violations), maxSchemaViolations)] {
+			problems = append(problems, violation.String())
+		}
+		if extra := len(violations) - len(problems); extra > 0 {
+			problems = append(problems, fmt.Sprintf("and %d more", extra))
+		}
+		return fmt.Errorf("jsonschema assertion failed: response body does not match %s: %s", current.File, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// loadSchema reads a schema document in JSON or YAML.
+func loadSchema(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document any
+	if err := goyaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("parse schema %s: %w", path, err)
+	}
+	switch document.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, fmt.Errorf("schema %s is not an object", path)
+	}
+
+	return document, nil
+}
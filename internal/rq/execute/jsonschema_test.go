@@ -0,0 +1,83 @@
+package execute
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestCheckJSONSchemas(t *testing.T) {
+	t.Parallel()
+
+	baseDir := t.TempDir()
+	files := map[string]string{
+		"user.schema.json": `{
+  "type": "object",
+  "required": ["id", "name"],
+  "properties": {
+    "id": {"type": "integer"},
+    "name": {"type": "string"},
+    "tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}}
+  },
+  "$defs": {"tag": {"type": "string", "maxLength": 3}}
+}`,
+		"list.schema.yaml":   "type: array\nitems:\n  type: integer\n",
+		"scalar.schema.json": `"object"`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(baseDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	many := make([]string, 12)
+	for i := range many {
+		many[i] = fmt.Sprintf("%q", "x")
+	}
+
+	tests := []struct {
+		name      string
+		file      string
+		body      string
+		wantError string
+	}{
+		{name: "matching body", file: "user.schema.json", body: `{"id":1,"name":"rq","tags":["a"]}`},
+		{name: "yaml schema", file: "list.schema.yaml", body: `[1,2,3]`},
+		{
+			name:      "violation paths",
+			file:      "user.schema.json",
+			body:      `{"id":"1","tags":["toolong"]}`,
+			wantError: `jsonschema assertion failed: response body does not match user.schema.json: $: missing required property "name"; $.id: expected integer, got string; $.tags[0]:`,
+		},
+		{
+			name:      "violations are capped",
+			file:      "list.schema.yaml",
+			body:      "[" + strings.Join(many, ",") + "]",
+			wantError: "$[9]: expected integer, got string; and 2 more",
+		},
+		{name: "body is not json", file: "user.schema.json", body: "pong", wantError: "response body is not valid JSON"},
+		{name: "schema is not an object", file: "scalar.schema.json", body: "{}", wantError: "is not an object"},
+		{name: "missing file", file: "missing.json", body: "{}", wantError: "jsonschema assertion error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkJSONSchemas([]model.JSONSchemaAssert{{File: tt.file}}, []byte(tt.body), baseDir)
+			if tt.wantError == "" {
+				if err != nil {
+					t.Fatalf("checkJSONSchemas() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Fatalf("checkJSONSchemas() error = %v, want %q", err, tt.wantError)
+			}
+		})
+	}
+}
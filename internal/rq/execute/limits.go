@@ -0,0 +1,38 @@
+package execute
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ResponseTooLargeError reports a response body larger than --max-response-bytes.
+// The limit applies to the body as read, i.e. after transparent decompression.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds --max-response-bytes limit of %d bytes", e.Limit)
+}
+
+// readResponseBody reads at most limit bytes, failing as soon as the body is
+// known to be larger. A limit of zero reads the whole body.
+func readResponseBody(resp *http.Response, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(resp.Body)
+	}
+	if resp.ContentLength > limit {
+		return nil, &ResponseTooLargeError{Limit: limit}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, &ResponseTooLargeError{Limit: limit}
+	}
+
+	return body, nil
+}
@@ -0,0 +1,79 @@
+package execute
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepMaxResponseBytes(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("a", 4096)
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write([]byte(payload))
+	writer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gzip":
+			// Small on the wire, large once the transport decompresses it.
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(compressed.Bytes())
+		case "/chunked":
+			w.(http.Flusher).Flush()
+			w.Write([]byte(payload))
+		default:
+			w.Write([]byte(payload))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name    string
+		path    string
+		limit   int64
+		wantErr bool
+	}{
+		{name: "unlimited", path: "/plain", limit: 0},
+		{name: "within limit", path: "/plain", limit: 4096},
+		{name: "content length over limit", path: "/plain", limit: 1024, wantErr: true},
+		{name: "chunked body over limit", path: "/chunked", limit: 1024, wantErr: true},
+		{name: "decompressed body over limit", path: "/gzip", limit: 1024, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner := newDefault()
+			runner.config = &config.Config{MaxResponseBytes: tt.limit}
+			step := model.Step{Method: "GET", URL: server.URL + tt.path}
+
+			_, err := runner.executeStep(context.Background(), step, map[string]CaptureValue{}, "")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("executeStep() error = %v", err)
+				}
+				return
+			}
+
+			var tooLarge *ResponseTooLargeError
+			if !errors.As(err, &tooLarge) {
+				t.Fatalf("executeStep() error = %v, want ResponseTooLargeError", err)
+			}
+			if tooLarge.Limit != tt.limit {
+				t.Fatalf("limit = %d, want %d", tooLarge.Limit, tt.limit)
+			}
+		})
+	}
+}
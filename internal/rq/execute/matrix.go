@@ -0,0 +1,81 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// executeMatrix sends the step once per options.accept_matrix or
+// accept_language_matrix value, stopping at the first variant that fails.
+// Captures from each variant overwrite the previous ones, so the last
+// variant's values remain.
+func (r *Runner) executeMatrix(ctx context.Context, step model.Step, captures map[string]CaptureValue, stepBaseDir string) (bool, error) {
+	header, values := step.Options.Matrix()
+
+	requestMade := false
+	for _, value := range values {
+		made, err := r.executeStepWithRetries(ctx, matrixVariant(step, header, value), captures, stepBaseDir)
+		requestMade = requestMade || made
+		if err != nil {
+			return requestMade, fmt.Errorf("%s %s: %w", strings.ToLower(header), value, err)
+		}
+	}
+
+	return requestMade, nil
+}
+
+// matrixVariant returns the step as sent for one value of the matrix header:
+// the header is set and the variant's asserts are added to the step's own.
+func matrixVariant(step model.Step, header string, value string) model.Step {
+	variant := step
+	variant.Options.AcceptMatrix = nil
+	variant.Options.LanguageMatrix = nil
+	variant.VariantAsserts = nil
+	variant.Headers = append(slices.Clone(step.Headers), model.KeyValue{Key: header, Value: value})
+	variant.Asserts = mergeAsserts(step.Asserts, step.VariantAsserts[value])
+
+	return variant
+}
+
+func mergeAsserts(base, extra model.Asserts) model.Asserts {
+	merged := model.Asserts{
+		Status:          slices.Concat(base.Status, extra.Status),
+		Headers:         slices.Concat(base.Headers, extra.Headers),
+		Cookies:         slices.Concat(base.Cookies, extra.Cookies),
+		Certificate:     slices.Concat(base.Certificate, extra.Certificate),
+		JSONPath:        slices.Concat(base.JSONPath, extra.JSONPath),
+		CSS:             slices.Concat(base.CSS, extra.CSS),
+		URL:             slices.Concat(base.URL, extra.URL),
+		TLS:             slices.Concat(base.TLS, extra.TLS),
+		TTFB:            slices.Concat(base.TTFB, extra.TTFB),
+		Duration:        slices.Concat(base.Duration, extra.Duration),
+		Attempts:        slices.Concat(base.Attempts, extra.Attempts),
+		Redirects:       slices.Concat(base.Redirects, extra.Redirects),
+		Golden:          slices.Concat(base.Golden, extra.Golden),
+		JSONSchema:      slices.Concat(base.JSONSchema, extra.JSONSchema),
+		Body:            slices.Concat(base.Body, extra.Body),
+		ContentLanguage: slices.Concat(base.ContentLanguage, extra.ContentLanguage),
+		Vary:            slices.Concat(base.Vary, extra.Vary),
+		Expr:            slices.Concat(base.Expr, extra.Expr),
+		Stable:          slices.Concat(base.Stable, extra.Stable),
+		Continue:        slices.Concat(base.Continue, extra.Continue),
+		Range:           base.Range,
+		Allow:           base.Allow,
+		GraphQL:         base.GraphQL,
+	}
+	if extra.Allow != nil {
+		merged.Allow = extra.Allow
+	}
+	if extra.Range != nil {
+		merged.Range = extra.Range
+	}
+	if extra.GraphQL != nil {
+		merged.GraphQL = extra.GraphQL
+	}
+
+	return merged
+}
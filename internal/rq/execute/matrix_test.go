@@ -0,0 +1,160 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepAcceptMatrix(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Accept") {
+		case "application/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"a"}`))
+		case "application/xml":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(`<order id="a"/>`))
+		default:
+			w.WriteHeader(http.StatusNotAcceptable)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	contentType := func(value string) model.HeaderAssert {
+		return model.HeaderAssert{Name: "Content-Type", Predicate: model.Predicate{Operation: "equals", Value: value, HasValue: true}}
+	}
+
+	tests := []struct {
+		name    string
+		matrix  []string
+		wantErr string
+	}{
+		{name: "every variant passes", matrix: []string{"application/json", "application/xml"}},
+		{name: "failing variant is named", matrix: []string{"application/json", "text/csv"}, wantErr: "accept text/csv:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			step := model.Step{
+				Method:  "GET",
+				URL:     server.URL,
+				Options: model.Options{AcceptMatrix: tt.matrix},
+				Asserts: model.Asserts{
+					Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200, HasValue: true}}},
+				},
+				VariantAsserts: map[string]model.Asserts{
+					"application/json": {Headers: []model.HeaderAssert{contentType("application/json")}},
+					"application/xml":  {Headers: []model.HeaderAssert{contentType("application/xml")}},
+				},
+			}
+
+			requestMade, err := newDefault().executeStep(context.Background(), step, map[string]CaptureValue{}, "")
+			if !requestMade {
+				t.Fatal("expected requests to be made")
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("executeStep() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteStepLanguageMatrix(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "Accept-Encoding, Accept-Language")
+		switch language := r.Header.Get("Accept-Language"); language {
+		case "en", "fr-FR":
+			w.Header().Set("Content-Language", language)
+		default:
+			w.Header().Set("Content-Language", "en")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	setAssert := func(op string, value any) model.HeaderSetAssert {
+		return model.HeaderSetAssert{Predicate: model.Predicate{Operation: op, Value: value, HasValue: true}}
+	}
+
+	tests := []struct {
+		name    string
+		matrix  []string
+		wantErr string
+	}{
+		{name: "every language served", matrix: []string{"en", "fr-FR"}},
+		{name: "fallback language fails", matrix: []string{"en", "de"}, wantErr: "accept-language de: assertion failed: Content-Language assertion failed: expected {en} to contain de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			variants := make(map[string]model.Asserts, len(tt.matrix))
+			for _, language := range tt.matrix {
+				variants[language] = model.Asserts{ContentLanguage: []model.HeaderSetAssert{setAssert("contains", language)}}
+			}
+			step := model.Step{
+				Method:         "GET",
+				URL:            server.URL,
+				Options:        model.Options{LanguageMatrix: tt.matrix},
+				Asserts:        model.Asserts{Vary: []model.HeaderSetAssert{setAssert("contains", "accept-language")}},
+				VariantAsserts: variants,
+			}
+
+			_, err := newDefault().executeStep(context.Background(), step, map[string]CaptureValue{}, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("executeStep() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestMergeAssertsCoversEveryField fails when a new assert type is added to
+// model.Asserts without being merged for accept_matrix variants.
+func TestMergeAssertsCoversEveryField(t *testing.T) {
+	t.Parallel()
+
+	var extra model.Asserts
+	value := reflect.ValueOf(&extra).Elem()
+	for i := range value.NumField() {
+		field := value.Field(i)
+		switch field.Kind() {
+		case reflect.Slice:
+			field.Set(reflect.MakeSlice(field.Type(), 1, 1))
+		case reflect.Pointer:
+			field.Set(reflect.New(field.Type().Elem()))
+		default:
+			t.Fatalf("unexpected kind %s for Asserts.%s", field.Kind(), value.Type().Field(i).Name)
+		}
+	}
+
+	merged := reflect.ValueOf(mergeAsserts(model.Asserts{}, extra))
+	for i := range merged.NumField() {
+		if merged.Field(i).IsZero() {
+			t.Errorf("mergeAsserts() drops Asserts.%s", merged.Type().Field(i).Name)
+		}
+	}
+}
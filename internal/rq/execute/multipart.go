@@ -0,0 +1,214 @@
+package execute
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jacoelho/rq/internal/pathing"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/templating"
+)
+
+// multipartBody is a rendered multipart/form-data body. Files are only
+// stat'ed when the body is rendered and are streamed when the request is
+// sent, so large uploads are never held in memory.
+type multipartBody struct {
+	boundary string
+	parts    []model.Part
+	length   int64
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// renderMultipart applies templates to the parts, resolves files against
+// baseDir and computes the length of the encoded body.
+func renderMultipart(parts []model.Part, templateVars map[string]any, baseDir string) (*multipartBody, error) {
+	body := &multipartBody{
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+		parts:    make([]model.Part, 0, len(parts)),
+	}
+
+	var fileBytes int64
+	for _, part := range parts {
+		rendered, err := renderPart(part, templateVars)
+		if err != nil {
+			return nil, err
+		}
+
+		if rendered.File != "" {
+			rendered.File = pathing.ResolveBodyFilePath(rendered.File, baseDir)
+			info, err := os.Stat(rendered.File)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read multipart file %s: %w", rendered.File, err)
+			}
+			if info.IsDir() {
+				return nil, fmt.Errorf("failed to read multipart file %s: is a directory", rendered.File)
+			}
+			fileBytes += info.Size()
+		}
+		body.parts = append(body.parts, rendered)
+	}
+
+	// The encoding around the file contents does not depend on them, so the
+	// length is the encoded body without files plus the file sizes.
+	counter := &countingWriter{}
+	if err := body.write(counter, false); err != nil {
+		return nil, err
+	}
+	body.length = counter.n + fileBytes
+
+	return body, nil
+}
+
+func renderPart(part model.Part, templateVars map[string]any) (model.Part, error) {
+	fields := []*string{&part.Name, &part.Value, &part.File, &part.Filename, &part.ContentType}
+	for _, field := range fields {
+		rendered, err := templating.Apply(*field, templateVars)
+		if err != nil {
+			return model.Part{}, fmt.Errorf("failed to process multipart template for %s: %w", part.Name, err)
+		}
+		*field = rendered
+	}
+	part.File = strings.TrimSpace(part.File)
+
+	return part, nil
+}
+
+// attach sets req to stream the body. GetBody lets redirects that keep the
+// method, such as 307, send it again.
+func (b *multipartBody) attach(req *http.Request) {
+	req.Body = b.reader()
+	req.GetBody = func() (io.ReadCloser, error) {
+		return b.reader(), nil
+	}
+	req.ContentLength = b.length
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+b.boundary)
+}
+
+// reader encodes the body on the fly, starting at the first read so a
+// request that is never sent leaves nothing running. Closing the reader, as
+// the transport does when the server answers early, stops the encoding.
+func (b *multipartBody) reader() io.ReadCloser {
+	return &multipartReader{body: b}
+}
+
+type multipartReader struct {
+	body *multipartBody
+
+	mu     sync.Mutex
+	pipe   *io.PipeReader
+	closed bool
+}
+
+func (r *multipartReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return 0, io.ErrClosedPipe
+	}
+	if r.pipe == nil {
+		reader, writer := io.Pipe()
+		go func() {
+			writer.CloseWithError(r.body.write(writer, true))
+		}()
+		r.pipe = reader
+	}
+	pipe := r.pipe
+	r.mu.Unlock()
+
+	return pipe.Read(p)
+}
+
+func (r *multipartReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	if r.pipe == nil {
+		return nil
+	}
+	return r.pipe.Close()
+}
+
+// write encodes the parts to w. Without withFiles, file contents are left
+// out, which is how the body length is measured.
+func (b *multipartBody) write(w io.Writer, withFiles bool) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(b.boundary); err != nil {
+		return err
+	}
+
+	for _, part := range b.parts {
+		disposition := fmt.Sprintf(`form-data; name="%s"`, quoteEscaper.Replace(part.Name))
+		contentType := part.ContentType
+		if part.File != "" {
+			filename := part.Filename
+			if filename == "" {
+				filename = filepath.Base(part.File)
+			}
+			disposition += fmt.Sprintf(`; filename="%s"`, quoteEscaper.Replace(filename))
+			if contentType == "" {
+				contentType = fileContentType(filename)
+			}
+		}
+
+		header := textproto.MIMEHeader{"Content-Disposition": {disposition}}
+		if contentType != "" {
+			header.Set("Content-Type", contentType)
+		}
+		writer, err := mw.CreatePart(header)
+		if err != nil {
+			return err
+		}
+
+		if part.File == "" {
+			if _, err := io.WriteString(writer, part.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		if withFiles {
+			if err := copyFile(writer, part.File); err != nil {
+				return err
+			}
+		}
+	}
+
+	return mw.Close()
+}
+
+func copyFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read multipart file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// fileContentType guesses the type of an uploaded file from its extension.
+func fileContentType(filename string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(filename)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
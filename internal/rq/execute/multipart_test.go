@@ -0,0 +1,136 @@
+package execute
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepMultipart(t *testing.T) {
+	t.Parallel()
+
+	type upload struct {
+		contentLength int64
+		title         string
+		filename      string
+		fileType      string
+		content       string
+	}
+	received := make(chan upload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("doc")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		received <- upload{
+			contentLength: r.ContentLength,
+			title:         r.FormValue("title"),
+			filename:      header.Filename,
+			fileType:      header.Header.Get("Content-Type"),
+			content:       string(content),
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.json"), []byte(`{"ok":true}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	step := model.Step{
+		Method: "POST",
+		URL:    server.URL,
+		Multipart: []model.Part{
+			{Name: "title", Value: "{{.title}}"},
+			{Name: "doc", File: "report.json", Filename: "{{.title}}.json"},
+		},
+		Asserts: model.Asserts{Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200, HasValue: true}}}},
+	}
+	captures := map[string]CaptureValue{"title": {Value: "weekly"}}
+
+	if _, err := newDefault().executeStep(context.Background(), step, captures, dir); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+
+	got := <-received
+	want := upload{title: "weekly", filename: "weekly.json", fileType: "application/json", content: `{"ok":true}`}
+	if got.contentLength <= 0 {
+		t.Fatalf("ContentLength = %d, want the computed length", got.contentLength)
+	}
+	got.contentLength = 0
+	if got != want {
+		t.Fatalf("upload = %+v, want %+v", got, want)
+	}
+}
+
+func TestRenderMultipart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "data.bin"), []byte("binary-data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		parts   []model.Part
+		wantErr string
+	}{
+		{
+			name:  "fields_and_file",
+			parts: []model.Part{{Name: "a", Value: "1"}, {Name: "b", File: "data.bin"}},
+		},
+		{
+			name:    "missing_file",
+			parts:   []model.Part{{Name: "b", File: "missing.bin"}},
+			wantErr: "failed to read multipart file",
+		},
+		{
+			name:    "directory",
+			parts:   []model.Part{{Name: "b", File: "."}},
+			wantErr: "is a directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body, err := renderMultipart(tt.parts, nil, dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("renderMultipart() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderMultipart() error = %v", err)
+			}
+
+			encoded, err := io.ReadAll(body.reader())
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if int64(len(encoded)) != body.length {
+				t.Fatalf("encoded %d bytes, length = %d", len(encoded), body.length)
+			}
+			if !strings.Contains(string(encoded), "binary-data") {
+				t.Fatalf("encoded body misses file contents: %q", encoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package execute
+
+import (
+	"net/http"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// checkOpenAPI validates an HTTP response against the operation of the
+// --openapi document matching its request. Requests no operation matches
+// are not checked, and neither are WebSocket, gRPC, connect and dns steps or
+// bodies streamed to options.output. With --openapi-warn a mismatch is
+// logged instead of failing the step.
+func (r *Runner) checkOpenAPI(step model.Step, resp *http.Response, body []byte, output string) error {
+	if r.openAPI == nil || resp.Request == nil || output != "" {
+		return nil
+	}
+	if step.WebSocket != nil || step.GRPC != nil || step.Connect != nil || step.DNS != nil {
+		return nil
+	}
+
+	operation, ok := r.openAPI.Match(resp.Request.Method, resp.Request.URL)
+	if !ok {
+		return nil
+	}
+
+	err := operation.ValidateResponse(resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	if err == nil {
+		return nil
+	}
+	if r.config != nil && r.config.OpenAPIWarn {
+		r.printf(i18n.OpenAPIWarning, resp.Request.URL.Redacted(), err)
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,89 @@
+package execute
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/openapi"
+)
+
+func TestExecuteStepOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	spec, err := openapi.Parse([]byte(`
+openapi: 3.0.3
+paths:
+  /orders/{id}:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id: {type: integer}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/orders/1":
+			_, _ = w.Write([]byte(`{"id":1}`))
+		case "/orders/2":
+			_, _ = w.Write([]byte(`{"id":"2"}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name        string
+		path        string
+		warn        bool
+		wantErr     string
+		wantWarning string
+	}{
+		{name: "matches", path: "/orders/1"},
+		{name: "mismatch_fails", path: "/orders/2", wantErr: "assertion failed: response does not match OpenAPI operation GET /orders/{id}: $.id: expected integer, got string"},
+		{name: "mismatch_warns", path: "/orders/2", warn: true, wantWarning: "Warning: " + server.URL + "/orders/2: response does not match"},
+		{name: "undocumented_path_skipped", path: "/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var logs bytes.Buffer
+			runner := newDefault()
+			runner.config = &config.Config{OpenAPIPath: "spec.yaml", OpenAPIWarn: tt.warn}
+			runner.openAPI = spec
+			runner.SetErrorOutput(&logs)
+
+			step := model.Step{Method: "GET", URL: server.URL + tt.path}
+			_, err := runner.executeStep(context.Background(), step, map[string]CaptureValue{}, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("executeStep() error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("executeStep() error = %v, want containing %q", err, tt.wantErr)
+			}
+
+			if tt.wantWarning != "" && !strings.Contains(logs.String(), tt.wantWarning) {
+				t.Fatalf("log = %q, want containing %q", logs.String(), tt.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package execute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/pathing"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/templating"
+)
+
+// resolveOutputPath renders the options.output template of step. Relative
+// paths resolve against the step base directory, like body_file. An empty
+// result means the body is held in memory.
+func resolveOutputPath(step model.Step, captures map[string]CaptureValue, baseDir string) (string, error) {
+	if strings.TrimSpace(step.Options.Output) == "" {
+		return "", nil
+	}
+
+	path, err := templating.Apply(step.Options.Output, captureMapForTemplate(captures))
+	if err != nil {
+		return "", fmt.Errorf("failed to process output template: %w", err)
+	}
+
+	return pathing.ResolveBodyFilePath(strings.TrimSpace(path), baseDir), nil
+}
+
+// writeResponseBody streams the response body to path. The body is written
+// to a temporary file next to path and renamed once complete, so a failed
+// download never leaves a truncated file behind. limit works as in
+// readResponseBody.
+func writeResponseBody(resp *http.Response, path string, limit int64) (err error) {
+	if limit > 0 && resp.ContentLength > limit {
+		return &ResponseTooLargeError{Limit: limit}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.part")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	var body io.Reader = resp.Body
+	if limit > 0 {
+		body = io.LimitReader(resp.Body, limit+1)
+	}
+	written, err := io.Copy(file, body)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && written > limit {
+		return &ResponseTooLargeError{Limit: limit}
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// captureOutput captures properties of the file written by options.output.
+func captureOutput(stepCaptures *model.Captures, path string, captures map[string]CaptureValue) error {
+	if stepCaptures == nil || len(stepCaptures.Output) == 0 {
+		return nil
+	}
+
+	for _, current := range stepCaptures.Output {
+		value, err := outputProperty(path, current.Property)
+		if err != nil {
+			return fmt.Errorf("output capture failed for %s: %w", current.Name, err)
+		}
+
+		captures[current.Name] = CaptureValue{Value: value, Redact: current.Redact}
+	}
+
+	return nil
+}
+
+func outputProperty(path string, property string) (any, error) {
+	switch property {
+	case model.OutputPropertyPath:
+		return path, nil
+	case model.OutputPropertySize:
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		return info.Size(), nil
+	case model.OutputPropertySHA256:
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		hash := sha256.New()
+		if _, err := io.Copy(hash, file); err != nil {
+			return nil, err
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	default:
+		return nil, fmt.Errorf("unsupported property %q", property)
+	}
+}
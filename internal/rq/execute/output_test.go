@@ -0,0 +1,125 @@
+package execute
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepOutput(t *testing.T) {
+	t.Parallel()
+
+	payload := strings.Repeat("rq-download-", 1<<12)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(payload))
+	}))
+	t.Cleanup(server.Close)
+
+	sum := sha256.Sum256([]byte(payload))
+	digest := hex.EncodeToString(sum[:])
+	dir := t.TempDir()
+
+	step := model.Step{
+		Method:  "GET",
+		URL:     server.URL,
+		Options: model.Options{Output: "downloads/{{.name}}.bin"},
+		Asserts: model.Asserts{Body: []model.BodyAssert{
+			{Predicate: model.Predicate{Operation: model.BodyOpSizeEquals, Value: int64(len(payload)), HasValue: true}},
+			{Predicate: model.Predicate{Operation: model.BodyOpSHA256Equals, Value: digest, HasValue: true}},
+			{Predicate: model.Predicate{Operation: model.BodyOpStartsWithBytes, Value: "72 71 2d", HasValue: true}},
+		}},
+		Captures: &model.Captures{Output: []model.OutputCapture{
+			{Name: "size", Property: model.OutputPropertySize},
+			{Name: "digest", Property: model.OutputPropertySHA256},
+			{Name: "path", Property: model.OutputPropertyPath},
+		}},
+	}
+	captures := map[string]CaptureValue{"name": {Value: "archive"}}
+
+	if _, err := newDefault().executeStep(context.Background(), step, captures, dir); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+
+	path := filepath.Join(dir, "downloads", "archive.bin")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(content) != payload {
+		t.Fatalf("output has %d bytes, want %d", len(content), len(payload))
+	}
+	if got := captures["size"].Value; got != int64(len(payload)) {
+		t.Fatalf("size capture = %v, want %d", got, len(payload))
+	}
+	if got := captures["digest"].Value; got != digest {
+		t.Fatalf("digest capture = %v, want %s", got, digest)
+	}
+	if got := captures["path"].Value; got != path {
+		t.Fatalf("path capture = %v, want %s", got, path)
+	}
+}
+
+func TestExecuteStepOutputJSONPath(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"items":[{"id":"a1"},{"id":"a2"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	step := model.Step{
+		Method:  "GET",
+		URL:     server.URL,
+		Options: model.Options{Output: "export.json"},
+		Asserts: model.Asserts{JSONPath: []model.JSONPathAssert{
+			{Path: "$.items[1].id", Predicate: model.Predicate{Operation: "equals", Value: "a2", HasValue: true}},
+		}},
+		Captures: &model.Captures{JSONPath: []model.JSONPathCapture{{Name: "first", Path: "$.items[0].id"}}},
+	}
+	captures := map[string]CaptureValue{}
+
+	if _, err := newDefault().executeStep(context.Background(), step, captures, t.TempDir()); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+	if got := captures["first"].Value; got != "a1" {
+		t.Fatalf("first = %v, want a1", got)
+	}
+}
+
+func TestExecuteStepOutputTooLarge(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("x", 64)))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	runner := newDefault()
+	runner.config = &config.Config{MaxResponseBytes: 16}
+	step := model.Step{Method: "GET", URL: server.URL, Options: model.Options{Output: "body.bin"}}
+
+	_, err := runner.executeStep(context.Background(), step, map[string]CaptureValue{}, dir)
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("executeStep() error = %v, want ResponseTooLargeError", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("failed download left %d files behind", len(entries))
+	}
+}
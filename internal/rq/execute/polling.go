@@ -0,0 +1,197 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/capture"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/templating"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultPollTimeout  = time.Minute
+)
+
+// pollJob sends the step request once to start the job, then polls the status
+// URL with GET until the status field reaches a success or failure value. The
+// Retry-After response header takes precedence over the configured interval
+// when present.
+func (r *Runner) pollJob(ctx context.Context, step model.Step, req *http.Request, captures map[string]CaptureValue) (*http.Response, []byte, error) {
+	poll := step.PollJob
+	interval := poll.Interval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+	timeout := poll.Timeout
+	if timeout == 0 {
+		timeout = defaultPollTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	start := req
+	secrets := redactValues(captures, r.staticSecrets())
+
+	var statusURL string
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			req = pollRequest(ctx, start, statusURL, secrets)
+		}
+
+		resp, body, err := r.executeRequest(ctx, step.Options, req)
+		if err != nil {
+			return nil, nil, err
+		}
+		if attempt == 1 {
+			statusURL, err = pollStatusURL(poll, start, resp, captures)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		status, found := pollStatus(body, poll.StatusPath, step.Options)
+		if r.config != nil && r.config.Debug {
+			r.logf("Poll attempt %d: %s = %q\n", attempt, poll.StatusPath, status)
+		}
+
+		if found && slices.Contains(poll.Success, status) {
+			return resp, body, nil
+		}
+		if found && slices.Contains(poll.Failure, status) {
+			return nil, nil, fmt.Errorf("poll_job failed: %s reached failure value %q", poll.StatusPath, status)
+		}
+
+		wait := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if wait <= 0 {
+			wait = interval
+		}
+
+		if time.Now().Add(wait).After(deadline) {
+			return nil, nil, fmt.Errorf("poll_job timed out after %s and %d attempt(s): last %s = %q", timeout, attempt, poll.StatusPath, status)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pollStatusURL returns the URL polled after the start request: status_url
+// when set, else the Location header of the start response, else the step URL
+// itself for GET steps. Relative URLs resolve against the start request.
+func pollStatusURL(poll *model.PollJob, start *http.Request, resp *http.Response, captures map[string]CaptureValue) (string, error) {
+	target := ""
+	switch {
+	case poll.StatusURL != "":
+		rendered, err := templating.Apply(poll.StatusURL, captureMapForTemplate(captures))
+		if err != nil {
+			return "", fmt.Errorf("failed to process poll_job status_url template: %w", err)
+		}
+		target = rendered
+	case resp.Header.Get("Location") != "":
+		target = resp.Header.Get("Location")
+	case start.Method == http.MethodGet:
+		return start.URL.String(), nil
+	default:
+		return "", fmt.Errorf("poll_job: %s start response has no Location header; set status_url", start.Method)
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(target))
+	if err != nil {
+		return "", fmt.Errorf("poll_job: invalid status URL %q: %w", target, err)
+	}
+	return start.URL.ResolveReference(ref).String(), nil
+}
+
+// credentialHeaders are dropped from status requests sent to another origin,
+// as net/http does when a redirect leaves the original host.
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Www-Authenticate", "Cookie", "Cookie2"}
+
+// pollRequest builds a status GET carrying the headers of the start request
+// without the body headers. When the status URL is on another scheme or
+// host, credentials and headers holding a secret are not sent, so a Location
+// pointing elsewhere cannot collect them.
+func pollRequest(ctx context.Context, start *http.Request, statusURL string, secrets []any) *http.Request {
+	req := start.Clone(ctx)
+	req.Method = http.MethodGet
+	req.Body = nil
+	req.GetBody = nil
+	req.ContentLength = 0
+	req.Header.Del("Content-Type")
+	req.Header.Del("Content-Length")
+	// statusURL was resolved from a valid URL, so parsing cannot fail.
+	req.URL, _ = url.Parse(statusURL)
+	req.Host = req.URL.Host
+
+	if !sameOrigin(start.URL, req.URL) {
+		for _, name := range credentialHeaders {
+			req.Header.Del(name)
+		}
+		for name, values := range req.Header {
+			if slices.ContainsFunc(values, func(value string) bool { return containsSecret(value, secrets) }) {
+				req.Header.Del(name)
+			}
+		}
+	}
+	return req
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+func containsSecret(value string, secrets []any) bool {
+	for _, secret := range secrets {
+		if text := fmt.Sprint(secret); text != "" && strings.Contains(value, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// pollStatus extracts the status field as a string; non-JSON bodies and missing
+// fields are treated as still pending.
+func pollStatus(body []byte, path string, options model.Options) (string, bool) {
+	data, err := parseJSONBody(body, options)
+	if err != nil {
+		return "", false
+	}
+
+	value, err := capture.ExtractJSONPathFromDataString(data, path)
+	if err != nil {
+		return "", false
+	}
+
+	return value, true
+}
+
+// retryAfter parses a Retry-After header given either as delay seconds or as an HTTP date.
+func retryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return at.Sub(now)
+	}
+
+	return 0
+}
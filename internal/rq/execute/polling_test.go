@@ -0,0 +1,311 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepPollJob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("polls until success and captures final payload", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if calls.Add(1) < 3 {
+				w.Write([]byte(`{"status":"running"}`))
+				return
+			}
+			w.Write([]byte(`{"status":"done","result":{"id":"abc"}}`))
+		}))
+		defer server.Close()
+
+		runner := newDefault()
+		step := model.Step{
+			Method: "GET",
+			URL:    server.URL,
+			PollJob: &model.PollJob{
+				StatusPath: "$.status",
+				Success:    []string{"done"},
+				Failure:    []string{"failed"},
+				Interval:   time.Millisecond,
+				Timeout:    time.Second,
+			},
+			Captures: &model.Captures{
+				JSONPath: []model.JSONPathCapture{{Name: "result_id", Path: "$.result.id"}},
+			},
+		}
+		captures := map[string]CaptureValue{}
+
+		if _, err := runner.executeStep(context.Background(), step, captures, ""); err != nil {
+			t.Fatalf("executeStep() error = %v", err)
+		}
+		if got := calls.Load(); got != 3 {
+			t.Fatalf("calls = %d, want 3", got)
+		}
+		if captures["result_id"].Value != "abc" {
+			t.Fatalf("result_id = %v, want abc", captures["result_id"].Value)
+		}
+	})
+
+	t.Run("starts the job once and polls the Location with GET", func(t *testing.T) {
+		t.Parallel()
+
+		var starts, polls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/jobs":
+				starts.Add(1)
+				w.Header().Set("Location", "/jobs/42")
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte(`{"status":"queued"}`))
+			case r.Method == http.MethodGet && r.URL.Path == "/jobs/42":
+				if r.Header.Get("Authorization") != "Bearer t" || r.Header.Get("Content-Type") != "" {
+					t.Errorf("unexpected poll headers: %v", r.Header)
+				}
+				if polls.Add(1) < 2 {
+					w.Write([]byte(`{"status":"running"}`))
+					return
+				}
+				w.Write([]byte(`{"status":"done"}`))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		runner := newDefault()
+		step := model.Step{
+			Method:  "POST",
+			URL:     server.URL + "/jobs",
+			Headers: model.KeyValues{{Key: "Authorization", Value: "Bearer t"}},
+			Body:    model.Body{Text: `{"report":"monthly"}`},
+			PollJob: &model.PollJob{
+				StatusPath: "$.status",
+				Success:    []string{"done"},
+				Interval:   time.Millisecond,
+				Timeout:    time.Second,
+			},
+		}
+
+		if _, err := runner.executeStep(context.Background(), step, map[string]CaptureValue{}, ""); err != nil {
+			t.Fatalf("executeStep() error = %v", err)
+		}
+		if got := starts.Load(); got != 1 {
+			t.Fatalf("start requests = %d, want 1", got)
+		}
+		if got := polls.Load(); got != 2 {
+			t.Fatalf("poll requests = %d, want 2", got)
+		}
+	})
+
+	t.Run("drops credentials when the Location is on another host", func(t *testing.T) {
+		t.Parallel()
+
+		var polls atomic.Int32
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			polls.Add(1)
+			for _, name := range []string{"Authorization", "Cookie", "X-Api-Key"} {
+				if value := r.Header.Get(name); value != "" {
+					t.Errorf("%s sent to another host: %q", name, value)
+				}
+			}
+			if got := r.Header.Get("X-Trace"); got != "abc" {
+				t.Errorf("X-Trace = %q, want abc", got)
+			}
+			w.Write([]byte(`{"status":"done"}`))
+		}))
+		defer other.Close()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", other.URL+"/jobs/42")
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		runner := newDefault()
+		step := model.Step{
+			Method: "POST",
+			URL:    server.URL + "/jobs",
+			Headers: model.KeyValues{
+				{Key: "Authorization", Value: "Bearer t"},
+				{Key: "Cookie", Value: "session=1"},
+				{Key: "X-Api-Key", Value: "{{.api_key}}"},
+				{Key: "X-Trace", Value: "abc"},
+			},
+			PollJob: &model.PollJob{
+				StatusPath: "$.status",
+				Success:    []string{"done"},
+				Interval:   time.Millisecond,
+				Timeout:    time.Second,
+			},
+		}
+		captures := map[string]CaptureValue{"api_key": {Value: "k-123", Redact: true}}
+
+		if _, err := runner.executeStep(context.Background(), step, captures, ""); err != nil {
+			t.Fatalf("executeStep() error = %v", err)
+		}
+		if got := polls.Load(); got != 1 {
+			t.Fatalf("poll requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("polls the templated status_url", func(t *testing.T) {
+		t.Parallel()
+
+		var starts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				starts.Add(1)
+				w.Write([]byte(`{"status":"queued"}`))
+				return
+			}
+			if r.URL.Path != "/status/7" {
+				t.Errorf("unexpected poll path %s", r.URL.Path)
+			}
+			w.Write([]byte(`{"status":"done"}`))
+		}))
+		defer server.Close()
+
+		runner := newDefault()
+		step := model.Step{
+			Method: "POST",
+			URL:    server.URL + "/jobs",
+			PollJob: &model.PollJob{
+				StatusURL:  server.URL + "/status/{{.job}}",
+				StatusPath: "$.status",
+				Success:    []string{"done"},
+				Interval:   time.Millisecond,
+				Timeout:    time.Second,
+			},
+		}
+		captures := map[string]CaptureValue{"job": {Value: "7"}}
+
+		if _, err := runner.executeStep(context.Background(), step, captures, ""); err != nil {
+			t.Fatalf("executeStep() error = %v", err)
+		}
+		if got := starts.Load(); got != 1 {
+			t.Fatalf("start requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("non-GET start without a status URL fails", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Write([]byte(`{"status":"queued"}`))
+		}))
+		defer server.Close()
+
+		runner := newDefault()
+		step := model.Step{
+			Method: "POST",
+			URL:    server.URL,
+			PollJob: &model.PollJob{
+				StatusPath: "$.status",
+				Success:    []string{"done"},
+				Interval:   time.Millisecond,
+			},
+		}
+
+		_, err := runner.executeStep(context.Background(), step, map[string]CaptureValue{}, "")
+		if err == nil || !strings.Contains(err.Error(), "set status_url") {
+			t.Fatalf("expected status URL error, got %v", err)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Fatalf("calls = %d, want 1", got)
+		}
+	})
+
+	t.Run("failure value stops polling", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"status":"failed"}`))
+		}))
+		defer server.Close()
+
+		runner := newDefault()
+		step := model.Step{
+			Method: "GET",
+			URL:    server.URL,
+			PollJob: &model.PollJob{
+				StatusPath: "$.status",
+				Success:    []string{"done"},
+				Failure:    []string{"failed"},
+				Interval:   time.Millisecond,
+			},
+		}
+
+		_, err := runner.executeStep(context.Background(), step, map[string]CaptureValue{}, "")
+		if err == nil || !strings.Contains(err.Error(), `failure value "failed"`) {
+			t.Fatalf("expected failure value error, got %v", err)
+		}
+	})
+
+	t.Run("times out when status never terminates", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		runner := newDefault()
+		step := model.Step{
+			Method: "GET",
+			URL:    server.URL,
+			PollJob: &model.PollJob{
+				StatusPath: "$.status",
+				Success:    []string{"done"},
+				Interval:   5 * time.Millisecond,
+				Timeout:    20 * time.Millisecond,
+			},
+		}
+
+		_, err := runner.executeStep(context.Background(), step, map[string]CaptureValue{}, "")
+		if err == nil || !strings.Contains(err.Error(), "poll_job timed out") {
+			t.Fatalf("expected timeout error, got %v", err)
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "empty", value: "", want: 0},
+		{name: "seconds", value: "3", want: 3 * time.Second},
+		{name: "negative seconds", value: "-1", want: 0},
+		{name: "http date", value: "Mon, 01 Jan 2024 12:00:05 GMT", want: 5 * time.Second},
+		{name: "invalid", value: "soon", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := retryAfter(tt.value, now); got != tt.want {
+				t.Fatalf("retryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
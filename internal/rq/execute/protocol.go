@@ -0,0 +1,75 @@
+package execute
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// versionTransportKey identifies a transport built for options.http_version
+// on top of a base transport.
+type versionTransportKey struct {
+	base    http.RoundTripper
+	version string
+}
+
+// versionClient returns client with a transport that only speaks version.
+// HTTP/1.1 and HTTP/2 reuse a clone of the base transport, HTTP/2 using prior
+// knowledge (h2c) for http:// URLs; HTTP/3 runs over QUIC with the base TLS
+// configuration. Transports are cached per base transport so connections are
+// still reused.
+func (r *Runner) versionClient(client *http.Client, version string) (*http.Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := versionTransportKey{base: client.Transport, version: version}
+	transport, ok := r.versionTransports[key]
+	if !ok {
+		var base *http.Transport
+		switch t := client.Transport.(type) {
+		case nil:
+			base = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			base = t.Clone()
+		default:
+			return nil, fmt.Errorf("http_version requires an *http.Transport, got %T", t)
+		}
+
+		// An inherited ALPN list could still offer a protocol the transport
+		// is not allowed to speak; the transport fills it from Protocols.
+		if base.TLSClientConfig != nil {
+			base.TLSClientConfig = base.TLSClientConfig.Clone()
+			base.TLSClientConfig.NextProtos = nil
+		}
+
+		switch version {
+		case model.HTTPVersion1:
+			base.Protocols = new(http.Protocols)
+			base.Protocols.SetHTTP1(true)
+			transport = base
+		case model.HTTPVersion2:
+			base.Protocols = new(http.Protocols)
+			base.Protocols.SetHTTP2(true)
+			base.Protocols.SetUnencryptedHTTP2(true)
+			transport = base
+		case model.HTTPVersion3:
+			transport = &http3.Transport{
+				TLSClientConfig:        base.TLSClientConfig,
+				MaxResponseHeaderBytes: int(base.MaxResponseHeaderBytes),
+			}
+		default:
+			return nil, fmt.Errorf("unsupported http_version: %s", version)
+		}
+
+		if r.versionTransports == nil {
+			r.versionTransports = make(map[versionTransportKey]http.RoundTripper)
+		}
+		r.versionTransports[key] = transport
+	}
+
+	versionClient := *client
+	versionClient.Transport = transport
+	return &versionClient, nil
+}
@@ -0,0 +1,107 @@
+package execute
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+func TestExecuteStepHTTPVersion(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+	})
+
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+	t.Cleanup(tlsServer.Close)
+
+	plainServer := httptest.NewUnstartedServer(handler)
+	plainServer.Config.Protocols = new(http.Protocols)
+	plainServer.Config.Protocols.SetHTTP1(true)
+	plainServer.Config.Protocols.SetUnencryptedHTTP2(true)
+	plainServer.Start()
+	t.Cleanup(plainServer.Close)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	quicServer := &http3.Server{Handler: handler, TLSConfig: http3.ConfigureTLSConfig(tlsServer.TLS)}
+	go func() { _ = quicServer.Serve(conn) }()
+	t.Cleanup(func() { quicServer.Close() })
+	quicURL := "https://" + conn.LocalAddr().String()
+
+	step := func(url, version, proto string) model.Step {
+		return model.Step{
+			Method:  "GET",
+			URL:     url,
+			Options: model.Options{HTTPVersion: version},
+			Asserts: model.Asserts{
+				Headers: []model.HeaderAssert{{
+					Name:      "x-proto",
+					Predicate: model.Predicate{Operation: "equals", Value: proto, HasValue: true},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		step    model.Step
+		wantErr string
+	}{
+		{
+			name: "http/1.1 over tls",
+			step: step(tlsServer.URL, "1.1", "HTTP/1.1"),
+		},
+		{
+			name: "http/2 over tls",
+			step: step(tlsServer.URL, "2", "HTTP/2.0"),
+		},
+		{
+			name: "http/2 with prior knowledge",
+			step: step(plainServer.URL, "2", "HTTP/2.0"),
+		},
+		{
+			name: "http/3 over quic",
+			step: step(quicURL, "3", "HTTP/3.0"),
+		},
+		{
+			name:    "http/3 without a quic listener",
+			step:    step(tlsServer.URL, "3", "HTTP/3.0"),
+			wantErr: "request failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner := newDefault()
+			runner.client = tlsServer.Client()
+			runner.client.Timeout = time.Second
+
+			_, err := runner.executeStep(context.Background(), tt.step, map[string]CaptureValue{}, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeStep() error = %v", err)
+			}
+		})
+	}
+}
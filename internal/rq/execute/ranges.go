@@ -0,0 +1,134 @@
+package execute
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// byteRange is a single range of a Range request header. A suffix range,
+// bytes=-n, has suffix set and asks for the last n bytes. An open range,
+// bytes=s-, has end set to -1.
+type byteRange struct {
+	start  int64
+	end    int64
+	suffix bool
+}
+
+// checkRange verifies a partial response to the Range header of its request:
+// the status is 206, Content-Range covers exactly the requested bytes of the
+// object, and the body holds that many bytes.
+func checkRange(resp *http.Response, body []byte) error {
+	if resp.Request == nil {
+		return errors.New("range assertion failed: request is not available")
+	}
+
+	requested, err := parseByteRange(resp.Request.Header.Get("Range"))
+	if err != nil {
+		return fmt.Errorf("range assertion failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("range assertion failed: expected status 206, got %d", resp.StatusCode)
+	}
+
+	contentRange := resp.Header.Get("Content-Range")
+	start, end, total, err := parseContentRange(contentRange)
+	if err != nil {
+		return fmt.Errorf("range assertion failed: %w", err)
+	}
+
+	wantStart, wantEnd := requested.resolve(total)
+	if start != wantStart || end != wantEnd {
+		return fmt.Errorf("range assertion failed: Content-Range %q does not match requested bytes %d-%d/%d", contentRange, wantStart, wantEnd, total)
+	}
+	if length := end - start + 1; int64(len(body)) != length {
+		return fmt.Errorf("range assertion failed: Content-Range %q announces %d bytes, body has %d", contentRange, length, len(body))
+	}
+
+	return nil
+}
+
+// resolve returns the first and last byte positions a server should send for
+// the range of an object of total bytes.
+func (r byteRange) resolve(total int64) (int64, int64) {
+	if r.suffix {
+		return max(total-r.end, 0), total - 1
+	}
+	if r.end < 0 || r.end >= total {
+		return r.start, total - 1
+	}
+	return r.start, r.end
+}
+
+// parseByteRange parses a Range header holding a single bytes range.
+func parseByteRange(value string) (byteRange, error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(value), "bytes=")
+	if !ok {
+		return byteRange{}, fmt.Errorf("expected a bytes Range request header, got %q", value)
+	}
+	if strings.Contains(spec, ",") {
+		return byteRange{}, fmt.Errorf("only a single range is supported, got %q", value)
+	}
+
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return byteRange{}, fmt.Errorf("invalid Range request header %q", value)
+	}
+
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return byteRange{}, fmt.Errorf("invalid suffix range in %q", value)
+		}
+		return byteRange{end: n, suffix: true}, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return byteRange{}, fmt.Errorf("invalid range start in %q", value)
+	}
+	if last == "" {
+		return byteRange{start: start, end: -1}, nil
+	}
+
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || end < start {
+		return byteRange{}, fmt.Errorf("invalid range end in %q", value)
+	}
+	return byteRange{start: start, end: end}, nil
+}
+
+// parseContentRange parses a "bytes start-end/total" Content-Range header.
+// An unknown total, "*", is rejected because the range cannot be checked.
+func parseContentRange(value string) (int64, int64, int64, error) {
+	if value == "" {
+		return 0, 0, 0, errors.New("response has no Content-Range header")
+	}
+
+	spec, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("expected a bytes Content-Range, got %q", value)
+	}
+	positions, size, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+	first, last, ok := strings.Cut(positions, "-")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+
+	start, startErr := strconv.ParseInt(first, 10, 64)
+	end, endErr := strconv.ParseInt(last, 10, 64)
+	total, totalErr := strconv.ParseInt(size, 10, 64)
+	if startErr != nil || endErr != nil || totalErr != nil {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+	if start < 0 || end < start || end >= total {
+		return 0, 0, 0, fmt.Errorf("inconsistent Content-Range %q", value)
+	}
+
+	return start, end, total, nil
+}
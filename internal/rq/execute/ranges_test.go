@@ -0,0 +1,62 @@
+package execute
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCheckRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		rangeHeader  string
+		status       int
+		contentRange string
+		body         string
+		wantErr      string
+	}{
+		{name: "closed_range", rangeHeader: "bytes=0-3", status: 206, contentRange: "bytes 0-3/10", body: "abcd"},
+		{name: "open_range", rangeHeader: "bytes=6-", status: 206, contentRange: "bytes 6-9/10", body: "ghij"},
+		{name: "suffix_range", rangeHeader: "bytes=-3", status: 206, contentRange: "bytes 7-9/10", body: "hij"},
+		{name: "end_past_total", rangeHeader: "bytes=8-100", status: 206, contentRange: "bytes 8-9/10", body: "ij"},
+		{name: "suffix_larger_than_total", rangeHeader: "bytes=-50", status: 206, contentRange: "bytes 0-9/10", body: "abcdefghij"},
+		{name: "full_response", rangeHeader: "bytes=0-3", status: 200, body: "abcdefghij", wantErr: "expected status 206, got 200"},
+		{name: "missing_content_range", rangeHeader: "bytes=0-3", status: 206, body: "abcd", wantErr: "no Content-Range header"},
+		{name: "wrong_start", rangeHeader: "bytes=4-", status: 206, contentRange: "bytes 0-9/10", body: "abcdefghij", wantErr: "does not match requested bytes 4-9/10"},
+		{name: "short_body", rangeHeader: "bytes=0-3", status: 206, contentRange: "bytes 0-3/10", body: "abc", wantErr: "announces 4 bytes, body has 3"},
+		{name: "unknown_total", rangeHeader: "bytes=0-3", status: 206, contentRange: "bytes 0-3/*", body: "abcd", wantErr: "invalid Content-Range"},
+		{name: "inconsistent_content_range", rangeHeader: "bytes=0-3", status: 206, contentRange: "bytes 0-10/10", body: "abcd", wantErr: "inconsistent Content-Range"},
+		{name: "multiple_ranges", rangeHeader: "bytes=0-1,4-5", status: 206, wantErr: "only a single range"},
+		{name: "not_bytes", rangeHeader: "items=0-3", status: 206, wantErr: "expected a bytes Range"},
+		{name: "end_before_start", rangeHeader: "bytes=5-2", status: 206, wantErr: "invalid range end"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest(http.MethodGet, "https://example.com/object", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Range", tt.rangeHeader)
+			resp := &http.Response{StatusCode: tt.status, Header: make(http.Header), Request: req}
+			if tt.contentRange != "" {
+				resp.Header.Set("Content-Range", tt.contentRange)
+			}
+
+			err = checkRange(resp, []byte(tt.body))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkRange() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkRange() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package execute
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+// reportWriters maps each --report kind to its writer.
+var reportWriters = map[string]func(io.Writer, []*output.Summary) error{
+	config.ReportJUnit: output.WriteJUnit,
+}
+
+// writeReports saves the summaries of every iteration to the --report files.
+func (r *Runner) writeReports() error {
+	if r.config == nil {
+		return nil
+	}
+
+	for _, kind := range slices.Sorted(maps.Keys(r.config.Reports)) {
+		write, ok := reportWriters[kind]
+		if !ok {
+			return fmt.Errorf("unsupported report kind %q", kind)
+		}
+		if err := writeReportFile(r.config.Reports[kind], r.reported, write); err != nil {
+			return fmt.Errorf("%s report: %w", kind, err)
+		}
+	}
+
+	return nil
+}
+
+func writeReportFile(path string, summaries []*output.Summary, write func(io.Writer, []*output.Summary) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(file, summaries); err != nil {
+		file.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return file.Close()
+}
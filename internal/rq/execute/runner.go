@@ -2,23 +2,36 @@ package execute
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/jacoelho/rq/internal/rq/assert"
+	"github.com/jacoelho/rq/internal/rq/cache"
 	"github.com/jacoelho/rq/internal/rq/compile"
 	"github.com/jacoelho/rq/internal/rq/config"
 	"github.com/jacoelho/rq/internal/rq/exit"
+	"github.com/jacoelho/rq/internal/rq/grpc"
+	"github.com/jacoelho/rq/internal/rq/httpclient"
+	"github.com/jacoelho/rq/internal/rq/i18n"
 	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/openapi"
 	"github.com/jacoelho/rq/internal/rq/output"
+	"github.com/jacoelho/rq/internal/rq/ratelimit"
+	"github.com/jacoelho/rq/internal/rq/sanitizer"
+	"github.com/jacoelho/rq/internal/rq/trace"
 	"github.com/jacoelho/rq/internal/rq/yaml"
-	"golang.org/x/time/rate"
 )
 
+// errRunTimeout is the cause of a run context whose --run-timeout passed.
+var errRunTimeout = errors.New("run timeout exceeded")
+
 type CompiledFile struct {
 	Filename string
 	BaseDir  string
@@ -26,39 +39,90 @@ type CompiledFile struct {
 }
 
 type Runner struct {
-	client          *http.Client
-	variables       map[string]any
-	config          *config.Config
-	compiled        []CompiledFile
-	rateLimiter     *rate.Limiter
-	assertEvaluator *assert.Evaluator
-	output          io.Writer
-	errOutput       io.Writer
+	client            *http.Client
+	variables         map[string]any
+	config            *config.Config
+	compiled          []CompiledFile
+	rateLimiter       *ratelimit.Limiter
+	breaker           *circuitBreaker
+	conns             *httpclient.ConnTracker
+	printer           *i18n.Printer
+	tracer            *trace.Recorder
+	reported          []*output.Summary
+	assertEvaluator   *assert.Evaluator
+	openAPI           *openapi.Spec
+	cache             *cache.Store
+	stableCaptures    map[string]any
+	tlsClients        map[string]*http.Client
+	grpcMethods       map[string]*grpc.Method
+	grpcTransports    map[http.RoundTripper]*http.Transport
+	versionTransports map[versionTransportKey]http.RoundTripper
+	oauthTokens       map[string]oauthToken
+	cleanups          []pendingCleanup
+	input             io.Reader
+	output            io.Writer
+	errOutput         io.Writer
+
+	// mu guards the state shared by files running in parallel: stable
+	// captures, cached TLS clients, gRPC methods, gRPC and http_version
+	// transports, queued cleanups, and the lazily created evaluator. logMu
+	// keeps log lines and debug dumps from interleaving. authMu guards the
+	// OAuth2 token cache and serialises token requests.
+	mu     sync.Mutex
+	logMu  sync.Mutex
+	authMu sync.Mutex
 }
 
 func New(cfg *config.Config) (*Runner, *exit.Result) {
+	printer := i18n.NewPrinter(cfg.Lang)
 	client, err := cfg.HTTPClient()
 	if err != nil {
-		return nil, exit.Errorf("Error creating runner: %v\n", err)
+		return nil, exit.Error(printer.Sprintf(i18n.CreateRunnerError, err) + "\n")
+	}
+
+	var conns *httpclient.ConnTracker
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		conns = httpclient.TrackConnections(transport)
+	}
+
+	var tracer *trace.Recorder
+	if cfg.TracePath != "" {
+		tracer = trace.New(time.Now())
+	}
+
+	var spec *openapi.Spec
+	if cfg.OpenAPIPath != "" {
+		spec, err = openapi.Load(cfg.OpenAPIPath)
+		if err != nil {
+			return nil, exit.Error(printer.Sprintf(i18n.OpenAPILoadError, err) + "\n")
+		}
+	}
+
+	var store *cache.Store
+	if cfg.CacheDir != "" {
+		store = cache.New(cfg.CacheDir)
 	}
 
 	return &Runner{
 		client:          client,
 		variables:       cfg.AllVariables(),
 		config:          cfg,
-		rateLimiter:     newRateLimiter(cfg.RateLimit),
+		rateLimiter:     ratelimit.New(cfg.RateLimit),
+		breaker:         newCircuitBreaker(cfg.CircuitBreaker),
+		conns:           conns,
+		printer:         printer,
+		tracer:          tracer,
 		assertEvaluator: assert.NewEvaluator(),
+		openAPI:         spec,
+		cache:           store,
+		input:           os.Stdin,
 		output:          os.Stdout,
 		errOutput:       os.Stderr,
 	}, nil
 }
 
-func newRateLimiter(requestsPerSecond float64) *rate.Limiter {
-	if requestsPerSecond <= 0 {
-		return rate.NewLimiter(rate.Inf, 1)
-	}
-
-	return rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+func (r *Runner) SetInput(in io.Reader) {
+	r.input = in
 }
 
 func (r *Runner) SetOutput(w io.Writer) {
@@ -69,6 +133,13 @@ func (r *Runner) SetErrorOutput(w io.Writer) {
 	r.errOutput = w
 }
 
+func (r *Runner) inputReader() io.Reader {
+	if r.input == nil {
+		return strings.NewReader("")
+	}
+	return r.input
+}
+
 func (r *Runner) payloadWriter() io.Writer {
 	if r.output == nil {
 		return io.Discard
@@ -84,6 +155,9 @@ func (r *Runner) errorWriter() io.Writer {
 }
 
 func (r *Runner) assertionEvaluator() *assert.Evaluator {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.assertEvaluator == nil {
 		r.assertEvaluator = assert.NewEvaluator()
 	}
@@ -91,11 +165,64 @@ func (r *Runner) assertionEvaluator() *assert.Evaluator {
 	return r.assertEvaluator
 }
 
+// parallelism returns how many files may run at once.
+func (r *Runner) parallelism() int {
+	if r.config == nil {
+		return 1
+	}
+
+	return max(r.config.Parallel, 1)
+}
+
+// logf writes a log message with --secret values masked.
 func (r *Runner) logf(format string, args ...any) {
-	_, _ = fmt.Fprintf(r.errorWriter(), format, args...)
+	message := sanitizer.Redact(fmt.Appendf(nil, format, args...), redactValues(nil, r.staticSecrets()), r.secretSalt())
+
+	r.logMu.Lock()
+	defer r.logMu.Unlock()
+
+	_, _ = r.errorWriter().Write(message)
+}
+
+// printf logs the message of key, in the language of the run, on its own line.
+func (r *Runner) printf(key i18n.Key, args ...any) {
+	r.logf("%s\n", r.printer.Sprintf(key, args...))
 }
 
 func (r *Runner) Run(ctx context.Context) int {
+	if r.config.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, r.config.RunTimeout, errRunTimeout)
+		defer cancel()
+	}
+
+	exitCode := r.run(ctx)
+	if err := r.writeTrace(); err != nil {
+		r.printf(i18n.WriteTraceError, err)
+		return 1
+	}
+	if err := r.writeReports(); err != nil {
+		r.printf(i18n.WriteReportError, err)
+		return 1
+	}
+	if err := r.writeExports(); err != nil {
+		r.printf(i18n.WriteCapturesError, err)
+		return 1
+	}
+	if !r.applyBaseline() {
+		return 1
+	}
+
+	return exitCode
+}
+
+func (r *Runner) run(ctx context.Context) int {
+	if r.config.Interactive {
+		return r.runInteractive(ctx)
+	}
+	if r.config.Daemon {
+		return r.runDaemon(ctx)
+	}
 	if r.config.Repeat < 0 {
 		return r.runInfiniteLoop(ctx)
 	}
@@ -106,12 +233,13 @@ func (r *Runner) runInfiniteLoop(ctx context.Context) int {
 	return r.runLoop(
 		ctx,
 		0,
+		false,
 		func(completed int) string {
-			return fmt.Sprintf("Interrupted after %d iterations", completed)
+			return r.printer.Sprintf(i18n.Interrupted, completed)
 		},
 		func(iteration int) string {
 			if r.config.Debug {
-				return fmt.Sprintf("--- Iteration %d ---", iteration)
+				return r.printer.Sprintf(i18n.IterationHeader, iteration)
 			}
 			return ""
 		},
@@ -126,15 +254,18 @@ func (r *Runner) runFiniteLoop(ctx context.Context) int {
 	totalIterations := r.config.Repeat + 1
 	allResults := make([]*output.Summary, 0, totalIterations)
 
+	// Repeated runs keep going after a failed iteration so intermittent
+	// failures can be told apart from consistent ones in the summary.
 	return r.runLoop(
 		ctx,
 		totalIterations,
+		totalIterations > 1,
 		func(completed int) string {
-			return fmt.Sprintf("Interrupted after %d of %d iterations", completed, totalIterations)
+			return r.printer.Sprintf(i18n.InterruptedOf, completed, totalIterations)
 		},
 		func(iteration int) string {
 			if r.config.Debug && totalIterations > 1 {
-				return fmt.Sprintf("--- Iteration %d of %d ---", iteration, totalIterations)
+				return r.printer.Sprintf(i18n.IterationHeaderOf, iteration, totalIterations)
 			}
 			return ""
 		},
@@ -151,14 +282,19 @@ func (r *Runner) runFiniteLoop(ctx context.Context) int {
 func (r *Runner) runLoop(
 	ctx context.Context,
 	totalIterations int,
+	continueOnFailure bool,
 	interruptMessage func(completed int) string,
 	debugHeader func(iteration int) string,
 	handleResult func(*output.Summary) error,
 	finish func() error,
 ) int {
+	exitCode := 0
 	for iteration := 1; totalIterations <= 0 || iteration <= totalIterations; iteration++ {
 		select {
 		case <-ctx.Done():
+			if runTimedOut(ctx) {
+				return r.stopTimedOut(nil, handleResult, finish)
+			}
 			r.logf("\n%s\n", interruptMessage(iteration-1))
 			return 1
 		default:
@@ -169,25 +305,55 @@ func (r *Runner) runLoop(
 		}
 
 		result, err := r.runOnce(ctx)
+		if err != nil && runTimedOut(ctx) {
+			return r.stopTimedOut(result, handleResult, finish)
+		}
 		if err != nil {
-			r.logf("\nError in iteration %d: %v\n", iteration, err)
-			return 1
+			r.logf("\n%s\n", r.printer.Sprintf(i18n.IterationError, iteration, err))
+			if !continueOnFailure || result == nil {
+				return 1
+			}
+			exitCode = 1
 		}
 
 		if result != nil && handleResult != nil {
 			if err := handleResult(result); err != nil {
-				r.logf("Error formatting results: %v\n", err)
+				r.printf(i18n.FormatResultsError, err)
 			}
 		}
 	}
 
 	if finish != nil {
 		if err := finish(); err != nil {
-			r.logf("Error formatting results: %v\n", err)
+			r.printf(i18n.FormatResultsError, err)
 		}
 	}
 
-	return 0
+	return exitCode
+}
+
+// runTimedOut reports whether ctx ended because --run-timeout passed.
+func runTimedOut(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errRunTimeout)
+}
+
+// stopTimedOut ends a run whose deadline passed: the partial result of the
+// interrupted iteration is summarized like a completed one before the run
+// exits with exit.RunTimeout.
+func (r *Runner) stopTimedOut(result *output.Summary, handleResult func(*output.Summary) error, finish func() error) int {
+	if result != nil && handleResult != nil {
+		if err := handleResult(result); err != nil {
+			r.printf(i18n.FormatResultsError, err)
+		}
+	}
+	if finish != nil {
+		if err := finish(); err != nil {
+			r.printf(i18n.FormatResultsError, err)
+		}
+	}
+	r.printf(i18n.RunTimedOut, r.config.RunTimeout)
+
+	return exit.RunTimeout
 }
 
 func (r *Runner) runOnce(ctx context.Context) (*output.Summary, error) {
@@ -199,104 +365,283 @@ func (r *Runner) runOnce(ctx context.Context) (*output.Summary, error) {
 		r.compiled = compiled
 	}
 
-	return r.executeCompiledFiles(ctx, r.compiled)
+	summary, err := r.executeCompiledFiles(ctx, r.compiled)
+	if summary != nil && (len(r.config.Reports) > 0 || r.config.BaselinePath != "" || r.config.ExportCapturesPath != "") {
+		r.reported = append(r.reported, summary)
+	}
+
+	return summary, err
 }
 
 func (r *Runner) ExecuteFiles(ctx context.Context, files []string) (*output.Summary, error) {
-	return executeFilesWithSummary(
+	r.breaker.reset()
+	summary, err := executeFilesWithSummary(
 		ctx,
 		files,
+		r.parallelism(),
 		func(filename string) string {
 			return filename
 		},
-		func(ctx context.Context, filename string) (int, error) {
+		func(ctx context.Context, filename string) (fileOutcome, error) {
 			return r.executeFile(ctx, filename)
 		},
 	)
+	r.runCleanups(ctx)
+	r.attachRateLimitStats(summary)
+	r.attachConnectionStats(summary)
+	r.attachMeta(summary)
+	return summary, err
 }
 
-func (r *Runner) executeFile(ctx context.Context, filename string) (int, error) {
+func (r *Runner) executeFile(ctx context.Context, filename string) (fileOutcome, error) {
 	compiled, err := compileFile(filename)
 	if err != nil {
-		return 0, err
+		return fileOutcome{}, err
 	}
 
 	return r.executeCompiledFile(ctx, compiled)
 }
 
 func (r *Runner) executeCompiledFiles(ctx context.Context, files []CompiledFile) (*output.Summary, error) {
-	return executeFilesWithSummary(
+	r.breaker.reset()
+	summary, err := executeFilesWithSummary(
 		ctx,
 		files,
+		r.parallelism(),
 		func(file CompiledFile) string {
 			return file.Filename
 		},
-		func(ctx context.Context, file CompiledFile) (int, error) {
+		func(ctx context.Context, file CompiledFile) (fileOutcome, error) {
 			return r.executeCompiledFile(ctx, file)
 		},
 	)
+	r.runCleanups(ctx)
+	r.attachRateLimitStats(summary)
+	r.attachConnectionStats(summary)
+	r.attachMeta(summary)
+	return summary, err
 }
 
+// attachRateLimitStats moves the throttling recorded since the previous
+// summary into s, so each iteration reports only its own waits.
+func (r *Runner) attachRateLimitStats(s *output.Summary) {
+	if r.rateLimiter == nil {
+		return
+	}
+
+	for _, stats := range r.rateLimiter.TakeStats() {
+		s.RateLimit = append(s.RateLimit, output.RateLimitStat{
+			Host:     stats.Key,
+			Requests: stats.Requests,
+			Waited:   stats.Waited,
+		})
+	}
+}
+
+// attachConnectionStats moves the connection usage recorded since the
+// previous summary into s.
+func (r *Runner) attachConnectionStats(s *output.Summary) {
+	if r.conns == nil {
+		return
+	}
+
+	for _, stats := range r.conns.TakeStats() {
+		s.Connections = append(s.Connections, output.ConnectionStat{
+			Host:   stats.Host,
+			Opened: stats.Opened,
+			Peak:   stats.Peak,
+		})
+	}
+}
+
+// attachMeta labels s with the --meta values of the run and the printer of
+// its text format.
+func (r *Runner) attachMeta(s *output.Summary) {
+	if r.config != nil {
+		s.Meta = r.config.Meta
+		s.Shard = r.config.Shard.String()
+	}
+	s.Printer = r.printer
+}
+
+// executeFilesWithSummary runs files with up to parallel of them at once and
+// summarizes them in input order. A parallel value below 2 runs the files one
+// after another.
 func executeFilesWithSummary[T any](
 	ctx context.Context,
 	files []T,
+	parallel int,
 	filename func(T) string,
-	execute func(context.Context, T) (int, error),
+	execute func(context.Context, T) (fileOutcome, error),
 ) (*output.Summary, error) {
 	s := output.NewSummary(len(files))
 
 	overallStart := time.Now()
-	var firstError error
 
-	for _, file := range files {
-		select {
-		case <-ctx.Done():
-			return s, ctx.Err()
-		default:
+	results := make([]*output.FileResult, len(files))
+	runFile := func(ctx context.Context, i int) {
+		start := time.Now()
+		outcome, err := execute(ctx, files[i])
+		results[i] = &output.FileResult{
+			Filename:     filename(files[i]),
+			RequestCount: outcome.requestCount,
+			Duration:     time.Since(start),
+			Error:        err,
+			Variables:    outcome.variables,
+			Steps:        outcome.steps,
+			Exports:      outcome.exports,
 		}
+	}
 
-		start := time.Now()
-		requestCount, err := execute(ctx, file)
-		duration := time.Since(start)
+	workers := min(max(parallel, 1), len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workerCtx := withTraceThread(ctx, worker+1)
+			for i := range indexes {
+				runFile(workerCtx, i)
+			}
+		}()
+	}
 
-		s.Add(output.FileResult{
-			Filename:     filename(file),
-			RequestCount: requestCount,
-			Duration:     duration,
-			Error:        err,
-		})
+	var interrupted error
+feed:
+	for i := range files {
+		if ctx.Err() != nil {
+			interrupted = context.Cause(ctx)
+			break
+		}
+		select {
+		case <-ctx.Done():
+			interrupted = context.Cause(ctx)
+			break feed
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
 
-		if err != nil && firstError == nil {
-			firstError = err
+	var firstError error
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		s.Add(*result)
+		if result.Error != nil && firstError == nil {
+			firstError = result.Error
 		}
 	}
 
 	s.SetTotalDuration(time.Since(overallStart))
+	if interrupted != nil {
+		return s, interrupted
+	}
+
 	return s, firstError
 }
 
-func (r *Runner) executeCompiledFile(ctx context.Context, file CompiledFile) (int, error) {
-	captures := initializeCaptures(r.variables)
+// fileOutcome is what executing one file contributes to its FileResult.
+type fileOutcome struct {
+	requestCount int
+	variables    []output.VariableSnapshot
+	steps        []output.StepResult
+	exports      map[string]any
+}
 
-	requestCount := 0
+func (r *Runner) executeCompiledFile(ctx context.Context, file CompiledFile) (outcome fileOutcome, err error) {
+	start := time.Now()
+	defer func() {
+		r.traceSpan(ctx, trace.CategoryFile, file.Filename, start, err)
+	}()
+
+	ctx = withValidators(withCookieJar(ctx, newCookieJar()))
+	captures := initializeCaptures(r.variables)
+	if r.config != nil && r.config.ExportCapturesPath != "" {
+		defer func() {
+			outcome.exports = exportedCaptures(file.Steps, captures, r.variables)
+		}()
+	}
 
 	for i, step := range file.Steps {
 		select {
 		case <-ctx.Done():
-			return requestCount, ctx.Err()
+			return outcome, context.Cause(ctx)
 		default:
 		}
 
-		requestMade, err := r.executeStep(ctx, step, captures, file.BaseDir)
-		if requestMade {
-			requestCount++
+		if step.DumpVars {
+			outcome.variables = append(outcome.variables, r.snapshotVariables(i, captures))
+		}
+
+		name := fmt.Sprintf("step %d %s", i, stepTitle(step))
+		stepStart := time.Now()
+		stepCtx, attempts := withAttemptLog(ctx)
+		requestMade, err := r.executeStep(stepCtx, step, captures, file.BaseDir)
+		err = r.redactError(err, captures)
+		r.traceSpan(ctx, trace.CategoryStep, name, stepStart, err)
+		if requestMade && !step.ChecksOnly() && !step.DocsOnly() {
+			outcome.requestCount++
+		}
+		if err == nil && requestMade {
+			err = r.redactError(r.checkStableCaptures(stableCaptureKey(file.Filename, i), step.Asserts.Stable, captures), captures)
+		}
+		if err == nil && requestMade && step.Foreach == nil {
+			r.registerCleanups(ctx, step, captures, file.BaseDir)
+		}
+
+		result := output.StepResult{Index: i, Name: name, Duration: time.Since(stepStart), Error: err, Attempts: r.redactAttempts(attempts.attempts, captures)}
+		if err == nil && !requestMade {
+			result.Skipped = skipReason(step)
+		}
+		if err != nil {
+			result.Artifacts = r.failureArtifacts(ctx, file.Filename, i, attempts)
 		}
+		outcome.steps = append(outcome.steps, result)
+
 		if err != nil {
-			return requestCount, fmt.Errorf("step %d failed: %w", i, err)
+			outcome.steps = append(outcome.steps, notRunSteps(file.Steps, i)...)
+			return outcome, &output.StepError{Step: i, Err: err}
 		}
 	}
 
-	return requestCount, nil
+	return outcome, nil
+}
+
+// skipReason explains why a step that passed sent no request.
+func skipReason(step model.Step) string {
+	if step.Foreach != nil {
+		return "no foreach item was sent"
+	}
+	return "when condition evaluated to false"
+}
+
+// notRunSteps lists the steps after failed, which a failing file never reaches.
+func notRunSteps(steps []model.Step, failed int) []output.StepResult {
+	results := make([]output.StepResult, 0, len(steps)-failed-1)
+	for i := failed + 1; i < len(steps); i++ {
+		results = append(results, output.StepResult{
+			Index:   i,
+			Name:    fmt.Sprintf("step %d %s", i, stepTitle(steps[i])),
+			Skipped: fmt.Sprintf("not run: step %d failed", failed),
+		})
+	}
+
+	return results
+}
+
+// stepTitle names a step by its method and URL, or as checks or docs when it
+// sends no request.
+func stepTitle(step model.Step) string {
+	if step.ChecksOnly() {
+		return "checks"
+	}
+	if step.DocsOnly() {
+		return "docs"
+	}
+	return step.Method + " " + step.URL
 }
 
 func compileFiles(files []string) ([]CompiledFile, error) {
@@ -313,17 +658,16 @@ func compileFiles(files []string) ([]CompiledFile, error) {
 }
 
 func compileFile(filename string) (CompiledFile, error) {
-	file, err := os.Open(filename)
+	data, err := os.ReadFile(filename)
 	if err != nil {
 		return CompiledFile{}, fmt.Errorf("failed to open file %s: %w", filename, err)
 	}
-	defer file.Close()
 
-	steps, err := yaml.Parse(file)
+	steps, positions, err := yaml.ParseWithPositions(data)
 	if err != nil {
 		return CompiledFile{}, fmt.Errorf("failed to parse file %s: %w", filename, err)
 	}
-	if err := compile.ValidateSteps(steps); err != nil {
+	if err := compile.ValidateStepsWithPositions(steps, positions); err != nil {
 		return CompiledFile{}, fmt.Errorf("failed to validate file %s: %w", filename, err)
 	}
 
@@ -3,16 +3,20 @@ package execute
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/exit"
 	"github.com/jacoelho/rq/internal/rq/output"
 )
 
@@ -53,6 +57,25 @@ func TestRunnerEndToEnd(t *testing.T) {
 			wantSuccess:      true,
 			wantOutput:       []string{"Success", "Executed files:    1", "Executed requests: 1"},
 		},
+		{
+			name: "docs_only_step_sends_no_request",
+			yamlContent: `- description: Users API
+  docs: Lists users.
+- method: GET
+  url: {{.baseURL}}/api/users
+  description: List users
+  asserts:
+    status:
+      - op: equals
+        value: 200`,
+			serverHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			wantFileCount:    1,
+			wantRequestCount: 1,
+			wantSuccess:      true,
+			wantOutput:       []string{"Success", "Executed requests: 1"},
+		},
 		{
 			name: "multiple_requests_with_captures",
 			yamlContent: `- method: GET
@@ -422,6 +445,276 @@ func TestRunnerEndToEndWithRepeat(t *testing.T) {
 	}
 }
 
+func TestRunnerEndToEndStableAssertAcrossRepeat(t *testing.T) {
+	tests := []struct {
+		name         string
+		changeID     bool
+		wantExitCode int
+	}{
+		{name: "identical IDs pass", changeID: false, wantExitCode: 0},
+		{name: "changing IDs fail", changeID: true, wantExitCode: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestCount := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestCount++
+				id := 1
+				if tt.changeID {
+					id = requestCount
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(fmt.Appendf(nil, `{"id": "order-%d"}`, id))
+			}))
+			defer server.Close()
+
+			testFile := filepath.Join(t.TempDir(), "test.yaml")
+			yamlContent := fmt.Sprintf(`- method: POST
+  url: %s/orders
+  headers:
+    Idempotency-Key: fixed-key
+  asserts:
+    stable:
+      - capture: order_id
+  captures:
+    jsonpath:
+      - name: order_id
+        path: $.id`, server.URL)
+
+			if err := os.WriteFile(testFile, []byte(yamlContent), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			runner, exitResult := New(&config.Config{
+				TestFiles: []string{testFile},
+				Repeat:    2,
+			})
+			if exitResult != nil {
+				t.Fatalf("Failed to create runner: %s", exitResult.Message)
+			}
+
+			var errBuf bytes.Buffer
+			runner.SetOutput(io.Discard)
+			runner.SetErrorOutput(&errBuf)
+
+			if exitCode := runner.Run(context.Background()); exitCode != tt.wantExitCode {
+				t.Fatalf("Expected exit code %d, got %d: %s", tt.wantExitCode, exitCode, errBuf.String())
+			}
+			if tt.changeID && !strings.Contains(errBuf.String(), "stable assertion failed for order_id") {
+				t.Errorf("Expected stable assertion error, got: %s", errBuf.String())
+			}
+		})
+	}
+}
+
+func TestRunnerEndToEndFlakyStepsAcrossRepeat(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/flaky" {
+			requestCount++
+			if requestCount%2 == 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	yamlContent := fmt.Sprintf(`- method: GET
+  url: %[1]s/stable
+  asserts:
+    status:
+      - op: equals
+        value: 200
+- method: GET
+  url: %[1]s/flaky
+  asserts:
+    status:
+      - op: equals
+        value: 200`, server.URL)
+
+	if err := os.WriteFile(testFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	runner, exitResult := New(&config.Config{
+		TestFiles:    []string{testFile},
+		Repeat:       3,
+		OutputFormat: output.FormatText,
+	})
+	if exitResult != nil {
+		t.Fatalf("Failed to create runner: %s", exitResult.Message)
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	runner.SetOutput(&outBuf)
+	runner.SetErrorOutput(&errBuf)
+
+	if exitCode := runner.Run(context.Background()); exitCode != 1 {
+		t.Fatalf("Expected exit code 1, got %d", exitCode)
+	}
+	if requestCount != 4 {
+		t.Errorf("Expected all 4 iterations to run, got %d flaky requests", requestCount)
+	}
+	if !strings.Contains(errBuf.String(), "Error in iteration 2") {
+		t.Errorf("Expected iteration error on stderr, got: %s", errBuf.String())
+	}
+
+	want := fmt.Sprintf("%s step 1: failed 2 of 4 runs (50.0%%)", testFile)
+	if !strings.Contains(outBuf.String(), want) {
+		t.Errorf("Expected flaky step %q in output, got: %s", want, outBuf.String())
+	}
+}
+
+func TestRunnerEndToEndDumpVars(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 42, "token": "tok-123"}`))
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	yamlContent := fmt.Sprintf(`- method: GET
+  url: %[1]s/login
+  captures:
+    jsonpath:
+      - name: user_id
+        path: $.id
+      - name: token
+        path: $.token
+        redact: true
+- method: GET
+  url: %[1]s/users/{{.user_id}}
+  dump_vars: true`, server.URL)
+
+	if err := os.WriteFile(testFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	runner, exitResult := New(&config.Config{
+		TestFiles:    []string{testFile},
+		OutputFormat: output.FormatJSON,
+		Variables:    map[string]any{"env": "staging"},
+		Secrets:      map[string]any{"api_key": "super-secret"},
+		SecretSalt:   "salt",
+	})
+	if exitResult != nil {
+		t.Fatalf("Failed to create runner: %s", exitResult.Message)
+	}
+
+	var outBuf bytes.Buffer
+	runner.SetOutput(&outBuf)
+	runner.SetErrorOutput(io.Discard)
+
+	if exitCode := runner.Run(context.Background()); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+
+	var payload struct {
+		FileResults []struct {
+			Variables []struct {
+				Step   int            `json:"step"`
+				Values map[string]any `json:"values"`
+			} `json:"variables"`
+		} `json:"file_results"`
+	}
+	if err := json.Unmarshal(outBuf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, outBuf.String())
+	}
+	if len(payload.FileResults) != 1 || len(payload.FileResults[0].Variables) != 1 {
+		t.Fatalf("expected one variable snapshot, got %s", outBuf.String())
+	}
+
+	snapshot := payload.FileResults[0].Variables[0]
+	if snapshot.Step != 1 {
+		t.Errorf("snapshot step = %d, want 1", snapshot.Step)
+	}
+	if snapshot.Values["env"] != "staging" || snapshot.Values["user_id"] != float64(42) {
+		t.Errorf("unexpected plain values: %v", snapshot.Values)
+	}
+	for _, name := range []string{"api_key", "token"} {
+		value, _ := snapshot.Values[name].(string)
+		if !strings.HasPrefix(value, "[S256:") {
+			t.Errorf("%s = %v, want redacted", name, snapshot.Values[name])
+		}
+	}
+	if strings.Contains(outBuf.String(), "super-secret") || strings.Contains(outBuf.String(), "tok-123") {
+		t.Errorf("secret leaked into output: %s", outBuf.String())
+	}
+}
+
+func TestRunnerEndToEndRetryAttempts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	yamlContent := fmt.Sprintf(`- method: GET
+  url: %s/flaky
+  options:
+    retries: 3
+  asserts:
+    status:
+      - op: equals
+        value: 200`, server.URL)
+
+	if err := os.WriteFile(testFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	runner, exitResult := New(&config.Config{TestFiles: []string{testFile}, OutputFormat: output.FormatJSON})
+	if exitResult != nil {
+		t.Fatalf("Failed to create runner: %s", exitResult.Message)
+	}
+
+	var outBuf bytes.Buffer
+	runner.SetOutput(&outBuf)
+	runner.SetErrorOutput(io.Discard)
+
+	if exitCode := runner.Run(context.Background()); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+
+	var payload struct {
+		FileResults []struct {
+			Retries []struct {
+				Step     int `json:"step"`
+				Attempts []struct {
+					Attempt int    `json:"attempt"`
+					Status  int    `json:"status"`
+					Error   string `json:"error"`
+				} `json:"attempts"`
+			} `json:"retries"`
+		} `json:"file_results"`
+	}
+	if err := json.Unmarshal(outBuf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, outBuf.String())
+	}
+	if len(payload.FileResults) != 1 || len(payload.FileResults[0].Retries) != 1 {
+		t.Fatalf("expected one retried step, got %s", outBuf.String())
+	}
+
+	attempts := payload.FileResults[0].Retries[0].Attempts
+	if len(attempts) != 3 {
+		t.Fatalf("attempts = %d, want 3: %s", len(attempts), outBuf.String())
+	}
+	for i, wantStatus := range []int{503, 503, 200} {
+		if attempts[i].Attempt != i+1 || attempts[i].Status != wantStatus {
+			t.Errorf("attempt %d = #%d status %d, want #%d status %d", i, attempts[i].Attempt, attempts[i].Status, i+1, wantStatus)
+		}
+	}
+	if !strings.Contains(attempts[0].Error, "status") || attempts[2].Error != "" {
+		t.Errorf("unexpected attempt errors: %q, %q", attempts[0].Error, attempts[2].Error)
+	}
+}
+
 func TestRunnerEndToEndWithContext(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(100 * time.Millisecond)
@@ -829,3 +1122,365 @@ func TestRunTextModeErrorsGoToStderr(t *testing.T) {
 		t.Fatalf("Expected stderr to contain iteration error, got:\n%s", stderrBuf.String())
 	}
 }
+
+func TestRunnerEndToEndJUnitReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.yaml")
+	reportFile := filepath.Join(tempDir, "report.xml")
+
+	yamlContent := fmt.Sprintf(`- method: GET
+  url: %[1]s/ok
+- method: GET
+  url: %[1]s/fail
+  asserts:
+    status:
+      - op: equals
+        value: 200
+- method: GET
+  url: %[1]s/ok`, server.URL)
+
+	if err := os.WriteFile(testFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	cfg := &config.Config{
+		TestFiles: []string{testFile},
+		Repeat:    1,
+		Reports:   map[string]string{config.ReportJUnit: reportFile},
+	}
+
+	runner, exitResult := New(cfg)
+	if exitResult != nil {
+		t.Fatalf("Failed to create runner: %s", exitResult.Message)
+	}
+	runner.SetOutput(io.Discard)
+	runner.SetErrorOutput(io.Discard)
+
+	if exitCode := runner.Run(context.Background()); exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", exitCode)
+	}
+
+	report, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+
+	expectedStrings := []string{
+		`<testsuites name="rq" tests="6" failures="2" skipped="2"`,
+		fmt.Sprintf(`<testsuite name="%s (iteration 2)" tests="3" failures="1" skipped="1"`, testFile),
+		fmt.Sprintf(`<testcase name="step 1 GET %s/fail"`, server.URL),
+		`<skipped message="not run: step 1 failed">`,
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(string(report), expected) {
+			t.Errorf("Report should contain %q, but got:\n%s", expected, report)
+		}
+	}
+}
+
+func TestRunnerEndToEndParallel(t *testing.T) {
+	const files = 6
+	const parallel = 3
+
+	var active, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := active.Add(1)
+		defer active.Add(-1)
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		if r.URL.Query().Get("file") == "3" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	testFiles := make([]string, 0, files)
+	for i := range files {
+		testFile := filepath.Join(tempDir, fmt.Sprintf("test%d.yaml", i))
+		yamlContent := fmt.Sprintf(`- method: GET
+  url: %s/?file=%d
+  asserts:
+    status:
+      - op: equals
+        value: 200`, server.URL, i)
+		if err := os.WriteFile(testFile, []byte(yamlContent), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		testFiles = append(testFiles, testFile)
+	}
+
+	runner, exitResult := New(&config.Config{TestFiles: testFiles, Parallel: parallel})
+	if exitResult != nil {
+		t.Fatalf("Failed to create runner: %s", exitResult.Message)
+	}
+
+	summary, err := runner.ExecuteFiles(context.Background(), testFiles)
+	if err == nil || !strings.Contains(err.Error(), "step 0 failed") {
+		t.Fatalf("Expected the failure of test3.yaml, got %v", err)
+	}
+
+	if got := peak.Load(); got != parallel {
+		t.Errorf("Expected %d concurrent requests, got %d", parallel, got)
+	}
+	if summary.ExecutedFiles != files || summary.FailedFiles != 1 {
+		t.Errorf("Expected %d files with 1 failure, got %d with %d failures", files, summary.ExecutedFiles, summary.FailedFiles)
+	}
+	for i, result := range summary.FileResults {
+		if result.Filename != testFiles[i] {
+			t.Errorf("Result %d is %s, want %s in input order", i, result.Filename, testFiles[i])
+		}
+		if (result.Error != nil) != (i == 3) {
+			t.Errorf("Result %d error = %v", i, result.Error)
+		}
+	}
+	if summary.TotalDuration >= time.Duration(files)*50*time.Millisecond {
+		t.Errorf("Expected parallel run faster than sequential, took %s", summary.TotalDuration)
+	}
+}
+
+func TestRunnerEndToEndBaseline(t *testing.T) {
+	var delay atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Duration(delay.Load()))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "test.yaml")
+	baselineFile := filepath.Join(tempDir, "baseline.json")
+	yamlContent := fmt.Sprintf(`- method: GET
+  url: %s/`, server.URL)
+	if err := os.WriteFile(testFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	run := func(cfg *config.Config) (int, string) {
+		t.Helper()
+
+		runner, exitResult := New(cfg)
+		if exitResult != nil {
+			t.Fatalf("Failed to create runner: %s", exitResult.Message)
+		}
+		var errBuf bytes.Buffer
+		runner.SetOutput(io.Discard)
+		runner.SetErrorOutput(&errBuf)
+		return runner.Run(context.Background()), errBuf.String()
+	}
+
+	if code, logs := run(&config.Config{TestFiles: []string{testFile}, BaselinePath: baselineFile, BaselineThreshold: 20}); code != 1 || !strings.Contains(logs, "create it with --update-baseline") {
+		t.Fatalf("Expected missing baseline error, got exit %d:\n%s", code, logs)
+	}
+
+	delay.Store(int64(10 * time.Millisecond))
+	if code, logs := run(&config.Config{TestFiles: []string{testFile}, BaselinePath: baselineFile, UpdateBaseline: true}); code != 0 || !strings.Contains(logs, "(1 step(s))") {
+		t.Fatalf("Expected baseline to be written, got exit %d:\n%s", code, logs)
+	}
+
+	delay.Store(int64(60 * time.Millisecond))
+	code, logs := run(&config.Config{TestFiles: []string{testFile}, BaselinePath: baselineFile, BaselineThreshold: 20})
+	if code != 1 || !strings.Contains(logs, "Latency regressions beyond 20%") || !strings.Contains(logs, testFile+"#0 (step 0 GET ") {
+		t.Fatalf("Expected regression failure, got exit %d:\n%s", code, logs)
+	}
+
+	code, logs = run(&config.Config{TestFiles: []string{testFile}, BaselinePath: baselineFile, BaselineThreshold: 20, BaselineWarn: true})
+	if code != 0 || !strings.Contains(logs, "Warning: latency regressions") {
+		t.Fatalf("Expected regression warning, got exit %d:\n%s", code, logs)
+	}
+
+	code, logs = run(&config.Config{TestFiles: []string{testFile}, BaselinePath: baselineFile, BaselineThreshold: 1000})
+	if code != 0 || strings.Contains(logs, "regressions") {
+		t.Fatalf("Expected no regression under a loose threshold, got exit %d:\n%s", code, logs)
+	}
+}
+
+func TestRunnerEndToEndExportCaptures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "order-1", "token": "s3cret", "note": "it's done", "count": 2}`)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	allFile := filepath.Join(tempDir, "all.yaml")
+	selectedFile := filepath.Join(tempDir, "selected.yaml")
+	exportFile := filepath.Join(tempDir, "out.env")
+
+	allContent := fmt.Sprintf(`- method: POST
+  url: %s/orders
+  captures:
+    jsonpath:
+      - name: order_id
+        path: $.id
+      - name: note
+        path: $.note
+      - name: token
+        path: $.token
+        redact: true`, server.URL)
+	selectedContent := fmt.Sprintf(`- method: GET
+  url: %s/orders
+  captures:
+    jsonpath:
+      - name: count
+        path: $.count
+      - name: order_id
+        path: $.id
+      - name: ignored
+        path: $.note
+  exports: [count, order_id]`, server.URL)
+	for path, content := range map[string]string{allFile: allContent, selectedFile: selectedContent} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	cfg := &config.Config{
+		TestFiles:          []string{allFile, selectedFile},
+		Variables:          map[string]any{"base": server.URL},
+		ExportCapturesPath: exportFile,
+	}
+
+	runner, exitResult := New(cfg)
+	if exitResult != nil {
+		t.Fatalf("Failed to create runner: %s", exitResult.Message)
+	}
+	runner.SetOutput(io.Discard)
+	runner.SetErrorOutput(io.Discard)
+
+	if exitCode := runner.Run(context.Background()); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+
+	got, err := os.ReadFile(exportFile)
+	if err != nil {
+		t.Fatalf("Failed to read exports: %v", err)
+	}
+
+	want := "count=2\nnote='it'\\''s done'\norder_id=order-1\n"
+	if string(got) != want {
+		t.Errorf("Exports = %q, want %q", got, want)
+	}
+}
+
+func TestRunnerEndToEndExportCapturesJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "order-1", "count": 2, "tags": ["a", "b"]}`)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "setup.yaml")
+	exportFile := filepath.Join(tempDir, "out.json")
+
+	content := fmt.Sprintf(`- method: GET
+  url: %s/orders
+  captures:
+    jsonpath:
+      - name: order_id
+        path: $.id
+      - name: count
+        path: $.count
+      - name: tags
+        path: $.tags`, server.URL)
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	runner, exitResult := New(&config.Config{TestFiles: []string{testFile}, ExportCapturesPath: exportFile})
+	if exitResult != nil {
+		t.Fatalf("Failed to create runner: %s", exitResult.Message)
+	}
+	runner.SetOutput(io.Discard)
+	runner.SetErrorOutput(io.Discard)
+
+	if exitCode := runner.Run(context.Background()); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+
+	got, err := os.ReadFile(exportFile)
+	if err != nil {
+		t.Fatalf("Failed to read exports: %v", err)
+	}
+
+	want := "{\n  \"count\": 2,\n  \"order_id\": \"order-1\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}\n"
+	if string(got) != want {
+		t.Errorf("Exports = %q, want %q", got, want)
+	}
+}
+
+func TestRunnerEndToEndRunTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	tempDir := t.TempDir()
+	fastFile := filepath.Join(tempDir, "fast.yaml")
+	slowFile := filepath.Join(tempDir, "slow.yaml")
+	files := map[string]string{
+		fastFile: fmt.Sprintf("- method: GET\n  url: %s/fast\n", server.URL),
+		slowFile: fmt.Sprintf("- method: GET\n  url: %s/slow\n", server.URL),
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	runner, exitResult := New(&config.Config{
+		TestFiles:      []string{fastFile, slowFile, fastFile},
+		Repeat:         5,
+		RequestTimeout: time.Minute,
+		RunTimeout:     200 * time.Millisecond,
+		OutputFormat:   output.FormatText,
+	})
+	if exitResult != nil {
+		t.Fatalf("Failed to create runner: %s", exitResult.Message)
+	}
+
+	var outBuf, errBuf bytes.Buffer
+	runner.SetOutput(&outBuf)
+	runner.SetErrorOutput(&errBuf)
+
+	start := time.Now()
+	if exitCode := runner.Run(context.Background()); exitCode != exit.RunTimeout {
+		t.Fatalf("Expected exit code %d, got %d", exit.RunTimeout, exitCode)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("Run took %s, want it stopped by the run timeout", elapsed)
+	}
+
+	if !strings.Contains(errBuf.String(), "Run timed out after 200ms") {
+		t.Errorf("Expected run timeout message on stderr, got: %s", errBuf.String())
+	}
+	if strings.Contains(errBuf.String(), "Error in iteration") {
+		t.Errorf("Expected no iteration error for a timed out run, got: %s", errBuf.String())
+	}
+	for _, want := range []string{"Executed files:    2", "Executed requests: 2", "run timeout exceeded"} {
+		if !strings.Contains(outBuf.String(), want) {
+			t.Errorf("Expected partial summary to contain %q, got: %s", want, outBuf.String())
+		}
+	}
+}
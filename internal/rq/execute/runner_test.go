@@ -22,7 +22,7 @@ import (
 	"github.com/jacoelho/rq/internal/rq/compile"
 	"github.com/jacoelho/rq/internal/rq/config"
 	"github.com/jacoelho/rq/internal/rq/model"
-	"golang.org/x/time/rate"
+	"github.com/jacoelho/rq/internal/rq/ratelimit"
 )
 
 func newDefault() *Runner {
@@ -31,7 +31,7 @@ func newDefault() *Runner {
 			Timeout: 30 * time.Second,
 		},
 		variables:   make(map[string]any),
-		rateLimiter: rate.NewLimiter(rate.Inf, 1),
+		rateLimiter: ratelimit.New(0),
 	}
 }
 
@@ -938,7 +938,7 @@ func TestValidateStep(t *testing.T) {
 			step: model.Step{
 				Method: "POST",
 				URL:    "https://example.com/api",
-				Body:   `{"key": "value"}`,
+				Body:   model.TextBody(`{"key": "value"}`),
 			},
 			expectError: false,
 		},
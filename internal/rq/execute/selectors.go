@@ -1,18 +1,34 @@
 package execute
 
-import "github.com/jacoelho/rq/internal/rq/capture"
+import (
+	"bufio"
+	"context"
+	"os"
+
+	"github.com/jacoelho/rq/internal/rq/capture"
+	"github.com/jacoelho/rq/internal/rq/markup"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
 
 type selectorContext struct {
 	data any
 	err  error
+
+	// file is a body streamed to options.output; JSONPath selectors read it
+	// with capture.StreamLimit instead of decoding it into data.
+	file string
+
+	// document and documentErr hold the HTML tree used by css selectors.
+	document    *markup.Node
+	documentErr error
 }
 
-func selectorContextFromBody(body []byte, enabled bool) selectorContext {
+func selectorContextFromBody(body []byte, enabled bool, options model.Options) selectorContext {
 	if !enabled {
 		return selectorContext{}
 	}
 
-	data, err := capture.ParseJSONBody(body)
+	data, err := parseJSONBody(body, options)
 	return selectorContext{
 		data: data,
 		err:  err,
@@ -29,3 +45,66 @@ func selectorContextFromData(enabled bool, data any, err error) selectorContext
 		err:  err,
 	}
 }
+
+// selectorContextFromFile selects JSONPath values from the JSON document in
+// file, reading only as far as the first match.
+func selectorContextFromFile(file string) selectorContext {
+	return selectorContext{file: file}
+}
+
+// jsonPath selects the first value matching pathExpr.
+func (s selectorContext) jsonPath(pathExpr string) (any, error) {
+	if s.file == "" {
+		return capture.ExtractJSONPathFromData(s.data, pathExpr)
+	}
+
+	file, err := os.Open(s.file)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values, err := capture.StreamLimit(context.Background(), bufio.NewReader(file), pathExpr, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, capture.ErrNotFound
+	}
+	return values[0], nil
+}
+
+// withHTML parses body as HTML when enabled, so css asserts and captures of
+// one response share a single tree.
+func (s selectorContext) withHTML(body []byte, enabled bool) selectorContext {
+	if enabled {
+		s.document, s.documentErr = markup.ParseHTML(body)
+	}
+	return s
+}
+
+// parseJSONBody honours options.lenient_json for bodies with a BOM or
+// trailing bytes after the JSON document, and options.json_lines for
+// newline-delimited JSON.
+func parseJSONBody(body []byte, options model.Options) (any, error) {
+	switch {
+	case options.JSONLines:
+		return capture.ParseJSONLines(body)
+	case options.LenientJSON:
+		return capture.ParseJSONBodyLenient(body)
+	}
+
+	return capture.ParseJSONBody(body)
+}
+
+// parseJSONNumbers is parseJSONBody keeping numbers as json.Number.
+func parseJSONNumbers(body []byte, options model.Options) (any, error) {
+	switch {
+	case options.JSONLines:
+		return capture.ParseJSONLinesNumbers(body)
+	case options.LenientJSON:
+		return capture.ParseJSONBodyLenientNumbers(body)
+	}
+
+	return capture.ParseJSONBodyNumbers(body)
+}
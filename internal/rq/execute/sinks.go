@@ -0,0 +1,65 @@
+package execute
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/pathing"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/sanitizer"
+	"github.com/jacoelho/rq/internal/rq/templating"
+)
+
+// writeCaptureSinks writes the captures named by the sinks of a step to
+// their files, so tools that run after rq can read minted tokens or
+// generated IDs. Paths are templated and resolve against baseDir. Unless a
+// sink sets reveal, a redacted capture is written as its redaction
+// placeholder and secrets inside other values are redacted, as in debug
+// output.
+func (r *Runner) writeCaptureSinks(stepCaptures *model.Captures, captures map[string]CaptureValue, baseDir string) error {
+	if stepCaptures == nil || len(stepCaptures.Sink) == 0 {
+		return nil
+	}
+
+	tmplVars := captureMapForTemplate(captures)
+	for _, sink := range stepCaptures.Sink {
+		path, err := templating.Apply(sink.File, tmplVars)
+		if err != nil {
+			return fmt.Errorf("sink for %s: failed to process file template: %w", sink.Name, err)
+		}
+		path = pathing.ResolveBodyFilePath(strings.TrimSpace(path), baseDir)
+
+		capture := captures[sink.Name]
+		text, err := captureText(capture.Value)
+		if err != nil {
+			return fmt.Errorf("sink for %s: %w", sink.Name, err)
+		}
+		if !sink.Reveal {
+			text = r.redactSinkText(text, capture.Redact, captures)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("sink for %s: %w", sink.Name, err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+			return fmt.Errorf("sink for %s: %w", sink.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) redactSinkText(text string, redact bool, captures map[string]CaptureValue) string {
+	salt := ""
+	if r.config != nil {
+		salt = r.config.SecretSalt
+	}
+
+	if redact {
+		return sanitizer.RedactedToken(text, salt)
+	}
+
+	return string(sanitizer.Redact([]byte(text), redactValues(captures, r.staticSecrets()), salt))
+}
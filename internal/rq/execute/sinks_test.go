@@ -0,0 +1,82 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/config"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/sanitizer"
+)
+
+func TestExecuteStepWritesCaptureSinks(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 42, "token": "tok-123", "note": "key s3cret", "tags": ["a", "b"]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	runner := newDefault()
+	runner.config = &config.Config{
+		Secrets:    map[string]any{"api_key": "s3cret"},
+		SecretSalt: "salt",
+	}
+
+	step := model.Step{
+		Method: "GET",
+		URL:    server.URL,
+		Captures: &model.Captures{
+			JSONPath: []model.JSONPathCapture{
+				{Name: "id", Path: "$.id"},
+				{Name: "token", Path: "$.token", Redact: true},
+				{Name: "note", Path: "$.note"},
+				{Name: "tags", Path: "$.tags"},
+			},
+			Sink: []model.CaptureSink{
+				{Name: "id", File: "out/{{.env}}/id.txt"},
+				{Name: "token", File: "out/token.txt"},
+				{Name: "token", File: "out/token-clear.txt", Reveal: true},
+				{Name: "note", File: "out/note.txt"},
+				{Name: "tags", File: "out/tags.json"},
+			},
+		},
+	}
+	captures := map[string]CaptureValue{"env": {Value: "staging"}}
+
+	if _, err := runner.executeStep(context.Background(), step, captures, dir); err != nil {
+		t.Fatalf("executeStep() error = %v", err)
+	}
+
+	want := map[string]string{
+		"out/staging/id.txt":  "42",
+		"out/token.txt":       sanitizer.RedactedToken("tok-123", "salt"),
+		"out/token-clear.txt": "tok-123",
+		"out/note.txt":        "key " + sanitizer.RedactedToken("s3cret", "salt"),
+		"out/tags.json":       `["a","b"]`,
+	}
+	for name, content := range want {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error = %v", name, err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", name, data, content)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("%s mode = %v, want 0600", name, perm)
+		}
+	}
+}
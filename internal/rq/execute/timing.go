@@ -0,0 +1,220 @@
+package execute
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// requestTiming records time-to-first-byte and the connection phases for the
+// last hop of a request, so redirects report the latency of the response that
+// is asserted on. total spans every hop, from sending the request until the
+// body was read. continued records whether the server sent 100 Continue.
+type requestTiming struct {
+	mu        sync.Mutex
+	sent      time.Time
+	start     time.Time
+	ttfb      time.Duration
+	done      bool
+	total     time.Duration
+	complete  bool
+	continued bool
+	phases    map[string]*phaseTiming
+}
+
+// phaseTiming is the interval of one connection phase, such as DNS lookup.
+type phaseTiming struct {
+	start time.Time
+	end   time.Time
+}
+
+// requestPhase is a completed connection phase in the order it began.
+type requestPhase struct {
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+var requestPhaseOrder = []string{"dns", "connect", "tls"}
+
+type requestTimingKey struct{}
+
+// withRequestTiming attaches an httptrace hook that measures time-to-first-byte.
+// The timing travels with the request context and is read back from resp.Request.
+func withRequestTiming(req *http.Request) *http.Request {
+	timing := &requestTiming{sent: time.Now()}
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) {
+			timing.mu.Lock()
+			defer timing.mu.Unlock()
+			timing.start = time.Now()
+			timing.done = false
+			timing.continued = false
+			timing.phases = make(map[string]*phaseTiming)
+		},
+		GotFirstResponseByte: func() {
+			timing.mu.Lock()
+			defer timing.mu.Unlock()
+			timing.ttfb = time.Since(timing.start)
+			timing.done = true
+		},
+		Got100Continue: func() {
+			timing.mu.Lock()
+			defer timing.mu.Unlock()
+			timing.continued = true
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			timing.phaseStart("dns")
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			timing.phaseEnd("dns")
+		},
+		ConnectStart: func(string, string) {
+			timing.phaseStart("connect")
+		},
+		ConnectDone: func(string, string, error) {
+			timing.phaseEnd("connect")
+		},
+		TLSHandshakeStart: func() {
+			timing.phaseStart("tls")
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			timing.phaseEnd("tls")
+		},
+	}
+
+	ctx := context.WithValue(req.Context(), requestTimingKey{}, timing)
+	return req.WithContext(httptrace.WithClientTrace(ctx, trace))
+}
+
+// withMeasuredTiming attaches a completed timing to req, so ttfb and duration
+// asserts and captures read duration for steps that measure their own
+// latency.
+func withMeasuredTiming(req *http.Request, start time.Time, duration time.Duration) *http.Request {
+	timing := &requestTiming{sent: start, start: start, ttfb: duration, done: true, total: duration, complete: true}
+	return req.WithContext(context.WithValue(req.Context(), requestTimingKey{}, timing))
+}
+
+// phaseStart marks the start of a phase. When a phase repeats, as with
+// parallel dial attempts, the earliest start is kept.
+func (t *requestTiming) phaseStart(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.phases == nil {
+		t.phases = make(map[string]*phaseTiming)
+	}
+	if _, ok := t.phases[name]; !ok {
+		t.phases[name] = &phaseTiming{start: time.Now()}
+	}
+}
+
+func (t *requestTiming) phaseEnd(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if phase, ok := t.phases[name]; ok {
+		phase.end = time.Now()
+	}
+}
+
+// responseTiming returns the timing attached to the request behind resp.
+func responseTiming(resp *http.Response) (*requestTiming, bool) {
+	if resp == nil || resp.Request == nil {
+		return nil, false
+	}
+
+	timing, ok := resp.Request.Context().Value(requestTimingKey{}).(*requestTiming)
+	return timing, ok
+}
+
+// responseTTFB returns the measured time-to-first-byte for resp.
+func responseTTFB(resp *http.Response) (time.Duration, bool) {
+	timing, ok := responseTiming(resp)
+	if !ok {
+		return 0, false
+	}
+
+	timing.mu.Lock()
+	defer timing.mu.Unlock()
+
+	if !timing.done {
+		return 0, false
+	}
+
+	return timing.ttfb, true
+}
+
+// responseContinued reports whether the server answered the Expect:
+// 100-continue request behind resp with 100 Continue.
+func responseContinued(resp *http.Response) bool {
+	timing, ok := responseTiming(resp)
+	if !ok {
+		return false
+	}
+
+	timing.mu.Lock()
+	defer timing.mu.Unlock()
+
+	return timing.continued
+}
+
+// completeTiming records the total duration of resp once its body was read.
+func completeTiming(resp *http.Response) {
+	timing, ok := responseTiming(resp)
+	if !ok {
+		return
+	}
+
+	timing.mu.Lock()
+	defer timing.mu.Unlock()
+
+	timing.total = time.Since(timing.sent)
+	timing.complete = true
+}
+
+// responseDuration returns the total duration of resp.
+func responseDuration(resp *http.Response) (time.Duration, bool) {
+	timing, ok := responseTiming(resp)
+	if !ok {
+		return 0, false
+	}
+
+	timing.mu.Lock()
+	defer timing.mu.Unlock()
+
+	if !timing.complete {
+		return 0, false
+	}
+
+	return timing.total, true
+}
+
+// responsePhases returns the completed connection phases and the
+// time-to-first-byte interval of resp. Reused connections have no phases.
+func responsePhases(resp *http.Response) []requestPhase {
+	timing, ok := responseTiming(resp)
+	if !ok {
+		return nil
+	}
+
+	timing.mu.Lock()
+	defer timing.mu.Unlock()
+
+	var phases []requestPhase
+	for _, name := range requestPhaseOrder {
+		phase, ok := timing.phases[name]
+		if !ok || phase.end.IsZero() {
+			continue
+		}
+		phases = append(phases, requestPhase{name: name, start: phase.start, end: phase.end})
+	}
+	if timing.done {
+		phases = append(phases, requestPhase{name: "ttfb", start: timing.start, end: timing.start.Add(timing.ttfb)})
+	}
+
+	return phases
+}
@@ -0,0 +1,75 @@
+package execute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/trace"
+)
+
+type traceThreadKey struct{}
+
+// withTraceThread assigns the spans recorded under ctx to a timeline row, so
+// files running in parallel do not overlap in the trace viewer.
+func withTraceThread(ctx context.Context, thread int) context.Context {
+	return context.WithValue(ctx, traceThreadKey{}, thread)
+}
+
+func traceThread(ctx context.Context) int {
+	if thread, ok := ctx.Value(traceThreadKey{}).(int); ok {
+		return thread
+	}
+
+	return 1
+}
+
+// traceSpan records an operation that started at start and ends now. Failed
+// operations carry their error so it shows when the span is selected.
+func (r *Runner) traceSpan(ctx context.Context, category, name string, start time.Time, err error) {
+	if r.tracer == nil {
+		return
+	}
+
+	var args map[string]any
+	if err != nil {
+		args = map[string]any{"error": err.Error()}
+	}
+
+	r.tracer.Span(traceThread(ctx), category, name, start, time.Since(start), args)
+}
+
+// traceRequestPhases records the connection phases of resp and the body read
+// that started at bodyStart.
+func (r *Runner) traceRequestPhases(resp *http.Response, bodyStart time.Time) {
+	if r.tracer == nil {
+		return
+	}
+
+	thread := traceThread(resp.Request.Context())
+	for _, phase := range responsePhases(resp) {
+		r.tracer.Span(thread, trace.CategoryPhase, phase.name, phase.start, phase.end.Sub(phase.start), nil)
+	}
+	r.tracer.Span(thread, trace.CategoryPhase, "body", bodyStart, time.Since(bodyStart), map[string]any{"status": resp.StatusCode})
+}
+
+// writeTrace saves the recorded spans to the --trace file.
+func (r *Runner) writeTrace() error {
+	if r.tracer == nil || r.config == nil || r.config.TracePath == "" {
+		return nil
+	}
+
+	file, err := os.Create(r.config.TracePath)
+	if err != nil {
+		return err
+	}
+
+	if err := r.tracer.Write(file); err != nil {
+		file.Close()
+		return fmt.Errorf("write %s: %w", r.config.TracePath, err)
+	}
+
+	return file.Close()
+}
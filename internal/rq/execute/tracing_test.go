@@ -0,0 +1,71 @@
+package execute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/trace"
+)
+
+func TestExecuteCompiledFileTrace(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	runner := newDefault()
+	runner.tracer = trace.New(time.Now())
+
+	step := model.Step{
+		Method:  "GET",
+		URL:     server.URL,
+		Options: model.Options{Retries: 1},
+		Asserts: model.Asserts{Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 200}}}},
+	}
+	file := CompiledFile{Filename: "trace.yaml", Steps: []model.Step{step}}
+
+	if _, err := runner.executeCompiledFile(context.Background(), file); err != nil {
+		t.Fatalf("executeCompiledFile() error = %v", err)
+	}
+
+	counts := map[string]int{}
+	names := map[string]bool{}
+	var fileEvent, stepEvent trace.Event
+	for _, event := range runner.tracer.Events() {
+		counts[event.Category]++
+		names[event.Name] = true
+		switch event.Category {
+		case trace.CategoryFile:
+			fileEvent = event
+		case trace.CategoryStep:
+			stepEvent = event
+		}
+	}
+
+	want := map[string]int{trace.CategoryFile: 1, trace.CategoryStep: 1, trace.CategoryAttempt: 2}
+	for category, count := range want {
+		if counts[category] != count {
+			t.Errorf("%s events = %d, want %d", category, counts[category], count)
+		}
+	}
+	for _, name := range []string{"trace.yaml", "step 0 GET " + server.URL, "attempt 1", "attempt 2", "connect", "ttfb", "body"} {
+		if !names[name] {
+			t.Errorf("missing span %q in %+v", name, runner.tracer.Events())
+		}
+	}
+	if stepEvent.Time < fileEvent.Time || stepEvent.Time+stepEvent.Duration > fileEvent.Time+fileEvent.Duration {
+		t.Errorf("step span %+v is not nested in file span %+v", stepEvent, fileEvent)
+	}
+}
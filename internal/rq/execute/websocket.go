@@ -0,0 +1,296 @@
+package execute
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/templating"
+)
+
+// DefaultWebSocketTimeout bounds a WebSocket exchange when the step sets no
+// websocket.timeout.
+const DefaultWebSocketTimeout = 10 * time.Second
+
+// maxWebSocketMessage caps one received message when --max-response-bytes is
+// not set, so a misbehaving server cannot exhaust memory.
+const maxWebSocketMessage = 64 << 20
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes from RFC 6455, section 5.2.
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// executeWebSocket performs the handshake for req, sends the step's messages
+// and collects the expected number of replies. The replies, joined by
+// newlines, are returned as the response body.
+func (r *Runner) executeWebSocket(ctx context.Context, step model.Step, req *http.Request, captures map[string]CaptureValue) (*http.Response, []byte, error) {
+	ws := step.WebSocket
+	timeout := ws.Timeout
+	if timeout == 0 {
+		timeout = DefaultWebSocketTimeout
+	}
+	expected := max(ws.Receive, 1)
+
+	messages, err := renderWebSocketMessages(ws.Send, captures)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.rateLimiter.Wait(ctx, req.URL.Host); err != nil {
+		return nil, nil, fmt.Errorf("rate limiting interrupted: %w", err)
+	}
+
+	client, err := r.getClient(step.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The exchange is bounded by the websocket timeout instead of the client
+	// timeout, which would cut the upgraded connection mid-read.
+	upgradeClient := *client
+	upgradeClient.Timeout = 0
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	key, err := websocketKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	switch req.URL.Scheme {
+	case "ws":
+		req.URL.Scheme = "http"
+	case "wss":
+		req.URL.Scheme = "https"
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+
+	resp, err := upgradeClient.Do(withRequestTiming(req))
+	r.breaker.record(req.URL.Host, err != nil && ctx.Err() == nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("websocket handshake failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, nil, fmt.Errorf("websocket handshake failed: server answered %s", resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		return nil, nil, errors.New("websocket handshake failed: invalid Sec-WebSocket-Accept header")
+	}
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		return nil, nil, errors.New("websocket handshake failed: connection cannot be upgraded")
+	}
+
+	// Closing the connection unblocks reads once the timeout expires.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	for _, message := range messages {
+		if err := writeWebSocketFrame(conn, wsText, []byte(message), true); err != nil {
+			return nil, nil, fmt.Errorf("websocket send failed: %w", err)
+		}
+	}
+
+	received, err := readWebSocketMessages(bufio.NewReader(conn), conn, expected, r.maxResponseBytes())
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		return nil, nil, fmt.Errorf("websocket received %d of %d messages: %w", len(received), expected, err)
+	}
+
+	completeTiming(resp)
+	closePayload := binary.BigEndian.AppendUint16(nil, 1000)
+	_ = writeWebSocketFrame(conn, wsClose, closePayload, true)
+
+	return resp, bytes.Join(received, []byte("\n")), nil
+}
+
+func renderWebSocketMessages(send []string, captures map[string]CaptureValue) ([]string, error) {
+	tmplVars := captureMapForTemplate(captures)
+	messages := make([]string, 0, len(send))
+	for i, message := range send {
+		rendered, err := templating.Apply(message, tmplVars)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process websocket message %d template: %w", i, err)
+		}
+		messages = append(messages, rendered)
+	}
+
+	return messages, nil
+}
+
+// readWebSocketMessages reads data messages until expected have arrived,
+// answering pings on w. limit caps the total size of the messages when set.
+func readWebSocketMessages(r *bufio.Reader, w io.Writer, expected int, limit int64) ([][]byte, error) {
+	maxMessage := int64(maxWebSocketMessage)
+	if limit > 0 {
+		maxMessage = limit
+	}
+
+	var (
+		messages [][]byte
+		partial  []byte
+		total    int64
+	)
+	for len(messages) < expected {
+		fin, opcode, payload, err := readWebSocketFrame(r, maxMessage)
+		if err != nil {
+			return messages, err
+		}
+
+		switch opcode {
+		case wsPing:
+			if err := writeWebSocketFrame(w, wsPong, payload, true); err != nil {
+				return messages, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			return messages, errors.New("connection closed by server")
+		case wsText, wsBinary, wsContinuation:
+		default:
+			return messages, fmt.Errorf("unsupported frame opcode %#x", opcode)
+		}
+
+		total += int64(len(payload))
+		if limit > 0 && total > limit {
+			return messages, &ResponseTooLargeError{Limit: limit}
+		}
+
+		partial = append(partial, payload...)
+		if fin {
+			messages = append(messages, partial)
+			partial = nil
+		}
+	}
+
+	return messages, nil
+}
+
+// readWebSocketFrame reads one frame, unmasking its payload when masked.
+func readWebSocketFrame(r *bufio.Reader, maxPayload int64) (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(r, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(r, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if length > uint64(maxPayload) {
+		return false, 0, nil, fmt.Errorf("frame of %d bytes exceeds limit of %d bytes", length, maxPayload)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeWebSocketFrame writes payload as a single final frame. Clients must
+// mask the frames they send; servers must not.
+func writeWebSocketFrame(w io.Writer, opcode byte, payload []byte, mask bool) error {
+	frame := []byte{0x80 | opcode}
+
+	var maskBit byte
+	if mask {
+		maskBit = 0x80
+	}
+	switch length := len(payload); {
+	case length < 126:
+		frame = append(frame, maskBit|byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	if !mask {
+		_, err := w.Write(append(frame, payload...))
+		return err
+	}
+
+	var key [4]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return err
+	}
+	frame = append(frame, key[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^key[i%4])
+	}
+
+	_, err := w.Write(frame)
+	return err
+}
+
+func websocketKey() (string, error) {
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(nonce[:]), nil
+}
+
+// websocketAccept is the Sec-WebSocket-Accept value a server must answer
+// for key.
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
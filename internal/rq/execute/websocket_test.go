@@ -0,0 +1,156 @@
+package execute
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// newWebSocketServer upgrades every request and hands each text message the
+// client sends to reply, writing back whatever it returns.
+func newWebSocketServer(t *testing.T, reply func(message string) []string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/plain" {
+			http.Error(w, "not a websocket endpoint", http.StatusNotFound)
+			return
+		}
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack() error = %v", err)
+			return
+		}
+		defer conn.Close()
+
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: " + websocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		rw.Flush()
+
+		reader := bufio.NewReader(rw)
+		for {
+			_, opcode, payload, err := readWebSocketFrame(reader, maxWebSocketMessage)
+			if err != nil || opcode == wsClose {
+				return
+			}
+			for _, message := range reply(string(payload)) {
+				if err := writeWebSocketFrame(conn, wsText, []byte(message), false); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestExecuteStepWebSocket(t *testing.T) {
+	t.Parallel()
+
+	server := newWebSocketServer(t, func(message string) []string {
+		if message == "silence" {
+			return nil
+		}
+		return []string{`{"echo":"` + message + `"}`}
+	})
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	tests := []struct {
+		name    string
+		step    model.Step
+		want    string
+		wantErr string
+	}{
+		{
+			name: "messages are asserted and captured",
+			step: model.Step{
+				Method:    "GET",
+				URL:       wsURL,
+				Options:   model.Options{JSONLines: true},
+				WebSocket: &model.WebSocket{Send: []string{"{{.first}}", "second"}, Receive: 2},
+				Asserts: model.Asserts{
+					Status: []model.StatusAssert{{Predicate: model.Predicate{Operation: "equals", Value: 101, HasValue: true}}},
+					JSONPath: []model.JSONPathAssert{{
+						Path:      "$[0].echo",
+						Predicate: model.Predicate{Operation: "equals", Value: "hello", HasValue: true},
+					}},
+				},
+				Captures: &model.Captures{
+					JSONPath: []model.JSONPathCapture{{Name: "reply", Path: "$[1].echo"}},
+				},
+			},
+			want: "second",
+		},
+		{
+			name: "timeout reports received messages",
+			step: model.Step{
+				Method:    "GET",
+				URL:       wsURL,
+				WebSocket: &model.WebSocket{Send: []string{"silence"}, Timeout: 50 * time.Millisecond},
+			},
+			wantErr: "websocket received 0 of 1 messages: timed out after 50ms",
+		},
+		{
+			name: "rejected handshake",
+			step: model.Step{
+				Method:    "GET",
+				URL:       server.URL + "/plain",
+				WebSocket: &model.WebSocket{},
+			},
+			wantErr: "websocket handshake failed: server answered 404 Not Found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			captures := map[string]CaptureValue{"first": {Value: "hello"}}
+			_, err := newDefault().executeStep(context.Background(), tt.step, captures, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("executeStep() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("executeStep() error = %v", err)
+			}
+			if captures["reply"].Value != tt.want {
+				t.Fatalf("reply = %v, want %s", captures["reply"].Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebSocketFrameRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, 125, 126, 0xFFFF, 0x10000} {
+		for _, mask := range []bool{false, true} {
+			payload := []byte(strings.Repeat("x", size))
+
+			var buf bytes.Buffer
+			if err := writeWebSocketFrame(&buf, wsBinary, payload, mask); err != nil {
+				t.Fatalf("writeWebSocketFrame(%d, %t) error = %v", size, mask, err)
+			}
+
+			fin, opcode, got, err := readWebSocketFrame(bufio.NewReader(&buf), maxWebSocketMessage)
+			if err != nil {
+				t.Fatalf("readWebSocketFrame(%d, %t) error = %v", size, mask, err)
+			}
+			if !fin || opcode != wsBinary || !bytes.Equal(got, payload) {
+				t.Fatalf("readWebSocketFrame(%d, %t) = %t, %#x, %d bytes", size, mask, fin, opcode, len(got))
+			}
+		}
+	}
+}
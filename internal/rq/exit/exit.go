@@ -6,6 +6,10 @@ import (
 	"os"
 )
 
+// RunTimeout is the exit code of a run stopped by --run-timeout. It matches
+// timeout(1), so CI can tell a slow suite from a failing one.
+const RunTimeout = 124
+
 // Result holds the output destination and exit code for program termination.
 type Result struct {
 	Output   io.Writer
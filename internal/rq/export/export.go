@@ -0,0 +1,270 @@
+// Package export renders resolved rq steps as commands of other tools, so a
+// failing step can be reproduced or shared outside rq.
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jacoelho/rq/internal/pathing"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/plan"
+)
+
+// Format selects the command steps are rendered as.
+type Format string
+
+const (
+	FormatCurl Format = "curl"
+)
+
+// ErrInvalidFormat is returned for unknown export formats.
+var ErrInvalidFormat = fmt.Errorf("export format must be one of: curl")
+
+// ParseFormat parses an export format name.
+func ParseFormat(input string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "curl", "":
+		return FormatCurl, nil
+	default:
+		return FormatCurl, fmt.Errorf("%w, got: %s", ErrInvalidFormat, input)
+	}
+}
+
+// Options holds the run options that change how requests are sent.
+type Options struct {
+	Insecure   bool
+	CACertFile string
+}
+
+// Write renders every step of files. Steps curl cannot send, such as gRPC or
+// WebSocket steps, are written as comments explaining why.
+func Write(w io.Writer, format Format, files []plan.File, opts Options) error {
+	if format != FormatCurl {
+		return fmt.Errorf("%w, got: %s", ErrInvalidFormat, format)
+	}
+
+	var b strings.Builder
+	for i, file := range files {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "# %s\n", file.Filename)
+		for index, step := range file.Steps {
+			fmt.Fprintf(&b, "\n# step %d", index)
+			if title, _, _ := strings.Cut(strings.TrimSpace(step.Description), "\n"); title != "" {
+				fmt.Fprintf(&b, ": %s", title)
+			}
+			b.WriteString("\n")
+
+			if reason := unsupported(step); reason != "" {
+				fmt.Fprintf(&b, "# skipped: %s\n", reason)
+				continue
+			}
+			if step.Auth != nil && step.Auth.OAuth2 != nil {
+				fmt.Fprintf(&b, "# auth.oauth2 is not exported: fetch a token from %s and add an Authorization header\n", step.Auth.OAuth2.TokenURL)
+			}
+			for _, part := range step.Multipart {
+				if part.File == "" && part.ContentType != "" {
+					fmt.Fprintf(&b, "# the content type of part %s is not exported: %s\n", part.Name, part.ContentType)
+				}
+			}
+			var scope map[string]any
+			if index < len(file.Scopes) {
+				scope = file.Scopes[index]
+			}
+			b.WriteString(curl(step, filepath.Dir(file.Filename), scope, opts))
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// unsupported returns why step cannot be sent by curl, or "".
+func unsupported(step model.Step) string {
+	switch {
+	case step.DocsOnly():
+		return "documentation step"
+	case step.ChecksOnly():
+		return "checks-only step"
+	case step.GRPC != nil:
+		return "gRPC steps cannot be sent with curl"
+	case step.WebSocket != nil:
+		return "WebSocket steps cannot be sent with curl"
+	case step.Connect != nil:
+		return "connect steps cannot be sent with curl"
+	case step.DNS != nil:
+		return "DNS steps cannot be sent with curl"
+	default:
+		return ""
+	}
+}
+
+// curl renders step as a curl command with one option per line. variables
+// are the values step was planned with, used to render a templated body_file.
+func curl(step model.Step, baseDir string, variables map[string]any, opts Options) string {
+	args := []string{"curl"}
+	body, isJSON := requestBody(step)
+
+	// curl turns a GET with --data or --form into a POST, so the method is
+	// only left implicit for requests without a body.
+	hasBody := body != "" || step.BodyFile != "" || len(step.Multipart) > 0
+	switch method := strings.ToUpper(step.Method); {
+	case method == "GET" && !hasBody:
+	case method == "HEAD" && !hasBody:
+		args = append(args, "--head")
+	default:
+		args = append(args, "-X "+quote(method))
+	}
+
+	if step.Options.FollowRedirect == nil || *step.Options.FollowRedirect {
+		args = append(args, "--location")
+	}
+	switch step.Options.HTTPVersion {
+	case model.HTTPVersion1:
+		args = append(args, "--http1.1")
+	case model.HTTPVersion2:
+		args = append(args, "--http2")
+	case model.HTTPVersion3:
+		args = append(args, "--http3-only")
+	}
+	if opts.Insecure {
+		args = append(args, "--insecure")
+	}
+	if opts.CACertFile != "" {
+		args = append(args, "--cacert "+quote(opts.CACertFile))
+	}
+
+	headers := step.Headers
+	for _, header := range headers {
+		args = append(args, "-H "+quote(headerArg(header)))
+	}
+
+	if isJSON {
+		if _, ok := headers.GetFold("Content-Type"); !ok {
+			args = append(args, "-H "+quote("Content-Type: application/json"))
+		}
+	}
+	switch {
+	case body != "":
+		args = append(args, "--data-raw "+quote(body))
+	case step.BodyFile != "":
+		filename := pathing.ResolveBodyFilePath(step.BodyFile, baseDir)
+		if text, ok := bodyFileText(step, filename, variables); ok {
+			args = append(args, "--data-raw "+quote(text))
+		} else {
+			args = append(args, "--data-binary "+quote("@"+filename))
+		}
+	}
+	for _, part := range step.Multipart {
+		if part.File == "" {
+			// --form-string sends the value as is; -F would read a file for
+			// values starting with @ or < and parse ;type= suffixes.
+			args = append(args, "--form-string "+quote(part.Name+"="+part.Value))
+			continue
+		}
+		args = append(args, "-F "+quote(filePart(part, baseDir)))
+	}
+
+	args = append(args, quote(requestURL(step)))
+	return strings.Join(args, " \\\n  ")
+}
+
+// requestBody returns the inline body of step and whether it is JSON that
+// rq would send with a default Content-Type.
+func requestBody(step model.Step) (string, bool) {
+	if step.GraphQL != nil {
+		payload := map[string]any{"query": step.GraphQL.Query}
+		if step.GraphQL.OperationName != "" {
+			payload["operationName"] = step.GraphQL.OperationName
+		}
+		if len(step.GraphQL.Variables) > 0 {
+			payload["variables"] = step.GraphQL.Variables
+		}
+		data, _ := json.Marshal(payload)
+		return string(data), true
+	}
+	if step.Body.IsStructured() {
+		data, err := json.Marshal(step.Body.Value)
+		if err != nil {
+			return fmt.Sprint(step.Body.Value), false
+		}
+		return string(data), true
+	}
+	return step.Body.Text, false
+}
+
+// headerArg returns the -H argument for header. curl removes a header given
+// as "Name:", so an empty value is written as "Name;".
+func headerArg(header model.KeyValue) string {
+	if header.Value == "" {
+		return header.Key + ";"
+	}
+	return header.Key + ": " + header.Value
+}
+
+// bodyFileText returns the body rq would send for a body_file that it
+// templates, with the planned variables substituted. Files sent as read
+// (body_file_raw), files that cannot be read and binary content stay file
+// references.
+func bodyFileText(step model.Step, filename string, variables map[string]any) (string, bool) {
+	if step.BodyFileRaw {
+		return "", false
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil || bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return "", false
+	}
+	return plan.Substitute(string(data), variables), true
+}
+
+func filePart(part model.Part, baseDir string) string {
+	value := part.Name + "=@" + pathing.ResolveBodyFilePath(part.File, baseDir)
+	if part.Filename != "" {
+		value += ";filename=" + part.Filename
+	}
+	if part.ContentType != "" {
+		value += ";type=" + part.ContentType
+	}
+	return value
+}
+
+// requestURL appends the query of step to its URL. Values still holding
+// template actions are written as they are, so they stay readable.
+func requestURL(step model.Step) string {
+	if len(step.Query) == 0 {
+		return step.URL
+	}
+
+	pairs := make([]string, 0, len(step.Query))
+	for _, entry := range step.Query {
+		pairs = append(pairs, escapeQuery(entry.Key)+"="+escapeQuery(entry.Value))
+	}
+
+	separator := "?"
+	if strings.Contains(step.URL, "?") {
+		separator = "&"
+	}
+	return step.URL + separator + strings.Join(pairs, "&")
+}
+
+func escapeQuery(text string) string {
+	if strings.Contains(text, "{{") {
+		return text
+	}
+	return url.QueryEscape(text)
+}
+
+// quote wraps text in single quotes for POSIX shells.
+func quote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
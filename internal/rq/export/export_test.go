@@ -0,0 +1,171 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/plan"
+)
+
+func TestWriteCurl(t *testing.T) {
+	t.Parallel()
+
+	noRedirect := false
+
+	tests := []struct {
+		name string
+		step model.Step
+		opts Options
+		want string
+	}{
+		{
+			name: "get",
+			step: model.Step{Method: "GET", URL: "https://api.example.com/users"},
+			want: "curl \\\n  --location \\\n  'https://api.example.com/users'",
+		},
+		{
+			name: "json body with query and quote",
+			step: model.Step{
+				Method:  "POST",
+				URL:     "https://api.example.com/users?v=1",
+				Headers: model.KeyValues{{Key: "Authorization", Value: "Bearer {{.token}}"}},
+				Query:   model.KeyValues{{Key: "tag", Value: "a b"}, {Key: "id", Value: "{{.id}}"}},
+				Body:    model.Body{Value: map[string]any{"name": "O'Brien"}},
+			},
+			want: "curl \\\n  -X 'POST' \\\n  --location \\\n  -H 'Authorization: Bearer {{.token}}' \\\n  -H 'Content-Type: application/json' \\\n  --data-raw '{\"name\":\"O'\\''Brien\"}' \\\n  'https://api.example.com/users?v=1&tag=a+b&id={{.id}}'",
+		},
+		{
+			name: "tls and transport options",
+			step: model.Step{Method: "HEAD", URL: "https://api.example.com", Options: model.Options{FollowRedirect: &noRedirect, HTTPVersion: model.HTTPVersion2}},
+			opts: Options{CACertFile: "ca.pem"},
+			want: "curl \\\n  --head \\\n  --http2 \\\n  --cacert 'ca.pem' \\\n  'https://api.example.com'",
+		},
+		{
+			name: "insecure",
+			step: model.Step{Method: "DELETE", URL: "https://localhost/x", Options: model.Options{FollowRedirect: &noRedirect}},
+			opts: Options{Insecure: true},
+			want: "curl \\\n  -X 'DELETE' \\\n  --insecure \\\n  'https://localhost/x'",
+		},
+		{
+			name: "body file and multipart resolve against the file",
+			step: model.Step{
+				Method:    "PUT",
+				URL:       "https://api.example.com/upload",
+				BodyFile:  "data.bin",
+				Multipart: []model.Part{{Name: "meta", Value: "x"}, {Name: "file", File: "a.png", Filename: "logo.png", ContentType: "image/png"}},
+				Options:   model.Options{FollowRedirect: &noRedirect},
+			},
+			want: "curl \\\n  -X 'PUT' \\\n  --data-binary '@suite/data.bin' \\\n  --form-string 'meta=x' \\\n  -F 'file=@suite/a.png;filename=logo.png;type=image/png' \\\n  'https://api.example.com/upload'",
+		},
+		{
+			name: "empty header and form values that curl would parse",
+			step: model.Step{
+				Method:    "POST",
+				URL:       "https://api.example.com/upload",
+				Headers:   model.KeyValues{{Key: "X-Empty", Value: ""}},
+				Multipart: []model.Part{{Name: "handle", Value: "@rq"}, {Name: "doc", Value: "{}", ContentType: "application/json"}},
+				Options:   model.Options{FollowRedirect: &noRedirect},
+			},
+			want: "# the content type of part doc is not exported: application/json\ncurl \\\n  -X 'POST' \\\n  -H 'X-Empty;' \\\n  --form-string 'handle=@rq' \\\n  --form-string 'doc={}' \\\n  'https://api.example.com/upload'",
+		},
+		{
+			name: "get with body keeps its method",
+			step: model.Step{Method: "GET", URL: "https://api.example.com/search", Body: model.Body{Value: map[string]any{"q": "x"}}, Options: model.Options{FollowRedirect: &noRedirect}},
+			want: "curl \\\n  -X 'GET' \\\n  -H 'Content-Type: application/json' \\\n  --data-raw '{\"q\":\"x\"}' \\\n  'https://api.example.com/search'",
+		},
+		{
+			name: "get with multipart keeps its method",
+			step: model.Step{Method: "GET", URL: "https://api.example.com/search", Multipart: []model.Part{{Name: "q", Value: "x"}}, Options: model.Options{FollowRedirect: &noRedirect}},
+			want: "curl \\\n  -X 'GET' \\\n  --form-string 'q=x' \\\n  'https://api.example.com/search'",
+		},
+		{
+			name: "head with body keeps its method",
+			step: model.Step{Method: "HEAD", URL: "https://api.example.com", Body: model.Body{Text: "x"}, Options: model.Options{FollowRedirect: &noRedirect}},
+			want: "curl \\\n  -X 'HEAD' \\\n  --data-raw 'x' \\\n  'https://api.example.com'",
+		},
+		{
+			name: "grpc skipped",
+			step: model.Step{Method: "POST", URL: "grpc://localhost:50051", GRPC: &model.GRPC{}},
+			want: "# skipped: gRPC steps cannot be sent with curl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+			files := []plan.File{{Filename: "suite/flow.yaml", Steps: []model.Step{tt.step}}}
+			if err := Write(&b, FormatCurl, files, tt.opts); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			want := "# suite/flow.yaml\n\n# step 0\n" + tt.want + "\n"
+			if got := b.String(); got != want {
+				t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteCurlBodyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	suite := filepath.Join(dir, "suite.yaml")
+	content := `- method: POST
+  url: https://api.example.com/regions/{{.item}}
+  foreach: [eu, us]
+  body_file: body.json
+- method: POST
+  url: https://api.example.com/raw
+  body_file: body.json
+  body_file_raw: true
+`
+	for name, data := range map[string]string{
+		suite:                           content,
+		filepath.Join(dir, "body.json"): `{"region":"{{.item}}","token":"{{.token}}"}`,
+	} {
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := plan.Build([]string{suite}, nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	var b strings.Builder
+	if err := Write(&b, FormatCurl, files, Options{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got := b.String()
+	for _, want := range []string{
+		"--data-raw '{\"region\":\"eu\",\"token\":\"{{.token}}\"}' \\\n  'https://api.example.com/regions/eu'",
+		"--data-raw '{\"region\":\"us\",\"token\":\"{{.token}}\"}' \\\n  'https://api.example.com/regions/us'",
+		"--data-binary '@" + filepath.Join(dir, "body.json") + "' \\\n  'https://api.example.com/raw'",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "regions/{{.item}}") {
+		t.Errorf("foreach step was not expanded:\n%s", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	if format, err := ParseFormat("CURL"); err != nil || format != FormatCurl {
+		t.Errorf("ParseFormat(CURL) = %q, %v", format, err)
+	}
+	if _, err := ParseFormat("httpie"); err == nil {
+		t.Error("ParseFormat(httpie) error = nil")
+	}
+}
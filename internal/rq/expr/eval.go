@@ -3,61 +3,113 @@ package expr
 import (
 	"math"
 	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/jacoelho/rq/internal/rq/number"
 )
 
+// binding is a variable bound by a macro, such as i in items.all(i, ...).
+type binding struct {
+	name  string
+	value any
+}
+
+// evaluator evaluates a parsed expression. Macro bindings shadow variables.
+type evaluator struct {
+	variables map[string]any
+	bound     []binding
+}
+
 func evaluate(root node, variables map[string]any) (any, error) {
+	e := &evaluator{variables: variables}
+	return e.eval(root)
+}
+
+func (e *evaluator) lookup(name string) (any, bool) {
+	for i := len(e.bound) - 1; i >= 0; i-- {
+		if e.bound[i].name == name {
+			return e.bound[i].value, true
+		}
+	}
+
+	value, ok := e.variables[name]
+	return value, ok
+}
+
+func (e *evaluator) eval(root node) (any, error) {
 	switch current := root.(type) {
 	case literalNode:
 		return current.value, nil
 	case identifierNode:
-		value, ok := variables[current.name]
+		value, ok := e.lookup(current.name)
 		if !ok {
 			return nil, expressionError("unknown variable %q", current.name)
 		}
 		return value, nil
-	case unaryNode:
-		if current.op != tokenNot {
-			return nil, expressionError("unsupported unary operator")
+	case listNode:
+		items := make([]any, 0, len(current.items))
+		for _, item := range current.items {
+			value, err := e.eval(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
 		}
-
-		rightValue, err := evaluate(current.right, variables)
+		return items, nil
+	case memberNode:
+		if value, ok := e.dotted(current); ok {
+			return value, nil
+		}
+		target, err := e.eval(current.target)
 		if err != nil {
 			return nil, err
 		}
-
-		rightBool, err := mustBool(rightValue)
+		return selectField(target, current.name)
+	case indexNode:
+		target, err := e.eval(current.target)
 		if err != nil {
 			return nil, err
 		}
-		return !rightBool, nil
-	case binaryNode:
+		index, err := e.eval(current.index)
+		if err != nil {
+			return nil, err
+		}
+		return selectIndex(target, index)
+	case callNode:
+		return e.call(current)
+	case unaryNode:
+		rightValue, err := e.eval(current.right)
+		if err != nil {
+			return nil, err
+		}
+
 		switch current.op {
-		case tokenAnd:
-			leftValue, err := evaluate(current.left, variables)
-			if err != nil {
-				return nil, err
-			}
-			leftBool, err := mustBool(leftValue)
+		case tokenNot:
+			rightBool, err := mustBool(rightValue)
 			if err != nil {
 				return nil, err
 			}
-			if !leftBool {
-				return false, nil
+			return !rightBool, nil
+		case tokenMinus:
+			if value, ok := number.ToInt64(rightValue); ok {
+				if value == math.MinInt64 {
+					return nil, expressionError("integer overflow")
+				}
+				return -value, nil
 			}
-
-			rightValue, err := evaluate(current.right, variables)
-			if err != nil {
-				return nil, err
-			}
-			rightBool, err := mustBool(rightValue)
-			if err != nil {
-				return nil, err
+			value, ok := number.ToFloat64(rightValue)
+			if !ok {
+				return nil, expressionError("cannot negate %T", rightValue)
 			}
-			return rightBool, nil
-		case tokenOr:
-			leftValue, err := evaluate(current.left, variables)
+			return -value, nil
+		default:
+			return nil, expressionError("unsupported unary operator")
+		}
+	case binaryNode:
+		switch current.op {
+		case tokenAnd, tokenOr:
+			leftValue, err := e.eval(current.left)
 			if err != nil {
 				return nil, err
 			}
@@ -65,11 +117,11 @@ func evaluate(root node, variables map[string]any) (any, error) {
 			if err != nil {
 				return nil, err
 			}
-			if leftBool {
-				return true, nil
+			if leftBool == (current.op == tokenOr) {
+				return leftBool, nil
 			}
 
-			rightValue, err := evaluate(current.right, variables)
+			rightValue, err := e.eval(current.right)
 			if err != nil {
 				return nil, err
 			}
@@ -78,16 +130,19 @@ func evaluate(root node, variables map[string]any) (any, error) {
 				return nil, err
 			}
 			return rightBool, nil
-		case tokenEqual, tokenNotEqual:
-			leftValue, err := evaluate(current.left, variables)
-			if err != nil {
-				return nil, err
-			}
-			rightValue, err := evaluate(current.right, variables)
-			if err != nil {
-				return nil, err
-			}
+		}
 
+		leftValue, err := e.eval(current.left)
+		if err != nil {
+			return nil, err
+		}
+		rightValue, err := e.eval(current.right)
+		if err != nil {
+			return nil, err
+		}
+
+		switch current.op {
+		case tokenEqual, tokenNotEqual:
 			equal, err := compareValues(leftValue, rightValue)
 			if err != nil {
 				return nil, err
@@ -97,6 +152,12 @@ func evaluate(root node, variables map[string]any) (any, error) {
 				return equal, nil
 			}
 			return !equal, nil
+		case tokenLess, tokenLessEqual, tokenGreater, tokenGreaterEqual:
+			return orderValues(current.op, leftValue, rightValue)
+		case tokenIn:
+			return contains(rightValue, leftValue)
+		case tokenPlus, tokenMinus, tokenStar, tokenSlash, tokenPercent:
+			return arithmetic(current.op, leftValue, rightValue)
 		default:
 			return nil, expressionError("unsupported binary operator")
 		}
@@ -105,6 +166,247 @@ func evaluate(root node, variables map[string]any) (any, error) {
 	}
 }
 
+// dotted resolves a selection such as db.host to the variable of that
+// name, as structured variable files define dotted names. Macro bindings
+// take precedence.
+func (e *evaluator) dotted(member memberNode) (any, bool) {
+	name := member.name
+	for target := member.target; ; {
+		switch current := target.(type) {
+		case memberNode:
+			name = current.name + "." + name
+			target = current.target
+			continue
+		case identifierNode:
+			if _, bound := e.lookupBound(current.name); bound {
+				return nil, false
+			}
+			value, ok := e.variables[current.name+"."+name]
+			return value, ok
+		}
+		return nil, false
+	}
+}
+
+func (e *evaluator) lookupBound(name string) (any, bool) {
+	for i := len(e.bound) - 1; i >= 0; i-- {
+		if e.bound[i].name == name {
+			return e.bound[i].value, true
+		}
+	}
+	return nil, false
+}
+
+func selectField(target any, name string) (any, error) {
+	fields, ok := target.(map[string]any)
+	if !ok {
+		return nil, expressionError("cannot select field %q of %T", name, target)
+	}
+
+	value, ok := fields[name]
+	if !ok {
+		return nil, expressionError("no such key %q", name)
+	}
+	return value, nil
+}
+
+func selectIndex(target any, index any) (any, error) {
+	switch container := target.(type) {
+	case []any:
+		position, ok := toInteger(index)
+		if !ok {
+			return nil, expressionError("list index must be an integer, got %v", index)
+		}
+		if position < 0 || position >= int64(len(container)) {
+			return nil, expressionError("index %v out of range for list of size %d", index, len(container))
+		}
+		return container[position], nil
+	case map[string]any:
+		key, ok := index.(string)
+		if !ok {
+			return nil, expressionError("map key must be a string, got %T", index)
+		}
+		return selectField(container, key)
+	default:
+		return nil, expressionError("cannot index %T", target)
+	}
+}
+
+// contains implements in: membership of an equal element in a list, or of
+// a key in a map.
+func contains(container any, value any) (bool, error) {
+	switch items := container.(type) {
+	case []any:
+		for _, item := range items {
+			if equal, err := compareValues(value, item); err == nil && equal {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := value.(string)
+		if !ok {
+			return false, expressionError("map key must be a string, got %T", value)
+		}
+		_, found := items[key]
+		return found, nil
+	default:
+		return false, expressionError("in expects a list or map, got %T", container)
+	}
+}
+
+// orderValues compares two numbers or two strings. Two integers are compared
+// exactly; other numbers as doubles.
+func orderValues(op tokenType, left any, right any) (bool, error) {
+	var cmp int
+	leftInt, leftIsInt := number.ToInt64(left)
+	rightInt, rightIsInt := number.ToInt64(right)
+	leftNumber, leftIsNumber := number.ToFloat64(left)
+	rightNumber, rightIsNumber := number.ToFloat64(right)
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+	switch {
+	case leftIsInt && rightIsInt:
+		cmp = cmpInt(leftInt, rightInt)
+	case leftIsNumber && rightIsNumber:
+		cmp = cmpFloat(leftNumber, rightNumber)
+	case leftIsString && rightIsString:
+		cmp = strings.Compare(leftString, rightString)
+	default:
+		return false, expressionError("cannot order %T and %T", left, right)
+	}
+
+	switch op {
+	case tokenLess:
+		return cmp < 0, nil
+	case tokenLessEqual:
+		return cmp <= 0, nil
+	case tokenGreater:
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func cmpInt(left int64, right int64) int {
+	switch {
+	case left == right:
+		return 0
+	case left < right:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func cmpFloat(left float64, right float64) int {
+	switch {
+	case nearlyEqual(left, right):
+		return 0
+	case left < right:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// arithmetic applies +, -, *, / and % to numbers. Two integers give an
+// integer, dividing with truncation and failing on overflow; any double
+// makes the result a double. + also concatenates strings and lists.
+func arithmetic(op tokenType, left any, right any) (any, error) {
+	if op == tokenPlus {
+		if leftString, ok := left.(string); ok {
+			if rightString, ok := right.(string); ok {
+				return leftString + rightString, nil
+			}
+		}
+		if leftList, ok := left.([]any); ok {
+			if rightList, ok := right.([]any); ok {
+				return slices.Concat(leftList, rightList), nil
+			}
+		}
+	}
+
+	leftInt, leftIsInt := number.ToInt64(left)
+	rightInt, rightIsInt := number.ToInt64(right)
+	if leftIsInt && rightIsInt {
+		return intArithmetic(op, leftInt, rightInt)
+	}
+
+	leftNumber, leftIsNumber := number.ToFloat64(left)
+	rightNumber, rightIsNumber := number.ToFloat64(right)
+	if !leftIsNumber || !rightIsNumber {
+		return nil, expressionError("cannot apply arithmetic to %T and %T", left, right)
+	}
+
+	switch op {
+	case tokenPlus:
+		return leftNumber + rightNumber, nil
+	case tokenMinus:
+		return leftNumber - rightNumber, nil
+	case tokenStar:
+		return leftNumber * rightNumber, nil
+	case tokenSlash:
+		if rightNumber == 0 {
+			return nil, expressionError("division by zero")
+		}
+		return leftNumber / rightNumber, nil
+	default:
+		if rightNumber == 0 {
+			return nil, expressionError("modulo by zero")
+		}
+		return math.Mod(leftNumber, rightNumber), nil
+	}
+}
+
+func intArithmetic(op tokenType, left int64, right int64) (any, error) {
+	var result int64
+	switch op {
+	case tokenPlus:
+		result = left + right
+		if (right > 0 && result < left) || (right < 0 && result > left) {
+			return nil, expressionError("integer overflow")
+		}
+	case tokenMinus:
+		result = left - right
+		if (right > 0 && result > left) || (right < 0 && result < left) {
+			return nil, expressionError("integer overflow")
+		}
+	case tokenStar:
+		result = left * right
+		if left != 0 && (result/left != right || (left == -1 && right == math.MinInt64)) {
+			return nil, expressionError("integer overflow")
+		}
+	case tokenSlash, tokenPercent:
+		switch {
+		case right == 0 && op == tokenSlash:
+			return nil, expressionError("division by zero")
+		case right == 0:
+			return nil, expressionError("modulo by zero")
+		case left == math.MinInt64 && right == -1:
+			return nil, expressionError("integer overflow")
+		case op == tokenSlash:
+			result = left / right
+		default:
+			result = left % right
+		}
+	}
+	return result, nil
+}
+
+// toInteger converts integers, and doubles without a fraction such as
+// numbers captured from JSON, into int64.
+func toInteger(value any) (int64, bool) {
+	if integer, ok := number.ToInt64(value); ok {
+		return integer, true
+	}
+	float, ok := number.ToFloat64(value)
+	if !ok || float != math.Trunc(float) || math.Abs(float) >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(float), true
+}
+
 func mustBool(value any) (bool, error) {
 	boolean, ok := value.(bool)
 	if !ok {
@@ -124,6 +426,11 @@ func compareValues(left any, right any) (bool, error) {
 		if !leftIsNumber || !rightIsNumber {
 			return false, expressionError("cannot compare %T and %T", left, right)
 		}
+		leftInt, leftIsInt := number.ToInt64(left)
+		rightInt, rightIsInt := number.ToInt64(right)
+		if leftIsInt && rightIsInt {
+			return leftInt == rightInt, nil
+		}
 		return nearlyEqual(leftNumber, rightNumber), nil
 	}
 
@@ -197,11 +504,18 @@ func validateBooleanExpression(root node) error {
 			return nil
 		}
 		return expressionError("expression must evaluate to boolean, got %T", current.value)
-	case identifierNode:
+	case identifierNode, memberNode, indexNode:
+		return nil
+	case callNode:
+		if !functions[current.name].boolean {
+			return expressionError("expression must evaluate to boolean, %s() does not", current.name)
+		}
 		return nil
+	case listNode:
+		return expressionError("expression must evaluate to boolean, got list")
 	case unaryNode:
 		if current.op != tokenNot {
-			return expressionError("unsupported unary operator")
+			return expressionError("expression must evaluate to boolean, got number")
 		}
 		return validateBooleanExpression(current.right)
 	case binaryNode:
@@ -214,10 +528,10 @@ func validateBooleanExpression(root node) error {
 				return err
 			}
 			return nil
-		case tokenEqual, tokenNotEqual:
+		case tokenEqual, tokenNotEqual, tokenLess, tokenLessEqual, tokenGreater, tokenGreaterEqual, tokenIn:
 			return nil
 		default:
-			return expressionError("unsupported binary operator")
+			return expressionError("expression must evaluate to boolean, got arithmetic")
 		}
 	default:
 		return expressionError("unsupported expression node")
@@ -1,6 +1,9 @@
 package expr
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestEval(t *testing.T) {
 	t.Parallel()
@@ -81,6 +84,160 @@ func TestEval(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "all_macro",
+			expr: "json.items.all(i, i.price > 0)",
+			variables: map[string]any{
+				"json": map[string]any{"items": []any{
+					map[string]any{"price": 10.5},
+					map[string]any{"price": float64(3)},
+				}},
+			},
+			want: true,
+		},
+		{
+			name: "exists_one_macro",
+			expr: "json.items.exists_one(i, i.sku == 'b')",
+			variables: map[string]any{
+				"json": map[string]any{"items": []any{
+					map[string]any{"sku": "a"},
+					map[string]any{"sku": "b"},
+				}},
+			},
+			want: true,
+		},
+		{
+			name: "filter_and_size",
+			expr: "size(json.items.filter(i, i > 1)) == 2",
+			variables: map[string]any{
+				"json": map[string]any{"items": []any{1.0, 2.0, 3.0}},
+			},
+			want: true,
+		},
+		{
+			name: "map_over_map_keys",
+			expr: "headers.map(k, k.upperAscii()) == ['A', 'B']",
+			variables: map[string]any{
+				"headers": map[string]any{"b": "2", "a": "1"},
+			},
+			want: true,
+		},
+		{
+			name: "index_and_arithmetic",
+			expr: "json.items[1] * 2 + 1 == 5 && -json.items[0] < 0",
+			variables: map[string]any{
+				"json": map[string]any{"items": []any{1.0, 2.0}},
+			},
+			want: true,
+		},
+		{
+			name: "map_index",
+			expr: "headers['content-type'].startsWith('application/json')",
+			variables: map[string]any{
+				"headers": map[string]any{"content-type": "application/json; charset=utf-8"},
+			},
+			want: true,
+		},
+		{
+			name: "in_list_and_map",
+			expr: "status in [200, 201] && 'id' in json",
+			variables: map[string]any{
+				"status": 201,
+				"json":   map[string]any{"id": "x"},
+			},
+			want: true,
+		},
+		{
+			name: "string_functions",
+			expr: "name.contains('ob') && name.matches('^b[a-z]+$') && size(name) == 3",
+			variables: map[string]any{
+				"name": "bob",
+			},
+			want: true,
+		},
+		{
+			name: "has_macro",
+			expr: "has(json.id) && !has(json.missing)",
+			variables: map[string]any{
+				"json": map[string]any{"id": 1},
+			},
+			want: true,
+		},
+		{
+			name: "dotted_variable",
+			expr: "db.host == 'localhost'",
+			variables: map[string]any{
+				"db.host": "localhost",
+			},
+			want: true,
+		},
+		{
+			name: "missing_key",
+			expr: "json.missing == 1",
+			variables: map[string]any{
+				"json": map[string]any{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "index_out_of_range",
+			expr: "json[3] == 1",
+			variables: map[string]any{
+				"json": []any{1.0},
+			},
+			wantErr: true,
+		},
+		{
+			name: "division_by_zero",
+			expr: "1 / zero == 1",
+			variables: map[string]any{
+				"zero": 0,
+			},
+			wantErr: true,
+		},
+		{
+			name: "json_number_beyond_float_precision",
+			expr: "json.id == 9007199254740993 && json.id != 9007199254740992 && json.id > 9007199254740992",
+			variables: map[string]any{
+				"json": map[string]any{"id": json.Number("9007199254740993")},
+			},
+			want: true,
+		},
+		{
+			name: "integer_arithmetic",
+			expr: "7 / 2 == 3 && 7 % 2 == 1 && 7.0 / 2 == 3.5 && -json.n * 2 == -9007199254740994",
+			variables: map[string]any{
+				"json": map[string]any{"n": json.Number("4503599627370497")},
+			},
+			want: true,
+		},
+		{
+			name: "int_of_strings",
+			expr: "int('42') == 42 && int(' 4.7 ') == 4 && int(json.big) == 9007199254740993 && string(int('9007199254740993')) == '9007199254740993'",
+			variables: map[string]any{
+				"json": map[string]any{"big": "9007199254740993"},
+			},
+			want: true,
+		},
+		{
+			name:    "int_of_non_numeric_string",
+			expr:    "int('forty') == 40",
+			wantErr: true,
+		},
+		{
+			name:    "integer_overflow",
+			expr:    "9223372036854775807 + 1 > 0",
+			wantErr: true,
+		},
+		{
+			name: "index_with_captured_double",
+			expr: "items[i] == 'b'",
+			variables: map[string]any{
+				"items": []any{"a", "b"},
+				"i":     1.0,
+			},
+			want: true,
+		},
 		{
 			name: "non_boolean_root",
 			expr: "status_code",
@@ -147,6 +304,12 @@ func TestValidateBoolean(t *testing.T) {
 		{name: "string_literal", expr: "'ok'", wantErr: true},
 		{name: "null_literal", expr: "null", wantErr: true},
 		{name: "invalid_boolean_operand", expr: "is_ready && 1", wantErr: true},
+		{name: "relation", expr: "json.count >= 1", wantErr: false},
+		{name: "boolean_method", expr: "json.items.all(i, i > 0)", wantErr: false},
+		{name: "non_boolean_method", expr: "json.items.map(i, i)", wantErr: true},
+		{name: "arithmetic_root", expr: "a + 1", wantErr: true},
+		{name: "unknown_function", expr: "nope(a)", wantErr: true},
+		{name: "macro_needs_identifier", expr: "items.all('i', true)", wantErr: true},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,314 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jacoelho/rq/internal/rq/number"
+)
+
+// function describes a callable name. Globals are called as name(args) and
+// methods as receiver.name(args); args does not count the receiver. A macro
+// binds its first argument, an identifier, to each element of the receiver
+// while it evaluates the second.
+type function struct {
+	global  bool
+	method  bool
+	args    int
+	macro   bool
+	boolean bool
+}
+
+var functions = map[string]function{
+	"size":       {global: true, method: true, args: 1},
+	"has":        {global: true, args: 1, boolean: true},
+	"int":        {global: true, args: 1},
+	"double":     {global: true, args: 1},
+	"string":     {global: true, args: 1},
+	"contains":   {method: true, args: 1, boolean: true},
+	"startsWith": {method: true, args: 1, boolean: true},
+	"endsWith":   {method: true, args: 1, boolean: true},
+	"matches":    {method: true, args: 1, boolean: true},
+	"lowerAscii": {method: true},
+	"upperAscii": {method: true},
+	"all":        {method: true, args: 2, macro: true, boolean: true},
+	"exists":     {method: true, args: 2, macro: true, boolean: true},
+	"exists_one": {method: true, args: 2, macro: true, boolean: true},
+	"filter":     {method: true, args: 2, macro: true},
+	"map":        {method: true, args: 2, macro: true},
+}
+
+// checkCall validates a call against the function table when it is parsed.
+func checkCall(call callNode) error {
+	fn, ok := functions[call.name]
+	if !ok {
+		return fmt.Errorf("unknown function %q", call.name)
+	}
+
+	args := fn.args
+	switch {
+	case call.target == nil && !fn.global:
+		return fmt.Errorf("%s must be called on a value, as in x.%s(...)", call.name, call.name)
+	case call.target != nil && !fn.method:
+		return fmt.Errorf("%s cannot be called as a method", call.name)
+	case call.target != nil && call.name == "size":
+		args = 0
+	}
+	if len(call.args) != args {
+		return fmt.Errorf("%s expects %d argument(s), got %d", call.name, args, len(call.args))
+	}
+
+	if fn.macro {
+		if _, ok := call.args[0].(identifierNode); !ok {
+			return fmt.Errorf("first argument of %s must be a variable name", call.name)
+		}
+	}
+	if call.name == "has" {
+		if _, ok := call.args[0].(memberNode); !ok {
+			return errors.New("has expects a field selection, as in has(json.name)")
+		}
+	}
+
+	return nil
+}
+
+func (e *evaluator) call(current callNode) (any, error) {
+	switch current.name {
+	case "has":
+		return e.has(current.args[0].(memberNode))
+	case "all", "exists", "exists_one", "filter", "map":
+		return e.macro(current)
+	}
+
+	var receiver any
+	args := current.args
+	if current.target != nil {
+		value, err := e.eval(current.target)
+		if err != nil {
+			return nil, err
+		}
+		receiver = value
+	} else {
+		value, err := e.eval(args[0])
+		if err != nil {
+			return nil, err
+		}
+		receiver, args = value, args[1:]
+	}
+
+	values := make([]any, 0, len(args))
+	for _, arg := range args {
+		value, err := e.eval(arg)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return callFunction(current.name, receiver, values)
+}
+
+func callFunction(name string, receiver any, args []any) (any, error) {
+	switch name {
+	case "size":
+		switch value := receiver.(type) {
+		case string:
+			return int64(utf8.RuneCountInString(value)), nil
+		case []any:
+			return int64(len(value)), nil
+		case map[string]any:
+			return int64(len(value)), nil
+		}
+	case "int":
+		if value, ok := toInt(receiver); ok {
+			return value, nil
+		}
+	case "double":
+		if value, ok := toNumber(receiver); ok {
+			return value, nil
+		}
+	case "string":
+		return toString(receiver), nil
+	case "contains", "startsWith", "endsWith", "matches":
+		text, ok := receiver.(string)
+		arg, argOK := args[0].(string)
+		if !ok || !argOK {
+			return nil, expressionError("%s expects strings, got %T and %T", name, receiver, args[0])
+		}
+		return stringPredicate(name, text, arg)
+	case "lowerAscii", "upperAscii":
+		if text, ok := receiver.(string); ok {
+			return mapASCII(text, name == "upperAscii"), nil
+		}
+	}
+
+	return nil, expressionError("%s does not support %T", name, receiver)
+}
+
+// toInt converts integers, numeric strings such as "42" or "4.7" and doubles
+// for int(), truncating any fraction. Values outside the int64 range are not
+// converted.
+func toInt(value any) (int64, bool) {
+	if integer, ok := number.ToInt64(value); ok {
+		return integer, true
+	}
+	if text, ok := value.(string); ok {
+		if integer, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64); err == nil {
+			return integer, true
+		}
+	}
+
+	float, ok := toNumber(value)
+	if !ok || math.IsNaN(float) || math.Abs(float) >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(float), true
+}
+
+// toNumber converts numbers and numeric strings for int() and double().
+func toNumber(value any) (float64, bool) {
+	if text, ok := value.(string); ok {
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		return parsed, err == nil
+	}
+	return number.ToFloat64(value)
+}
+
+func toString(value any) string {
+	if value == nil {
+		return "null"
+	}
+	if text, ok := value.(string); ok {
+		return text
+	}
+	if value, ok := number.ToInt64(value); ok {
+		return strconv.FormatInt(value, 10)
+	}
+	if value, ok := number.ToFloat64(value); ok {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+func stringPredicate(name, text, arg string) (bool, error) {
+	switch name {
+	case "contains":
+		return strings.Contains(text, arg), nil
+	case "startsWith":
+		return strings.HasPrefix(text, arg), nil
+	case "endsWith":
+		return strings.HasSuffix(text, arg), nil
+	}
+
+	pattern, err := regexp.Compile(arg)
+	if err != nil {
+		return false, expressionError("invalid pattern %q: %v", arg, err)
+	}
+	return pattern.MatchString(text), nil
+}
+
+func mapASCII(text string, upper bool) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case upper && r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case !upper && r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return r
+	}, text)
+}
+
+// has reports whether the map selected by the target of member has the
+// field, without failing when it does not.
+func (e *evaluator) has(member memberNode) (any, error) {
+	if _, ok := e.dotted(member); ok {
+		return true, nil
+	}
+
+	target, err := e.eval(member.target)
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := target.(map[string]any)
+	if !ok {
+		return nil, expressionError("has expects a map, got %T", target)
+	}
+
+	_, found := fields[member.name]
+	return found, nil
+}
+
+// macro evaluates all, exists, exists_one, filter and map over the elements
+// of a list or the keys of a map, in sorted order.
+func (e *evaluator) macro(current callNode) (any, error) {
+	receiver, err := e.eval(current.target)
+	if err != nil {
+		return nil, err
+	}
+
+	var elements []any
+	switch value := receiver.(type) {
+	case []any:
+		elements = value
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			elements = append(elements, key)
+		}
+	default:
+		return nil, expressionError("%s expects a list or map, got %T", current.name, receiver)
+	}
+
+	name := current.args[0].(identifierNode).name
+	body := current.args[1]
+
+	matches := 0
+	var results []any
+	for _, element := range elements {
+		e.bound = append(e.bound, binding{name: name, value: element})
+		value, err := e.eval(body)
+		e.bound = e.bound[:len(e.bound)-1]
+		if err != nil {
+			return nil, err
+		}
+
+		if current.name == "map" {
+			results = append(results, value)
+			continue
+		}
+
+		matched, err := mustBool(value)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case current.name == "all" && !matched:
+			return false, nil
+		case current.name == "exists" && matched:
+			return true, nil
+		case matched:
+			matches++
+			results = append(results, element)
+		}
+	}
+
+	switch current.name {
+	case "all":
+		return true, nil
+	case "exists":
+		return false, nil
+	case "exists_one":
+		return matches == 1, nil
+	}
+	if results == nil {
+		results = []any{}
+	}
+	return results, nil
+}
@@ -23,6 +23,20 @@ const (
 	tokenNot
 	tokenLParen
 	tokenRParen
+	tokenLess
+	tokenLessEqual
+	tokenGreater
+	tokenGreaterEqual
+	tokenIn
+	tokenPlus
+	tokenMinus
+	tokenStar
+	tokenSlash
+	tokenPercent
+	tokenDot
+	tokenComma
+	tokenLBracket
+	tokenRBracket
 )
 
 type token struct {
@@ -56,6 +70,8 @@ func lex(input string) ([]token, error) {
 				tokens = append(tokens, token{typ: tokenFalse, pos: start})
 			case "null":
 				tokens = append(tokens, token{typ: tokenNull, pos: start})
+			case "in":
+				tokens = append(tokens, token{typ: tokenIn, pos: start})
 			default:
 				tokens = append(tokens, token{typ: tokenIdentifier, literal: literal, pos: start})
 			}
@@ -82,6 +98,12 @@ func lex(input string) ([]token, error) {
 			continue
 		}
 
+		if typ, ok := punctuation[input[pos]]; ok {
+			tokens = append(tokens, token{typ: typ, pos: pos})
+			pos++
+			continue
+		}
+
 		switch input[pos] {
 		case '=':
 			if pos+1 < len(input) && input[pos+1] == '=' {
@@ -113,12 +135,17 @@ func lex(input string) ([]token, error) {
 				continue
 			}
 			return nil, expressionError("unexpected '|' at position %d", pos)
-		case '(':
-			tokens = append(tokens, token{typ: tokenLParen, pos: pos})
-			pos++
-			continue
-		case ')':
-			tokens = append(tokens, token{typ: tokenRParen, pos: pos})
+		case '<', '>':
+			typ, orEqual := tokenLess, tokenLessEqual
+			if input[pos] == '>' {
+				typ, orEqual = tokenGreater, tokenGreaterEqual
+			}
+			if pos+1 < len(input) && input[pos+1] == '=' {
+				tokens = append(tokens, token{typ: orEqual, pos: pos})
+				pos += 2
+				continue
+			}
+			tokens = append(tokens, token{typ: typ, pos: pos})
 			pos++
 			continue
 		default:
@@ -130,6 +157,21 @@ func lex(input string) ([]token, error) {
 	return tokens, nil
 }
 
+// punctuation maps the single-character tokens.
+var punctuation = map[byte]tokenType{
+	'(': tokenLParen,
+	')': tokenRParen,
+	'[': tokenLBracket,
+	']': tokenRBracket,
+	'.': tokenDot,
+	',': tokenComma,
+	'+': tokenPlus,
+	'-': tokenMinus,
+	'*': tokenStar,
+	'/': tokenSlash,
+	'%': tokenPercent,
+}
+
 func isIdentifierStart(r rune) bool {
 	return r == '_' || unicode.IsLetter(r)
 }
@@ -138,25 +180,14 @@ func isIdentifierPart(r rune) bool {
 	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
 }
 
+// isNumberStart reports whether a number literal starts at pos. A leading
+// minus is lexed as its own token and applied as unary negation.
 func isNumberStart(input string, pos int) bool {
-	if pos >= len(input) {
-		return false
-	}
-	if input[pos] >= '0' && input[pos] <= '9' {
-		return true
-	}
-	if input[pos] == '-' {
-		return pos+1 < len(input) && input[pos+1] >= '0' && input[pos+1] <= '9'
-	}
-	return false
+	return pos < len(input) && input[pos] >= '0' && input[pos] <= '9'
 }
 
 func lexNumber(input string, start int) (token, int, error) {
 	pos := start
-	if input[pos] == '-' {
-		pos++
-	}
-
 	digitStart := pos
 	for pos < len(input) && input[pos] >= '0' && input[pos] <= '9' {
 		pos++
@@ -1,6 +1,10 @@
 package expr
 
-import "strconv"
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
 
 type node interface{}
 
@@ -23,6 +27,30 @@ type binaryNode struct {
 	right node
 }
 
+// memberNode selects the field name of a map, as in json.items.
+type memberNode struct {
+	target node
+	name   string
+}
+
+// indexNode selects a list element or a map entry, as in items[0].
+type indexNode struct {
+	target node
+	index  node
+}
+
+type listNode struct {
+	items []node
+}
+
+// callNode calls a function, or a method on target when target is set, as
+// in size(items) or name.startsWith('a').
+type callNode struct {
+	target node
+	name   string
+	args   []node
+}
+
 type parserState struct {
 	tokens []token
 	pos    int
@@ -92,19 +120,32 @@ func (p *parserState) parseAnd() (node, error) {
 }
 
 func (p *parserState) parseEquality() (node, error) {
-	left, err := p.parseUnary()
+	return p.parseBinary(p.parseRelation, tokenEqual, tokenNotEqual)
+}
+
+func (p *parserState) parseRelation() (node, error) {
+	return p.parseBinary(p.parseAdditive, tokenLess, tokenLessEqual, tokenGreater, tokenGreaterEqual, tokenIn)
+}
+
+func (p *parserState) parseAdditive() (node, error) {
+	return p.parseBinary(p.parseMultiplicative, tokenPlus, tokenMinus)
+}
+
+func (p *parserState) parseMultiplicative() (node, error) {
+	return p.parseBinary(p.parseUnary, tokenStar, tokenSlash, tokenPercent)
+}
+
+// parseBinary parses a left-associative chain of the operators ops over
+// operands parsed by next.
+func (p *parserState) parseBinary(next func() (node, error), ops ...tokenType) (node, error) {
+	left, err := next()
 	if err != nil {
 		return nil, err
 	}
 
-	for {
-		typ := p.current().typ
-		if typ != tokenEqual && typ != tokenNotEqual {
-			break
-		}
-
+	for slices.Contains(ops, p.current().typ) {
 		op := p.advance().typ
-		right, err := p.parseUnary()
+		right, err := next()
 		if err != nil {
 			return nil, err
 		}
@@ -115,7 +156,7 @@ func (p *parserState) parseEquality() (node, error) {
 }
 
 func (p *parserState) parseUnary() (node, error) {
-	if p.current().typ == tokenNot {
+	if typ := p.current().typ; typ == tokenNot || typ == tokenMinus {
 		op := p.advance().typ
 		right, err := p.parseUnary()
 		if err != nil {
@@ -124,7 +165,84 @@ func (p *parserState) parseUnary() (node, error) {
 		return unaryNode{op: op, right: right}, nil
 	}
 
-	return p.parsePrimary()
+	return p.parsePostfix()
+}
+
+// parsePostfix parses member access, indexing and method calls after a
+// primary expression.
+func (p *parserState) parsePostfix() (node, error) {
+	target, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.current().typ {
+		case tokenDot:
+			p.advance()
+			name := p.current()
+			if name.typ != tokenIdentifier {
+				return nil, expressionError("expected field name at position %d", name.pos)
+			}
+			p.advance()
+			if p.current().typ != tokenLParen {
+				target = memberNode{target: target, name: name.literal}
+				continue
+			}
+			target, err = p.parseCall(target, name)
+			if err != nil {
+				return nil, err
+			}
+		case tokenLBracket:
+			p.advance()
+			index, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenRBracket, "']'"); err != nil {
+				return nil, err
+			}
+			target = indexNode{target: target, index: index}
+		default:
+			return target, nil
+		}
+	}
+}
+
+// parseCall parses the arguments of a call to name and checks them against
+// the function table, so unknown functions fail before the expression runs.
+func (p *parserState) parseCall(target node, name token) (node, error) {
+	p.advance()
+
+	var args []node
+	for p.current().typ != tokenRParen {
+		if len(args) > 0 {
+			if err := p.expect(tokenComma, "',' or ')'"); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.advance()
+
+	call := callNode{target: target, name: name.literal, args: args}
+	if err := checkCall(call); err != nil {
+		return nil, expressionError("%v at position %d", err, name.pos)
+	}
+
+	return call, nil
+}
+
+func (p *parserState) expect(typ tokenType, what string) error {
+	if tok := p.current(); tok.typ != typ {
+		return expressionError("expected %s at position %d", what, tok.pos)
+	}
+	p.advance()
+	return nil
 }
 
 func (p *parserState) parsePrimary() (node, error) {
@@ -132,9 +250,19 @@ func (p *parserState) parsePrimary() (node, error) {
 	switch tok.typ {
 	case tokenIdentifier:
 		p.advance()
+		if p.current().typ == tokenLParen {
+			return p.parseCall(nil, tok)
+		}
 		return identifierNode{name: tok.literal}, nil
 	case tokenNumber:
 		p.advance()
+		if !strings.Contains(tok.literal, ".") {
+			value, err := strconv.ParseInt(tok.literal, 10, 64)
+			if err != nil {
+				return nil, expressionError("integer literal %q at position %d is out of range", tok.literal, tok.pos)
+			}
+			return literalNode{value: value}, nil
+		}
 		value, err := strconv.ParseFloat(tok.literal, 64)
 		if err != nil {
 			return nil, expressionError("invalid number literal %q at position %d", tok.literal, tok.pos)
@@ -163,6 +291,23 @@ func (p *parserState) parsePrimary() (node, error) {
 		}
 		p.advance()
 		return expr, nil
+	case tokenLBracket:
+		p.advance()
+		var items []node
+		for p.current().typ != tokenRBracket {
+			if len(items) > 0 {
+				if err := p.expect(tokenComma, "',' or ']'"); err != nil {
+					return nil, err
+				}
+			}
+			item, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		p.advance()
+		return listNode{items: items}, nil
 	default:
 		return nil, expressionError("unexpected token at position %d", tok.pos)
 	}
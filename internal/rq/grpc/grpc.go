@@ -0,0 +1,138 @@
+// Package grpc encodes and decodes unary gRPC messages described by a
+// protobuf descriptor set, so steps can call gRPC services with JSON request
+// and response bodies.
+package grpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ContentType is the media type of gRPC requests over HTTP/2.
+const ContentType = "application/grpc"
+
+// prefixLength is the size of the message prefix: a compression flag and a
+// big-endian message length.
+const prefixLength = 5
+
+// LoadDescriptorSet reads a binary FileDescriptorSet, as written by
+// protoc --descriptor_set_out or buf build -o, with its imports included.
+func LoadDescriptorSet(path string) (*protoregistry.Files, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse descriptor set %s: %w", path, err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("load descriptor set %s: %w", path, err)
+	}
+
+	return files, nil
+}
+
+// Method is a unary RPC resolved from a descriptor set.
+type Method struct {
+	desc protoreflect.MethodDescriptor
+}
+
+// FindMethod resolves method of the fully qualified service, e.g.
+// orders.v1.Orders. Streaming methods are rejected.
+func FindMethod(files *protoregistry.Files, service, method string) (*Method, error) {
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found in descriptor set", service)
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+
+	methodDesc := serviceDesc.Methods().ByName(protoreflect.Name(method))
+	if methodDesc == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", method, service)
+	}
+	if methodDesc.IsStreamingClient() || methodDesc.IsStreamingServer() {
+		return nil, fmt.Errorf("method %s.%s is streaming; only unary methods are supported", service, method)
+	}
+
+	return &Method{desc: methodDesc}, nil
+}
+
+// Path returns the HTTP/2 request path of the method, /service/method.
+func (m *Method) Path() string {
+	return "/" + string(m.desc.Parent().FullName()) + "/" + string(m.desc.Name())
+}
+
+// EncodeRequest converts a JSON request message to its binary form and
+// prefixes it for the wire. An empty message encodes the zero value.
+func (m *Method) EncodeRequest(message []byte) ([]byte, error) {
+	request := dynamicpb.NewMessage(m.desc.Input())
+	if len(message) > 0 {
+		if err := protojson.Unmarshal(message, request); err != nil {
+			return nil, fmt.Errorf("invalid %s message: %w", m.desc.Input().FullName(), err)
+		}
+	}
+
+	data, err := proto.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return Frame(data), nil
+}
+
+// DecodeResponse converts a prefixed binary response message to JSON.
+func (m *Method) DecodeResponse(body []byte) ([]byte, error) {
+	data, err := Unframe(body)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dynamicpb.NewMessage(m.desc.Output())
+	if err := proto.Unmarshal(data, response); err != nil {
+		return nil, fmt.Errorf("invalid %s message: %w", m.desc.Output().FullName(), err)
+	}
+
+	return protojson.Marshal(response)
+}
+
+// Frame prefixes an uncompressed message for the wire.
+func Frame(message []byte) []byte {
+	frame := make([]byte, prefixLength, prefixLength+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	return append(frame, message...)
+}
+
+// Unframe returns the single message carried by body.
+func Unframe(body []byte) ([]byte, error) {
+	if len(body) < prefixLength {
+		return nil, errors.New("response is missing the grpc message prefix")
+	}
+	if body[0] != 0 {
+		return nil, errors.New("compressed grpc messages are not supported")
+	}
+
+	length := binary.BigEndian.Uint32(body[1:prefixLength])
+	message := body[prefixLength:]
+	if uint64(len(message)) != uint64(length) {
+		return nil, fmt.Errorf("grpc message declares %d bytes, got %d", length, len(message))
+	}
+
+	return message, nil
+}
@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"strings"
+	"testing"
+)
+
+// ordersProtoset is the descriptor set of testdata/orders.proto, shared with
+// the execute tests.
+const ordersProtoset = "testdata/orders.protoset"
+
+func TestMethodRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	method := mustFindMethod(t, "orders.v1.Orders", "GetOrder")
+	if got := method.Path(); got != "/orders.v1.Orders/GetOrder" {
+		t.Fatalf("Path() = %q", got)
+	}
+
+	frame, err := method.EncodeRequest([]byte(`{"id": "42"}`))
+	if err != nil {
+		t.Fatalf("EncodeRequest() error = %v", err)
+	}
+	if frame[0] != 0 || len(frame) != prefixLength+4 {
+		t.Fatalf("EncodeRequest() = %x", frame)
+	}
+
+	// GetOrderRequest and Order share field 1, so the request decodes as an
+	// order with the same id.
+	decoded, err := method.DecodeResponse(frame)
+	if err != nil {
+		t.Fatalf("DecodeResponse() error = %v", err)
+	}
+	if got := strings.ReplaceAll(string(decoded), " ", ""); got != `{"id":"42"}` {
+		t.Fatalf("DecodeResponse() = %s", decoded)
+	}
+}
+
+func TestEncodeRequestInvalidMessage(t *testing.T) {
+	t.Parallel()
+
+	method := mustFindMethod(t, "orders.v1.Orders", "GetOrder")
+	if _, err := method.EncodeRequest([]byte(`{"unknown": 1}`)); err == nil {
+		t.Fatal("EncodeRequest() expected error for unknown field")
+	}
+}
+
+func TestFindMethodErrors(t *testing.T) {
+	t.Parallel()
+
+	files, err := LoadDescriptorSet(ordersProtoset)
+	if err != nil {
+		t.Fatalf("LoadDescriptorSet() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		service string
+		method  string
+		want    string
+	}{
+		{name: "unknown_service", service: "orders.v1.Missing", method: "GetOrder", want: "not found"},
+		{name: "not_a_service", service: "orders.v1.Order", method: "GetOrder", want: "not a service"},
+		{name: "unknown_method", service: "orders.v1.Orders", method: "DeleteOrder", want: "not found"},
+		{name: "streaming_method", service: "orders.v1.Orders", method: "WatchOrders", want: "streaming"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := FindMethod(files, tt.service, tt.method)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("FindMethod() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnframe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		body    []byte
+		want    string
+		wantErr bool
+	}{
+		{name: "message", body: Frame([]byte("abc")), want: "abc"},
+		{name: "empty_message", body: Frame(nil), want: ""},
+		{name: "missing_prefix", body: []byte{0, 0}, wantErr: true},
+		{name: "compressed", body: []byte{1, 0, 0, 0, 0}, wantErr: true},
+		{name: "truncated", body: []byte{0, 0, 0, 0, 4, 'a'}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Unframe(tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unframe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("Unframe() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func mustFindMethod(t *testing.T, service, method string) *Method {
+	t.Helper()
+
+	files, err := LoadDescriptorSet(ordersProtoset)
+	if err != nil {
+		t.Fatalf("LoadDescriptorSet() error = %v", err)
+	}
+	found, err := FindMethod(files, service, method)
+	if err != nil {
+		t.Fatalf("FindMethod() error = %v", err)
+	}
+
+	return found
+}
@@ -7,8 +7,17 @@ import (
 	"time"
 )
 
-// New creates a tuned HTTP client for rq execution.
-func New(tlsConfig *tls.Config, timeout time.Duration) *http.Client {
+// DefaultMaxConnsPerHost is the connection limit per host used unless
+// --max-conns-per-host overrides it.
+const DefaultMaxConnsPerHost = 50
+
+// New creates a tuned HTTP client for rq execution. maxConnsPerHost caps the
+// connections open to one host at a time; zero uses DefaultMaxConnsPerHost.
+func New(tlsConfig *tls.Config, timeout time.Duration, maxConnsPerHost int) *http.Client {
+	if maxConnsPerHost <= 0 {
+		maxConnsPerHost = DefaultMaxConnsPerHost
+	}
+
 	dialer := &net.Dialer{
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
@@ -24,7 +33,7 @@ func New(tlsConfig *tls.Config, timeout time.Duration) *http.Client {
 		IdleConnTimeout:        60 * time.Second,
 		MaxIdleConns:           100,
 		MaxIdleConnsPerHost:    10,
-		MaxConnsPerHost:        50,
+		MaxConnsPerHost:        maxConnsPerHost,
 		MaxResponseHeaderBytes: 1 << 20, // 1 MiB
 	}
 
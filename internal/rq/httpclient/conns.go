@@ -0,0 +1,108 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ConnStats is the connection usage observed for one host address.
+type ConnStats struct {
+	Host   string
+	Opened int
+	Peak   int
+}
+
+// ConnTracker counts the connections a transport holds open to each host and
+// remembers the highest number held at once. It is safe for concurrent use.
+type ConnTracker struct {
+	mu    sync.Mutex
+	open  map[string]int
+	stats map[string]*ConnStats
+}
+
+// TrackConnections wraps the dialer of transport so every connection it opens
+// is counted. Clones of transport made afterwards share the tracker.
+func TrackConnections(transport *http.Transport) *ConnTracker {
+	tracker := &ConnTracker{
+		open:  make(map[string]int),
+		stats: make(map[string]*ConnStats),
+	}
+
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		tracker.opened(addr)
+		return &trackedConn{Conn: conn, release: func() { tracker.closed(addr) }}, nil
+	}
+
+	return tracker
+}
+
+func (t *ConnTracker) opened(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.open[host]++
+	stats := t.stats[host]
+	if stats == nil {
+		stats = &ConnStats{Host: host}
+		t.stats[host] = stats
+	}
+	stats.Opened++
+	stats.Peak = max(stats.Peak, t.open[host])
+}
+
+func (t *ConnTracker) closed(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.open[host]--
+}
+
+// TakeStats returns the usage recorded since the previous call, sorted by
+// host, and starts a new window. Connections still open count towards the
+// peak of the next window.
+func (t *ConnTracker) TakeStats() []ConnStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]ConnStats, 0, len(t.stats))
+	for _, stats := range t.stats {
+		result = append(result, *stats)
+	}
+	clear(t.stats)
+
+	for host, open := range t.open {
+		if open > 0 {
+			t.stats[host] = &ConnStats{Host: host, Peak: open}
+		}
+	}
+
+	slices.SortFunc(result, func(a, b ConnStats) int {
+		return strings.Compare(a.Host, b.Host)
+	})
+	return result
+}
+
+// trackedConn reports its first Close to the tracker.
+type trackedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
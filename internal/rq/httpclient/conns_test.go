@@ -0,0 +1,74 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConnTrackerPeakRespectsMaxConnsPerHost(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, peakInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := peakInFlight.Load()
+			if current <= seen || peakInFlight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}))
+	t.Cleanup(server.Close)
+
+	client := New(nil, 5*time.Second, 2)
+	tracker := TrackConnections(client.Transport.(*http.Transport))
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Errorf("Get() error = %v", err)
+				return
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	stats := tracker.TakeStats()
+	host := strings.TrimPrefix(server.URL, "http://")
+	if len(stats) != 1 || stats[0].Host != host {
+		t.Fatalf("stats = %+v, want one entry for %s", stats, host)
+	}
+	if stats[0].Peak != 2 || stats[0].Opened < 2 {
+		t.Fatalf("stats = %+v, want peak 2", stats[0])
+	}
+	if got := peakInFlight.Load(); got > 2 {
+		t.Fatalf("server saw %d concurrent requests, want at most 2", got)
+	}
+
+	// Idle keep-alive connections carry over into the next window.
+	if next := tracker.TakeStats(); len(next) != 1 || next[0].Opened != 0 || next[0].Peak != 2 {
+		t.Fatalf("next stats = %+v, want the 2 idle connections", next)
+	}
+
+	client.CloseIdleConnections()
+	if last := tracker.TakeStats(); len(last) != 1 {
+		t.Fatalf("stats after close = %+v", last)
+	}
+	if empty := tracker.TakeStats(); len(empty) != 0 {
+		t.Fatalf("stats after closed connections = %+v, want none", empty)
+	}
+}
@@ -0,0 +1,383 @@
+package i18n
+
+// Message keys. Catalog entries are fmt format strings and take the same
+// arguments as the English entry of the key.
+const (
+	// Command line
+	Usage           Key = "cli.usage"
+	UsageError      Key = "cli.usage_error"
+	Error           Key = "cli.error"
+	ParseArgsError  Key = "cli.parse_args_error"
+	VariableFileErr Key = "cli.variable_file_error"
+	SecretFileErr   Key = "cli.secret_file_error"
+	FileNotFound    Key = "cli.file_not_found"
+	ReportNotFound  Key = "cli.report_not_found"
+	UnknownReport   Key = "cli.unknown_report_command"
+	PlanUsage       Key = "cli.plan_usage"
+	ExportUsage     Key = "cli.export_usage"
+	DocsUsage       Key = "cli.docs_usage"
+	MigrateUsage    Key = "cli.migrate_usage"
+	ReportUsage     Key = "cli.report_usage"
+
+	// Runner
+	CreateRunnerError   Key = "run.create_runner_error"
+	WriteTraceError     Key = "run.write_trace_error"
+	WriteReportError    Key = "run.write_report_error"
+	WriteCapturesError  Key = "run.write_captures_error"
+	FormatResultsError  Key = "run.format_results_error"
+	IterationError      Key = "run.iteration_error"
+	Interrupted         Key = "run.interrupted"
+	InterruptedOf       Key = "run.interrupted_of"
+	RunTimedOut         Key = "run.run_timed_out"
+	IterationHeader     Key = "run.iteration_header"
+	IterationHeaderOf   Key = "run.iteration_header_of"
+	DaemonListenError   Key = "run.daemon_listen_error"
+	DaemonListening     Key = "run.daemon_listening"
+	DaemonRunFailed     Key = "run.daemon_run_failed"
+	DaemonStopped       Key = "run.daemon_stopped"
+	BaselineWriteError  Key = "run.baseline_write_error"
+	BaselineWritten     Key = "run.baseline_written"
+	BaselineNotFound    Key = "run.baseline_not_found"
+	BaselineReadError   Key = "run.baseline_read_error"
+	BaselineRegressions Key = "run.baseline_regressions"
+	BaselineWarnings    Key = "run.baseline_warnings"
+	OpenAPILoadError    Key = "run.openapi_load_error"
+	OpenAPIWarning      Key = "run.openapi_warning"
+	CacheError          Key = "run.cache_error"
+	CleanupError        Key = "run.cleanup_error"
+	ScreenshotError     Key = "run.screenshot_error"
+
+	// Interactive session
+	InteractiveLoaded         Key = "interactive.loaded"
+	InteractivePrompt         Key = "interactive.prompt"
+	InteractiveHelp           Key = "interactive.help"
+	InteractiveNoMoreSteps    Key = "interactive.no_more_steps"
+	InteractiveInvalidStep    Key = "interactive.invalid_step"
+	InteractiveSetUsage       Key = "interactive.set_usage"
+	InteractiveUnknownCommand Key = "interactive.unknown_command"
+	InteractiveStepFailed     Key = "interactive.step_failed"
+	InteractiveStepSkipped    Key = "interactive.step_skipped"
+	InteractiveStepSuccess    Key = "interactive.step_success"
+	InteractiveNoVariables    Key = "interactive.no_variables"
+	InteractivePending        Key = "interactive.pending"
+	InteractivePassed         Key = "interactive.passed"
+	InteractiveFailed         Key = "interactive.failed"
+	InteractiveSkipped        Key = "interactive.skipped"
+
+	// Subcommands
+	WritePlanError    Key = "cmd.write_plan_error"
+	WriteExportError  Key = "cmd.write_export_error"
+	WriteDocsError    Key = "cmd.write_docs_error"
+	WriteMergedError  Key = "cmd.write_merged_error"
+	MissingShards     Key = "cmd.missing_shards"
+	MigrateError      Key = "cmd.migrate_error"
+	MigrateWriteError Key = "cmd.migrate_write_error"
+	MigrateUpToDate   Key = "cmd.migrate_up_to_date"
+	MigrateChange     Key = "cmd.migrate_change"
+
+	// Text summary
+	FileSuccess         Key = "summary.file_success"
+	FileFailed          Key = "summary.file_failed"
+	FileSkipped         Key = "summary.file_skipped"
+	FileResult          Key = "summary.file_result"
+	VariablesBefore     Key = "summary.variables_before"
+	StepAttempts        Key = "summary.step_attempts"
+	StepArtifact        Key = "summary.step_artifact"
+	Attempt             Key = "summary.attempt"
+	NoResponse          Key = "summary.no_response"
+	ExecutedFiles       Key = "summary.executed_files"
+	ExecutedRequests    Key = "summary.executed_requests"
+	SucceededFiles      Key = "summary.succeeded_files"
+	FailedFiles         Key = "summary.failed_files"
+	Duration            Key = "summary.duration"
+	RateLimitWait       Key = "summary.rate_limit_wait"
+	RateLimitHost       Key = "summary.rate_limit_host"
+	PeakConnections     Key = "summary.peak_connections"
+	ConnectionsHost     Key = "summary.connections_host"
+	IterationResults    Key = "summary.iteration_results"
+	IterationSuccess    Key = "summary.iteration_success"
+	IterationFailed     Key = "summary.iteration_failed"
+	IterationResult     Key = "summary.iteration_result"
+	AggregatedResults   Key = "summary.aggregated_results"
+	TotalIterations     Key = "summary.total_iterations"
+	SuccessfulIters     Key = "summary.successful_iterations"
+	FailedIters         Key = "summary.failed_iterations"
+	TotalExecutedFiles  Key = "summary.total_executed_files"
+	TotalExecutedReqs   Key = "summary.total_executed_requests"
+	TotalSucceededFiles Key = "summary.total_succeeded_files"
+	TotalFailedFiles    Key = "summary.total_failed_files"
+	TotalDuration       Key = "summary.total_duration"
+	AvgFiles            Key = "summary.avg_files"
+	AvgRequests         Key = "summary.avg_requests"
+	AvgDuration         Key = "summary.avg_duration"
+	FlakySteps          Key = "summary.flaky_steps"
+	FlakyStep           Key = "summary.flaky_step"
+)
+
+var english = Catalog{
+	UsageError:      "Error: %s\n\n%s",
+	Error:           "Error: %v",
+	ParseArgsError:  "failed to parse arguments: %v",
+	VariableFileErr: "failed to load variable file: %v",
+	SecretFileErr:   "failed to load secret file: %v",
+	FileNotFound:    "test file %s not found: %v",
+	ReportNotFound:  "report %s not found: %v",
+	UnknownReport:   "unknown report command %q",
+
+	CreateRunnerError:   "Error creating runner: %v",
+	WriteTraceError:     "Error writing trace: %v",
+	WriteReportError:    "Error writing report: %v",
+	WriteCapturesError:  "Error writing captures: %v",
+	FormatResultsError:  "Error formatting results: %v",
+	IterationError:      "Error in iteration %d: %v",
+	Interrupted:         "Interrupted after %d iterations",
+	InterruptedOf:       "Interrupted after %d of %d iterations",
+	RunTimedOut:         "Run timed out after %s",
+	IterationHeader:     "--- Iteration %d ---",
+	IterationHeaderOf:   "--- Iteration %d of %d ---",
+	DaemonListenError:   "Error starting daemon listener: %v",
+	DaemonListening:     "Daemon listening on %s, running every %s",
+	DaemonRunFailed:     "Run failed: %v",
+	DaemonStopped:       "Daemon server stopped: %v",
+	BaselineWriteError:  "Error writing baseline: %v",
+	BaselineWritten:     "Baseline written to %s (%d step(s))",
+	BaselineNotFound:    "Error: baseline %s not found, create it with --update-baseline",
+	BaselineReadError:   "Error reading baseline: %v",
+	BaselineRegressions: "Latency regressions beyond %.0f%% of %s:",
+	BaselineWarnings:    "Warning: latency regressions beyond %.0f%% of %s:",
+	OpenAPILoadError:    "Error loading OpenAPI document: %v",
+	OpenAPIWarning:      "Warning: %s: %v",
+	CacheError:          "Warning: response cache: %v",
+	CleanupError:        "Cleanup %s %s failed: %v",
+	ScreenshotError:     "Screenshot of %s failed: %v",
+
+	InteractiveLoaded:         "rq interactive: %d step(s) loaded, type help for commands",
+	InteractivePrompt:         "rq> ",
+	InteractiveNoMoreSteps:    "No more steps; use run N to re-run a step",
+	InteractiveInvalidStep:    "Invalid step %q: expected a number between 0 and %d",
+	InteractiveSetUsage:       "Usage: set NAME=VALUE",
+	InteractiveUnknownCommand: "Unknown command %q, type help for commands",
+	InteractiveStepFailed:     "Step %d %s: Failed: %v (%d ms)",
+	InteractiveStepSkipped:    "Step %d %s: Skipped: when condition is false",
+	InteractiveStepSuccess:    "Step %d %s: Success (%d ms)",
+	InteractiveNoVariables:    "No variables",
+	InteractivePending:        "pending",
+	InteractivePassed:         "passed",
+	InteractiveFailed:         "failed",
+	InteractiveSkipped:        "skipped",
+	InteractiveHelp: `Commands:
+  next, n              Execute the next step (also on an empty line)
+  run N, r N           Execute step N, re-running it if it already ran
+  continue, c          Execute the remaining steps until one fails
+  list, l              List steps with their last result
+  vars, v              Show captured variables (secrets are redacted)
+  set NAME=VALUE       Set a variable for the following steps
+  help, h              Show this help
+  quit, q              Leave the session
+`,
+
+	WritePlanError:    "Error: failed to write plan: %v",
+	WriteExportError:  "Error: failed to write export: %v",
+	WriteDocsError:    "Error: failed to write docs: %v",
+	WriteMergedError:  "Error: failed to write report: %v",
+	MissingShards:     "Warning: no report for shards %s",
+	MigrateError:      "Error: failed to migrate %s: %v",
+	MigrateWriteError: "Error: failed to write %s: %v",
+	MigrateUpToDate:   "%s: up to date",
+	MigrateChange:     "%s: %s",
+
+	FileSuccess:         "Success",
+	FileFailed:          "Failed: %v",
+	FileSkipped:         "Skipped: %s",
+	FileResult:          "%s: %s (%d request(s) in %d ms)",
+	VariablesBefore:     "  variables before step %d:",
+	StepAttempts:        "  %s: %d attempts",
+	StepArtifact:        "  %s: artifact %s",
+	Attempt:             "    #%d %s in %d ms",
+	NoResponse:          "no response",
+	ExecutedFiles:       "Executed files:    %d",
+	ExecutedRequests:    "Executed requests: %d (%.2f/s)",
+	SucceededFiles:      "Succeeded files:   %d (%.1f%%)",
+	FailedFiles:         "Failed files:      %d (%.1f%%)",
+	Duration:            "Duration:          %d ms",
+	RateLimitWait:       "Rate limit wait:   %d ms (%.1f%% of duration)",
+	RateLimitHost:       "  %s: %d request(s), waited %d ms",
+	PeakConnections:     "Peak connections:  %d per host",
+	ConnectionsHost:     "  %s: %d opened, peak %d",
+	IterationResults:    "ITERATION RESULTS:",
+	IterationSuccess:    "SUCCESS",
+	IterationFailed:     "FAILED",
+	IterationResult:     "Iteration %d: %s (%d files, %d requests, %d ms)",
+	AggregatedResults:   "AGGREGATED RESULTS:",
+	TotalIterations:     "Total iterations:    %d",
+	SuccessfulIters:     "Successful iterations: %d (%.1f%%)",
+	FailedIters:         "Failed iterations:   %d (%.1f%%)",
+	TotalExecutedFiles:  "Total executed files: %d",
+	TotalExecutedReqs:   "Total executed requests: %d (%.2f/s)",
+	TotalSucceededFiles: "Total succeeded files: %d",
+	TotalFailedFiles:    "Total failed files:  %d",
+	TotalDuration:       "Total duration:      %d ms",
+	AvgFiles:            "Avg files per iteration: %.1f",
+	AvgRequests:         "Avg requests per iteration: %.1f",
+	AvgDuration:         "Avg duration per iteration: %d ms",
+	FlakySteps:          "FLAKY STEPS:",
+	FlakyStep:           "  %s step %d: failed %d of %d runs (%.1f%%)",
+
+	Usage: `rq - HTTP testing tool
+
+Usage: rq [options] <file1> [file2] ...
+       rq plan [options] <file1> [file2] ...
+       rq migrate [options] <file1> [file2] ...
+       rq docs [options] <file1> [file2] ...
+       rq export [options] <file1> [file2] ...
+       rq report merge [options] <report1.json> [report2.json] ...
+
+Options:
+  --debug                 Enable debug output showing request and response details
+  --debug-body-limit N    Bytes of each body shown by --debug (default: 8192, 0 for unlimited)
+  --repeat N              Number of additional times to repeat after first run (negative for infinite)
+  --parallel N            Number of test files to execute concurrently (0 or 1 for sequential)
+  --shard K/N             Run only shard K of N of the test files, e.g. one per CI job
+  --interactive           Execute steps one at a time from a prompt (type help for commands)
+  --daemon                Run the suite on a schedule and serve /healthz and /metrics
+  --interval DURATION     Delay between runs in daemon mode (default: 30s)
+  --listen ADDR           Address for daemon endpoints (default: :9090)
+  --insecure              Skip TLS certificate verification
+  --cacert FILE           Path to CA certificate file for TLS verification
+  --tls-min VERSION       Minimum TLS version: 1.0, 1.1, 1.2 or 1.3
+  --tls-max VERSION       Maximum TLS version: 1.0, 1.1, 1.2 or 1.3
+  --timeout DURATION      HTTP request timeout (default: 30s)
+  --run-timeout DURATION  Stop the whole run after DURATION with exit code 124 (0 for no limit)
+  --rate-limit N          Rate limit in requests per second (0 for unlimited)
+  --max-response-bytes N  Fail a step when its response body exceeds N bytes (0 for unlimited)
+  --circuit-breaker N     Skip remaining steps for a host after N consecutive connection failures (0 to disable)
+  --max-conns-per-host N  Maximum connections open to one host at a time (default: 50)
+  --output FORMAT         Output format: text or json (default: text)
+  --lang LANG             Language of messages and the text summary (default: en)
+  --trace FILE            Write a Chrome trace-event timeline of the run to FILE
+  --report KIND=FILE      Write a report when the run ends; KIND is junit (can be used multiple times)
+  --meta KEY=VALUE        Attach run metadata to JSON output, reports and metrics (can be used multiple times)
+  --export-captures FILE  Write captures to FILE when the run ends (JSON for .json, else KEY=value)
+  --baseline FILE         Fail when a step is slower than its duration recorded in FILE
+  --update-baseline       Record this run's step durations to the --baseline file
+  --baseline-threshold N  Slowdown in percent tolerated against the baseline (default: 20)
+  --baseline-warn         Report baseline regressions without failing the run
+  --openapi FILE          Validate every response against the matching operation of an OpenAPI 3 FILE
+  --openapi-warn          Report OpenAPI mismatches as warnings instead of failing the step
+  --cache DIR             Reuse responses of steps with options.cacheable, stored in DIR
+  --screenshot-cmd CMD    Render the HTML response of a failed step (HTML on stdin, artifact path as last argument)
+  --artifacts-dir DIR     Directory for failure artifacts such as screenshots (default: rq-artifacts)
+  --secret NAME=VALUE     Secret in format name=value (can be used multiple times)
+  --secret-file FILE      Path to key=value, YAML or JSON file containing secrets
+  --secret-salt SALT      Salt to use for secret redaction hashes (default: current date)
+  --variable NAME=VALUE   Variable in format name=value (can be used multiple times)
+  --variable-file FILE    Path to key=value, YAML or JSON file containing template variables
+  -h, --help              Show this help message
+  -v, --version           Show version information
+
+Examples:
+  rq test.yaml                           # Run test file once
+  rq test.yaml --debug                   # Run with debug output
+  rq test.yaml --rate-limit 5            # Rate limit to 5 requests per second
+  rq test.yaml --repeat 1                # Run test file twice (1 + 1 additional)
+  rq test.yaml --repeat -1               # Run test file infinitely
+  rq test.yaml --daemon --interval 1m    # Monitor every minute, metrics on :9090
+  rq test.yaml --interactive             # Step through the file from a prompt
+  rq file1.yaml file2.yaml              # Run multiple test files in sequence
+  rq test.yaml --secret API_KEY=secret   # Pass secret to test
+  rq test.yaml --variable HOST=localhost # Pass variable to test`,
+
+	PlanUsage: `rq plan - print the resolved steps without sending requests
+
+Usage: rq plan [options] <file1> [file2] ...
+
+Variables are substituted into url, headers, query, body and body_file.
+Captures, secrets and template functions are left as written.
+
+Options:
+  --lang LANG             Language of messages (default: en)
+  --output FORMAT         Output format: yaml or json (default: yaml)
+  --variable NAME=VALUE   Variable in format name=value (can be used multiple times)
+  --variable-file FILE    Path to key=value, YAML or JSON file containing template variables
+  -h, --help              Show this help message
+
+Examples:
+  rq plan test.yaml --variable HOST=localhost
+  rq plan test.yaml --output json`,
+
+	ExportUsage: `rq export - print steps as commands for other tools
+
+Usage: rq export [options] <file1> [file2] ...
+
+Variables are substituted like in rq plan. Captures, secrets and template
+functions are left as written.
+
+Options:
+  --format FORMAT         Export format: curl (default: curl)
+  --variable NAME=VALUE   Variable in format name=value (can be used multiple times)
+  --variable-file FILE    Path to key=value, YAML or JSON file containing template variables
+  --insecure              Add --insecure to every command, as when running with --insecure
+  --cacert FILE           Add --cacert FILE to every command, as when running with --cacert
+  --lang LANG             Language of messages (default: en)
+  -h, --help              Show this help message
+
+Examples:
+  rq export test.yaml --variable host=localhost
+  rq export --format curl --cacert ca.pem test.yaml`,
+
+	DocsUsage: `rq docs - render test files as API documentation
+
+Usage: rq docs [options] <file1> [file2] ...
+
+Each step becomes a section with its description and docs, the request,
+the expected response, captures, checks and the variables it uses. Steps
+with only description and docs describe their file.
+
+Options:
+  --output FORMAT         Output format: markdown or html (default: markdown)
+  --lang LANG             Language of messages (default: en)
+  -h, --help              Show this help message
+
+Examples:
+  rq docs suite/*.yaml > API.md
+  rq docs --output html suite/*.yaml > api.html`,
+
+	MigrateUsage: `rq migrate - upgrade test files to the current schema
+
+Usage: rq migrate [options] <file1> [file2] ...
+
+Lists the rewrites each file needs. Shorthand captures, status and header
+asserts are converted to their structured form; comments and formatting
+outside the rewritten blocks are kept.
+
+Options:
+  --write                 Rewrite files in place
+  --check                 Exit with status 1 when any file needs migration
+  --lang LANG             Language of messages (default: en)
+  -h, --help              Show this help message
+
+Examples:
+  rq migrate tests/*.yaml
+  rq migrate --write tests/*.yaml
+  rq migrate --check tests/*.yaml`,
+
+	ReportUsage: `rq report - combine JSON run reports
+
+Usage: rq report merge [options] <report1.json> [report2.json] ...
+
+Merges reports written by --output json, such as one per --shard job, per
+--repeat run or per environment, into one summary. File results are kept in
+order, counts and durations are added up, and meta keys keep every distinct
+value. A warning lists the shards missing from sharded reports. The exit
+code is 1 when any merged file failed.
+
+Options:
+  -o, --output FILE       Write the merged report to FILE instead of stdout
+  --format FORMAT         Format of the merged report: json, junit or text (default: json)
+  --lang LANG             Language of messages (default: en)
+  -h, --help              Show this help message
+
+Examples:
+  rq report merge shard-*.json -o merged.json
+  rq report merge shard-*.json -o junit.xml --format junit`,
+}
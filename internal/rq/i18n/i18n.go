@@ -0,0 +1,124 @@
+// Package i18n holds the user-facing messages of rq in per-language catalogs
+// so CI output can be localized. English is the fallback for languages
+// without a catalog and for messages a catalog does not translate.
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultLang is the language of the built-in catalog.
+const DefaultLang = "en"
+
+// Key identifies a message in the catalogs.
+type Key string
+
+// Catalog maps message keys to fmt format strings in one language.
+type Catalog map[Key]string
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]Catalog{DefaultLang: english}
+)
+
+// Register adds or replaces the catalog of lang. Catalogs of other languages
+// are expected to register themselves from init.
+func Register(lang string, catalog Catalog) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	catalogs[normalize(lang)] = catalog
+}
+
+// Languages returns the registered languages, sorted.
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// Printer formats messages in one language. The nil Printer prints English.
+type Printer struct {
+	lang    string
+	catalog Catalog
+}
+
+// NewPrinter returns a printer for lang, a tag such as "pt-BR" or a locale
+// such as "pt_BR.UTF-8". A region without its own catalog falls back to its
+// base language and an unknown language falls back to English.
+func NewPrinter(lang string) *Printer {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for tag := normalize(lang); tag != ""; tag = parent(tag) {
+		if catalog, ok := catalogs[tag]; ok {
+			return &Printer{lang: tag, catalog: catalog}
+		}
+	}
+
+	return &Printer{lang: DefaultLang, catalog: english}
+}
+
+// Lang returns the language the printer resolved to.
+func (p *Printer) Lang() string {
+	if p == nil {
+		return DefaultLang
+	}
+	return p.lang
+}
+
+// Text returns the message of key without formatting it.
+func (p *Printer) Text(key Key) string {
+	if p != nil {
+		if message, ok := p.catalog[key]; ok {
+			return message
+		}
+	}
+	if message, ok := english[key]; ok {
+		return message
+	}
+	return string(key)
+}
+
+// Sprintf formats the message of key with args.
+func (p *Printer) Sprintf(key Key, args ...any) string {
+	return fmt.Sprintf(p.Text(key), args...)
+}
+
+// Fprintf formats the message of key with args and writes it to w.
+func (p *Printer) Fprintf(w io.Writer, key Key, args ...any) (int, error) {
+	return fmt.Fprintf(w, p.Text(key), args...)
+}
+
+// Fprintln writes the formatted message of key and a newline to w.
+func (p *Printer) Fprintln(w io.Writer, key Key, args ...any) (int, error) {
+	return fmt.Fprintln(w, p.Sprintf(key, args...))
+}
+
+// normalize turns a language tag or POSIX locale into a lowercase tag:
+// "pt_BR.UTF-8" becomes "pt-br".
+func normalize(lang string) string {
+	lang, _, _ = strings.Cut(lang, ".")
+	lang, _, _ = strings.Cut(lang, "@")
+	lang = strings.ReplaceAll(strings.TrimSpace(lang), "_", "-")
+	return strings.ToLower(lang)
+}
+
+// parent drops the last subtag: "pt-br" becomes "pt" and "pt" becomes "".
+func parent(tag string) string {
+	i := strings.LastIndex(tag, "-")
+	if i < 0 {
+		return ""
+	}
+	return tag[:i]
+}
@@ -0,0 +1,141 @@
+package i18n
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestNewPrinter(t *testing.T) {
+	Register("xx", Catalog{FileSuccess: "Sucesso", FileFailed: "Falhou: %v"})
+	Register("xx-yy", Catalog{FileSuccess: "Sucesso regional"})
+
+	tests := []struct {
+		name     string
+		lang     string
+		wantLang string
+		key      Key
+		args     []any
+		want     string
+	}{
+		{
+			name:     "english",
+			lang:     "en",
+			wantLang: "en",
+			key:      FileFailed,
+			args:     []any{"boom"},
+			want:     "Failed: boom",
+		},
+		{
+			name:     "empty_is_english",
+			lang:     "",
+			wantLang: "en",
+			key:      FileSuccess,
+			want:     "Success",
+		},
+		{
+			name:     "unknown_language_falls_back_to_english",
+			lang:     "zz",
+			wantLang: "en",
+			key:      FileSuccess,
+			want:     "Success",
+		},
+		{
+			name:     "registered_language",
+			lang:     "xx",
+			wantLang: "xx",
+			key:      FileFailed,
+			args:     []any{"boom"},
+			want:     "Falhou: boom",
+		},
+		{
+			name:     "missing_message_falls_back_to_english",
+			lang:     "xx",
+			wantLang: "xx",
+			key:      Duration,
+			args:     []any{12},
+			want:     "Duration:          12 ms",
+		},
+		{
+			name:     "region_with_catalog",
+			lang:     "xx-YY",
+			wantLang: "xx-yy",
+			key:      FileSuccess,
+			want:     "Sucesso regional",
+		},
+		{
+			name:     "region_falls_back_to_base_language",
+			lang:     "xx-YY",
+			wantLang: "xx-yy",
+			key:      FileFailed,
+			args:     []any{"boom"},
+			want:     "Failed: boom",
+		},
+		{
+			name:     "region_without_catalog",
+			lang:     "xx-ZZ",
+			wantLang: "xx",
+			key:      FileSuccess,
+			want:     "Sucesso",
+		},
+		{
+			name:     "posix_locale",
+			lang:     "xx_ZZ.UTF-8",
+			wantLang: "xx",
+			key:      FileSuccess,
+			want:     "Sucesso",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			printer := NewPrinter(tt.lang)
+			if got := printer.Lang(); got != tt.wantLang {
+				t.Errorf("Lang() = %q, want %q", got, tt.wantLang)
+			}
+			if got := printer.Sprintf(tt.key, tt.args...); got != tt.want {
+				t.Errorf("Sprintf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrinterFallbacks(t *testing.T) {
+	t.Parallel()
+
+	var printer *Printer
+	if got := printer.Sprintf(ExecutedFiles, 2); got != "Executed files:    2" {
+		t.Errorf("nil Printer Sprintf() = %q", got)
+	}
+	if got := printer.Lang(); got != DefaultLang {
+		t.Errorf("nil Printer Lang() = %q, want %q", got, DefaultLang)
+	}
+
+	unknown := Key("summary.unknown")
+	if got := NewPrinter("en").Text(unknown); got != string(unknown) {
+		t.Errorf("Text() of unknown key = %q, want the key", got)
+	}
+
+	var buf bytes.Buffer
+	if _, err := printer.Fprintln(&buf, PeakConnections, 3); err != nil {
+		t.Fatalf("Fprintln() error = %v", err)
+	}
+	if got := buf.String(); got != "Peak connections:  3 per host\n" {
+		t.Errorf("Fprintln() = %q", got)
+	}
+}
+
+func TestEnglishCatalogIsComplete(t *testing.T) {
+	t.Parallel()
+
+	for key, message := range english {
+		if message == "" {
+			t.Errorf("english[%q] is empty", key)
+		}
+	}
+	if !slices.Contains(Languages(), DefaultLang) {
+		t.Errorf("Languages() = %v, want %q", Languages(), DefaultLang)
+	}
+}
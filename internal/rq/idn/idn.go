@@ -0,0 +1,43 @@
+// Package idn converts internationalized domain names to their ASCII form
+// with the UTS #46 lookup profile of golang.org/x/net/idna, so labels are
+// mapped and normalized the way browsers resolve them.
+package idn
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalidLabel is returned for hosts that cannot be converted.
+var ErrInvalidLabel = errors.New("invalid domain label")
+
+// ToASCII converts every non-ASCII label of host to its "xn--" form.
+// ASCII hosts are returned unchanged, so IP literals and names that are not
+// valid IDNs, such as labels with underscores, are still accepted.
+func ToASCII(host string) (string, error) {
+	if isASCII(host) {
+		return host, nil
+	}
+	if !utf8.ValidString(host) {
+		return "", fmt.Errorf("%w: %q is not valid UTF-8", ErrInvalidLabel, host)
+	}
+
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %v", ErrInvalidLabel, host, err)
+	}
+
+	return ascii, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package idn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestToASCII(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{name: "ascii unchanged", host: "example.com", want: "example.com"},
+		{name: "german label", host: "münchen.de", want: "xn--mnchen-3ya.de"},
+		{name: "uppercase is lowercased", host: "MÜNCHEN.de", want: "xn--mnchen-3ya.de"},
+		{name: "japanese label", host: "日本語.jp", want: "xn--wgv71a119e.jp"},
+		{name: "only non-ascii label", host: "bücher.example", want: "xn--bcher-kva.example"},
+		{name: "rfc 3492 arabic sample", host: "ليهمابتكلموشعربي؟", want: "xn--egbpdaj6bu4bxfgehfvwxn"},
+		{name: "fullwidth is mapped", host: "ｅｘａｍｐｌｅ.ｃｏｍ", want: "example.com"},
+		{name: "ideographic full stop separates labels", host: "日本語。jp", want: "xn--wgv71a119e.jp"},
+		{name: "sharp s is kept", host: "straße.de", want: "xn--strae-oqa.de"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ToASCII(tt.host)
+			if err != nil {
+				t.Fatalf("ToASCII(%q) error = %v", tt.host, err)
+			}
+			if got != tt.want {
+				t.Fatalf("ToASCII(%q) = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToASCIIInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		host string
+	}{
+		{name: "invalid utf-8", host: "bad\xff.example"},
+		{name: "disallowed rune", host: "a\u2488b.example"},
+		{name: "leading combining mark", host: "\u0301abc.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ToASCII(tt.host); !errors.Is(err, ErrInvalidLabel) {
+				t.Fatalf("ToASCII(%q) error = %v, want ErrInvalidLabel", tt.host, err)
+			}
+		})
+	}
+}
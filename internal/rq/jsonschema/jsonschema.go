@@ -0,0 +1,538 @@
+// Package jsonschema validates decoded JSON values against JSON Schema
+// documents. It implements the keywords used to describe API payloads, as
+// shared by draft 2020-12 and the OpenAPI 3 schema object; unknown keywords
+// are ignored.
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jacoelho/rq/internal/rq/number"
+)
+
+// maxDepth bounds nested schemas, so deeply nested values cannot exhaust the
+// stack. $ref cycles that do not descend into the value are caught earlier,
+// by validator.active.
+const maxDepth = 128
+
+// Violation is one way a value does not match its schema. Path locates the
+// value in JSONPath notation, such as $.items[0].price.
+type Violation struct {
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Schema is a schema inside a document. $ref pointers resolve against the
+// whole document.
+type Schema struct {
+	document any
+	node     any
+}
+
+// New returns the schema at the root of document, a decoded JSON or YAML
+// value.
+func New(document any) *Schema {
+	return &Schema{document: document, node: document}
+}
+
+// Node returns the schema node, a part of the same document.
+func (s *Schema) Node(node any) *Schema {
+	return &Schema{document: s.document, node: node}
+}
+
+// Validate reports every violation of value against the schema. A value
+// that matches has no violations.
+func (s *Schema) Validate(value any) []Violation {
+	v := &validator{document: s.document, patterns: make(map[string]*regexp.Regexp), active: make(map[evaluation]bool)}
+	v.validate(s.node, value, "$", 0)
+	return v.violations
+}
+
+// Resolve returns the node of document at ref, a local reference such as
+// #/components/schemas/Pet.
+func Resolve(document any, ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q: only references within the document are supported", ref)
+	}
+	if pointer == "" {
+		return document, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("unsupported $ref %q: expected a JSON pointer", ref)
+	}
+
+	node := document
+	for token := range strings.SplitSeq(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+
+		switch current := node.(type) {
+		case map[string]any:
+			next, found := current[token]
+			if !found {
+				return nil, fmt.Errorf("unresolved $ref %q", ref)
+			}
+			node = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil, fmt.Errorf("unresolved $ref %q", ref)
+			}
+			node = current[index]
+		default:
+			return nil, fmt.Errorf("unresolved $ref %q", ref)
+		}
+	}
+
+	return node, nil
+}
+
+type validator struct {
+	document   any
+	patterns   map[string]*regexp.Regexp
+	violations []Violation
+
+	// active holds the schemas being evaluated, by the value they are
+	// evaluated against. Entering one again before it returns means a $ref
+	// cycle that never reaches a smaller value, such as
+	// {"anyOf": [{"$ref": "#"}, {"$ref": "#"}]}, which would otherwise branch
+	// exponentially up to maxDepth.
+	active map[evaluation]bool
+}
+
+// evaluation identifies a schema node applied at a value path.
+type evaluation struct {
+	schema uintptr
+	path   string
+}
+
+func (v *validator) fail(path string, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches reports whether value matches node without recording violations.
+func (v *validator) matches(node any, value any, path string, depth int) bool {
+	sub := &validator{document: v.document, patterns: v.patterns, active: v.active}
+	sub.validate(node, value, path, depth)
+	return len(sub.violations) == 0
+}
+
+func (v *validator) validate(node any, value any, path string, depth int) {
+	var schema map[string]any
+	switch current := node.(type) {
+	case bool:
+		if !current {
+			v.fail(path, "no value is allowed here")
+		}
+		return
+	case map[string]any:
+		schema = current
+	default:
+		return
+	}
+
+	if depth > maxDepth {
+		v.fail(path, "schema nesting exceeds %d levels", maxDepth)
+		return
+	}
+
+	key := evaluation{schema: reflect.ValueOf(schema).Pointer(), path: path}
+	if v.active[key] {
+		v.fail(path, "$ref cycle: the schema refers back to itself for the same value")
+		return
+	}
+	v.active[key] = true
+	defer delete(v.active, key)
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := Resolve(v.document, ref)
+		if err != nil {
+			v.fail(path, "%v", err)
+			return
+		}
+		v.validate(target, value, path, depth+1)
+		return
+	}
+
+	if value == nil && schema["nullable"] == true {
+		return
+	}
+
+	if !v.checkType(schema, value, path) {
+		return
+	}
+	v.checkEnum(schema, value, path)
+
+	switch current := value.(type) {
+	case map[string]any:
+		v.checkObject(schema, current, path, depth)
+	case []any:
+		v.checkArray(schema, current, path, depth)
+	case string:
+		v.checkString(schema, current, path)
+	default:
+		if value, ok := number.ToFloat64(value); ok {
+			v.checkNumber(schema, value, path)
+		}
+	}
+
+	v.checkCombinators(schema, value, path, depth)
+}
+
+func (v *validator) checkType(schema map[string]any, value any, path string) bool {
+	var allowed []string
+	switch current := schema["type"].(type) {
+	case string:
+		allowed = []string{current}
+	case []any:
+		for _, item := range current {
+			if name, ok := item.(string); ok {
+				allowed = append(allowed, name)
+			}
+		}
+	default:
+		return true
+	}
+
+	got := typeOf(value)
+	for _, name := range allowed {
+		if name == got || (name == "number" && got == "integer") {
+			return true
+		}
+	}
+
+	v.fail(path, "expected %s, got %s", strings.Join(allowed, " or "), got)
+	return false
+}
+
+func (v *validator) checkEnum(schema map[string]any, value any, path string) {
+	if want, ok := schema["const"]; ok && !equal(value, want) {
+		v.fail(path, "expected %s, got %s", describe(want), describe(value))
+	}
+
+	options, ok := schema["enum"].([]any)
+	if !ok {
+		return
+	}
+	if !slices.ContainsFunc(options, func(option any) bool { return equal(value, option) }) {
+		names := make([]string, 0, len(options))
+		for _, option := range options {
+			names = append(names, describe(option))
+		}
+		v.fail(path, "expected one of %s, got %s", strings.Join(names, ", "), describe(value))
+	}
+}
+
+func (v *validator) checkObject(schema map[string]any, object map[string]any, path string, depth int) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, item := range required {
+			name, ok := item.(string)
+			if !ok {
+				continue
+			}
+			if _, found := object[name]; !found {
+				v.fail(path, "missing required property %q", name)
+			}
+		}
+	}
+
+	if limit, ok := integer(schema["minProperties"]); ok && len(object) < limit {
+		v.fail(path, "expected at least %d properties, got %d", limit, len(object))
+	}
+	if limit, ok := integer(schema["maxProperties"]); ok && len(object) > limit {
+		v.fail(path, "expected at most %d properties, got %d", limit, len(object))
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"]
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		childPath := memberPath(path, name)
+		if property, found := properties[name]; found {
+			v.validate(property, object[name], childPath, depth+1)
+			continue
+		}
+		if !hasAdditional {
+			continue
+		}
+		if allowed, ok := additional.(bool); ok {
+			if !allowed {
+				v.fail(path, "unexpected property %q", name)
+			}
+			continue
+		}
+		v.validate(additional, object[name], childPath, depth+1)
+	}
+}
+
+func (v *validator) checkArray(schema map[string]any, items []any, path string, depth int) {
+	if limit, ok := integer(schema["minItems"]); ok && len(items) < limit {
+		v.fail(path, "expected at least %d items, got %d", limit, len(items))
+	}
+	if limit, ok := integer(schema["maxItems"]); ok && len(items) > limit {
+		v.fail(path, "expected at most %d items, got %d", limit, len(items))
+	}
+
+	if schema["uniqueItems"] == true {
+		for i := range items {
+			for j := range i {
+				if equal(items[i], items[j]) {
+					v.fail(indexPath(path, i), "duplicates item %d", j)
+				}
+			}
+		}
+	}
+
+	itemSchema, ok := schema["items"]
+	if !ok {
+		return
+	}
+	for i, item := range items {
+		v.validate(itemSchema, item, indexPath(path, i), depth+1)
+	}
+}
+
+func (v *validator) checkString(schema map[string]any, value string, path string) {
+	length := utf8.RuneCountInString(value)
+	if limit, ok := integer(schema["minLength"]); ok && length < limit {
+		v.fail(path, "expected at least %d characters, got %d", limit, length)
+	}
+	if limit, ok := integer(schema["maxLength"]); ok && length > limit {
+		v.fail(path, "expected at most %d characters, got %d", limit, length)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := v.pattern(pattern)
+		if err != nil {
+			v.fail(path, "invalid pattern %q: %v", pattern, err)
+		} else if !re.MatchString(value) {
+			v.fail(path, "%q does not match pattern %q", value, pattern)
+		}
+	}
+
+	if format, ok := schema["format"].(string); ok && !validFormat(format, value) {
+		v.fail(path, "%q is not a valid %s", value, format)
+	}
+}
+
+func (v *validator) pattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	v.patterns[pattern] = re
+	return re, nil
+}
+
+func (v *validator) checkNumber(schema map[string]any, value float64, path string) {
+	minimum, hasMinimum := number.ToFloat64(schema["minimum"])
+	maximum, hasMaximum := number.ToFloat64(schema["maximum"])
+
+	// exclusiveMinimum and exclusiveMaximum are numbers since draft 6 and
+	// booleans modifying minimum and maximum in OpenAPI 3.0.
+	if hasMinimum {
+		if schema["exclusiveMinimum"] == true {
+			if value <= minimum {
+				v.fail(path, "expected greater than %v, got %v", minimum, value)
+			}
+		} else if value < minimum {
+			v.fail(path, "expected at least %v, got %v", minimum, value)
+		}
+	}
+	if hasMaximum {
+		if schema["exclusiveMaximum"] == true {
+			if value >= maximum {
+				v.fail(path, "expected less than %v, got %v", maximum, value)
+			}
+		} else if value > maximum {
+			v.fail(path, "expected at most %v, got %v", maximum, value)
+		}
+	}
+	if limit, ok := number.ToFloat64(schema["exclusiveMinimum"]); ok && value <= limit {
+		v.fail(path, "expected greater than %v, got %v", limit, value)
+	}
+	if limit, ok := number.ToFloat64(schema["exclusiveMaximum"]); ok && value >= limit {
+		v.fail(path, "expected less than %v, got %v", limit, value)
+	}
+
+	if step, ok := number.ToFloat64(schema["multipleOf"]); ok && step > 0 {
+		quotient := value / step
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			v.fail(path, "expected a multiple of %v, got %v", step, value)
+		}
+	}
+}
+
+func (v *validator) checkCombinators(schema map[string]any, value any, path string, depth int) {
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, item := range all {
+			v.validate(item, value, path, depth+1)
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		if !slices.ContainsFunc(anyOf, func(item any) bool { return v.matches(item, value, path, depth+1) }) {
+			v.fail(path, "does not match any schema of anyOf")
+		}
+	}
+
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		matched := 0
+		for _, item := range oneOf {
+			if v.matches(item, value, path, depth+1) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.fail(path, "matches %d schemas of oneOf, expected exactly 1", matched)
+		}
+	}
+
+	if not, ok := schema["not"]; ok && v.matches(not, value, path, depth+1) {
+		v.fail(path, "matches the schema of not")
+	}
+}
+
+func typeOf(value any) string {
+	switch current := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		parsed, ok := number.ToFloat64(current)
+		if !ok {
+			return fmt.Sprintf("%T", value)
+		}
+		if parsed == math.Trunc(parsed) && !math.IsInf(parsed, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+}
+
+// equal compares decoded values, treating numbers of any Go type by value,
+// since JSON bodies decode to float64 and YAML schemas to integers.
+func equal(left any, right any) bool {
+	leftNumber, leftIsNumber := number.ToFloat64(left)
+	rightNumber, rightIsNumber := number.ToFloat64(right)
+	if leftIsNumber || rightIsNumber {
+		return leftIsNumber && rightIsNumber && leftNumber == rightNumber
+	}
+
+	switch current := left.(type) {
+	case []any:
+		other, ok := right.([]any)
+		return ok && slices.EqualFunc(current, other, equal)
+	case map[string]any:
+		other, ok := right.(map[string]any)
+		if !ok || len(current) != len(other) {
+			return false
+		}
+		for key, item := range current {
+			otherItem, found := other[key]
+			if !found || !equal(item, otherItem) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(left, right)
+	}
+}
+
+func integer(value any) (int, bool) {
+	parsed, ok := number.ToFloat64(value)
+	if !ok {
+		return 0, false
+	}
+	return int(parsed), true
+}
+
+func describe(value any) string {
+	if text, ok := value.(string); ok {
+		return strconv.Quote(text)
+	}
+	if value == nil {
+		return "null"
+	}
+	return fmt.Sprint(value)
+}
+
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	identifier  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// validFormat checks the common string formats. Unknown formats are
+// annotations only and always pass.
+func validFormat(format string, value string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "ipv4":
+		addr, err := netip.ParseAddr(value)
+		return err == nil && addr.Is4()
+	case "ipv6":
+		addr, err := netip.ParseAddr(value)
+		return err == nil && addr.Is6()
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.Scheme != ""
+	default:
+		return true
+	}
+}
+
+func memberPath(path string, name string) string {
+	if identifier.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "[" + strconv.Quote(name) + "]"
+}
+
+func indexPath(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
@@ -0,0 +1,212 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	const document = `
+type: object
+required: [id, items]
+additionalProperties: false
+properties:
+  id:
+    type: integer
+    minimum: 1
+  status:
+    enum: [open, closed]
+  email:
+    type: string
+    format: email
+  tags:
+    type: array
+    uniqueItems: true
+    maxItems: 2
+    items:
+      type: string
+      pattern: "^[a-z]+$"
+  items:
+    type: array
+    items:
+      $ref: "#/$defs/item"
+  note:
+    type: string
+    nullable: true
+  owner:
+    oneOf:
+      - type: string
+      - type: object
+        required: [name]
+$defs:
+  item:
+    type: object
+    required: [price]
+    properties:
+      price:
+        type: number
+        exclusiveMinimum: 0
+`
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "valid",
+			body: `{"id":1,"status":"open","email":"a@example.com","tags":["x","y"],"items":[{"price":2.5}],"note":null,"owner":"me"}`,
+		},
+		{
+			name: "missing_required",
+			body: `{"items":[]}`,
+			want: []string{`$: missing required property "id"`},
+		},
+		{
+			name: "wrong_type",
+			body: `{"id":"1","items":[]}`,
+			want: []string{"$.id: expected integer, got string"},
+		},
+		{
+			name: "integer_and_minimum",
+			body: `{"id":0.5,"items":[]}`,
+			want: []string{"$.id: expected integer, got number"},
+		},
+		{
+			name: "nested_ref",
+			body: `{"id":1,"items":[{"price":1},{"price":0},{}]}`,
+			want: []string{
+				"$.items[1].price: expected greater than 0, got 0",
+				`$.items[2]: missing required property "price"`,
+			},
+		},
+		{
+			name: "enum_format_and_additional",
+			body: `{"id":1,"items":[],"status":"gone","email":"nope","extra":true}`,
+			want: []string{
+				`$.email: "nope" is not a valid email`,
+				`$: unexpected property "extra"`,
+				`$.status: expected one of "open", "closed", got "gone"`,
+			},
+		},
+		{
+			name: "array_keywords",
+			body: `{"id":1,"items":[],"tags":["a","a","B"]}`,
+			want: []string{
+				"$.tags: expected at most 2 items, got 3",
+				"$.tags[1]: duplicates item 0",
+				`$.tags[2]: "B" does not match pattern "^[a-z]+$"`,
+			},
+		},
+		{
+			name: "one_of",
+			body: `{"id":1,"items":[],"owner":{}}`,
+			want: []string{"$.owner: matches 0 schemas of oneOf, expected exactly 1"},
+		},
+	}
+
+	var schema any
+	if err := yaml.Unmarshal([]byte(document), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var value any
+			if err := json.Unmarshal([]byte(tt.body), &value); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, violation := range New(schema).Validate(value) {
+				got = append(got, violation.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRefCycle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		schema   string
+		value    string
+		wantFail bool
+	}{
+		{name: "linear", schema: `{"$ref": "#"}`, value: `1`, wantFail: true},
+		{name: "branching_any_of", schema: `{"anyOf": [{"$ref": "#"}, {"$ref": "#"}]}`, value: `1`, wantFail: true},
+		{name: "branching_one_of", schema: `{"oneOf": [{"$ref": "#/$defs/a"}, {"$ref": "#/$defs/a"}], "$defs": {"a": {"allOf": [{"$ref": "#"}]}}}`, value: `{"a": 1}`, wantFail: true},
+		{
+			name:   "recursion_into_the_value",
+			schema: `{"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#"}}}}`,
+			value:  `{"children": [{"children": []}, {"children": [{"children": []}]}]}`,
+		},
+		{name: "same_schema_twice", schema: `{"allOf": [{"$ref": "#/$defs/n"}, {"$ref": "#/$defs/n"}], "$defs": {"n": {"type": "number"}}}`, value: `1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var schema, value any
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("unmarshal schema: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatalf("unmarshal value: %v", err)
+			}
+
+			violations := New(schema).Validate(value)
+			if got := len(violations) > 0; got != tt.wantFail {
+				t.Fatalf("Validate() = %v, want failure %v", violations, tt.wantFail)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	document := map[string]any{
+		"components": map[string]any{
+			"schemas": map[string]any{"a/b": "slash", "list": []any{"zero", "one"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    any
+		wantErr bool
+	}{
+		{name: "escaped_slash", ref: "#/components/schemas/a~1b", want: "slash"},
+		{name: "array_index", ref: "#/components/schemas/list/1", want: "one"},
+		{name: "missing", ref: "#/components/schemas/missing", wantErr: true},
+		{name: "remote", ref: "other.yaml#/schemas/Pet", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Resolve(document, tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
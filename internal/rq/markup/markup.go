@@ -0,0 +1,170 @@
+// Package markup parses XML and HTML response bodies into one node tree that
+// the XPath and CSS selectors walk.
+package markup
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// NodeType is the kind of a Node.
+type NodeType int
+
+const (
+	DocumentNode NodeType = iota
+	ElementNode
+	TextNode
+)
+
+// Attr is an attribute of an element. Namespace prefixes are dropped.
+type Attr struct {
+	Name  string
+	Value string
+}
+
+// Node is a document, element or text node.
+type Node struct {
+	Type     NodeType
+	Name     string // Local name of an element
+	Data     string // Content of a text node
+	Attrs    []Attr
+	Parent   *Node
+	Children []*Node
+}
+
+// Attr returns the value of the attribute name.
+func (n *Node) Attr(name string) (string, bool) {
+	for _, attr := range n.Attrs {
+		if attr.Name == name {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// Text returns the text of the node and its descendants.
+func (n *Node) Text() string {
+	if n.Type == TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	n.writeText(&b)
+	return b.String()
+}
+
+func (n *Node) writeText(b *strings.Builder) {
+	for _, child := range n.Children {
+		if child.Type == TextNode {
+			b.WriteString(child.Data)
+			continue
+		}
+		child.writeText(b)
+	}
+}
+
+// Elements returns the element children of the node.
+func (n *Node) Elements() []*Node {
+	var elements []*Node
+	for _, child := range n.Children {
+		if child.Type == ElementNode {
+			elements = append(elements, child)
+		}
+	}
+	return elements
+}
+
+func (n *Node) append(child *Node) {
+	child.Parent = n
+	n.Children = append(n.Children, child)
+}
+
+// Parse parses body as XML and falls back to HTML when it is not well-formed
+// XML, so both API payloads and rendered pages can be queried.
+func Parse(body []byte) (*Node, error) {
+	if doc, err := ParseXML(body); err == nil {
+		return doc, nil
+	}
+	return ParseHTML(body)
+}
+
+// ParseXML parses a well-formed XML document.
+func ParseXML(body []byte) (*Node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	// Declared encodings other than UTF-8 are read as is rather than rejected.
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	doc := &Node{Type: DocumentNode}
+	current := doc
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse xml: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &Node{Type: ElementNode, Name: t.Name.Local}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				element.Attrs = append(element.Attrs, Attr{Name: attr.Name.Local, Value: attr.Value})
+			}
+			current.append(element)
+			current = element
+		case xml.EndElement:
+			current = current.Parent
+		case xml.CharData:
+			if current != doc {
+				current.append(&Node{Type: TextNode, Data: string(t)})
+			}
+		}
+	}
+
+	if len(doc.Elements()) == 0 {
+		return nil, errors.New("parse xml: no root element")
+	}
+
+	return doc, nil
+}
+
+// ParseHTML parses an HTML document the way browsers do, adding the html,
+// head and body elements when they are missing.
+func ParseHTML(body []byte) (*Node, error) {
+	root, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parse html: %w", err)
+	}
+
+	doc := &Node{Type: DocumentNode}
+	convertHTML(doc, root)
+	return doc, nil
+}
+
+func convertHTML(parent *Node, source *html.Node) {
+	for child := source.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.ElementNode:
+			element := &Node{Type: ElementNode, Name: child.Data}
+			for _, attr := range child.Attr {
+				element.Attrs = append(element.Attrs, Attr{Name: attr.Key, Value: attr.Val})
+			}
+			parent.append(element)
+			convertHTML(element, child)
+		case html.TextNode:
+			parent.append(&Node{Type: TextNode, Data: child.Data})
+		}
+	}
+}
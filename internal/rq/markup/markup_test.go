@@ -0,0 +1,83 @@
+package markup
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     string
+		wantRoot string
+		wantText string
+	}{
+		{
+			name:     "xml",
+			body:     `<?xml version="1.0"?><order id="7"><item>pen</item><item>ink</item></order>`,
+			wantRoot: "order",
+			wantText: "penink",
+		},
+		{
+			name:     "xml_keeps_case",
+			body:     `<Order><Item>pen</Item></Order>`,
+			wantRoot: "Order",
+			wantText: "pen",
+		},
+		{
+			name:     "html_fallback",
+			body:     `<p>one<br>two &nbsp;`,
+			wantRoot: "html",
+			wantText: "onetwo  ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := Parse([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			roots := doc.Elements()
+			if len(roots) != 1 || roots[0].Name != tt.wantRoot {
+				t.Fatalf("root elements = %v, want %s", roots, tt.wantRoot)
+			}
+			if got := doc.Text(); got != tt.wantText {
+				t.Errorf("Text() = %q, want %q", got, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestParseXMLErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, body := range []string{"", "plain text", "<a><b></a>"} {
+		if _, err := ParseXML([]byte(body)); err == nil {
+			t.Errorf("ParseXML(%q) error = nil, want error", body)
+		}
+	}
+}
+
+func TestNodeAttr(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ParseXML([]byte(`<a xmlns:x="urn:x" x:id="1" name="n"/>`))
+	if err != nil {
+		t.Fatalf("ParseXML() error = %v", err)
+	}
+	root := doc.Elements()[0]
+
+	if got, ok := root.Attr("id"); !ok || got != "1" {
+		t.Errorf("Attr(id) = (%q, %v), want (1, true)", got, ok)
+	}
+	if got, ok := root.Attr("name"); !ok || got != "n" {
+		t.Errorf("Attr(name) = (%q, %v), want (n, true)", got, ok)
+	}
+	if _, ok := root.Attr("x"); ok {
+		t.Error("Attr(x) found the namespace declaration")
+	}
+}
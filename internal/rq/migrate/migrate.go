@@ -0,0 +1,164 @@
+// Package migrate upgrades rq test files written with older shorthand shapes
+// to the current schema. Rewrites are applied to the source text so comments
+// and formatting outside the rewritten blocks are preserved.
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/jacoelho/rq/internal/rq/model"
+)
+
+// Change describes one rewrite. Step is 1-based, Line is the line of the
+// rewritten key in the original file.
+type Change struct {
+	Step        int
+	Line        int
+	Path        string
+	Description string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("line %d: step %d: %s: %s", c.Line, c.Step, c.Path, c.Description)
+}
+
+// rewrite replaces the block of key with value rendered as YAML.
+type rewrite struct {
+	key   *ast.MappingValueNode
+	value any
+}
+
+// Migrate returns data upgraded to the current schema and the changes made.
+// When nothing applies, data is returned unchanged with no changes. The result
+// is checked with the step parser before it is returned.
+func Migrate(data []byte) ([]byte, []Change, error) {
+	file, err := parser.ParseBytes(data, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse YAML: %w", err)
+	}
+	if len(file.Docs) == 0 {
+		return data, nil, nil
+	}
+
+	steps, ok := file.Docs[0].Body.(*ast.SequenceNode)
+	if !ok {
+		return data, nil, nil
+	}
+
+	var (
+		rewrites []rewrite
+		changes  []Change
+	)
+	for index, stepNode := range steps.Values {
+		step := asMapping(stepNode)
+		if step == nil {
+			continue
+		}
+
+		for _, migration := range migrations {
+			found, err := migration(step)
+			if err != nil {
+				return nil, nil, fmt.Errorf("step %d: %w", index+1, err)
+			}
+			for _, current := range found {
+				rewrites = append(rewrites, current.rewrite)
+				changes = append(changes, Change{
+					Step:        index + 1,
+					Line:        current.rewrite.key.Key.GetToken().Position.Line,
+					Path:        current.path,
+					Description: current.description,
+				})
+			}
+		}
+	}
+
+	if len(rewrites) == 0 {
+		return data, nil, nil
+	}
+
+	migrated, err := applyRewrites(data, rewrites)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := model.Parse(bytes.NewReader(migrated)); err != nil {
+		return nil, nil, fmt.Errorf("migrated file is not valid: %w", err)
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		return a.Line - b.Line
+	})
+
+	return migrated, changes, nil
+}
+
+// applyRewrites replaces each key's lines, starting from the end of the file
+// so earlier line numbers stay valid.
+func applyRewrites(data []byte, rewrites []rewrite) ([]byte, error) {
+	lines := strings.Split(string(data), "\n")
+
+	slices.SortFunc(rewrites, func(a, b rewrite) int {
+		return b.key.Key.GetToken().Position.Line - a.key.Key.GetToken().Position.Line
+	})
+
+	for _, current := range rewrites {
+		position := current.key.Key.GetToken().Position
+		start := position.Line - 1
+		indent := position.Column - 1
+		end := blockEnd(lines, start, indent)
+
+		replacement, err := render(lines[start][:indent], current.key.Key.GetToken().Value, indent, current.value)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = slices.Replace(lines, start, end, replacement...)
+	}
+
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// blockEnd returns the index after the last line belonging to the key at
+// start, i.e. before the next non-blank line indented at or left of indent.
+// Trailing blank lines are left in place.
+func blockEnd(lines []string, start, indent int) int {
+	end := start + 1
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue
+		}
+		if len(lines[i])-len(strings.TrimLeft(lines[i], " ")) <= indent {
+			break
+		}
+		end = i + 1
+	}
+
+	return end
+}
+
+// render emits "key:" followed by value as a block indented under the key.
+// prefix is the original text before the key, such as "- " for the first
+// key of a step.
+func render(prefix, key string, indent int, value any) ([]string, error) {
+	payload, err := yaml.MarshalWithOptions(yaml.MapSlice{{Key: key, Value: value}}, yaml.Indent(2), yaml.IndentSequence(true))
+	if err != nil {
+		return nil, fmt.Errorf("render %s: %w", key, err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(payload), "\n"), "\n")
+	lines[0] = prefix + lines[0]
+
+	padding := strings.Repeat(" ", indent)
+	for i := 1; i < len(lines); i++ {
+		lines[i] = padding + lines[i]
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,150 @@
+package migrate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		want        string
+		wantChanges []Change
+	}{
+		{
+			name: "current schema is unchanged",
+			input: `- method: GET
+  url: https://api.example.com
+  asserts:
+    status:
+      - op: equals
+        value: 200
+`,
+			want: `- method: GET
+  url: https://api.example.com
+  asserts:
+    status:
+      - op: equals
+        value: 200
+`,
+		},
+		{
+			name: "shorthand shapes keep surrounding comments",
+			input: `# login flow
+- method: POST # authenticate
+  url: https://api.example.com/login
+  asserts:
+    status: 200
+    headers:
+      Content-Type: application/json
+  captures:
+    token: $.auth.token
+    user_id: $.user.id
+
+- method: GET
+  url: https://api.example.com/me # uses token
+`,
+			want: `# login flow
+- method: POST # authenticate
+  url: https://api.example.com/login
+  asserts:
+    status:
+      - op: equals
+        value: 200
+    headers:
+      - name: Content-Type
+        op: equals
+        value: application/json
+  captures:
+    jsonpath:
+      - name: token
+        path: $.auth.token
+      - name: user_id
+        path: $.user.id
+
+- method: GET
+  url: https://api.example.com/me # uses token
+`,
+			wantChanges: []Change{
+				{Step: 1, Line: 5, Path: "asserts.status", Description: "status shorthand converted to an equals assert"},
+				{Step: 1, Line: 6, Path: "asserts.headers", Description: "header map converted to equals asserts"},
+				{Step: 1, Line: 8, Path: "captures", Description: "shorthand captures converted to jsonpath captures"},
+			},
+		},
+		{
+			name: "first key of a step keeps the sequence marker",
+			input: `- captures: {id: $.id}
+  method: GET
+  url: https://api.example.com
+`,
+			want: `- captures:
+    jsonpath:
+      - name: id
+        path: $.id
+  method: GET
+  url: https://api.example.com
+`,
+			wantChanges: []Change{
+				{Step: 1, Line: 1, Path: "captures", Description: "shorthand captures converted to jsonpath captures"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, changes, err := Migrate([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("Migrate() output:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Fatalf("Migrate() changes = %+v, want %+v", changes, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "invalid YAML", input: "- method: [GET\n"},
+		{
+			name: "non-string shorthand capture",
+			input: `- method: GET
+  url: https://api.example.com
+  captures:
+    count: 3
+`,
+		},
+		{
+			name: "migrated file still invalid",
+			input: `- method: GET
+  url: https://api.example.com
+  unknown: true
+  asserts:
+    status: 200
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, _, err := Migrate([]byte(tt.input)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+)
+
+// found is a rewrite proposed by a migration together with its description.
+type found struct {
+	rewrite     rewrite
+	path        string
+	description string
+}
+
+// migrations run in order against every step mapping. Each one recognises a
+// shorthand shape that the current parser rejects and proposes its structured
+// replacement.
+var migrations = []func(step *ast.MappingNode) ([]found, error){
+	migrateCaptureShorthand,
+	migrateStatusShorthand,
+	migrateHeaderAssertShorthand,
+}
+
+// migrateCaptureShorthand rewrites
+//
+//	captures:
+//	  token: $.auth.token
+//
+// into a jsonpath capture list.
+func migrateCaptureShorthand(step *ast.MappingNode) ([]found, error) {
+	captures := mappingValue(step, "captures")
+	if captures == nil {
+		return nil, nil
+	}
+
+	entries, ok := scalarEntries(captures.Value)
+	if !ok {
+		return nil, nil
+	}
+
+	items := make([]yaml.MapSlice, 0, len(entries))
+	for _, entry := range entries {
+		path, ok := entry.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("captures.%s: shorthand capture must be a JSONPath string", entry.Key)
+		}
+		items = append(items, yaml.MapSlice{
+			{Key: "name", Value: entry.Key},
+			{Key: "path", Value: path},
+		})
+	}
+
+	return []found{{
+		rewrite:     rewrite{key: captures, value: yaml.MapSlice{{Key: "jsonpath", Value: items}}},
+		path:        "captures",
+		description: "shorthand captures converted to jsonpath captures",
+	}}, nil
+}
+
+// migrateStatusShorthand rewrites "status: 200" into an equals predicate.
+func migrateStatusShorthand(step *ast.MappingNode) ([]found, error) {
+	status := assertValue(step, "status")
+	if status == nil {
+		return nil, nil
+	}
+
+	integer, ok := status.Value.(*ast.IntegerNode)
+	if !ok {
+		return nil, nil
+	}
+
+	var code int
+	if err := yaml.NodeToValue(integer, &code); err != nil {
+		return nil, fmt.Errorf("asserts.status: %w", err)
+	}
+
+	return []found{{
+		rewrite:     rewrite{key: status, value: []yaml.MapSlice{equalsPredicate(nil, code)}},
+		path:        "asserts.status",
+		description: "status shorthand converted to an equals assert",
+	}}, nil
+}
+
+// migrateHeaderAssertShorthand rewrites
+//
+//	headers:
+//	  Content-Type: application/json
+//
+// under asserts into a list of equals asserts.
+func migrateHeaderAssertShorthand(step *ast.MappingNode) ([]found, error) {
+	headers := assertValue(step, "headers")
+	if headers == nil {
+		return nil, nil
+	}
+
+	entries, ok := scalarEntries(headers.Value)
+	if !ok {
+		return nil, nil
+	}
+
+	items := make([]yaml.MapSlice, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, equalsPredicate(&yaml.MapItem{Key: "name", Value: entry.Key}, entry.Value))
+	}
+
+	return []found{{
+		rewrite:     rewrite{key: headers, value: items},
+		path:        "asserts.headers",
+		description: "header map converted to equals asserts",
+	}}, nil
+}
+
+func equalsPredicate(field *yaml.MapItem, value any) yaml.MapSlice {
+	predicate := yaml.MapSlice{}
+	if field != nil {
+		predicate = append(predicate, *field)
+	}
+
+	return append(predicate,
+		yaml.MapItem{Key: "op", Value: "equals"},
+		yaml.MapItem{Key: "value", Value: value},
+	)
+}
+
+func assertValue(step *ast.MappingNode, key string) *ast.MappingValueNode {
+	asserts := mappingValue(step, "asserts")
+	if asserts == nil {
+		return nil
+	}
+
+	mapping := asMapping(asserts.Value)
+	if mapping == nil {
+		return nil
+	}
+
+	return mappingValue(mapping, key)
+}
+
+// asMapping returns node as a mapping; the parser may represent a mapping
+// with a single entry as a bare key/value node.
+func asMapping(node ast.Node) *ast.MappingNode {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n
+	case *ast.MappingValueNode:
+		return &ast.MappingNode{Values: []*ast.MappingValueNode{n}}
+	default:
+		return nil
+	}
+}
+
+func mappingValue(mapping *ast.MappingNode, key string) *ast.MappingValueNode {
+	for _, value := range mapping.Values {
+		if value.Key.GetToken().Value == key {
+			return value
+		}
+	}
+
+	return nil
+}
+
+type scalarEntry struct {
+	Key   string
+	Value any
+}
+
+// scalarEntries returns the entries of a mapping whose values are all
+// scalars. Mappings with any nested value are left to the current schema.
+func scalarEntries(node ast.Node) ([]scalarEntry, bool) {
+	mapping := asMapping(node)
+	if mapping == nil || len(mapping.Values) == 0 {
+		return nil, false
+	}
+
+	entries := make([]scalarEntry, 0, len(mapping.Values))
+	for _, value := range mapping.Values {
+		if _, ok := value.Value.(ast.ScalarNode); !ok {
+			return nil, false
+		}
+
+		var decoded any
+		if err := yaml.NodeToValue(value.Value, &decoded); err != nil {
+			return nil, false
+		}
+		entries = append(entries, scalarEntry{Key: value.Key.GetToken().Value, Value: decoded})
+	}
+
+	return entries, true
+}
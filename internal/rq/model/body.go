@@ -0,0 +1,88 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml/ast"
+)
+
+// Body is a request payload given either as literal text or as a YAML
+// mapping/sequence that the runner serializes to JSON.
+//
+//	body: |
+//	  {"name": "rq"}
+//
+// or:
+//
+//	body:
+//	  name: rq
+type Body struct {
+	Text  string
+	Value any
+}
+
+// TextBody returns a literal text body.
+func TextBody(text string) Body {
+	return Body{Text: text}
+}
+
+// IsStructured reports whether the body was declared as a mapping or sequence.
+func (b Body) IsStructured() bool {
+	return b.Value != nil
+}
+
+// IsZero reports whether no body was declared.
+func (b Body) IsZero() bool {
+	return b.Text == "" && b.Value == nil
+}
+
+// UnmarshalYAML supports scalar text and structured mapping/sequence bodies.
+func (b *Body) UnmarshalYAML(node ast.Node) error {
+	switch n := node.(type) {
+	case *ast.MappingNode, *ast.SequenceNode:
+		value, err := nodeToValue(n)
+		if err != nil {
+			return fmt.Errorf("%w: invalid structured body: %v", ErrParser, err)
+		}
+		*b = Body{Value: value}
+		return nil
+	case *ast.LiteralNode:
+		*b = Body{Text: n.Value.Value}
+		return nil
+	default:
+		text, err := nodeToString(node)
+		if err != nil {
+			return fmt.Errorf("%w: body must be string, mapping or sequence: %v", ErrParser, err)
+		}
+		*b = Body{Text: text}
+		return nil
+	}
+}
+
+// MarshalYAML emits the text form or the structured value.
+func (b Body) MarshalYAML() (any, error) {
+	if b.IsStructured() {
+		return b.Value, nil
+	}
+
+	return b.Text, nil
+}
+
+// Part is one part of a multipart/form-data body: a field with Value, or a
+// file upload when File is set. Filename defaults to the base name of File.
+// ContentType defaults to the type of the file extension for files and is
+// omitted for fields.
+//
+//	multipart:
+//	  - name: title
+//	    value: Q3 report
+//	  - name: attachment
+//	    file: reports/q3.pdf
+//	    content_type: application/pdf
+type Part struct {
+	Name        string `yaml:"name"`
+	Value       string `yaml:"value,omitempty"`
+	File        string `yaml:"file,omitempty"`
+	Filename    string `yaml:"filename,omitempty"`
+	ContentType string `yaml:"content_type,omitempty"`
+}
@@ -0,0 +1,26 @@
+package model
+
+// Cookie attributes selectable by cookie asserts and captures. An empty
+// attribute selects the value.
+const (
+	CookieAttributeValue    = "value"
+	CookieAttributeDomain   = "domain"
+	CookieAttributePath     = "path"
+	CookieAttributeExpires  = "expires"
+	CookieAttributeMaxAge   = "max_age"
+	CookieAttributeSecure   = "secure"
+	CookieAttributeHTTPOnly = "http_only"
+	CookieAttributeSameSite = "same_site"
+)
+
+// IsSupportedCookieAttribute reports whether attribute can be used in cookie
+// asserts and captures.
+func IsSupportedCookieAttribute(attribute string) bool {
+	switch attribute {
+	case "", CookieAttributeValue, CookieAttributeDomain, CookieAttributePath, CookieAttributeExpires,
+		CookieAttributeMaxAge, CookieAttributeSecure, CookieAttributeHTTPOnly, CookieAttributeSameSite:
+		return true
+	default:
+		return false
+	}
+}
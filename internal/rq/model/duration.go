@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/number"
+)
+
+// DurationMillis converts a duration assert value to milliseconds. The value
+// is a duration string such as "500ms" or "1.5s", or a number of
+// milliseconds; lists, as used by operators like in, are converted element by
+// element.
+func DurationMillis(value any) (any, error) {
+	switch current := value.(type) {
+	case string:
+		duration, err := time.ParseDuration(current)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q: use a value such as 500ms or 1.5s", current)
+		}
+		return float64(duration) / float64(time.Millisecond), nil
+	case []any:
+		converted := make([]any, 0, len(current))
+		for i, item := range current {
+			millis, err := DurationMillis(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			converted = append(converted, millis)
+		}
+		return converted, nil
+	default:
+		millis, ok := number.ToFloat64(value)
+		if !ok {
+			return nil, fmt.Errorf("invalid duration %v (%T): use a value such as 500ms or a number of milliseconds", value, value)
+		}
+		return millis, nil
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml/ast"
+)
+
+// Variables a foreach step sets for each element.
+const (
+	ForeachItem  = "item"
+	ForeachIndex = "item_index"
+)
+
+// Foreach runs a step once per element of a list given either literally or
+// as the name of a variable or capture holding a list. Each run sees the
+// element as {{.item}} and its zero-based position as {{.item_index}}.
+//
+//	foreach: [eu, us, ap]
+//
+// or:
+//
+//	foreach: tenants
+type Foreach struct {
+	Items []any
+	From  string
+}
+
+// UnmarshalYAML supports a sequence of items and a variable name.
+func (f *Foreach) UnmarshalYAML(node ast.Node) error {
+	if sequence, ok := node.(*ast.SequenceNode); ok {
+		value, err := nodeToValue(sequence)
+		if err != nil {
+			return fmt.Errorf("%w: invalid foreach list: %v", ErrParser, err)
+		}
+		items, _ := value.([]any)
+		*f = Foreach{Items: items}
+		if f.Items == nil {
+			f.Items = []any{}
+		}
+		return nil
+	}
+
+	name, err := nodeToString(node)
+	if err != nil {
+		return fmt.Errorf("%w: foreach must be a list or a variable name: %v", ErrParser, err)
+	}
+	*f = Foreach{From: name}
+	return nil
+}
+
+// MarshalYAML emits the list or the variable name.
+func (f Foreach) MarshalYAML() (any, error) {
+	if f.From != "" {
+		return f.From, nil
+	}
+
+	return f.Items, nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// grpcCodes lists the gRPC status code names, indexed by code.
+var grpcCodes = []string{
+	"OK",
+	"CANCELLED",
+	"UNKNOWN",
+	"INVALID_ARGUMENT",
+	"DEADLINE_EXCEEDED",
+	"NOT_FOUND",
+	"ALREADY_EXISTS",
+	"PERMISSION_DENIED",
+	"RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION",
+	"ABORTED",
+	"OUT_OF_RANGE",
+	"UNIMPLEMENTED",
+	"INTERNAL",
+	"UNAVAILABLE",
+	"DATA_LOSS",
+	"UNAUTHENTICATED",
+}
+
+// ParseGRPCCode accepts a gRPC status code by name, e.g. NOT_FOUND, or by
+// number. An empty value means OK.
+func ParseGRPCCode(value string) (int, error) {
+	normalized := strings.TrimSpace(strings.ToUpper(value))
+	if normalized == "" {
+		return 0, nil
+	}
+
+	if code, err := strconv.Atoi(normalized); err == nil {
+		if code < 0 || code >= len(grpcCodes) {
+			return 0, fmt.Errorf("unsupported grpc status code %d (expected 0 to %d)", code, len(grpcCodes)-1)
+		}
+		return code, nil
+	}
+
+	for code, name := range grpcCodes {
+		if name == normalized {
+			return code, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unsupported grpc status %q", value)
+}
+
+// GRPCCodeName returns the name of a gRPC status code, or the number for
+// codes outside the standard set.
+func GRPCCodeName(code int) string {
+	if code >= 0 && code < len(grpcCodes) {
+		return grpcCodes[code]
+	}
+	return strconv.Itoa(code)
+}
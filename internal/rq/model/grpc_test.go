@@ -0,0 +1,47 @@
+package model
+
+import "testing"
+
+func TestParseGRPCCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{input: "", want: 0},
+		{input: "OK", want: 0},
+		{input: "not_found", want: 5},
+		{input: " UNAUTHENTICATED ", want: 16},
+		{input: "14", want: 14},
+		{input: "17", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "MISSING", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseGRPCCode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGRPCCode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseGRPCCode(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGRPCCodeName(t *testing.T) {
+	t.Parallel()
+
+	if got := GRPCCodeName(5); got != "NOT_FOUND" {
+		t.Fatalf("GRPCCodeName(5) = %q, want NOT_FOUND", got)
+	}
+	if got := GRPCCodeName(42); got != "42" {
+		t.Fatalf("GRPCCodeName(42) = %q, want 42", got)
+	}
+}
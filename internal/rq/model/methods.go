@@ -1,5 +1,7 @@
 package model
 
+import "strings"
+
 // Supported HTTP methods accepted by both rq runtime specs and pm2rq conversion.
 const (
 	MethodGet     = "GET"
@@ -11,6 +13,69 @@ const (
 	MethodOptions = "OPTIONS"
 )
 
+// MethodConnect marks connect steps, which open a TCP or TLS connection
+// instead of sending an HTTP request. It is not a supported HTTP method.
+const MethodConnect = "CONNECT"
+
+// MethodLookup marks dns steps, which resolve a host name instead of sending
+// an HTTP request.
+const MethodLookup = "LOOKUP"
+
+// DNS record types supported by dns steps.
+const (
+	DNSTypeA     = "A"
+	DNSTypeAAAA  = "AAAA"
+	DNSTypeCNAME = "CNAME"
+	DNSTypeTXT   = "TXT"
+	DNSTypeMX    = "MX"
+	DNSTypeNS    = "NS"
+)
+
+// IsSupportedDNSType reports whether recordType can be queried by dns steps.
+func IsSupportedDNSType(recordType string) bool {
+	switch recordType {
+	case DNSTypeA, DNSTypeAAAA, DNSTypeCNAME, DNSTypeTXT, DNSTypeMX, DNSTypeNS:
+		return true
+	default:
+		return false
+	}
+}
+
+// HTTP versions accepted by options.http_version.
+const (
+	HTTPVersion1 = "1.1"
+	HTTPVersion2 = "2"
+	HTTPVersion3 = "3"
+)
+
+// IsSupportedHTTPVersion reports whether version can be set in
+// options.http_version.
+func IsSupportedHTTPVersion(version string) bool {
+	switch version {
+	case HTTPVersion1, HTTPVersion2, HTTPVersion3:
+		return true
+	default:
+		return false
+	}
+}
+
+// Backoff strategies accepted by options.retry_backoff.
+const (
+	RetryBackoffConstant    = "constant"
+	RetryBackoffExponential = "exponential"
+)
+
+// IsSupportedRetryBackoff reports whether backoff can be set in
+// options.retry_backoff.
+func IsSupportedRetryBackoff(backoff string) bool {
+	switch backoff {
+	case RetryBackoffConstant, RetryBackoffExponential:
+		return true
+	default:
+		return false
+	}
+}
+
 var supportedMethods = map[string]struct{}{
 	MethodGet:     {},
 	MethodPost:    {},
@@ -27,6 +92,25 @@ func IsSupportedMethod(method string) bool {
 	return ok
 }
 
+// IsMethodToken reports whether method is a syntactically valid HTTP method,
+// i.e. an RFC 9110 token such as PURGE or PROPFIND.
+func IsMethodToken(method string) bool {
+	if method == "" {
+		return false
+	}
+
+	for _, char := range method {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", char):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
 // SupportedMethods returns the canonical method list in stable order.
 func SupportedMethods() []string {
 	return []string{
@@ -1,8 +1,15 @@
 package model
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/goccy/go-yaml"
 	"github.com/goccy/go-yaml/ast"
@@ -15,22 +22,213 @@ var ErrParser = fmt.Errorf("parser error")
 // Step represents a single HTTP workflow step, including request, assertions, and captures.
 // Each step defines an HTTP operation with optional validation and data extraction.
 type Step struct {
-	Method   string    `yaml:"method"`
-	URL      string    `yaml:"url"`
-	When     string    `yaml:"when,omitempty"`
-	Headers  KeyValues `yaml:"headers,omitempty"`
-	Query    KeyValues `yaml:"query,omitempty"`
-	Options  Options   `yaml:"options,omitempty"`
-	Body     string    `yaml:"body,omitempty"`
-	BodyFile string    `yaml:"body_file,omitempty"`
-	Asserts  Asserts   `yaml:"asserts,omitempty"`
-	Captures *Captures `yaml:"captures,omitempty"`
-}
-
-// Options configures retry and redirect behavior for a step.
+	Method      string       `yaml:"method"`
+	URL         string       `yaml:"url"`
+	Name        string       `yaml:"name,omitempty"`
+	Description string       `yaml:"description,omitempty"`
+	Docs        string       `yaml:"docs,omitempty"`
+	When        string       `yaml:"when,omitempty"`
+	Foreach     *Foreach     `yaml:"foreach,omitempty"`
+	DumpVars    bool         `yaml:"dump_vars,omitempty"`
+	Headers     KeyValues    `yaml:"headers,omitempty"`
+	Query       KeyValues    `yaml:"query,omitempty"`
+	Options     Options      `yaml:"options,omitempty"`
+	Body        Body         `yaml:"body,omitempty"`
+	BodyFile    string       `yaml:"body_file,omitempty"`
+	BodyFileRaw bool         `yaml:"body_file_raw,omitempty"`
+	Multipart   []Part       `yaml:"multipart,omitempty"`
+	PollJob     *PollJob     `yaml:"poll_job,omitempty"`
+	WebSocket   *WebSocket   `yaml:"websocket,omitempty"`
+	Conditional *Conditional `yaml:"conditional,omitempty"`
+	GRPC        *GRPC        `yaml:"grpc,omitempty"`
+	GraphQL     *GraphQL     `yaml:"graphql,omitempty"`
+	Connect     *Connect     `yaml:"connect,omitempty"`
+	DNS         *DNS         `yaml:"dns,omitempty"`
+	Auth        *Auth        `yaml:"auth,omitempty"`
+	Asserts     Asserts      `yaml:"asserts,omitempty"`
+	Captures    *Captures    `yaml:"captures,omitempty"`
+	Checks      []Check      `yaml:"checks,omitempty"`
+	Exports     []string     `yaml:"exports,omitempty"`
+
+	// ConditionalOn names an earlier step whose response ETag and
+	// Last-Modified are sent as conditional headers; see ConditionalHeaders.
+	ConditionalOn string `yaml:"conditional_on,omitempty"`
+
+	// RegisterCleanup lists requests queued when the step succeeds, such as
+	// deleting the resource it created.
+	RegisterCleanup []Cleanup `yaml:"register_cleanup,omitempty"`
+
+	// VariantAsserts holds asserts that only apply to the run of an
+	// options.accept_matrix or accept_language_matrix step with that value.
+	VariantAsserts map[string]Asserts `yaml:"variant_asserts,omitempty"`
+}
+
+// Cleanup is a request registered by a step and sent when the run ends. Its
+// templates see the captures as they were when the step succeeded.
+type Cleanup struct {
+	Method  string    `yaml:"method"`
+	URL     string    `yaml:"url"`
+	Headers KeyValues `yaml:"headers,omitempty"`
+	Query   KeyValues `yaml:"query,omitempty"`
+}
+
+// ChecksOnly reports whether the step has no method and URL and only
+// evaluates its checks, sending no request.
+func (s Step) ChecksOnly() bool {
+	return s.Method == "" && s.URL == "" && len(s.Checks) > 0
+}
+
+// DocsOnly reports whether the step only documents its file: it sets
+// description or docs and nothing else, and is skipped when the file runs.
+func (s Step) DocsOnly() bool {
+	return s.Method == "" && s.URL == "" && len(s.Checks) == 0 && (s.Description != "" || s.Docs != "")
+}
+
+// Options configures retry, redirect, cookie, TLS and request body behavior
+// for a step. Cookies set by earlier steps of the same file are sent unless
+// Cookies is false. Output streams the response body to a file instead of
+// holding it in memory. Cacheable lets --cache reuse the response of a GET
+// step across runs.
 type Options struct {
-	Retries        int   `yaml:"retries,omitempty"`
-	FollowRedirect *bool `yaml:"follow_redirect,omitempty"`
+	Retries           int           `yaml:"retries,omitempty"`
+	RetryBackoff      string        `yaml:"retry_backoff,omitempty"`
+	RetryDelay        time.Duration `yaml:"retry_delay,omitempty"`
+	RetryMaxWait      time.Duration `yaml:"retry_max_wait,omitempty"`
+	RetryJitter       float64       `yaml:"retry_jitter,omitempty"`
+	FollowRedirect    *bool         `yaml:"follow_redirect,omitempty"`
+	Cookies           *bool         `yaml:"cookies,omitempty"`
+	BodyCanonicalJSON bool          `yaml:"body_canonical_json,omitempty"`
+	AllowCustomMethod bool          `yaml:"allow_custom_method,omitempty"`
+	LenientJSON       bool          `yaml:"lenient_json,omitempty"`
+	JSONLines         bool          `yaml:"json_lines,omitempty"`
+	AcceptMatrix      []string      `yaml:"accept_matrix,omitempty"`
+	LanguageMatrix    []string      `yaml:"accept_language_matrix,omitempty"`
+	TLS               *TLSOptions   `yaml:"tls,omitempty"`
+	HTTPVersion       string        `yaml:"http_version,omitempty"`
+	ExpectContinue    bool          `yaml:"expect_continue,omitempty"`
+	Output            string        `yaml:"output,omitempty"`
+	Cacheable         bool          `yaml:"cacheable,omitempty"`
+}
+
+// Matrix returns the request header a step matrix varies and its values:
+// Accept for accept_matrix and Accept-Language for accept_language_matrix.
+// The header is empty when the step has no matrix.
+func (o Options) Matrix() (string, []string) {
+	switch {
+	case len(o.AcceptMatrix) > 0:
+		return "Accept", o.AcceptMatrix
+	case len(o.LanguageMatrix) > 0:
+		return "Accept-Language", o.LanguageMatrix
+	default:
+		return "", nil
+	}
+}
+
+// PollJob sends the step request once to start a job, then polls its status
+// URL with GET until a JSON status field reaches a terminal value. StatusURL
+// defaults to the Location header of the start response, or to the step URL
+// for GET steps. Asserts and captures run against the final response only.
+type PollJob struct {
+	StatusURL  string        `yaml:"status_url,omitempty"`
+	StatusPath string        `yaml:"status_path"`
+	Success    []string      `yaml:"success"`
+	Failure    []string      `yaml:"failure,omitempty"`
+	Interval   time.Duration `yaml:"interval,omitempty"`
+	Timeout    time.Duration `yaml:"timeout,omitempty"`
+}
+
+// WebSocket turns a GET step into a WebSocket exchange: after the handshake
+// the Send messages are written as text frames and the step waits for Receive
+// messages. The received messages, one per line, are the body seen by
+// asserts and captures.
+type WebSocket struct {
+	Send    []string      `yaml:"send,omitempty"`
+	Receive int           `yaml:"receive,omitempty"`
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// Conditional sends an ETag captured by an earlier step as If-None-Match or
+// If-Match. Each field names the capture holding the ETag; exactly one is set.
+type Conditional struct {
+	IfNoneMatch string `yaml:"if_none_match,omitempty"`
+	IfMatch     string `yaml:"if_match,omitempty"`
+}
+
+// Header returns the conditional header to send and the capture it reads.
+func (c Conditional) Header() (header, capture string) {
+	if c.IfMatch != "" {
+		return "If-Match", c.IfMatch
+	}
+	return "If-None-Match", c.IfNoneMatch
+}
+
+// ConditionalHeaders returns the headers conditional_on sends for method: a
+// safe method revalidates with If-None-Match and If-Modified-Since, any other
+// method guards an update with If-Match and If-Unmodified-Since.
+func ConditionalHeaders(method string) (etag, date string) {
+	switch strings.ToUpper(method) {
+	case MethodGet, MethodHead:
+		return "If-None-Match", "If-Modified-Since"
+	default:
+		return "If-Match", "If-Unmodified-Since"
+	}
+}
+
+// GRPC turns a POST step into a unary gRPC call. The request message is the
+// step body in its JSON form; it is encoded with the method's input type from
+// the Protoset descriptor set. The response message, in its JSON form, is the
+// body seen by asserts and captures. Status is the expected gRPC status code,
+// by name or number, and defaults to OK.
+type GRPC struct {
+	Service  string `yaml:"service"`
+	Method   string `yaml:"method"`
+	Protoset string `yaml:"protoset"`
+	Status   string `yaml:"status,omitempty"`
+}
+
+// GraphQL turns a POST step into a GraphQL request. The body is the JSON
+// object with query, operationName and variables; templates apply to the leaf
+// strings of Variables.
+type GraphQL struct {
+	Query         string         `yaml:"query"`
+	OperationName string         `yaml:"operation_name,omitempty"`
+	Variables     map[string]any `yaml:"variables,omitempty"`
+}
+
+// Connect turns a step into a raw connection check: a CONNECT step to
+// tcp://host:port or tls://host:port dials the address and, for tls://,
+// completes the handshake offering the ALPN protocols. No HTTP request is
+// sent; tls and certificate asserts and captures read the connection state.
+type Connect struct {
+	ALPN       []string      `yaml:"alpn,omitempty"`
+	ServerName string        `yaml:"server_name,omitempty"`
+	Timeout    time.Duration `yaml:"timeout,omitempty"`
+}
+
+// DNS turns a step into a DNS query: a LOOKUP step to dns://host resolves
+// host and exposes the records, sorted, as a JSON list of strings to jsonpath
+// asserts and captures. Type defaults to A. Server, as host:port, replaces
+// the system resolver.
+type DNS struct {
+	Type    string        `yaml:"type,omitempty"`
+	Server  string        `yaml:"server,omitempty"`
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// Auth obtains credentials for a step before its request is sent.
+type Auth struct {
+	OAuth2 *OAuth2 `yaml:"oauth2,omitempty"`
+}
+
+// OAuth2 fetches an access token with the client credentials grant and sends
+// it as a bearer token. Tokens are cached for the run and fetched again when
+// they expire. Fields support templates, so the secret can come from
+// --secret-file.
+type OAuth2 struct {
+	TokenURL     string   `yaml:"token_url"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	Scopes       []string `yaml:"scopes,omitempty"`
 }
 
 // StatusAssert represents an assertion on the HTTP status code.
@@ -45,6 +243,15 @@ type HeaderAssert struct {
 	Predicate Predicate `yaml:",inline"`
 }
 
+// CookieAssert represents an assertion on a cookie set by the response,
+// found by name. Attribute selects the compared value: the cookie value by
+// default, or another cookie attribute (see IsSupportedCookieAttribute).
+type CookieAssert struct {
+	Name      string    `yaml:"name"`
+	Attribute string    `yaml:"attribute,omitempty"`
+	Predicate Predicate `yaml:",inline"`
+}
+
 // CertificateAssert represents an assertion on SSL certificate information.
 // It allows validation of certificate fields like Subject, Issuer, ExpireDate, and SerialNumber.
 type CertificateAssert struct {
@@ -59,16 +266,262 @@ type JSONPathAssert struct {
 	Predicate Predicate `yaml:",inline"`
 }
 
+// CSSAssert represents an assertion on the first element of an HTML response
+// that matches Selector: its text, or the value of Attr when set.
+type CSSAssert struct {
+	Selector  string    `yaml:"selector"`
+	Attr      string    `yaml:"attr,omitempty"`
+	Predicate Predicate `yaml:",inline"`
+}
+
+// TLSAssert represents an assertion on the negotiated TLS connection,
+// either its protocol version or cipher suite.
+type TLSAssert struct {
+	Name      string    `yaml:"name"`
+	Predicate Predicate `yaml:",inline"`
+}
+
+// URLAssert represents an assertion on the normalized URL that was requested.
+type URLAssert struct {
+	Predicate `yaml:",inline"`
+}
+
+// TTFBAssert represents an assertion on time-to-first-byte in milliseconds,
+// measured separately from body transfer time.
+type TTFBAssert struct {
+	Predicate `yaml:",inline"`
+}
+
+// DurationAssert represents an assertion on the total response time, from
+// sending the request until the body was read, including redirects. Values
+// are durations such as "500ms" or numbers of milliseconds.
+type DurationAssert struct {
+	Predicate `yaml:",inline"`
+}
+
+// ContinueAssert represents an assertion on whether the server answered an
+// Expect: 100-continue request with 100 Continue before the final response.
+type ContinueAssert struct {
+	Predicate `yaml:",inline"`
+}
+
+// AttemptsAssert represents an assertion on the number of attempts the step
+// needed, including the first one and any retries.
+type AttemptsAssert struct {
+	Predicate `yaml:",inline"`
+}
+
+// RedirectsAssert represents an assertion on the number of redirect hops
+// followed before the final response.
+type RedirectsAssert struct {
+	Predicate `yaml:",inline"`
+}
+
+// GoldenAssert compares the response body with a golden file. Relative paths
+// resolve against the step file directory, like body_file.
+type GoldenAssert struct {
+	File string `yaml:"file"`
+}
+
+// JSONSchemaAssert validates the JSON response body against a JSON Schema
+// file in JSON or YAML. Relative paths resolve against the step file
+// directory, like body_file.
+type JSONSchemaAssert struct {
+	File string `yaml:"file"`
+}
+
+// BodyAssert checks the raw response body without decoding it: its size in
+// bytes, its SHA-256 digest or its leading bytes. The operation is one of the
+// BodyOp constants rather than a general predicate.
+type BodyAssert struct {
+	Predicate `yaml:",inline"`
+}
+
+// UnmarshalYAML decodes a body assert like a predicate. Hex values such as
+// 25504446 or 89504e47 read as YAML numbers, so digest and byte values keep
+// the text they were written with.
+func (b *BodyAssert) UnmarshalYAML(node ast.Node) error {
+	if err := b.Predicate.UnmarshalYAML(node); err != nil {
+		return err
+	}
+	if b.Operation != BodyOpSHA256Equals && b.Operation != BodyOpStartsWithBytes {
+		return nil
+	}
+
+	mapNode, ok := node.(*ast.MappingNode)
+	if !ok {
+		return nil
+	}
+	for _, pair := range mapNode.Values {
+		if pair.Key.String() != "value" {
+			continue
+		}
+		switch value := pair.Value.(type) {
+		case *ast.IntegerNode, *ast.FloatNode:
+			b.Value = value.GetToken().Value
+		}
+	}
+
+	return nil
+}
+
+// Operations of body asserts.
+const (
+	BodyOpSizeEquals      = "size_equals"
+	BodyOpSizeLessThan    = "size_less_than"
+	BodyOpSHA256Equals    = "sha256_equals"
+	BodyOpStartsWithBytes = "starts_with_bytes"
+)
+
+// ParseHexBytes decodes the hex value of a starts_with_bytes body assert.
+// Spaces and colons between bytes are ignored, so "89 50 4E 47" and
+// "89:50:4e:47" are accepted.
+func ParseHexBytes(value string) ([]byte, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r == ':' || unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, value)
+	if digits == "" {
+		return nil, errors.New("expected at least one hex byte")
+	}
+
+	data, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex bytes %q: %w", value, err)
+	}
+
+	return data, nil
+}
+
+// HeaderSetAssert checks a header holding a comma-separated list, such as
+// Content-Language or Vary, as a set of members compared case-insensitively.
+// The operation is one of the SetOp constants; the value is one member or a
+// list of members.
+type HeaderSetAssert struct {
+	Predicate `yaml:",inline"`
+}
+
+// Operations of content_language and vary asserts.
+const (
+	SetOpContains    = "contains"
+	SetOpNotContains = "not_contains"
+	SetOpEquals      = "equals"
+	SetOpSubsetOf    = "subset_of"
+)
+
+// HeaderSetMembers splits the values of a list header into its members,
+// trimmed and in order of appearance. Empty members are dropped.
+func HeaderSetMembers(values []string) []string {
+	var members []string
+	for _, value := range values {
+		for member := range strings.SplitSeq(value, ",") {
+			if member = strings.TrimSpace(member); member != "" {
+				members = append(members, member)
+			}
+		}
+	}
+	return members
+}
+
+// StableAssert requires a value captured by the same step to stay identical
+// across --repeat iterations, e.g. a resource ID returned for an Idempotency-Key.
+type StableAssert struct {
+	Capture string `yaml:"capture"`
+}
+
+// GraphQLAsserts checks a GraphQL response. NoErrors fails when the errors
+// member is present and not empty. Data and Errors paths are rooted at the
+// data and errors members, so $.user.name reads data.user.name.
+type GraphQLAsserts struct {
+	NoErrors bool             `yaml:"no_errors,omitempty"`
+	Data     []JSONPathAssert `yaml:"data,omitempty"`
+	Errors   []JSONPathAssert `yaml:"errors,omitempty"`
+}
+
+// JSONPath returns the Data and Errors asserts rooted at the response document.
+func (a GraphQLAsserts) JSONPath() []JSONPathAssert {
+	rooted := make([]JSONPathAssert, 0, len(a.Data)+len(a.Errors))
+	for _, assert := range a.Data {
+		assert.Path = GraphQLPath("data", assert.Path)
+		rooted = append(rooted, assert)
+	}
+	for _, assert := range a.Errors {
+		assert.Path = GraphQLPath("errors", assert.Path)
+		rooted = append(rooted, assert)
+	}
+	return rooted
+}
+
+// GraphQLPath roots path, which starts with $, at member of the response.
+func GraphQLPath(member, path string) string {
+	return "$." + member + strings.TrimPrefix(path, "$")
+}
+
+// AllowAssert checks the method set advertised by the Allow response header,
+// typically on OPTIONS responses. Methods are compared case-insensitively.
+//
+//	allow:
+//	  contains: [GET, POST]
+//	  excludes: [DELETE]
+type AllowAssert struct {
+	Contains []string `yaml:"contains,omitempty"`
+	Excludes []string `yaml:"excludes,omitempty"`
+}
+
 // StatusCapture represents a capture of the HTTP status code.
 type StatusCapture struct {
 	Name   string `yaml:"name"`
 	Redact bool   `yaml:"redact"`
 }
 
+// Capture types accepted by the type field of header, regex, body and
+// jsonpath captures. Without a type, captured values keep their raw form.
+const (
+	CaptureTypeInt    = "int"
+	CaptureTypeFloat  = "float"
+	CaptureTypeBool   = "bool"
+	CaptureTypeJSON   = "json"
+	CaptureTypeString = "string"
+)
+
+// IsSupportedCaptureType reports whether value is a known capture type.
+func IsSupportedCaptureType(value string) bool {
+	switch value {
+	case CaptureTypeInt, CaptureTypeFloat, CaptureTypeBool, CaptureTypeJSON, CaptureTypeString:
+		return true
+	default:
+		return false
+	}
+}
+
 // HeaderCapture represents a capture of a specific HTTP header.
+//
+// Parse captures structured values instead of the raw header: HeaderParseLink
+// maps each RFC 8288 link relation to its URL, and HeaderParseList splits
+// comma-separated values into a list.
 type HeaderCapture struct {
 	Name       string `yaml:"name"`
 	HeaderName string `yaml:"header_name"`
+	Parse      string `yaml:"parse,omitempty"`
+	Type       string `yaml:"type,omitempty"`
+	Redact     bool   `yaml:"redact"`
+}
+
+// Structured header formats for header captures.
+const (
+	HeaderParseLink = "link"
+	HeaderParseList = "list"
+)
+
+// CookieCapture represents a capture of the value, or another attribute, of a
+// cookie set by the response.
+type CookieCapture struct {
+	Name       string `yaml:"name"`
+	CookieName string `yaml:"cookie_name"`
+	Attribute  string `yaml:"attribute,omitempty"`
+	Type       string `yaml:"type,omitempty"`
 	Redact     bool   `yaml:"redact"`
 }
 
@@ -83,6 +536,67 @@ type CertificateCapture struct {
 type JSONPathCapture struct {
 	Name   string `yaml:"name"`
 	Path   string `yaml:"path"`
+	Type   string `yaml:"type,omitempty"`
+	Redact bool   `yaml:"redact"`
+}
+
+// XPathCapture represents a capture using an XPath expression over an XML or
+// HTML response body.
+type XPathCapture struct {
+	Name   string `yaml:"name"`
+	Path   string `yaml:"path"`
+	Type   string `yaml:"type,omitempty"`
+	Redact bool   `yaml:"redact"`
+}
+
+// CSSCapture represents a capture of the text, or the value of Attr, of the
+// first element of an HTML response that matches Selector.
+type CSSCapture struct {
+	Name     string `yaml:"name"`
+	Selector string `yaml:"selector"`
+	Attr     string `yaml:"attr,omitempty"`
+	Type     string `yaml:"type,omitempty"`
+	Redact   bool   `yaml:"redact"`
+}
+
+// TLSCapture represents a capture of the negotiated TLS version or cipher suite.
+type TLSCapture struct {
+	Name     string `yaml:"name"`
+	TLSField string `yaml:"tls_field"`
+	Redact   bool   `yaml:"redact"`
+}
+
+// OutputCapture represents a capture of a property of the file written by
+// options.output: its size in bytes, its hex SHA-256 digest or its path.
+type OutputCapture struct {
+	Name     string `yaml:"name"`
+	Property string `yaml:"property"`
+	Redact   bool   `yaml:"redact"`
+}
+
+// Properties of the options.output file available to output captures.
+const (
+	OutputPropertySize   = "size"
+	OutputPropertySHA256 = "sha256"
+	OutputPropertyPath   = "path"
+)
+
+// URLCapture represents a capture of the normalized URL that was requested.
+type URLCapture struct {
+	Name   string `yaml:"name"`
+	Redact bool   `yaml:"redact"`
+}
+
+// TTFBCapture represents a capture of time-to-first-byte in milliseconds.
+type TTFBCapture struct {
+	Name   string `yaml:"name"`
+	Redact bool   `yaml:"redact"`
+}
+
+// DurationCapture represents a capture of the total response time in
+// milliseconds.
+type DurationCapture struct {
+	Name   string `yaml:"name"`
 	Redact bool   `yaml:"redact"`
 }
 
@@ -91,22 +605,43 @@ type RegexCapture struct {
 	Name    string `yaml:"name"`
 	Pattern string `yaml:"pattern"`
 	Group   int    `yaml:"group"`
+	Type    string `yaml:"type,omitempty"`
 	Redact  bool   `yaml:"redact"`
 }
 
 // BodyCapture represents a capture of the entire response body.
 type BodyCapture struct {
 	Name   string `yaml:"name"`
+	Type   string `yaml:"type,omitempty"`
 	Redact bool   `yaml:"redact"`
 }
 
 // Asserts groups all supported assertion types for a step.
 // Each assertion type validates different aspects of the HTTP response.
 type Asserts struct {
-	Status      []StatusAssert      `yaml:"status,omitempty"`
-	Headers     []HeaderAssert      `yaml:"headers,omitempty"`
-	Certificate []CertificateAssert `yaml:"certificate,omitempty"`
-	JSONPath    []JSONPathAssert    `yaml:"jsonpath,omitempty"`
+	Status          []StatusAssert      `yaml:"status,omitempty"`
+	Headers         []HeaderAssert      `yaml:"headers,omitempty"`
+	Cookies         []CookieAssert      `yaml:"cookies,omitempty"`
+	Certificate     []CertificateAssert `yaml:"certificate,omitempty"`
+	JSONPath        []JSONPathAssert    `yaml:"jsonpath,omitempty"`
+	CSS             []CSSAssert         `yaml:"css,omitempty"`
+	URL             []URLAssert         `yaml:"url,omitempty"`
+	TLS             []TLSAssert         `yaml:"tls,omitempty"`
+	TTFB            []TTFBAssert        `yaml:"ttfb,omitempty"`
+	Duration        []DurationAssert    `yaml:"duration,omitempty"`
+	Attempts        []AttemptsAssert    `yaml:"attempts,omitempty"`
+	Redirects       []RedirectsAssert   `yaml:"redirects,omitempty"`
+	Golden          []GoldenAssert      `yaml:"golden,omitempty"`
+	JSONSchema      []JSONSchemaAssert  `yaml:"jsonschema,omitempty"`
+	Body            []BodyAssert        `yaml:"body,omitempty"`
+	ContentLanguage []HeaderSetAssert   `yaml:"content_language,omitempty"`
+	Vary            []HeaderSetAssert   `yaml:"vary,omitempty"`
+	Expr            []string            `yaml:"expr,omitempty"`
+	Stable          []StableAssert      `yaml:"stable,omitempty"`
+	Continue        []ContinueAssert    `yaml:"continue,omitempty"`
+	Range           *bool               `yaml:"range,omitempty"`
+	Allow           *AllowAssert        `yaml:"allow,omitempty"`
+	GraphQL         *GraphQLAsserts     `yaml:"graphql,omitempty"`
 }
 
 // Captures groups all supported capture types for a step.
@@ -114,10 +649,41 @@ type Asserts struct {
 type Captures struct {
 	Status      []StatusCapture      `yaml:"status,omitempty"`
 	Headers     []HeaderCapture      `yaml:"headers,omitempty"`
+	Cookies     []CookieCapture      `yaml:"cookies,omitempty"`
 	Certificate []CertificateCapture `yaml:"certificate,omitempty"`
 	JSONPath    []JSONPathCapture    `yaml:"jsonpath,omitempty"`
+	XPath       []XPathCapture       `yaml:"xpath,omitempty"`
+	CSS         []CSSCapture         `yaml:"css,omitempty"`
 	Regex       []RegexCapture       `yaml:"regex,omitempty"`
 	Body        []BodyCapture        `yaml:"body,omitempty"`
+	URL         []URLCapture         `yaml:"url,omitempty"`
+	TLS         []TLSCapture         `yaml:"tls,omitempty"`
+	TTFB        []TTFBCapture        `yaml:"ttfb,omitempty"`
+	Duration    []DurationCapture    `yaml:"duration,omitempty"`
+	Output      []OutputCapture      `yaml:"output,omitempty"`
+	Sink        []CaptureSink        `yaml:"sink,omitempty"`
+}
+
+// CaptureSink writes the value of the capture Name, made by the same step, to
+// File, for tools that run after rq. Redacted values are written as their
+// redaction placeholder unless Reveal is set.
+type CaptureSink struct {
+	Name   string `yaml:"name"`
+	File   string `yaml:"file"`
+	Reveal bool   `yaml:"reveal,omitempty"`
+}
+
+// Check is a predicate over the variable Name, evaluated after the step's
+// captures without sending a request. A string value is rendered as a
+// template first, so it can compute the expected value from other variables.
+type Check struct {
+	Name      string    `yaml:"name"`
+	Predicate Predicate `yaml:",inline"`
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for Check.
+func (c *Check) UnmarshalYAML(node ast.Node) error {
+	return unmarshalAssertWithField(node, "name", &c.Name, &c.Predicate, "Check")
 }
 
 // UnmarshalYAML implements custom YAML unmarshaling for HeaderAssert.
@@ -151,6 +717,18 @@ func (h *HeaderCapture) UnmarshalYAML(node ast.Node) error {
 			} else {
 				return fmt.Errorf("%w: HeaderCapture: header_name must be string", ErrParser)
 			}
+		case "parse":
+			if stringVal, ok := valNode.Value.(*ast.StringNode); ok {
+				h.Parse = stringVal.Value
+			} else {
+				return fmt.Errorf("%w: HeaderCapture: parse must be string", ErrParser)
+			}
+		case "type":
+			if stringVal, ok := valNode.Value.(*ast.StringNode); ok {
+				h.Type = stringVal.Value
+			} else {
+				return fmt.Errorf("%w: HeaderCapture: type must be string", ErrParser)
+			}
 		case "redact":
 			if boolVal, ok := valNode.Value.(*ast.BoolNode); ok {
 				h.Redact = boolVal.Value
@@ -158,6 +736,9 @@ func (h *HeaderCapture) UnmarshalYAML(node ast.Node) error {
 				return fmt.Errorf("%w: HeaderCapture: redact must be boolean", ErrParser)
 			}
 		default:
+			if suggestion := closestName(kNode.Value, yamlFieldNames(reflect.TypeFor[HeaderCapture]())); suggestion != "" {
+				return fmt.Errorf("%w: HeaderCapture: unknown field %q, did you mean %q?", ErrParser, kNode.Value, suggestion)
+			}
 			return fmt.Errorf("%w: HeaderCapture: unknown field %q", ErrParser, kNode.Value)
 		}
 	}
@@ -175,6 +756,54 @@ func (p *JSONPathAssert) UnmarshalYAML(node ast.Node) error {
 	return unmarshalAssertWithField(node, "path", &p.Path, &p.Predicate, "JSONPathAssert")
 }
 
+// UnmarshalYAML implements custom YAML unmarshaling for CSSAssert.
+func (c *CSSAssert) UnmarshalYAML(node ast.Node) error {
+	rest, err := takeOptionalField(node, "attr", &c.Attr, "CSSAssert")
+	if err != nil {
+		return err
+	}
+	return unmarshalAssertWithField(rest, "selector", &c.Selector, &c.Predicate, "CSSAssert")
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for CookieAssert.
+func (c *CookieAssert) UnmarshalYAML(node ast.Node) error {
+	rest, err := takeOptionalField(node, "attribute", &c.Attribute, "CookieAssert")
+	if err != nil {
+		return err
+	}
+	return unmarshalAssertWithField(rest, "name", &c.Name, &c.Predicate, "CookieAssert")
+}
+
+// takeOptionalField stores the string value of fieldName, when present, and
+// returns the rest of the mapping for unmarshalAssertWithField.
+func takeOptionalField(node ast.Node, fieldName string, fieldValue *string, typeName string) (ast.Node, error) {
+	mapNode, ok := node.(*ast.MappingNode)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: expected mapping node", ErrParser, typeName)
+	}
+
+	rest := &ast.MappingNode{}
+	for _, valNode := range mapNode.Values {
+		kNode, ok := valNode.Key.(*ast.StringNode)
+		if !ok || kNode.Value != fieldName {
+			rest.Values = append(rest.Values, valNode)
+			continue
+		}
+		stringVal, ok := valNode.Value.(*ast.StringNode)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s: %s value must be string", ErrParser, typeName, fieldName)
+		}
+		*fieldValue = stringVal.Value
+	}
+
+	return rest, nil
+}
+
+// UnmarshalYAML implements custom YAML unmarshaling for TLSAssert.
+func (a *TLSAssert) UnmarshalYAML(node ast.Node) error {
+	return unmarshalAssertWithField(node, "name", &a.Name, &a.Predicate, "TLSAssert")
+}
+
 // unmarshalAssertWithField is a helper function to reduce code duplication.
 func unmarshalAssertWithField(node ast.Node, fieldName string, fieldValue *string, predicate *Predicate, typeName string) error {
 	mapNode, ok := node.(*ast.MappingNode)
@@ -216,11 +845,21 @@ func unmarshalAssertWithField(node ast.Node, fieldName string, fieldValue *strin
 }
 
 // Parse decodes a YAML stream of steps.
+// Unknown keys are rejected; when a known key at the same level is a near
+// miss, the error suggests it.
 func Parse(r io.Reader) ([]Step, error) {
-	decoder := yaml.NewDecoder(r, yaml.Strict(), yaml.DisallowUnknownField())
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read YAML: %v", ErrParser, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data), yaml.Strict(), yaml.DisallowUnknownField())
 	var steps []Step
 
 	if err := decoder.Decode(&steps); err != nil {
+		if hint := unknownFieldHint(err, data); hint != "" {
+			return nil, fmt.Errorf("%w: failed to decode YAML: %v\n%s", ErrParser, err, hint)
+		}
 		return nil, fmt.Errorf("%w: failed to decode YAML: %v", ErrParser, err)
 	}
 
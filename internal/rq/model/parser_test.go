@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -102,6 +103,25 @@ func TestParse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "predicate_value_with_scalar_keys",
+			yaml: `
+- method: GET
+  url: https://api.example.com/health
+  asserts:
+    jsonpath:
+      - path: $.labels
+        op: equals
+        value: {1: a, true: x, name: b}
+`,
+			check: func(t *testing.T, steps []Step) {
+				want := map[string]any{"1": "a", "true": "x", "name": "b"}
+				got := steps[0].Asserts.JSONPath[0].Predicate.Value
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Predicate.Value = %#v, want %#v", got, want)
+				}
+			},
+		},
 		{
 			name: "structured_captures_status",
 			yaml: `
@@ -358,6 +378,20 @@ func TestParse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "body_canonical_json_option",
+			yaml: `
+- method: POST
+  url: https://api.example.com/orders
+  options:
+    body_canonical_json: true
+`,
+			check: func(t *testing.T, steps []Step) {
+				if !steps[0].Options.BodyCanonicalJSON {
+					t.Fatal("Options.BodyCanonicalJSON = false, want true")
+				}
+			},
+		},
 		{
 			name: "missing_method",
 			yaml: `
@@ -761,6 +795,33 @@ url: https://api.example.com/health
       - path: string(//profile/name)
         op: equals
         value: "Alice"
+`,
+			wantErr: true,
+		},
+		{
+			name: "css_assert_with_attr",
+			yaml: `
+- method: GET
+  url: https://shop.example.com/cart
+  asserts:
+    css:
+      - selector: a.checkout
+        attr: href
+        op: equals
+        value: /checkout
+`,
+			wantErr: false,
+		},
+		{
+			name: "css_attr_not_string",
+			yaml: `
+- method: GET
+  url: https://shop.example.com/cart
+  asserts:
+    css:
+      - selector: a.checkout
+        attr: [href]
+        op: exists
 `,
 			wantErr: true,
 		},
@@ -1025,6 +1086,122 @@ func TestParseBodyFile(t *testing.T) {
 	}
 }
 
+func TestParseBodyAssertsKeepHexText(t *testing.T) {
+	t.Parallel()
+
+	yamlContent := `
+- method: GET
+  url: https://example.com/report.pdf
+  asserts:
+    body:
+      - op: starts_with_bytes
+        value: 25504446
+      - op: starts_with_bytes
+        value: 89504e47
+      - op: size_less_than
+        value: 1048576
+`
+
+	steps, err := Parse(strings.NewReader(yamlContent))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []any{"25504446", "89504e47", int64(1048576)}
+	got := steps[0].Asserts.Body
+	if len(got) != len(want) {
+		t.Fatalf("expected %d body asserts, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].Value != want[i] {
+			t.Errorf("Body[%d].Value = %#v, want %#v", i, got[i].Value, want[i])
+		}
+	}
+}
+
+func TestParseBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		yaml      string
+		wantText  string
+		wantValue any
+	}{
+		{
+			name: "literal_block",
+			yaml: `
+- method: POST
+  url: https://example.com/users
+  body: |
+    {"name": "rq"}
+`,
+			wantText: "{\"name\": \"rq\"}\n",
+		},
+		{
+			name: "mapping",
+			yaml: `
+- method: POST
+  url: https://example.com/users
+  body:
+    name: "{{.name}}"
+    age: 30
+    tags: [a, b]
+`,
+			wantValue: map[string]any{
+				"name": "{{.name}}",
+				"age":  int64(30),
+				"tags": []any{"a", "b"},
+			},
+		},
+		{
+			name: "sequence",
+			yaml: `
+- method: POST
+  url: https://example.com/users
+  body:
+    - id: 1
+    - id: 2
+`,
+			wantValue: []any{
+				map[string]any{"id": int64(1)},
+				map[string]any{"id": int64(2)},
+			},
+		},
+		{
+			name: "empty_mapping",
+			yaml: `
+- method: POST
+  url: https://example.com/users
+  body: {}
+`,
+			wantValue: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			steps, err := Parse(strings.NewReader(tt.yaml))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			body := steps[0].Body
+			if body.Text != tt.wantText {
+				t.Errorf("Body.Text = %q, want %q", body.Text, tt.wantText)
+			}
+			if body.IsStructured() != (tt.wantValue != nil) {
+				t.Fatalf("Body.IsStructured() = %v", body.IsStructured())
+			}
+			if !reflect.DeepEqual(body.Value, tt.wantValue) {
+				t.Errorf("Body.Value = %#v, want %#v", body.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
 // compareSlices compares two []any slices for equality
 func compareSlices(a, b []any) bool {
 	if len(a) != len(b) {
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// Positions maps field paths of one step, using YAML key names such as
+// "asserts.jsonpath[2]", to 1-based source lines. The empty path is the step.
+type Positions map[string]int
+
+// Line returns the line of path, falling back to its closest recorded parent
+// and finally to the step itself. It returns 0 when nothing is known.
+func (p Positions) Line(path string) int {
+	for {
+		if line, ok := p[path]; ok {
+			return line
+		}
+		if path == "" {
+			return 0
+		}
+		path = parentPath(path)
+	}
+}
+
+func parentPath(path string) string {
+	cut := strings.LastIndexAny(path, ".[")
+	if cut < 0 {
+		return ""
+	}
+
+	return path[:cut]
+}
+
+// ParsePositions records where each step and its fields are declared in an
+// rq YAML document, indexed like the steps returned by Parse.
+func ParsePositions(data []byte) ([]Positions, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse YAML: %v", ErrParser, err)
+	}
+	if len(file.Docs) == 0 {
+		return nil, nil
+	}
+
+	sequence, ok := unwrapNode(file.Docs[0].Body).(*ast.SequenceNode)
+	if !ok {
+		return nil, nil
+	}
+
+	result := make([]Positions, 0, len(sequence.Values))
+	for _, stepNode := range sequence.Values {
+		positions := Positions{}
+		collectPositions(stepNode, "", positions)
+		result = append(result, positions)
+	}
+
+	return result, nil
+}
+
+func collectPositions(node ast.Node, path string, positions Positions) {
+	node = unwrapNode(node)
+	if node == nil {
+		return
+	}
+	if _, ok := positions[path]; !ok {
+		positions[path] = nodeLine(node)
+	}
+
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		for _, value := range n.Values {
+			collectMappingValue(value, path, positions)
+		}
+	case *ast.MappingValueNode:
+		collectMappingValue(n, path, positions)
+	case *ast.SequenceNode:
+		for i, value := range n.Values {
+			collectPositions(value, fmt.Sprintf("%s[%d]", path, i), positions)
+		}
+	}
+}
+
+func collectMappingValue(value *ast.MappingValueNode, path string, positions Positions) {
+	key := value.Key.GetToken().Value
+	if path != "" {
+		key = path + "." + key
+	}
+
+	positions[key] = value.Key.GetToken().Position.Line
+	collectPositions(value.Value, key, positions)
+}
+
+func nodeLine(node ast.Node) int {
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		if len(n.Values) > 0 {
+			return n.Values[0].Key.GetToken().Position.Line
+		}
+	case *ast.MappingValueNode:
+		return n.Key.GetToken().Position.Line
+	}
+
+	if token := node.GetToken(); token != nil {
+		return token.Position.Line
+	}
+
+	return 0
+}
+
+func unwrapNode(node ast.Node) ast.Node {
+	for {
+		switch n := node.(type) {
+		case *ast.TagNode:
+			node = n.Value
+		case *ast.AnchorNode:
+			node = n.Value
+		default:
+			return node
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePositions(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`- method: GET
+  url: https://api.example.com/health
+- method: POST
+  url: https://api.example.com/users
+  asserts:
+    jsonpath:
+      - path: $.id
+        op: exists
+      - {path: $.name, op: equals, value: rq}
+`)
+
+	positions, err := ParsePositions(data)
+	if err != nil {
+		t.Fatalf("ParsePositions() error = %v", err)
+	}
+	if len(positions) != 2 {
+		t.Fatalf("len(positions) = %d, want 2", len(positions))
+	}
+
+	tests := []struct {
+		step int
+		path string
+		want int
+	}{
+		{step: 0, path: "", want: 1},
+		{step: 0, path: "url", want: 2},
+		{step: 1, path: "", want: 3},
+		{step: 1, path: "asserts.jsonpath", want: 6},
+		{step: 1, path: "asserts.jsonpath[0]", want: 7},
+		{step: 1, path: "asserts.jsonpath[0].op", want: 8},
+		{step: 1, path: "asserts.jsonpath[1]", want: 9},
+		{step: 1, path: "asserts.jsonpath[5]", want: 6},
+		{step: 1, path: "captures.status[0]", want: 3},
+	}
+
+	for _, tt := range tests {
+		if got := positions[tt.step].Line(tt.path); got != tt.want {
+			t.Errorf("step %d Line(%q) = %d, want %d", tt.step, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParsePositionsNonSequence(t *testing.T) {
+	t.Parallel()
+
+	positions, err := ParsePositions([]byte("method: GET\n"))
+	if err != nil {
+		t.Fatalf("ParsePositions() error = %v", err)
+	}
+	if !reflect.DeepEqual(positions, []Positions(nil)) {
+		t.Fatalf("ParsePositions() = %v, want nil", positions)
+	}
+}
@@ -28,12 +28,14 @@ func nodeToValue(node ast.Node) (any, error) {
 		return n.Value, nil
 	case *ast.StringNode:
 		return n.Value, nil
+	case *ast.LiteralNode:
+		return n.Value.Value, nil
 	case *ast.BoolNode:
 		return n.Value, nil
 	case *ast.NullNode:
 		return nil, nil
 	case *ast.SequenceNode:
-		var result []any
+		result := make([]any, 0, len(n.Values))
 		for i, item := range n.Values {
 			val, err := nodeToValue(item)
 			if err != nil {
@@ -42,11 +44,38 @@ func nodeToValue(node ast.Node) (any, error) {
 			result = append(result, val)
 		}
 		return result, nil
+	case *ast.MappingNode:
+		result := make(map[string]any, len(n.Values))
+		for _, pair := range n.Values {
+			key, err := mappingKey(pair.Key)
+			if err != nil {
+				return nil, err
+			}
+			val, err := nodeToValue(pair.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for key %q: %w", key, err)
+			}
+			result[key] = val
+		}
+		return result, nil
 	default:
 		return nil, fmt.Errorf("unsupported node type: %T", node)
 	}
 }
 
+// mappingKey returns the text of a scalar mapping key, so {1: a} and
+// {true: x} decode to the keys "1" and "true" as JSON object keys would.
+func mappingKey(node ast.MapKeyNode) (string, error) {
+	switch key := node.(type) {
+	case *ast.StringNode:
+		return key.Value, nil
+	case ast.ScalarNode:
+		return key.String(), nil
+	default:
+		return "", fmt.Errorf("mapping key must be a scalar, got %T", node)
+	}
+}
+
 // Predicate represents a parsed predicate from YAML.
 // The parser handles YAML parsing only; semantic validation is delegated to spec/predicate.
 type Predicate struct {
@@ -70,12 +99,12 @@ func (p *Predicate) UnmarshalYAML(node ast.Node) error {
 	}
 
 	for _, valNode := range mapNode.Values {
-		key, ok := valNode.Key.(*ast.StringNode)
-		if !ok {
-			return errors.New("predicate key must be a string")
+		key, err := mappingKey(valNode.Key)
+		if err != nil {
+			return fmt.Errorf("predicate key: %w", err)
 		}
 
-		switch key.Value {
+		switch key {
 		case "op":
 			opNode, ok := valNode.Value.(*ast.StringNode)
 			if !ok {
@@ -94,7 +123,7 @@ func (p *Predicate) UnmarshalYAML(node ast.Node) error {
 			p.Value = value
 			p.HasValue = true
 		default:
-			return fmt.Errorf("unsupported predicate key %q: use 'op' and optional 'value'", key.Value)
+			return fmt.Errorf("unsupported predicate key %q: use 'op' and optional 'value'", key)
 		}
 	}
 
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// maxSuggestionDistance bounds how different a known field may be from a
+// misspelled one and still be suggested, e.g. "assert" for "asserts".
+const maxSuggestionDistance = 2
+
+// unknownFieldHint returns a "did you mean" hint for an unknown field error,
+// or "" when err is another error or no known field at that level is close.
+func unknownFieldHint(err error, data []byte) string {
+	var unknown *yaml.UnknownFieldError
+	if !errors.As(err, &unknown) || unknown.Token == nil {
+		return ""
+	}
+
+	file, parseErr := parser.ParseBytes(data, 0)
+	if parseErr != nil || len(file.Docs) == 0 {
+		return ""
+	}
+
+	parents, found := findKeyParents(file.Docs[0].Body, unknown.Token.Position.Line, unknown.Token.Position.Column, nil)
+	if !found {
+		return ""
+	}
+
+	typ := reflect.TypeFor[Step]()
+	for _, key := range parents {
+		next, ok := fieldType(typ, key)
+		if !ok {
+			return ""
+		}
+		typ = next
+	}
+
+	suggestion := closestName(unknown.Token.Value, yamlFieldNames(typ))
+	if suggestion == "" {
+		return ""
+	}
+
+	return `did you mean "` + suggestion + `"?`
+}
+
+// findKeyParents locates the mapping key at line:column and returns the keys
+// of the mappings enclosing it, outermost first. Sequence levels are skipped
+// because they do not change the decoded type beyond the element.
+func findKeyParents(node ast.Node, line, column int, parents []string) ([]string, bool) {
+	switch n := unwrapNode(node).(type) {
+	case *ast.MappingNode:
+		for _, value := range n.Values {
+			if result, ok := findKeyParents(value, line, column, parents); ok {
+				return result, true
+			}
+		}
+	case *ast.MappingValueNode:
+		position := n.Key.GetToken().Position
+		if position.Line == line && position.Column == column {
+			return parents, true
+		}
+		return findKeyParents(n.Value, line, column, append(slices.Clone(parents), n.Key.GetToken().Value))
+	case *ast.SequenceNode:
+		for _, value := range n.Values {
+			if result, ok := findKeyParents(value, line, column, parents); ok {
+				return result, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// fieldType returns the element type decoded for key within typ, following
+// pointers and slices.
+func fieldType(typ reflect.Type, key string) (reflect.Type, bool) {
+	typ = elemType(typ)
+	if typ.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, inline := yamlFieldName(field)
+		if inline {
+			if found, ok := fieldType(field.Type, key); ok {
+				return found, true
+			}
+			continue
+		}
+		if name == key {
+			return elemType(field.Type), true
+		}
+	}
+
+	return nil, false
+}
+
+func elemType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+
+	return typ
+}
+
+func yamlFieldNames(typ reflect.Type) []string {
+	typ = elemType(typ)
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, inline := yamlFieldName(field)
+		switch {
+		case inline:
+			names = append(names, yamlFieldNames(field.Type)...)
+		case name != "":
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func yamlFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag, ok := field.Tag.Lookup("yaml")
+	if !ok {
+		return strings.ToLower(field.Name), false
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return "", false
+	}
+
+	return name, strings.Contains(options, "inline")
+}
+
+// closestName returns the candidate closest to name, or "" when none is within
+// maxSuggestionDistance edits.
+func closestName(name string, candidates []string) string {
+	name = strings.ToLower(name)
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, candidate := range candidates {
+		distance := editDistance(name, candidate)
+		if len(name) >= 3 && strings.HasPrefix(candidate, name) {
+			// Truncated keys such as "header" for "header_name".
+			distance = min(distance, maxSuggestionDistance)
+		}
+		if distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSuggestsNearMissFields(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		wantHint string
+	}{
+		{
+			name: "step level",
+			input: `- method: GET
+  url: https://api.example.com
+  assert:
+    status:
+      - op: equals
+        value: 200
+`,
+			wantHint: `did you mean "asserts"?`,
+		},
+		{
+			name: "capture group level",
+			input: `- method: GET
+  url: https://api.example.com
+  captures:
+    jsonpaths:
+      - name: id
+        path: $.id
+`,
+			wantHint: `did you mean "jsonpath"?`,
+		},
+		{
+			name: "nested options",
+			input: `- method: GET
+  url: https://api.example.com
+  options:
+    retrys: 2
+`,
+			wantHint: `did you mean "retries"?`,
+		},
+		{
+			name: "header capture field",
+			input: `- method: GET
+  url: https://api.example.com
+  captures:
+    headers:
+      - name: request_id
+        header: X-Request-Id
+`,
+			wantHint: `did you mean "header_name"?`,
+		},
+		{
+			name: "no close match",
+			input: `- method: GET
+  url: https://api.example.com
+  completely_unrelated: true
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(strings.NewReader(tt.input))
+			if !errors.Is(err, ErrParser) {
+				t.Fatalf("Parse() error = %v, want ErrParser", err)
+			}
+			if tt.wantHint == "" {
+				if strings.Contains(err.Error(), "did you mean") {
+					t.Fatalf("unexpected suggestion in %q", err.Error())
+				}
+				return
+			}
+			if !strings.Contains(err.Error(), tt.wantHint) {
+				t.Fatalf("error %q does not contain %q", err.Error(), tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "asserts", b: "asserts", want: 0},
+		{a: "assert", b: "asserts", want: 1},
+		{a: "optoins", b: "options", want: 2},
+		{a: "", b: "url", want: 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
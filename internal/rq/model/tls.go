@@ -0,0 +1,64 @@
+package model
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// TLS connection fields available to tls asserts and captures.
+const (
+	TLSFieldVersion = "version"
+	TLSFieldCipher  = "cipher"
+	TLSFieldALPN    = "alpn"
+)
+
+// TLSOptions restricts the protocol versions and cipher suites offered for a step.
+// Cipher suites only apply to TLS 1.0-1.2; TLS 1.3 suites are not configurable.
+type TLSOptions struct {
+	MinVersion string   `yaml:"min_version,omitempty"`
+	MaxVersion string   `yaml:"max_version,omitempty"`
+	Ciphers    []string `yaml:"ciphers,omitempty"`
+}
+
+// ParseTLSVersion accepts "1.0" to "1.3", optionally prefixed with "TLS".
+func ParseTLSVersion(value string) (uint16, error) {
+	normalized := strings.TrimSpace(strings.ToUpper(value))
+	normalized = strings.TrimSpace(strings.TrimPrefix(normalized, "TLS"))
+
+	switch normalized {
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q (expected 1.0, 1.1, 1.2 or 1.3)", value)
+	}
+}
+
+// ParseCipherSuite resolves a cipher suite by its IANA name,
+// e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+func ParseCipherSuite(name string) (uint16, error) {
+	name = strings.TrimSpace(name)
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, nil
+		}
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.Name == name {
+			return suite.ID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unsupported cipher suite %q", name)
+}
+
+// IsSupportedTLSField reports whether field can be used in tls asserts and captures.
+func IsSupportedTLSField(field string) bool {
+	return field == TLSFieldVersion || field == TLSFieldCipher || field == TLSFieldALPN
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    uint16
+		wantErr bool
+	}{
+		{input: "1.0", want: tls.VersionTLS10},
+		{input: "1.1", want: tls.VersionTLS11},
+		{input: "1.2", want: tls.VersionTLS12},
+		{input: "1.3", want: tls.VersionTLS13},
+		{input: "TLS 1.2", want: tls.VersionTLS12},
+		{input: "tls1.3", want: tls.VersionTLS13},
+		{input: "1.4", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseTLSVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTLSVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseTLSVersion(%q) = %x, want %x", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCipherSuite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    uint16
+		wantErr bool
+	}{
+		{input: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", want: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		{input: "TLS_RSA_WITH_RC4_128_SHA", want: tls.TLS_RSA_WITH_RC4_128_SHA},
+		{input: "TLS_MADE_UP", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseCipherSuite(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCipherSuite(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseCipherSuite(%q) = %x, want %x", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Package monitor exposes the results of scheduled rq runs over HTTP:
+// /healthz reports the last run outcome and /metrics serves them in the
+// Prometheus text exposition format.
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+// State holds the outcome of completed runs. It is safe for concurrent use.
+type State struct {
+	labels map[string]string
+
+	mu          sync.RWMutex
+	runs        int
+	failedRuns  int
+	hasLastRun  bool
+	lastSuccess bool
+	lastError   string
+	lastAt      time.Time
+	lastSummary *output.Summary
+}
+
+// New returns an empty monitor state. labels, such as the --meta values of
+// the run, are added to every sample.
+func New(labels map[string]string) *State {
+	return &State{labels: labels}
+}
+
+// Record stores the result of a run. summary may be nil when the run failed
+// before any file executed, e.g. on a parse error.
+func (s *State) Record(summary *output.Summary, runErr error, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.runs++
+	s.hasLastRun = true
+	s.lastAt = at
+	s.lastSummary = summary
+	s.lastSuccess = runErr == nil
+	s.lastError = ""
+	if runErr != nil {
+		s.failedRuns++
+		s.lastError = runErr.Error()
+	}
+}
+
+// Handler serves /healthz and /metrics.
+func (s *State) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.serveHealth)
+	mux.HandleFunc("/metrics", s.serveMetrics)
+	return mux
+}
+
+func (s *State) serveHealth(w http.ResponseWriter, _ *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	switch {
+	case !s.hasLastRun:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = io.WriteString(w, "pending: no completed run\n")
+	case !s.lastSuccess:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintf(w, "failing: %s\n", s.lastError)
+	default:
+		_, _ = io.WriteString(w, "ok\n")
+	}
+}
+
+func (s *State) serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = s.WriteMetrics(w)
+}
+
+// WriteMetrics writes all metrics in the Prometheus text exposition format.
+func (s *State) WriteMetrics(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var b strings.Builder
+
+	writeMetric(&b, "rq_runs_total", "counter", "Completed suite runs.", s.sampleLabels(nil), float64(s.runs))
+	writeMetric(&b, "rq_run_failures_total", "counter", "Completed suite runs with at least one failure.", s.sampleLabels(nil), float64(s.failedRuns))
+
+	if s.hasLastRun {
+		writeMetric(&b, "rq_last_run_success", "gauge", "Whether the last run passed (1) or failed (0).", s.sampleLabels(nil), boolToFloat(s.lastSuccess))
+		writeMetric(&b, "rq_last_run_timestamp_seconds", "gauge", "Unix time the last run finished.", s.sampleLabels(nil), float64(s.lastAt.UnixNano())/1e9)
+	}
+
+	if summary := s.lastSummary; summary != nil {
+		writeMetric(&b, "rq_last_run_duration_seconds", "gauge", "Duration of the last run.", s.sampleLabels(nil), summary.TotalDuration.Seconds())
+		writeMetric(&b, "rq_last_run_requests", "gauge", "Requests executed by the last run.", s.sampleLabels(nil), float64(summary.ExecutedRequests))
+
+		results := append([]output.FileResult(nil), summary.FileResults...)
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Filename < results[j].Filename })
+
+		writeHeader(&b, "rq_file_success", "gauge", "Whether the file passed (1) or failed (0) in the last run.")
+		for _, result := range results {
+			writeSample(&b, "rq_file_success", s.sampleLabels(fileLabel(result.Filename)), boolToFloat(result.Error == nil))
+		}
+		writeHeader(&b, "rq_file_duration_seconds", "gauge", "Duration of the file in the last run.")
+		for _, result := range results {
+			writeSample(&b, "rq_file_duration_seconds", s.sampleLabels(fileLabel(result.Filename)), result.Duration.Seconds())
+		}
+		writeHeader(&b, "rq_file_requests", "gauge", "Requests executed by the file in the last run.")
+		for _, result := range results {
+			writeSample(&b, "rq_file_requests", s.sampleLabels(fileLabel(result.Filename)), float64(result.RequestCount))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMetric(b *strings.Builder, name, kind, help string, labels map[string]string, value float64) {
+	writeHeader(b, name, kind, help)
+	writeSample(b, name, labels, value)
+}
+
+func writeHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeSample(b *strings.Builder, name string, labels map[string]string, value float64) {
+	b.WriteString(name)
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(b, "%s=\"%s\"", key, escapeLabelValue(labels[key]))
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	b.WriteByte('\n')
+}
+
+// sampleLabels adds the state labels to the labels of one sample.
+func (s *State) sampleLabels(labels map[string]string) map[string]string {
+	if len(s.labels) == 0 {
+		return labels
+	}
+
+	merged := make(map[string]string, len(s.labels)+len(labels))
+	maps.Copy(merged, s.labels)
+	maps.Copy(merged, labels)
+	return merged
+}
+
+func fileLabel(filename string) map[string]string {
+	return map[string]string{"file": filename}
+}
+
+func escapeLabelValue(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	return replacer.Replace(value)
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
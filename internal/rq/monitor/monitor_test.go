@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		record     func(*State)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "no run yet",
+			record:     func(*State) {},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "pending",
+		},
+		{
+			name: "last run passed",
+			record: func(s *State) {
+				s.Record(output.NewSummary(0), nil, time.Unix(0, 0))
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "ok",
+		},
+		{
+			name: "last run failed",
+			record: func(s *State) {
+				s.Record(nil, errors.New("step 0 failed"), time.Unix(0, 0))
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "failing: step 0 failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			state := New(nil)
+			tt.record(state)
+
+			rec := httptest.NewRecorder()
+			state.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	summary := output.NewSummary(2)
+	summary.Add(output.FileResult{Filename: "b.yaml", RequestCount: 2, Duration: 1500 * time.Millisecond})
+	summary.Add(output.FileResult{Filename: `a"x.yaml`, RequestCount: 1, Duration: time.Second, Error: errors.New("boom")})
+	summary.SetTotalDuration(2500 * time.Millisecond)
+
+	state := New(nil)
+	state.Record(output.NewSummary(0), nil, time.Unix(100, 0))
+	state.Record(summary, errors.New("boom"), time.Unix(200, 0))
+
+	rec := httptest.NewRecorder()
+	state.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE rq_runs_total counter\nrq_runs_total 2\n",
+		"rq_run_failures_total 1\n",
+		"rq_last_run_success 0\n",
+		"rq_last_run_timestamp_seconds 200\n",
+		"rq_last_run_duration_seconds 2.5\n",
+		"rq_last_run_requests 3\n",
+		`rq_file_success{file="a\"x.yaml"} 0` + "\n" + `rq_file_success{file="b.yaml"} 1`,
+		`rq_file_duration_seconds{file="b.yaml"} 1.5`,
+		`rq_file_requests{file="b.yaml"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsLabels(t *testing.T) {
+	t.Parallel()
+
+	summary := output.NewSummary(1)
+	summary.Add(output.FileResult{Filename: "a.yaml", RequestCount: 1})
+
+	state := New(map[string]string{"env": "staging", "build": "1234"})
+	state.Record(summary, nil, time.Unix(100, 0))
+
+	var b strings.Builder
+	if err := state.WriteMetrics(&b); err != nil {
+		t.Fatalf("WriteMetrics() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`rq_runs_total{build="1234",env="staging"} 1`,
+		`rq_file_success{build="1234",env="staging",file="a.yaml"} 1`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, b.String())
+		}
+	}
+}
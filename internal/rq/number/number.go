@@ -3,6 +3,7 @@ package number
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 )
 
 // ToFloat64 converts supported numeric values to float64.
@@ -43,6 +44,39 @@ func ToFloat64(value any) (float64, bool) {
 	}
 }
 
+// ToInt64 converts integer-typed values and json.Number integers into int64.
+// Floats, json.Number values with a fraction or exponent and integers
+// outside the int64 range are not converted.
+func ToInt64(value any) (int64, bool) {
+	switch current := value.(type) {
+	case int:
+		return int64(current), true
+	case int8:
+		return int64(current), true
+	case int16:
+		return int64(current), true
+	case int32:
+		return int64(current), true
+	case int64:
+		return current, true
+	case uint:
+		return int64(current), uint64(current) <= math.MaxInt64
+	case uint8:
+		return int64(current), true
+	case uint16:
+		return int64(current), true
+	case uint32:
+		return int64(current), true
+	case uint64:
+		return int64(current), current <= math.MaxInt64
+	case json.Number:
+		parsed, err := current.Int64()
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
+
 // ToStrictInt converts integer-typed values into int.
 func ToStrictInt(value any) (int, error) {
 	switch current := value.(type) {
@@ -33,6 +33,37 @@ func TestToFloat64(t *testing.T) {
 	}
 }
 
+func TestToInt64(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input any
+		ok    bool
+		want  int64
+	}{
+		{name: "int", input: int(10), ok: true, want: 10},
+		{name: "json_number_beyond_float_precision", input: json.Number("9007199254740993"), ok: true, want: 9007199254740993},
+		{name: "json_number_with_fraction", input: json.Number("4.0"), ok: false},
+		{name: "uint64_out_of_range", input: uint64(1 << 63), ok: false},
+		{name: "float64", input: 12.0, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ToInt64(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("ToInt64(%v) ok = %v, want %v", tt.input, ok, tt.ok)
+			}
+			if ok && got != tt.want {
+				t.Fatalf("ToInt64(%v) value = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestToStrictInt(t *testing.T) {
 	t.Parallel()
 
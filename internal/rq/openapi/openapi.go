@@ -0,0 +1,313 @@
+// Package openapi loads OpenAPI 3 documents and checks responses against the
+// operations they describe.
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/jacoelho/rq/internal/rq/jsonschema"
+)
+
+// Spec is a loaded OpenAPI document.
+type Spec struct {
+	document map[string]any
+	bases    []string
+	paths    []pathTemplate
+}
+
+type pathTemplate struct {
+	template string
+	segments []string
+	item     map[string]any
+}
+
+// Load reads an OpenAPI 3 document in YAML or JSON.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// Parse decodes an OpenAPI 3 document in YAML or JSON.
+func Parse(data []byte) (*Spec, error) {
+	var document map[string]any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	version := fmt.Sprint(document["openapi"])
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, expected 3.x", version)
+	}
+
+	paths, ok := document["paths"].(map[string]any)
+	if !ok {
+		return nil, errors.New("document has no paths")
+	}
+
+	spec := &Spec{document: document, bases: serverBases(document)}
+	for template, node := range paths {
+		item, ok := node.(map[string]any)
+		if !ok {
+			continue
+		}
+		spec.paths = append(spec.paths, pathTemplate{
+			template: template,
+			segments: splitPath(template),
+			item:     item,
+		})
+	}
+	// More literal segments first, so /pets/mine wins over /pets/{id}.
+	slices.SortFunc(spec.paths, func(a, b pathTemplate) int {
+		if diff := literalSegments(b.segments) - literalSegments(a.segments); diff != 0 {
+			return diff
+		}
+		return strings.Compare(a.template, b.template)
+	})
+
+	return spec, nil
+}
+
+// Document returns the decoded document. Callers must not modify it.
+func (s *Spec) Document() map[string]any {
+	return s.document
+}
+
+// serverBases returns the path prefixes of the servers, such as /v1 for
+// https://api.example.com/v1. Server URLs may hold {variables}, so the path
+// is cut out by hand instead of parsing the URL.
+func serverBases(document map[string]any) []string {
+	servers, _ := document["servers"].([]any)
+
+	var bases []string
+	for _, node := range servers {
+		server, _ := node.(map[string]any)
+		rawURL, _ := server["url"].(string)
+		if _, rest, found := strings.Cut(rawURL, "://"); found {
+			_, rawURL, found = strings.Cut(rest, "/")
+			if !found {
+				continue
+			}
+			rawURL = "/" + rawURL
+		}
+		if base := strings.TrimRight(rawURL, "/"); base != "" && !slices.Contains(bases, base) {
+			bases = append(bases, base)
+		}
+	}
+
+	return bases
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func literalSegments(segments []string) int {
+	count := 0
+	for _, segment := range segments {
+		if !strings.Contains(segment, "{") {
+			count++
+		}
+	}
+	return count
+}
+
+// Operation is the operation of a spec that matched a request.
+type Operation struct {
+	Method string
+	Path   string
+
+	spec *Spec
+	node map[string]any
+}
+
+func (o *Operation) String() string {
+	return o.Method + " " + o.Path
+}
+
+// Match finds the operation for method and the request URL. Server path
+// prefixes are stripped before matching path templates.
+func (s *Spec) Match(method string, requestURL *url.URL) (*Operation, bool) {
+	key := strings.ToLower(method)
+	candidates := []string{requestURL.Path}
+	for _, base := range s.bases {
+		if rest, ok := strings.CutPrefix(requestURL.Path, base); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+			candidates = append(candidates, rest)
+		}
+	}
+
+	for _, candidate := range candidates {
+		segments := splitPath(candidate)
+		for _, path := range s.paths {
+			if !matchSegments(path.segments, segments) {
+				continue
+			}
+			node, ok := path.item[key].(map[string]any)
+			if !ok {
+				continue
+			}
+			return &Operation{Method: strings.ToUpper(method), Path: path.template, spec: s, node: node}, true
+		}
+	}
+
+	return nil, false
+}
+
+func matchSegments(template []string, segments []string) bool {
+	if len(template) != len(segments) {
+		return false
+	}
+	for i, segment := range template {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MismatchError lists the ways a response differs from its operation.
+type MismatchError struct {
+	Operation string
+	Problems  []string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("response does not match OpenAPI operation %s: %s", e.Operation, strings.Join(e.Problems, "; "))
+}
+
+// ValidateResponse checks the status, content type and JSON body of a
+// response against the operation. It returns a *MismatchError listing every
+// problem, or nil. Bodies of non-JSON media types are not checked.
+func (o *Operation) ValidateResponse(status int, contentType string, body []byte) error {
+	problems := o.responseProblems(status, contentType, body)
+	if len(problems) == 0 {
+		return nil
+	}
+	return &MismatchError{Operation: o.String(), Problems: problems}
+}
+
+func (o *Operation) responseProblems(status int, contentType string, body []byte) []string {
+	responses, _ := o.node["responses"].(map[string]any)
+	response, ok := o.findResponse(responses, status)
+	if !ok {
+		return []string{fmt.Sprintf("status %d is not documented", status)}
+	}
+
+	content, _ := response["content"].(map[string]any)
+	if len(content) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+	media, ok := findMedia(content, mediaType)
+	if !ok {
+		documented := make([]string, 0, len(content))
+		for name := range content {
+			documented = append(documented, name)
+		}
+		slices.Sort(documented)
+		return []string{fmt.Sprintf("content type %q is not documented for status %d (documented: %s)", contentType, status, strings.Join(documented, ", "))}
+	}
+
+	schema, hasSchema := media["schema"]
+	if !hasSchema || !isJSON(mediaType) {
+		return nil
+	}
+
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return []string{fmt.Sprintf("body is not valid JSON: %v", err)}
+	}
+
+	violations := jsonschema.New(o.spec.document).Node(schema).Validate(data)
+	problems := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		problems = append(problems, violation.String())
+	}
+	return problems
+}
+
+// findResponse picks the response for status: an exact code, then a range
+// such as 2XX, then default.
+func (o *Operation) findResponse(responses map[string]any, status int) (map[string]any, bool) {
+	code := strconv.Itoa(status)
+	keys := []string{code, code[:1] + "XX", code[:1] + "xx", "default"}
+	for _, key := range keys {
+		node, found := responses[key]
+		if !found {
+			continue
+		}
+		response, ok := o.resolve(node)
+		return response, ok
+	}
+	return nil, false
+}
+
+// resolve follows a $ref to a response object.
+func (o *Operation) resolve(node any) (map[string]any, bool) {
+	for range 8 {
+		current, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		ref, isRef := current["$ref"].(string)
+		if !isRef {
+			return current, true
+		}
+		target, err := jsonschema.Resolve(o.spec.document, ref)
+		if err != nil {
+			return nil, false
+		}
+		node = target
+	}
+	return nil, false
+}
+
+// findMedia matches the response media type against the content keys,
+// exact first, then type/* and */*.
+func findMedia(content map[string]any, mediaType string) (map[string]any, bool) {
+	major, _, _ := strings.Cut(mediaType, "/")
+	for _, want := range []string{mediaType, major + "/*", "*/*"} {
+		for key, node := range content {
+			name, _, err := mime.ParseMediaType(key)
+			if err != nil {
+				name = strings.ToLower(key)
+			}
+			if name == want {
+				media, _ := node.(map[string]any)
+				return media, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
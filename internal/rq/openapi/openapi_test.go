@@ -0,0 +1,180 @@
+package openapi
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const petstore = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+servers:
+  - url: https://{env}.example.com/v1
+paths:
+  /pets/{id}:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+        "404":
+          $ref: "#/components/responses/NotFound"
+  /pets/mine:
+    get:
+      responses:
+        2XX:
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+  /pets:
+    post:
+      responses:
+        default:
+          description: anything
+components:
+  responses:
+    NotFound:
+      content:
+        application/problem+json:
+          schema:
+            type: object
+            required: [title]
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+        tag: {type: string, nullable: true}
+`
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	spec, err := Parse([]byte(petstore))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		method string
+		url    string
+		want   string
+	}{
+		{method: "GET", url: "https://dev.example.com/v1/pets/7", want: "GET /pets/{id}"},
+		{method: "GET", url: "https://dev.example.com/v1/pets/mine", want: "GET /pets/mine"},
+		{method: "GET", url: "http://localhost:8080/pets/7", want: "GET /pets/{id}"},
+		{method: "post", url: "https://dev.example.com/v1/pets", want: "POST /pets"},
+		{method: "DELETE", url: "https://dev.example.com/v1/pets/7"},
+		{method: "GET", url: "https://dev.example.com/v1/owners/7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			t.Parallel()
+
+			requestURL, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			operation, ok := spec.Match(tt.method, requestURL)
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("Match() = %s, want no match", operation)
+				}
+				return
+			}
+			if !ok || operation.String() != tt.want {
+				t.Fatalf("Match() = %v, %v, want %s", operation, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	t.Parallel()
+
+	spec, err := Parse([]byte(petstore))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		method      string
+		status      int
+		contentType string
+		body        string
+		wantErr     []string
+	}{
+		{name: "valid", path: "/pets/1", status: 200, contentType: "application/json; charset=utf-8", body: `{"id":1,"name":"rex","tag":null}`},
+		{name: "schema_violation", path: "/pets/1", status: 200, contentType: "application/json", body: `{"id":"1"}`,
+			wantErr: []string{"GET /pets/{id}", "$.id: expected integer, got string", `missing required property "name"`}},
+		{name: "undocumented_status", path: "/pets/1", status: 500, contentType: "application/json", body: `{}`,
+			wantErr: []string{"status 500 is not documented"}},
+		{name: "undocumented_content_type", path: "/pets/1", status: 200, contentType: "text/html", body: `<p>`,
+			wantErr: []string{`content type "text/html" is not documented for status 200 (documented: application/json)`}},
+		{name: "response_ref", path: "/pets/1", status: 404, contentType: "application/problem+json", body: `{}`,
+			wantErr: []string{`$: missing required property "title"`}},
+		{name: "status_range", path: "/pets/mine", status: 206, contentType: "application/json", body: `[{"id":1,"name":"a"},{"id":2}]`,
+			wantErr: []string{`$[1]: missing required property "name"`}},
+		{name: "invalid_json", path: "/pets/1", status: 200, contentType: "application/json", body: `{`,
+			wantErr: []string{"body is not valid JSON"}},
+		{name: "no_content", path: "/pets", method: "POST", status: 201, contentType: "text/plain", body: "created"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			operation, ok := spec.Match(method, &url.URL{Path: tt.path})
+			if !ok {
+				t.Fatalf("Match(%s %s) found no operation", method, tt.path)
+			}
+
+			err := operation.ValidateResponse(tt.status, tt.contentType, []byte(tt.body))
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("ValidateResponse() error = %v", err)
+				}
+				return
+			}
+
+			var mismatch *MismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("ValidateResponse() error = %v, want *MismatchError", err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("ValidateResponse() error = %v, want containing %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	// Swagger 2.0 documents and documents without paths are rejected.
+	for _, document := range []string{`swagger: "2.0"`, `openapi: 3.1.0`} {
+		_, err := Parse([]byte(document + "\n"))
+		if err == nil {
+			t.Fatalf("Parse(%q) succeeded, want error", document)
+		}
+	}
+}
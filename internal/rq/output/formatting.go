@@ -4,6 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
 )
 
 // OutputFormat represents the output format for output.
@@ -43,73 +49,281 @@ func FormatAggregated(format OutputFormat, w io.Writer, summaries []*Summary) er
 	}
 }
 
-// FormatDebug outputs debug information with a description and data.
-func FormatDebug(format OutputFormat, w io.Writer, description string, data []byte) error {
+// DebugTiming is how long the request of a debugged response took, from
+// sending it until the body was read, and its time to first byte. A zero
+// field was not measured.
+type DebugTiming struct {
+	Duration time.Duration
+	TTFB     time.Duration
+}
+
+func (t DebugTiming) String() string {
+	var parts []string
+	if t.Duration > 0 {
+		parts = append(parts, fmt.Sprintf("%d ms", t.Duration.Milliseconds()))
+	}
+	if t.TTFB > 0 {
+		parts = append(parts, fmt.Sprintf("ttfb %d ms", t.TTFB.Milliseconds()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatDebug outputs debug information with a description and data. timing
+// is nil for requests.
+func FormatDebug(format OutputFormat, w io.Writer, description string, data []byte, timing *DebugTiming) error {
 	switch format {
 	case FormatJSON:
-		return formatDebugJSON(w, description, data)
+		return formatDebugJSON(w, description, data, timing)
 	case FormatText:
 		fallthrough
 	default:
-		return formatDebugText(w, description, data)
+		return formatDebugText(w, description, data, timing)
 	}
 }
 
 // formatText formats a single iteration summary in text format.
 func (s *Summary) formatText(w io.Writer) error {
+	p := s.Printer
 	for _, fileResult := range s.FileResults {
-		status := "Success"
+		status := p.Text(i18n.FileSuccess)
 		if fileResult.Error != nil {
-			status = fmt.Sprintf("Failed: %v", fileResult.Error)
+			status = p.Sprintf(i18n.FileFailed, fileResult.Error)
 		}
-		_, err := fmt.Fprintf(w, "%s: %s (%d request(s) in %d ms)\n",
+		if reason, ok := skipReason(fileResult.Error); ok {
+			status = p.Sprintf(i18n.FileSkipped, reason)
+		}
+		_, err := p.Fprintln(w, i18n.FileResult,
 			fileResult.Filename, status, fileResult.RequestCount, fileResult.Duration.Milliseconds())
 		if err != nil {
 			return err
 		}
+		if err := printVariableSnapshots(w, p, fileResult.Variables); err != nil {
+			return err
+		}
+		if err := printAttempts(w, p, fileResult.Steps); err != nil {
+			return err
+		}
+		if err := printArtifacts(w, p, fileResult.Steps); err != nil {
+			return err
+		}
 	}
 
 	if _, err := fmt.Fprintln(w, "--------------------------------------------------------------------------------"); err != nil {
 		return err
 	}
 
-	if _, err := fmt.Fprintf(w, "Executed files:    %d\n", s.ExecutedFiles); err != nil {
+	if _, err := p.Fprintln(w, i18n.ExecutedFiles, s.ExecutedFiles); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Executed requests: %d (%.2f/s)\n", s.ExecutedRequests, s.RequestsPerSecond()); err != nil {
+	if _, err := p.Fprintln(w, i18n.ExecutedRequests, s.ExecutedRequests, s.RequestsPerSecond()); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Succeeded files:   %d (%.1f%%)\n", s.SucceededFiles, s.SuccessPercentage()); err != nil {
+	if _, err := p.Fprintln(w, i18n.SucceededFiles, s.SucceededFiles, s.SuccessPercentage()); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Failed files:      %d (%.1f%%)\n", s.FailedFiles, s.FailurePercentage()); err != nil {
+	if _, err := p.Fprintln(w, i18n.FailedFiles, s.FailedFiles, s.FailurePercentage()); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Duration:          %d ms\n", s.TotalDuration.Milliseconds()); err != nil {
+	if _, err := p.Fprintln(w, i18n.Duration, s.TotalDuration.Milliseconds()); err != nil {
+		return err
+	}
+
+	if err := s.printRateLimit(w); err != nil {
+		return err
+	}
+
+	return s.printConnections(w)
+}
+
+// printRateLimit shows per-host throttling when the rate limiter delayed any
+// request, so a slow run can be attributed to --rate-limit rather than the server.
+func (s *Summary) printRateLimit(w io.Writer) error {
+	waited := s.RateLimitWait()
+	if waited <= 0 {
+		return nil
+	}
+
+	share := 0.0
+	if s.TotalDuration > 0 {
+		share = float64(waited) / float64(s.TotalDuration) * 100
+	}
+	if _, err := s.Printer.Fprintln(w, i18n.RateLimitWait, waited.Milliseconds(), share); err != nil {
+		return err
+	}
+
+	for _, stat := range s.RateLimit {
+		if _, err := s.Printer.Fprintln(w, i18n.RateLimitHost, stat.Host, stat.Requests, stat.Waited.Milliseconds()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printConnections shows how many connections each host used and the peak
+// held at once, to check --max-conns-per-host against real concurrency.
+func (s *Summary) printConnections(w io.Writer) error {
+	if len(s.Connections) == 0 {
+		return nil
+	}
+
+	peak := 0
+	for _, stat := range s.Connections {
+		peak = max(peak, stat.Peak)
+	}
+	if _, err := s.Printer.Fprintln(w, i18n.PeakConnections, peak); err != nil {
 		return err
 	}
 
+	for _, stat := range s.Connections {
+		if _, err := s.Printer.Fprintln(w, i18n.ConnectionsHost, stat.Host, stat.Opened, stat.Peak); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// printVariableSnapshots lists dump_vars snapshots below their file result.
+func printVariableSnapshots(w io.Writer, p *i18n.Printer, snapshots []VariableSnapshot) error {
+	for _, snapshot := range snapshots {
+		if _, err := p.Fprintln(w, i18n.VariablesBefore, snapshot.Step); err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(snapshot.Values))
+		for name := range snapshot.Values {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			if _, err := fmt.Fprintf(w, "    %s = %v\n", name, snapshot.Values[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// printAttempts shows the attempt timeline of each retried step below its
+// file result.
+func printAttempts(w io.Writer, p *i18n.Printer, steps []StepResult) error {
+	for _, step := range steps {
+		if !step.Retried() {
+			continue
+		}
+
+		if _, err := p.Fprintln(w, i18n.StepAttempts, step.Name, len(step.Attempts)); err != nil {
+			return err
+		}
+		for _, attempt := range step.Attempts {
+			line := p.Sprintf(i18n.Attempt, attempt.Number, attemptStatus(p, attempt.Status), attempt.Duration.Milliseconds())
+			if attempt.Error != nil {
+				line += ": " + attempt.Error.Error()
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// printArtifacts lists the files produced for failed steps, such as
+// screenshots, below their file result.
+func printArtifacts(w io.Writer, p *i18n.Printer, steps []StepResult) error {
+	for _, step := range steps {
+		for _, path := range step.Artifacts {
+			if _, err := p.Fprintln(w, i18n.StepArtifact, step.Name, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func attemptStatus(p *i18n.Printer, status int) string {
+	if status == 0 {
+		return p.Text(i18n.NoResponse)
+	}
+	return strconv.Itoa(status)
+}
+
 type jsonFileResult struct {
-	Filename             string `json:"filename"`
-	RequestCount         int    `json:"request_count"`
+	Filename             string                 `json:"filename"`
+	RequestCount         int                    `json:"request_count"`
+	DurationMilliseconds int64                  `json:"duration_ms"`
+	Success              bool                   `json:"success"`
+	Error                string                 `json:"error,omitempty"`
+	Skipped              bool                   `json:"skipped,omitempty"`
+	Variables            []jsonVariableSnapshot `json:"variables,omitempty"`
+	Steps                []jsonStep             `json:"steps,omitempty"`
+	Retries              []jsonRetriedStep      `json:"retries,omitempty"`
+	Artifacts            []jsonArtifact         `json:"artifacts,omitempty"`
+}
+
+type jsonStep struct {
+	Step                 int    `json:"step"`
+	Name                 string `json:"name"`
+	DurationMilliseconds int64  `json:"duration_ms"`
+	Error                string `json:"error,omitempty"`
+	Skipped              string `json:"skipped,omitempty"`
+}
+
+type jsonArtifact struct {
+	Step int    `json:"step"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type jsonRetriedStep struct {
+	Step     int           `json:"step"`
+	Name     string        `json:"name"`
+	Attempts []jsonAttempt `json:"attempts"`
+}
+
+type jsonAttempt struct {
+	Attempt              int    `json:"attempt"`
+	Status               int    `json:"status,omitempty"`
 	DurationMilliseconds int64  `json:"duration_ms"`
-	Success              bool   `json:"success"`
 	Error                string `json:"error,omitempty"`
 }
 
+type jsonVariableSnapshot struct {
+	Step   int            `json:"step"`
+	Values map[string]any `json:"values"`
+}
+
 type jsonSummary struct {
-	FileResults          []jsonFileResult `json:"file_results"`
-	ExecutedFiles        int              `json:"executed_files"`
-	ExecutedRequests     int              `json:"executed_requests"`
-	SucceededFiles       int              `json:"succeeded_files"`
-	FailedFiles          int              `json:"failed_files"`
-	DurationMilliseconds int64            `json:"duration_ms"`
-	RequestsPerSecond    float64          `json:"requests_per_second"`
-	SuccessPercentage    float64          `json:"success_percentage"`
-	FailurePercentage    float64          `json:"failure_percentage"`
+	FileResults          []jsonFileResult  `json:"file_results"`
+	ExecutedFiles        int               `json:"executed_files"`
+	ExecutedRequests     int               `json:"executed_requests"`
+	SucceededFiles       int               `json:"succeeded_files"`
+	FailedFiles          int               `json:"failed_files"`
+	DurationMilliseconds int64             `json:"duration_ms"`
+	RequestsPerSecond    float64           `json:"requests_per_second"`
+	SuccessPercentage    float64           `json:"success_percentage"`
+	FailurePercentage    float64           `json:"failure_percentage"`
+	RateLimit            []jsonRateLimit   `json:"rate_limit,omitempty"`
+	Connections          []jsonConnection  `json:"connections,omitempty"`
+	Meta                 map[string]string `json:"meta,omitempty"`
+	Shard                string            `json:"shard,omitempty"`
+}
+
+type jsonRateLimit struct {
+	Host               string `json:"host"`
+	Requests           int    `json:"requests"`
+	WaitedMilliseconds int64  `json:"waited_ms"`
+}
+
+type jsonConnection struct {
+	Host   string `json:"host"`
+	Opened int    `json:"opened"`
+	Peak   int    `json:"peak"`
 }
 
 func (s *Summary) toJSONSummary() jsonSummary {
@@ -123,10 +337,35 @@ func (s *Summary) toJSONSummary() jsonSummary {
 		}
 		if result.Error != nil {
 			item.Error = result.Error.Error()
+			item.Skipped = IsSkipped(result.Error)
+		}
+		for _, snapshot := range result.Variables {
+			item.Variables = append(item.Variables, jsonVariableSnapshot(snapshot))
+		}
+		item.Steps = toJSONSteps(result.Steps)
+		item.Retries = toJSONRetries(result.Steps)
+		for _, step := range result.Steps {
+			for _, path := range step.Artifacts {
+				item.Artifacts = append(item.Artifacts, jsonArtifact{Step: step.Index, Name: step.Name, Path: path})
+			}
 		}
 		fileResults = append(fileResults, item)
 	}
 
+	var rateLimit []jsonRateLimit
+	for _, stat := range s.RateLimit {
+		rateLimit = append(rateLimit, jsonRateLimit{
+			Host:               stat.Host,
+			Requests:           stat.Requests,
+			WaitedMilliseconds: stat.Waited.Milliseconds(),
+		})
+	}
+
+	var connections []jsonConnection
+	for _, stat := range s.Connections {
+		connections = append(connections, jsonConnection(stat))
+	}
+
 	return jsonSummary{
 		FileResults:          fileResults,
 		ExecutedFiles:        s.ExecutedFiles,
@@ -137,9 +376,55 @@ func (s *Summary) toJSONSummary() jsonSummary {
 		RequestsPerSecond:    s.RequestsPerSecond(),
 		SuccessPercentage:    s.SuccessPercentage(),
 		FailurePercentage:    s.FailurePercentage(),
+		RateLimit:            rateLimit,
+		Connections:          connections,
+		Meta:                 s.Meta,
+		Shard:                s.Shard,
 	}
 }
 
+func toJSONSteps(steps []StepResult) []jsonStep {
+	var out []jsonStep
+	for _, step := range steps {
+		item := jsonStep{
+			Step:                 step.Index,
+			Name:                 step.Name,
+			DurationMilliseconds: step.Duration.Milliseconds(),
+			Skipped:              step.Skipped,
+		}
+		if step.Error != nil {
+			item.Error = step.Error.Error()
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func toJSONRetries(steps []StepResult) []jsonRetriedStep {
+	var retries []jsonRetriedStep
+	for _, step := range steps {
+		if !step.Retried() {
+			continue
+		}
+
+		retried := jsonRetriedStep{Step: step.Index, Name: step.Name}
+		for _, attempt := range step.Attempts {
+			item := jsonAttempt{
+				Attempt:              attempt.Number,
+				Status:               attempt.Status,
+				DurationMilliseconds: attempt.Duration.Milliseconds(),
+			}
+			if attempt.Error != nil {
+				item.Error = attempt.Error.Error()
+			}
+			retried.Attempts = append(retried.Attempts, item)
+		}
+		retries = append(retries, retried)
+	}
+
+	return retries
+}
+
 func (s *Summary) formatJSON(w io.Writer) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
@@ -156,29 +441,40 @@ func formatAggregatedText(w io.Writer, allResults []*Summary) error {
 		return allResults[0].formatText(w)
 	}
 
+	// Every iteration of a run shares the printer of the runner.
+	p := allResults[0].Printer
 	stats := CalculateAggregatedStats(allResults)
 
-	if err := printIterationSummary(w, allResults); err != nil {
+	if err := printIterationSummary(w, p, allResults); err != nil {
 		return err
 	}
 
-	return printAggregatedSummary(w, stats)
+	return printAggregatedSummary(w, p, stats)
 }
 
 type jsonAggregatedStats struct {
-	TotalIterations           int     `json:"total_iterations"`
-	SuccessfulIterations      int     `json:"successful_iterations"`
-	FailedIterations          int     `json:"failed_iterations"`
-	IterationSuccessRate      float64 `json:"iteration_success_rate"`
-	TotalExecutedFiles        int     `json:"total_executed_files"`
-	TotalExecutedRequests     int     `json:"total_executed_requests"`
-	TotalSucceededFiles       int     `json:"total_succeeded_files"`
-	TotalFailedFiles          int     `json:"total_failed_files"`
-	TotalDurationMilliseconds int64   `json:"total_duration_ms"`
-	OverallRequestsPerSecond  float64 `json:"overall_requests_per_second"`
-	AvgFilesPerIteration      float64 `json:"avg_files_per_iteration"`
-	AvgRequestsPerIteration   float64 `json:"avg_requests_per_iteration"`
-	AvgDurationMilliseconds   int64   `json:"avg_duration_ms"`
+	TotalIterations           int             `json:"total_iterations"`
+	SuccessfulIterations      int             `json:"successful_iterations"`
+	FailedIterations          int             `json:"failed_iterations"`
+	IterationSuccessRate      float64         `json:"iteration_success_rate"`
+	TotalExecutedFiles        int             `json:"total_executed_files"`
+	TotalExecutedRequests     int             `json:"total_executed_requests"`
+	TotalSucceededFiles       int             `json:"total_succeeded_files"`
+	TotalFailedFiles          int             `json:"total_failed_files"`
+	TotalDurationMilliseconds int64           `json:"total_duration_ms"`
+	OverallRequestsPerSecond  float64         `json:"overall_requests_per_second"`
+	AvgFilesPerIteration      float64         `json:"avg_files_per_iteration"`
+	AvgRequestsPerIteration   float64         `json:"avg_requests_per_iteration"`
+	AvgDurationMilliseconds   int64           `json:"avg_duration_ms"`
+	FlakySteps                []jsonFlakyStep `json:"flaky_steps,omitempty"`
+}
+
+type jsonFlakyStep struct {
+	File        string  `json:"file"`
+	Step        int     `json:"step"`
+	Failures    int     `json:"failures"`
+	Runs        int     `json:"runs"`
+	FailureRate float64 `json:"failure_rate"`
 }
 
 type jsonAggregatedResults struct {
@@ -217,6 +513,7 @@ func formatAggregatedJSON(w io.Writer, allResults []*Summary) error {
 			AvgFilesPerIteration:      stats.AvgFilesPerIteration(),
 			AvgRequestsPerIteration:   stats.AvgRequestsPerIteration(),
 			AvgDurationMilliseconds:   stats.AvgDurationPerIteration().Milliseconds(),
+			FlakySteps:                toJSONFlakySteps(stats.FlakySteps),
 		},
 	}
 
@@ -225,12 +522,31 @@ func formatAggregatedJSON(w io.Writer, allResults []*Summary) error {
 	return encoder.Encode(payload)
 }
 
+func toJSONFlakySteps(steps []FlakyStep) []jsonFlakyStep {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	out := make([]jsonFlakyStep, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, jsonFlakyStep{
+			File:        step.Filename,
+			Step:        step.Step,
+			Failures:    step.Failures,
+			Runs:        step.Runs,
+			FailureRate: step.FailureRate(),
+		})
+	}
+
+	return out
+}
+
 // printIterationSummary prints per-iteration output.
-func printIterationSummary(w io.Writer, allResults []*Summary) error {
+func printIterationSummary(w io.Writer, p *i18n.Printer, allResults []*Summary) error {
 	if _, err := fmt.Fprintln(w, "================================================================================"); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintln(w, "ITERATION RESULTS:"); err != nil {
+	if _, err := p.Fprintln(w, i18n.IterationResults); err != nil {
 		return err
 	}
 	if _, err := fmt.Fprintln(w, "================================================================================"); err != nil {
@@ -238,12 +554,12 @@ func printIterationSummary(w io.Writer, allResults []*Summary) error {
 	}
 
 	for i, results := range allResults {
-		status := "SUCCESS"
+		status := p.Text(i18n.IterationSuccess)
 		if results.FailedFiles > 0 {
-			status = "FAILED"
+			status = p.Text(i18n.IterationFailed)
 		}
 
-		_, err := fmt.Fprintf(w, "Iteration %d: %s (%d files, %d requests, %d ms)\n",
+		_, err := p.Fprintln(w, i18n.IterationResult,
 			i+1, status, results.ExecutedFiles, results.ExecutedRequests,
 			results.TotalDuration.Milliseconds())
 		if err != nil {
@@ -255,11 +571,11 @@ func printIterationSummary(w io.Writer, allResults []*Summary) error {
 }
 
 // printAggregatedSummary prints overall statistics and averages.
-func printAggregatedSummary(w io.Writer, stats AggregatedStats) error {
+func printAggregatedSummary(w io.Writer, p *i18n.Printer, stats AggregatedStats) error {
 	if _, err := fmt.Fprintln(w, "================================================================================"); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintln(w, "AGGREGATED RESULTS:"); err != nil {
+	if _, err := p.Fprintln(w, i18n.AggregatedResults); err != nil {
 		return err
 	}
 	if _, err := fmt.Fprintln(w, "================================================================================"); err != nil {
@@ -269,52 +585,78 @@ func printAggregatedSummary(w io.Writer, stats AggregatedStats) error {
 	successRate := stats.IterationSuccessRate()
 	failureRate := 100 - successRate
 
-	if _, err := fmt.Fprintf(w, "Total iterations:    %d\n", stats.IterationCount); err != nil {
+	if _, err := p.Fprintln(w, i18n.TotalIterations, stats.IterationCount); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Successful iterations: %d (%.1f%%)\n", stats.SuccessfulIterations, successRate); err != nil {
+	if _, err := p.Fprintln(w, i18n.SuccessfulIters, stats.SuccessfulIterations, successRate); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Failed iterations:   %d (%.1f%%)\n", stats.FailedIterations(), failureRate); err != nil {
+	if _, err := p.Fprintln(w, i18n.FailedIters, stats.FailedIterations(), failureRate); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Total executed files: %d\n", stats.TotalExecutedFiles); err != nil {
+	if _, err := p.Fprintln(w, i18n.TotalExecutedFiles, stats.TotalExecutedFiles); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Total executed requests: %d (%.2f/s)\n", stats.TotalExecutedRequests, stats.OverallRequestsPerSecond()); err != nil {
+	if _, err := p.Fprintln(w, i18n.TotalExecutedReqs, stats.TotalExecutedRequests, stats.OverallRequestsPerSecond()); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Total succeeded files: %d\n", stats.TotalSucceededFiles); err != nil {
+	if _, err := p.Fprintln(w, i18n.TotalSucceededFiles, stats.TotalSucceededFiles); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Total failed files:  %d\n", stats.TotalFailedFiles); err != nil {
+	if _, err := p.Fprintln(w, i18n.TotalFailedFiles, stats.TotalFailedFiles); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Total duration:      %d ms\n", stats.TotalDuration.Milliseconds()); err != nil {
+	if _, err := p.Fprintln(w, i18n.TotalDuration, stats.TotalDuration.Milliseconds()); err != nil {
 		return err
 	}
 
 	if _, err := fmt.Fprintln(w, "--------------------------------------------------------------------------------"); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Avg files per iteration: %.1f\n", stats.AvgFilesPerIteration()); err != nil {
+	if _, err := p.Fprintln(w, i18n.AvgFiles, stats.AvgFilesPerIteration()); err != nil {
+		return err
+	}
+	if _, err := p.Fprintln(w, i18n.AvgRequests, stats.AvgRequestsPerIteration()); err != nil {
+		return err
+	}
+	if _, err := p.Fprintln(w, i18n.AvgDuration, stats.AvgDurationPerIteration().Milliseconds()); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Avg requests per iteration: %.1f\n", stats.AvgRequestsPerIteration()); err != nil {
+
+	return printFlakySteps(w, p, stats.FlakySteps)
+}
+
+// printFlakySteps lists steps that failed intermittently across iterations.
+func printFlakySteps(w io.Writer, p *i18n.Printer, steps []FlakyStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintln(w, "--------------------------------------------------------------------------------"); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "Avg duration per iteration: %d ms\n", stats.AvgDurationPerIteration().Milliseconds()); err != nil {
+	if _, err := p.Fprintln(w, i18n.FlakySteps); err != nil {
 		return err
 	}
+	for _, step := range steps {
+		_, err := p.Fprintln(w, i18n.FlakyStep,
+			step.Filename, step.Step, step.Failures, step.Runs, step.FailureRate())
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
 // formatDebugText outputs debug information in text format.
-func formatDebugText(w io.Writer, description string, data []byte) error {
+func formatDebugText(w io.Writer, description string, data []byte, timing *DebugTiming) error {
 	if _, err := fmt.Fprintln(w, "========================================"); err != nil {
 		return err
 	}
+	if timing != nil && timing.String() != "" {
+		description += " (" + timing.String() + ")"
+	}
 	if _, err := fmt.Fprintf(w, "%s:\n", description); err != nil {
 		return err
 	}
@@ -336,12 +678,24 @@ func formatDebugText(w io.Writer, description string, data []byte) error {
 type debugOutput struct {
 	Description string `json:"description"`
 	Data        string `json:"data"`
+	DurationMS  *int64 `json:"duration_ms,omitempty"`
+	TTFBMS      *int64 `json:"ttfb_ms,omitempty"`
 }
 
-func formatDebugJSON(w io.Writer, description string, data []byte) error {
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(debugOutput{
+func formatDebugJSON(w io.Writer, description string, data []byte, timing *DebugTiming) error {
+	entry := debugOutput{
 		Description: description,
 		Data:        string(data),
-	})
+	}
+	if timing != nil && timing.Duration > 0 {
+		duration := timing.Duration.Milliseconds()
+		entry.DurationMS = &duration
+	}
+	if timing != nil && timing.TTFB > 0 {
+		ttfb := timing.TTFB.Milliseconds()
+		entry.TTFBMS = &ttfb
+	}
+
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(entry)
 }
@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
 )
 
 func TestSummaryFormatJSON(t *testing.T) {
@@ -19,6 +23,8 @@ func TestSummaryFormatJSON(t *testing.T) {
 		Error:        errors.New("boom"),
 	})
 	summary.SetTotalDuration(2 * time.Second)
+	summary.Meta = map[string]string{"env": "staging"}
+	summary.Shard = "2/5"
 
 	var out bytes.Buffer
 	if err := summary.Format(FormatJSON, &out); err != nil {
@@ -36,6 +42,12 @@ func TestSummaryFormatJSON(t *testing.T) {
 	if payload["failed_files"] != float64(1) {
 		t.Fatalf("failed_files = %v, want 1", payload["failed_files"])
 	}
+	if meta, _ := payload["meta"].(map[string]any); meta["env"] != "staging" {
+		t.Fatalf("meta = %v, want env=staging", payload["meta"])
+	}
+	if payload["shard"] != "2/5" {
+		t.Fatalf("shard = %v, want 2/5", payload["shard"])
+	}
 }
 
 func TestFormatAggregatedJSON(t *testing.T) {
@@ -71,7 +83,7 @@ func TestFormatDebugJSON(t *testing.T) {
 	t.Parallel()
 
 	var out bytes.Buffer
-	if err := FormatDebug(FormatJSON, &out, "REQUEST", []byte("GET / HTTP/1.1")); err != nil {
+	if err := FormatDebug(FormatJSON, &out, "REQUEST", []byte("GET / HTTP/1.1"), nil); err != nil {
 		t.Fatalf("FormatDebug() error = %v", err)
 	}
 
@@ -84,3 +96,272 @@ func TestFormatDebugJSON(t *testing.T) {
 		t.Fatalf("description = %v, want REQUEST", payload["description"])
 	}
 }
+
+func TestFormatDebugTiming(t *testing.T) {
+	t.Parallel()
+
+	timing := &DebugTiming{Duration: 125 * time.Millisecond, TTFB: 40 * time.Millisecond}
+
+	var text bytes.Buffer
+	if err := FormatDebug(FormatText, &text, "RESPONSE", []byte("HTTP/1.1 200 OK"), timing); err != nil {
+		t.Fatalf("FormatDebug() error = %v", err)
+	}
+	if !strings.Contains(text.String(), "RESPONSE (125 ms, ttfb 40 ms):\n") {
+		t.Errorf("text debug output = %q, want the timing after the description", text.String())
+	}
+
+	var out bytes.Buffer
+	if err := FormatDebug(FormatJSON, &out, "RESPONSE", []byte("HTTP/1.1 200 OK"), timing); err != nil {
+		t.Fatalf("FormatDebug() error = %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("debug result is not valid JSON: %v", err)
+	}
+	if payload["description"] != "RESPONSE" || payload["duration_ms"] != float64(125) || payload["ttfb_ms"] != float64(40) {
+		t.Errorf("JSON debug output = %v, want description RESPONSE, duration_ms 125 and ttfb_ms 40", payload)
+	}
+}
+
+func TestSummaryFormatTextVariableSnapshots(t *testing.T) {
+	t.Parallel()
+
+	summary := NewSummary(1)
+	summary.Add(FileResult{
+		Filename:     "test.yaml",
+		RequestCount: 1,
+		Variables: []VariableSnapshot{
+			{Step: 2, Values: map[string]any{"user_id": 42, "env": "staging"}},
+		},
+	})
+
+	var out bytes.Buffer
+	if err := summary.Format(FormatText, &out); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	want := "  variables before step 2:\n    env = staging\n    user_id = 42\n"
+	if !strings.Contains(out.String(), want) {
+		t.Fatalf("text output missing snapshot %q:\n%s", want, out.String())
+	}
+}
+
+func TestSummaryFormatTextAttempts(t *testing.T) {
+	t.Parallel()
+
+	summary := NewSummary(1)
+	summary.Add(FileResult{
+		Filename:     "test.yaml",
+		RequestCount: 1,
+		Steps: []StepResult{
+			{Index: 0, Name: "step 0 GET /ok", Attempts: []Attempt{{Number: 1, Status: 200}}},
+			{Index: 1, Name: "step 1 GET /flaky", Attempts: []Attempt{
+				{Number: 1, Duration: 30 * time.Millisecond, Error: errors.New("request failed: EOF")},
+				{Number: 2, Status: 503, Duration: 12 * time.Millisecond, Error: errors.New("assertion failed: status")},
+				{Number: 3, Status: 200, Duration: 9 * time.Millisecond},
+			}},
+		},
+	})
+
+	var out bytes.Buffer
+	if err := summary.Format(FormatText, &out); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	want := "  step 1 GET /flaky: 3 attempts\n" +
+		"    #1 no response in 30 ms: request failed: EOF\n" +
+		"    #2 503 in 12 ms: assertion failed: status\n" +
+		"    #3 200 in 9 ms\n"
+	if !strings.Contains(out.String(), want) {
+		t.Fatalf("text output missing attempts %q:\n%s", want, out.String())
+	}
+	if strings.Contains(out.String(), "/ok") {
+		t.Fatalf("text output lists a step without retries:\n%s", out.String())
+	}
+}
+
+func TestSummaryFormatArtifacts(t *testing.T) {
+	t.Parallel()
+
+	summary := NewSummary(1)
+	summary.Add(FileResult{
+		Filename: "pages.yaml",
+		Error:    &StepError{Step: 1, Err: errors.New("assertion failed")},
+		Steps: []StepResult{
+			{Index: 0, Name: "step 0 GET /"},
+			{Index: 1, Name: "step 1 GET /cart", Error: errors.New("assertion failed"), Artifacts: []string{"rq-artifacts/pages.yaml-step1.png"}},
+		},
+	})
+
+	var text bytes.Buffer
+	if err := summary.Format(FormatText, &text); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if want := "  step 1 GET /cart: artifact rq-artifacts/pages.yaml-step1.png\n"; !strings.Contains(text.String(), want) {
+		t.Errorf("text output missing %q:\n%s", want, text.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := summary.Format(FormatJSON, &jsonOut); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	var decoded jsonSummary
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := []jsonArtifact{{Step: 1, Name: "step 1 GET /cart", Path: "rq-artifacts/pages.yaml-step1.png"}}
+	if got := decoded.FileResults[0].Artifacts; !reflect.DeepEqual(got, want) {
+		t.Errorf("json artifacts = %+v, want %+v", got, want)
+	}
+}
+
+func TestSummaryFormatRateLimit(t *testing.T) {
+	t.Parallel()
+
+	summary := NewSummary(1)
+	summary.Add(FileResult{Filename: "test.yaml", RequestCount: 4})
+	summary.SetTotalDuration(1000 * time.Millisecond)
+	summary.RateLimit = []RateLimitStat{
+		{Host: "a.example.com", Requests: 3, Waited: 600 * time.Millisecond},
+		{Host: "b.example.com", Requests: 1, Waited: 150 * time.Millisecond},
+	}
+
+	var text bytes.Buffer
+	if err := summary.Format(FormatText, &text); err != nil {
+		t.Fatalf("Format(text) error = %v", err)
+	}
+	want := "Rate limit wait:   750 ms (75.0% of duration)\n  a.example.com: 3 request(s), waited 600 ms\n  b.example.com: 1 request(s), waited 150 ms\n"
+	if !strings.Contains(text.String(), want) {
+		t.Fatalf("text output missing rate limit section %q:\n%s", want, text.String())
+	}
+
+	var payload bytes.Buffer
+	if err := summary.Format(FormatJSON, &payload); err != nil {
+		t.Fatalf("Format(json) error = %v", err)
+	}
+	var decoded struct {
+		RateLimit []jsonRateLimit `json:"rate_limit"`
+	}
+	if err := json.Unmarshal(payload.Bytes(), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	wantJSON := []jsonRateLimit{
+		{Host: "a.example.com", Requests: 3, WaitedMilliseconds: 600},
+		{Host: "b.example.com", Requests: 1, WaitedMilliseconds: 150},
+	}
+	if !reflect.DeepEqual(decoded.RateLimit, wantJSON) {
+		t.Fatalf("rate_limit = %+v, want %+v", decoded.RateLimit, wantJSON)
+	}
+}
+
+func TestSummaryFormatConnections(t *testing.T) {
+	t.Parallel()
+
+	summary := NewSummary(1)
+	summary.Add(FileResult{Filename: "test.yaml", RequestCount: 8})
+	summary.Connections = []ConnectionStat{
+		{Host: "a.example.com:443", Opened: 6, Peak: 4},
+		{Host: "b.example.com:443", Opened: 1, Peak: 1},
+	}
+
+	var text bytes.Buffer
+	if err := summary.Format(FormatText, &text); err != nil {
+		t.Fatalf("Format(text) error = %v", err)
+	}
+	want := "Peak connections:  4 per host\n  a.example.com:443: 6 opened, peak 4\n  b.example.com:443: 1 opened, peak 1\n"
+	if !strings.Contains(text.String(), want) {
+		t.Fatalf("text output missing connections section %q:\n%s", want, text.String())
+	}
+
+	var payload bytes.Buffer
+	if err := summary.Format(FormatJSON, &payload); err != nil {
+		t.Fatalf("Format(json) error = %v", err)
+	}
+	var decoded struct {
+		Connections []jsonConnection `json:"connections"`
+	}
+	if err := json.Unmarshal(payload.Bytes(), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	wantJSON := []jsonConnection{
+		{Host: "a.example.com:443", Opened: 6, Peak: 4},
+		{Host: "b.example.com:443", Opened: 1, Peak: 1},
+	}
+	if !reflect.DeepEqual(decoded.Connections, wantJSON) {
+		t.Fatalf("connections = %+v, want %+v", decoded.Connections, wantJSON)
+	}
+}
+
+func TestSummaryFormatLocalized(t *testing.T) {
+	t.Parallel()
+
+	i18n.Register("x-output", i18n.Catalog{
+		i18n.FileSuccess:       "Sucesso",
+		i18n.ExecutedFiles:     "Arquivos executados: %d",
+		i18n.IterationResults:  "RESULTADOS POR ITERAÇÃO:",
+		i18n.AggregatedResults: "RESULTADOS AGREGADOS:",
+	})
+	printer := i18n.NewPrinter("x-output")
+
+	summary := NewSummary(1)
+	summary.Add(FileResult{Filename: "test.yaml", RequestCount: 1})
+	summary.Printer = printer
+
+	var text bytes.Buffer
+	if err := summary.Format(FormatText, &text); err != nil {
+		t.Fatalf("Format(text) error = %v", err)
+	}
+	for _, want := range []string{
+		"test.yaml: Sucesso (1 request(s) in 0 ms)\n",
+		"Arquivos executados: 1\n",
+		"Executed requests: 1", // untranslated messages fall back to English
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text output missing %q:\n%s", want, text.String())
+		}
+	}
+
+	var aggregated bytes.Buffer
+	if err := FormatAggregated(FormatText, &aggregated, []*Summary{summary, summary}); err != nil {
+		t.Fatalf("FormatAggregated(text) error = %v", err)
+	}
+	for _, want := range []string{"RESULTADOS POR ITERAÇÃO:\n", "RESULTADOS AGREGADOS:\n"} {
+		if !strings.Contains(aggregated.String(), want) {
+			t.Errorf("aggregated output missing %q:\n%s", want, aggregated.String())
+		}
+	}
+}
+
+func TestSummaryFormatSkipped(t *testing.T) {
+	t.Parallel()
+
+	summary := NewSummary(1)
+	summary.Add(FileResult{
+		Filename: "down.yaml",
+		Error:    &StepError{Step: 1, Err: &SkippedError{Reason: "circuit breaker open for api.example.com"}},
+	})
+
+	var text bytes.Buffer
+	if err := summary.Format(FormatText, &text); err != nil {
+		t.Fatalf("Format(text) error = %v", err)
+	}
+	want := "down.yaml: Skipped: step 1: circuit breaker open for api.example.com (0 request(s) in 0 ms)\n"
+	if !strings.Contains(text.String(), want) {
+		t.Fatalf("text output missing %q:\n%s", want, text.String())
+	}
+	if summary.FailedFiles != 1 {
+		t.Fatalf("FailedFiles = %d, want 1", summary.FailedFiles)
+	}
+
+	var payload bytes.Buffer
+	if err := summary.Format(FormatJSON, &payload); err != nil {
+		t.Fatalf("Format(json) error = %v", err)
+	}
+	var decoded jsonSummary
+	if err := json.Unmarshal(payload.Bytes(), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got := decoded.FileResults[0]; !got.Skipped || got.Success {
+		t.Fatalf("file result = %+v, want skipped failure", got)
+	}
+}
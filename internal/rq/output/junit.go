@@ -0,0 +1,164 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Cases      []junitTestCase  `xml:"testcase"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes summaries as a JUnit XML report with one test suite per
+// file and one test case per step. When there are several summaries, as with
+// --repeat, suite names carry the iteration number.
+func WriteJUnit(w io.Writer, summaries []*Summary) error {
+	report := junitTestSuites{Name: "rq"}
+
+	var total time.Duration
+	for iteration, summary := range summaries {
+		total += summary.TotalDuration
+		for _, file := range summary.FileResults {
+			name := file.Filename
+			if len(summaries) > 1 {
+				name = fmt.Sprintf("%s (iteration %d)", file.Filename, iteration+1)
+			}
+
+			suite := junitSuite(name, file)
+			suite.Properties = junitMeta(summary.Meta)
+			report.Tests += suite.Tests
+			report.Failures += suite.Failures
+			report.Skipped += suite.Skipped
+			report.Suites = append(report.Suites, suite)
+		}
+	}
+	report.Time = junitSeconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitSuite(name string, file FileResult) junitTestSuite {
+	suite := junitTestSuite{Name: name, Time: junitSeconds(file.Duration)}
+
+	// A file that failed before any step ran, such as on a parse error, is
+	// reported as a single failing case so the failure is not lost.
+	if len(file.Steps) == 0 && file.Error != nil {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      file.Filename,
+			ClassName: file.Filename,
+			Time:      junitSeconds(file.Duration),
+			Failure:   &junitMessage{Message: file.Error.Error(), Text: file.Error.Error()},
+		})
+	}
+
+	for _, step := range file.Steps {
+		testCase := junitTestCase{
+			Name:      step.Name,
+			ClassName: file.Filename,
+			Time:      junitSeconds(step.Duration),
+		}
+		switch {
+		case step.Error != nil:
+			testCase.Failure = &junitMessage{Message: step.Error.Error(), Text: step.Error.Error()}
+		case step.Skipped != "":
+			testCase.Skipped = &junitMessage{Message: step.Skipped}
+		}
+		testCase.SystemOut = junitAttachments(step.Artifacts)
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	for _, testCase := range suite.Cases {
+		suite.Tests++
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+		if testCase.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+
+	return suite
+}
+
+// junitAttachments lists artifacts in the [[ATTACHMENT|path]] form that CI
+// servers such as Jenkins link from the test case.
+func junitAttachments(artifacts []string) string {
+	var out strings.Builder
+	for _, path := range artifacts {
+		fmt.Fprintf(&out, "[[ATTACHMENT|%s]]\n", path)
+	}
+	return out.String()
+}
+
+// junitMeta renders --meta values as suite properties, sorted by name.
+func junitMeta(meta map[string]string) *junitProperties {
+	if len(meta) == 0 {
+		return nil
+	}
+
+	properties := &junitProperties{}
+	for _, name := range slices.Sorted(maps.Keys(meta)) {
+		properties.Properties = append(properties.Properties, junitProperty{Name: name, Value: meta[name]})
+	}
+
+	return properties
+}
+
+func junitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
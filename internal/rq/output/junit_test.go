@@ -0,0 +1,100 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	summary := NewSummary(2)
+	summary.Add(FileResult{
+		Filename: "users.yaml",
+		Duration: 1500 * time.Millisecond,
+		Error:    &StepError{Step: 1, Err: errors.New("assertion failed: status 500")},
+		Steps: []StepResult{
+			{Name: "step 0 GET /users", Duration: 250 * time.Millisecond},
+			{Name: "step 1 POST /users", Duration: time.Second, Error: errors.New("assertion failed: status 500"), Artifacts: []string{"rq-artifacts/users.yaml-step1.png"}},
+			{Name: "step 2 GET /users/1", Skipped: "not run: step 1 failed"},
+		},
+	})
+	summary.Add(FileResult{
+		Filename: "broken.yaml",
+		Error:    errors.New("failed to parse file broken.yaml: <bad>"),
+	})
+	summary.SetTotalDuration(2 * time.Second)
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, []*Summary{summary}); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="rq" tests="4" failures="2" skipped="1" time="2.000">
+  <testsuite name="users.yaml" tests="3" failures="1" skipped="1" time="1.500">
+    <testcase name="step 0 GET /users" classname="users.yaml" time="0.250"></testcase>
+    <testcase name="step 1 POST /users" classname="users.yaml" time="1.000">
+      <failure message="assertion failed: status 500">assertion failed: status 500</failure>
+      <system-out>[[ATTACHMENT|rq-artifacts/users.yaml-step1.png]]&#xA;</system-out>
+    </testcase>
+    <testcase name="step 2 GET /users/1" classname="users.yaml" time="0.000">
+      <skipped message="not run: step 1 failed"></skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="broken.yaml" tests="1" failures="1" skipped="0" time="0.000">
+    <testcase name="broken.yaml" classname="broken.yaml" time="0.000">
+      <failure message="failed to parse file broken.yaml: &lt;bad&gt;">failed to parse file broken.yaml: &lt;bad&gt;</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("WriteJUnit() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteJUnitIterations(t *testing.T) {
+	t.Parallel()
+
+	first := NewSummary(1)
+	first.Add(FileResult{Filename: "a.yaml", Steps: []StepResult{{Name: "step 0 GET /"}}})
+	second := NewSummary(1)
+	second.Add(FileResult{Filename: "a.yaml", Steps: []StepResult{{Name: "step 0 GET /"}}})
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, []*Summary{first, second}); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	for _, want := range []string{`name="a.yaml (iteration 1)"`, `name="a.yaml (iteration 2)"`, `<testsuites name="rq" tests="2"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteJUnitMeta(t *testing.T) {
+	t.Parallel()
+
+	summary := NewSummary(1)
+	summary.Add(FileResult{Filename: "a.yaml", Steps: []StepResult{{Name: "step 0 GET /"}}})
+	summary.Meta = map[string]string{"git_sha": "abc123", "build": "42"}
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, []*Summary{summary}); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	want := `    <properties>
+      <property name="build" value="42"></property>
+      <property name="git_sha" value="abc123"></property>
+    </properties>
+    <testcase name="step 0 GET /"`
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("report missing properties:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,153 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrNotReport is returned by ReadJSON for JSON that is not an rq report.
+var ErrNotReport = errors.New("not an rq JSON report")
+
+// reportError is a file or step error read back from a JSON report, where
+// only its message survives. A skipped file unwraps to a *SkippedError, so
+// IsSkipped still holds.
+type reportError struct {
+	message string
+	skipped *SkippedError
+}
+
+func (e *reportError) Error() string {
+	return e.message
+}
+
+func (e *reportError) Unwrap() error {
+	if e.skipped == nil {
+		return nil
+	}
+	return e.skipped
+}
+
+// ReadJSON reads a report written by --output json: a single run, or the
+// iterations of a --repeat run. It returns one summary per run. Errors keep
+// only their messages, and step results are only present in reports that
+// list steps.
+func ReadJSON(r io.Reader) ([]*Summary, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+
+	if raw, ok := payload["iterations"]; ok {
+		var iterations []jsonSummary
+		if err := json.Unmarshal(raw, &iterations); err != nil {
+			return nil, fmt.Errorf("iterations: %w", err)
+		}
+
+		summaries := make([]*Summary, 0, len(iterations))
+		for _, iteration := range iterations {
+			summaries = append(summaries, iteration.toSummary())
+		}
+		return summaries, nil
+	}
+
+	if _, ok := payload["file_results"]; !ok {
+		return nil, ErrNotReport
+	}
+
+	var single jsonSummary
+	if err := json.Unmarshal(data, &single); err != nil {
+		return nil, err
+	}
+	return []*Summary{single.toSummary()}, nil
+}
+
+func (j jsonSummary) toSummary() *Summary {
+	summary := NewSummary(len(j.FileResults))
+	for _, file := range j.FileResults {
+		summary.Add(file.toFileResult())
+	}
+	summary.SetTotalDuration(time.Duration(j.DurationMilliseconds) * time.Millisecond)
+
+	for _, stat := range j.RateLimit {
+		summary.RateLimit = append(summary.RateLimit, RateLimitStat{
+			Host:     stat.Host,
+			Requests: stat.Requests,
+			Waited:   time.Duration(stat.WaitedMilliseconds) * time.Millisecond,
+		})
+	}
+	for _, stat := range j.Connections {
+		summary.Connections = append(summary.Connections, ConnectionStat(stat))
+	}
+	summary.Meta = j.Meta
+	summary.Shard = j.Shard
+
+	return summary
+}
+
+func (j jsonFileResult) toFileResult() FileResult {
+	result := FileResult{
+		Filename:     j.Filename,
+		RequestCount: j.RequestCount,
+		Duration:     time.Duration(j.DurationMilliseconds) * time.Millisecond,
+	}
+	if !j.Success {
+		err := &reportError{message: j.Error}
+		if j.Skipped {
+			err.skipped = &SkippedError{}
+		}
+		result.Error = err
+	}
+	for _, snapshot := range j.Variables {
+		result.Variables = append(result.Variables, VariableSnapshot(snapshot))
+	}
+
+	for _, step := range j.Steps {
+		current := StepResult{
+			Index:    step.Step,
+			Name:     step.Name,
+			Duration: time.Duration(step.DurationMilliseconds) * time.Millisecond,
+			Skipped:  step.Skipped,
+		}
+		if step.Error != "" {
+			current.Error = &reportError{message: step.Error}
+		}
+		result.Steps = append(result.Steps, current)
+	}
+	steps := make(map[int]*StepResult, len(result.Steps))
+	for i := range result.Steps {
+		steps[result.Steps[i].Index] = &result.Steps[i]
+	}
+
+	for _, retried := range j.Retries {
+		step, ok := steps[retried.Step]
+		if !ok {
+			continue
+		}
+		for _, attempt := range retried.Attempts {
+			current := Attempt{
+				Number:   attempt.Attempt,
+				Status:   attempt.Status,
+				Duration: time.Duration(attempt.DurationMilliseconds) * time.Millisecond,
+			}
+			if attempt.Error != "" {
+				current.Error = &reportError{message: attempt.Error}
+			}
+			step.Attempts = append(step.Attempts, current)
+		}
+	}
+	for _, artifact := range j.Artifacts {
+		if step, ok := steps[artifact.Step]; ok {
+			step.Artifacts = append(step.Artifacts, artifact.Path)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	summary := NewSummary(2)
+	summary.Add(FileResult{
+		Filename:     "orders.yaml",
+		RequestCount: 3,
+		Duration:     1200 * time.Millisecond,
+		Steps: []StepResult{
+			{Index: 0, Name: "create", Duration: 200 * time.Millisecond},
+			{
+				Index:    1,
+				Name:     "read",
+				Duration: time.Second,
+				Attempts: []Attempt{
+					{Number: 1, Status: 503, Duration: 400 * time.Millisecond, Error: errors.New("status 503")},
+					{Number: 2, Status: 200, Duration: 600 * time.Millisecond},
+				},
+				Artifacts: []string{"rq-artifacts/read.png"},
+			},
+		},
+	})
+	summary.Add(FileResult{
+		Filename: "users.yaml",
+		Duration: 10 * time.Millisecond,
+		Error:    &StepError{Step: 0, Err: &SkippedError{Reason: "circuit open"}},
+		Steps:    []StepResult{{Index: 0, Name: "list", Skipped: "circuit open"}},
+	})
+	summary.SetTotalDuration(1300 * time.Millisecond)
+	summary.Connections = []ConnectionStat{{Host: "api.example.com:443", Opened: 2, Peak: 1}}
+	summary.Meta = map[string]string{"env": "staging"}
+	summary.Shard = "1/2"
+
+	var out bytes.Buffer
+	if err := summary.Format(FormatJSON, &out); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	runs, err := ReadJSON(&out)
+	if err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("ReadJSON() returned %d runs, want 1", len(runs))
+	}
+
+	got := runs[0]
+	if got.ExecutedFiles != 2 || got.FailedFiles != 1 || got.ExecutedRequests != 3 || got.TotalDuration != 1300*time.Millisecond {
+		t.Errorf("counts = %+v", got)
+	}
+	if got.Shard != "1/2" || got.Meta["env"] != "staging" || len(got.Connections) != 1 {
+		t.Errorf("shard %q, meta %v, connections %v", got.Shard, got.Meta, got.Connections)
+	}
+
+	read := got.FileResults[0].Steps[1]
+	if read.Name != "read" || len(read.Attempts) != 2 || read.Attempts[0].Error.Error() != "status 503" || read.Artifacts[0] != "rq-artifacts/read.png" {
+		t.Errorf("step = %+v", read)
+	}
+	if !read.Retried() {
+		t.Error("Retried() = false after reading the report")
+	}
+
+	failed := got.FileResults[1]
+	if !IsSkipped(failed.Error) || failed.Error.Error() != "step 0 failed: skipped: circuit open" {
+		t.Errorf("file error = %v, skipped %v", failed.Error, IsSkipped(failed.Error))
+	}
+}
+
+func TestReadJSONIterations(t *testing.T) {
+	t.Parallel()
+
+	first := NewSummary(1)
+	first.Add(FileResult{Filename: "a.yaml", RequestCount: 1})
+	second := NewSummary(1)
+	second.Add(FileResult{Filename: "a.yaml", RequestCount: 1, Error: errors.New("boom")})
+
+	var out bytes.Buffer
+	if err := FormatAggregated(FormatJSON, &out, []*Summary{first, second}); err != nil {
+		t.Fatalf("FormatAggregated() error = %v", err)
+	}
+
+	runs, err := ReadJSON(&out)
+	if err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if len(runs) != 2 || runs[0].FailedFiles != 0 || runs[1].FailedFiles != 1 {
+		t.Fatalf("ReadJSON() = %d runs", len(runs))
+	}
+}
+
+func TestReadJSONNotReport(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ReadJSON(strings.NewReader(`{"openapi":"3.0.0"}`)); !errors.Is(err, ErrNotReport) {
+		t.Fatalf("ReadJSON() error = %v, want %v", err, ErrNotReport)
+	}
+	if _, err := ReadJSON(strings.NewReader(`not json`)); err == nil {
+		t.Fatal("ReadJSON() error = nil for invalid JSON")
+	}
+}
@@ -1,7 +1,11 @@
 package output
 
 import (
+	"errors"
+	"fmt"
 	"time"
+
+	"github.com/jacoelho/rq/internal/rq/i18n"
 )
 
 type FileResult struct {
@@ -9,6 +13,115 @@ type FileResult struct {
 	RequestCount int
 	Duration     time.Duration
 	Error        error
+	Variables    []VariableSnapshot
+	Steps        []StepResult
+	Exports      map[string]any // Captures written by --export-captures
+}
+
+// StepResult is the outcome of one step of a file. Index is the zero-based
+// step index and Skipped holds the reason when the step sent no request.
+// Attempts lists every attempt in order, so retries are visible. Artifacts
+// holds the paths of files produced for a failed step, such as screenshots.
+type StepResult struct {
+	Index     int
+	Name      string
+	Duration  time.Duration
+	Error     error
+	Skipped   string
+	Attempts  []Attempt
+	Artifacts []string
+}
+
+// Attempt is one try of a step. Status is the response status code, 0 when
+// no response arrived, and Error why the attempt failed, such as the failing
+// assert.
+type Attempt struct {
+	Number   int
+	Status   int
+	Duration time.Duration
+	Error    error
+}
+
+// Retried reports whether the step needed more than one attempt. Steps
+// with options.accept_matrix list one first attempt per variant, which does
+// not count as a retry.
+func (s StepResult) Retried() bool {
+	for _, attempt := range s.Attempts {
+		if attempt.Number > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// VariableSnapshot records the variables visible to a step's templates,
+// requested with dump_vars. Secret and redacted values are already masked.
+type VariableSnapshot struct {
+	Step   int
+	Values map[string]any
+}
+
+// StepError identifies the step that made a file fail. Step is the zero-based
+// step index, matching the "step N failed" messages.
+type StepError struct {
+	Step int
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %d failed: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// SkippedError reports a step that was not sent, leaving the rest of the file
+// unexecuted. The file still counts as failed.
+type SkippedError struct {
+	Reason string
+}
+
+func (e *SkippedError) Error() string {
+	return "skipped: " + e.Reason
+}
+
+// IsSkipped reports whether err stopped a file because a step was skipped.
+func IsSkipped(err error) bool {
+	var skipped *SkippedError
+	return errors.As(err, &skipped)
+}
+
+// skipReason describes a skipped step for the text summary, including the
+// step index when known.
+func skipReason(err error) (string, bool) {
+	var skipped *SkippedError
+	if !errors.As(err, &skipped) {
+		return "", false
+	}
+
+	var stepErr *StepError
+	if errors.As(err, &stepErr) {
+		return fmt.Sprintf("step %d: %s", stepErr.Step, skipped.Reason), true
+	}
+
+	return skipped.Reason, true
+}
+
+// RateLimitStat is the time requests to one host spent waiting for the
+// client-side rate limiter.
+type RateLimitStat struct {
+	Host     string
+	Requests int
+	Waited   time.Duration
+}
+
+// ConnectionStat is the connection usage to one host address: how many
+// connections were opened and the most held open at once.
+type ConnectionStat struct {
+	Host   string
+	Opened int
+	Peak   int
 }
 
 type Summary struct {
@@ -18,6 +131,11 @@ type Summary struct {
 	SucceededFiles   int
 	FailedFiles      int
 	TotalDuration    time.Duration
+	RateLimit        []RateLimitStat
+	Connections      []ConnectionStat
+	Meta             map[string]string // --meta values of the run
+	Shard            string            // --shard of the run, such as 2/5, empty when unsharded
+	Printer          *i18n.Printer     // Language of the text format (nil = English)
 }
 
 func NewSummary(expectedFiles int) *Summary {
@@ -42,6 +160,15 @@ func (s *Summary) SetTotalDuration(duration time.Duration) {
 	s.TotalDuration = duration
 }
 
+// RateLimitWait returns the total time spent waiting for the rate limiter.
+func (s *Summary) RateLimitWait() time.Duration {
+	var total time.Duration
+	for _, stat := range s.RateLimit {
+		total += stat.Waited
+	}
+	return total
+}
+
 func (s *Summary) RequestsPerSecond() float64 {
 	if s.TotalDuration == 0 {
 		return 0
@@ -71,6 +198,25 @@ type AggregatedStats struct {
 	TotalDuration         time.Duration
 	SuccessfulIterations  int
 	IterationCount        int
+	FlakySteps            []FlakyStep
+}
+
+// FlakyStep is a step that failed in some, but not all, of the iterations
+// that reached it.
+type FlakyStep struct {
+	Filename string
+	Step     int
+	Failures int
+	Runs     int
+}
+
+// FailureRate returns the percentage of runs in which the step failed.
+func (f FlakyStep) FailureRate() float64 {
+	if f.Runs == 0 {
+		return 0
+	}
+
+	return float64(f.Failures) / float64(f.Runs) * 100
 }
 
 func CalculateAggregatedStats(allResults []*Summary) AggregatedStats {
@@ -89,9 +235,77 @@ func CalculateAggregatedStats(allResults []*Summary) AggregatedStats {
 		}
 	}
 
+	stats.FlakySteps = DetectFlakySteps(allResults)
+
 	return stats
 }
 
+// DetectFlakySteps classifies steps that failed intermittently across
+// iterations. A step only counts as run when every earlier step of its file
+// passed, so failures of an earlier step do not dilute its failure rate.
+// Files that failed before any step ran (e.g. parse errors) are ignored.
+func DetectFlakySteps(allResults []*Summary) []FlakyStep {
+	const passed = -1
+
+	var order []string
+	outcomes := make(map[string][]int)
+
+	for _, results := range allResults {
+		for _, result := range results.FileResults {
+			outcome := passed
+			if result.Error != nil {
+				var stepErr *StepError
+				if !errors.As(result.Error, &stepErr) {
+					continue
+				}
+				outcome = stepErr.Step
+			}
+
+			if _, seen := outcomes[result.Filename]; !seen {
+				order = append(order, result.Filename)
+			}
+			outcomes[result.Filename] = append(outcomes[result.Filename], outcome)
+		}
+	}
+
+	var flaky []FlakyStep
+	for _, filename := range order {
+		failures := make(map[int]int)
+		maxStep := passed
+		for _, outcome := range outcomes[filename] {
+			if outcome != passed {
+				failures[outcome]++
+				maxStep = max(maxStep, outcome)
+			}
+		}
+
+		for step := 0; step <= maxStep; step++ {
+			failed := failures[step]
+			if failed == 0 {
+				continue
+			}
+
+			runs := 0
+			for _, outcome := range outcomes[filename] {
+				if outcome == passed || outcome >= step {
+					runs++
+				}
+			}
+
+			if failed < runs {
+				flaky = append(flaky, FlakyStep{
+					Filename: filename,
+					Step:     step,
+					Failures: failed,
+					Runs:     runs,
+				})
+			}
+		}
+	}
+
+	return flaky
+}
+
 func (s AggregatedStats) FailedIterations() int {
 	return s.IterationCount - s.SuccessfulIterations
 }
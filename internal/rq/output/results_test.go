@@ -2,6 +2,8 @@ package output
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -185,7 +187,7 @@ func TestCalculateAggregatedStats(t *testing.T) {
 		IterationCount:        3,
 	}
 
-	if stats != expected {
+	if !reflect.DeepEqual(stats, expected) {
 		t.Errorf("CalculateAggregatedStats() = %+v, want %+v", stats, expected)
 	}
 }
@@ -330,3 +332,91 @@ func TestSummary_Add(t *testing.T) {
 		t.Error("Second result error = nil, want non-nil error")
 	}
 }
+
+func TestDetectFlakySteps(t *testing.T) {
+	t.Parallel()
+
+	stepFailure := func(step int) error {
+		return fmt.Errorf("wrapped: %w", &StepError{Step: step, Err: errors.New("boom")})
+	}
+	iteration := func(results ...FileResult) *Summary {
+		summary := NewSummary(len(results))
+		for _, result := range results {
+			summary.Add(result)
+		}
+		return summary
+	}
+
+	tests := []struct {
+		name    string
+		results []*Summary
+		want    []FlakyStep
+	}{
+		{
+			name: "all passing",
+			results: []*Summary{
+				iteration(FileResult{Filename: "a.yaml"}),
+				iteration(FileResult{Filename: "a.yaml"}),
+			},
+		},
+		{
+			name: "consistent failure is not flaky",
+			results: []*Summary{
+				iteration(FileResult{Filename: "a.yaml", Error: stepFailure(1)}),
+				iteration(FileResult{Filename: "a.yaml", Error: stepFailure(1)}),
+			},
+		},
+		{
+			name: "intermittent failure",
+			results: []*Summary{
+				iteration(FileResult{Filename: "a.yaml", Error: stepFailure(0)}, FileResult{Filename: "b.yaml"}),
+				iteration(FileResult{Filename: "a.yaml"}, FileResult{Filename: "b.yaml"}),
+				iteration(FileResult{Filename: "a.yaml"}, FileResult{Filename: "b.yaml", Error: stepFailure(2)}),
+				iteration(FileResult{Filename: "a.yaml"}, FileResult{Filename: "b.yaml"}),
+			},
+			want: []FlakyStep{
+				{Filename: "a.yaml", Step: 0, Failures: 1, Runs: 4},
+				{Filename: "b.yaml", Step: 2, Failures: 1, Runs: 4},
+			},
+		},
+		{
+			name: "earlier failures do not count as runs",
+			results: []*Summary{
+				iteration(FileResult{Filename: "a.yaml", Error: stepFailure(0)}),
+				iteration(FileResult{Filename: "a.yaml", Error: stepFailure(1)}),
+				iteration(FileResult{Filename: "a.yaml"}),
+			},
+			want: []FlakyStep{
+				{Filename: "a.yaml", Step: 0, Failures: 1, Runs: 3},
+				{Filename: "a.yaml", Step: 1, Failures: 1, Runs: 2},
+			},
+		},
+		{
+			name: "errors outside steps are ignored",
+			results: []*Summary{
+				iteration(FileResult{Filename: "a.yaml", Error: errors.New("parse error")}),
+				iteration(FileResult{Filename: "a.yaml"}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := DetectFlakySteps(tt.results)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("DetectFlakySteps() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlakyStep_FailureRate(t *testing.T) {
+	t.Parallel()
+
+	step := FlakyStep{Failures: 1, Runs: 4}
+	if got := step.FailureRate(); got != 25 {
+		t.Fatalf("FailureRate() = %v, want 25", got)
+	}
+}
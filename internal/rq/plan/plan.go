@@ -0,0 +1,335 @@
+// Package plan resolves rq test files into the steps that would run,
+// without sending any request.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"strings"
+	"text/template/parse"
+
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/jacoelho/rq/internal/rq/compile"
+	"github.com/jacoelho/rq/internal/rq/model"
+	"github.com/jacoelho/rq/internal/rq/templating"
+	"github.com/jacoelho/rq/internal/rq/yaml"
+)
+
+// Format selects how a plan is rendered.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// ErrInvalidFormat is returned for unknown plan output formats.
+var ErrInvalidFormat = fmt.Errorf("plan output format must be one of: yaml, json")
+
+// ParseFormat parses a plan output format name.
+func ParseFormat(input string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "yaml", "":
+		return FormatYAML, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatYAML, fmt.Errorf("%w, got: %s", ErrInvalidFormat, input)
+	}
+}
+
+// File is the resolved plan for a single test file.
+type File struct {
+	Filename string
+	Steps    []model.Step
+	// Scopes holds the variables each step was resolved with, including
+	// item and item_index for expanded foreach steps.
+	Scopes []map[string]any
+}
+
+// Build parses and validates each file, then substitutes the given variables
+// into request fields. A foreach step becomes one step per element, with item
+// and item_index substituted. Template actions that depend on captures or
+// template functions are left as written, since their values are only known
+// at run time.
+func Build(files []string, variables map[string]any) ([]File, error) {
+	out := make([]File, 0, len(files))
+	for _, filename := range files {
+		steps, err := parseFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		file := File{Filename: filename}
+		for _, step := range steps {
+			expanded, scopes := expandForeach(step, variables)
+			file.Steps = append(file.Steps, expanded...)
+			file.Scopes = append(file.Scopes, scopes...)
+		}
+
+		out = append(out, file)
+	}
+
+	return out, nil
+}
+
+func parseFile(filename string) ([]model.Step, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+
+	steps, positions, err := yaml.ParseWithPositions(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", filename, err)
+	}
+	if err := compile.ValidateStepsWithPositions(steps, positions); err != nil {
+		return nil, fmt.Errorf("failed to validate file %s: %w", filename, err)
+	}
+
+	return steps, nil
+}
+
+// expandForeach resolves a step once per foreach element. A foreach over a
+// name that is not a variable, such as a capture, is kept as written, since
+// its list is only known at run time.
+func expandForeach(step model.Step, variables map[string]any) ([]model.Step, []map[string]any) {
+	if step.Foreach == nil {
+		return []model.Step{resolveStep(step, variables)}, []map[string]any{variables}
+	}
+
+	items, ok := foreachItems(*step.Foreach, variables)
+	if !ok {
+		return []model.Step{resolveStep(step, variables)}, []map[string]any{variables}
+	}
+
+	single := step
+	single.Foreach = nil
+
+	steps := make([]model.Step, 0, len(items))
+	scopes := make([]map[string]any, 0, len(items))
+	for i, item := range items {
+		scoped := maps.Clone(variables)
+		if scoped == nil {
+			scoped = make(map[string]any, 2)
+		}
+		scoped[model.ForeachItem] = item
+		scoped[model.ForeachIndex] = i
+		steps = append(steps, resolveStep(single, scoped))
+		scopes = append(scopes, scoped)
+	}
+
+	return steps, scopes
+}
+
+func foreachItems(foreach model.Foreach, variables map[string]any) ([]any, bool) {
+	if foreach.From == "" {
+		return foreach.Items, true
+	}
+
+	source, ok := variables[foreach.From]
+	if !ok {
+		return nil, false
+	}
+	if items, ok := source.([]any); ok {
+		return items, true
+	}
+
+	value := reflect.ValueOf(source)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]any, value.Len())
+	for i := range items {
+		items[i] = value.Index(i).Interface()
+	}
+
+	return items, true
+}
+
+func resolveStep(step model.Step, variables map[string]any) model.Step {
+	step.URL = Substitute(step.URL, variables)
+	step.Headers = resolveKeyValues(step.Headers, variables)
+	step.Query = resolveKeyValues(step.Query, variables)
+	step.BodyFile = Substitute(step.BodyFile, variables)
+	step.Multipart = resolveParts(step.Multipart, variables)
+
+	if step.Body.IsStructured() {
+		step.Body = model.Body{Value: resolveValue(step.Body.Value, variables)}
+	} else {
+		step.Body = model.TextBody(Substitute(step.Body.Text, variables))
+	}
+
+	return step
+}
+
+func resolveKeyValues(entries model.KeyValues, variables map[string]any) model.KeyValues {
+	if entries == nil {
+		return nil
+	}
+
+	out := make(model.KeyValues, len(entries))
+	for i, entry := range entries {
+		entry.Value = Substitute(entry.Value, variables)
+		out[i] = entry
+	}
+
+	return out
+}
+
+func resolveParts(parts []model.Part, variables map[string]any) []model.Part {
+	if parts == nil {
+		return nil
+	}
+
+	out := make([]model.Part, len(parts))
+	for i, part := range parts {
+		part.Value = Substitute(part.Value, variables)
+		part.File = Substitute(part.File, variables)
+		part.Filename = Substitute(part.Filename, variables)
+		out[i] = part
+	}
+
+	return out
+}
+
+func resolveValue(value any, variables map[string]any) any {
+	switch v := value.(type) {
+	case string:
+		return Substitute(v, variables)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = resolveValue(item, variables)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = resolveValue(item, variables)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// Substitute replaces {{.name}} actions whose name is a known variable and
+// keeps every other action verbatim. Unparseable templates are returned as is.
+func Substitute(text string, variables map[string]any) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+
+	trees, err := parse.Parse("plan", text, "{{", "}}", templating.FuncMap())
+	if err != nil {
+		return text
+	}
+	tree := trees["plan"]
+	if tree == nil || tree.Root == nil {
+		return text
+	}
+
+	var buf strings.Builder
+	for _, node := range tree.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			buf.Write(n.Text)
+		case *parse.ActionNode:
+			if value, ok := variableValue(n, variables); ok {
+				fmt.Fprint(&buf, value)
+				continue
+			}
+			buf.WriteString(n.String())
+		default:
+			buf.WriteString(n.String())
+		}
+	}
+
+	return buf.String()
+}
+
+func variableValue(action *parse.ActionNode, variables map[string]any) (any, bool) {
+	pipe := action.Pipe
+	if pipe == nil || len(pipe.Decl) != 0 || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return nil, false
+	}
+
+	field, ok := pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok || len(field.Ident) == 0 {
+		return nil, false
+	}
+
+	value, ok := variables[field.Ident[0]]
+	for _, key := range field.Ident[1:] {
+		if !ok {
+			break
+		}
+		var object map[string]any
+		if object, ok = value.(map[string]any); ok {
+			value, ok = object[key]
+		}
+	}
+	return value, ok
+}
+
+// Write renders the plan. YAML output keeps rq's file format, one document per
+// test file; JSON output is an array of {"file", "steps"} objects.
+func Write(w io.Writer, format Format, files []File) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, files)
+	default:
+		return writeYAML(w, files)
+	}
+}
+
+func writeYAML(w io.Writer, files []File) error {
+	for i, file := range files {
+		payload, err := yaml.EncodeSteps(file.Steps)
+		if err != nil {
+			return fmt.Errorf("failed to encode plan for %s: %w", file.Filename, err)
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# %s\n%s", file.Filename, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type fileJSON struct {
+	File  string          `json:"file"`
+	Steps json.RawMessage `json:"steps"`
+}
+
+func writeJSON(w io.Writer, files []File) error {
+	out := make([]fileJSON, 0, len(files))
+	for _, file := range files {
+		payload, err := yaml.EncodeSteps(file.Steps)
+		if err != nil {
+			return fmt.Errorf("failed to encode plan for %s: %w", file.Filename, err)
+		}
+
+		steps, err := goyaml.YAMLToJSON(payload)
+		if err != nil {
+			return fmt.Errorf("failed to convert plan for %s to JSON: %w", file.Filename, err)
+		}
+
+		out = append(out, fileJSON{File: file.Filename, Steps: steps})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
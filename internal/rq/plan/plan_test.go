@@ -0,0 +1,200 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSubstitute(t *testing.T) {
+	t.Parallel()
+
+	variables := map[string]any{"host": "localhost", "port": 8080, "user": map[string]any{"name": "ada"}}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no template", input: "https://example.com", want: "https://example.com"},
+		{name: "known variable", input: "https://{{.host}}/api", want: "https://localhost/api"},
+		{name: "non string variable", input: "{{.host}}:{{.port}}", want: "localhost:8080"},
+		{name: "unknown variable kept", input: "/users/{{.user_id}}", want: "/users/{{.user_id}}"},
+		{name: "function kept", input: "id={{uuidv4}}", want: "id={{uuidv4}}"},
+		{name: "pipeline kept", input: `{{.host | printf "%s"}}`, want: `{{.host | printf "%s"}}`},
+		{name: "map field", input: "{{.user.name}}", want: "ada"},
+		{name: "missing map field kept", input: "{{.user.email}}", want: "{{.user.email}}"},
+		{name: "invalid template kept", input: "{{.host", want: "{{.host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Substitute(tt.input, variables); got != tt.want {
+				t.Fatalf("Substitute(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildAndWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.yaml")
+	content := `- method: POST
+  url: https://{{.host}}/users
+  headers:
+    Authorization: Bearer {{.token}}
+  query:
+    env: "{{.env}}"
+  body:
+    name: "{{.name}}"
+`
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	files, err := Build([]string{filename}, map[string]any{"host": "localhost", "env": "dev", "name": "rq"})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		if err := Write(&buf, FormatYAML, files); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		got := buf.String()
+		for _, want := range []string{"# " + filename, "url: https://localhost/users", "value: Bearer {{.token}}", "value: dev", "name: rq"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("output missing %q:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		if err := Write(&buf, FormatJSON, files); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		var decoded []struct {
+			File  string           `json:"file"`
+			Steps []map[string]any `json:"steps"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, buf.String())
+		}
+		if len(decoded) != 1 || decoded[0].File != filename || len(decoded[0].Steps) != 1 {
+			t.Fatalf("unexpected plan: %+v", decoded)
+		}
+		if url := decoded[0].Steps[0]["url"]; url != "https://localhost/users" {
+			t.Fatalf("url = %v, want https://localhost/users", url)
+		}
+	})
+}
+
+func TestBuildExpandsForeach(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.yaml")
+	content := `- method: GET
+  url: https://{{.host}}/regions/{{.item}}?n={{.item_index}}
+  foreach: [eu, us]
+- method: GET
+  url: https://{{.host}}/tenants/{{.item.id}}
+  foreach: tenants
+- method: GET
+  url: https://{{.host}}/users/{{.item}}
+  foreach: user_ids
+`
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	variables := map[string]any{
+		"host":    "localhost",
+		"tenants": []any{map[string]any{"id": "a"}, map[string]any{"id": "b"}},
+	}
+	files, err := Build([]string{filename}, variables)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	var urls []string
+	for _, step := range files[0].Steps {
+		urls = append(urls, step.URL)
+	}
+	want := []string{
+		"https://localhost/regions/eu?n=0",
+		"https://localhost/regions/us?n=1",
+		"https://localhost/tenants/a",
+		"https://localhost/tenants/b",
+		"https://localhost/users/{{.item}}",
+	}
+	if !reflect.DeepEqual(urls, want) {
+		t.Fatalf("urls = %v, want %v", urls, want)
+	}
+	for i, step := range files[0].Steps[:4] {
+		if step.Foreach != nil {
+			t.Fatalf("step %d keeps foreach after expansion", i)
+		}
+	}
+	if files[0].Steps[4].Foreach == nil || files[0].Steps[4].Foreach.From != "user_ids" {
+		t.Fatalf("foreach over a capture should be kept: %+v", files[0].Steps[4].Foreach)
+	}
+}
+
+func TestBuildRejectsInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "invalid.yaml")
+	if err := os.WriteFile(filename, []byte("- method: GET\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	if _, err := Build([]string{filename}, nil); err == nil {
+		t.Fatal("expected validation error")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{input: "", want: FormatYAML},
+		{input: "yaml", want: FormatYAML},
+		{input: "JSON", want: FormatJSON},
+		{input: "text", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("ParseFormat(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -29,11 +29,15 @@ const (
 	OpLessThan           Operator = "less_than"
 	OpGreaterThanOrEqual Operator = "greater_than_or_equal"
 	OpLessThanOrEqual    Operator = "less_than_or_equal"
+	OpBetween            Operator = "between"
 	OpStartsWith         Operator = "starts_with"
 	OpEndsWith           Operator = "ends_with"
 	OpNotContains        Operator = "not_contains"
 	OpIn                 Operator = "in"
 	OpTypeIs             Operator = "type_is"
+
+	OpSameMembers               Operator = "same_members"
+	OpSameMembersWithDuplicates Operator = "same_members_with_duplicates"
 )
 
 type Expr struct {
@@ -53,11 +57,21 @@ var supportedOperatorSet = map[Operator]struct{}{
 	OpLessThan:           {},
 	OpGreaterThanOrEqual: {},
 	OpLessThanOrEqual:    {},
+	OpBetween:            {},
 	OpStartsWith:         {},
 	OpEndsWith:           {},
 	OpNotContains:        {},
 	OpIn:                 {},
 	OpTypeIs:             {},
+
+	OpSameMembers:               {},
+	OpSameMembersWithDuplicates: {},
+}
+
+// operatorAliases are accepted spellings of operators.
+var operatorAliases = map[string]Operator{
+	"greater_or_equal": OpGreaterThanOrEqual,
+	"less_or_equal":    OpLessThanOrEqual,
 }
 
 var supportedTypeValues = []string{
@@ -146,11 +160,19 @@ func newEvaluator(compiler regexCompiler) *Evaluator {
 		OpLessThan:           evaluateLessThan,
 		OpGreaterThanOrEqual: evaluateGreaterThanOrEqual,
 		OpLessThanOrEqual:    evaluateLessThanOrEqual,
+		OpBetween:            evaluateBetween,
 		OpStartsWith:         evaluateStartsWith,
 		OpEndsWith:           evaluateEndsWith,
 		OpNotContains:        evaluateNotContains,
 		OpIn:                 evaluateIn,
 		OpTypeIs:             evaluateTypeIs,
+
+		OpSameMembers: func(actual any, expected any) (bool, error) {
+			return evaluateSameMembers(OpSameMembers, actual, expected, false)
+		},
+		OpSameMembersWithDuplicates: func(actual any, expected any) (bool, error) {
+			return evaluateSameMembers(OpSameMembersWithDuplicates, actual, expected, true)
+		},
 	}
 
 	return e
@@ -166,6 +188,9 @@ func ParseOperator(input string) (Operator, error) {
 	if isSupportedOperator(op) {
 		return op, nil
 	}
+	if alias, ok := operatorAliases[input]; ok {
+		return alias, nil
+	}
 	return "", fmt.Errorf("%w: %q", ErrUnsupported, input)
 }
 
@@ -191,6 +216,18 @@ func ValidateExpr(expr Expr) error {
 		}
 	}
 
+	if expr.Op == OpBetween {
+		if _, _, err := betweenBounds(expr.Value); err != nil {
+			return err
+		}
+	}
+
+	if expr.Op == OpSameMembers || expr.Op == OpSameMembersWithDuplicates {
+		if _, ok := sliceValues(expr.Value); !ok {
+			return fmt.Errorf("%w: %q requires array/slice expected value, got %T", ErrInvalidInput, expr.Op, expr.Value)
+		}
+	}
+
 	return nil
 }
 
@@ -300,6 +337,50 @@ func evaluateLessThanOrEqual(actual, expected any) (bool, error) {
 	return evaluateNumericComparison(OpLessThanOrEqual, actual, expected, func(a, b float64) bool { return a <= b })
 }
 
+// IsNumeric reports whether op compares numbers.
+func (op Operator) IsNumeric() bool {
+	switch op {
+	case OpGreaterThan, OpLessThan, OpGreaterThanOrEqual, OpLessThanOrEqual, OpBetween:
+		return true
+	default:
+		return false
+	}
+}
+
+// evaluateBetween checks that actual lies within the inclusive [min, max]
+// range given as a two element array.
+func evaluateBetween(actual, expected any) (bool, error) {
+	low, high, err := betweenBounds(expected)
+	if err != nil {
+		return false, err
+	}
+
+	actualNumber, ok := number.ToFloat64(actual)
+	if !ok {
+		return false, fmt.Errorf("%w: %q requires numeric actual value, got %T", ErrInvalidInput, OpBetween, actual)
+	}
+
+	return actualNumber >= low && actualNumber <= high, nil
+}
+
+func betweenBounds(expected any) (float64, float64, error) {
+	values, ok := sliceValues(expected)
+	if !ok || len(values) != 2 {
+		return 0, 0, fmt.Errorf("%w: %q requires a [min, max] expected value, got %v", ErrInvalidInput, OpBetween, expected)
+	}
+
+	low, lowOK := number.ToFloat64(values[0])
+	high, highOK := number.ToFloat64(values[1])
+	if !lowOK || !highOK {
+		return 0, 0, fmt.Errorf("%w: %q requires numeric bounds, got %T and %T", ErrInvalidInput, OpBetween, values[0], values[1])
+	}
+	if low > high {
+		return 0, 0, fmt.Errorf("%w: %q minimum %v is greater than maximum %v", ErrInvalidInput, OpBetween, values[0], values[1])
+	}
+
+	return low, high, nil
+}
+
 func evaluateNumericComparison(op Operator, actual, expected any, compare func(float64, float64) bool) (bool, error) {
 	actualNumber, actualIsNumber := number.ToFloat64(actual)
 	expectedNumber, expectedIsNumber := number.ToFloat64(expected)
@@ -339,6 +420,78 @@ func evaluateIn(actual, expected any) (bool, error) {
 	return false, nil
 }
 
+// evaluateSameMembers compares two arrays ignoring order. Unless duplicates is
+// set, repeated elements count once, so [a, a, b] has the same members as [b, a].
+func evaluateSameMembers(op Operator, actual, expected any, duplicates bool) (bool, error) {
+	actualValues, ok := sliceValues(actual)
+	if !ok {
+		return false, fmt.Errorf("%w: %q requires array/slice actual value, got %T", ErrInvalidInput, op, actual)
+	}
+	expectedValues, ok := sliceValues(expected)
+	if !ok {
+		return false, fmt.Errorf("%w: %q requires array/slice expected value, got %T", ErrInvalidInput, op, expected)
+	}
+
+	if !duplicates {
+		actualValues = distinctValues(actualValues)
+		expectedValues = distinctValues(expectedValues)
+	}
+	if len(actualValues) != len(expectedValues) {
+		return false, nil
+	}
+
+	// Elements may be objects or mixed numeric types, so match pairwise
+	// instead of hashing.
+	matched := make([]bool, len(expectedValues))
+	for _, value := range actualValues {
+		found := false
+		for i, candidate := range expectedValues {
+			if !matched[i] && equalValues(value, candidate) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func sliceValues(value any) ([]any, bool) {
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.Slice && reflected.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	values := make([]any, reflected.Len())
+	for i := range values {
+		values[i] = reflected.Index(i).Interface()
+	}
+
+	return values, true
+}
+
+func distinctValues(values []any) []any {
+	distinct := make([]any, 0, len(values))
+	for _, value := range values {
+		seen := false
+		for _, existing := range distinct {
+			if equalValues(value, existing) {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			distinct = append(distinct, value)
+		}
+	}
+
+	return distinct
+}
+
 func evaluateTypeIs(actual, expected any) (bool, error) {
 	expectedType, err := parseTypeValue(expected)
 	if err != nil {
@@ -12,6 +12,9 @@ func TestParseOperator(t *testing.T) {
 	}{
 		{name: "supported", input: "equals"},
 		{name: "supported_type_is", input: "type_is"},
+		{name: "supported_between", input: "between"},
+		{name: "alias_greater_or_equal", input: "greater_or_equal"},
+		{name: "alias_less_or_equal", input: "less_or_equal"},
 		{name: "unsupported", input: "bad", wantErr: true},
 		{name: "empty", input: "", wantErr: true},
 	}
@@ -47,6 +50,15 @@ func TestValidateExpr(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "same_members_non_array_value",
+			expr: Expr{
+				Op:       OpSameMembers,
+				Value:    "a",
+				HasValue: true,
+			},
+			wantErr: true,
+		},
 		{
 			name: "equals_without_value",
 			expr: Expr{
@@ -79,6 +91,41 @@ func TestValidateExpr(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "between_valid_range",
+			expr: Expr{
+				Op:       OpBetween,
+				Value:    []any{1, 10.5},
+				HasValue: true,
+			},
+		},
+		{
+			name: "between_single_value",
+			expr: Expr{
+				Op:       OpBetween,
+				Value:    5,
+				HasValue: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "between_non_numeric_bound",
+			expr: Expr{
+				Op:       OpBetween,
+				Value:    []any{"a", 5},
+				HasValue: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "between_min_above_max",
+			expr: Expr{
+				Op:       OpBetween,
+				Value:    []any{10, 1},
+				HasValue: true,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -179,6 +226,76 @@ func TestEvaluateExpr(t *testing.T) {
 			actual:    "b",
 			wantError: true,
 		},
+		{
+			name: "same_members_ignores_order",
+			expr: Expr{
+				Op:       OpSameMembers,
+				Value:    []any{"c", "a", "b"},
+				HasValue: true,
+			},
+			actual: []any{"a", "b", "c"},
+			want:   true,
+		},
+		{
+			name: "same_members_ignores_duplicates",
+			expr: Expr{
+				Op:       OpSameMembers,
+				Value:    []any{"b", "a"},
+				HasValue: true,
+			},
+			actual: []any{"a", "a", "b"},
+			want:   true,
+		},
+		{
+			name: "same_members_mixed_numbers_and_objects",
+			expr: Expr{
+				Op:       OpSameMembers,
+				Value:    []any{map[string]any{"id": int64(2)}, int64(1)},
+				HasValue: true,
+			},
+			actual: []any{float64(1), map[string]any{"id": int64(2)}},
+			want:   true,
+		},
+		{
+			name: "same_members_missing_member",
+			expr: Expr{
+				Op:       OpSameMembers,
+				Value:    []any{"a", "b"},
+				HasValue: true,
+			},
+			actual: []any{"a", "c"},
+			want:   false,
+		},
+		{
+			name: "same_members_with_duplicates_counts",
+			expr: Expr{
+				Op:       OpSameMembersWithDuplicates,
+				Value:    []any{"b", "a"},
+				HasValue: true,
+			},
+			actual: []any{"a", "a", "b"},
+			want:   false,
+		},
+		{
+			name: "same_members_with_duplicates_matching_counts",
+			expr: Expr{
+				Op:       OpSameMembersWithDuplicates,
+				Value:    []any{"a", "b", "a"},
+				HasValue: true,
+			},
+			actual: []any{"b", "a", "a"},
+			want:   true,
+		},
+		{
+			name: "same_members_non_array_actual",
+			expr: Expr{
+				Op:       OpSameMembers,
+				Value:    []any{"a"},
+				HasValue: true,
+			},
+			actual:    "a",
+			wantError: true,
+		},
 		{
 			name: "exists_true",
 			expr: Expr{
@@ -245,6 +362,36 @@ func TestEvaluateExpr(t *testing.T) {
 			actual: nil,
 			want:   true,
 		},
+		{
+			name: "between_inside_range",
+			expr: Expr{
+				Op:       OpBetween,
+				Value:    []any{1, 10},
+				HasValue: true,
+			},
+			actual: float64(10),
+			want:   true,
+		},
+		{
+			name: "between_outside_range",
+			expr: Expr{
+				Op:       OpBetween,
+				Value:    []any{1, 10},
+				HasValue: true,
+			},
+			actual: 11,
+			want:   false,
+		},
+		{
+			name: "between_non_numeric_actual",
+			expr: Expr{
+				Op:       OpBetween,
+				Value:    []any{1, 10},
+				HasValue: true,
+			},
+			actual:    "5",
+			wantError: true,
+		},
 		{
 			name: "type_is_invalid_expected_type",
 			expr: Expr{
@@ -0,0 +1,123 @@
+// Package ratelimit throttles outgoing requests and records how long each key,
+// typically a host, spent waiting for a token.
+package ratelimit
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter combines a shared limit with optional per-key limits. A request
+// waits on the shared limit first and then on the limit of its key. It is
+// safe for concurrent use.
+type Limiter struct {
+	shared *rate.Limiter
+
+	mu       sync.Mutex
+	keyed    map[string]*rate.Limiter
+	stats    map[string]*Stats
+	recorded bool
+}
+
+// Stats is the throttling observed for one key.
+type Stats struct {
+	Key      string
+	Requests int
+	Waited   time.Duration
+}
+
+// New returns a limiter allowing requestsPerSecond across all keys; zero or a
+// negative value leaves the shared limit unbounded.
+func New(requestsPerSecond float64) *Limiter {
+	return &Limiter{
+		shared:   newLimiter(requestsPerSecond),
+		keyed:    make(map[string]*rate.Limiter),
+		stats:    make(map[string]*Stats),
+		recorded: requestsPerSecond > 0,
+	}
+}
+
+func newLimiter(requestsPerSecond float64) *rate.Limiter {
+	if requestsPerSecond <= 0 {
+		return rate.NewLimiter(rate.Inf, 1)
+	}
+
+	return rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+}
+
+// SetKeyLimit limits requests for key independently of other keys. Zero or a
+// negative value removes the key limit.
+func (l *Limiter) SetKeyLimit(key string, requestsPerSecond float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if requestsPerSecond <= 0 {
+		delete(l.keyed, key)
+		return
+	}
+
+	l.keyed[key] = newLimiter(requestsPerSecond)
+	l.recorded = true
+}
+
+// Wait blocks until a request for key may proceed or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, key string) error {
+	l.mu.Lock()
+	keyed := l.keyed[key]
+	recorded := l.recorded
+	l.mu.Unlock()
+
+	start := time.Now()
+	err := l.shared.Wait(ctx)
+	if err == nil && keyed != nil {
+		err = keyed.Wait(ctx)
+	}
+
+	if recorded {
+		l.record(key, time.Since(start))
+	}
+
+	return err
+}
+
+func (l *Limiter) record(key string, waited time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats, ok := l.stats[key]
+	if !ok {
+		stats = &Stats{Key: key}
+		l.stats[key] = stats
+	}
+	stats.Requests++
+	stats.Waited += waited
+}
+
+// TakeStats returns the statistics gathered since the previous call, sorted by
+// key, and starts a new collection period. It returns nil when no limit is
+// configured.
+func (l *Limiter) TakeStats() []Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.stats) == 0 {
+		return nil
+	}
+
+	result := make([]Stats, 0, len(l.stats))
+	for _, stats := range l.stats {
+		result = append(result, *stats)
+	}
+	clear(l.stats)
+
+	slices.SortFunc(result, func(a, b Stats) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return result
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unlimited limiter records nothing", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := New(0)
+		for range 3 {
+			if err := limiter.Wait(context.Background(), "api.example.com"); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+		}
+
+		if got := limiter.TakeStats(); got != nil {
+			t.Fatalf("TakeStats() = %v, want nil", got)
+		}
+	})
+
+	t.Run("shared limit records waits per key", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := New(100)
+		for _, key := range []string{"b.example.com", "a.example.com", "b.example.com"} {
+			if err := limiter.Wait(context.Background(), key); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+		}
+
+		stats := limiter.TakeStats()
+		if len(stats) != 2 {
+			t.Fatalf("TakeStats() = %v, want 2 keys", stats)
+		}
+		if stats[0].Key != "a.example.com" || stats[0].Requests != 1 {
+			t.Fatalf("stats[0] = %+v, want a.example.com with 1 request", stats[0])
+		}
+		if stats[1].Key != "b.example.com" || stats[1].Requests != 2 {
+			t.Fatalf("stats[1] = %+v, want b.example.com with 2 requests", stats[1])
+		}
+		if stats[0].Waited+stats[1].Waited < 10*time.Millisecond {
+			t.Fatalf("total wait = %s, want at least two 10ms intervals", stats[0].Waited+stats[1].Waited)
+		}
+
+		if got := limiter.TakeStats(); got != nil {
+			t.Fatalf("second TakeStats() = %v, want nil", got)
+		}
+	})
+
+	t.Run("key limit only throttles its key", func(t *testing.T) {
+		t.Parallel()
+
+		limiter := New(0)
+		limiter.SetKeyLimit("slow.example.com", 20)
+
+		for range 3 {
+			if err := limiter.Wait(context.Background(), "slow.example.com"); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+			if err := limiter.Wait(context.Background(), "fast.example.com"); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+		}
+
+		stats := limiter.TakeStats()
+		if len(stats) != 2 {
+			t.Fatalf("TakeStats() = %v, want 2 keys", stats)
+		}
+		fast, slow := stats[0], stats[1]
+		if slow.Waited < 80*time.Millisecond {
+			t.Fatalf("slow wait = %s, want at least 80ms", slow.Waited)
+		}
+		if fast.Waited >= slow.Waited {
+			t.Fatalf("fast wait = %s, want less than slow wait %s", fast.Waited, slow.Waited)
+		}
+	})
+}
+
+func TestLimiterWaitHonoursContext(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(0.001)
+	if err := limiter.Wait(context.Background(), "api.example.com"); err != nil {
+		t.Fatalf("first Wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx, "api.example.com"); err == nil {
+		t.Fatal("expected error when the context expires before a token is available")
+	}
+}
+
+func TestLimiterConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(10000)
+	limiter.SetKeyLimit("a", 10000)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := "a"
+			if i%2 == 1 {
+				key = "b"
+			}
+			_ = limiter.Wait(context.Background(), key)
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for _, stats := range limiter.TakeStats() {
+		total += stats.Requests
+	}
+	if total != 20 {
+		t.Fatalf("recorded %d requests, want 20", total)
+	}
+}
@@ -0,0 +1,171 @@
+// Package report combines JSON run reports, such as the partial reports of
+// --shard jobs or runs against separate environments, into one summary.
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+// Format selects how a merged report is written.
+type Format string
+
+const (
+	FormatJSON  Format = "json"
+	FormatJUnit Format = "junit"
+	FormatText  Format = "text"
+)
+
+// ErrInvalidFormat is returned for unknown report formats.
+var ErrInvalidFormat = errors.New("report format must be one of: json, junit, text")
+
+// ParseFormat parses a report format name.
+func ParseFormat(input string) (Format, error) {
+	switch Format(input) {
+	case FormatJSON, FormatJUnit, FormatText:
+		return Format(input), nil
+	default:
+		return FormatJSON, fmt.Errorf("%w, got: %s", ErrInvalidFormat, input)
+	}
+}
+
+// Load reads the runs of every JSON report in paths, in order. A --repeat
+// report contributes one run per iteration.
+func Load(paths []string) ([]*output.Summary, error) {
+	var summaries []*output.Summary
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		runs, err := output.ReadJSON(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+		}
+		summaries = append(summaries, runs...)
+	}
+
+	return summaries, nil
+}
+
+// Merge combines runs into one summary. File results are kept in order and
+// counted again, durations are added up, rate limit waits and connections
+// are combined per host, and meta keys keep every distinct value, joined
+// with commas in the order first seen.
+func Merge(runs []*output.Summary) *output.Summary {
+	merged := output.NewSummary(0)
+
+	meta := make(map[string][]string)
+	for _, run := range runs {
+		for _, result := range run.FileResults {
+			merged.Add(result)
+		}
+		merged.TotalDuration += run.TotalDuration
+		merged.RateLimit = mergeRateLimit(merged.RateLimit, run.RateLimit)
+		merged.Connections = mergeConnections(merged.Connections, run.Connections)
+		for key, value := range run.Meta {
+			if !slices.Contains(meta[key], value) {
+				meta[key] = append(meta[key], value)
+			}
+		}
+	}
+
+	if len(meta) > 0 {
+		merged.Meta = make(map[string]string, len(meta))
+		for key, values := range meta {
+			merged.Meta[key] = strings.Join(values, ",")
+		}
+	}
+
+	return merged
+}
+
+func mergeRateLimit(stats []output.RateLimitStat, more []output.RateLimitStat) []output.RateLimitStat {
+	for _, stat := range more {
+		i := slices.IndexFunc(stats, func(existing output.RateLimitStat) bool { return existing.Host == stat.Host })
+		if i < 0 {
+			stats = append(stats, stat)
+			continue
+		}
+		stats[i].Requests += stat.Requests
+		stats[i].Waited += stat.Waited
+	}
+	return stats
+}
+
+func mergeConnections(stats []output.ConnectionStat, more []output.ConnectionStat) []output.ConnectionStat {
+	for _, stat := range more {
+		i := slices.IndexFunc(stats, func(existing output.ConnectionStat) bool { return existing.Host == stat.Host })
+		if i < 0 {
+			stats = append(stats, stat)
+			continue
+		}
+		stats[i].Opened += stat.Opened
+		stats[i].Peak = max(stats[i].Peak, stat.Peak)
+	}
+	return stats
+}
+
+// MissingShards lists the shards, as K/N, absent from sharded runs: when
+// runs of shards 1/3 and 3/3 are merged, 2/3 is missing. Runs without a
+// shard are ignored.
+func MissingShards(runs []*output.Summary) []string {
+	seen := make(map[int][]int)
+	var counts []int
+	for _, run := range runs {
+		index, count, ok := parseShard(run.Shard)
+		if !ok {
+			continue
+		}
+		if _, known := seen[count]; !known {
+			counts = append(counts, count)
+		}
+		seen[count] = append(seen[count], index)
+	}
+
+	var missing []string
+	for _, count := range counts {
+		for index := 1; index <= count; index++ {
+			if !slices.Contains(seen[count], index) {
+				missing = append(missing, fmt.Sprintf("%d/%d", index, count))
+			}
+		}
+	}
+	return missing
+}
+
+func parseShard(shard string) (int, int, bool) {
+	index, count, found := strings.Cut(shard, "/")
+	if !found {
+		return 0, 0, false
+	}
+	k, err := strconv.Atoi(index)
+	if err != nil {
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || k < 1 || k > n {
+		return 0, 0, false
+	}
+	return k, n, true
+}
+
+// Write writes summary in format.
+func Write(w io.Writer, format Format, summary *output.Summary) error {
+	switch format {
+	case FormatJUnit:
+		return output.WriteJUnit(w, []*output.Summary{summary})
+	case FormatText:
+		return summary.Format(output.FormatText, w)
+	default:
+		return summary.Format(output.FormatJSON, w)
+	}
+}
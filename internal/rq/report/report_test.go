@@ -0,0 +1,152 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/output"
+)
+
+func run(shard string, meta map[string]string, duration time.Duration, results ...output.FileResult) *output.Summary {
+	summary := output.NewSummary(len(results))
+	for _, result := range results {
+		summary.Add(result)
+	}
+	summary.SetTotalDuration(duration)
+	summary.Meta = meta
+	summary.Shard = shard
+	return summary
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	first := run("1/2", map[string]string{"env": "staging", "git_sha": "abc"}, time.Second,
+		output.FileResult{Filename: "a.yaml", RequestCount: 2},
+		output.FileResult{Filename: "b.yaml", RequestCount: 1, Error: errors.New("boom")},
+	)
+	first.RateLimit = []output.RateLimitStat{{Host: "api", Requests: 2, Waited: 100 * time.Millisecond}}
+	first.Connections = []output.ConnectionStat{{Host: "api:443", Opened: 1, Peak: 1}}
+
+	second := run("2/2", map[string]string{"env": "prod", "git_sha": "abc"}, 2*time.Second,
+		output.FileResult{Filename: "c.yaml", RequestCount: 4},
+	)
+	second.RateLimit = []output.RateLimitStat{{Host: "api", Requests: 4, Waited: 50 * time.Millisecond}}
+	second.Connections = []output.ConnectionStat{{Host: "api:443", Opened: 3, Peak: 2}}
+
+	merged := Merge([]*output.Summary{first, second})
+
+	var files []string
+	for _, result := range merged.FileResults {
+		files = append(files, result.Filename)
+	}
+	if want := []string{"a.yaml", "b.yaml", "c.yaml"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+	if merged.ExecutedFiles != 3 || merged.FailedFiles != 1 || merged.SucceededFiles != 2 || merged.ExecutedRequests != 7 {
+		t.Errorf("counts = %+v", merged)
+	}
+	if merged.TotalDuration != 3*time.Second {
+		t.Errorf("TotalDuration = %s, want 3s", merged.TotalDuration)
+	}
+	if want := map[string]string{"env": "staging,prod", "git_sha": "abc"}; !reflect.DeepEqual(merged.Meta, want) {
+		t.Errorf("Meta = %v, want %v", merged.Meta, want)
+	}
+	if want := []output.RateLimitStat{{Host: "api", Requests: 6, Waited: 150 * time.Millisecond}}; !reflect.DeepEqual(merged.RateLimit, want) {
+		t.Errorf("RateLimit = %v, want %v", merged.RateLimit, want)
+	}
+	if want := []output.ConnectionStat{{Host: "api:443", Opened: 4, Peak: 2}}; !reflect.DeepEqual(merged.Connections, want) {
+		t.Errorf("Connections = %v, want %v", merged.Connections, want)
+	}
+	if merged.Shard != "" {
+		t.Errorf("Shard = %q, want empty", merged.Shard)
+	}
+}
+
+func TestMissingShards(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		shards []string
+		want   []string
+	}{
+		{name: "complete", shards: []string{"2/3", "1/3", "3/3"}},
+		{name: "missing", shards: []string{"1/4", "3/4"}, want: []string{"2/4", "4/4"}},
+		{name: "unsharded", shards: []string{"", ""}},
+		{name: "mixed counts", shards: []string{"1/2", "1/1"}, want: []string{"2/2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var runs []*output.Summary
+			for _, shard := range tt.shards {
+				runs = append(runs, run(shard, nil, 0))
+			}
+			if got := MissingShards(runs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingShards() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAndWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var paths []string
+	for i, summary := range []*output.Summary{
+		run("1/2", nil, time.Second, output.FileResult{Filename: "a.yaml", RequestCount: 1, Steps: []output.StepResult{{Name: "get a"}}}),
+		run("2/2", nil, time.Second, output.FileResult{Filename: "b.yaml", RequestCount: 1, Steps: []output.StepResult{{Name: "get b"}}}),
+	} {
+		var out bytes.Buffer
+		if err := summary.Format(output.FormatJSON, &out); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, []string{"shard-1.json", "shard-2.json"}[i])
+		if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	runs, err := Load(paths)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := Write(&out, FormatJUnit, Merge(runs)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	for _, want := range []string{`tests="2"`, `name="get a"`, `name="get b"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("JUnit report missing %s:\n%s", want, out.String())
+		}
+	}
+
+	if _, err := Load([]string{filepath.Join(dir, "missing.json")}); err == nil {
+		t.Error("Load() error = nil for a missing report")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"json", "junit", "text"} {
+		if got, err := ParseFormat(input); err != nil || string(got) != input {
+			t.Errorf("ParseFormat(%q) = %q, %v", input, got, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ParseFormat(xml) error = %v, want %v", err, ErrInvalidFormat)
+	}
+}
@@ -35,6 +35,42 @@ func DumpResponseRedacted(resp *http.Response, body []byte, redactValues []any,
 	return redactOutput(dump, redactValues, salt), nil
 }
 
+// Redact replaces the secret values in data with [S256:hash] placeholders.
+func Redact(data []byte, redactValues []any, salt string) []byte {
+	return redactOutput(data, redactValues, salt)
+}
+
+// RedactError returns err with the secret values in its message replaced by
+// [S256:hash] placeholders. The original error stays in the chain, so
+// errors.Is and errors.As keep working, but code that prints a wrapped
+// error instead of the returned one sees the unredacted message.
+func RedactError(err error, redactValues []any, salt string) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	redacted := string(redactOutput([]byte(message), redactValues, salt))
+	if redacted == message {
+		return err
+	}
+
+	return &redactedError{err: err, message: redacted}
+}
+
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
 // redactOutput replaces secret values in the given data with [S256:hash].
 func redactOutput(data []byte, redactValues []any, salt string) []byte {
 	if len(redactValues) == 0 || len(data) == 0 {
@@ -88,6 +124,11 @@ func matchRedactionTargetAt(data []byte, index int, targets []redactionTarget) *
 	return nil
 }
 
+// RedactedToken returns the placeholder that replaces secret in redacted output.
+func RedactedToken(secret, salt string) string {
+	return string(hashToken(secret, salt))
+}
+
 func hashToken(secret, salt string) []byte {
 	sum := sha256.Sum256([]byte(salt + secret))
 	hex := hex.EncodeToString(sum[:8])
@@ -1,6 +1,7 @@
 package sanitizer
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -152,3 +153,49 @@ func TestRedactSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestRedactError(t *testing.T) {
+	t.Parallel()
+
+	base := errors.New("request failed")
+
+	tests := []struct {
+		name         string
+		err          error
+		redactValues []any
+		want         string
+		same         bool
+	}{
+		{name: "nil error", err: nil, redactValues: []any{"secret123"}},
+		{name: "no secret in message", err: base, redactValues: []any{"secret123"}, want: "request failed", same: true},
+		{
+			name:         "secret in message",
+			err:          fmt.Errorf("token secret123 rejected: %w", base),
+			redactValues: []any{"secret123"},
+			want:         "token [S256:b693407b4d117bbe] rejected: request failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := RedactError(tt.err, tt.redactValues, testSalt)
+			if tt.err == nil {
+				if got != nil {
+					t.Fatalf("RedactError() = %v, want nil", got)
+				}
+				return
+			}
+			if got.Error() != tt.want {
+				t.Errorf("RedactError() = %q, want %q", got.Error(), tt.want)
+			}
+			if tt.same && got != tt.err {
+				t.Errorf("RedactError() = %#v, want the original error", got)
+			}
+			if !errors.Is(got, base) {
+				t.Errorf("RedactError() lost the wrapped error")
+			}
+		})
+	}
+}
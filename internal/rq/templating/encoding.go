@@ -0,0 +1,57 @@
+package templating
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Encoding helpers take any value, so captured numbers can be encoded
+// without converting them first.
+
+func b64dec(value any) (string, error) {
+	text := fmt.Sprint(value)
+	decoded, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		// Accept unpadded and URL-safe input, as produced by JWTs.
+		decoded, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(text, "="))
+		if err != nil {
+			return "", fmt.Errorf("b64dec: invalid base64 input: %w", err)
+		}
+	}
+
+	return string(decoded), nil
+}
+
+func urlEncode(value any) string {
+	return url.QueryEscape(fmt.Sprint(value))
+}
+
+func sha256Hex(value any) string {
+	sum := sha256.Sum256([]byte(fmt.Sprint(value)))
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 signs message with key and returns the hex digest. The message
+// comes last, so it can be piped: {{ .body | hmacSHA256 .secret }}.
+func hmacSHA256(key any, message any) string {
+	mac := hmac.New(sha256.New, []byte(fmt.Sprint(key)))
+	mac.Write([]byte(fmt.Sprint(message)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// jsonEscape escapes value for use inside a JSON string literal, without
+// the surrounding quotes.
+func jsonEscape(value any) (string, error) {
+	encoded, err := json.Marshal(fmt.Sprint(value))
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded[1 : len(encoded)-1]), nil
+}
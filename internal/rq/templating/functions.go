@@ -2,6 +2,7 @@ package templating
 
 import (
 	"encoding/base64"
+	"fmt"
 	"strconv"
 	"strings"
 	"text/template"
@@ -18,7 +19,7 @@ func FuncMap() template.FuncMap {
 		"uuidv4": generateUUIDv4,
 		"uuid":   generateUUIDv4, // Alias for uuidv4
 
-		"now":       timeRFC3339,
+		"now":       timeNow,
 		"timestamp": timeUnix,
 		"iso8601":   timeISO8601,
 		"rfc3339":   timeRFC3339,
@@ -31,7 +32,19 @@ func FuncMap() template.FuncMap {
 		"randomInt":    randomInt,
 		"randomString": randomString,
 
-		"base64": base64Encode,
+		"base64":     base64Encode,
+		"b64enc":     base64Encode,
+		"b64dec":     b64dec,
+		"urlencode":  urlEncode,
+		"sha256":     sha256Hex,
+		"hmacSHA256": hmacSHA256,
+		"jsonEscape": jsonEscape,
+
+		"add": add,
+		"sub": sub,
+		"mul": mul,
+		"div": div,
+		"sum": sum,
 	}
 }
 
@@ -47,6 +60,19 @@ func timeISO8601() string {
 	return clock.Now().Format("2006-01-02T15:04:05Z07:00")
 }
 
+// timeNow formats the current time with an optional Go layout, such as
+// "2006-01-02"; without one it uses RFC3339.
+func timeNow(layout ...string) (string, error) {
+	switch len(layout) {
+	case 0:
+		return timeRFC3339(), nil
+	case 1:
+		return clock.Now().Format(layout[0]), nil
+	default:
+		return "", fmt.Errorf("now takes at most one layout, got %d", len(layout))
+	}
+}
+
 func timeRFC3339() string {
 	return clock.Now().Format(time.RFC3339)
 }
@@ -92,8 +118,8 @@ func randomString(length int) string {
 	return string(buf)
 }
 
-func base64Encode(s string) string {
-	return base64.StdEncoding.EncodeToString([]byte(s))
+func base64Encode(value any) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
 }
 
 func NewTemplate(name string) *template.Template {
@@ -2,9 +2,13 @@ package templating
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/jacoelho/rq/internal/rq/clock"
 )
 
 func TestRandomFunctions(t *testing.T) {
@@ -77,6 +81,8 @@ func TestFuncMap(t *testing.T) {
 	expectedFunctions := []string{
 		"uuidv4", "uuid", "now", "timestamp", "iso8601", "rfc3339",
 		"upper", "lower", "title", "trim", "randomInt", "randomString", "base64",
+		"b64enc", "b64dec", "urlencode", "sha256", "hmacSHA256", "jsonEscape",
+		"add", "sub", "mul", "div", "sum",
 	}
 
 	for _, funcName := range expectedFunctions {
@@ -86,6 +92,83 @@ func TestFuncMap(t *testing.T) {
 	}
 }
 
+// TestNowLayout overrides the global clock, so it does not run in parallel.
+func TestNowLayout(t *testing.T) {
+	restore := clock.SetNowForTest(func() time.Time {
+		return time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+	})
+	t.Cleanup(restore)
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{name: "default layout", template: "{{now}}", want: "2026-03-14T15:09:26Z"},
+		{name: "date layout", template: `{{now "2006-01-02"}}`, want: "2026-03-14"},
+		{name: "custom layout", template: `{{now "15:04 Jan 2"}}`, want: "15:09 Mar 14"},
+		{name: "too many layouts", template: `{{now "2006" "01"}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.template, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodingFunctions(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"secret": "key",
+		"body":   `{"id":1}`,
+		"id":     json.Number("42"),
+		"query":  "a b&c=d",
+		"text":   "say \"hi\"\n<tab>\t",
+		"jwt":    "eyJzdWIiOiIxMjM0In0",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{name: "b64enc", template: "{{ b64enc .body }}", want: "eyJpZCI6MX0="},
+		{name: "b64enc number", template: "{{ b64enc .id }}", want: "NDI="},
+		{name: "b64dec", template: `{{ b64dec "eyJpZCI6MX0=" }}`, want: `{"id":1}`},
+		{name: "b64dec unpadded url safe", template: "{{ b64dec .jwt }}", want: `{"sub":"1234"}`},
+		{name: "b64dec invalid", template: `{{ b64dec "%%%" }}`, wantErr: true},
+		{name: "urlencode", template: "{{ urlencode .query }}", want: "a+b%26c%3Dd"},
+		{name: "sha256", template: `{{ sha256 "abc" }}`, want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{name: "hmacSHA256", template: "{{ hmacSHA256 .secret .body }}", want: "c95c6a7c2c7c761e984c68cf64b4bca93f07242900aafdbb328d3bb75ab0dcb0"},
+		{name: "hmacSHA256 piped", template: "{{ .body | hmacSHA256 .secret }}", want: "c95c6a7c2c7c761e984c68cf64b4bca93f07242900aafdbb328d3bb75ab0dcb0"},
+		{name: "jsonEscape", template: "{{ jsonEscape .text }}", want: `say \"hi\"\n\u003ctab\u003e\t`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Apply(tt.template, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestNewTemplate(t *testing.T) {
 	t.Parallel()
 
@@ -455,3 +538,49 @@ func TestMissingVariableError(t *testing.T) {
 		})
 	}
 }
+
+func TestMathFunctions(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]any{
+		"prices":   []any{19.99, json.Number("39.98"), int64(5)},
+		"quantity": "3",
+		"price":    0.1,
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{name: "add", template: "{{ add .price 0.2 }}", want: "0.3"},
+		{name: "sub", template: "{{ sub 10 .quantity }}", want: "7"},
+		{name: "mul numeric string", template: "{{ mul .quantity .price }}", want: "0.3"},
+		{name: "div", template: "{{ div 10 4 }}", want: "2.5"},
+		{name: "sum list", template: "{{ sum .prices }}", want: "64.97"},
+		{name: "sum arguments", template: "{{ sum 1 2 .quantity }}", want: "6"},
+		{name: "division by zero", template: "{{ div 1 0 }}", wantErr: true},
+		{name: "not a number", template: `{{ add "abc" 1 }}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Apply(tt.template, data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Apply() = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package templating
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/number"
+)
+
+// mathPrecision is the number of decimal places math results are rounded to,
+// so 0.1 + 0.2 renders as 0.3 instead of exposing binary floating point noise.
+const mathPrecision = 1e9
+
+func add(a, b any) (float64, error) {
+	return applyMath(a, b, func(x, y float64) (float64, error) { return x + y, nil })
+}
+
+func sub(a, b any) (float64, error) {
+	return applyMath(a, b, func(x, y float64) (float64, error) { return x - y, nil })
+}
+
+func mul(a, b any) (float64, error) {
+	return applyMath(a, b, func(x, y float64) (float64, error) { return x * y, nil })
+}
+
+func div(a, b any) (float64, error) {
+	return applyMath(a, b, func(x, y float64) (float64, error) {
+		if y == 0 {
+			return 0, errors.New("division by zero")
+		}
+		return x / y, nil
+	})
+}
+
+// sum adds its arguments. Lists, such as a jsonpath capture of several
+// values, are added element by element.
+func sum(values ...any) (float64, error) {
+	var total float64
+	for _, value := range values {
+		items := reflect.ValueOf(value)
+		if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+			x, err := toFloat(value)
+			if err != nil {
+				return 0, err
+			}
+			total += x
+			continue
+		}
+
+		for i := range items.Len() {
+			x, err := toFloat(items.Index(i).Interface())
+			if err != nil {
+				return 0, fmt.Errorf("item %d: %w", i, err)
+			}
+			total += x
+		}
+	}
+
+	return roundMath(total), nil
+}
+
+func applyMath(a, b any, op func(x, y float64) (float64, error)) (float64, error) {
+	x, err := toFloat(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toFloat(b)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := op(x, y)
+	if err != nil {
+		return 0, err
+	}
+
+	return roundMath(result), nil
+}
+
+// toFloat accepts numbers and numeric strings, as captured values of either
+// form are common.
+func toFloat(value any) (float64, error) {
+	if x, ok := number.ToFloat64(value); ok {
+		return x, nil
+	}
+	if s, ok := value.(string); ok {
+		x, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err == nil {
+			return x, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%v (%T) is not a number", value, value)
+}
+
+func roundMath(value float64) float64 {
+	return math.Round(value*mathPrecision) / mathPrecision
+}
@@ -0,0 +1,97 @@
+// Package trace records execution spans and writes them in the Chrome
+// trace-event format, which chrome://tracing and Perfetto load directly.
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Categories used for spans, so viewers can filter the timeline.
+const (
+	CategoryFile    = "file"
+	CategoryStep    = "step"
+	CategoryAttempt = "attempt"
+	CategoryPhase   = "phase"
+)
+
+// Event is a complete ("X") trace event. Timestamps and durations are in
+// microseconds relative to the start of the recording.
+type Event struct {
+	Name     string         `json:"name"`
+	Category string         `json:"cat"`
+	Phase    string         `json:"ph"`
+	Time     int64          `json:"ts"`
+	Duration int64          `json:"dur"`
+	Process  int            `json:"pid"`
+	Thread   int            `json:"tid"`
+	Args     map[string]any `json:"args,omitempty"`
+}
+
+// Recorder collects spans. A nil Recorder discards them, so callers do not
+// need to check whether tracing is enabled. It is safe for concurrent use.
+type Recorder struct {
+	start time.Time
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// New returns a recorder whose timeline starts at start.
+func New(start time.Time) *Recorder {
+	return &Recorder{start: start}
+}
+
+// Span records an operation that began at start and took duration on thread,
+// a timeline row. Spans on the same thread nest by time, so a step drawn
+// inside its file's span shows as its child.
+func (r *Recorder) Span(thread int, category, name string, start time.Time, duration time.Duration, args map[string]any) {
+	if r == nil {
+		return
+	}
+
+	event := Event{
+		Name:     name,
+		Category: category,
+		Phase:    "X",
+		Time:     start.Sub(r.start).Microseconds(),
+		Duration: max(duration.Microseconds(), 0),
+		Process:  1,
+		Thread:   thread,
+		Args:     args,
+	}
+
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+// Events returns a copy of the recorded events in recording order.
+func (r *Recorder) Events() []Event {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Event(nil), r.events...)
+}
+
+type document struct {
+	TraceEvents     []Event `json:"traceEvents"`
+	DisplayTimeUnit string  `json:"displayTimeUnit"`
+}
+
+// Write emits the recorded events as a trace-event JSON document.
+func (r *Recorder) Write(w io.Writer) error {
+	events := r.Events()
+	if events == nil {
+		events = []Event{}
+	}
+
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(document{TraceEvents: events, DisplayTimeUnit: "ms"})
+}
@@ -0,0 +1,57 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecorderWrite(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder := New(start)
+	recorder.Span(1, CategoryFile, "test.yaml", start.Add(time.Millisecond), 5*time.Millisecond, nil)
+	recorder.Span(1, CategoryStep, "step 0 GET /", start.Add(2*time.Millisecond), 1500*time.Microsecond, map[string]any{"error": "boom"})
+
+	var buf bytes.Buffer
+	if err := recorder.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var decoded struct {
+		TraceEvents     []Event `json:"traceEvents"`
+		DisplayTimeUnit string  `json:"displayTimeUnit"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := []Event{
+		{Name: "test.yaml", Category: "file", Phase: "X", Time: 1000, Duration: 5000, Process: 1, Thread: 1},
+		{Name: "step 0 GET /", Category: "step", Phase: "X", Time: 2000, Duration: 1500, Process: 1, Thread: 1, Args: map[string]any{"error": "boom"}},
+	}
+	if !reflect.DeepEqual(decoded.TraceEvents, want) {
+		t.Fatalf("events = %+v, want %+v", decoded.TraceEvents, want)
+	}
+	if decoded.DisplayTimeUnit != "ms" {
+		t.Fatalf("displayTimeUnit = %q, want ms", decoded.DisplayTimeUnit)
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	t.Parallel()
+
+	var recorder *Recorder
+	recorder.Span(1, CategoryFile, "ignored", time.Now(), time.Second, nil)
+
+	var buf bytes.Buffer
+	if err := recorder.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if got := buf.String(); got != "{\"traceEvents\":[],\"displayTimeUnit\":\"ms\"}\n" {
+		t.Fatalf("Write() = %q", got)
+	}
+}
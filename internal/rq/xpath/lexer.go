@@ -0,0 +1,114 @@
+package xpath
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenNumber
+	tokenString
+	tokenSlash
+	tokenDoubleSlash
+	tokenLBracket
+	tokenRBracket
+	tokenLParen
+	tokenRParen
+	tokenAt
+	tokenComma
+	tokenEquals
+	tokenNotEquals
+	tokenDot
+	tokenDoubleDot
+	tokenStar
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+var punctuation = []struct {
+	text string
+	kind tokenKind
+}{
+	// Longer tokens first so // wins over /.
+	{"//", tokenDoubleSlash},
+	{"..", tokenDoubleDot},
+	{"!=", tokenNotEquals},
+	{"/", tokenSlash},
+	{"[", tokenLBracket},
+	{"]", tokenRBracket},
+	{"(", tokenLParen},
+	{")", tokenRParen},
+	{"@", tokenAt},
+	{",", tokenComma},
+	{"=", tokenEquals},
+	{".", tokenDot},
+	{"*", tokenStar},
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	rest := expr
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			return tokens, nil
+		}
+
+		switch c, _ := utf8.DecodeRuneInString(rest); {
+		case c == '\'' || c == '"':
+			end := strings.IndexRune(rest[1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("%w %q: unterminated string literal", ErrSyntax, expr)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: rest[1 : end+1]})
+			rest = rest[end+2:]
+			continue
+		case unicode.IsDigit(c):
+			end := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsDigit(r) })
+			if end < 0 {
+				end = len(rest)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: rest[:end]})
+			rest = rest[end:]
+			continue
+		case isNameStart(c):
+			end := strings.IndexFunc(rest, func(r rune) bool { return !isNameChar(r) })
+			if end < 0 {
+				end = len(rest)
+			}
+			tokens = append(tokens, token{kind: tokenName, text: rest[:end]})
+			rest = rest[end:]
+			continue
+		}
+
+		matched := false
+		for _, p := range punctuation {
+			if strings.HasPrefix(rest, p.text) {
+				tokens = append(tokens, token{kind: p.kind, text: p.text})
+				rest = rest[len(p.text):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("%w %q: unexpected character %q", ErrSyntax, expr, rest[:1])
+		}
+	}
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isNameChar(r rune) bool {
+	return isNameStart(r) || unicode.IsDigit(r) || r == '-' || r == '.' || r == ':'
+}
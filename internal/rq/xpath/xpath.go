@@ -0,0 +1,493 @@
+// Package xpath evaluates a subset of XPath 1.0 over markup documents.
+//
+// Supported are absolute and relative location paths with / and //, the
+// steps name, *, ., .., @name, @*, text() and node(), and predicates holding
+// a position, last(), a relative path, a comparison of a path with a string
+// literal (= and !=), or contains() and starts-with() of a path and a literal.
+// A whole expression may be wrapped in string(), normalize-space() or count().
+// Namespace prefixes in name tests are ignored.
+package xpath
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jacoelho/rq/internal/rq/markup"
+)
+
+// ErrSyntax is returned for expressions outside the supported subset.
+var ErrSyntax = errors.New("invalid xpath expression")
+
+// Expr is a compiled expression.
+type Expr struct {
+	source string
+	fn     string // Wrapping function, empty for a bare path
+	path   path
+}
+
+// Compile parses expr.
+func Compile(expr string) (*Expr, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{source: expr, tokens: tokens}
+	compiled := &Expr{source: expr}
+	if tok := p.peek(); tok.kind == tokenName && isTopLevelFunc(tok.text) && p.peekAt(1).kind == tokenLParen {
+		compiled.fn = tok.text
+		p.next()
+		p.next()
+		if compiled.path, err = p.parsePath(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen); err != nil {
+			return nil, err
+		}
+	} else if compiled.path, err = p.parsePath(); err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf("unexpected %q", tok.text)
+	}
+
+	return compiled, nil
+}
+
+func isTopLevelFunc(name string) bool {
+	switch name {
+	case "string", "normalize-space", "count":
+		return true
+	}
+	return false
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Evaluate runs the expression against doc. A bare path yields the string
+// value of its first match and reports false when nothing matched; string()
+// and normalize-space() yield a string and count() an int.
+func (e *Expr) Evaluate(doc *markup.Node) (any, bool) {
+	items := e.path.evaluate(doc)
+
+	switch e.fn {
+	case "count":
+		return len(items), true
+	case "string":
+		return firstValue(items), true
+	case "normalize-space":
+		return strings.Join(strings.Fields(firstValue(items)), " "), true
+	}
+
+	if len(items) == 0 {
+		return nil, false
+	}
+	return items[0].value(), true
+}
+
+// item is a node or an attribute of a node.
+type item struct {
+	node *markup.Node
+	attr *markup.Attr
+}
+
+func (i item) value() string {
+	if i.attr != nil {
+		return i.attr.Value
+	}
+	return i.node.Text()
+}
+
+func firstValue(items []item) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return items[0].value()
+}
+
+type path struct {
+	absolute bool
+	steps    []step
+}
+
+type axis int
+
+const (
+	axisChild axis = iota
+	axisDescendant
+	axisDescendantOrSelf
+	axisSelf
+	axisParent
+	axisAttribute
+)
+
+type step struct {
+	axis       axis
+	name       string // Name test; "*" matches any, "text()" and "node()" are kind tests
+	predicates []predicate
+}
+
+func (p path) evaluate(doc *markup.Node) []item {
+	return p.evaluateFrom(doc, doc)
+}
+
+func (p path) evaluateFrom(doc, context *markup.Node) []item {
+	start := context
+	if p.absolute {
+		start = doc
+	}
+
+	items := []item{{node: start}}
+	for _, s := range p.steps {
+		seen := make(map[item]bool)
+		var next []item
+		for _, current := range items {
+			if current.attr != nil {
+				continue
+			}
+			for _, selected := range s.apply(doc, current.node) {
+				if !seen[selected] {
+					seen[selected] = true
+					next = append(next, selected)
+				}
+			}
+		}
+		items = next
+	}
+
+	return items
+}
+
+// apply selects the items of step from context and filters them by the
+// predicates, which see positions relative to context.
+func (s step) apply(doc, context *markup.Node) []item {
+	var candidates []item
+	switch s.axis {
+	case axisSelf:
+		candidates = []item{{node: context}}
+	case axisDescendantOrSelf:
+		var walk func(node *markup.Node)
+		walk = func(node *markup.Node) {
+			candidates = append(candidates, item{node: node})
+			for _, child := range node.Children {
+				walk(child)
+			}
+		}
+		walk(context)
+	case axisParent:
+		if context.Parent != nil {
+			candidates = []item{{node: context.Parent}}
+		}
+	case axisAttribute:
+		for i := range context.Attrs {
+			if s.name == "*" || s.name == context.Attrs[i].Name {
+				candidates = append(candidates, item{node: context, attr: &context.Attrs[i]})
+			}
+		}
+	case axisChild:
+		candidates = s.matchChildren(context, nil)
+	case axisDescendant:
+		var walk func(node *markup.Node)
+		walk = func(node *markup.Node) {
+			candidates = append(candidates, s.filter(doc, s.matchChildren(node, nil))...)
+			for _, child := range node.Children {
+				walk(child)
+			}
+		}
+		walk(context)
+		return candidates
+	}
+
+	return s.filter(doc, candidates)
+}
+
+func (s step) matchChildren(context *markup.Node, out []item) []item {
+	for _, child := range context.Children {
+		if s.matches(child) {
+			out = append(out, item{node: child})
+		}
+	}
+	return out
+}
+
+func (s step) matches(node *markup.Node) bool {
+	switch s.name {
+	case "node()":
+		return true
+	case "text()":
+		return node.Type == markup.TextNode
+	case "*":
+		return node.Type == markup.ElementNode
+	}
+	return node.Type == markup.ElementNode && node.Name == s.name
+}
+
+func (s step) filter(doc *markup.Node, items []item) []item {
+	for _, pred := range s.predicates {
+		var kept []item
+		for i, current := range items {
+			if pred.matches(doc, current, i+1, len(items)) {
+				kept = append(kept, current)
+			}
+		}
+		items = kept
+	}
+	return items
+}
+
+type predicate struct {
+	position int    // [n]
+	last     bool   // [last()]
+	fn       string // contains or starts-with
+	op       string // = or !=, empty to test for a match
+	path     path
+	literal  string
+}
+
+func (p predicate) matches(doc *markup.Node, current item, position, size int) bool {
+	switch {
+	case p.position > 0:
+		return position == p.position
+	case p.last:
+		return position == size
+	}
+	if current.attr != nil {
+		return false
+	}
+
+	items := p.path.evaluateFrom(doc, current.node)
+	switch p.fn {
+	case "contains":
+		return strings.Contains(firstValue(items), p.literal)
+	case "starts-with":
+		return strings.HasPrefix(firstValue(items), p.literal)
+	}
+
+	switch p.op {
+	case "=":
+		for _, matched := range items {
+			if matched.value() == p.literal {
+				return true
+			}
+		}
+		return false
+	case "!=":
+		for _, matched := range items {
+			if matched.value() != p.literal {
+				return true
+			}
+		}
+		return false
+	}
+
+	return len(items) > 0
+}
+
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return token{kind: tokenEOF}
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind) error {
+	if tok := p.next(); tok.kind != kind {
+		if tok.kind == tokenEOF {
+			return p.errorf("unexpected end of expression")
+		}
+		return p.errorf("unexpected %q", tok.text)
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w %q: %s", ErrSyntax, p.source, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parsePath() (path, error) {
+	var result path
+	separator := axisChild
+	switch p.peek().kind {
+	case tokenSlash:
+		p.next()
+		result.absolute = true
+		if !startsStep(p.peek()) {
+			// A lone / selects the document.
+			return result, nil
+		}
+	case tokenDoubleSlash:
+		p.next()
+		result.absolute = true
+		separator = axisDescendant
+	}
+
+	for {
+		if separator == axisDescendant && !startsNameStep(p.peek()) {
+			// //@id and //. select from every node below the context.
+			result.steps = append(result.steps, step{axis: axisDescendantOrSelf})
+			separator = axisChild
+		}
+
+		s, err := p.parseStep(separator)
+		if err != nil {
+			return path{}, err
+		}
+		result.steps = append(result.steps, s)
+
+		switch p.peek().kind {
+		case tokenSlash:
+			separator = axisChild
+		case tokenDoubleSlash:
+			separator = axisDescendant
+		default:
+			return result, nil
+		}
+		p.next()
+	}
+}
+
+func startsNameStep(tok token) bool {
+	return tok.kind == tokenName || tok.kind == tokenStar
+}
+
+func startsStep(tok token) bool {
+	switch tok.kind {
+	case tokenName, tokenStar, tokenAt, tokenDot, tokenDoubleDot:
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseStep(separator axis) (step, error) {
+	s := step{axis: separator}
+	tok := p.next()
+	switch tok.kind {
+	case tokenDot, tokenDoubleDot:
+		s.axis = axisSelf
+		if tok.kind == tokenDoubleDot {
+			s.axis = axisParent
+		}
+		return s, nil
+	case tokenAt:
+		name := p.next()
+		if name.kind != tokenName && name.kind != tokenStar {
+			return step{}, p.errorf("expected attribute name after @")
+		}
+		s.axis = axisAttribute
+		s.name = localName(name.text)
+	case tokenStar:
+		s.name = "*"
+	case tokenName:
+		if strings.Contains(tok.text, "::") {
+			return step{}, p.errorf("axis %s is not supported", tok.text)
+		}
+		s.name = localName(tok.text)
+		if p.peek().kind == tokenLParen {
+			if tok.text != "text" && tok.text != "node" {
+				return step{}, p.errorf("unsupported function %s()", tok.text)
+			}
+			p.next()
+			if err := p.expect(tokenRParen); err != nil {
+				return step{}, err
+			}
+			s.name = tok.text + "()"
+		}
+	case tokenEOF:
+		return step{}, p.errorf("unexpected end of expression")
+	default:
+		return step{}, p.errorf("unexpected %q", tok.text)
+	}
+
+	for p.peek().kind == tokenLBracket {
+		p.next()
+		pred, err := p.parsePredicate()
+		if err != nil {
+			return step{}, err
+		}
+		if err := p.expect(tokenRBracket); err != nil {
+			return step{}, err
+		}
+		s.predicates = append(s.predicates, pred)
+	}
+
+	return s, nil
+}
+
+func (p *parser) parsePredicate() (predicate, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenNumber:
+		p.next()
+		position, err := strconv.Atoi(tok.text)
+		if err != nil || position < 1 {
+			return predicate{}, p.errorf("position must be a positive integer, got %s", tok.text)
+		}
+		return predicate{position: position}, nil
+	case tok.kind == tokenName && tok.text == "last" && p.peekAt(1).kind == tokenLParen:
+		p.next()
+		p.next()
+		return predicate{last: true}, p.expect(tokenRParen)
+	case tok.kind == tokenName && (tok.text == "contains" || tok.text == "starts-with") && p.peekAt(1).kind == tokenLParen:
+		p.next()
+		p.next()
+		relative, err := p.parsePath()
+		if err != nil {
+			return predicate{}, err
+		}
+		if err := p.expect(tokenComma); err != nil {
+			return predicate{}, err
+		}
+		literal := p.next()
+		if literal.kind != tokenString {
+			return predicate{}, p.errorf("%s() expects a string literal", tok.text)
+		}
+		return predicate{fn: tok.text, path: relative, literal: literal.text}, p.expect(tokenRParen)
+	}
+
+	relative, err := p.parsePath()
+	if err != nil {
+		return predicate{}, err
+	}
+	pred := predicate{path: relative}
+	if op := p.peek(); op.kind == tokenEquals || op.kind == tokenNotEquals {
+		p.next()
+		literal := p.next()
+		if literal.kind != tokenString && literal.kind != tokenNumber {
+			return predicate{}, p.errorf("%s must be followed by a literal", op.text)
+		}
+		pred.op = op.text
+		pred.literal = literal.text
+	}
+
+	return pred, nil
+}
+
+// localName drops a namespace prefix.
+func localName(name string) string {
+	if _, local, ok := strings.Cut(name, ":"); ok {
+		return local
+	}
+	return name
+}
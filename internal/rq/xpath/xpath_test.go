@@ -0,0 +1,125 @@
+package xpath
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jacoelho/rq/internal/rq/markup"
+)
+
+const catalog = `<?xml version="1.0"?>
+<catalog xmlns:bk="urn:books">
+  <book id="b1" lang="en">
+    <title>Go in Practice</title>
+    <price>30</price>
+  </book>
+  <book id="b2" lang="pt">
+    <title>  Programação   Go </title>
+    <price>25</price>
+  </book>
+  <bk:book id="b3">
+    <title>Namespaced</title>
+  </bk:book>
+</catalog>`
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	doc, err := markup.ParseXML([]byte(catalog))
+	if err != nil {
+		t.Fatalf("ParseXML() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		expr      string
+		want      any
+		wantFound bool
+	}{
+		{name: "absolute_path", expr: "/catalog/book/title", want: "Go in Practice", wantFound: true},
+		{name: "relative_path", expr: "catalog/book/price", want: "30", wantFound: true},
+		{name: "descendant", expr: "//price", want: "30", wantFound: true},
+		{name: "position", expr: "//book[2]/price", want: "25", wantFound: true},
+		{name: "last", expr: "//book[last()]/@id", want: "b3", wantFound: true},
+		{name: "attribute", expr: "/catalog/book/@id", want: "b1", wantFound: true},
+		{name: "descendant_attribute", expr: "//@lang", want: "en", wantFound: true},
+		{name: "attribute_equals", expr: "//book[@lang='pt']/price", want: "25", wantFound: true},
+		{name: "attribute_not_equals", expr: "//book[@id!='b1']/@id", want: "b2", wantFound: true},
+		{name: "child_equals", expr: `//book[price="25"]/@id`, want: "b2", wantFound: true},
+		{name: "has_attribute", expr: "//book[@lang][2]/@id", want: "b2", wantFound: true},
+		{name: "contains", expr: "//book[contains(title, 'Practice')]/@id", want: "b1", wantFound: true},
+		{name: "starts_with", expr: "//book[starts-with(@id, 'b3')]/title", want: "Namespaced", wantFound: true},
+		{name: "namespace_prefix_ignored", expr: "//bk:book/title", want: "Go in Practice", wantFound: true},
+		{name: "wildcard", expr: "/*/*[3]/@id", want: "b3", wantFound: true},
+		{name: "parent", expr: "//price[.='25']/../@id", want: "b2", wantFound: true},
+		{name: "text_node", expr: "//book[1]/title/text()", want: "Go in Practice", wantFound: true},
+		{name: "string", expr: "string(//book[1]/price)", want: "30", wantFound: true},
+		{name: "string_without_match", expr: "string(//missing)", want: "", wantFound: true},
+		{name: "normalize_space", expr: "normalize-space(//book[2]/title)", want: "Programação Go", wantFound: true},
+		{name: "count", expr: "count(//book)", want: 3, wantFound: true},
+		{name: "count_with_predicate", expr: "count(//book[@lang])", want: 2, wantFound: true},
+		{name: "not_found", expr: "//author", want: nil, wantFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatalf("Compile(%q) error = %v", tt.expr, err)
+			}
+			got, found := expr.Evaluate(doc)
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("Evaluate(%q) = (%v, %v), want (%v, %v)", tt.expr, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestEvaluateHTML(t *testing.T) {
+	t.Parallel()
+
+	doc, err := markup.Parse([]byte(`<!DOCTYPE html><title>Shop</title><ul><li>one<li class="sale">two<br></ul>`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	expr, err := Compile("//li[@class='sale']")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if got, found := expr.Evaluate(doc); got != "two" || !found {
+		t.Errorf("Evaluate() = (%v, %v), want (two, true)", got, found)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"",
+		"//",
+		"/catalog/",
+		"//book[",
+		"//book[0]",
+		"//book[@id=]",
+		"//book['b1'",
+		"child::book",
+		"//book/sum(price)",
+		"count(//book",
+		"//book[contains(title)]",
+		"//book#",
+		"//book[@id='b1]",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Compile(expr); !errors.Is(err, ErrSyntax) {
+				t.Errorf("Compile(%q) error = %v, want ErrSyntax", expr, err)
+			}
+		})
+	}
+}
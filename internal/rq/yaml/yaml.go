@@ -1,6 +1,8 @@
 package yaml
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -13,9 +15,68 @@ func Parse(r io.Reader) ([]model.Step, error) {
 	return model.Parse(r)
 }
 
+// ParseWithPositions decodes steps like Parse and also returns where each
+// step's fields are declared, for validation messages with line numbers.
+func ParseWithPositions(data []byte) ([]model.Step, []model.Positions, error) {
+	steps, err := model.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	positions, err := model.ParsePositions(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return steps, positions, nil
+}
+
 // EncodeStep renders a single step as rq YAML file content.
 func EncodeStep(step model.Step) ([]byte, error) {
-	payload, err := yaml.Marshal([]stepYAML{mapStep(step)})
+	return EncodeSteps([]model.Step{step})
+}
+
+// EncodeSteps renders steps as rq YAML file content.
+func EncodeSteps(steps []model.Step) ([]byte, error) {
+	mapped := make([]stepYAML, 0, len(steps))
+	for _, step := range steps {
+		mapped = append(mapped, mapStep(step))
+	}
+
+	payload, err := yaml.Marshal(mapped)
+	if err != nil {
+		return nil, fmt.Errorf("encode YAML: %w", err)
+	}
+
+	return payload, nil
+}
+
+// EncodeStepsJSON renders steps as indented JSON. YAML is a superset of
+// JSON, so the result is also a valid rq test file.
+func EncodeStepsJSON(steps []model.Step) ([]byte, error) {
+	payload, err := EncodeSteps(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	compact, err := yaml.YAMLToJSON(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode JSON: %w", err)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, compact, "", "  "); err != nil {
+		return nil, fmt.Errorf("encode JSON: %w", err)
+	}
+	indented.WriteByte('\n')
+
+	return indented.Bytes(), nil
+}
+
+// EncodeAsserts renders asserts as the mapping found under a step's asserts
+// key.
+func EncodeAsserts(asserts model.Asserts) ([]byte, error) {
+	payload, err := yaml.Marshal(mapAsserts(asserts))
 	if err != nil {
 		return nil, fmt.Errorf("encode YAML: %w", err)
 	}
@@ -24,23 +85,65 @@ func EncodeStep(step model.Step) ([]byte, error) {
 }
 
 type stepYAML struct {
-	Method   string          `yaml:"method"`
-	URL      string          `yaml:"url"`
-	When     string          `yaml:"when,omitempty"`
-	Headers  model.KeyValues `yaml:"headers,omitempty"`
-	Query    model.KeyValues `yaml:"query,omitempty"`
-	Options  model.Options   `yaml:"options,omitempty"`
-	Body     string          `yaml:"body,omitempty"`
-	BodyFile string          `yaml:"body_file,omitempty"`
-	Asserts  assertsYAML     `yaml:"asserts,omitempty"`
-	Captures *model.Captures `yaml:"captures,omitempty"`
+	Method      string             `yaml:"method,omitempty"`
+	URL         string             `yaml:"url,omitempty"`
+	Name        string             `yaml:"name,omitempty"`
+	Description string             `yaml:"description,omitempty"`
+	Docs        string             `yaml:"docs,omitempty"`
+	When        string             `yaml:"when,omitempty"`
+	Foreach     *model.Foreach     `yaml:"foreach,omitempty"`
+	DumpVars    bool               `yaml:"dump_vars,omitempty"`
+	Headers     model.KeyValues    `yaml:"headers,omitempty"`
+	Query       model.KeyValues    `yaml:"query,omitempty"`
+	Options     model.Options      `yaml:"options,omitempty"`
+	Body        model.Body         `yaml:"body,omitempty"`
+	BodyFile    string             `yaml:"body_file,omitempty"`
+	BodyFileRaw bool               `yaml:"body_file_raw,omitempty"`
+	Multipart   []model.Part       `yaml:"multipart,omitempty"`
+	PollJob     *model.PollJob     `yaml:"poll_job,omitempty"`
+	WebSocket   *model.WebSocket   `yaml:"websocket,omitempty"`
+	Conditional *model.Conditional `yaml:"conditional,omitempty"`
+	GRPC        *model.GRPC        `yaml:"grpc,omitempty"`
+	GraphQL     *model.GraphQL     `yaml:"graphql,omitempty"`
+	Connect     *model.Connect     `yaml:"connect,omitempty"`
+	DNS         *model.DNS         `yaml:"dns,omitempty"`
+	Auth        *model.Auth        `yaml:"auth,omitempty"`
+	Asserts     assertsYAML        `yaml:"asserts,omitempty"`
+	Captures    *model.Captures    `yaml:"captures,omitempty"`
+	Checks      []checkYAML        `yaml:"checks,omitempty"`
+	Exports     []string           `yaml:"exports,omitempty"`
+
+	ConditionalOn string `yaml:"conditional_on,omitempty"`
+
+	RegisterCleanup []model.Cleanup `yaml:"register_cleanup,omitempty"`
+
+	VariantAsserts map[string]assertsYAML `yaml:"variant_asserts,omitempty"`
 }
 
 type assertsYAML struct {
-	Status      []statusAssertYAML      `yaml:"status,omitempty"`
-	Headers     []headerAssertYAML      `yaml:"headers,omitempty"`
-	Certificate []certificateAssertYAML `yaml:"certificate,omitempty"`
-	JSONPath    []jsonPathAssertYAML    `yaml:"jsonpath,omitempty"`
+	Status          []statusAssertYAML       `yaml:"status,omitempty"`
+	Headers         []headerAssertYAML       `yaml:"headers,omitempty"`
+	Cookies         []cookieAssertYAML       `yaml:"cookies,omitempty"`
+	Certificate     []certificateAssertYAML  `yaml:"certificate,omitempty"`
+	JSONPath        []jsonPathAssertYAML     `yaml:"jsonpath,omitempty"`
+	CSS             []cssAssertYAML          `yaml:"css,omitempty"`
+	URL             []urlAssertYAML          `yaml:"url,omitempty"`
+	TLS             []tlsAssertYAML          `yaml:"tls,omitempty"`
+	TTFB            []ttfbAssertYAML         `yaml:"ttfb,omitempty"`
+	Duration        []durationAssertYAML     `yaml:"duration,omitempty"`
+	Attempts        []countAssertYAML        `yaml:"attempts,omitempty"`
+	Redirects       []countAssertYAML        `yaml:"redirects,omitempty"`
+	Golden          []model.GoldenAssert     `yaml:"golden,omitempty"`
+	JSONSchema      []model.JSONSchemaAssert `yaml:"jsonschema,omitempty"`
+	Body            []bodyAssertYAML         `yaml:"body,omitempty"`
+	ContentLanguage []setAssertYAML          `yaml:"content_language,omitempty"`
+	Vary            []setAssertYAML          `yaml:"vary,omitempty"`
+	Expr            []string                 `yaml:"expr,omitempty"`
+	Stable          []model.StableAssert     `yaml:"stable,omitempty"`
+	Continue        []continueAssertYAML     `yaml:"continue,omitempty"`
+	Range           *bool                    `yaml:"range,omitempty"`
+	Allow           *model.AllowAssert       `yaml:"allow,omitempty"`
+	GraphQL         *graphQLAssertsYAML      `yaml:"graphql,omitempty"`
 }
 
 type statusAssertYAML struct {
@@ -60,12 +163,79 @@ type certificateAssertYAML struct {
 	Value *yamlValue `yaml:"value,omitempty"`
 }
 
+type graphQLAssertsYAML struct {
+	NoErrors bool                 `yaml:"no_errors,omitempty"`
+	Data     []jsonPathAssertYAML `yaml:"data,omitempty"`
+	Errors   []jsonPathAssertYAML `yaml:"errors,omitempty"`
+}
+
 type jsonPathAssertYAML struct {
 	Path  string     `yaml:"path"`
 	Op    string     `yaml:"op"`
 	Value *yamlValue `yaml:"value,omitempty"`
 }
 
+type cookieAssertYAML struct {
+	Name      string     `yaml:"name"`
+	Attribute string     `yaml:"attribute,omitempty"`
+	Op        string     `yaml:"op"`
+	Value     *yamlValue `yaml:"value,omitempty"`
+}
+
+type cssAssertYAML struct {
+	Selector string     `yaml:"selector"`
+	Attr     string     `yaml:"attr,omitempty"`
+	Op       string     `yaml:"op"`
+	Value    *yamlValue `yaml:"value,omitempty"`
+}
+
+type urlAssertYAML struct {
+	Op    string     `yaml:"op"`
+	Value *yamlValue `yaml:"value,omitempty"`
+}
+
+type tlsAssertYAML struct {
+	Name  string     `yaml:"name"`
+	Op    string     `yaml:"op"`
+	Value *yamlValue `yaml:"value,omitempty"`
+}
+
+type ttfbAssertYAML struct {
+	Op    string     `yaml:"op"`
+	Value *yamlValue `yaml:"value,omitempty"`
+}
+
+type checkYAML struct {
+	Name  string     `yaml:"name"`
+	Op    string     `yaml:"op"`
+	Value *yamlValue `yaml:"value,omitempty"`
+}
+
+type durationAssertYAML struct {
+	Op    string     `yaml:"op"`
+	Value *yamlValue `yaml:"value,omitempty"`
+}
+
+type bodyAssertYAML struct {
+	Op    string     `yaml:"op"`
+	Value *yamlValue `yaml:"value,omitempty"`
+}
+
+type setAssertYAML struct {
+	Op    string     `yaml:"op"`
+	Value *yamlValue `yaml:"value,omitempty"`
+}
+
+type continueAssertYAML struct {
+	Op    string     `yaml:"op"`
+	Value *yamlValue `yaml:"value,omitempty"`
+}
+
+type countAssertYAML struct {
+	Op    string     `yaml:"op"`
+	Value *yamlValue `yaml:"value,omitempty"`
+}
+
 type yamlValue struct {
 	Value any
 }
@@ -76,16 +246,48 @@ func (v *yamlValue) MarshalYAML() (any, error) {
 
 func mapStep(step model.Step) stepYAML {
 	mapped := stepYAML{
-		Method:   step.Method,
-		URL:      step.URL,
-		When:     step.When,
-		Headers:  step.Headers,
-		Query:    step.Query,
-		Options:  step.Options,
-		Body:     step.Body,
-		BodyFile: step.BodyFile,
-		Asserts:  mapAsserts(step.Asserts),
-		Captures: step.Captures,
+		Method:      step.Method,
+		URL:         step.URL,
+		Name:        step.Name,
+		Description: step.Description,
+		Docs:        step.Docs,
+		When:        step.When,
+		Foreach:     step.Foreach,
+		DumpVars:    step.DumpVars,
+		Headers:     step.Headers,
+		Query:       step.Query,
+		Options:     step.Options,
+		Body:        step.Body,
+		BodyFile:    step.BodyFile,
+		BodyFileRaw: step.BodyFileRaw,
+		Multipart:   step.Multipart,
+		PollJob:     step.PollJob,
+		WebSocket:   step.WebSocket,
+		Conditional: step.Conditional,
+		GRPC:        step.GRPC,
+		GraphQL:     step.GraphQL,
+		Connect:     step.Connect,
+		DNS:         step.DNS,
+		Auth:        step.Auth,
+		Asserts:     mapAsserts(step.Asserts),
+		Captures:    step.Captures,
+		Exports:     step.Exports,
+
+		ConditionalOn:   step.ConditionalOn,
+		RegisterCleanup: step.RegisterCleanup,
+	}
+	for _, check := range step.Checks {
+		mapped.Checks = append(mapped.Checks, checkYAML{
+			Name:  check.Name,
+			Op:    check.Predicate.Operation,
+			Value: predicateValue(check.Predicate),
+		})
+	}
+	for accept, asserts := range step.VariantAsserts {
+		if mapped.VariantAsserts == nil {
+			mapped.VariantAsserts = make(map[string]assertsYAML, len(step.VariantAsserts))
+		}
+		mapped.VariantAsserts[accept] = mapAsserts(asserts)
 	}
 
 	return mapped
@@ -93,10 +295,28 @@ func mapStep(step model.Step) stepYAML {
 
 func mapAsserts(asserts model.Asserts) assertsYAML {
 	out := assertsYAML{
-		Status:      make([]statusAssertYAML, 0, len(asserts.Status)),
-		Headers:     make([]headerAssertYAML, 0, len(asserts.Headers)),
-		Certificate: make([]certificateAssertYAML, 0, len(asserts.Certificate)),
-		JSONPath:    make([]jsonPathAssertYAML, 0, len(asserts.JSONPath)),
+		Status:          make([]statusAssertYAML, 0, len(asserts.Status)),
+		Headers:         make([]headerAssertYAML, 0, len(asserts.Headers)),
+		Cookies:         make([]cookieAssertYAML, 0, len(asserts.Cookies)),
+		Certificate:     make([]certificateAssertYAML, 0, len(asserts.Certificate)),
+		JSONPath:        mapJSONPathAsserts(asserts.JSONPath),
+		CSS:             make([]cssAssertYAML, 0, len(asserts.CSS)),
+		URL:             make([]urlAssertYAML, 0, len(asserts.URL)),
+		TLS:             make([]tlsAssertYAML, 0, len(asserts.TLS)),
+		TTFB:            make([]ttfbAssertYAML, 0, len(asserts.TTFB)),
+		Duration:        make([]durationAssertYAML, 0, len(asserts.Duration)),
+		Attempts:        make([]countAssertYAML, 0, len(asserts.Attempts)),
+		Redirects:       make([]countAssertYAML, 0, len(asserts.Redirects)),
+		Golden:          asserts.Golden,
+		JSONSchema:      asserts.JSONSchema,
+		Body:            make([]bodyAssertYAML, 0, len(asserts.Body)),
+		ContentLanguage: mapSetAsserts(asserts.ContentLanguage),
+		Vary:            mapSetAsserts(asserts.Vary),
+		Expr:            asserts.Expr,
+		Stable:          asserts.Stable,
+		Continue:        make([]continueAssertYAML, 0, len(asserts.Continue)),
+		Range:           asserts.Range,
+		Allow:           asserts.Allow,
 	}
 
 	for _, assert := range asserts.Status {
@@ -122,14 +342,112 @@ func mapAsserts(asserts model.Asserts) assertsYAML {
 		})
 	}
 
-	for _, assert := range asserts.JSONPath {
-		out.JSONPath = append(out.JSONPath, jsonPathAssertYAML{
+	if asserts.GraphQL != nil {
+		out.GraphQL = &graphQLAssertsYAML{
+			NoErrors: asserts.GraphQL.NoErrors,
+			Data:     mapJSONPathAsserts(asserts.GraphQL.Data),
+			Errors:   mapJSONPathAsserts(asserts.GraphQL.Errors),
+		}
+	}
+
+	for _, assert := range asserts.Cookies {
+		out.Cookies = append(out.Cookies, cookieAssertYAML{
+			Name:      assert.Name,
+			Attribute: assert.Attribute,
+			Op:        assert.Predicate.Operation,
+			Value:     predicateValue(assert.Predicate),
+		})
+	}
+
+	for _, assert := range asserts.CSS {
+		out.CSS = append(out.CSS, cssAssertYAML{
+			Selector: assert.Selector,
+			Attr:     assert.Attr,
+			Op:       assert.Predicate.Operation,
+			Value:    predicateValue(assert.Predicate),
+		})
+	}
+
+	for _, assert := range asserts.URL {
+		out.URL = append(out.URL, urlAssertYAML{
+			Op:    assert.Predicate.Operation,
+			Value: predicateValue(assert.Predicate),
+		})
+	}
+
+	for _, assert := range asserts.TLS {
+		out.TLS = append(out.TLS, tlsAssertYAML{
+			Name:  assert.Name,
+			Op:    assert.Predicate.Operation,
+			Value: predicateValue(assert.Predicate),
+		})
+	}
+
+	for _, assert := range asserts.TTFB {
+		out.TTFB = append(out.TTFB, ttfbAssertYAML{
+			Op:    assert.Predicate.Operation,
+			Value: predicateValue(assert.Predicate),
+		})
+	}
+
+	for _, assert := range asserts.Duration {
+		out.Duration = append(out.Duration, durationAssertYAML{
+			Op:    assert.Predicate.Operation,
+			Value: predicateValue(assert.Predicate),
+		})
+	}
+
+	for _, assert := range asserts.Attempts {
+		out.Attempts = append(out.Attempts, countAssertYAML{
+			Op:    assert.Predicate.Operation,
+			Value: predicateValue(assert.Predicate),
+		})
+	}
+
+	for _, assert := range asserts.Redirects {
+		out.Redirects = append(out.Redirects, countAssertYAML{
+			Op:    assert.Predicate.Operation,
+			Value: predicateValue(assert.Predicate),
+		})
+	}
+
+	for _, assert := range asserts.Body {
+		out.Body = append(out.Body, bodyAssertYAML{
+			Op:    assert.Predicate.Operation,
+			Value: predicateValue(assert.Predicate),
+		})
+	}
+
+	for _, assert := range asserts.Continue {
+		out.Continue = append(out.Continue, continueAssertYAML{
+			Op:    assert.Predicate.Operation,
+			Value: predicateValue(assert.Predicate),
+		})
+	}
+
+	return out
+}
+
+func mapJSONPathAsserts(asserts []model.JSONPathAssert) []jsonPathAssertYAML {
+	out := make([]jsonPathAssertYAML, 0, len(asserts))
+	for _, assert := range asserts {
+		out = append(out, jsonPathAssertYAML{
 			Path:  assert.Path,
 			Op:    assert.Predicate.Operation,
 			Value: predicateValue(assert.Predicate),
 		})
 	}
+	return out
+}
 
+func mapSetAsserts(asserts []model.HeaderSetAssert) []setAssertYAML {
+	out := make([]setAssertYAML, 0, len(asserts))
+	for _, assert := range asserts {
+		out = append(out, setAssertYAML{
+			Op:    assert.Predicate.Operation,
+			Value: predicateValue(assert.Predicate),
+		})
+	}
 	return out
 }
 
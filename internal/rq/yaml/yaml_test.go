@@ -1,6 +1,7 @@
 package yaml
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -45,6 +46,45 @@ func TestEncodeStep(t *testing.T) {
 	}
 }
 
+func TestEncodeStepsJSON(t *testing.T) {
+	t.Parallel()
+
+	steps := []model.Step{
+		{Method: "GET", URL: "https://api.example.com/users"},
+		{
+			Method: "POST",
+			URL:    "https://api.example.com/users",
+			Body:   model.TextBody(`{"name":"ada"}`),
+			Asserts: model.Asserts{
+				Status: []model.StatusAssert{{
+					Predicate: model.Predicate{Operation: "equals", Value: int64(201), HasValue: true},
+				}},
+			},
+		},
+	}
+
+	payload, err := EncodeStepsJSON(steps)
+	if err != nil {
+		t.Fatalf("EncodeStepsJSON() error = %v", err)
+	}
+
+	var decoded []map[string]any
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("generated JSON is invalid: %v\n%s", err, payload)
+	}
+	if !strings.HasPrefix(string(payload), "[\n  {\n    \"method\": \"GET\"") {
+		t.Fatalf("expected indented JSON with keys in step order, got:\n%s", payload)
+	}
+
+	parsed, err := model.Parse(strings.NewReader(string(payload)))
+	if err != nil {
+		t.Fatalf("generated JSON failed to parse as rq steps: %v\n%s", err, payload)
+	}
+	if len(parsed) != 2 || parsed[1].Body.Text != `{"name":"ada"}` || parsed[1].Asserts.Status[0].Predicate.Value != int64(201) {
+		t.Fatalf("parsed steps = %#v", parsed)
+	}
+}
+
 func TestEncodeStepKeepsExplicitNullPredicateValue(t *testing.T) {
 	t.Parallel()
 